@@ -219,6 +219,15 @@ func (api *API) Query(ctx context.Context, req *QueryRequest) (QueryResponse, er
 		defer api.tracker.Finish(api.tracker.Start(req.Query, req.SQLQuery, api.server.nodeID, req.Index, start))
 	}
 
+	// Wait to be admitted to the resource pool of the request's user. The
+	// parts of the query sent to other nodes run there in the same pool,
+	// without being admitted again.
+	ctx, release, err := api.server.workload.admit(ctx)
+	if err != nil {
+		return QueryResponse{}, errors.Wrap(err, "admitting query")
+	}
+	defer release()
+
 	return api.query(ctx, req)
 }
 
@@ -366,6 +375,40 @@ func (api *API) DeleteIndex(ctx context.Context, indexName string) error {
 	return nil
 }
 
+// RenameIndex renames an existing index.
+func (api *API) RenameIndex(ctx context.Context, indexName, newIndexName string) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.RenameIndex")
+	defer span.Finish()
+
+	if err := api.validate(apiRenameIndex); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	// Rename index in the holder.
+	if err := api.holder.RenameIndex(indexName, newIndexName); err != nil {
+		return errors.Wrap(err, "renaming index")
+	}
+
+	// Send the rename index message to all nodes.
+	err := api.server.SendSync(
+		&RenameIndexMessage{
+			Index:    indexName,
+			NewIndex: newIndexName,
+		})
+	if err != nil {
+		api.server.logger.Errorf("problem sending RenameIndex message: %s", err)
+		return errors.Wrap(err, "sending RenameIndex message")
+	}
+	// Move ids allocated for index if any present
+	snap := api.cluster.NewSnapshot()
+	if snap.IsPrimaryFieldTranslationNode(api.NodeID()) {
+		if err := api.holder.ida.rename(indexName, newIndexName); err != nil {
+			return errors.Wrap(err, "renaming id allocation for index")
+		}
+	}
+	return nil
+}
+
 // CreateField makes the named field in the named index with the given options.
 //
 // The resulting field will always have TrackExistence set.
@@ -459,6 +502,36 @@ func (api *API) UpdateField(ctx context.Context, indexName, fieldName string, up
 	return errors.Wrap(err, "sending UpdateField message")
 }
 
+// IndexUpdate represents a change to an index. Like FieldUpdate, only one
+// option is changed at a time. The supported options are "retentionField"
+// and "retentionPeriod".
+type IndexUpdate struct {
+	Option string `json:"option"`
+	Value  string `json:"value"`
+}
+
+func (api *API) UpdateIndex(ctx context.Context, indexName string, update IndexUpdate) error {
+	// Find index.
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return newNotFoundError(ErrIndexNotFound, indexName)
+	}
+
+	cim, err := index.Update(ctx, update)
+	if err != nil {
+		return errors.Wrap(err, "updating index")
+	}
+
+	index.UpdateLocal(cim)
+
+	// broadcast index update
+	err = api.holder.sendOrSpool(&UpdateIndexMessage{
+		CreateIndexMessage: *cim,
+		Update:             update,
+	})
+	return errors.Wrap(err, "sending UpdateIndex message")
+}
+
 // Field retrieves the named field.
 func (api *API) Field(ctx context.Context, indexName, fieldName string) (*Field, error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "API.Field")
@@ -648,6 +721,24 @@ func (api *API) ImportRoaring(ctx context.Context, indexName, fieldName string,
 	// Create a snapshot of the cluster to use for node/partition calculations.
 	snap := api.cluster.NewSnapshot()
 
+	changes, err := api.importChangeSet(qcx, index, shard)
+	if err != nil {
+		return errors.Wrap(err, "reading existing records")
+	}
+	if changes != nil {
+		for _, viewData := range req.Views {
+			cols, err := roaringColumns(viewData, shard)
+			if err != nil {
+				return err
+			}
+			if req.Action == RequestActionClear || (req.Action == "" && req.Clear) {
+				changes.addClear(fieldName, cols)
+			} else {
+				changes.addSet(fieldName, cols)
+			}
+		}
+	}
+
 	nodes := snap.ShardNodes(indexName, shard)
 	errCh := make(chan error, len(nodes))
 	for _, node := range nodes {
@@ -719,7 +810,13 @@ func (api *API) ImportRoaring(ctx context.Context, indexName, fieldName string,
 				}
 			}
 
-			return qcx.Finish()
+			if err := qcx.Finish(); err != nil {
+				return err
+			}
+			if changes != nil {
+				index.recordChangeSet(changes)
+			}
+			return nil
 		}
 	}
 }
@@ -760,6 +857,40 @@ func (api *API) DeleteField(ctx context.Context, indexName string, fieldName str
 	return nil
 }
 
+// RenameField renames a field in the named index.
+func (api *API) RenameField(ctx context.Context, indexName, fieldName, newFieldName string) error {
+	span, _ := tracing.StartSpanFromContext(ctx, "API.RenameField")
+	defer span.Finish()
+
+	if err := api.validate(apiRenameField); err != nil {
+		return errors.Wrap(err, "validating api method")
+	}
+
+	// Find index.
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return newNotFoundError(ErrIndexNotFound, indexName)
+	}
+
+	// Rename field in the index.
+	if err := index.RenameField(fieldName, newFieldName); err != nil {
+		return errors.Wrap(err, "renaming field")
+	}
+
+	// Send the rename field message to all nodes.
+	err := api.server.SendSync(
+		&RenameFieldMessage{
+			Index:    indexName,
+			Field:    fieldName,
+			NewField: newFieldName,
+		})
+	if err != nil {
+		api.server.logger.Errorf("problem sending RenameField message: %s", err)
+		return errors.Wrap(err, "sending RenameField message")
+	}
+	return nil
+}
+
 // DeleteAvailableShard a shard ID from the available shard set cache.
 func (api *API) DeleteAvailableShard(_ context.Context, indexName, fieldName string, shardID uint64) error {
 	if err := api.validate(apiDeleteAvailableShard); err != nil {
@@ -1614,6 +1745,11 @@ func (api *API) ImportWithTx(ctx context.Context, qcx *Qcx, req *ImportRequest,
 		}
 	}
 
+	changes, err := api.importChangeSet(qcx, idx, req.Shard)
+	if err != nil {
+		return errors.Wrap(err, "reading existing records")
+	}
+
 	// Import columnIDs into existence field.
 	if !options.Clear {
 		if err := importExistenceColumns(qcx, idx, req.ColumnIDs, req.Shard); err != nil {
@@ -1625,12 +1761,27 @@ func (api *API) ImportWithTx(ctx context.Context, qcx *Qcx, req *ImportRequest,
 		}
 	}
 
+	// The import reuses req.ColumnIDs, so the changed columns are read first.
+	var columns *Row
+	if changes != nil {
+		columns = NewRow(req.ColumnIDs...)
+	}
+
 	// Import into fragment.
 	err = field.Import(qcx, req.RowIDs, req.ColumnIDs, timestamps, req.Shard, options)
 	if err != nil {
 		api.server.logger.Errorf("import error: index=%s, field=%s, shard=%d, columns=%d, err=%s", req.Index, req.Field, req.Shard, len(req.ColumnIDs), err)
 		return errors.Wrap(err, "importing")
 	}
+
+	if changes != nil {
+		if options.Clear {
+			changes.addClear(req.Field, columns)
+		} else {
+			changes.addSet(req.Field, columns)
+		}
+		qcx.OnCommit(func() { idx.recordChangeSet(changes) })
+	}
 	return errors.Wrap(err, "committing")
 }
 
@@ -1650,15 +1801,41 @@ func (api *API) ImportRoaringShard(ctx context.Context, indexName string, shard
 		return errors.Wrap(err, "getting index")
 	}
 
-	// we really only need a Tx, but getting a Qcx so that there's only one path for getting a Tx
-	qcx := api.Txf().NewQcx()
-	qcx.write = true
+	// we really only need a Tx, but getting a Qcx so that there's only one path for getting a Tx.
+	// An import which is part of a sql transaction writes through the
+	// transaction's qcx, which commits when the transaction does.
+	var qcx *Qcx
+	txn := sqlTransactionFromContext(ctx)
+	if txn != nil {
+		if qcx, err = txn.sessionQcx(api.Txf()); err != nil {
+			return errors.Wrap(err, "getting transaction qcx")
+		}
+	} else {
+		qcx = api.Txf().NewQcx()
+		qcx.write = true
+	}
 	tx, finisher, err := qcx.GetTx(Txo{Write: true, Index: index, Shard: shard})
 	if err != nil {
 		return errors.Wrap(err, "getting Tx")
 	}
-	defer qcx.Finish()
+	if txn == nil {
+		defer qcx.Finish()
+	}
 	var err1 error
+
+	// Changes are recorded by qcx.Finish once the writes have committed.
+	var changes *changeSet
+	if index.changes != nil && api.cluster.recordsChanges(indexName, shard) {
+		if changes, err1 = index.newChangeSet(tx, shard); err1 != nil {
+			finisher(&err1)
+			return err1
+		}
+		defer func() {
+			if err1 == nil {
+				qcx.OnCommit(func() { index.recordChangeSet(changes) })
+			}
+		}()
+	}
 	defer finisher(&err1)
 
 	if !req.Remote {
@@ -1697,6 +1874,17 @@ func (api *API) ImportRoaringShard(ctx context.Context, indexName string, shard
 			} else {
 				err1 = frag.ImportRoaringSingleValued(ctx, tx, viewUpdate.Clear, viewUpdate.Set)
 			}
+		case FieldTypeGeo:
+			// A record holds a single point, so setting one replaces
+			// whatever point the record had.
+			var set *Row
+			if set, err1 = roaringColumns(viewUpdate.Set, shard); err1 != nil {
+				return err1
+			}
+			if _, err1 = frag.ClearRecords(tx, set.Columns()); err1 != nil {
+				return err1
+			}
+			err1 = frag.ImportRoaringClearAndSet(ctx, tx, viewUpdate.Clear, viewUpdate.Set)
 		case FieldTypeInt, FieldTypeTimestamp, FieldTypeDecimal:
 			err1 = frag.ImportRoaringBSI(ctx, tx, viewUpdate.Clear, viewUpdate.Set)
 		case FieldTypeMutex, FieldTypeBool:
@@ -1708,6 +1896,20 @@ func (api *API) ImportRoaringShard(ctx context.Context, indexName string, shard
 			return err1
 		}
 
+		// the existence field is maintained by the batch API alongside
+		// the fields it describes, so it isn't a change of its own
+		if changes != nil && field.name != existenceFieldName {
+			var set, clear *Row
+			if set, err1 = roaringColumns(viewUpdate.Set, shard); err1 != nil {
+				return err1
+			}
+			if clear, err1 = roaringColumns(viewUpdate.Clear, shard); err1 != nil {
+				return err1
+			}
+			changes.addSet(field.name, set)
+			changes.addClear(field.name, clear)
+		}
+
 		// need to update field/bsiGroup bitDepth value if this is an int-like field.
 		//
 		// TODO get rid of cached bitDepth entirely because the fact
@@ -1937,6 +2139,10 @@ func (api *API) ImportValueWithTx(ctx context.Context, qcx *Qcx, req *ImportValu
 		if err := api.validateShardOwnership(req.Index, req.Shard); err != nil {
 			return errors.Wrap(err, "validating shard ownership")
 		}
+		changes, err := api.importChangeSet(qcx, idx, shard)
+		if err != nil {
+			return errors.Wrap(err, "reading existing records")
+		}
 		// Import columnIDs into existence field.
 		if !options.Clear {
 			if err := importExistenceColumns(qcx, idx, req.ColumnIDs, shard); err != nil {
@@ -1962,6 +2168,14 @@ func (api *API) ImportValueWithTx(ctx context.Context, qcx *Qcx, req *ImportValu
 				api.server.logger.Errorf("import error: index=%s, field=%s, shard=%d, columns=%d, err=%s", req.Index, req.Field, req.Shard, len(req.ColumnIDs), err)
 			}
 		}
+		if err == nil && changes != nil {
+			if options.Clear {
+				changes.addClear(req.Field, NewRow(req.ColumnIDs...))
+			} else {
+				changes.addSet(req.Field, NewRow(req.ColumnIDs...))
+			}
+			qcx.OnCommit(func() { idx.recordChangeSet(changes) })
+		}
 		return errors.Wrap(err, "importing value")
 
 	} // end if req.Shard != math.MaxUint64
@@ -2234,6 +2448,57 @@ func (api *API) TranslateIndexIDs(ctx context.Context, indexName string, ids []u
 	return api.cluster.translateIndexIDs(ctx, indexName, ids)
 }
 
+// Changes returns up to limit of the change events this node has recorded
+// for an index after the offset since, waiting up to wait for an event if
+// there are none yet. Each node records the changes to the shards it is the
+// primary owner of, so a consumer of every change to an index reads the
+// changes of each node.
+func (api *API) Changes(ctx context.Context, indexName string, since uint64, limit int, wait time.Duration) ([]*ChangeEvent, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "API.Changes")
+	defer span.Finish()
+
+	if err := api.validate(apiChanges); err != nil {
+		return nil, errors.Wrap(err, "validating api method")
+	}
+
+	index := api.holder.Index(indexName)
+	if index == nil {
+		return nil, newNotFoundError(ErrIndexNotFound, indexName)
+	}
+	if index.changes == nil {
+		return nil, ErrChangeCaptureDisabled
+	}
+
+	events, err := index.changes.read(since, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 && wait > 0 {
+		wctx, cancel := context.WithTimeout(ctx, wait)
+		defer cancel()
+		if err := index.changes.wait(wctx, since); err != nil && wctx.Err() == nil {
+			return nil, err
+		}
+		if events, err = index.changes.read(since, limit); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keys are translated as the events are read, except for deleted
+	// records, whose keys are gone by then.
+	if index.Keys() {
+		for _, ev := range events {
+			if ev.Op == ChangeOpDelete {
+				continue
+			}
+			if ev.ColumnKeys, err = api.cluster.translateIndexIDs(ctx, indexName, ev.Columns); err != nil {
+				return nil, errors.Wrap(err, "translating change event columns")
+			}
+		}
+	}
+	return events, nil
+}
+
 // TranslateKeys handles a TranslateKeyRequest.
 // ErrTranslatingKeyNotFound error will be swallowed here, so the empty response will be returned.
 func (api *API) TranslateKeys(ctx context.Context, r io.Reader) (_ []byte, err error) {
@@ -2340,13 +2605,14 @@ func (api *API) CreateFieldKeys(ctx context.Context, index, field string, keys .
 	return api.cluster.createFieldKeys(ctx, f, keys...)
 }
 
-// MatchField finds the IDs of all field keys matching a filter.
-func (api *API) MatchField(ctx context.Context, index, field string, like string) ([]uint64, error) {
+// MatchField finds the IDs of all field keys matching a pattern. The syntax
+// of the pattern is one of "like", "regexp" or "glob".
+func (api *API) MatchField(ctx context.Context, index, field string, syntax, pattern string) ([]uint64, error) {
 	f := api.holder.Field(index, field)
 	if f == nil {
 		return nil, newNotFoundError(ErrFieldNotFound, field)
 	}
-	return api.cluster.matchField(ctx, f, like)
+	return api.cluster.matchField(ctx, f, syntax, pattern)
 }
 
 // PrimaryReplicaNodeURL returns the URL of the cluster's primary replica.
@@ -2573,7 +2839,7 @@ func (api *API) RestoreShard(ctx context.Context, indexName string, shard uint64
 	defer o.Close()
 
 	bw := bufio.NewWriter(o)
-	if _, err = io.Copy(bw, rd); err != nil {
+	if _, err = rbf.CopyPages(bw, rd, api.holder.cfg.RBFConfig.Cipher); err != nil {
 		return err
 	} else if err := bw.Flush(); err != nil {
 		return err
@@ -3025,6 +3291,12 @@ func (api *API) CompilePlan(ctx context.Context, q string) (planner_types.PlanOp
 	return api.server.CompileExecutionPlan(ctx, q)
 }
 
+// CompilePreparedPlan takes a sql string containing positional parameters
+// ($1, $2, ...), and the values to bind to them, and returns a PlanOperator.
+func (api *API) CompilePreparedPlan(ctx context.Context, q string, parameters []interface{}) (planner_types.PlanOperator, error) {
+	return api.server.CompilePreparedExecutionPlan(ctx, q, parameters)
+}
+
 func (api *API) RehydratePlanOperator(ctx context.Context, reader io.Reader) (planner_types.PlanOperator, error) {
 	return api.server.RehydratePlanOperator(ctx, reader)
 }
@@ -3222,6 +3494,9 @@ const (
 	apiMutexCheck
 	apiApplyChangeset
 	apiDeleteDataframe
+	apiRenameIndex
+	apiRenameField
+	apiChanges
 )
 
 var methodsCommon = map[apiMethod]struct{}{
@@ -3247,6 +3522,7 @@ var methodsDegraded = map[apiMethod]struct{}{
 	apiActiveQueries:     {},
 	apiPastQueries:       {},
 	apiPartitionNodes:    {},
+	apiChanges:           {},
 }
 
 var methodsNormal = map[apiMethod]struct{}{
@@ -3284,6 +3560,9 @@ var methodsNormal = map[apiMethod]struct{}{
 	apiMutexCheck:           {},
 	apiApplyChangeset:       {},
 	apiDeleteDataframe:      {},
+	apiRenameIndex:          {},
+	apiRenameField:          {},
+	apiChanges:              {},
 }
 
 func shardInShards(i dax.ShardNum, s dax.ShardNums) bool {
@@ -3313,6 +3592,11 @@ type SchemaAPI interface {
 
 	DeleteTable(ctx context.Context, tname dax.TableName) error
 	DeleteField(ctx context.Context, tname dax.TableName, fname dax.FieldName) error
+
+	RenameTable(ctx context.Context, tname dax.TableName, newName dax.TableName) error
+	RenameField(ctx context.Context, tname dax.TableName, fname dax.FieldName, newName dax.FieldName) error
+
+	SetTableOption(ctx context.Context, tname dax.TableName, option string, value string) error
 }
 
 // Ensure type implements interface.
@@ -3354,6 +3638,15 @@ func (n *NopSchemaAPI) DeleteTable(ctx context.Context, tname dax.TableName) err
 func (n *NopSchemaAPI) DeleteField(ctx context.Context, tname dax.TableName, fname dax.FieldName) error {
 	return nil
 }
+func (n *NopSchemaAPI) RenameTable(ctx context.Context, tname dax.TableName, newName dax.TableName) error {
+	return nil
+}
+func (n *NopSchemaAPI) RenameField(ctx context.Context, tname dax.TableName, fname dax.FieldName, newName dax.FieldName) error {
+	return nil
+}
+func (n *NopSchemaAPI) SetTableOption(ctx context.Context, tname dax.TableName, option string, value string) error {
+	return nil
+}
 
 type ClusterNode struct {
 	ID        string
@@ -3375,6 +3668,12 @@ type SystemAPI interface {
 	ClusterState() string
 	DataDir() string
 
+	// ExportDirectory is the directory sql FILE exports are written under,
+	// or empty if they are not allowed.
+	ExportDirectory() string
+	// ExportURLs are the urls sql URL exports can be posted to, or under.
+	ExportURLs() []string
+
 	NodeID() string
 	ClusterNodes() []ClusterNode
 }
@@ -3450,6 +3749,14 @@ func (fsapi *FeatureBaseSystemAPI) DataDir() string {
 	return fsapi.server.dataDir
 }
 
+func (fsapi *FeatureBaseSystemAPI) ExportDirectory() string {
+	return fsapi.server.exportDirectory
+}
+
+func (fsapi *FeatureBaseSystemAPI) ExportURLs() []string {
+	return fsapi.server.exportURLs
+}
+
 func (fsapi *FeatureBaseSystemAPI) NodeID() string {
 	return fsapi.cluster.Node.ID
 }
@@ -3515,6 +3822,14 @@ func (napi *NopSystemAPI) DataDir() string {
 	return ""
 }
 
+func (napi *NopSystemAPI) ExportDirectory() string {
+	return ""
+}
+
+func (napi *NopSystemAPI) ExportURLs() []string {
+	return nil
+}
+
 func (napi *NopSystemAPI) NodeID() string {
 	return ""
 }
@@ -632,6 +632,9 @@ func (api *API) loadShard(ctx context.Context, tkey dax.TableKey, shard dax.Shar
 				if err := api.Import(ctx, qcx, req, opts...); err != nil {
 					return errors.Wrapf(err, "import, table: %s, field: %s, shard: %d", msg.Table, msg.Field, msg.Shard)
 				}
+				if err := qcx.Finish(); err != nil {
+					return errors.Wrapf(err, "committing import, table: %s, field: %s, shard: %d", msg.Table, msg.Field, msg.Shard)
+				}
 
 			case *computer.ImportValueMessage:
 				req := &ImportValueRequest{
@@ -659,6 +662,9 @@ func (api *API) loadShard(ctx context.Context, tkey dax.TableKey, shard dax.Shar
 				if err := api.ImportValue(ctx, qcx, req, opts...); err != nil {
 					return errors.Wrapf(err, "import value, table: %s, field: %s, shard: %d", msg.Table, msg.Field, msg.Shard)
 				}
+				if err := qcx.Finish(); err != nil {
+					return errors.Wrapf(err, "committing import value, table: %s, field: %s, shard: %d", msg.Table, msg.Field, msg.Shard)
+				}
 			case *computer.ImportRoaringShardMessage:
 				req := &ImportRoaringShardRequest{
 					Remote:      true,
@@ -2,6 +2,7 @@
 package pilosa
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 	"github.com/apache/arrow/go/v10/parquet"
 	"github.com/apache/arrow/go/v10/parquet/file"
 	"github.com/apache/arrow/go/v10/parquet/pqarrow"
+	"github.com/featurebasedb/featurebase/v3/encryption"
 	"github.com/featurebasedb/featurebase/v3/pql"
 	"github.com/featurebasedb/featurebase/v3/tracing"
 	"github.com/gomem/gomem/pkg/dataframe"
@@ -437,10 +439,10 @@ func (e *executor) dataFrameExists(fname string) bool {
 
 func (e *executor) getDataTable(ctx context.Context, fname string, mem memory.Allocator) (arrow.Table, error) {
 	if e.typeIsParquet() {
-		table, err := readTableParquetCtx(ctx, fname, mem)
+		table, err := readTableParquetCtx(ctx, fname, mem, e.cipher)
 		return table, err
 	}
-	return readTableArrow(fname, mem)
+	return readTableArrow(fname, mem, e.cipher)
 }
 
 func (e *executor) typeIsParquet() bool {
@@ -456,9 +458,9 @@ func (e *executor) IsDataframeFile(name string) bool {
 
 func (e *executor) SaveTable(name string, table arrow.Table, mem memory.Allocator) error {
 	if e.typeIsParquet() {
-		return writeTableParquet(table, name)
+		return writeTableParquet(table, name, e.cipher)
 	}
-	return writeTableArrow(table, name, mem)
+	return writeTableArrow(table, name, mem, e.cipher)
 }
 
 func (e *executor) TableExtension() string {
@@ -468,11 +470,105 @@ func (e *executor) TableExtension() string {
 	return ".arrow"
 }
 
-func readTableArrow(filename string, mem memory.Allocator) (arrow.Table, error) {
-	r, err := os.Open(filename + ".arrow")
+// dataframeReader is the random access a dataframe file reader needs.
+type dataframeReader interface {
+	io.ReaderAt
+	io.ReadSeeker
+	io.Closer
+}
+
+// openDataframeFile opens a dataframe file, decrypting it with c if set.
+// Encrypted files are sealed as a whole, so they are read into memory.
+func openDataframeFile(filename string, c *encryption.Cipher) (dataframeReader, error) {
+	if c == nil {
+		return os.Open(filename)
+	}
+	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
+	if data, err = c.Open(nil, data); err != nil {
+		return nil, errors.Wrapf(err, "decrypting %s", filename)
+	}
+	return nopCloserReader{bytes.NewReader(data)}, nil
+}
+
+type nopCloserReader struct {
+	*bytes.Reader
+}
+
+func (nopCloserReader) Close() error { return nil }
+
+// dataframeWriter is written to by the dataframe file writers.
+type dataframeWriter interface {
+	io.WriteCloser
+	io.Seeker
+	Sync() error
+}
+
+// createDataframeFile creates a dataframe file, which is encrypted with c if
+// set.
+func createDataframeFile(filename string, c *encryption.Cipher) (dataframeWriter, error) {
+	if c == nil {
+		return os.Create(filename)
+	}
+	return &sealedDataframeFile{name: filename, cipher: c}, nil
+}
+
+// sealedDataframeFile buffers a dataframe file in memory, and writes it
+// encrypted when it is synced.
+type sealedDataframeFile struct {
+	name   string
+	cipher *encryption.Cipher
+	buf    bytes.Buffer
+	synced bool
+}
+
+func (f *sealedDataframeFile) Write(p []byte) (int, error) {
+	return f.buf.Write(p)
+}
+
+// Seek only reports the current position, which is all the arrow IPC
+// writer needs; the file is written sequentially.
+func (f *sealedDataframeFile) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence == io.SeekStart {
+		return 0, errors.New("seek not supported on encrypted dataframe file")
+	}
+	return int64(f.buf.Len()), nil
+}
+
+func (f *sealedDataframeFile) Sync() error {
+	if f.synced {
+		return nil
+	}
+	data, err := f.cipher.Seal(nil, f.buf.Bytes())
+	if err != nil {
+		return errors.Wrapf(err, "encrypting %s", f.name)
+	}
+	file, err := os.Create(f.name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		return err
+	} else if err := file.Sync(); err != nil {
+		return err
+	}
+	f.synced = true
+	return file.Close()
+}
+
+func (f *sealedDataframeFile) Close() error {
+	return f.Sync()
+}
+
+func readTableArrow(filename string, mem memory.Allocator, c *encryption.Cipher) (arrow.Table, error) {
+	r, err := openDataframeFile(filename+".arrow", c)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
 	rr, err := ipc.NewFileReader(r, ipc.WithAllocator(mem))
 	if err != nil {
 		return nil, err
@@ -495,8 +591,8 @@ func readTableArrow(filename string, mem memory.Allocator) (arrow.Table, error)
 	return table, nil
 }
 
-func readTableParquetCtx(ctx context.Context, filename string, mem memory.Allocator) (arrow.Table, error) {
-	r, err := os.Open(filename + ".parquet")
+func readTableParquetCtx(ctx context.Context, filename string, mem memory.Allocator, c *encryption.Cipher) (arrow.Table, error) {
+	r, err := openDataframeFile(filename+".parquet", c)
 	if err != nil {
 		return nil, err
 	}
@@ -514,8 +610,8 @@ func readTableParquetCtx(ctx context.Context, filename string, mem memory.Alloca
 	return reader.ReadTable(ctx)
 }
 
-func writeTableParquet(table arrow.Table, filename string) error {
-	f, err := os.Create(filename + ".parquet")
+func writeTableParquet(table arrow.Table, filename string, c *encryption.Cipher) error {
+	f, err := createDataframeFile(filename+".parquet", c)
 	if err != nil {
 		return err
 	}
@@ -531,8 +627,8 @@ func writeTableParquet(table arrow.Table, filename string) error {
 	return nil
 }
 
-func writeTableArrow(table arrow.Table, filename string, mem memory.Allocator) error {
-	f, err := os.Create(filename + ".arrow")
+func writeTableArrow(table arrow.Table, filename string, mem memory.Allocator, c *encryption.Cipher) error {
+	f, err := createDataframeFile(filename+".arrow", c)
 	if err != nil {
 		return err
 	}
@@ -557,6 +653,5 @@ func writeTableArrow(table arrow.Table, filename string, mem memory.Allocator) e
 	if err != nil {
 		panic(err)
 	}
-	f.Sync()
-	return nil
+	return f.Sync()
 }
@@ -2,6 +2,7 @@
 package pilosa
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"math/rand"
@@ -12,6 +13,7 @@ import (
 	"github.com/apache/arrow/go/v10/arrow"
 	"github.com/apache/arrow/go/v10/arrow/array"
 	"github.com/apache/arrow/go/v10/arrow/memory"
+	"github.com/featurebasedb/featurebase/v3/encryption"
 )
 
 func TempFileName(prefix string) string {
@@ -34,20 +36,38 @@ func Test_TableParquet(t *testing.T) {
 	b.Field(0).(*array.Float64Builder).AppendValues([]float64{1.0, 1.5, 2.0}, nil)
 	table := array.NewTableFromRecords(schema, []arrow.Record{b.NewRecord()})
 	defer table.Release()
-	fileName := TempFileName("pq-")
-	// save it as  a parquet file
-	err := writeTableParquet(table, fileName)
+	keyring, err := encryption.NewStaticKeyring(map[uint32][]byte{1: bytes.Repeat([]byte{1}, 32)})
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer os.Remove(fileName)
-
-	// read it back in and compare the result
-	got, err := readTableParquetCtx(context.Background(), fileName, mem)
+	cipher, err := encryption.NewCipher(keyring)
 	if err != nil {
-		t.Fatalf("readTableParquetCtx() error = %v", err)
+		t.Fatal(err)
 	}
-	if got.NumCols() != table.NumCols() {
-		t.Errorf("got:%v expected:%v", got.NumCols(), table.NumCols())
+
+	for _, c := range []*encryption.Cipher{nil, cipher} {
+		fileName := TempFileName("pq-")
+		// save it as  a parquet file
+		err := writeTableParquet(table, fileName, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(fileName + ".parquet")
+
+		// read it back in and compare the result
+		got, err := readTableParquetCtx(context.Background(), fileName, mem, c)
+		if err != nil {
+			t.Fatalf("readTableParquetCtx() error = %v", err)
+		}
+		if got.NumCols() != table.NumCols() {
+			t.Errorf("got:%v expected:%v", got.NumCols(), table.NumCols())
+		}
+
+		// an encrypted file cannot be read as plaintext.
+		if c != nil {
+			if _, err := readTableParquetCtx(context.Background(), fileName, mem, nil); err == nil {
+				t.Fatal("expected error reading encrypted file without a key")
+			}
+		}
 	}
 }
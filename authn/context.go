@@ -8,6 +8,7 @@ type contextKeyAccessToken struct{}
 type contextKeyRefreshToken struct{}
 type contextKeyUserInfo struct{}
 type contextKeyIndexes struct{}
+type contextKeyAdmin struct{}
 
 // GetAccessToken gets the access token from a context.
 func GetAccessToken(ctx context.Context) (token string, ok bool) {
@@ -52,3 +53,16 @@ func GetIndexes(ctx context.Context) (indexes []string, ok bool) {
 func WithIndexes(ctx context.Context, indexes []string) context.Context {
 	return context.WithValue(ctx, contextKeyUserInfo{}, indexes)
 }
+
+// GetAdmin gets whether the user a context was authorized for is an admin. ok
+// is false if the request was not authorized, as when auth is not turned on.
+func GetAdmin(ctx context.Context) (admin bool, ok bool) {
+	admin, ok = ctx.Value(contextKeyAdmin{}).(bool)
+	return
+}
+
+// WithAdmin makes a new Context recording whether the user it was authorized
+// for is an admin.
+func WithAdmin(ctx context.Context, admin bool) context.Context {
+	return context.WithValue(ctx, contextKeyAdmin{}, admin)
+}
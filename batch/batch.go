@@ -185,6 +185,9 @@ type Batch struct {
 	clearFrags     fragments
 
 	useShardTransactionalEndpoint bool
+
+	// ctx is the context the batch imports with.
+	ctx context.Context
 }
 
 func (b *Batch) Len() int { return len(b.ids) }
@@ -236,6 +239,15 @@ func OptUseShardTransactionalEndpoint(use bool) BatchOption {
 	}
 }
 
+// OptContext sets the context the batch imports with. It defaults to
+// context.Background().
+func OptContext(ctx context.Context) BatchOption {
+	return func(b *Batch) error {
+		b.ctx = ctx
+		return nil
+	}
+}
+
 func OptImporter(i featurebase.Importer) BatchOption {
 	return func(b *Batch) error {
 		b.importer = i
@@ -293,6 +305,9 @@ func NewBatch(importer featurebase.Importer, size int, tbl *dax.Table, fields []
 			rowIDs[i] = make([]uint64, 0, size)
 		case featurebase.FieldTypeBool:
 			boolValues[field.Name] = make(map[int]bool)
+		case featurebase.FieldTypeGeo:
+			// points arrive as the []uint64 of their cells (see
+			// featurebase.GeoRowIDs), and are handled like sets.
 		default:
 			return nil, errors.Errorf("field type '%s' is not currently supported through Batch", typ)
 		}
@@ -322,6 +337,7 @@ func NewBatch(importer featurebase.Importer, size int, tbl *dax.Table, fields []
 		keyTranslateBatchSize: DefaultKeyTranslateBatchSize,
 
 		log: logger.NopLogger,
+		ctx: context.Background(),
 
 		frags:      make(fragments),
 		clearFrags: make(fragments),
@@ -751,7 +767,7 @@ var ErrBatchNowStale = errors.New("batch is stale and needs to be imported (howe
 // continues.  split batch mode DOES NOT CURRENTLY SUPPORT MUTEX
 // OR INT FIELDS!
 func (b *Batch) Import() error {
-	ctx := context.Background()
+	ctx := b.ctx
 	start := time.Now()
 	if !b.useShardTransactionalEndpoint {
 		trns, err := b.importer.StartTransaction(ctx, "", b.prevDuration*10, false, time.Hour)
@@ -824,7 +840,7 @@ func (b *Batch) Import() error {
 // imports the stored data to Pilosa. Otherwise it simply returns
 // nil.
 func (b *Batch) Flush() error {
-	ctx := context.Background()
+	ctx := b.ctx
 
 	if !b.splitBatchMode {
 		return nil
@@ -1082,7 +1098,7 @@ func (b *Batch) doTranslation() error {
 }
 
 func (b *Batch) createIndexKeys(keys ...string) (map[string]uint64, error) {
-	ctx := context.Background()
+	ctx := b.ctx
 
 	batchSize := b.keyTranslateBatchSize
 	if batchSize <= 0 || len(keys) <= batchSize {
@@ -1113,7 +1129,7 @@ func (b *Batch) createIndexKeys(keys ...string) (map[string]uint64, error) {
 }
 
 func (b *Batch) createFieldKeys(field *featurebase.FieldInfo, keys ...string) (map[string]uint64, error) {
-	ctx := context.Background()
+	ctx := b.ctx
 
 	batchSize := b.keyTranslateBatchSize
 	if batchSize <= 0 || len(keys) <= batchSize {
@@ -1144,7 +1160,7 @@ func (b *Batch) createFieldKeys(field *featurebase.FieldInfo, keys ...string) (m
 }
 
 func (b *Batch) doImportShardTransactional(frags, clearFrags fragments) error {
-	ctx := context.Background()
+	ctx := b.ctx
 
 	start := time.Now()
 	requests := make(map[uint64]*featurebase.ImportRoaringShardRequest)
@@ -1218,7 +1234,7 @@ func (b *Batch) doImportShardTransactional(frags, clearFrags fragments) error {
 }
 
 func (b *Batch) doImport(frags, clearFrags fragments) error {
-	ctx := context.Background()
+	ctx := b.ctx
 
 	start := time.Now()
 	eg := egpool.Group{PoolSize: 20}
@@ -1712,7 +1728,7 @@ func (v *valsByIDsSortable) Swap(i, j int) {
 
 // importValueData imports data for int fields.
 func (b *Batch) importValueData() error {
-	ctx := context.Background()
+	ctx := b.ctx
 
 	shardWidth := uint64(featurebase.ShardWidth)
 	eg := egpool.Group{PoolSize: 20}
@@ -1804,7 +1820,7 @@ func (v *rowsByIDsSortable) Swap(i, j int) {
 // TODO this should work for bools as well - just need to support them
 // at batch creation time and when calling Add, I think.
 func (b *Batch) importMutexData() error {
-	ctx := context.Background()
+	ctx := b.ctx
 
 	shardWidth := uint64(featurebase.ShardWidth)
 
@@ -73,6 +73,9 @@ const (
 	messageTypeUNUSED3 // used to be ResizeAbortMessage
 	messageTypeUpdateField
 	messageTypeDeleteDataframe
+	messageTypeRenameIndex
+	messageTypeRenameField
+	messageTypeUpdateIndex
 )
 
 // MarshalInternalMessage serializes the pilosa message and adds pilosa internal
@@ -120,6 +123,12 @@ func getMessage(typ byte) Message {
 		return &UpdateFieldMessage{}
 	case messageTypeDeleteDataframe:
 		return &DeleteDataframeMessage{}
+	case messageTypeRenameIndex:
+		return &RenameIndexMessage{}
+	case messageTypeRenameField:
+		return &RenameFieldMessage{}
+	case messageTypeUpdateIndex:
+		return &UpdateIndexMessage{}
 	default:
 		panic(fmt.Sprintf("unknown message type %d", typ))
 	}
@@ -159,6 +168,12 @@ func getMessageType(m Message) byte {
 		return messageTypeUpdateField
 	case *DeleteDataframeMessage:
 		return messageTypeDeleteDataframe
+	case *RenameIndexMessage:
+		return messageTypeRenameIndex
+	case *RenameFieldMessage:
+		return messageTypeRenameField
+	case *UpdateIndexMessage:
+		return messageTypeUpdateIndex
 	default:
 		panic(fmt.Sprintf("don't have type for message %#v", m))
 	}
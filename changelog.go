@@ -0,0 +1,546 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package pilosa
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/roaring"
+	"github.com/pkg/errors"
+)
+
+// ChangesDir is the directory in each index where the change log is stored.
+const ChangesDir = "changes"
+
+const (
+	changeLogSegmentExt = ".log"
+
+	defaultChangeCaptureSegmentSize = 64 << 20
+	defaultChangeCaptureSegments    = 16
+)
+
+// ChangeOp is the kind of change a ChangeEvent describes.
+type ChangeOp string
+
+const (
+	// ChangeOpInsert is values written to records which didn't exist.
+	ChangeOpInsert ChangeOp = "insert"
+	// ChangeOpUpdate is values written to records which already existed, or
+	// to records of an index which doesn't track existence.
+	ChangeOpUpdate ChangeOp = "update"
+	// ChangeOpClear is values cleared from records.
+	ChangeOpClear ChangeOp = "clear"
+	// ChangeOpDelete is records deleted.
+	ChangeOpDelete ChangeOp = "delete"
+)
+
+// ChangeEvent describes a change to some of the records in one shard of an
+// index. Events identify the records and fields which changed rather than the
+// values written; a consumer mirroring a table reads the current values of the
+// records.
+type ChangeEvent struct {
+	// Offset is the position of the event in the change log of the node
+	// which recorded it. Offsets start at 1 and increase by 1.
+	Offset uint64    `json:"offset"`
+	Time   time.Time `json:"time"`
+
+	Index  string   `json:"index"`
+	Shard  uint64   `json:"shard"`
+	Op     ChangeOp `json:"op"`
+	Fields []string `json:"fields,omitempty"`
+
+	Columns    []uint64 `json:"columns"`
+	ColumnKeys []string `json:"columnKeys,omitempty"`
+}
+
+// ChangeCaptureConfig configures the change log each index keeps of the
+// changes to its records.
+type ChangeCaptureConfig struct {
+	// Enabled turns on recording of change events.
+	Enabled bool `toml:"enabled"`
+
+	// SegmentSize is the size in bytes at which a change log starts a new
+	// segment file.
+	SegmentSize int64 `toml:"segment-size"`
+
+	// Segments is the number of segment files kept for each index. When a
+	// new segment is started the oldest is deleted, and with it the events
+	// it holds.
+	Segments int `toml:"segments"`
+}
+
+// NewDefaultChangeCaptureConfig returns a ChangeCaptureConfig with change
+// capture disabled.
+func NewDefaultChangeCaptureConfig() ChangeCaptureConfig {
+	return ChangeCaptureConfig{
+		SegmentSize: defaultChangeCaptureSegmentSize,
+		Segments:    defaultChangeCaptureSegments,
+	}
+}
+
+// changeLog is the log of the change events of an index. Events are appended
+// as lines of JSON to segment files, each named by the offset of its first
+// event.
+type changeLog struct {
+	mu sync.Mutex
+
+	path  string
+	cfg   ChangeCaptureConfig
+	fsync bool
+
+	// the first offset in each segment, in order
+	segments []uint64
+
+	// the last segment, which events are appended to
+	file *os.File
+	size int64
+
+	// the offset of the next event
+	next uint64
+
+	// closed and replaced when events are appended or the log is closed
+	appended chan struct{}
+	closed   bool
+}
+
+func openChangeLog(path string, cfg ChangeCaptureConfig, fsync bool) (*changeLog, error) {
+	if cfg.SegmentSize <= 0 {
+		cfg.SegmentSize = defaultChangeCaptureSegmentSize
+	}
+	if cfg.Segments <= 0 {
+		cfg.Segments = defaultChangeCaptureSegments
+	}
+	l := &changeLog{
+		path:     path,
+		cfg:      cfg,
+		fsync:    fsync,
+		next:     1,
+		appended: make(chan struct{}),
+	}
+
+	if err := os.MkdirAll(path, 0o750); err != nil {
+		return nil, errors.Wrap(err, "creating change log directory")
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading change log directory")
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, changeLogSegmentExt) {
+			continue
+		}
+		first, err := strconv.ParseUint(strings.TrimSuffix(name, changeLogSegmentExt), 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing change log segment name '%s'", name)
+		}
+		l.segments = append(l.segments, first)
+	}
+	sort.Slice(l.segments, func(i, j int) bool { return l.segments[i] < l.segments[j] })
+
+	if len(l.segments) == 0 {
+		if err := l.startSegment(); err != nil {
+			return nil, err
+		}
+		return l, nil
+	}
+
+	// find the next offset from the last segment, dropping any partially
+	// written event at its end
+	last := l.segments[len(l.segments)-1]
+	l.next = last
+	f, err := os.OpenFile(l.segmentPath(last), os.O_RDWR, 0o640)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening change log segment")
+	}
+	var size int64
+	rd := bufio.NewReader(f)
+	for {
+		line, err := rd.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			f.Close()
+			return nil, errors.Wrap(err, "reading change log segment")
+		}
+		var ev ChangeEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			break
+		}
+		size += int64(len(line))
+		l.next = ev.Offset + 1
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "truncating change log segment")
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "seeking change log segment")
+	}
+	l.file, l.size = f, size
+	return l, nil
+}
+
+func (l *changeLog) segmentPath(first uint64) string {
+	return filepath.Join(l.path, fmt.Sprintf("%020d%s", first, changeLogSegmentExt))
+}
+
+// startSegment closes the current segment and starts a new one beginning at
+// the next offset, deleting the oldest segments beyond the configured number.
+func (l *changeLog) startSegment() error {
+	if l.file != nil {
+		if err := l.file.Close(); err != nil {
+			return errors.Wrap(err, "closing change log segment")
+		}
+		l.file = nil
+	}
+	f, err := os.OpenFile(l.segmentPath(l.next), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0o640)
+	if err != nil {
+		return errors.Wrap(err, "creating change log segment")
+	}
+	l.file, l.size = f, 0
+	l.segments = append(l.segments, l.next)
+
+	for len(l.segments) > l.cfg.Segments {
+		if err := os.Remove(l.segmentPath(l.segments[0])); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "removing change log segment")
+		}
+		l.segments = l.segments[1:]
+	}
+	return nil
+}
+
+// append assigns offsets to events and writes them to the log.
+func (l *changeLog) append(events ...*ChangeEvent) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return errors.New("change log is closed")
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	now := time.Now().UTC()
+	for _, ev := range events {
+		ev.Offset = l.next
+		ev.Time = now
+		if err := enc.Encode(ev); err != nil {
+			return errors.Wrap(err, "encoding change event")
+		}
+		l.next++
+	}
+	n, err := l.file.Write(buf.Bytes())
+	l.size += int64(n)
+	if err != nil {
+		return errors.Wrap(err, "writing change events")
+	}
+	if l.fsync {
+		if err := l.file.Sync(); err != nil {
+			return errors.Wrap(err, "syncing change log segment")
+		}
+	}
+
+	close(l.appended)
+	l.appended = make(chan struct{})
+
+	if l.size >= l.cfg.SegmentSize {
+		return l.startSegment()
+	}
+	return nil
+}
+
+// bounds returns the offsets of the first and last events in the log. If the
+// log is empty, last is first-1.
+func (l *changeLog) bounds() (first, last uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.segments[0], l.next - 1
+}
+
+// read returns up to limit events with offsets after since. If since is 0,
+// events are read from the first in the log. It returns
+// ErrChangeOffsetExpired if events after since have been dropped.
+func (l *changeLog) read(since uint64, limit int) ([]*ChangeEvent, error) {
+	l.mu.Lock()
+	segments := append([]uint64(nil), l.segments...)
+	last := l.next - 1
+	l.mu.Unlock()
+
+	if since == 0 {
+		since = segments[0] - 1
+	} else if since+1 < segments[0] {
+		return nil, ErrChangeOffsetExpired
+	}
+	if since >= last {
+		return nil, nil
+	}
+
+	// start with the last segment beginning at or before the first offset
+	// wanted
+	i := sort.Search(len(segments), func(i int) bool { return segments[i] > since+1 }) - 1
+
+	var events []*ChangeEvent
+	for ; i < len(segments); i++ {
+		f, err := os.Open(l.segmentPath(segments[i]))
+		if os.IsNotExist(err) {
+			// dropped since we looked
+			return nil, ErrChangeOffsetExpired
+		} else if err != nil {
+			return nil, errors.Wrap(err, "opening change log segment")
+		}
+		done, err := func() (bool, error) {
+			defer f.Close()
+			rd := bufio.NewReader(f)
+			for {
+				line, err := rd.ReadBytes('\n')
+				if err == io.EOF {
+					return false, nil
+				} else if err != nil {
+					return false, errors.Wrap(err, "reading change log segment")
+				}
+				ev := &ChangeEvent{}
+				if err := json.Unmarshal(line, ev); err != nil {
+					return false, errors.Wrap(err, "decoding change event")
+				}
+				if ev.Offset <= since {
+					continue
+				}
+				events = append(events, ev)
+				if len(events) == limit || ev.Offset >= last {
+					return true, nil
+				}
+			}
+		}()
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			break
+		}
+	}
+	return events, nil
+}
+
+// wait blocks until there are events after since, the context is done or
+// the log is closed.
+func (l *changeLog) wait(ctx context.Context, since uint64) error {
+	l.mu.Lock()
+	closed, last, appended := l.closed, l.next-1, l.appended
+	l.mu.Unlock()
+	if closed || last > since {
+		return nil
+	}
+	select {
+	case <-appended:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *changeLog) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	close(l.appended)
+	return errors.Wrap(l.file.Close(), "closing change log segment")
+}
+
+// changeSet collects the records changed by a write to one shard of an index
+// so they can be recorded as change events once the write is done.
+type changeSet struct {
+	index string
+	shard uint64
+
+	// the records which existed before the write, or nil if unknown
+	existed *Row
+
+	// the fields written to each record, and cleared from each record
+	set   map[string]*Row
+	clear map[string]*Row
+}
+
+func newChangeSet(index string, shard uint64, existed *Row) *changeSet {
+	return &changeSet{
+		index:   index,
+		shard:   shard,
+		existed: existed,
+		set:     make(map[string]*Row),
+		clear:   make(map[string]*Row),
+	}
+}
+
+func (c *changeSet) addSet(field string, columns *Row) {
+	if columns == nil || !columns.Any() {
+		return
+	}
+	if prev, ok := c.set[field]; ok {
+		columns = prev.Union(columns)
+	}
+	c.set[field] = columns
+}
+
+func (c *changeSet) addClear(field string, columns *Row) {
+	if columns == nil || !columns.Any() {
+		return
+	}
+	if prev, ok := c.clear[field]; ok {
+		columns = prev.Union(columns)
+	}
+	c.clear[field] = columns
+}
+
+// events returns an event for each kind of change in the set. A record which
+// has values both cleared and set, as when a mutex value is replaced, is an
+// update.
+func (c *changeSet) events() []*ChangeEvent {
+	set, setFields := unionChangedColumns(c.set)
+	cleared, clearFields := unionChangedColumns(c.clear)
+
+	var events []*ChangeEvent
+	add := func(op ChangeOp, fields []string, columns *Row) {
+		if !columns.Any() {
+			return
+		}
+		events = append(events, &ChangeEvent{
+			Index:   c.index,
+			Shard:   c.shard,
+			Op:      op,
+			Fields:  fields,
+			Columns: columns.Columns(),
+		})
+	}
+
+	if c.existed == nil {
+		add(ChangeOpUpdate, setFields, set)
+	} else {
+		add(ChangeOpInsert, setFields, set.Difference(c.existed))
+		add(ChangeOpUpdate, setFields, set.Intersect(c.existed))
+	}
+	add(ChangeOpClear, clearFields, cleared.Difference(set))
+	return events
+}
+
+// unionChangedColumns returns the union of the columns changed in each field,
+// and the sorted names of the fields.
+func unionChangedColumns(byField map[string]*Row) (*Row, []string) {
+	columns := NewRow()
+	fields := make([]string, 0, len(byField))
+	for field, cols := range byField {
+		columns = columns.Union(cols)
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return columns, fields
+}
+
+// roaringColumns returns the columns of a shard which have a bit set in any
+// row of a roaring encoded bit matrix.
+func roaringColumns(data []byte, shard uint64) (*Row, error) {
+	if len(data) == 0 {
+		return NewRow(), nil
+	}
+	bm := roaring.NewBitmap()
+	if err := bm.UnmarshalBinary(data); err != nil {
+		return nil, errors.Wrap(err, "decoding roaring data")
+	}
+	cols := roaring.NewBitmap()
+	var rowID uint64
+	for bit, ok := bm.MinAt(0); ok; bit, ok = bm.MinAt(rowID * ShardWidth) {
+		rowID = bit / ShardWidth
+		cols = cols.Union(bm.OffsetRange(shard*ShardWidth, rowID*ShardWidth, (rowID+1)*ShardWidth))
+		rowID++
+	}
+	// the containers may still refer to data, which an import can modify
+	return NewRowFromBitmap(cols.Clone()), nil
+}
+
+// existenceRow returns the records which exist in a shard of an index, or nil
+// if the index doesn't track existence.
+func (i *Index) existenceRow(tx Tx, shard uint64) (*Row, error) {
+	ef := i.existenceField()
+	if ef == nil {
+		return nil, nil
+	}
+	v := ef.view(viewStandard)
+	if v == nil {
+		return NewRow(), nil
+	}
+	frag := v.Fragment(shard)
+	if frag == nil {
+		return NewRow(), nil
+	}
+	row, err := frag.row(tx, 0)
+	if err != nil {
+		return nil, err
+	}
+	// the row may refer to storage which changes once tx is done
+	return row.Clone(), nil
+}
+
+// recordChanges appends events to the change log of an index, if it has one.
+// The write has already happened, so an error recording it is logged rather
+// than returned.
+func (i *Index) recordChanges(events ...*ChangeEvent) {
+	if i.changes == nil || len(events) == 0 {
+		return
+	}
+	if err := i.changes.append(events...); err != nil {
+		i.holder.Logger.Errorf("recording changes to index %s: %v", i.name, err)
+	}
+}
+
+// recordChangeSet records the events of a change set.
+func (i *Index) recordChangeSet(c *changeSet) {
+	if c == nil {
+		return
+	}
+	i.recordChanges(c.events()...)
+}
+
+// recordsChanges returns true if changes to a shard of an index are recorded
+// on this node, which is the shard's primary owner.
+func (c *cluster) recordsChanges(index string, shard uint64) bool {
+	nodes := c.NewSnapshot().ShardNodes(index, shard)
+	return len(nodes) > 0 && nodes[0].ID == c.Node.ID
+}
+
+// newChangeSet returns a change set for a write to a shard of the index,
+// reading the records which already exist with tx.
+func (i *Index) newChangeSet(tx Tx, shard uint64) (*changeSet, error) {
+	existed, err := i.existenceRow(tx, shard)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading existing records")
+	}
+	return newChangeSet(i.name, shard, existed), nil
+}
+
+// importChangeSet returns a change set for an import into a shard of an
+// index, or nil if changes to the shard aren't recorded on this node.
+func (api *API) importChangeSet(qcx *Qcx, idx *Index, shard uint64) (_ *changeSet, err0 error) {
+	if idx.changes == nil || !api.cluster.recordsChanges(idx.name, shard) {
+		return nil, nil
+	}
+	tx, finisher, err := qcx.GetTx(Txo{Write: true, Index: idx, Shard: shard})
+	if err != nil {
+		return nil, err
+	}
+	defer finisher(&err0)
+	return idx.newChangeSet(tx, shard)
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package pilosa
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChangeLog(t *testing.T) {
+	path := t.TempDir()
+	cfg := ChangeCaptureConfig{SegmentSize: 1 << 20, Segments: 2}
+	l, err := openChangeLog(path, cfg, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if events, err := l.read(0, 10); err != nil {
+		t.Fatal(err)
+	} else if len(events) != 0 {
+		t.Fatalf("expected no events, got %d", len(events))
+	}
+
+	for i := uint64(0); i < 5; i++ {
+		if err := l.append(&ChangeEvent{Index: "i", Op: ChangeOpUpdate, Columns: []uint64{i}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Read", func(t *testing.T) {
+		events, err := l.read(2, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 2 || events[0].Offset != 3 || events[1].Offset != 4 {
+			t.Fatalf("unexpected events: %+v", events)
+		}
+		if !reflect.DeepEqual(events[0].Columns, []uint64{2}) {
+			t.Fatalf("unexpected columns: %v", events[0].Columns)
+		}
+
+		if events, err := l.read(5, 10); err != nil {
+			t.Fatal(err)
+		} else if len(events) != 0 {
+			t.Fatalf("expected no events after the last, got %d", len(events))
+		}
+	})
+
+	t.Run("Wait", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := l.wait(ctx, 5); err != context.DeadlineExceeded {
+			t.Fatalf("expected deadline exceeded, got %v", err)
+		}
+
+		done := make(chan error)
+		go func() { done <- l.wait(context.Background(), 5) }()
+		if err := l.append(&ChangeEvent{Index: "i", Op: ChangeOpDelete, Columns: []uint64{5}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Reopen", func(t *testing.T) {
+		if err := l.close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// a partially written event is dropped when the log is opened
+		f, err := os.OpenFile(l.segmentPath(1), os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.WriteString(`{"offset":7,"ind`); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		if l, err = openChangeLog(path, cfg, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.append(&ChangeEvent{Index: "i", Op: ChangeOpInsert, Columns: []uint64{6}}); err != nil {
+			t.Fatal(err)
+		}
+		events, err := l.read(5, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 2 || events[0].Op != ChangeOpDelete || events[1].Offset != 7 || events[1].Op != ChangeOpInsert {
+			t.Fatalf("unexpected events: %+v", events)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		if err := l.close(); err != nil {
+			t.Fatal(err)
+		}
+		// every event starts a new segment, and only two are kept
+		cfg := ChangeCaptureConfig{SegmentSize: 1, Segments: 2}
+		if l, err = openChangeLog(path, cfg, false); err != nil {
+			t.Fatal(err)
+		}
+		defer l.close()
+		for i := 0; i < 3; i++ {
+			if err := l.append(&ChangeEvent{Index: "i", Op: ChangeOpUpdate}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if first, last := l.bounds(); first != 10 || last != 10 {
+			t.Fatalf("unexpected bounds: %d, %d", first, last)
+		}
+		if _, err := l.read(5, 10); err != ErrChangeOffsetExpired {
+			t.Fatalf("expected ErrChangeOffsetExpired, got %v", err)
+		}
+		if events, err := l.read(0, 10); err != nil {
+			t.Fatal(err)
+		} else if len(events) != 1 || events[0].Offset != 10 {
+			t.Fatalf("unexpected events: %+v", events)
+		}
+	})
+}
+
+func TestChangeSet_Events(t *testing.T) {
+	c := newChangeSet("i", 0, NewRow(1, 2))
+	c.addSet("a", NewRow(1, 3))
+	c.addSet("b", NewRow(4))
+	c.addClear("a", NewRow(2, 3))
+
+	exp := []*ChangeEvent{
+		{Index: "i", Op: ChangeOpInsert, Fields: []string{"a", "b"}, Columns: []uint64{3, 4}},
+		{Index: "i", Op: ChangeOpUpdate, Fields: []string{"a", "b"}, Columns: []uint64{1}},
+		{Index: "i", Op: ChangeOpClear, Fields: []string{"a"}, Columns: []uint64{2}},
+	}
+	if events := c.events(); !reflect.DeepEqual(events, exp) {
+		t.Fatalf("unexpected events:\n%+v\nexpected:\n%+v", events, exp)
+	}
+
+	// without existence every write is an update
+	c = newChangeSet("i", 0, nil)
+	c.addSet("a", NewRow(1))
+	if events := c.events(); len(events) != 1 || events[0].Op != ChangeOpUpdate {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/featurebasedb/featurebase/v3/cli/fbcloud"
 	"github.com/featurebasedb/featurebase/v3/errors"
 	"github.com/featurebasedb/featurebase/v3/logger"
+	uuid "github.com/satori/go.uuid"
 )
 
 const (
@@ -604,9 +605,14 @@ func (cmd *Command) setupClient() error {
 	switch typ {
 	case featurebaseTypeOnPremClassic:
 		p.Printf("Detected on-prem, classic deployment.\n")
+		sessionID, err := uuid.NewV4()
+		if err != nil {
+			return errors.Wrap(err, "generating session id")
+		}
 		cmd.Queryer = &standardQueryer{
-			Host: cmd.host,
-			Port: cmd.port,
+			Host:      cmd.host,
+			Port:      cmd.port,
+			SessionID: sessionID.String(),
 		}
 	case featurebaseTypeOnPremServerless:
 		p.Printf("Detected on-prem, serverless deployment.\n")
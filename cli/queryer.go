@@ -27,16 +27,27 @@ func (qryr *nopQueryer) Query(org string, db string, sql io.Reader) (*featurebas
 var _ Queryer = (*standardQueryer)(nil)
 
 // standardQueryer supports a standard featurebase deployment hitting the /sql
-// endpoint with a payload containing only the sql statement.
+// endpoint with a payload containing only the sql statement. Every query is
+// sent with SessionID so that a transaction can span multiple statements.
 type standardQueryer struct {
-	Host string
-	Port string
+	Host      string
+	Port      string
+	SessionID string
 }
 
 func (qryr *standardQueryer) Query(org string, db string, sql io.Reader) (*featurebase.WireQueryResponse, error) {
 	url := fmt.Sprintf("%s/sql", hostPort(qryr.Host, qryr.Port))
 
-	resp, err := http.Post(url, "application/json", sql)
+	req, err := http.NewRequest(http.MethodPost, url, sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating new post request")
+	}
+	req.Header.Add("Content-Type", "application/json")
+	if qryr.SessionID != "" {
+		req.Header.Add(featurebase.HeaderSQLSessionID, qryr.SessionID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, errors.Wrapf(err, "posting query")
 	}
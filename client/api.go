@@ -110,6 +110,16 @@ func (s *schemaAPI) DeleteTable(ctx context.Context, tname dax.TableName) error
 func (s *schemaAPI) DeleteField(ctx context.Context, tname dax.TableName, fname dax.FieldName) error {
 	return errors.New(errors.ErrUncoded, "schemaAPI.DeleteField not implemented")
 }
+func (s *schemaAPI) RenameTable(ctx context.Context, tname dax.TableName, newName dax.TableName) error {
+	return errors.New(errors.ErrUncoded, "schemaAPI.RenameTable not implemented")
+}
+func (s *schemaAPI) RenameField(ctx context.Context, tname dax.TableName, fname dax.FieldName, newName dax.FieldName) error {
+	return errors.New(errors.ErrUncoded, "schemaAPI.RenameField not implemented")
+}
+
+func (s *schemaAPI) SetTableOption(ctx context.Context, tname dax.TableName, option string, value string) error {
+	return errors.New(errors.ErrUncoded, "schemaAPI.SetTableOption not implemented")
+}
 
 func (s *schemaAPI) addFieldToIndex(idx *Index, fieldName string, ffos featurebase.FieldOptions) (*Field, error) {
 	cfos := []FieldOption{}
@@ -438,26 +438,29 @@ func (c *cluster) createFieldKeys(ctx context.Context, field *Field, keys ...str
 	return translations, nil
 }
 
-func (c *cluster) matchField(ctx context.Context, field *Field, like string) ([]uint64, error) {
+// matchField finds the IDs of the keys in a field matching a pattern. The
+// syntax of the pattern is one of "like", "regexp" or "glob".
+func (c *cluster) matchField(ctx context.Context, field *Field, syntax, pattern string) ([]uint64, error) {
 	// The primary is the only node that can match field keys, since it is the only node with all of the keys.
 	primary := c.primaryNode()
 	if primary == nil {
-		return nil, errors.Errorf("matching field(%s/%s) like %q - cannot find primary node", field.Index(), field.Name(), like)
+		return nil, errors.Errorf("matching field(%s/%s) %s %q - cannot find primary node", field.Index(), field.Name(), syntax, pattern)
 	}
 	if c.Node.ID == primary.ID {
 		// The local copy is the authoritative copy.
-		plan := planLike(like)
+		match, err := newKeyMatcher(syntax, pattern)
+		if err != nil {
+			return nil, err
+		}
 		store := field.TranslateStore()
 		if store == nil {
 			return nil, ErrTranslateStoreNotFound
 		}
-		return field.TranslateStore().Match(func(key []byte) bool {
-			return matchLike(key, plan...)
-		})
+		return store.Match(match)
 	}
 
 	// Forward the request to the primary.
-	return c.InternalClient.MatchFieldKeysNode(ctx, &primary.URI, field.Index(), field.Name(), like)
+	return c.InternalClient.MatchFieldKeysNode(ctx, &primary.URI, field.Index(), field.Name(), syntax, pattern)
 }
 
 func (c *cluster) translateFieldIDs(ctx context.Context, field *Field, ids map[uint64]struct{}) (map[uint64]string, error) {
@@ -888,7 +891,11 @@ func (c *cluster) translateIndexIDSet(ctx context.Context, indexName string, idS
 }
 
 func (c *cluster) NewSnapshot() *disco.ClusterSnapshot {
-	return disco.NewClusterSnapshot(c.noder, c.Hasher, c.partitionAssigner, c.ReplicaN)
+	snap := disco.NewClusterSnapshot(c.noder, c.Hasher, c.partitionAssigner, c.ReplicaN)
+	if c.holder != nil {
+		snap.PartitionKey = c.holder.partitionKey
+	}
+	return snap
 }
 
 // ClusterStatus describes the status of the cluster including its
@@ -926,6 +933,20 @@ type DeleteIndexMessage struct {
 	Index string
 }
 
+// UpdateIndexMessage represents a change to an existing index. The
+// CreateIndexMessage holds the changed index, while the update shows
+// the change that was made.
+type UpdateIndexMessage struct {
+	CreateIndexMessage CreateIndexMessage
+	Update             IndexUpdate
+}
+
+// RenameIndexMessage is an internal message indicating an index was renamed.
+type RenameIndexMessage struct {
+	Index    string
+	NewIndex string
+}
+
 // CreateFieldMessage is an internal message indicating field creation.
 type CreateFieldMessage struct {
 	Index     string
@@ -949,6 +970,13 @@ type DeleteFieldMessage struct {
 	Field string
 }
 
+// RenameFieldMessage is an internal message indicating a field was renamed.
+type RenameFieldMessage struct {
+	Index    string
+	Field    string
+	NewField string
+}
+
 // DeleteAvailableShardMessage is an internal message indicating available shard deletion.
 type DeleteAvailableShardMessage struct {
 	Index   string
@@ -21,6 +21,7 @@ Takes all input files and writes PartitionN numbered files to a directory, where
 	flags := ccmd.Flags()
 	flags.StringVarP(&cmd.File, "file", "", "", "Input file or directory.")
 	flags.StringVarP(&cmd.Table, "table", "", "", "Name of table (used to hash keys to determine partition).")
+	flags.StringVarP(&cmd.PartitionKey, "partition-key", "", "", "Name the table was created with, if it has been renamed since (used instead of table to hash keys).")
 	flags.StringVarP(&cmd.Type, "type", "", cmd.Type, "Input file type (csv or ndjson).")
 	flags.StringSliceVar(&cmd.PrimaryKeyFields, "primary-key-fields", []string{}, "Names of primary key fields. For CSV there must be a header row and these are pulled from there.")
 	flags.IntVar(&cmd.PartitionN, "partition-n", cmd.PartitionN, "Number of partitions.")
@@ -24,6 +24,8 @@ Provides a set of commands for inspecting RBF data files.
 	cmd.AddCommand(newRBFDumpCommand(logdest))
 	cmd.AddCommand(newRBFPagesCommand(logdest))
 	cmd.AddCommand(newRBFPageCommand(logdest))
+	cmd.AddCommand(newRBFReencryptCommand(logdest))
+	cmd.AddCommand(newRBFVacuumCommand(logdest))
 	return cmd
 }
 
@@ -137,3 +139,61 @@ Prints the header & cell data for one or more pages.
 	}
 	return cmd
 }
+
+func newRBFReencryptCommand(logdest logger.Logger) *cobra.Command {
+	c := ctl.NewRBFReencryptCommand(logdest)
+	cmd := &cobra.Command{
+		Use:   "reencrypt [flags] PATH",
+		Short: "Re-encrypts RBF data.",
+		Long: `
+Rewrites every page of an RBF data directory with the current key of a
+keyfile, for example after adding a new key to rotate keys. It can also
+encrypt a plaintext database or decrypt an encrypted one. The database must
+not be in use by a running server.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("data directory path required")
+			} else if len(args) > 1 {
+				return fmt.Errorf("too many command line arguments")
+			}
+			c.Path = args[0]
+			return nil
+		},
+		RunE: UsageErrorWrapper(c),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&c.Keyfile, "keyfile", "", "Keyfile to encrypt with, and to decrypt with unless --from-keyfile is set")
+	flags.StringVar(&c.FromKeyfile, "from-keyfile", "", "Keyfile the data is currently encrypted with")
+	flags.BoolVar(&c.FromPlaintext, "from-plaintext", false, "Data is currently not encrypted")
+	flags.BoolVar(&c.ToPlaintext, "to-plaintext", false, "Decrypt the data rather than re-encrypting it")
+	return cmd
+}
+
+func newRBFVacuumCommand(logdest logger.Logger) *cobra.Command {
+	c := ctl.NewRBFVacuumCommand(logdest)
+	cmd := &cobra.Command{
+		Use:   "vacuum [flags] PATH",
+		Short: "Compacts RBF data.",
+		Long: `
+Moves in-use pages toward the front of an RBF data file and truncates the
+free pages left at the end, reclaiming space after bitmaps or fields are
+deleted. The database must not be in use by a running server.
+`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("data directory path required")
+			} else if len(args) > 1 {
+				return fmt.Errorf("too many command line arguments")
+			}
+			c.Path = args[0]
+			return nil
+		},
+		RunE: UsageErrorWrapper(c),
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&c.Keyfile, "keyfile", "", "Keyfile the data is encrypted with")
+	return cmd
+}
@@ -7,6 +7,7 @@ import "context"
 type contextKeyOriginalIP struct{}
 type contextKeyRequestUserID struct{}
 type contextKeyRequestRequestID struct{}
+type contextKeySessionID struct{}
 
 // OriginalIP gets the original IP from the context.
 func OriginalIP(ctx context.Context) (originalIP string, ok bool) {
@@ -36,3 +37,14 @@ func RequestID(ctx context.Context) (userID string, ok bool) {
 func WithRequestID(ctx context.Context, userID string) context.Context {
 	return context.WithValue(ctx, contextKeyRequestRequestID{}, userID)
 }
+
+// SessionID gets the id of the sql session the request belongs to.
+func SessionID(ctx context.Context) (sessionID string, ok bool) {
+	sessionID, ok = ctx.Value(contextKeySessionID{}).(string)
+	return
+}
+
+// WithSessionID makes a new context with the sql session id in the context.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, contextKeySessionID{}, sessionID)
+}
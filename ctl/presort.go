@@ -26,6 +26,11 @@ type PreSortCommand struct {
 
 	Table string
 
+	// PartitionKey is the name keys are hashed by to find their partition.
+	// It is the name the table was created with, which is only different
+	// from Table if the table has been renamed. Defaults to Table.
+	PartitionKey string
+
 	PrimaryKeyFields    []string
 	PrimaryKeySeparator string
 
@@ -208,9 +213,17 @@ func (cmd *PreSortCommand) outputNDJSON(partition int, record map[string]interfa
 	return cmd.outputEncoders[partition].Encode(record)
 }
 
+// partitionKey returns the name keys are hashed by to find their partition.
+func (cmd *PreSortCommand) partitionKey() string {
+	if cmd.PartitionKey != "" {
+		return cmd.PartitionKey
+	}
+	return cmd.Table
+}
+
 func (cmd *PreSortCommand) ndjsonPartition(rec map[string]interface{}) (int, error) {
 	h := fnv.New64a()
-	_, _ = h.Write([]byte(cmd.Table))
+	_, _ = h.Write([]byte(cmd.partitionKey()))
 
 	for i, name := range cmd.PrimaryKeyFields {
 		val, ok := rec[name]
@@ -280,7 +293,7 @@ func (cmd *PreSortCommand) RunCSV(filelist []string) error {
 
 func (cmd *PreSortCommand) csvPartition(header map[string]int, rec []string) (int, error) {
 	h := fnv.New64a()
-	_, _ = h.Write([]byte(cmd.Table))
+	_, _ = h.Write([]byte(cmd.partitionKey()))
 
 	for i, name := range cmd.PrimaryKeyFields {
 		pos, ok := header[name]
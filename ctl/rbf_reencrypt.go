@@ -0,0 +1,99 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/featurebasedb/featurebase/v3/encryption"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/featurebasedb/featurebase/v3/rbf"
+)
+
+// RBFReencryptCommand represents a command for re-encrypting an RBF database.
+type RBFReencryptCommand struct {
+	// Filepath to the RBF database.
+	Path string
+
+	// Keyfile holds the keys to encrypt with; the database is encrypted with
+	// its current key. It is also used to decrypt unless FromKeyfile is set.
+	Keyfile string
+
+	// FromKeyfile holds the keys the database is currently encrypted with,
+	// if they are not all in Keyfile.
+	FromKeyfile string
+
+	// FromPlaintext and ToPlaintext convert a database from or to
+	// plaintext.
+	FromPlaintext bool
+	ToPlaintext   bool
+
+	// Standard input/output
+	stdout  io.Writer
+	logDest logger.Logger
+}
+
+// NewRBFReencryptCommand returns a new instance of RBFReencryptCommand.
+func NewRBFReencryptCommand(logdest logger.Logger) *RBFReencryptCommand {
+	return &RBFReencryptCommand{
+		stdout:  os.Stdout,
+		logDest: logdest,
+	}
+}
+
+// Run re-encrypts every page of an RBF database.
+func (cmd *RBFReencryptCommand) Run(ctx context.Context) error {
+	if cmd.FromPlaintext && cmd.ToPlaintext {
+		return fmt.Errorf("cannot use both --from-plaintext and --to-plaintext")
+	} else if cmd.FromPlaintext && cmd.FromKeyfile != "" {
+		return fmt.Errorf("cannot use both --from-plaintext and --from-keyfile")
+	}
+
+	to, err := cmd.openKeyfile(cmd.Keyfile)
+	if err != nil {
+		return err
+	}
+	from := to
+	if cmd.FromKeyfile != "" {
+		if from, err = cmd.openKeyfile(cmd.FromKeyfile); err != nil {
+			return err
+		}
+	}
+	if cmd.FromPlaintext {
+		from = nil
+	}
+	if cmd.ToPlaintext {
+		to = nil
+	}
+	if from == nil && to == nil {
+		return fmt.Errorf("keyfile required")
+	}
+
+	if err := rbf.Reencrypt(cmd.Path, from, to); err != nil {
+		return err
+	}
+
+	switch {
+	case to == nil:
+		fmt.Fprintln(cmd.stdout, "decrypted")
+	default:
+		fmt.Fprintf(cmd.stdout, "encrypted with key %d\n", to.CurrentKeyID())
+	}
+	return nil
+}
+
+// openKeyfile returns a cipher for the keys in a keyfile, or nil if path is
+// empty.
+func (cmd *RBFReencryptCommand) openKeyfile(path string) (*encryption.Cipher, error) {
+	if path == "" {
+		return nil, nil
+	}
+	keyring, err := encryption.LoadKeyfile(path)
+	if err != nil {
+		return nil, err
+	}
+	return encryption.NewCipher(keyring)
+}
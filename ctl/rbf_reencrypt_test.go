@@ -0,0 +1,85 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/featurebasedb/featurebase/v3/logger"
+)
+
+func TestRBFReencryptCommand_Run(t *testing.T) {
+	// work on a copy of a known good database.
+	path := t.TempDir()
+	for _, name := range []string{"data", "wal"} {
+		data, err := os.ReadFile(filepath.Join("testdata", "ok", name))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			t.Fatal(err)
+		} else if err := os.WriteFile(filepath.Join(path, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keyfile := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(keyfile, []byte("1 000102030405060708090a0b0c0d0e0f\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(t *testing.T, fn func(cmd *RBFReencryptCommand)) string {
+		t.Helper()
+		cmd := NewRBFReencryptCommand(logger.NewStandardLogger(os.Stderr))
+		buf := &bytes.Buffer{}
+		cmd.stdout = buf
+		cmd.Path = path
+		fn(cmd)
+		if err := cmd.Run(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	check := func(t *testing.T) error {
+		t.Helper()
+		cmd := NewRBFCheckCommand(logger.NewStandardLogger(os.Stderr))
+		cmd.stdout = &bytes.Buffer{}
+		cmd.Path = path
+		return cmd.Run(context.Background())
+	}
+
+	if got := run(t, func(cmd *RBFReencryptCommand) {
+		cmd.Keyfile, cmd.FromPlaintext = keyfile, true
+	}); got != "encrypted with key 1\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+	if err := check(t); err == nil {
+		t.Fatal("expected check of encrypted database to fail without a key")
+	}
+
+	// rotate to a new key.
+	if err := os.WriteFile(keyfile, []byte("1 000102030405060708090a0b0c0d0e0f\n2 101112131415161718191a1b1c1d1e1f\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := run(t, func(cmd *RBFReencryptCommand) { cmd.Keyfile = keyfile }); got != "encrypted with key 2\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+
+	if got := run(t, func(cmd *RBFReencryptCommand) {
+		cmd.Keyfile, cmd.ToPlaintext = keyfile, true
+	}); got != "decrypted\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+	if err := check(t); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewRBFReencryptCommand(logger.NewStandardLogger(os.Stderr))
+	cmd.Path, cmd.FromPlaintext, cmd.ToPlaintext = path, true, true
+	if err := cmd.Run(context.Background()); err == nil {
+		t.Fatal("expected error using both --from-plaintext and --to-plaintext")
+	}
+}
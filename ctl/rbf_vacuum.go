@@ -0,0 +1,69 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/featurebasedb/featurebase/v3/encryption"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/featurebasedb/featurebase/v3/rbf"
+	rbfcfg "github.com/featurebasedb/featurebase/v3/rbf/cfg"
+)
+
+// RBFVacuumCommand represents a command for compacting an RBF database.
+type RBFVacuumCommand struct {
+	// Filepath to the RBF database.
+	Path string
+
+	// Keyfile holds the keys an encrypted database is encrypted with.
+	Keyfile string
+
+	// Standard input/output
+	stdout  io.Writer
+	logDest logger.Logger
+}
+
+// NewRBFVacuumCommand returns a new instance of RBFVacuumCommand.
+func NewRBFVacuumCommand(logdest logger.Logger) *RBFVacuumCommand {
+	return &RBFVacuumCommand{
+		stdout:  os.Stdout,
+		logDest: logdest,
+	}
+}
+
+// Run moves in-use pages to the front of an RBF database and truncates the
+// free pages left at the end of the file.
+func (cmd *RBFVacuumCommand) Run(ctx context.Context) error {
+	cfg := rbfcfg.NewDefaultConfig()
+	c, err := encryption.Open(encryption.Config{Keyfile: cmd.Keyfile})
+	if err != nil {
+		return err
+	}
+	cfg.Cipher = c
+
+	db := rbf.NewDB(cmd.Path, cfg)
+	if err := db.Open(); err != nil {
+		return err
+	}
+	defer db.Close()
+
+	before, err := os.Stat(db.DataPath())
+	if err != nil {
+		return err
+	}
+	moved, err := db.Vacuum()
+	if err != nil {
+		return err
+	}
+	after, err := os.Stat(db.DataPath())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.stdout, "moved %d pages, %d bytes -> %d bytes\n", moved, before.Size(), after.Size())
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/featurebasedb/featurebase/v3/rbf"
+	rbfcfg "github.com/featurebasedb/featurebase/v3/rbf/cfg"
+)
+
+func TestRBFVacuumCommand_Run(t *testing.T) {
+	// create a database with free pages at the front of the file by
+	// deleting the first of two bitmaps.
+	path := t.TempDir()
+	cfg := rbfcfg.NewDefaultConfig()
+	cfg.CompactionFreeRatio = 0
+	db := rbf.NewDB(path, cfg)
+	if err := db.Open(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b"} {
+		tx, err := db.Begin(true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for row := uint64(0); row < 100; row++ {
+			for col := uint64(0); col < 10000; col += 2 {
+				if _, err := tx.Add(name, row<<16|col); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tx, err := db.Begin(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pageN := tx.PageN()
+	if err := tx.DeleteBitmap("a"); err != nil {
+		t.Fatal(err)
+	} else if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	} else if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewRBFVacuumCommand(logger.NewStandardLogger(os.Stderr))
+	buf := &bytes.Buffer{}
+	cmd.stdout = buf
+	cmd.Path = path
+	if err := cmd.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if !strings.HasPrefix(buf.String(), "moved ") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+
+	db = rbf.NewDB(path, nil)
+	if err := db.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if tx, err := db.Begin(false); err != nil {
+		t.Fatal(err)
+	} else {
+		defer tx.Rollback()
+		if n, err := tx.Count("b"); err != nil {
+			t.Fatal(err)
+		} else if n != 100*5000 {
+			t.Fatalf("Count(b)=%d, want %d", n, 100*5000)
+		} else if n := tx.PageN(); n > pageN*2/3 {
+			t.Fatalf("expected fewer than %d pages, got %d", pageN*2/3, n)
+		}
+	}
+}
@@ -151,8 +151,17 @@ func serverFlagSet(srv *server.Config, prefix string) *pflag.FlagSet {
 	flags.BoolVar(&srv.DataDog.BlockProfile, pre("datadog.block-profile"), false, "golang pprof goroutine ")
 
 	flags.BoolVar(&srv.Dataframe.Enable, pre("dataframe.enable"), false, "EXPERIMENTAL enable support for Apply and Arrow")
+	flags.StringVar(&srv.Export.Directory, pre("export.directory"), srv.Export.Directory, "Directory sql FILE exports are written under. FILE exports are refused if empty.")
+	flags.StringSliceVar(&srv.Export.AllowedURLs, pre("export.allowed-urls"), srv.Export.AllowedURLs, "URLs sql URL exports can be posted to, or under. URL exports are refused if empty.")
 	flags.BoolVar(&srv.Dataframe.UseParquet, pre("dataframe.use-parquet"), false, "EXPERIMENTAL use parquet for file format")
 
+	flags.StringVar(&srv.Encryption.Provider, pre("encryption.provider"), srv.Encryption.Provider, "Keyring provider for encryption at rest (default \"keyfile\" when a keyfile is set)")
+	flags.StringVar(&srv.Encryption.Keyfile, pre("encryption.keyfile"), srv.Encryption.Keyfile, "Keyfile of \"<id> <hex key>\" lines for encryption at rest; the highest ID is the current key")
+
+	flags.BoolVar(&srv.ChangeCapture.Enabled, pre("change-capture.enabled"), srv.ChangeCapture.Enabled, "Record a change log of the changes to each table's records")
+	flags.Int64Var(&srv.ChangeCapture.SegmentSize, pre("change-capture.segment-size"), srv.ChangeCapture.SegmentSize, "Size in bytes at which a table's change log starts a new segment")
+	flags.IntVar(&srv.ChangeCapture.Segments, pre("change-capture.segments"), srv.ChangeCapture.Segments, "Number of change log segments kept per table")
+
 	return flags
 }
 
@@ -464,6 +464,65 @@ func (c *Client) DropField(ctx context.Context, qtid dax.QualifiedTableID, fldNa
 	return nil
 }
 
+func (c *Client) RenameTable(ctx context.Context, qtid dax.QualifiedTableID, newName dax.TableName) error {
+	url := fmt.Sprintf("%s/rename-table", c.address.WithScheme(defaultScheme))
+
+	// Encode the request.
+	req := controllerhttp.RenameTableRequest{
+		Table:   qtid,
+		NewName: newName,
+	}
+
+	postBody, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "marshalling post request")
+	}
+	responseBody := bytes.NewBuffer(postBody)
+
+	// Post the request.
+	resp, err := c.httpClient.Post(url, "application/json", responseBody)
+	if err != nil {
+		return errors.Wrap(err, "posting rename table request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrapf(errors.UnmarshalJSON(resp.Body), "status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (c *Client) RenameField(ctx context.Context, qtid dax.QualifiedTableID, fldName dax.FieldName, newName dax.FieldName) error {
+	url := fmt.Sprintf("%s/rename-field", c.address.WithScheme(defaultScheme))
+
+	// Encode the request.
+	req := controllerhttp.RenameFieldRequest{
+		Table:   qtid,
+		Field:   fldName,
+		NewName: newName,
+	}
+
+	postBody, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "marshalling post request")
+	}
+	responseBody := bytes.NewBuffer(postBody)
+
+	// Post the request.
+	resp, err := c.httpClient.Post(url, "application/json", responseBody)
+	if err != nil {
+		return errors.Wrap(err, "posting rename field request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrapf(errors.UnmarshalJSON(resp.Body), "status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
 func (c *Client) IngestShard(ctx context.Context, qtid dax.QualifiedTableID, shard dax.ShardNum) (dax.Address, error) {
 	url := fmt.Sprintf("%s/ingest-shard", c.address.WithScheme(defaultScheme))
 
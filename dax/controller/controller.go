@@ -2073,38 +2073,89 @@ func (c *Controller) DropField(ctx context.Context, qtid dax.QualifiedTableID, f
 			return errors.Wrapf(err, "dropping field: %s, %s", qtid, fldName)
 		}
 
-		// workerSet maintains the set of workers which have a job assignment change
-		// and therefore need to be sent an updated Directive.
-		workerSet := NewAddressSet()
+		workerSet, err := c.tableWorkers(tx, qtid)
+		if err != nil {
+			return errors.Wrapf(err, "getting workers for table: %s", qtid)
+		}
 
-		qdbid := qtid.QualifiedDatabaseID
+		// Convert the slice of addresses into a slice of addressMethod containing
+		// the appropriate method.
+		addrMethods := applyAddressMethod(workerSet.SortedSlice(), dax.DirectiveMethodFull)
 
-		// Get the worker(s) responsible for partition 0.
-		job := partition(qtid.Key(), 0).Job()
-		workers, err := c.Balancer.WorkersForJobs(tx, dax.RoleTypeTranslate, qdbid, job)
+		directives, err = c.buildDirectives(ctx, tx, addrMethods)
 		if err != nil {
-			return errors.Wrapf(err, "getting workers for job: %s", job)
+			return errors.Wrap(err, "building directives")
 		}
 
-		for _, w := range workers {
-			workerSet.Add(dax.Address(w.Address))
-		}
+		return nil
+	}
 
-		// Get the list of workers responsible for shard data for this table.
-		if state, err := c.Balancer.CurrentState(tx, dax.RoleTypeCompute, qdbid); err != nil {
-			return errors.Wrap(err, "getting current compute state")
-		} else {
-			for _, worker := range state {
-				for _, job := range worker.Jobs {
-					if shard, err := decodeShard(job); err != nil {
-						return errors.Wrapf(err, "decoding shard: %s", job)
-					} else if shard.table() == qtid.Key() {
-						workerSet.Add(dax.Address(worker.Address))
-						break
-					}
+	if err := dax.RetryWithTx(ctx, c.Transactor, fn, true, txRetry); err != nil {
+		return errors.Wrap(err, "retry with tx: write")
+	}
+
+	if err := c.sendDirectives(ctx, directives); err != nil {
+		return NewErrDirectiveSendFailure(err.Error())
+	}
+	return nil
+}
+
+// tableWorkers returns the set of workers which hold either translate
+// partition 0 or shard data for the table.
+func (c *Controller) tableWorkers(tx dax.Transaction, qtid dax.QualifiedTableID) (AddressSet, error) {
+	workerSet := NewAddressSet()
+
+	qdbid := qtid.QualifiedDatabaseID
+
+	// Get the worker(s) responsible for partition 0.
+	job := partition(qtid.Key(), 0).Job()
+	workers, err := c.Balancer.WorkersForJobs(tx, dax.RoleTypeTranslate, qdbid, job)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting workers for job: %s", job)
+	}
+
+	for _, w := range workers {
+		workerSet.Add(dax.Address(w.Address))
+	}
+
+	// Get the list of workers responsible for shard data for this table.
+	if state, err := c.Balancer.CurrentState(tx, dax.RoleTypeCompute, qdbid); err != nil {
+		return nil, errors.Wrap(err, "getting current compute state")
+	} else {
+		for _, worker := range state {
+			for _, job := range worker.Jobs {
+				if shard, err := decodeShard(job); err != nil {
+					return nil, errors.Wrapf(err, "decoding shard: %s", job)
+				} else if shard.table() == qtid.Key() {
+					workerSet.Add(dax.Address(worker.Address))
+					break
 				}
 			}
 		}
+	}
+
+	return workerSet, nil
+}
+
+// RenameTable renames a table. Compute nodes refer to tables by key, so
+// this is a metadata change which is sent to the table's workers.
+func (c *Controller) RenameTable(ctx context.Context, qtid dax.QualifiedTableID, newName dax.TableName) error {
+	var directives []*dax.Directive
+
+	fn := func(tx dax.Transaction, writable bool) error {
+		if err := c.sanitizeQTID(tx, &qtid); err != nil {
+			return errors.Wrap(err, "sanitizing")
+		}
+
+		// Rename the table in schemar.
+		if err := c.Schemar.RenameTable(tx, qtid, newName); err != nil {
+			return errors.Wrapf(err, "renaming table: %s", qtid)
+		}
+
+		workerSet, err := c.tableWorkers(tx, qtid)
+		if err != nil {
+			return errors.Wrapf(err, "getting workers for table: %s", qtid)
+		}
 
 		// Convert the slice of addresses into a slice of addressMethod containing
 		// the appropriate method.
@@ -2128,6 +2179,14 @@ func (c *Controller) DropField(ctx context.Context, qtid dax.QualifiedTableID, f
 	return nil
 }
 
+// RenameField is not supported in serverless. Field data in the writelogger,
+// snapshotter, and translate stores is stored by field name, and the
+// directives sent on a schema change would cause compute nodes to drop the
+// field under its old name and create an empty one under the new name.
+func (c *Controller) RenameField(ctx context.Context, qtid dax.QualifiedTableID, fldName dax.FieldName, newName dax.FieldName) error {
+	return errors.New(dax.ErrUnimplemented, "renaming a field is not supported in serverless")
+}
+
 //////////////////////////////////
 
 func (c *Controller) AddAddresses(ctx context.Context, addrs ...dax.Address) error {
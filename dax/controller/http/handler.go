@@ -28,8 +28,10 @@ func Handler(c *controller.Controller) http.Handler {
 
 	router.HandleFunc("/create-table", server.postCreateTable).Methods("POST").Name("PostCreateTable")
 	router.HandleFunc("/drop-table", server.postDropTable).Methods("POST").Name("PostDropTable")
+	router.HandleFunc("/rename-table", server.postRenameTable).Methods("POST").Name("PostRenameTable")
 	router.HandleFunc("/create-field", server.postCreateField).Methods("POST").Name("PostCreateField")
 	router.HandleFunc("/drop-field", server.postDropField).Methods("POST").Name("PostDropField")
+	router.HandleFunc("/rename-field", server.postRenameField).Methods("POST").Name("PostRenameField")
 	router.HandleFunc("/table", server.postTable).Methods("POST").Name("PostTable")
 	router.HandleFunc("/table-id", server.postTableID).Methods("POST").Name("PostTable")
 	router.HandleFunc("/tables", server.postTables).Methods("POST").Name("PostTables")
@@ -372,6 +374,57 @@ type DropFieldRequest struct {
 	Field dax.FieldName        `json:"fields"`
 }
 
+// POST /rename-table
+func (s *server) postRenameTable(w http.ResponseWriter, r *http.Request) {
+	body := r.Body
+	defer body.Close()
+
+	ctx := r.Context()
+
+	req := RenameTableRequest{}
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := s.controller.RenameTable(ctx, req.Table, req.NewName)
+	if err != nil {
+		http.Error(w, errors.MarshalJSON(err), http.StatusBadRequest)
+		return
+	}
+}
+
+type RenameTableRequest struct {
+	Table   dax.QualifiedTableID `json:"table"`
+	NewName dax.TableName        `json:"new-name"`
+}
+
+// POST /rename-field
+func (s *server) postRenameField(w http.ResponseWriter, r *http.Request) {
+	body := r.Body
+	defer body.Close()
+
+	ctx := r.Context()
+
+	req := RenameFieldRequest{}
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := s.controller.RenameField(ctx, req.Table, req.Field, req.NewName)
+	if err != nil {
+		http.Error(w, errors.MarshalJSON(err), http.StatusBadRequest)
+		return
+	}
+}
+
+type RenameFieldRequest struct {
+	Table   dax.QualifiedTableID `json:"table"`
+	Field   dax.FieldName        `json:"field"`
+	NewName dax.FieldName        `json:"new-name"`
+}
+
 // POST /tables
 func (s *server) postTables(w http.ResponseWriter, r *http.Request) {
 	body := r.Body
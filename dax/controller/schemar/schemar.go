@@ -23,8 +23,10 @@ type Schemar interface {
 
 	CreateTable(dax.Transaction, *dax.QualifiedTable) error
 	DropTable(dax.Transaction, dax.QualifiedTableID) error
+	RenameTable(dax.Transaction, dax.QualifiedTableID, dax.TableName) error
 	CreateField(dax.Transaction, dax.QualifiedTableID, *dax.Field) error
 	DropField(dax.Transaction, dax.QualifiedTableID, dax.FieldName) error
+	RenameField(dax.Transaction, dax.QualifiedTableID, dax.FieldName, dax.FieldName) error
 	Table(dax.Transaction, dax.QualifiedTableID) (*dax.QualifiedTable, error)
 
 	// Tables returns a list of tables. If the qualifiers DatabaseID is empty,
@@ -83,6 +85,10 @@ func (s *NopSchemar) DropTable(tx dax.Transaction, qtid dax.QualifiedTableID) er
 	return nil
 }
 
+func (s *NopSchemar) RenameTable(tx dax.Transaction, qtid dax.QualifiedTableID, newName dax.TableName) error {
+	return nil
+}
+
 func (s *NopSchemar) CreateField(tx dax.Transaction, qtid dax.QualifiedTableID, fld *dax.Field) error {
 	return nil
 }
@@ -91,6 +97,10 @@ func (s *NopSchemar) DropField(tx dax.Transaction, qtid dax.QualifiedTableID, fl
 	return nil
 }
 
+func (s *NopSchemar) RenameField(tx dax.Transaction, qtid dax.QualifiedTableID, fld dax.FieldName, newName dax.FieldName) error {
+	return nil
+}
+
 func (s *NopSchemar) Table(tx dax.Transaction, qtid dax.QualifiedTableID) (*dax.QualifiedTable, error) {
 	return nil, nil
 }
@@ -347,6 +347,30 @@ func (s *Schemar) DropTable(tx dax.Transaction, qtid dax.QualifiedTableID) error
 	return errors.Wrap(err, "destroying table")
 }
 
+func (s *Schemar) RenameTable(tx dax.Transaction, qtid dax.QualifiedTableID, newName dax.TableName) error {
+	dt, ok := tx.(*DaxTransaction)
+	if !ok {
+		return dax.NewErrInvalidTransaction("*sqldb.DaxTransaction")
+	}
+	if newName == "" {
+		return schemar.NewErrTableNameInvalid(newName)
+	}
+
+	// Check to see if the new table name exists for the database ID.
+	if exists, err := dt.C.Where("name = ? AND database_id = ?", newName, qtid.DatabaseID).Exists(&models.Table{}); err != nil {
+		return errors.Wrap(err, "checking if table name exists")
+	} else if exists {
+		return dax.NewErrTableNameExists(newName)
+	}
+
+	tbl := &models.Table{}
+	err := dt.C.RawQuery("UPDATE tables SET name = ? WHERE id = ? RETURNING id", newName, qtid.Key()).First(tbl)
+	if isNoRowsError(err) {
+		return dax.NewErrTableIDDoesNotExist(qtid)
+	}
+	return errors.Wrap(err, "renaming table")
+}
+
 func (s *Schemar) CreateField(tx dax.Transaction, qtid dax.QualifiedTableID, field *dax.Field) error {
 	dt, ok := tx.(*DaxTransaction)
 	if !ok {
@@ -395,6 +419,29 @@ func (s *Schemar) DropField(tx dax.Transaction, qtid dax.QualifiedTableID, field
 	return errors.Wrap(err, "destroying col")
 }
 
+func (s *Schemar) RenameField(tx dax.Transaction, qtid dax.QualifiedTableID, fieldName dax.FieldName, newName dax.FieldName) error {
+	dt, ok := tx.(*DaxTransaction)
+	if !ok {
+		return dax.NewErrInvalidTransaction("*sqldb.DaxTransaction")
+	}
+	if newName == "" {
+		return schemar.NewErrFieldNameInvalid(newName)
+	}
+
+	if exists, err := dt.C.Where("table_id = ? and name = ?", qtid.Key(), newName).Exists(&models.Column{}); err != nil {
+		return errors.Wrap(err, "checking if field name exists")
+	} else if exists {
+		return dax.NewErrFieldExists(newName)
+	}
+
+	col := &models.Column{}
+	err := dt.C.RawQuery("UPDATE columns SET name = ? WHERE table_id = ? and name = ? RETURNING id", newName, qtid.Key(), fieldName).First(col)
+	if isNoRowsError(err) {
+		return dax.NewErrFieldDoesNotExist(fieldName)
+	}
+	return errors.Wrap(err, "renaming col")
+}
+
 func (s *Schemar) Table(tx dax.Transaction, qtid dax.QualifiedTableID) (*dax.QualifiedTable, error) {
 	dt, ok := tx.(*DaxTransaction)
 	if !ok {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
 	"sort"
 	"strings"
 	"time"
@@ -12,6 +13,7 @@ import (
 	featurebase "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/dax"
 	"github.com/featurebasedb/featurebase/v3/errors"
+	"github.com/featurebasedb/featurebase/v3/hll"
 	"github.com/featurebasedb/featurebase/v3/logger"
 	"github.com/featurebasedb/featurebase/v3/pql"
 	"github.com/featurebasedb/featurebase/v3/tracing"
@@ -77,6 +79,9 @@ type Translator interface {
 	TranslateIndexIDSet(ctx context.Context, index string, ids map[uint64]struct{}) (map[uint64]string, error)
 	TranslateFieldIDs(ctx context.Context, tableKeyer dax.TableKeyer, field string, ids map[uint64]struct{}) (map[uint64]string, error)
 	TranslateFieldListIDs(ctx context.Context, index, field string, ids []uint64) ([]string, error)
+	// MatchFieldKeys returns the IDs of the keys of a field which match a
+	// pattern. The syntax of the pattern is one of "like", "regexp" or "glob".
+	MatchFieldKeys(ctx context.Context, index, field string, syntax, pattern string) ([]uint64, error)
 }
 
 // executor recursively executes calls in a PQL query across all shards.
@@ -153,6 +158,28 @@ func (o *orchestrator) Execute(ctx context.Context, tableKeyer dax.TableKeyer, q
 	return resp, nil
 }
 
+// Shards returns the shards holding data for the table, in ascending order.
+func (o *orchestrator) Shards(ctx context.Context, tableKeyer dax.TableKeyer) ([]uint64, error) {
+	index := string(tableKeyer.Key())
+	nodes, err := o.topology.ComputeNodes(ctx, index, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting nodes/shards for index '%q'", index)
+	}
+	seen := make(map[uint64]struct{})
+	var shards []uint64
+	for _, node := range nodes {
+		for _, shard := range node.Shards {
+			if _, ok := seen[uint64(shard)]; ok {
+				continue
+			}
+			seen[uint64(shard)] = struct{}{}
+			shards = append(shards, uint64(shard))
+		}
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i] < shards[j] })
+	return shards, nil
+}
+
 func (o *orchestrator) execute(ctx context.Context, tableKeyer dax.TableKeyer, q *pql.Query, shards []uint64, opt *featurebase.ExecOptions) ([]interface{}, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.execute")
 	defer span.Finish()
@@ -480,6 +507,14 @@ func (o *orchestrator) executeCall(ctx context.Context, tableKeyer dax.TableKeye
 		statFn(featurebase.CounterQueryDistinctTotal)
 		res, err := o.executeDistinct(ctx, tableKeyer, c, shards, opt)
 		return res, errors.Wrap(err, "executeDistinct")
+	case "ApproxCountDistinct":
+		statFn(featurebase.CounterQueryApproxCountDistinctTotal)
+		res, err := o.executeApproxCountDistinct(ctx, tableKeyer, c, shards, opt)
+		return res, errors.Wrap(err, "executeApproxCountDistinct")
+	case "Funnel":
+		statFn(featurebase.CounterQueryFunnelTotal)
+		res, err := o.executeFunnel(ctx, tableKeyer, c, shards, opt)
+		return res, errors.Wrap(err, "executeFunnel")
 	// case "Store":
 	// 	statFn(featurebase.CounterQueryStoreTotal)
 	// 	res, err := o.executeSetRow(ctx, index, c, shards, opt)
@@ -496,6 +531,10 @@ func (o *orchestrator) executeCall(ctx context.Context, tableKeyer dax.TableKeye
 		statFn(featurebase.CounterQueryTopKTotal)
 		res, err := o.executeTopK(ctx, tableKeyer, c, shards, opt)
 		return res, errors.Wrap(err, "executeTopK")
+	case "Similar":
+		statFn(featurebase.CounterQuerySimilarTotal)
+		res, err := o.executeSimilar(ctx, tableKeyer, c, shards, opt)
+		return res, errors.Wrap(err, "executeSimilar")
 	case "TopN":
 		statFn(featurebase.CounterQueryTopNTotal)
 		res, err := o.executeTopN(ctx, tableKeyer, c, shards, opt)
@@ -536,6 +575,10 @@ func (o *orchestrator) executeCall(ctx context.Context, tableKeyer dax.TableKeye
 		statFn(featurebase.CounterQueryConstRowTotal)
 		res, err := o.executeConstRow(ctx, tableKeyer, c)
 		return res, errors.Wrap(err, "executeConstRow")
+	case "Sample":
+		statFn(featurebase.CounterQuerySampleTotal)
+		res, err := o.executeSample(ctx, tableKeyer, c, shards, opt)
+		return res, errors.Wrap(err, "executeSample")
 	case "Limit":
 		statFn(featurebase.CounterQueryLimitTotal)
 		res, err := o.executeLimitCall(ctx, tableKeyer, c, shards, opt)
@@ -736,6 +779,54 @@ func (o *orchestrator) executeLimitCall(ctx context.Context, tableKeyer dax.Tabl
 	return result, nil
 }
 
+// executeSample executes a Sample() call.
+func (o *orchestrator) executeSample(ctx context.Context, tableKeyer dax.TableKeyer, c *pql.Call, shards []uint64, opt *featurebase.ExecOptions) (*featurebase.Row, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSample")
+	defer span.Finish()
+
+	if len(c.Children) != 1 {
+		return nil, errors.New(errors.ErrUncoded, "Sample() requires a single bitmap argument")
+	}
+	// Pick the seed here so every computer samples with the same one.
+	if _, ok := c.Args["seed"]; !ok {
+		if c.Args == nil {
+			c.Args = make(map[string]interface{})
+		}
+		c.Args["seed"] = rand.Int63()
+	}
+	sample, err := featurebase.ParseSampleOptions(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge returned results at coordinating node. A sample of n columns
+	// has to be cut back down to the n lowest scores of the union.
+	reduceFn := func(ctx context.Context, prev, v interface{}) interface{} {
+		other, _ := prev.(*featurebase.Row)
+		if other == nil {
+			other = featurebase.NewRow()
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		other.Merge(v.(*featurebase.Row))
+		if sample.N > 0 {
+			return sample.Apply(other)
+		}
+		return other
+	}
+
+	result, err := o.mapReduce(ctx, tableKeyer, shards, c, opt, reduceFn)
+	if err != nil {
+		return nil, errors.Wrap(err, "mapReduce")
+	}
+	row, _ := result.(*featurebase.Row)
+	if row == nil {
+		row = featurebase.NewRow()
+	}
+	return row, nil
+}
+
 // executeSum executes a Sum() call.
 func (o *orchestrator) executeSum(ctx context.Context, tableKeyer dax.TableKeyer, c *pql.Call, shards []uint64, opt *featurebase.ExecOptions) (_ featurebase.ValCount, err error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSum")
@@ -832,6 +923,96 @@ func (o *orchestrator) executeDistinct(ctx context.Context, tableKeyer dax.Table
 	return result, nil
 }
 
+// executeApproxCountDistinct executes an ApproxCountDistinct() call by
+// merging the sketches built by each worker.
+func (o *orchestrator) executeApproxCountDistinct(ctx context.Context, tableKeyer dax.TableKeyer, c *pql.Call, shards []uint64, opt *featurebase.ExecOptions) (interface{}, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeApproxCountDistinct")
+	defer span.Finish()
+
+	if _, err := c.FirstStringArg("field", "_field"); err != nil {
+		return nil, errors.Wrap(err, "ApproxCountDistinct(): field required")
+	}
+
+	// Merge returned results at coordinating node.
+	reduceFn := func(ctx context.Context, prev, v interface{}) interface{} {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		switch other := v.(type) {
+		case *hll.Sketch:
+			sketch, _ := prev.(*hll.Sketch)
+			if sketch == nil {
+				return other
+			}
+			if err := sketch.Merge(other); err != nil {
+				return err
+			}
+			return sketch
+		case error:
+			return other
+		case nil:
+			return prev
+		default:
+			return errors.Errorf("unexpected return type from executeApproxCountDistinctShard: %T", v)
+		}
+	}
+
+	result, err := o.mapReduce(ctx, tableKeyer, shards, c, opt, reduceFn)
+	if err != nil {
+		return nil, errors.Wrap(err, "mapReduce")
+	}
+	sketch, _ := result.(*hll.Sketch)
+	if sketch == nil {
+		return uint64(0), nil
+	}
+	return sketch.Count(), nil
+}
+
+// executeFunnel executes a Funnel() call by merging the step counts and
+// columns from each worker.
+func (o *orchestrator) executeFunnel(ctx context.Context, tableKeyer dax.TableKeyer, c *pql.Call, shards []uint64, opt *featurebase.ExecOptions) (*featurebase.FunnelResult, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeFunnel")
+	defer span.Finish()
+
+	if len(c.Children) == 0 {
+		return nil, errors.New(errors.ErrUncoded, "Funnel() requires at least one step")
+	}
+
+	// Merge returned results at coordinating node.
+	reduceFn := func(ctx context.Context, prev, v interface{}) interface{} {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		switch other := v.(type) {
+		case *featurebase.FunnelResult:
+			funnel, _ := prev.(*featurebase.FunnelResult)
+			if funnel == nil {
+				return other
+			}
+			if err := funnel.Merge(other); err != nil {
+				return err
+			}
+			return funnel
+		case error:
+			return other
+		case nil:
+			return prev
+		default:
+			return errors.Errorf("unexpected return type from executeFunnelShard: %T", v)
+		}
+	}
+
+	result, err := o.mapReduce(ctx, tableKeyer, shards, c, opt, reduceFn)
+	if err != nil {
+		return nil, errors.Wrap(err, "mapReduce")
+	}
+	funnel, _ := result.(*featurebase.FunnelResult)
+	if funnel == nil {
+		funnel = &featurebase.FunnelResult{Counts: make([]uint64, len(c.Children)), Row: featurebase.NewRow()}
+	}
+	return funnel, nil
+}
+
 // executeMin executes a Min() call.
 func (o *orchestrator) executeMin(ctx context.Context, tableKeyer dax.TableKeyer, c *pql.Call, shards []uint64, opt *featurebase.ExecOptions) (_ featurebase.ValCount, err error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeMin")
@@ -1457,6 +1638,80 @@ func (o *orchestrator) executeTopN(ctx context.Context, tableKeyer dax.TableKeye
 	}, nil
 }
 
+// executeSimilar executes a Similar() call.
+func (o *orchestrator) executeSimilar(ctx context.Context, tableKeyer dax.TableKeyer, c *pql.Call, shards []uint64, opt *featurebase.ExecOptions) (*featurebase.SimilarResult, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeSimilar")
+	defer span.Finish()
+
+	fieldName, err := c.FirstStringArg("_field", "field")
+	if err != nil || fieldName == "" {
+		return nil, errors.New(errors.ErrUncoded, "Similar(): field required")
+	}
+	sim, err := featurebase.ParseSimilarOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	_, hasIDs, err := c.UintSliceArg("ids")
+	if err != nil {
+		return nil, errors.Wrap(err, "Similar(): reading ids")
+	}
+
+	result, err := o.executeSimilarShards(ctx, tableKeyer, c, shards, opt)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding similar rows")
+	}
+
+	// If this call is against specific ids, or we are part of a larger
+	// distributed query, return the counts for the coordinator to score.
+	if opt.Remote || hasIDs {
+		return result, nil
+	}
+
+	if len(result.Rows) > 0 {
+		// Only the original caller should refetch the full counts.
+		other := c.Clone()
+		ids := make([]uint64, len(result.Rows))
+		for i, row := range result.Rows {
+			ids[i] = row.ID
+		}
+		other.Args["ids"] = ids
+
+		if result, err = o.executeSimilarShards(ctx, tableKeyer, other, shards, opt); err != nil {
+			return nil, errors.Wrap(err, "retrieving full counts")
+		}
+	}
+	sim.Top(result)
+	result.Field = fieldName
+	return result, nil
+}
+
+func (o *orchestrator) executeSimilarShards(ctx context.Context, tableKeyer dax.TableKeyer, c *pql.Call, shards []uint64, opt *featurebase.ExecOptions) (*featurebase.SimilarResult, error) {
+	// Merge returned results at coordinating node.
+	reduceFn := func(ctx context.Context, prev, v interface{}) interface{} {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		other, _ := prev.(*featurebase.SimilarResult)
+		if other == nil {
+			other = &featurebase.SimilarResult{}
+		}
+		if v, ok := v.(*featurebase.SimilarResult); ok {
+			other.Merge(v)
+		}
+		return other
+	}
+
+	result, err := o.mapReduce(ctx, tableKeyer, shards, c, opt, reduceFn)
+	if err != nil {
+		return nil, err
+	}
+	sim, _ := result.(*featurebase.SimilarResult)
+	if sim == nil {
+		sim = &featurebase.SimilarResult{}
+	}
+	return sim, nil
+}
+
 func (o *orchestrator) executeTopNShards(ctx context.Context, tableKeyer dax.TableKeyer, c *pql.Call, shards []uint64, opt *featurebase.ExecOptions) (*featurebase.PairsField, error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "Executor.executeTopNShards")
 	defer span.Finish()
@@ -1649,14 +1904,22 @@ func (o *orchestrator) executeGroupBy(ctx context.Context, tableKeyer dax.TableK
 		if err != nil {
 			return nil, errors.Wrap(err, "getting column")
 		}
-		_, hasLike, err := child.StringArg("like")
+		_, _, hasLike, err := featurebase.RowsKeyPattern(child)
 		if err != nil {
-			return nil, errors.Wrap(err, "getting like")
+			return nil, err
 		}
 		_, hasIn, err := child.UintSliceArg("in")
 		if err != nil {
 			return nil, errors.Wrap(err, "getting 'in'")
 		}
+		if _, ok := child.Args["bucket"]; ok {
+			if hasLimit || hasCol || hasLike || hasIn {
+				return nil, errors.New(errors.ErrUncoded, "Rows() bucket cannot be combined with limit, column, like or in")
+			}
+			if agg, ok := c.Args["aggregate"].(*pql.Call); ok && agg.Name == "Count" {
+				return nil, errors.New(errors.ErrUncoded, "Rows() bucket is not supported with a Count(Distinct) aggregate")
+			}
+		}
 
 		if hasLimit || hasCol || hasLike || hasIn { // we need to perform this query cluster-wide ahead of executeGroupByShard
 			if idx, ok := child.Args["valueidx"].(int64); ok {
@@ -1885,11 +2148,9 @@ func (o *orchestrator) executeRows(ctx context.Context, tableKeyer dax.TableKeye
 	if fieldName, ok = c.Args["_field"].(string); !ok {
 		return nil, errors.New(errors.ErrUncoded, "Rows() field required")
 	}
-
-	// TODO(tlt): this is here to prevent the linter from complaining.
-	// Presumably this fieldName is/was used in code which is no longer here or
-	// is currently commented out.
-	_ = fieldName
+	if _, ok := c.Args["bucket"]; ok {
+		return nil, errors.New(errors.ErrUncoded, "Rows() bucket is only supported within GroupBy")
+	}
 
 	if columnID, ok, err := c.UintArg("column"); err != nil {
 		return nil, errors.Wrap(err, "getting column")
@@ -1933,34 +2194,31 @@ func (o *orchestrator) executeRows(ctx context.Context, tableKeyer dax.TableKeye
 	}
 	results, _ := other.(featurebase.RowIDs)
 
-	// TODO(jaffee) enable "like" support
-	// if !opt.Remote {
-	// 	if like, hasLike, err := c.StringArg("like"); err != nil {
-	// 		return nil, errors.Wrap(err, "getting like pattern")
-	// 	} else if hasLike {
-	// 		matches, err := e.Cluster.matchField(ctx, e.Holder.Field(index, fieldName), like)
-	// 		if err != nil {
-	// 			return nil, errors.Wrap(err, "matching like pattern")
-	// 		}
-
-	// 		i, j, k := 0, 0, 0
-	// 		for i < len(results) && j < len(matches) {
-	// 			x, y := results[i], matches[j]
-	// 			switch {
-	// 			case x < y:
-	// 				i++
-	// 			case y < x:
-	// 				j++
-	// 			default:
-	// 				results[k] = x
-	// 				i++
-	// 				j++
-	// 				k++
-	// 			}
-	// 		}
-	// 		results = results[:k]
-	// 	}
-	// }
+	if syntax, pattern, hasPattern, err := featurebase.RowsKeyPattern(c); err != nil {
+		return nil, err
+	} else if hasPattern {
+		matches, err := o.trans.MatchFieldKeys(ctx, string(tableKeyer.Key()), fieldName, syntax, pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "matching %s pattern", syntax)
+		}
+
+		i, j, k := 0, 0, 0
+		for i < len(results) && j < len(matches) {
+			x, y := results[i], matches[j]
+			switch {
+			case x < y:
+				i++
+			case y < x:
+				j++
+			default:
+				results[k] = x
+				i++
+				j++
+				k++
+			}
+		}
+		results = results[:k]
+	}
 
 	return results, nil
 }
@@ -2523,6 +2781,15 @@ func (o *orchestrator) collectCallKeys(dst *keyCollector, c *pql.Call, index str
 			dst.FindColumns(index, keys...)
 		}
 
+	case "Similar":
+		field, err := c.FirstStringArg("_field", "field")
+		if err != nil {
+			return errors.Wrap(err, "finding field for Similar row translation")
+		}
+		if row, ok := c.Args["row"].(string); ok {
+			dst.FindRows(index, field, row)
+		}
+
 	case "Rows":
 		// Find the field.
 		var field string
@@ -2910,6 +3177,29 @@ func (o *orchestrator) translateCall(ctx context.Context, c *pql.Call, tableKeye
 			c.Args["columns"] = out
 		}
 
+	case "Similar":
+		field, err := c.FirstStringArg("_field", "field")
+		if err != nil {
+			return nil, errors.Wrap(err, "finding field for Similar row translation")
+		}
+		// Translate the target row key.
+		if row, ok := c.Args["row"]; ok {
+			f, err := o.schemaFieldInfo(ctx, tableKeyer, field)
+			if err != nil {
+				return nil, errors.Wrapf(err, "validating value for field %q", field)
+			}
+			if err := fieldValidateValue(f, row); err != nil {
+				return nil, errors.Wrap(err, "validating row value")
+			}
+			if row, ok := row.(string); ok {
+				if translation, ok := indexRows[field][row]; ok {
+					c.Args["row"] = translation
+				} else {
+					return nil, errors.Wrapf(featurebase.ErrTranslatingKeyNotFound, "translating row key %q from field %q in index %q in Similar call", row, field, index)
+				}
+			}
+		}
+
 	case "Rows":
 		// Find the field.
 		var field string
@@ -2952,8 +3242,8 @@ func (o *orchestrator) translateCall(ctx context.Context, c *pql.Call, tableKeye
 			}
 		}
 
-		// Check if "like" argument is applied to keyed fields.
-		if _, found := c.Args["like"].(string); found {
+		// Check if a key pattern argument is applied to keyed fields.
+		if _, _, found, _ := featurebase.RowsKeyPattern(c); found {
 			fieldName, err := c.FirstStringArg("_field", "field")
 			if err != nil || fieldName == "" {
 				return nil, fmt.Errorf("cannot read field name for Rows call")
@@ -3158,6 +3448,8 @@ func (o *orchestrator) collectResultIDs(ctx context.Context, idx *featurebase.In
 		for _, col := range result.Columns {
 			idSet[col.ColumnID] = struct{}{}
 		}
+	case *featurebase.FunnelResult:
+		return o.collectResultIDs(ctx, idx, call, result.Row, idSet)
 	}
 
 	return nil
@@ -3179,6 +3471,12 @@ func (o *orchestrator) translateResult(ctx context.Context, qtbl *dax.QualifiedT
 	idx := featurebase.TableToIndexInfo(&qtbl.Table)
 
 	switch result := result.(type) {
+	case *featurebase.FunnelResult:
+		row, err := o.translateResult(ctx, qtbl, call, result.Row, idSet)
+		if err != nil {
+			return nil, errors.Wrap(err, "translating funnel columns")
+		}
+		return &featurebase.FunnelResult{Counts: result.Counts, Row: row.(*featurebase.Row)}, nil
 	case *featurebase.Row:
 		rowIdx, rowField, strategy, err := o.howToTranslate(ctx, idx, result)
 		if err != nil {
@@ -3312,6 +3610,27 @@ func (o *orchestrator) translateResult(ctx context.Context, qtbl *dax.QualifiedT
 			}
 		}
 
+	case *featurebase.SimilarResult:
+		field, err := o.schemaFieldInfo(ctx, qtbl, result.Field)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field '%q'", result.Field)
+		}
+		if field.Options.Keys {
+			ids := make([]uint64, len(result.Rows))
+			for i := range result.Rows {
+				ids[i] = result.Rows[i].ID
+			}
+			keys, err := o.trans.TranslateFieldListIDs(ctx, idx.Name, result.Field, ids)
+			if err != nil {
+				return nil, err
+			}
+			other := make([]featurebase.SimilarRow, len(result.Rows))
+			for i, row := range result.Rows {
+				other[i] = featurebase.SimilarRow{Key: keys[i], Score: row.Score, Intersection: row.Intersection, Count: row.Count}
+			}
+			return &featurebase.SimilarResult{Field: result.Field, Count: result.Count, Rows: other}, nil
+		}
+
 	case *featurebase.GroupCounts:
 		fieldIDs := make(map[*featurebase.FieldInfo]map[uint64]struct{})
 		foreignIDs := make(map[*featurebase.FieldInfo]map[uint64]struct{})
@@ -3692,6 +4011,18 @@ func (o *qualifiedOrchestrator) Execute(ctx context.Context, tableKeyer dax.Tabl
 	return o.orchestrator.Execute(ctx, qtbl, q, shards, opt)
 }
 
+// Shards implements the featurebase.ShardLister interface.
+func (o *qualifiedOrchestrator) Shards(ctx context.Context, tableKeyer dax.TableKeyer) ([]uint64, error) {
+	switch keyer := tableKeyer.(type) {
+	case *dax.Table:
+		return o.orchestrator.Shards(ctx, dax.NewQualifiedTable(o.qdbid, keyer))
+	case *dax.QualifiedTable:
+		return o.orchestrator.Shards(ctx, keyer)
+	default:
+		return nil, errors.Errorf("qualifiedOrchestrator.Shards expects a *dax.Table or *dax.QualifiedTable, but got: %T", tableKeyer)
+	}
+}
+
 // schemaFieldInfo is a function introduced when we replaced
 // `schema.FieldInfo()` calls, where schema was a `featurebase.SchemaInfoAPI` to
 // `schema.Table().Field()` calls, where schema is a `pilosa.SchemaAPI`. In the
@@ -140,3 +140,25 @@ func (s *qualifiedSchemaAPI) DeleteField(ctx context.Context, tname dax.TableNam
 
 	return s.schemar.DropField(ctx, qtid.Key().QualifiedTableID(), fname)
 }
+
+func (s *qualifiedSchemaAPI) RenameTable(ctx context.Context, tname dax.TableName, newName dax.TableName) error {
+	qtbl, err := s.schemar.TableByName(ctx, s.qdbid, tname)
+	if err != nil {
+		return errors.Wrapf(err, "getting table by name: (%s) %s", s.qdbid, tname)
+	}
+
+	return s.schemar.RenameTable(ctx, qtbl.QualifiedID(), newName)
+}
+
+func (s *qualifiedSchemaAPI) RenameField(ctx context.Context, tname dax.TableName, fname dax.FieldName, newName dax.FieldName) error {
+	qtbl, err := s.schemar.TableByName(ctx, s.qdbid, tname)
+	if err != nil {
+		return errors.Wrapf(err, "getting table by name: (%s) %s", s.qdbid, tname)
+	}
+
+	return s.schemar.RenameField(ctx, qtbl.QualifiedID(), fname, newName)
+}
+
+func (s *qualifiedSchemaAPI) SetTableOption(ctx context.Context, tname dax.TableName, option string, value string) error {
+	return errors.New(errors.ErrUncoded, "qualifiedSchemaAPI.SetTableOption not implemented")
+}
@@ -2,6 +2,8 @@ package queryer
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"sync"
 
@@ -277,6 +279,37 @@ func (m *serverlessTranslator) TranslateFieldListIDs(ctx context.Context, index,
 	return makeTranslateIDsRequest(fbClient, index, field, ids)
 }
 
+func (m *serverlessTranslator) MatchFieldKeys(ctx context.Context, index, field string, syntax, pattern string) ([]uint64, error) {
+	qtid := dax.TableKey(index).QualifiedTableID()
+	address, err := m.controller.IngestPartition(ctx, qtid, dax.PartitionNum(0))
+	if err != nil {
+		return nil, errors.Wrapf(err, "calling ingest-partition on table: %s, partition: %d", index, dax.PartitionNum(0))
+	}
+
+	fbClient, err := fbClient(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting featurebase client")
+	}
+
+	path := fmt.Sprintf("/internal/translate/field/%s/%s/keys/%s", index, field, syntax)
+	headers := map[string]string{
+		"Accept": "application/json",
+	}
+
+	status, body, err := fbClient.HTTPRequest("POST", path, []byte(pattern), headers)
+	if err != nil {
+		return nil, errors.Wrap(err, "http request")
+	} else if status != http.StatusOK {
+		return nil, errors.Errorf("http request status code: %d", status)
+	}
+
+	var matches []uint64
+	if err := json.Unmarshal(body, &matches); err != nil {
+		return nil, errors.Wrap(err, "json decoding")
+	}
+	return matches, nil
+}
+
 func makeTranslateIDsRequest(fbClient *fbclient.Client, table, field string, ids []uint64) ([]string, error) {
 	method := "POST"
 	path := "/internal/translate/ids"
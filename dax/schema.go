@@ -33,6 +33,7 @@ type Schemar interface {
 
 	CreateTable(ctx context.Context, qtbl *QualifiedTable) error
 	DropTable(ctx context.Context, qtid QualifiedTableID) error
+	RenameTable(ctx context.Context, qtid QualifiedTableID, newName TableName) error
 
 	TableByName(ctx context.Context, qdbid QualifiedDatabaseID, tname TableName) (*QualifiedTable, error)
 	TableByID(ctx context.Context, qtid QualifiedTableID) (*QualifiedTable, error)
@@ -50,6 +51,7 @@ type Schemar interface {
 
 	CreateField(ctx context.Context, qtid QualifiedTableID, fld *Field) error
 	DropField(ctx context.Context, qtid QualifiedTableID, fname FieldName) error
+	RenameField(ctx context.Context, qtid QualifiedTableID, fname FieldName, newName FieldName) error
 }
 
 //////////////////////////////////////////////
@@ -97,9 +99,15 @@ func (s *NopSchemar) CreateTable(ctx context.Context, qtbl *QualifiedTable) erro
 func (s *NopSchemar) DropTable(ctx context.Context, qtid QualifiedTableID) error {
 	return nil
 }
+func (s *NopSchemar) RenameTable(ctx context.Context, qtid QualifiedTableID, newName TableName) error {
+	return nil
+}
 func (s *NopSchemar) CreateField(ctx context.Context, qtid QualifiedTableID, fld *Field) error {
 	return nil
 }
 func (s *NopSchemar) DropField(ctx context.Context, qtid QualifiedTableID, fld FieldName) error {
 	return nil
 }
+func (s *NopSchemar) RenameField(ctx context.Context, qtid QualifiedTableID, fld FieldName, newName FieldName) error {
+	return nil
+}
@@ -83,6 +83,7 @@ const (
 	BaseTypeStringSet  = "stringset"  // keyed set
 	BaseTypeStringSetQ = "stringsetq" // keyed set timequantum
 	BaseTypeTimestamp  = "timestamp"  //
+	BaseTypeGeo        = "geo"        // point, stored as a set of cells
 
 	DefaultPartitionN = 256
 
@@ -684,7 +685,8 @@ func BaseTypeFromString(s string) (BaseType, error) {
 		BaseTypeString,
 		BaseTypeStringSet,
 		BaseTypeStringSetQ,
-		BaseTypeTimestamp:
+		BaseTypeTimestamp,
+		BaseTypeGeo:
 		return BaseType(lowered), nil
 	default:
 		return "", errors.Errorf("invalid field type: %s", s)
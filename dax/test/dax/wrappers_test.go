@@ -72,6 +72,10 @@ func (w *wrappedControllerClient) DropTable(ctx context.Context, qtid dax.Qualif
 	return w.cli.DropTable(ctx, qtid)
 }
 
+func (w *wrappedControllerClient) RenameTable(ctx context.Context, qtid dax.QualifiedTableID, newName dax.TableName) error {
+	return w.cli.RenameTable(ctx, qtid, newName)
+}
+
 func (w *wrappedControllerClient) TableByName(ctx context.Context, qdbid dax.QualifiedDatabaseID, tname dax.TableName) (*dax.QualifiedTable, error) {
 	return w.cli.TableByName(ctx, qdbid, tname)
 }
@@ -91,3 +95,7 @@ func (w *wrappedControllerClient) CreateField(ctx context.Context, qtid dax.Qual
 func (w *wrappedControllerClient) DropField(ctx context.Context, qtid dax.QualifiedTableID, fname dax.FieldName) error {
 	return w.cli.DropField(ctx, qtid, fname)
 }
+
+func (w *wrappedControllerClient) RenameField(ctx context.Context, qtid dax.QualifiedTableID, fname dax.FieldName, newName dax.FieldName) error {
+	return w.cli.RenameField(ctx, qtid, fname, newName)
+}
@@ -48,6 +48,7 @@ type DBWrapper interface {
 	Close() error
 	DeleteFragment(index, field, view string, shard uint64, frag interface{}) error
 	DeleteField(index, field, fieldPath string) error
+	RenameField(index, field, newField string) error
 	OpenListString() string
 	Path() string
 	HasData() (has bool, err error)
@@ -97,6 +98,13 @@ func (dbs *DBShard) DeleteFieldFromStore(index, field, fieldPath string) (err er
 	return dbs.W.DeleteField(index, field, fieldPath)
 }
 
+func (dbs *DBShard) RenameFieldInStore(index, field, newField string) (err error) {
+	if index != dbs.Index {
+		return fmt.Errorf("RenameFieldInStore called on DBShard for %q with index %q", dbs.Index, index)
+	}
+	return dbs.W.RenameField(index, field, newField)
+}
+
 func (dbs *DBShard) Close() (err error) {
 	dbs.closed = true
 	return dbs.W.Close()
@@ -331,6 +339,49 @@ func (per *DBPerShard) DeleteFieldFromStore(index, field, fieldPath string) (err
 	return err
 }
 
+// RenameFieldInStore renames the data for field in every shard of index so that
+// it belongs to newField.
+func (per *DBPerShard) RenameFieldInStore(index, field, newField string) (err error) {
+	per.Mu.Lock()
+	defer per.Mu.Unlock()
+
+	dbi, ok := per.dbh.Index[index]
+	if !ok {
+		return nil
+	}
+	for _, dbs := range dbi.Shard {
+		if e := dbs.RenameFieldInStore(index, field, newField); e != nil && err == nil {
+			err = errors.Wrap(e, "RenameFieldInStore()")
+		}
+	}
+	return err
+}
+
+// ReleaseIndex closes and forgets the shard databases of index so that its
+// directory can be moved, as is done when an index is renamed. The keys within
+// a shard database don't include the index name, so once the directory has
+// been moved, the shards are lazily reopened under the new name without any
+// change to their contents.
+func (per *DBPerShard) ReleaseIndex(index string) (err error) {
+	per.Mu.Lock()
+	defer per.Mu.Unlock()
+
+	dbi, ok := per.dbh.Index[index]
+	if !ok {
+		return nil
+	}
+	for shard, dbs := range dbi.Shard {
+		if err := dbs.Close(); err != nil {
+			return errors.Wrap(err, "DBPerShard.ReleaseIndex dbs.Close()")
+		}
+		delete(per.Flatmap, flatkey{index: index, shard: shard})
+	}
+	delete(per.index2shards, index)
+	delete(per.dbh.Index, index)
+
+	return nil
+}
+
 func (per *DBPerShard) DeleteFragment(index, field, view string, shard uint64, frag *fragment) error {
 
 	idx := per.txf.holder.Index(index)
@@ -662,6 +713,32 @@ func (vs *FieldView2Shards) removeField(name string) {
 	delete(vs.m, name)
 }
 
+// fieldViewShards returns the shards of each of the field's open views. It
+// is used in place of the entries for the field, which only reflect the shards
+// which were present when the index was opened.
+func fieldViewShards(f *Field) map[string]*shardSet {
+	m := make(map[string]*shardSet)
+	for _, v := range f.views() {
+		ss := newShardSet()
+		for _, shard := range v.availableShards().Slice() {
+			ss.add(shard)
+		}
+		m[v.name] = ss
+	}
+	return m
+}
+
+// renameField replaces the views of field name with views, stored under
+// newName along with the BSI group view.
+func (vs *FieldView2Shards) renameField(name, newName string, views map[string]*shardSet) {
+	delete(vs.m, name)
+	renamed := make(map[string]*shardSet, len(views))
+	for view, ss := range views {
+		renamed[renamedView(name, newName, view)] = ss
+	}
+	vs.m[newName] = renamed
+}
+
 func (per *DBPerShard) GetFieldView2ShardsMapForIndex(idx *Index) (vs *FieldView2Shards, err error) {
 	ty := per.typ
 
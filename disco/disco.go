@@ -98,11 +98,22 @@ type Schemator interface {
 	Index(ctx context.Context, name string) ([]byte, error)
 
 	CreateIndex(ctx context.Context, name string, val []byte) error
+	UpdateIndex(ctx context.Context, name string, val []byte) error
 	DeleteIndex(ctx context.Context, name string) error
+
+	// RenameIndex moves the index, along with all of its fields and views,
+	// to newName, replacing the index data with val.
+	RenameIndex(ctx context.Context, name, newName string, val []byte) error
+
 	Field(ctx context.Context, index, field string) ([]byte, error)
 	CreateField(ctx context.Context, index, field string, fieldVal []byte) error
 	UpdateField(ctx context.Context, index, field string, fieldVal []byte) error
 	DeleteField(ctx context.Context, index, field string) error
+
+	// RenameField moves the field, along with all of its views, to newField,
+	// replacing the field data with fieldVal.
+	RenameField(ctx context.Context, index, field, newField string, fieldVal []byte) error
+
 	View(ctx context.Context, index, field, view string) (bool, error)
 	CreateView(ctx context.Context, index, field, view string) error
 	DeleteView(ctx context.Context, index, field, view string) error
@@ -235,11 +246,19 @@ func (*nopSchemator) Index(ctx context.Context, name string) ([]byte, error) { r
 // CreateIndex is a no-op implementation of the Schemator CreateIndex method.
 func (*nopSchemator) CreateIndex(ctx context.Context, name string, val []byte) error { return nil }
 
+// UpdateIndex is a no-op implementation of the Schemator UpdateIndex method.
+func (*nopSchemator) UpdateIndex(ctx context.Context, name string, val []byte) error { return nil }
+
 // DeleteIndex is a no-op implementation of the Schemator DeleteIndex method.
 func (*nopSchemator) DeleteIndex(ctx context.Context, name string) error {
 	return nil
 }
 
+// RenameIndex is a no-op implementation of the Schemator RenameIndex method.
+func (*nopSchemator) RenameIndex(ctx context.Context, name, newName string, val []byte) error {
+	return nil
+}
+
 // Field is a no-op implementation of the Schemator Field method.
 func (*nopSchemator) Field(ctx context.Context, index, field string) ([]byte, error) { return nil, nil }
 
@@ -258,6 +277,11 @@ func (*nopSchemator) DeleteField(ctx context.Context, index, field string) error
 	return nil
 }
 
+// RenameField is a no-op implementation of the Schemator RenameField method.
+func (*nopSchemator) RenameField(ctx context.Context, index, field, newField string, fieldVal []byte) error {
+	return nil
+}
+
 // View is a no-op implementation of the Schemator View method.
 func (*nopSchemator) View(ctx context.Context, index, field, view string) (bool, error) {
 	return false, nil
@@ -320,6 +344,18 @@ func (s *inMemSchemator) CreateIndex(ctx context.Context, name string, val []byt
 	return nil
 }
 
+// UpdateIndex is an in-memory implementation of the Schemator UpdateIndex method.
+func (s *inMemSchemator) UpdateIndex(ctx context.Context, name string, val []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx, ok := s.schema[name]
+	if !ok {
+		return ErrIndexDoesNotExist
+	}
+	idx.Data = val
+	return nil
+}
+
 // DeleteIndex is an in-memory implementation of the Schemator DeleteIndex method.
 func (s *inMemSchemator) DeleteIndex(ctx context.Context, name string) error {
 	s.mu.Lock()
@@ -328,6 +364,23 @@ func (s *inMemSchemator) DeleteIndex(ctx context.Context, name string) error {
 	return nil
 }
 
+// RenameIndex is an in-memory implementation of the Schemator RenameIndex method.
+func (s *inMemSchemator) RenameIndex(ctx context.Context, name, newName string, val []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx, ok := s.schema[name]
+	if !ok {
+		return ErrIndexDoesNotExist
+	}
+	if _, ok := s.schema[newName]; ok {
+		return ErrIndexExists
+	}
+	idx.Data = val
+	s.schema[newName] = idx
+	delete(s.schema, name)
+	return nil
+}
+
 // Field is an in-memory implementation of the Schemator Field method.
 func (s *inMemSchemator) Field(ctx context.Context, index, field string) ([]byte, error) {
 	s.mu.RLock()
@@ -395,6 +448,27 @@ func (s *inMemSchemator) DeleteField(ctx context.Context, index, field string) e
 	return nil
 }
 
+// RenameField is an in-memory implementation of the Schemator RenameField method.
+func (s *inMemSchemator) RenameField(ctx context.Context, index, field, newField string, fieldVal []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx, ok := s.schema[index]
+	if !ok {
+		return ErrIndexDoesNotExist
+	}
+	fld, ok := idx.Fields[field]
+	if !ok {
+		return ErrFieldDoesNotExist
+	}
+	if _, ok := idx.Fields[newField]; ok {
+		return ErrFieldExists
+	}
+	fld.Data = fieldVal
+	idx.Fields[newField] = fld
+	delete(idx.Fields, field)
+	return nil
+}
+
 // View is an in-memory implementation of the Schemator View method.
 func (s *inMemSchemator) View(ctx context.Context, index, field, view string) (bool, error) {
 	s.mu.RLock()
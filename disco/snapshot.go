@@ -34,6 +34,11 @@ type ClusterSnapshot struct {
 	ReplicaN int
 
 	PartitionAssignment string
+
+	// PartitionKey returns the name an index's shards and keys are hashed by,
+	// which stays the same when the index is renamed. If it is nil, the
+	// index name is used.
+	PartitionKey func(index string) string
 }
 
 // NewClusterSnapshot returns a new instance of ClusterSnapshot.
@@ -62,7 +67,15 @@ func NewClusterSnapshot(noder Noder, hasher Hasher, partitionAssignment string,
 // ShardToShardPartition returns the shard-partition that the given shard
 // belongs to. NOTE: This is DIFFERENT from the key-partition.
 func (c *ClusterSnapshot) ShardToShardPartition(index string, shard uint64) int {
-	return ShardToShardPartition(index, shard, c.PartitionN)
+	return ShardToShardPartition(c.partitionKey(index), shard, c.PartitionN)
+}
+
+// partitionKey returns the name the shards and keys of index are hashed by.
+func (c *ClusterSnapshot) partitionKey(index string) string {
+	if c.PartitionKey == nil {
+		return index
+	}
+	return c.PartitionKey(index)
 }
 
 // ShardToShardParition ...
@@ -87,7 +100,7 @@ func (c *ClusterSnapshot) IDToShardPartition(index string, id uint64) int {
 func (c *ClusterSnapshot) KeyToKeyPartition(index, key string) int {
 	// Hash the bytes and mod by partition count.
 	h := fnv.New64a()
-	_, _ = h.Write([]byte(index))
+	_, _ = h.Write([]byte(c.partitionKey(index)))
 	_, _ = h.Write([]byte(key))
 	return int(h.Sum64() % uint64(c.PartitionN))
 }
@@ -245,7 +258,7 @@ func (c *ClusterSnapshot) PrimaryForShardReplication(index string, shard uint64)
 	if n == 0 {
 		return -1
 	}
-	partition := ShardToShardPartition(index, shard, c.PartitionN)
+	partition := c.ShardToShardPartition(index, shard)
 	nodeIndex := c.PrimaryNodeIndex(partition)
 	return nodeIndex
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/apache/arrow/go/v10/parquet/pqarrow"
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/disco"
+	"github.com/featurebasedb/featurebase/v3/hll"
 	pnet "github.com/featurebasedb/featurebase/v3/net"
 	"github.com/featurebasedb/featurebase/v3/pb"
 	"github.com/featurebasedb/featurebase/v3/pql"
@@ -90,6 +91,30 @@ func (s Serializer) Unmarshal(buf []byte, m pilosa.Message) error {
 		}
 		s.decodeUpdateFieldMessage(msg, mt)
 		return nil
+	case *pilosa.UpdateIndexMessage:
+		msg := &pb.UpdateIndexMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling UpdateIndexMessage")
+		}
+		s.decodeUpdateIndexMessage(msg, mt)
+		return nil
+	case *pilosa.RenameIndexMessage:
+		msg := &pb.RenameIndexMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling RenameIndexMessage")
+		}
+		s.decodeRenameIndexMessage(msg, mt)
+		return nil
+	case *pilosa.RenameFieldMessage:
+		msg := &pb.RenameFieldMessage{}
+		err := proto.Unmarshal(buf, msg)
+		if err != nil {
+			return errors.Wrap(err, "unmarshaling RenameFieldMessage")
+		}
+		s.decodeRenameFieldMessage(msg, mt)
+		return nil
 	case *pilosa.DeleteFieldMessage:
 		msg := &pb.DeleteFieldMessage{}
 		err := proto.Unmarshal(buf, msg)
@@ -317,6 +342,8 @@ func (s Serializer) encodeToProto(m pilosa.Message) proto.Message {
 		return s.encodeCreateShardMessage(mt)
 	case *pilosa.CreateIndexMessage:
 		return s.encodeCreateIndexMessage(mt)
+	case *pilosa.UpdateIndexMessage:
+		return s.encodeUpdateIndexMessage(mt)
 	case *pilosa.DeleteIndexMessage:
 		return s.encodeDeleteIndexMessage(mt)
 	case *pilosa.CreateFieldMessage:
@@ -325,6 +352,10 @@ func (s Serializer) encodeToProto(m pilosa.Message) proto.Message {
 		return s.encodeUpdateFieldMessage(mt)
 	case *pilosa.DeleteFieldMessage:
 		return s.encodeDeleteFieldMessage(mt)
+	case *pilosa.RenameIndexMessage:
+		return s.encodeRenameIndexMessage(mt)
+	case *pilosa.RenameFieldMessage:
+		return s.encodeRenameFieldMessage(mt)
 	case *pilosa.DeleteAvailableShardMessage:
 		return s.encodeDeleteAvailableShardMessage(mt)
 	case *pilosa.CreateViewMessage:
@@ -561,6 +592,15 @@ func (s Serializer) encodeQueryResponse(m *pilosa.QueryResponse) *pb.QueryRespon
 		case pilosa.ExtractedIDMatrixSorted:
 			resp.Results[i].Type = queryResultTypeExtractedIDMatrixSorted
 			resp.Results[i].ExtractedIDMatrixSorted = s.endcodeExtractedIDMatrixSorted(result)
+		case *hll.Sketch:
+			resp.Results[i].Type = queryResultTypeDistinctSketch
+			resp.Results[i].DistinctSketch, _ = result.MarshalBinary()
+		case *pilosa.FunnelResult:
+			resp.Results[i].Type = queryResultTypeFunnel
+			resp.Results[i].Funnel, _ = result.MarshalBinary()
+		case *pilosa.SimilarResult:
+			resp.Results[i].Type = queryResultTypeSimilar
+			resp.Results[i].Similar, _ = result.MarshalBinary()
 		default:
 			panic(fmt.Errorf("unknown type: %T", m.Results[i]))
 		}
@@ -697,9 +737,26 @@ func (s Serializer) encodeCreateIndexMessage(m *pilosa.CreateIndexMessage) *pb.C
 
 func (s Serializer) encodeIndexMeta(m *pilosa.IndexOptions) *pb.IndexMeta {
 	return &pb.IndexMeta{
-		Description:    m.Description,
-		Keys:           m.Keys,
-		TrackExistence: m.TrackExistence,
+		Description:     m.Description,
+		Keys:            m.Keys,
+		TrackExistence:  m.TrackExistence,
+		RetentionField:  m.RetentionField,
+		RetentionPeriod: m.RetentionPeriod.String(),
+		PartitionKey:    m.PartitionKey,
+	}
+}
+
+func (s Serializer) encodeUpdateIndexMessage(m *pilosa.UpdateIndexMessage) *pb.UpdateIndexMessage {
+	return &pb.UpdateIndexMessage{
+		CreateIndexMessage: s.encodeCreateIndexMessage(&m.CreateIndexMessage),
+		Update:             s.encodeIndexUpdate(&m.Update),
+	}
+}
+
+func (s Serializer) encodeIndexUpdate(m *pilosa.IndexUpdate) *pb.IndexUpdate {
+	return &pb.IndexUpdate{
+		Option: m.Option,
+		Value:  m.Value,
 	}
 }
 
@@ -740,6 +797,21 @@ func (s Serializer) encodeDeleteFieldMessage(m *pilosa.DeleteFieldMessage) *pb.D
 	}
 }
 
+func (s Serializer) encodeRenameIndexMessage(m *pilosa.RenameIndexMessage) *pb.RenameIndexMessage {
+	return &pb.RenameIndexMessage{
+		Index:    m.Index,
+		NewIndex: m.NewIndex,
+	}
+}
+
+func (s Serializer) encodeRenameFieldMessage(m *pilosa.RenameFieldMessage) *pb.RenameFieldMessage {
+	return &pb.RenameFieldMessage{
+		Index:    m.Index,
+		Field:    m.Field,
+		NewField: m.NewField,
+	}
+}
+
 func (s Serializer) encodeDeleteAvailableShardMessage(m *pilosa.DeleteAvailableShardMessage) *pb.DeleteAvailableShardMessage {
 	return &pb.DeleteAvailableShardMessage{
 		Index:   m.Index,
@@ -1044,9 +1116,26 @@ func (s Serializer) decodeIndexMeta(pb *pb.IndexMeta, m *pilosa.IndexOptions) {
 		m.Description = pb.Description
 		m.Keys = pb.Keys
 		m.TrackExistence = pb.TrackExistence
+		m.RetentionField = pb.RetentionField
+		retention, err := time.ParseDuration(pb.RetentionPeriod)
+		if err != nil {
+			retention = 0
+		}
+		m.RetentionPeriod = retention
+		m.PartitionKey = pb.PartitionKey
 	}
 }
 
+func (s Serializer) decodeUpdateIndexMessage(pb *pb.UpdateIndexMessage, m *pilosa.UpdateIndexMessage) {
+	s.decodeCreateIndexMessage(pb.CreateIndexMessage, &m.CreateIndexMessage)
+	s.decodeIndexUpdate(pb.Update, &m.Update)
+}
+
+func (s Serializer) decodeIndexUpdate(pb *pb.IndexUpdate, m *pilosa.IndexUpdate) {
+	m.Option = pb.Option
+	m.Value = pb.Value
+}
+
 func (s Serializer) decodeDeleteIndexMessage(pb *pb.DeleteIndexMessage, m *pilosa.DeleteIndexMessage) {
 	m.Index = pb.Index
 }
@@ -1075,6 +1164,17 @@ func (s Serializer) decodeDeleteFieldMessage(pb *pb.DeleteFieldMessage, m *pilos
 	m.Field = pb.Field
 }
 
+func (s Serializer) decodeRenameIndexMessage(pb *pb.RenameIndexMessage, m *pilosa.RenameIndexMessage) {
+	m.Index = pb.Index
+	m.NewIndex = pb.NewIndex
+}
+
+func (s Serializer) decodeRenameFieldMessage(pb *pb.RenameFieldMessage, m *pilosa.RenameFieldMessage) {
+	m.Index = pb.Index
+	m.Field = pb.Field
+	m.NewField = pb.NewField
+}
+
 func (s Serializer) decodeDeleteAvailableShardMessage(pb *pb.DeleteAvailableShardMessage, m *pilosa.DeleteAvailableShardMessage) {
 	m.Index = pb.Index
 	m.Field = pb.Field
@@ -1314,6 +1414,36 @@ func (s Serializer) decodeDistinctTimestamp(pb *pb.DistinctTimestamp) pilosa.Dis
 	}
 }
 
+// decodeDistinctSketch returns the sketch encoded in data, or the error
+// decoding it; the error is reduced like any other result, ending the query.
+func (s Serializer) decodeDistinctSketch(data []byte) interface{} {
+	sketch := &hll.Sketch{}
+	if err := sketch.UnmarshalBinary(data); err != nil {
+		return errors.Wrap(err, "decoding distinct sketch")
+	}
+	return sketch
+}
+
+// decodeFunnel returns the funnel result encoded in data, or the error
+// decoding it.
+func (s Serializer) decodeFunnel(data []byte) interface{} {
+	funnel := &pilosa.FunnelResult{}
+	if err := funnel.UnmarshalBinary(data); err != nil {
+		return errors.Wrap(err, "decoding funnel")
+	}
+	return funnel
+}
+
+// decodeSimilar returns the similar rows result encoded in data, or the
+// error decoding it.
+func (s Serializer) decodeSimilar(data []byte) interface{} {
+	similar := &pilosa.SimilarResult{}
+	if err := similar.UnmarshalBinary(data); err != nil {
+		return errors.Wrap(err, "decoding similar rows")
+	}
+	return similar
+}
+
 func decodeTransaction(pb *pb.Transaction, trns *pilosa.Transaction) {
 	trns.ID = pb.ID
 	trns.Active = pb.Active
@@ -1345,6 +1475,9 @@ const (
 	queryResultTypeDataFrame
 	queryResultTypeArrowTable
 	queryResultTypeExtractedIDMatrixSorted
+	queryResultTypeDistinctSketch
+	queryResultTypeFunnel
+	queryResultTypeSimilar
 )
 
 func (s Serializer) decodeQueryResult(pb *pb.QueryResult) interface{} {
@@ -1389,6 +1522,12 @@ func (s Serializer) decodeQueryResult(pb *pb.QueryResult) interface{} {
 		return s.decodeArrowTable(pb.ArrowTable)
 	case queryResultTypeExtractedIDMatrixSorted:
 		return s.decodeExtractedIDMatrixSorted(pb.ExtractedIDMatrixSorted)
+	case queryResultTypeDistinctSketch:
+		return s.decodeDistinctSketch(pb.DistinctSketch)
+	case queryResultTypeFunnel:
+		return s.decodeFunnel(pb.Funnel)
+	case queryResultTypeSimilar:
+		return s.decodeSimilar(pb.Similar)
 	}
 	panic(fmt.Sprintf("unknown type: %d", pb.Type))
 }
@@ -5,6 +5,7 @@ package proto
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/apache/arrow/go/v10/arrow"
 	"github.com/apache/arrow/go/v10/arrow/array"
@@ -68,6 +69,23 @@ func TestEncodeDecodeDistinctTimestamp(t *testing.T) {
 	}
 }
 
+func TestUpdateIndexMessage(t *testing.T) {
+	testOneRoundTrip(t, Serializer{}, &pilosa.UpdateIndexMessage{
+		CreateIndexMessage: pilosa.CreateIndexMessage{
+			Index:     "i",
+			CreatedAt: 1,
+			Meta: pilosa.IndexOptions{
+				Keys:            true,
+				TrackExistence:  true,
+				RetentionField:  "ts",
+				RetentionPeriod: 24 * time.Hour,
+				PartitionKey:    "h",
+			},
+		},
+		Update: pilosa.IndexUpdate{Option: "retentionPeriod", Value: "24h"},
+	}, nil, nil, nil)
+}
+
 func TestDecodeQueryResult(t *testing.T) {
 	t.Run("DistinctTimestamp", func(t *testing.T) {
 		pbTime := pb.DistinctTimestamp{
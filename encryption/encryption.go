@@ -0,0 +1,175 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+
+// Package encryption implements encryption at rest for the files FeatureBase
+// keeps under its data directory. Data is sealed with AES-GCM using keys from
+// a Keyring; each sealed message records the ID of the key that sealed it, so
+// keys can be rotated by adding a new current key while older keys remain
+// available for reading.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	keyIDSize = 4
+	nonceSize = 12
+	tagSize   = 16
+
+	// Overhead is the number of bytes a sealed message is longer than its
+	// plaintext: the key ID, the nonce and the authentication tag.
+	Overhead = keyIDSize + nonceSize + tagSize
+)
+
+// ErrDecrypt is returned when a sealed message fails authentication, which
+// means it was sealed with a different key or has been modified.
+var ErrDecrypt = errors.New("encryption: message authentication failed")
+
+// Cipher seals and opens data using the keys in a keyring. Messages are
+// always sealed with the keyring's current key, and may be opened with any
+// key the keyring holds.
+//
+// Every message gets a random nonce, so a single key should not seal more
+// than a few billion messages; rotate keys well before that.
+type Cipher struct {
+	keyring Keyring
+
+	mu         sync.RWMutex
+	aeads      map[uint32]cipher.AEAD
+	digestKeys map[uint32][]byte
+}
+
+// NewCipher returns a Cipher using keys from keyring.
+func NewCipher(keyring Keyring) (*Cipher, error) {
+	c := &Cipher{
+		keyring:    keyring,
+		aeads:      make(map[uint32]cipher.AEAD),
+		digestKeys: make(map[uint32][]byte),
+	}
+	// fail early if the current key is unusable.
+	if _, err := c.aead(keyring.CurrentKeyID()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CurrentKeyID returns the ID of the key used to seal new messages.
+func (c *Cipher) CurrentKeyID() uint32 {
+	return c.keyring.CurrentKeyID()
+}
+
+// aead returns the AEAD for a key, creating it on first use.
+func (c *Cipher) aead(id uint32) (cipher.AEAD, error) {
+	c.mu.RLock()
+	aead := c.aeads[id]
+	c.mu.RUnlock()
+	if aead != nil {
+		return aead, nil
+	}
+
+	key, err := c.keyring.Key(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting key %d", id)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "key %d", id)
+	}
+	if aead, err = cipher.NewGCM(block); err != nil {
+		return nil, errors.Wrapf(err, "key %d", id)
+	}
+
+	c.mu.Lock()
+	c.aeads[id] = aead
+	c.mu.Unlock()
+	return aead, nil
+}
+
+// Seal encrypts and authenticates plaintext with the current key, appends
+// the result to dst and returns the updated slice. The result is
+// len(plaintext)+Overhead bytes long.
+func (c *Cipher) Seal(dst, plaintext []byte) ([]byte, error) {
+	id := c.keyring.CurrentKeyID()
+	aead, err := c.aead(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var header [keyIDSize + nonceSize]byte
+	binary.BigEndian.PutUint32(header[:keyIDSize], id)
+	if _, err := io.ReadFull(rand.Reader, header[keyIDSize:]); err != nil {
+		return nil, errors.Wrap(err, "generating nonce")
+	}
+
+	// the key ID is authenticated along with the data so it cannot be
+	// swapped for another.
+	dst = append(dst, header[:]...)
+	return aead.Seal(dst, header[keyIDSize:], plaintext, header[:keyIDSize]), nil
+}
+
+// Open authenticates and decrypts a message produced by Seal, appends the
+// plaintext to dst and returns the updated slice.
+func (c *Cipher) Open(dst, sealed []byte) ([]byte, error) {
+	if len(sealed) < Overhead {
+		return nil, errors.Errorf("encryption: sealed message too short: %d bytes", len(sealed))
+	}
+	aead, err := c.aead(binary.BigEndian.Uint32(sealed[:keyIDSize]))
+	if err != nil {
+		return nil, err
+	}
+	nonce := sealed[keyIDSize : keyIDSize+nonceSize]
+	out, err := aead.Open(dst, nonce, sealed[keyIDSize+nonceSize:], sealed[:keyIDSize])
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return out, nil
+}
+
+// KeyID returns the ID of the key that sealed a message.
+func KeyID(sealed []byte) (uint32, error) {
+	if len(sealed) < Overhead {
+		return 0, errors.Errorf("encryption: sealed message too short: %d bytes", len(sealed))
+	}
+	return binary.BigEndian.Uint32(sealed[:keyIDSize]), nil
+}
+
+// Digest returns a keyed hash of data using the given key. Unlike Seal it is
+// deterministic, so it can be used to index sealed values by their plaintext
+// without storing the plaintext. Callers must record which key they used,
+// since a digest can only be reproduced with the same key.
+func (c *Cipher) Digest(keyID uint32, data []byte) ([]byte, error) {
+	c.mu.RLock()
+	digestKey := c.digestKeys[keyID]
+	c.mu.RUnlock()
+
+	if digestKey == nil {
+		key, err := c.keyring.Key(keyID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting key %d", keyID)
+		}
+
+		// derive a separate key for hashing rather than using the
+		// encryption key directly.
+		derive := hmac.New(sha256.New, key)
+		derive.Write([]byte("featurebase digest"))
+		digestKey = derive.Sum(nil)
+
+		c.mu.Lock()
+		c.digestKeys[keyID] = digestKey
+		c.mu.Unlock()
+	}
+
+	mac := hmac.New(sha256.New, digestKey)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
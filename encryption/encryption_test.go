@@ -0,0 +1,136 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package encryption_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/featurebasedb/featurebase/v3/encryption"
+)
+
+func mustCipher(t *testing.T, keys map[uint32][]byte) *encryption.Cipher {
+	t.Helper()
+	keyring, err := encryption.NewStaticKeyring(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := encryption.NewCipher(keyring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCipher_SealOpen(t *testing.T) {
+	c := mustCipher(t, map[uint32][]byte{1: bytes.Repeat([]byte{1}, 32)})
+
+	plaintext := []byte("the quick brown fox")
+	sealed, err := c.Seal(nil, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(sealed) != len(plaintext)+encryption.Overhead {
+		t.Fatalf("expected %d bytes, got %d", len(plaintext)+encryption.Overhead, len(sealed))
+	} else if bytes.Contains(sealed, plaintext) {
+		t.Fatal("sealed message contains plaintext")
+	}
+
+	if opened, err := c.Open(nil, sealed); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(opened, plaintext) {
+		t.Fatalf("expected %q, got %q", plaintext, opened)
+	}
+
+	// sealing twice uses different nonces.
+	if again, err := c.Seal(nil, plaintext); err != nil {
+		t.Fatal(err)
+	} else if bytes.Equal(again, sealed) {
+		t.Fatal("expected different sealed messages")
+	}
+
+	sealed[len(sealed)-1] ^= 1
+	if _, err := c.Open(nil, sealed); err != encryption.ErrDecrypt {
+		t.Fatalf("expected ErrDecrypt, got %v", err)
+	}
+}
+
+func TestCipher_Rotation(t *testing.T) {
+	oldKey, newKey := bytes.Repeat([]byte{1}, 16), bytes.Repeat([]byte{2}, 16)
+	old := mustCipher(t, map[uint32][]byte{1: oldKey})
+	sealed, err := old.Seal(nil, []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := mustCipher(t, map[uint32][]byte{1: oldKey, 2: newKey})
+	if rotated.CurrentKeyID() != 2 {
+		t.Fatalf("expected current key 2, got %d", rotated.CurrentKeyID())
+	}
+	if opened, err := rotated.Open(nil, sealed); err != nil {
+		t.Fatal(err)
+	} else if string(opened) != "data" {
+		t.Fatalf("unexpected plaintext %q", opened)
+	}
+
+	resealed, err := rotated.Seal(nil, []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	} else if id, _ := encryption.KeyID(resealed); id != 2 {
+		t.Fatalf("expected key 2, got %d", id)
+	}
+	if _, err := old.Open(nil, resealed); err == nil {
+		t.Fatal("expected error opening with a keyring missing the key")
+	}
+}
+
+func TestCipher_Digest(t *testing.T) {
+	c := mustCipher(t, map[uint32][]byte{1: bytes.Repeat([]byte{1}, 16), 2: bytes.Repeat([]byte{2}, 16)})
+	a, _ := c.Digest(1, []byte("key"))
+	b, _ := c.Digest(1, []byte("key"))
+	d, _ := c.Digest(2, []byte("key"))
+	if !bytes.Equal(a, b) {
+		t.Fatal("expected digest to be deterministic")
+	} else if bytes.Equal(a, d) {
+		t.Fatal("expected digests under different keys to differ")
+	}
+}
+
+func TestLoadKeyfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(path, []byte(`# rotated 2023-01-01
+1 000102030405060708090a0b0c0d0e0f
+2 101112131415161718191a1b1c1d1e1f101112131415161718191a1b1c1d1e1f
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keyring, err := encryption.LoadKeyfile(path)
+	if err != nil {
+		t.Fatal(err)
+	} else if keyring.CurrentKeyID() != 2 {
+		t.Fatalf("expected current key 2, got %d", keyring.CurrentKeyID())
+	}
+
+	c, err := encryption.Open(encryption.Config{Keyfile: path})
+	if err != nil {
+		t.Fatal(err)
+	} else if c == nil {
+		t.Fatal("expected cipher")
+	}
+	if c, err := encryption.Open(encryption.Config{}); err != nil || c != nil {
+		t.Fatalf("expected no cipher, got %v, %v", c, err)
+	}
+	if _, err := encryption.Open(encryption.Config{Provider: "nope"}); err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+
+	for _, bad := range []string{"1\n", "x 0011\n", "1 zz\n", "1 0011\n", "1 000102030405060708090a0b0c0d0e0f\n1 000102030405060708090a0b0c0d0e0f\n"} {
+		if err := os.WriteFile(path, []byte(bad), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := encryption.LoadKeyfile(path); err == nil {
+			t.Fatalf("expected error for keyfile %q", bad)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package encryption
+
+import (
+	"bufio"
+	"encoding/hex"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Keyring supplies encryption keys by ID. Keys must be 16, 24 or 32 bytes
+// long, selecting AES-128, AES-192 or AES-256.
+type Keyring interface {
+	// CurrentKeyID returns the ID of the key new data is sealed with.
+	CurrentKeyID() uint32
+
+	// Key returns the key with the given ID.
+	Key(id uint32) ([]byte, error)
+}
+
+// Config configures encryption at rest. Encryption is disabled when both
+// Provider and Keyfile are empty.
+type Config struct {
+	// Provider names the keyring provider. It defaults to "keyfile" when
+	// Keyfile is set.
+	Provider string `toml:"provider"`
+
+	// Keyfile is the path of the keyfile read by the "keyfile" provider.
+	Keyfile string `toml:"keyfile"`
+
+	// Params holds provider specific settings, such as the address of a
+	// key management service.
+	Params map[string]string `toml:"params"`
+}
+
+// Enabled returns true if the config enables encryption.
+func (cfg Config) Enabled() bool {
+	return cfg.Provider != "" || cfg.Keyfile != ""
+}
+
+// ProviderFunc returns a keyring for a config.
+type ProviderFunc func(cfg Config) (Keyring, error)
+
+var providers = struct {
+	mu sync.RWMutex
+	m  map[string]ProviderFunc
+}{
+	m: map[string]ProviderFunc{
+		"keyfile": func(cfg Config) (Keyring, error) {
+			if cfg.Keyfile == "" {
+				return nil, errors.New("keyfile provider requires a keyfile")
+			}
+			return LoadKeyfile(cfg.Keyfile)
+		},
+	},
+}
+
+// RegisterProvider makes a keyring provider available by name, so that key
+// management services can be plugged in without changes to this package.
+// Registering a name twice replaces the earlier provider.
+func RegisterProvider(name string, fn ProviderFunc) {
+	providers.mu.Lock()
+	defer providers.mu.Unlock()
+	providers.m[name] = fn
+}
+
+// Open returns a cipher for a config, or nil if the config does not enable
+// encryption.
+func Open(cfg Config) (*Cipher, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	name := cfg.Provider
+	if name == "" {
+		name = "keyfile"
+	}
+
+	providers.mu.RLock()
+	fn := providers.m[name]
+	providers.mu.RUnlock()
+	if fn == nil {
+		return nil, errors.Errorf("unknown encryption provider: %q", name)
+	}
+
+	keyring, err := fn(cfg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s keyring", name)
+	}
+	return NewCipher(keyring)
+}
+
+// StaticKeyring is a Keyring holding a fixed set of keys. The key with the
+// highest ID is the current key.
+type StaticKeyring struct {
+	keys    map[uint32][]byte
+	current uint32
+}
+
+// NewStaticKeyring returns a keyring holding keys.
+func NewStaticKeyring(keys map[uint32][]byte) (*StaticKeyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keyring has no keys")
+	}
+	k := &StaticKeyring{keys: make(map[uint32][]byte, len(keys))}
+	for id, key := range keys {
+		switch len(key) {
+		case 16, 24, 32:
+		default:
+			return nil, errors.Errorf("key %d must be 16, 24 or 32 bytes: %d", id, len(key))
+		}
+		k.keys[id] = append([]byte(nil), key...)
+		if id > k.current {
+			k.current = id
+		}
+	}
+	return k, nil
+}
+
+// CurrentKeyID returns the highest key ID in the keyring.
+func (k *StaticKeyring) CurrentKeyID() uint32 {
+	return k.current
+}
+
+// Key returns the key with the given ID.
+func (k *StaticKeyring) Key(id uint32) ([]byte, error) {
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, errors.Errorf("key %d not found in keyring", id)
+	}
+	return key, nil
+}
+
+// KeyIDs returns the IDs of the keys in the keyring in ascending order.
+func (k *StaticKeyring) KeyIDs() []uint32 {
+	ids := make([]uint32, 0, len(k.keys))
+	for id := range k.keys {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// LoadKeyfile reads a keyring from a keyfile. Each non-blank line of the
+// file holds a key ID and a hex encoded key separated by whitespace; lines
+// starting with # are comments. To rotate keys, append a line with a higher
+// ID and restart: new data is sealed with the new key while the old keys
+// still open existing data.
+func LoadKeyfile(path string) (*StaticKeyring, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening keyfile")
+	}
+	defer f.Close()
+
+	keys := make(map[uint32][]byte)
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, errors.Errorf("keyfile line %d: expected key ID and key", lineNo)
+		}
+		id, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("keyfile line %d: invalid key ID: %q", lineNo, fields[0])
+		}
+		key, err := hex.DecodeString(fields[1])
+		if err != nil {
+			return nil, errors.Errorf("keyfile line %d: key is not hex encoded", lineNo)
+		}
+		if _, ok := keys[uint32(id)]; ok {
+			return nil, errors.Errorf("keyfile line %d: duplicate key ID %d", lineNo, id)
+		}
+		keys[uint32(id)] = key
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading keyfile")
+	}
+	return NewStaticKeyring(keys)
+}
@@ -838,6 +838,33 @@ func (e *Etcd) CreateIndex(ctx context.Context, name string, val []byte) error {
 	return nil
 }
 
+func (e *Etcd) UpdateIndex(ctx context.Context, name string, val []byte) error {
+	key := schemaPrefix + name
+
+	// Set up Op to write index value as bytes.
+	op := clientv3.OpPut(key, "")
+	op.WithValueBytes(val)
+
+	// Check for key existence, and execute Op within a transaction.
+	var resp *clientv3.TxnResponse
+	err := e.retryClient(func(cli *clientv3.Client) (err error) {
+		resp, err = cli.Txn(ctx).
+			If(clientv3util.KeyExists(key)).
+			Then(op).
+			Commit()
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "executing transaction")
+	}
+
+	if !resp.Succeeded {
+		return disco.ErrIndexDoesNotExist
+	}
+
+	return nil
+}
+
 func (e *Etcd) Index(ctx context.Context, name string) ([]byte, error) {
 	return e.getKeyBytes(ctx, schemaPrefix+name)
 }
@@ -859,6 +886,11 @@ func (e *Etcd) DeleteIndex(ctx context.Context, name string) (err error) {
 	return errors.Wrap(err, "DeleteIndex")
 }
 
+func (e *Etcd) RenameIndex(ctx context.Context, name, newName string, val []byte) error {
+	err := e.moveKey(ctx, schemaPrefix+name, schemaPrefix+newName, val, disco.ErrIndexDoesNotExist, disco.ErrIndexExists)
+	return errors.Wrap(err, "RenameIndex")
+}
+
 func (e *Etcd) Field(ctx context.Context, indexName string, name string) ([]byte, error) {
 	key := schemaPrefix + indexName + "/" + name
 	return e.getKeyBytes(ctx, key)
@@ -948,6 +980,89 @@ func (e *Etcd) DeleteField(ctx context.Context, indexName string, name string) (
 	return nil
 }
 
+func (e *Etcd) RenameField(ctx context.Context, indexName, name, newName string, fieldVal []byte) error {
+	key := schemaPrefix + indexName + "/" + name
+	newKey := schemaPrefix + indexName + "/" + newName
+	err := e.moveKey(ctx, key, newKey, fieldVal, disco.ErrFieldDoesNotExist, disco.ErrFieldExists)
+	return errors.Wrap(err, "RenameField")
+}
+
+// maxMoveOps limits the number of operations in each of the transactions used
+// by moveKey, keeping them below etcd's default limit of 128 operations per
+// transaction.
+const maxMoveOps = 100
+
+// moveKey moves key, along with every key nested below it, to newKey. The
+// value of newKey is set to val; nested keys keep their values. errNotExist is
+// returned if key does not exist and errExists is returned if newKey already
+// exists.
+func (e *Etcd) moveKey(ctx context.Context, key, newKey string, val []byte, errNotExist, errExists error) error {
+	keys, vals, err := e.getKeyWithPrefix(ctx, key+"/")
+	if err != nil {
+		return errors.Wrap(err, "getting nested keys")
+	}
+
+	op := clientv3.OpPut(newKey, "")
+	op.WithValueBytes(val)
+
+	// Claim newKey first, so that a concurrent create or rename fails.
+	var resp *clientv3.TxnResponse
+	err = e.retryClient(func(cli *clientv3.Client) (err error) {
+		resp, err = cli.Txn(ctx).
+			If(
+				clientv3util.KeyExists(key),
+				clientv3util.KeyMissing(newKey)).
+			Then(op).
+			Commit()
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "executing transaction")
+	}
+	if !resp.Succeeded {
+		if exists, err := e.keyExists(ctx, key); err != nil {
+			return errors.Wrap(err, "checking key existence")
+		} else if !exists {
+			return errNotExist
+		}
+		return errExists
+	}
+
+	// Copy the nested keys.
+	for len(keys) > 0 {
+		n := len(keys)
+		if n > maxMoveOps {
+			n = maxMoveOps
+		}
+		ops := make([]clientv3.Op, 0, n)
+		for i := range keys[:n] {
+			op := clientv3.OpPut(newKey+strings.TrimPrefix(keys[i], key), "")
+			op.WithValueBytes(vals[i])
+			ops = append(ops, op)
+		}
+		keys, vals = keys[n:], vals[n:]
+
+		err = e.retryClient(func(cli *clientv3.Client) (err error) {
+			_, err = cli.Txn(ctx).Then(ops...).Commit()
+			return err
+		})
+		if err != nil {
+			return errors.Wrap(err, "copying nested keys")
+		}
+	}
+
+	// Remove the old keys.
+	err = e.retryClient(func(cli *clientv3.Client) (err error) {
+		_, err = cli.Txn(ctx).
+			Then(
+				clientv3.OpDelete(key+"/", clientv3.WithPrefix()),
+				clientv3.OpDelete(key),
+			).Commit()
+		return err
+	})
+	return errors.Wrap(err, "deleting old keys")
+}
+
 func (e *Etcd) View(ctx context.Context, indexName, fieldName, name string) (bool, error) {
 	key := schemaPrefix + indexName + "/" + fieldName + "/" + name
 	return e.keyExists(ctx, key)
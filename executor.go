@@ -3,14 +3,19 @@
 package pilosa
 
 import (
+	"bytes"
+	"container/heap"
 	"context"
 	"database/sql"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"math"
 	"math/bits"
+	"math/rand"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -19,6 +24,8 @@ import (
 
 	"github.com/featurebasedb/featurebase/v3/dax"
 	"github.com/featurebasedb/featurebase/v3/disco"
+	"github.com/featurebasedb/featurebase/v3/encryption"
+	"github.com/featurebasedb/featurebase/v3/hll"
 	"github.com/featurebasedb/featurebase/v3/pql"
 	"github.com/featurebasedb/featurebase/v3/proto"
 	"github.com/featurebasedb/featurebase/v3/roaring"
@@ -51,6 +58,20 @@ type Executor interface {
 	Execute(context.Context, dax.TableKeyer, *pql.Query, []uint64, *ExecOptions) (QueryResponse, error)
 }
 
+// ShardLister is implemented by executors which can report the shards holding
+// data for a table. Callers use it to execute a query over a batch of shards
+// at a time, rather than materializing the result for the whole table.
+type ShardLister interface {
+	Shards(context.Context, dax.TableKeyer) ([]uint64, error)
+}
+
+// ShardVersioner is implemented by executors which can report a version for
+// each shard of a table, which changes whenever the shard is written to.
+// Callers use it to find the shards which changed since they last read them.
+type ShardVersioner interface {
+	ShardVersions(context.Context, dax.TableKeyer) (map[uint64]uint64, error)
+}
+
 // executor recursively executes calls in a PQL query across all shards.
 type executor struct {
 	Holder *Holder
@@ -68,7 +89,9 @@ type executor struct {
 	shutdown       chan struct{}
 	workers        *task.Pool
 	workerPoolSize int
-	work           chan job
+	// work holds a channel of jobs per QueryPriority, so the shards of
+	// higher priority queries can be worked on first.
+	work [numQueryPriorities]chan job
 
 	// track active mapperLocal tasks so we can ensure we don't close
 	// e.work while they're still waiting to send
@@ -80,6 +103,9 @@ type executor struct {
 	// Temporary flag to be removed when stablized
 	dataframeEnabled   bool
 	datafameUseParquet bool
+
+	// cipher, if set, encrypts dataframe files.
+	cipher *encryption.Cipher
 }
 
 // executorOption is a functional option type for pilosa.executor
@@ -140,7 +166,9 @@ func newExecutor(opts ...executorOption) *executor {
 	// workerPoolSize... any larger doesn't seem to have an effect in
 	// the few tests we've done at scale with concurrent query
 	// workloads. Possible that it could be smaller.
-	e.work = make(chan job, e.workerPoolSize)
+	for i := range e.work {
+		e.work[i] = make(chan job, e.workerPoolSize)
+	}
 	_ = testhook.Opened(NewAuditor(), e, nil)
 	e.workers = task.NewPool(e.workerPoolSize, e.doOneJob, e)
 	return e
@@ -158,7 +186,9 @@ func (e *executor) Close() error {
 	for atomic.LoadUint64(&e.activeMappers) > 0 {
 		time.Sleep(1 * time.Millisecond)
 	}
-	close(e.work)
+	for _, work := range e.work {
+		close(work)
+	}
 	e.workers.Close()
 	return nil
 }
@@ -179,6 +209,45 @@ func (e *executor) InitStats() {
 	}
 }
 
+// Shards returns the shards holding data for the table, in ascending order.
+func (e *executor) Shards(ctx context.Context, tableKeyer dax.TableKeyer) ([]uint64, error) {
+	index := string(tableKeyer.Key())
+	idx := e.Holder.Index(index)
+	if idx == nil {
+		return nil, newNotFoundError(ErrIndexNotFound, index)
+	}
+	return idx.AvailableShards(includeRemote).Slice(), nil
+}
+
+// ShardVersions returns the version of each shard holding data for a table.
+// Each shard's version is read from the node which would serve a query of
+// the shard.
+func (e *executor) ShardVersions(ctx context.Context, tableKeyer dax.TableKeyer) (map[uint64]uint64, error) {
+	resp, err := e.Execute(ctx, tableKeyer, &pql.Query{Calls: []*pql.Call{{Name: "ShardVersions"}}}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Results) != 1 {
+		return nil, errors.Errorf("unexpected number of results: %d", len(resp.Results))
+	}
+	pairs, _ := resp.Results[0].([]Pair)
+	versions := make(map[uint64]uint64, len(pairs))
+	for _, p := range pairs {
+		versions[p.ID] = p.Count
+	}
+	return versions, nil
+}
+
+// checkSQLTransactions returns an error if the queries of a sql transaction
+// can't run on this cluster. A transaction holds back its writes in a qcx on
+// the node it runs on, so the shards it writes all have to be on that node.
+func (e *executor) checkSQLTransactions() error {
+	if e.Cluster != nil && len(e.Cluster.Nodes()) > 1 {
+		return ErrSQLTransactionsUnsupported
+	}
+	return nil
+}
+
 // Execute executes a PQL query.
 func (e *executor) Execute(ctx context.Context, tableKeyer dax.TableKeyer, q *pql.Query, shards []uint64, opt *ExecOptions) (QueryResponse, error) {
 	index := string(tableKeyer.Key())
@@ -219,9 +288,15 @@ func (e *executor) Execute(ctx context.Context, tableKeyer dax.TableKeyer, q *pq
 	if opt == nil {
 		opt = &ExecOptions{}
 	}
-	// Default maximum memory, if not passed in.
-	if opt.MaxMemory == 0 && q.HasCall("Extract") {
-		opt.MaxMemory = e.maxMemory
+	// Default maximum memory, if not passed in: the max query memory of the
+	// query's resource pool, or for Extract() the node-wide maximum. Either
+	// limits the memory used by the query's results.
+	if opt.MaxMemory == 0 {
+		if pool := resourcePoolFromContext(ctx); pool != nil && pool.MaxQueryMemory > 0 {
+			opt.MaxMemory = pool.MaxQueryMemory
+		} else if q.HasCall("Extract") {
+			opt.MaxMemory = e.maxMemory
+		}
 	}
 
 	if opt.Profile {
@@ -237,13 +312,27 @@ func (e *executor) Execute(ctx context.Context, tableKeyer dax.TableKeyer, q *pq
 
 	// Can't do NewTx() this high up, because we need a specific shard.
 	// So start a qcx with a TxGroup and pass it down.
+	//
+	// A query which is part of a sql transaction runs in the transaction's
+	// qcx instead, which is committed or aborted when the transaction ends.
 	var qcx *Qcx
-	if needWriteTxn {
+	txn := sqlTransactionFromContext(ctx)
+	if txn != nil {
+		if err := e.checkSQLTransactions(); err != nil {
+			return resp, err
+		}
+		var err error
+		if qcx, err = txn.sessionQcx(idx.holder.txf); err != nil {
+			return resp, errors.Wrap(err, "getting transaction qcx")
+		}
+	} else if needWriteTxn {
 		qcx = idx.holder.txf.NewWritableQcx()
 	} else {
 		qcx = idx.holder.txf.NewQcx()
 	}
-	defer qcx.Abort()
+	if txn == nil {
+		defer qcx.Abort()
+	}
 
 	results, err := e.execute(ctx, qcx, index, q, shards, opt)
 	if err != nil {
@@ -258,15 +347,14 @@ func (e *executor) Execute(ctx context.Context, tableKeyer dax.TableKeyer, q *pq
 	if !opt.Remote {
 		// only translateResults if this local node is the final destination. only string/column keys.
 		if err := e.translateResults(ctx, index, idx, q.Calls, results, opt.MaxMemory); err != nil {
-			if errors.Cause(err) == ErrTranslatingKeyNotFound {
-				// No error - return empty result
-				resp.Results = make([]interface{}, len(q.Calls))
-				for i, c := range q.Calls {
-					resp.Results[i] = emptyResult(c)
-				}
-				return resp, nil
+			if errors.Cause(err) != ErrTranslatingKeyNotFound {
+				return resp, err
+			}
+			// No error - return empty result
+			resp.Results = make([]interface{}, len(q.Calls))
+			for i, c := range q.Calls {
+				resp.Results[i] = emptyResult(c)
 			}
-			return resp, err
 		} else if err := validateQueryContext(ctx); err != nil {
 			return resp, err
 		}
@@ -275,7 +363,7 @@ func (e *executor) Execute(ctx context.Context, tableKeyer dax.TableKeyer, q *pq
 	respSafeNoTxData := safeCopy(resp)
 
 	// Commit transactions if writing; else let the defer grp.Abort do the rollbacks.
-	if needWriteTxn {
+	if needWriteTxn && txn == nil {
 		if err := qcx.Finish(); err != nil {
 			return respSafeNoTxData, err
 		}
@@ -305,6 +393,9 @@ func safeCopy(resp QueryResponse) (out QueryResponse) {
 		case *PairsField:
 			// no bitmap material, so should be ok to skip Clone()
 			out.Results = append(out.Results, x)
+		case []Pair:
+			// no bitmap material, so should be ok to skip Clone()
+			out.Results = append(out.Results, x)
 		case PairField: // not PairsField but PairField
 			// no bitmap material, so should be ok to skip Clone()
 			out.Results = append(out.Results, x)
@@ -352,6 +443,14 @@ func safeCopy(resp QueryResponse) (out QueryResponse) {
 			out.Results = append(out.Results, x)
 		case ExtractedIDMatrixSorted:
 			out.Results = append(out.Results, x)
+		case *hll.Sketch:
+			// registers are built on the heap, never in Tx memory
+			out.Results = append(out.Results, x)
+		case *FunnelResult:
+			out.Results = append(out.Results, &FunnelResult{Counts: x.Counts, Row: x.Row.Clone()})
+		case *SimilarResult:
+			// no bitmap material, so should be ok to skip Clone()
+			out.Results = append(out.Results, x)
 		default:
 			panic(fmt.Sprintf("handle %T here", v))
 		}
@@ -752,6 +851,14 @@ func (e *executor) executeCall(ctx context.Context, qcx *Qcx, index string, c *p
 		statFn(CounterQueryDistinctTotal)
 		res, err := e.executeDistinct(ctx, qcx, index, c, shards, opt)
 		return res, errors.Wrap(err, "executeDistinct")
+	case "ApproxCountDistinct":
+		statFn(CounterQueryApproxCountDistinctTotal)
+		res, err := e.executeApproxCountDistinct(ctx, qcx, index, c, shards, opt)
+		return res, errors.Wrap(err, "executeApproxCountDistinct")
+	case "Funnel":
+		statFn(CounterQueryFunnelTotal)
+		res, err := e.executeFunnel(ctx, qcx, index, c, shards, opt)
+		return res, errors.Wrap(err, "executeFunnel")
 	case "Store":
 		statFn(CounterQueryStoreTotal)
 		res, err := e.executeSetRow(ctx, qcx, index, c, shards, opt)
@@ -812,6 +919,18 @@ func (e *executor) executeCall(ctx context.Context, qcx *Qcx, index string, c *p
 		statFn(CounterQueryConstRowTotal)
 		res, err := e.executeConstRow(ctx, qcx, index, c, opt)
 		return res, errors.Wrap(err, "executeConstRow")
+	case "Similar":
+		statFn(CounterQuerySimilarTotal)
+		res, err := e.executeSimilar(ctx, qcx, index, c, shards, opt)
+		return res, errors.Wrap(err, "executeSimilar")
+	case "Sample":
+		statFn(CounterQuerySampleTotal)
+		res, err := e.executeSample(ctx, qcx, index, c, shards, opt)
+		return res, errors.Wrap(err, "executeSample")
+	case "ShardVersions":
+		statFn(CounterQueryShardVersionsTotal)
+		res, err := e.executeShardVersions(ctx, qcx, index, c, shards, opt)
+		return res, errors.Wrap(err, "executeShardVersions")
 	case "Limit":
 		statFn(CounterQueryLimitTotal)
 		res, err := e.executeLimitCall(ctx, qcx, index, c, shards, opt)
@@ -1099,6 +1218,211 @@ func (e *executor) executeLimitCall(ctx context.Context, qcx *Qcx, index string,
 	return result, nil
 }
 
+// SampleOptions describe how Sample() selects columns. Exactly one of N and
+// Fraction is set. Each column is scored with a hash seeded by Seed, so the
+// same seed always selects the same columns.
+type SampleOptions struct {
+	N        uint64
+	Fraction float64
+	Seed     uint64
+}
+
+// ParseSampleOptions reads the n, fraction and seed arguments of a Sample()
+// call.
+func ParseSampleOptions(c *pql.Call) (SampleOptions, error) {
+	var opts SampleOptions
+	n, hasN, err := c.UintArg("n")
+	if err != nil {
+		return opts, errors.Wrap(err, "Sample(): reading n")
+	}
+	var hasFraction bool
+	if v, ok := c.Args["fraction"]; ok {
+		hasFraction = true
+		switch v := v.(type) {
+		case pql.Decimal:
+			opts.Fraction = v.Float64()
+		case float64:
+			opts.Fraction = v
+		case int64:
+			opts.Fraction = float64(v)
+		default:
+			return opts, errors.Errorf("Sample(): fraction must be a number, got %T", v)
+		}
+		if opts.Fraction <= 0 || opts.Fraction > 1 {
+			return opts, errors.Errorf("Sample(): fraction must be in (0, 1], got %v", opts.Fraction)
+		}
+	}
+	if hasN == hasFraction {
+		return opts, errors.New("Sample(): exactly one of n or fraction is required")
+	}
+	opts.N = n
+	seed, _, err := c.IntArg("seed")
+	if err != nil {
+		return opts, errors.Wrap(err, "Sample(): reading seed")
+	}
+	opts.Seed = uint64(seed)
+	return opts, nil
+}
+
+// sampleHash scores a column for sampling. It is the splitmix64 finalizer
+// applied to the column mixed with the seed.
+func sampleHash(seed, col uint64) uint64 {
+	z := col*0x9e3779b97f4a7c15 + seed
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Apply returns the columns of row selected by the sample. For a fraction,
+// every column whose score falls below the fraction of the hash space is
+// kept, so sampling shards separately and merging gives the same result as
+// sampling the merged row. For n, the n columns with the lowest scores are
+// kept, which is a uniform sample that can also be applied per shard and
+// then again to the merged per-shard samples.
+func (s SampleOptions) Apply(row *Row) *Row {
+	if row == nil {
+		return NewRow()
+	}
+	if s.N == 0 {
+		if s.Fraction >= 1 {
+			return row
+		}
+		threshold := uint64(s.Fraction * math.MaxUint64)
+		var cols []uint64
+		for _, col := range row.Columns() {
+			if sampleHash(s.Seed, col) < threshold {
+				cols = append(cols, col)
+			}
+		}
+		return NewRow(cols...)
+	}
+	if row.Count() <= s.N {
+		return row
+	}
+	h := make(sampleHeap, 0, s.N)
+	for _, col := range row.Columns() {
+		score := sampleHash(s.Seed, col)
+		if uint64(len(h)) < s.N {
+			heap.Push(&h, sampleScore{col: col, score: score})
+		} else if score < h[0].score {
+			h[0] = sampleScore{col: col, score: score}
+			heap.Fix(&h, 0)
+		}
+	}
+	cols := make([]uint64, len(h))
+	for i := range h {
+		cols[i] = h[i].col
+	}
+	sort.Slice(cols, func(i, j int) bool { return cols[i] < cols[j] })
+	return NewRow(cols...)
+}
+
+type sampleScore struct {
+	col   uint64
+	score uint64
+}
+
+// sampleHeap is a max-heap on score holding the lowest scores seen so far.
+type sampleHeap []sampleScore
+
+func (h sampleHeap) Len() int            { return len(h) }
+func (h sampleHeap) Less(i, j int) bool  { return h[i].score > h[j].score }
+func (h sampleHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *sampleHeap) Push(x interface{}) { *h = append(*h, x.(sampleScore)) }
+func (h *sampleHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// executeSample executes a Sample() call, returning a random subset of the
+// columns of its bitmap argument.
+func (e *executor) executeSample(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shards []uint64, opt *ExecOptions) (*Row, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeSample")
+	defer span.Finish()
+
+	if len(c.Children) != 1 {
+		return nil, errors.New("Sample() requires a single bitmap argument")
+	}
+	// Pick the seed here so every node samples with the same one.
+	if _, ok := c.Args["seed"]; !ok {
+		if c.Args == nil {
+			c.Args = make(map[string]interface{})
+		}
+		c.Args["seed"] = rand.Int63()
+	}
+	sample, err := ParseSampleOptions(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute calls in bulk on each remote node and merge.
+	mapFn := func(ctx context.Context, shard uint64, mopt *mapOptions) (_ interface{}, err error) {
+		row, err := e.executeBitmapCallShard(ctx, qcx, index, c.Children[0], shard)
+		if err != nil {
+			return nil, err
+		}
+		return sample.Apply(row), nil
+	}
+
+	// Merge returned results at coordinating node. A sample of n columns
+	// has to be cut back down to the n lowest scores of the union.
+	reduceFn := func(ctx context.Context, prev, v interface{}) interface{} {
+		other, _ := prev.(*Row)
+		if other == nil {
+			other = NewRow()
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		other.Merge(v.(*Row))
+		if sample.N > 0 {
+			return sample.Apply(other)
+		}
+		return other
+	}
+
+	result, err := e.mapReduce(ctx, index, shards, c, opt, mapFn, reduceFn)
+	if err != nil {
+		return nil, errors.Wrap(err, "mapReduce")
+	}
+	row, _ := result.(*Row)
+	if row == nil {
+		row = NewRow()
+	}
+	return row, nil
+}
+
+// executeShardVersions returns a pair for each shard, holding the shard in ID
+// and its version in Count.
+func (e *executor) executeShardVersions(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shards []uint64, opt *ExecOptions) ([]Pair, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeShardVersions")
+	defer span.Finish()
+
+	idx := e.Holder.Index(index)
+	if idx == nil {
+		return nil, newNotFoundError(ErrIndexNotFound, index)
+	}
+
+	mapFn := func(ctx context.Context, shard uint64, mopt *mapOptions) (_ interface{}, err error) {
+		return []Pair{{ID: shard, Count: idx.ShardVersion(shard)}}, nil
+	}
+
+	reduceFn := func(ctx context.Context, prev, v interface{}) interface{} {
+		other, _ := prev.([]Pair)
+		return append(other, v.([]Pair)...)
+	}
+
+	result, err := e.mapReduce(ctx, index, shards, c, opt, mapFn, reduceFn)
+	if err != nil {
+		return nil, errors.Wrap(err, "mapReduce")
+	}
+	pairs, _ := result.([]Pair)
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].ID < pairs[j].ID })
+	return pairs, nil
+}
+
 // executeIncludesColumnCallShard
 func (e *executor) executeIncludesColumnCallShard(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shard uint64, column uint64) (_ bool, err error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeIncludesColumnCallShard")
@@ -1164,26 +1488,329 @@ func (e *executor) executeSum(ctx context.Context, qcx *Qcx, index string, c *pq
 			other.Val = 0
 		}
 	}
-
-	return other, nil
-}
-
-// executeDistinct executes a Distinct call on a field. It returns a
-// SignedRow for int fields and a *Row for set/mutex/time fields.
-func (e *executor) executeDistinct(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shards []uint64, opt *ExecOptions) (interface{}, error) {
-	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeDistinct")
-	defer span.Finish()
-
-	field, hasField, err := c.StringArg("field")
+
+	return other, nil
+}
+
+// executeDistinct executes a Distinct call on a field. It returns a
+// SignedRow for int fields and a *Row for set/mutex/time fields.
+func (e *executor) executeDistinct(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shards []uint64, opt *ExecOptions) (interface{}, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeDistinct")
+	defer span.Finish()
+
+	field, hasField, err := c.StringArg("field")
+	if err != nil {
+		return SignedRow{}, errors.Wrap(err, "loading field option in Distinct query")
+	} else if !hasField {
+		return SignedRow{}, fmt.Errorf("missing field option in Distinct query")
+	}
+
+	// Execute calls in bulk on each remote node and merge.
+	mapFn := func(ctx context.Context, shard uint64, mopt *mapOptions) (_ interface{}, err error) {
+		return e.executeDistinctShard(ctx, qcx, index, field, c, shard)
+	}
+
+	// Merge returned results at coordinating node.
+	reduceFn := func(ctx context.Context, prev, v interface{}) interface{} {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		switch other := prev.(type) {
+		case SignedRow:
+			return other.Union(v.(SignedRow))
+		case *Row:
+			if other == nil {
+				return v
+			} else if v.(*Row) == nil {
+				return other
+			}
+			return other.Union(v.(*Row))
+		case nil:
+			return v
+		case DistinctTimestamp:
+			return other.Union(v.(DistinctTimestamp))
+		default:
+			return errors.Errorf("unexpected return type from executeDistinctShard: %+v %T", other, other)
+		}
+	}
+
+	result, err := e.mapReduce(ctx, index, shards, c, opt, mapFn, reduceFn)
+	if err != nil {
+		return nil, errors.Wrap(err, "mapReduce")
+	}
+
+	if other, ok := result.(SignedRow); ok {
+		other.Field = field
+	}
+	return result, nil
+}
+
+// executeApproxCountDistinct executes an ApproxCountDistinct() call,
+// estimating the number of distinct values in a field with a HyperLogLog
+// sketch built on each shard and merged here. It returns the estimate, or
+// the merged sketch itself when executing on behalf of another node.
+func (e *executor) executeApproxCountDistinct(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shards []uint64, opt *ExecOptions) (interface{}, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeApproxCountDistinct")
+	defer span.Finish()
+
+	field, err := c.FirstStringArg("field", "_field")
+	if err != nil {
+		return nil, errors.Wrap(err, "ApproxCountDistinct(): field required")
+	}
+	if len(c.Children) > 1 {
+		return nil, errors.New("ApproxCountDistinct() only accepts a single bitmap input")
+	}
+	precision, err := approxCountDistinctPrecision(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute calls in bulk on each remote node and merge.
+	mapFn := func(ctx context.Context, shard uint64, mopt *mapOptions) (_ interface{}, err error) {
+		return e.executeApproxCountDistinctShard(ctx, qcx, index, field, c, shard, precision)
+	}
+
+	// Merge returned results at coordinating node.
+	reduceFn := func(ctx context.Context, prev, v interface{}) interface{} {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return reduceDistinctSketch(prev, v)
+	}
+
+	result, err := e.mapReduce(ctx, index, shards, c, opt, mapFn, reduceFn)
+	if err != nil {
+		return nil, errors.Wrap(err, "mapReduce")
+	}
+	sketch, _ := result.(*hll.Sketch)
+	if opt.Remote {
+		if sketch == nil {
+			return hll.New(precision)
+		}
+		return sketch, nil
+	}
+	if sketch == nil {
+		return uint64(0), nil
+	}
+	return sketch.Count(), nil
+}
+
+// approxCountDistinctPrecision returns the sketch precision requested by an
+// ApproxCountDistinct() call.
+func approxCountDistinctPrecision(c *pql.Call) (int, error) {
+	precision, ok, err := c.IntArg("precision")
+	if err != nil {
+		return 0, errors.Wrap(err, "ApproxCountDistinct(): reading precision")
+	} else if !ok {
+		return hll.DefaultPrecision, nil
+	}
+	if precision < hll.MinPrecision || precision > hll.MaxPrecision {
+		return 0, errors.Errorf("ApproxCountDistinct(): precision must be between %d and %d", hll.MinPrecision, hll.MaxPrecision)
+	}
+	return int(precision), nil
+}
+
+// reduceDistinctSketch merges the sketch v into prev.
+func reduceDistinctSketch(prev, v interface{}) interface{} {
+	switch other := v.(type) {
+	case *hll.Sketch:
+		if prev == nil {
+			return other
+		}
+		sketch, ok := prev.(*hll.Sketch)
+		if !ok {
+			return errors.Errorf("unexpected return type from executeApproxCountDistinctShard: %T", prev)
+		}
+		if err := sketch.Merge(other); err != nil {
+			return err
+		}
+		return sketch
+	case error:
+		return other
+	case nil:
+		return prev
+	default:
+		return errors.Errorf("unexpected return type from executeApproxCountDistinctShard: %T", v)
+	}
+}
+
+// executeApproxCountDistinctShard builds a sketch of the distinct values of a
+// field in a single shard.
+func (e *executor) executeApproxCountDistinctShard(ctx context.Context, qcx *Qcx, index string, fieldName string, c *pql.Call, shard uint64, precision int) (*hll.Sketch, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeApproxCountDistinctShard")
+	defer span.Finish()
+
+	sketch, err := hll.New(precision)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := e.executeDistinctShard(ctx, qcx, index, fieldName, c, shard)
+	if err != nil {
+		return nil, err
+	}
+	switch r := result.(type) {
+	case *Row:
+		// row ids, which for keyed fields stand for the same key on every
+		// shard
+		if r != nil {
+			for _, id := range r.Columns() {
+				sketch.AddUint64(id)
+			}
+		}
+	case SignedRow:
+		if r.Pos != nil {
+			for _, v := range r.Pos.Columns() {
+				sketch.AddInt64(int64(v))
+			}
+		}
+		if r.Neg != nil {
+			for _, v := range r.Neg.Columns() {
+				sketch.AddInt64(-int64(v))
+			}
+		}
+	case DistinctTimestamp:
+		for _, v := range r.Values {
+			sketch.AddString(v)
+		}
+	default:
+		return nil, errors.Errorf("unexpected return type from executeDistinctShard: %T", result)
+	}
+	return sketch, nil
+}
+
+// FunnelResult is the result of a Funnel() call: the number of columns which
+// reached each step of the funnel, and the columns which completed every
+// step.
+type FunnelResult struct {
+	Counts []uint64 `json:"counts"`
+	Row    *Row     `json:"row"`
+}
+
+// Merge adds the counts and columns of other, which must be from different
+// shards, to r.
+func (r *FunnelResult) Merge(other *FunnelResult) error {
+	if r.Counts == nil {
+		r.Counts, r.Row = other.Counts, other.Row
+		return nil
+	}
+	if len(r.Counts) != len(other.Counts) {
+		return errors.Errorf("merging funnels with %d and %d steps", len(r.Counts), len(other.Counts))
+	}
+	for i, n := range other.Counts {
+		r.Counts[i] += n
+	}
+	r.Row = r.Row.Union(other.Row)
+	return nil
+}
+
+// ToRows implements the ToRowser interface, returning a row per step,
+// numbered from 1.
+func (r *FunnelResult) ToRows(callback func(*proto.RowResponse) error) error {
+	ci := []*proto.ColumnInfo{
+		{Name: "step", Datatype: "uint64"},
+		{Name: "count", Datatype: "uint64"},
+	}
+	for i, n := range r.Counts {
+		if err := callback(&proto.RowResponse{
+			Headers: ci,
+			Columns: []*proto.ColumnResponse{
+				{ColumnVal: &proto.ColumnResponse_Uint64Val{Uint64Val: uint64(i + 1)}},
+				{ColumnVal: &proto.ColumnResponse_Uint64Val{Uint64Val: n}},
+			},
+		}); err != nil {
+			return errors.Wrap(err, "calling callback")
+		}
+		ci = nil
+	}
+	return nil
+}
+
+// MarshalBinary encodes the result for sending between nodes. Only column
+// IDs are encoded, since results are translated to keys on the coordinating
+// node.
+func (r *FunnelResult) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	buf := make([]byte, binary.MaxVarintLen64)
+	b.Write(buf[:binary.PutUvarint(buf, uint64(len(r.Counts)))])
+	for _, n := range r.Counts {
+		b.Write(buf[:binary.PutUvarint(buf, n)])
+	}
+	if _, err := roaring.NewBitmap(r.Row.Columns()...).WriteTo(&b); err != nil {
+		return nil, errors.Wrap(err, "encoding funnel columns")
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a result encoded by MarshalBinary.
+func (r *FunnelResult) UnmarshalBinary(data []byte) error {
+	steps, n := binary.Uvarint(data)
+	if n <= 0 || steps > uint64(len(data)) {
+		return errors.New("invalid funnel step count")
+	}
+	data = data[n:]
+	r.Counts = make([]uint64, steps)
+	for i := range r.Counts {
+		if r.Counts[i], n = binary.Uvarint(data); n <= 0 {
+			return errors.New("invalid funnel count")
+		}
+		data = data[n:]
+	}
+	bm := roaring.NewBitmap()
+	if err := bm.UnmarshalBinary(data); err != nil {
+		return errors.Wrap(err, "decoding funnel columns")
+	}
+	r.Row = NewRow(bm.Slice()...)
+	return nil
+}
+
+// executeFunnel executes a Funnel() call, which counts the columns passing
+// through a sequence of steps, each a Row() of the same time field. A
+// column reaches a step if it has every step up to it set within the
+// window, which begins at the time view in which the first step is set.
+// If ordered is true (the default), each step must be set in the same time
+// view as the step before or a later one.
+func (e *executor) executeFunnel(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shards []uint64, opt *ExecOptions) (*FunnelResult, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeFunnel")
+	defer span.Finish()
+
+	idx := e.Holder.Index(index)
+	if idx == nil {
+		return nil, newNotFoundError(ErrIndexNotFound, index)
+	}
+	f, rowIDs, err := funnelSteps(idx, c)
+	if err != nil {
+		return nil, err
+	}
+	window, err := funnelWindow(c)
+	if err != nil {
+		return nil, err
+	}
+	ordered, ok, err := c.BoolArg("ordered")
+	if err != nil {
+		return nil, errors.Wrap(err, "Funnel(): reading ordered")
+	} else if !ok {
+		ordered = true
+	}
+	var from, to time.Time
+	if v, ok := c.Args["from"]; ok {
+		if from, err = parseTime(v); err != nil {
+			return nil, errors.Wrap(err, "parsing from time")
+		}
+	}
+	if v, ok := c.Args["to"]; ok {
+		if to, err = parseTime(v); err != nil {
+			return nil, errors.Wrap(err, "parsing to time")
+		}
+	}
+	views, starts, err := f.finestTimeViews(from, to)
 	if err != nil {
-		return SignedRow{}, errors.Wrap(err, "loading field option in Distinct query")
-	} else if !hasField {
-		return SignedRow{}, fmt.Errorf("missing field option in Distinct query")
+		return nil, err
 	}
 
 	// Execute calls in bulk on each remote node and merge.
 	mapFn := func(ctx context.Context, shard uint64, mopt *mapOptions) (_ interface{}, err error) {
-		return e.executeDistinctShard(ctx, qcx, index, field, c, shard)
+		return e.executeFunnelShard(ctx, qcx, idx, f, rowIDs, views, starts, window, ordered, shard)
 	}
 
 	// Merge returned results at coordinating node.
@@ -1191,22 +1818,25 @@ func (e *executor) executeDistinct(ctx context.Context, qcx *Qcx, index string,
 		if err := ctx.Err(); err != nil {
 			return err
 		}
-		switch other := prev.(type) {
-		case SignedRow:
-			return other.Union(v.(SignedRow))
-		case *Row:
-			if other == nil {
-				return v
-			} else if v.(*Row) == nil {
+		switch other := v.(type) {
+		case *FunnelResult:
+			if prev == nil {
 				return other
 			}
-			return other.Union(v.(*Row))
+			result, ok := prev.(*FunnelResult)
+			if !ok {
+				return errors.Errorf("unexpected return type from executeFunnelShard: %T", prev)
+			}
+			if err := result.Merge(other); err != nil {
+				return err
+			}
+			return result
+		case error:
+			return other
 		case nil:
-			return v
-		case DistinctTimestamp:
-			return other.Union(v.(DistinctTimestamp))
+			return prev
 		default:
-			return errors.Errorf("unexpected return type from executeDistinctShard: %+v %T", other, other)
+			return errors.Errorf("unexpected return type from executeFunnelShard: %T", v)
 		}
 	}
 
@@ -1214,9 +1844,159 @@ func (e *executor) executeDistinct(ctx context.Context, qcx *Qcx, index string,
 	if err != nil {
 		return nil, errors.Wrap(err, "mapReduce")
 	}
+	funnel, _ := result.(*FunnelResult)
+	if funnel == nil {
+		funnel = &FunnelResult{Counts: make([]uint64, len(rowIDs)), Row: NewRow()}
+	}
+	return funnel, nil
+}
 
-	if other, ok := result.(SignedRow); ok {
-		other.Field = field
+// funnelSteps returns the time field and row IDs of the steps of a Funnel()
+// call, which must all be Row() calls on the same time field.
+func funnelSteps(idx *Index, c *pql.Call) (*Field, []uint64, error) {
+	if len(c.Children) == 0 {
+		return nil, nil, errors.New("Funnel() requires at least one step")
+	}
+	var f *Field
+	rowIDs := make([]uint64, len(c.Children))
+	for i, child := range c.Children {
+		if child.Name != "Row" {
+			return nil, nil, errors.Errorf("Funnel() steps must be Row() calls, got %s()", child.Name)
+		}
+		if _, ok := child.Args["from"]; ok {
+			return nil, nil, errors.New("Funnel() steps can't have a time range, use Funnel(from=, to=) instead")
+		}
+		if _, ok := child.Args["to"]; ok {
+			return nil, nil, errors.New("Funnel() steps can't have a time range, use Funnel(from=, to=) instead")
+		}
+		fieldName, err := child.FieldArg()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Funnel() step")
+		}
+		if f == nil {
+			if f = idx.Field(fieldName); f == nil {
+				return nil, nil, newNotFoundError(ErrFieldNotFound, fieldName)
+			}
+			if f.TimeQuantum() == "" {
+				return nil, nil, errors.Errorf("Funnel() field %s is not a time field", fieldName)
+			}
+		} else if fieldName != f.Name() {
+			return nil, nil, errors.Errorf("Funnel() steps must all be on the same field, got %s and %s", f.Name(), fieldName)
+		}
+		isNull, rowID, isEQ, err := child.FieldEquality(fieldName)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Funnel() step")
+		} else if isNull || !isEQ {
+			return nil, nil, errors.New("Funnel() steps must be a single row of the field")
+		}
+		rowIDs[i] = rowID
+	}
+	return f, rowIDs, nil
+}
+
+// funnelWindow returns the window of a Funnel() call, given as a duration
+// such as "36h", or a number of days such as "7d". A zero window is
+// unlimited.
+func funnelWindow(c *pql.Call) (time.Duration, error) {
+	v, ok := c.Args["window"]
+	if !ok {
+		return 0, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return 0, errors.Errorf("Funnel(): window must be a duration string, got %v of %T", v, v)
+	}
+	var window time.Duration
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		n, err := strconv.ParseUint(days, 10, 32)
+		if err != nil {
+			return 0, errors.Errorf("Funnel(): invalid window %q", s)
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if window, err = time.ParseDuration(s); err != nil {
+			return 0, errors.Errorf("Funnel(): invalid window %q", s)
+		}
+	}
+	if window < 0 {
+		return 0, errors.Errorf("Funnel(): window must not be negative")
+	}
+	return window, nil
+}
+
+// executeFunnelShard walks a funnel through the given time views, which
+// begin at the given times, in a single shard.
+func (e *executor) executeFunnelShard(ctx context.Context, qcx *Qcx, idx *Index, f *Field, rowIDs []uint64, views []string, starts []time.Time, window time.Duration, ordered bool, shard uint64) (_ *FunnelResult, err0 error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeFunnelShard")
+	defer span.Finish()
+
+	tx, finisher, err := qcx.GetTx(Txo{Write: !writable, Index: idx, Shard: shard})
+	if err != nil {
+		return nil, err
+	}
+	defer finisher(&err0)
+
+	// steps[i][j] is the row of step i in view j.
+	steps := make([][]*Row, len(rowIDs))
+	for i, rowID := range rowIDs {
+		steps[i] = make([]*Row, len(views))
+		for j, view := range views {
+			frag := e.Holder.fragment(idx.Name(), f.Name(), view, shard)
+			if frag == nil {
+				steps[i][j] = NewRow()
+				continue
+			}
+			if steps[i][j], err = frag.row(tx, rowID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	reached := make([]*Row, len(rowIDs))
+	for i := range reached {
+		reached[i] = NewRow()
+	}
+	cur := make([]*Row, len(rowIDs))
+	for start := range views {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := start + 1
+		for end < len(views) && (window == 0 || starts[end].Before(starts[start].Add(window))) {
+			end++
+		}
+		if ordered {
+			// Each step is reached in the first view in which it's set
+			// after the previous step was reached.
+			cur[0] = steps[0][start]
+			if !cur[0].Any() {
+				continue
+			}
+			for i := 1; i < len(cur); i++ {
+				cur[i] = NewRow()
+			}
+			for j := start; j < end; j++ {
+				for i := 1; i < len(cur); i++ {
+					cur[i] = cur[i].Union(cur[i-1].Intersect(steps[i][j]))
+				}
+			}
+		} else {
+			for i := range cur {
+				cur[i] = steps[i][start].Union(steps[i][start+1 : end]...)
+				if i > 0 {
+					cur[i] = cur[i].Intersect(cur[i-1])
+				}
+			}
+		}
+		for i := range reached {
+			reached[i] = reached[i].Union(cur[i])
+		}
+	}
+
+	result := &FunnelResult{Counts: make([]uint64, len(rowIDs)), Row: reached[len(reached)-1]}
+	for i, row := range reached {
+		result.Counts[i] = row.Count()
 	}
 	return result, nil
 }
@@ -1810,6 +2590,8 @@ func (e *executor) executeBitmapCallShard(ctx context.Context, qcx *Qcx, index s
 		return nil, errors.New("Distinct shouldn't be hit as a bitmap call")
 	case "Precomputed":
 		return e.executePrecomputedCallShard(ctx, qcx, index, c, shard)
+	case "Within", "Near":
+		return e.executeGeoShard(ctx, qcx, index, c, shard)
 	default:
 		return nil, fmt.Errorf("unknown call: %s", c.Name)
 	}
@@ -2941,9 +3723,383 @@ func (e *executor) executeTopNShard(ctx context.Context, qcx *Qcx, index string,
 		return nil, errors.Wrap(err, "getting top")
 	}
 
-	return &PairsField{
-		Pairs: pairs,
-	}, nil
+	return &PairsField{
+		Pairs: pairs,
+	}, nil
+}
+
+// SimilarRow is a row of a Similar() result: the number of columns it shares
+// with the target row, its own number of columns, and its similarity score.
+type SimilarRow struct {
+	ID           uint64  `json:"id"`
+	Key          string  `json:"key,omitempty"`
+	Score        float64 `json:"score"`
+	Intersection uint64  `json:"intersection"`
+	Count        uint64  `json:"count"`
+}
+
+// SimilarResult is the result of a Similar() call: the rows of a field most
+// similar to a target row, most similar first. Count is the number of
+// columns in the target row.
+type SimilarResult struct {
+	Field string       `json:"field"`
+	Count uint64       `json:"count"`
+	Rows  []SimilarRow `json:"rows"`
+}
+
+// Merge adds the counts of other, which must be from different shards, to
+// r. The rows of both results must be sorted by ID, as they are before
+// scoring.
+func (r *SimilarResult) Merge(other *SimilarResult) {
+	r.Count += other.Count
+	rows := make([]SimilarRow, 0, len(r.Rows)+len(other.Rows))
+	i, j := 0, 0
+	for i < len(r.Rows) && j < len(other.Rows) {
+		switch a, b := r.Rows[i], other.Rows[j]; {
+		case a.ID < b.ID:
+			rows = append(rows, a)
+			i++
+		case a.ID > b.ID:
+			rows = append(rows, b)
+			j++
+		default:
+			a.Intersection += b.Intersection
+			a.Count += b.Count
+			rows = append(rows, a)
+			i++
+			j++
+		}
+	}
+	rows = append(rows, r.Rows[i:]...)
+	r.Rows = append(rows, other.Rows[j:]...)
+}
+
+// ToRows implements the ToRowser interface.
+func (r *SimilarResult) ToRows(callback func(*proto.RowResponse) error) error {
+	dtype := "uint64"
+	if len(r.Rows) > 0 && r.Rows[0].Key != "" {
+		dtype = "string"
+	}
+	ci := []*proto.ColumnInfo{
+		{Name: r.Field, Datatype: dtype},
+		{Name: "score", Datatype: "float64"},
+		{Name: "intersection", Datatype: "uint64"},
+		{Name: "count", Datatype: "uint64"},
+	}
+	for _, row := range r.Rows {
+		id := &proto.ColumnResponse{ColumnVal: &proto.ColumnResponse_Uint64Val{Uint64Val: row.ID}}
+		if dtype == "string" {
+			id = &proto.ColumnResponse{ColumnVal: &proto.ColumnResponse_StringVal{StringVal: row.Key}}
+		}
+		if err := callback(&proto.RowResponse{
+			Headers: ci,
+			Columns: []*proto.ColumnResponse{
+				id,
+				{ColumnVal: &proto.ColumnResponse_Float64Val{Float64Val: row.Score}},
+				{ColumnVal: &proto.ColumnResponse_Uint64Val{Uint64Val: row.Intersection}},
+				{ColumnVal: &proto.ColumnResponse_Uint64Val{Uint64Val: row.Count}},
+			},
+		}); err != nil {
+			return errors.Wrap(err, "calling callback")
+		}
+		ci = nil
+	}
+	return nil
+}
+
+// MarshalBinary encodes the result for sending between nodes. Only the
+// counts are encoded, since results are scored and translated to keys on
+// the coordinating node.
+func (r *SimilarResult) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	buf := make([]byte, binary.MaxVarintLen64)
+	b.Write(buf[:binary.PutUvarint(buf, r.Count)])
+	b.Write(buf[:binary.PutUvarint(buf, uint64(len(r.Rows)))])
+	for _, row := range r.Rows {
+		b.Write(buf[:binary.PutUvarint(buf, row.ID)])
+		b.Write(buf[:binary.PutUvarint(buf, row.Intersection)])
+		b.Write(buf[:binary.PutUvarint(buf, row.Count)])
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a result encoded by MarshalBinary.
+func (r *SimilarResult) UnmarshalBinary(data []byte) error {
+	next := func() (uint64, error) {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, errors.New("invalid similar result")
+		}
+		data = data[n:]
+		return v, nil
+	}
+	var err error
+	if r.Count, err = next(); err != nil {
+		return err
+	}
+	n, err := next()
+	if err != nil {
+		return err
+	} else if n > uint64(len(data)) {
+		return errors.New("invalid similar row count")
+	}
+	r.Rows = make([]SimilarRow, n)
+	for i := range r.Rows {
+		if r.Rows[i].ID, err = next(); err != nil {
+			return err
+		}
+		if r.Rows[i].Intersection, err = next(); err != nil {
+			return err
+		}
+		if r.Rows[i].Count, err = next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Similarity metrics supported by Similar().
+const (
+	SimilarityJaccard = "jaccard"
+	SimilarityCosine  = "cosine"
+	SimilarityOverlap = "overlap"
+)
+
+// SimilarOptions are the scoring arguments of a Similar() call.
+type SimilarOptions struct {
+	Metric string
+	K      uint64
+}
+
+// ParseSimilarOptions reads the metric and k arguments of a Similar() call.
+// The metric defaults to jaccard and k to 10.
+func ParseSimilarOptions(c *pql.Call) (SimilarOptions, error) {
+	opts := SimilarOptions{Metric: SimilarityJaccard, K: 10}
+	if metric, ok, err := c.StringArg("metric"); err != nil {
+		return opts, errors.Wrap(err, "Similar(): reading metric")
+	} else if ok {
+		opts.Metric = strings.ToLower(metric)
+	}
+	switch opts.Metric {
+	case SimilarityJaccard, SimilarityCosine, SimilarityOverlap:
+	default:
+		return opts, errors.Errorf("Similar(): unknown metric %q", opts.Metric)
+	}
+	if k, ok, err := c.UintArg("k"); err != nil {
+		return opts, errors.Wrap(err, "Similar(): reading k")
+	} else if ok {
+		opts.K = k
+	}
+	return opts, nil
+}
+
+// Top scores the rows of r and keeps the k most similar, most similar
+// first. Ties are broken by the larger intersection, then by row ID.
+func (o SimilarOptions) Top(r *SimilarResult) {
+	x := float64(r.Count)
+	rows := r.Rows[:0]
+	for _, row := range r.Rows {
+		if row.Intersection == 0 {
+			continue
+		}
+		n, a := float64(row.Intersection), float64(row.Count)
+		switch o.Metric {
+		case SimilarityJaccard:
+			row.Score = n / (a + x - n)
+		case SimilarityCosine:
+			row.Score = n / math.Sqrt(a*x)
+		case SimilarityOverlap:
+			row.Score = n / math.Min(a, x)
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Score != rows[j].Score {
+			return rows[i].Score > rows[j].Score
+		}
+		if rows[i].Intersection != rows[j].Intersection {
+			return rows[i].Intersection > rows[j].Intersection
+		}
+		return rows[i].ID < rows[j].ID
+	})
+	if o.K > 0 && uint64(len(rows)) > o.K {
+		rows = rows[:o.K]
+	}
+	r.Rows = rows
+}
+
+// executeSimilar executes a Similar() call, which finds the rows of a field
+// sharing the most columns with a target row of the same field, relative to
+// their sizes. Like TopN(), it first finds the rows which intersect the
+// target in any shard, then counts exactly those rows in every shard.
+func (e *executor) executeSimilar(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shards []uint64, opt *ExecOptions) (*SimilarResult, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeSimilar")
+	defer span.Finish()
+
+	fieldName, err := c.FirstStringArg("_field", "field")
+	if err != nil || fieldName == "" {
+		return nil, errors.New("Similar(): field required")
+	}
+	f := e.Holder.Field(index, fieldName)
+	if f == nil {
+		return nil, newNotFoundError(ErrFieldNotFound, fieldName)
+	}
+	switch f.Type() {
+	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp:
+		return nil, errors.Errorf("cannot compute Similar() on integer, decimal, or timestamp field: %q", fieldName)
+	}
+	if _, ok, err := c.UintArg("row"); err != nil {
+		return nil, errors.Wrap(err, "Similar(): reading row")
+	} else if !ok {
+		return nil, errors.New("Similar(): row required")
+	}
+	sim, err := ParseSimilarOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	_, hasIDs, err := c.UintSliceArg("ids")
+	if err != nil {
+		return nil, errors.Wrap(err, "Similar(): reading ids")
+	}
+
+	result, err := e.executeSimilarShards(ctx, qcx, index, c, shards, opt)
+	if err != nil {
+		return nil, errors.Wrap(err, "finding similar rows")
+	}
+
+	// If this call is against specific ids, or we are part of a larger
+	// distributed query, return the counts for the coordinator to score.
+	if opt.Remote || hasIDs {
+		return result, nil
+	}
+
+	if len(result.Rows) > 0 {
+		// Only the original caller should refetch the full counts.
+		other := c.Clone()
+		ids := make([]uint64, len(result.Rows))
+		for i, row := range result.Rows {
+			ids[i] = row.ID
+		}
+		other.Args["ids"] = ids
+
+		if result, err = e.executeSimilarShards(ctx, qcx, index, other, shards, opt); err != nil {
+			return nil, errors.Wrap(err, "retrieving full counts")
+		}
+	}
+	sim.Top(result)
+	result.Field = fieldName
+	return result, nil
+}
+
+func (e *executor) executeSimilarShards(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shards []uint64, opt *ExecOptions) (*SimilarResult, error) {
+	// Execute calls in bulk on each remote node and merge.
+	mapFn := func(ctx context.Context, shard uint64, mopt *mapOptions) (_ interface{}, err error) {
+		return e.executeSimilarShard(ctx, qcx, index, c, shard)
+	}
+
+	// Merge returned results at coordinating node.
+	reduceFn := func(ctx context.Context, prev, v interface{}) interface{} {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		other, _ := prev.(*SimilarResult)
+		if other == nil {
+			other = &SimilarResult{}
+		}
+		if v, ok := v.(*SimilarResult); ok {
+			other.Merge(v)
+		}
+		return other
+	}
+
+	result, err := e.mapReduce(ctx, index, shards, c, opt, mapFn, reduceFn)
+	if err != nil {
+		return nil, err
+	}
+	sim, _ := result.(*SimilarResult)
+	if sim == nil {
+		sim = &SimilarResult{}
+	}
+	return sim, nil
+}
+
+// executeSimilarShard counts, for a single shard, the columns of the target
+// row and of each other row of the field, and the columns they share. Only
+// rows sharing columns with the target are returned, unless ids lists the
+// rows to count.
+func (e *executor) executeSimilarShard(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shard uint64) (_ *SimilarResult, err0 error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeSimilarShard")
+	defer span.Finish()
+
+	fieldName, err := c.FirstStringArg("_field", "field")
+	if err != nil {
+		return nil, errors.Wrap(err, "Similar(): reading field")
+	}
+	rowID, _, err := c.UintArg("row")
+	if err != nil {
+		return nil, errors.Wrap(err, "Similar(): reading row")
+	}
+	ids, hasIDs, err := c.UintSliceArg("ids")
+	if err != nil {
+		return nil, errors.Wrap(err, "Similar(): reading ids")
+	}
+
+	var filter *Row
+	if call, ok, err := c.CallArg("filter"); err != nil {
+		return nil, errors.Wrap(err, "Similar(): reading filter")
+	} else if ok {
+		if filter, err = e.executeBitmapCallShard(ctx, qcx, index, call, shard); err != nil {
+			return nil, errors.Wrap(err, "executing filter")
+		}
+	}
+
+	frag := e.Holder.fragment(index, fieldName, viewStandard, shard)
+	if frag == nil {
+		return &SimilarResult{}, nil
+	}
+	idx := e.Holder.Index(index)
+	tx, finisher, err := qcx.GetTx(Txo{Write: !writable, Fragment: frag, Index: idx, Shard: shard})
+	if err != nil {
+		return nil, err
+	}
+	defer finisher(&err0)
+
+	target, err := frag.row(tx, rowID)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting target row")
+	}
+	if filter != nil {
+		target = target.Intersect(filter)
+	}
+	result := &SimilarResult{Count: target.Count()}
+	if !hasIDs {
+		if result.Count == 0 {
+			return result, nil
+		}
+		if ids, err = frag.rows(ctx, tx, 0); err != nil {
+			return nil, errors.Wrap(err, "getting rows")
+		}
+	}
+	for _, id := range ids {
+		if id == rowID {
+			continue
+		}
+		row, err := frag.row(tx, id)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting row")
+		}
+		if filter != nil {
+			row = row.Intersect(filter)
+		}
+		n := target.intersectionCount(row)
+		if n == 0 && !hasIDs {
+			continue
+		}
+		if count := row.Count(); count > 0 {
+			result.Rows = append(result.Rows, SimilarRow{ID: id, Intersection: n, Count: count})
+		}
+	}
+	return result, nil
 }
 
 // executeDifferenceShard executes a difference() call for a local shard.
@@ -3239,9 +4395,9 @@ func (e *executor) executeGroupBy(ctx context.Context, qcx *Qcx, index string, c
 		if err != nil {
 			return nil, errors.Wrap(err, "getting column")
 		}
-		_, hasLike, err := child.StringArg("like")
+		_, _, hasLike, err := RowsKeyPattern(child)
 		if err != nil {
-			return nil, errors.Wrap(err, "getting like")
+			return nil, err
 		}
 		_, hasIn, err := child.UintSliceArg("in")
 		if err != nil {
@@ -3259,6 +4415,17 @@ func (e *executor) executeGroupBy(ctx context.Context, qcx *Qcx, index string, c
 		case FieldTypeInt, FieldTypeTimestamp:
 			bases[i] = f.bsiGroup(f.name).Base
 		}
+		if _, ok := child.Args["bucket"]; ok {
+			if _, err := groupByBucketFunc(f, child); err != nil {
+				return nil, err
+			}
+			if hasLimit || hasCol || hasLike || hasIn {
+				return nil, errors.New("Rows() bucket cannot be combined with limit, column, like or in")
+			}
+			if agg, ok := c.Args["aggregate"].(*pql.Call); ok && agg.Name == "Count" {
+				return nil, errors.New("Rows() bucket is not supported with a Count(Distinct) aggregate")
+			}
+		}
 
 		if hasLimit || hasCol || hasLike || hasIn { // we need to perform this query cluster-wide ahead of executeGroupByShard
 			if idx, ok := child.Args["valueidx"].(int64); ok {
@@ -3996,6 +5163,9 @@ func (e *executor) executeRows(ctx context.Context, qcx *Qcx, index string, c *p
 	if fieldName, ok = c.Args["_field"].(string); !ok {
 		return nil, errors.New("Rows() field required")
 	}
+	if _, ok := c.Args["bucket"]; ok {
+		return nil, errors.New("Rows() bucket is only supported within GroupBy")
+	}
 	if columnID, ok, err := c.UintArg("column"); err != nil {
 		return nil, errors.Wrap(err, "getting column")
 	} else if ok {
@@ -4044,12 +5214,12 @@ func (e *executor) executeRows(ctx context.Context, qcx *Qcx, index string, c *p
 	results, _ := other.(RowIDs)
 
 	if !opt.Remote {
-		if like, hasLike, err := c.StringArg("like"); err != nil {
-			return nil, errors.Wrap(err, "getting like pattern")
-		} else if hasLike {
-			matches, err := e.Cluster.matchField(ctx, e.Holder.Field(index, fieldName), like)
+		if syntax, pattern, hasPattern, err := RowsKeyPattern(c); err != nil {
+			return nil, err
+		} else if hasPattern {
+			matches, err := e.Cluster.matchField(ctx, e.Holder.Field(index, fieldName), syntax, pattern)
 			if err != nil {
-				return nil, errors.Wrap(err, "matching like pattern")
+				return nil, errors.Wrapf(err, "matching %s pattern", syntax)
 			}
 
 			i, j, k := 0, 0, 0
@@ -4875,6 +6045,30 @@ func (e *executor) executeExtractShard(ctx context.Context, qcx *Qcx, index stri
 					*fieldSlot = append(*fieldSlot, rowID)
 				}
 			}
+		case FieldTypeGeo:
+			// Handle a geo field by listing the rows of the finest cells,
+			// one of which is set for each point.
+
+			// Extract the standard view fragment.
+			fragment := e.Holder.fragment(index, name, viewStandard, shard)
+			if fragment == nil {
+				// There is nothing here.
+				continue
+			}
+
+			rows, err := fragment.rows(ctx, tx, 1<<(2*geoMaxLevel))
+			if err != nil {
+				return ExtractedIDMatrix{}, errors.Wrap(err, "listing rows in geo field")
+			}
+			for _, rowID := range rows {
+				row, err := fragment.row(tx, rowID)
+				if err != nil {
+					return ExtractedIDMatrix{}, errors.Wrap(err, "loading row from fragment")
+				}
+				for _, columnID := range row.Intersect(colsBitmap).Columns() {
+					m[mLookup[columnID]].Rows[i] = []uint64{rowID}
+				}
+			}
 		case FieldTypeTime:
 			// Handle a set field by listing the rows and then intersecting them with the filter.
 			timeArg := timeArgs[i]
@@ -5033,7 +6227,7 @@ func (e *executor) executeExtractShard(ctx context.Context, qcx *Qcx, index stri
 		Columns: m,
 	}
 	if v := atomic.AddInt64(mopt.memoryAvailable, -calcResultMemory(matrix)); v < 0 {
-		return ExtractedIDMatrix{}, fmt.Errorf("result exceeds available memory")
+		return ExtractedIDMatrix{}, queryMemoryExceeded(ctx)
 	}
 
 	if sortedResult != nil {
@@ -5920,19 +7114,36 @@ func (e *executor) executeClearBit(ctx context.Context, qcx *Qcx, index string,
 		return false, newNotFoundError(ErrFieldNotFound, fieldName)
 	}
 
+	var changed bool
 	// BSI field
 	if f.Type() == FieldTypeInt || f.Type() == FieldTypeDecimal || f.Type() == FieldTypeTimestamp {
-		return e.executeClearValueField(ctx, qcx, index, c, f, colID, opt)
+		changed, err = e.executeClearValueField(ctx, qcx, index, c, f, colID, opt)
+	} else {
+		var rowID uint64
+		rowID, ok, err = c.UintArg(fieldName)
+		if err != nil {
+			return false, fmt.Errorf("reading Clear() row: %v", err)
+		} else if !ok {
+			return false, fmt.Errorf("row=<row> argument required to Clear() call")
+		}
+		changed, err = e.executeClearBitField(ctx, qcx, index, c, f, colID, rowID, opt)
 	}
-
-	rowID, ok, err := c.UintArg(fieldName)
 	if err != nil {
-		return false, fmt.Errorf("reading Clear() row: %v", err)
-	} else if !ok {
-		return false, fmt.Errorf("row=<row> argument required to Clear() call")
+		return false, err
 	}
 
-	return e.executeClearBitField(ctx, qcx, index, c, f, colID, rowID, opt)
+	if changed && idx.changes != nil && e.Cluster.recordsChanges(index, colID/ShardWidth) {
+		qcx.OnCommit(func() {
+			idx.recordChanges(&ChangeEvent{
+				Index:   index,
+				Shard:   colID / ShardWidth,
+				Op:      ChangeOpClear,
+				Fields:  []string{fieldName},
+				Columns: []uint64{colID},
+			})
+		})
+	}
+	return changed, nil
 }
 
 // executeClearBitField executes a Clear() call for a field.
@@ -6055,6 +7266,11 @@ func (e *executor) executeClearRowShard(ctx context.Context, qcx *Qcx, index str
 	}
 	defer finisher(&err0)
 
+	var changes *changeSet
+	if idx.changes != nil && e.Cluster.recordsChanges(index, shard) {
+		changes = newChangeSet(index, shard, nil)
+	}
+
 	// Remove the row from all views.
 	changed := false
 	for _, view := range field.views() {
@@ -6062,6 +7278,13 @@ func (e *executor) executeClearRowShard(ctx context.Context, qcx *Qcx, index str
 		if fragment == nil {
 			continue
 		}
+		if changes != nil {
+			row, err := fragment.row(tx, rowID)
+			if err != nil {
+				return false, errors.Wrapf(err, "reading row %d on view %s shard %d", rowID, view.name, shard)
+			}
+			changes.addClear(fieldName, row.Clone())
+		}
 		cleared, err := fragment.clearRow(tx, rowID)
 		if err != nil {
 			return false, errors.Wrapf(err, "clearing row %d on view %s shard %d", rowID, view.name, shard)
@@ -6069,6 +7292,9 @@ func (e *executor) executeClearRowShard(ctx context.Context, qcx *Qcx, index str
 		changed = changed || cleared
 	}
 
+	if changes != nil {
+		qcx.OnCommit(func() { idx.recordChangeSet(changes) })
+	}
 	return changed, nil
 }
 
@@ -6179,6 +7405,26 @@ func (e *executor) executeSetRowShard(ctx context.Context, qcx *Qcx, index strin
 		return false, err
 	}
 
+	if idx.changes != nil && e.Cluster.recordsChanges(index, shard) {
+		changes, err := idx.newChangeSet(tx, shard)
+		if err != nil {
+			finisher(&err)
+			return false, err
+		}
+		prev, err := fragment.row(tx, rowID)
+		if err != nil {
+			finisher(&err)
+			return false, errors.Wrapf(err, "reading row %d on view %s shard %d", rowID, viewStandard, shard)
+		}
+		prev = prev.Clone()
+		changes.addSet(fieldName, src.Difference(prev))
+		changes.addClear(fieldName, prev.Difference(src))
+		defer func() {
+			if err0 == nil {
+				qcx.OnCommit(func() { idx.recordChangeSet(changes) })
+			}
+		}()
+	}
 	defer finisher(&err0)
 
 	set, err := fragment.setRow(tx, src, rowID)
@@ -6221,12 +7467,32 @@ func (e *executor) executeSet(ctx context.Context, qcx *Qcx, index string, c *pq
 	}
 
 	// Set column on existence field.
+	op := ChangeOpUpdate
 	if ef := idx.existenceField(); ef != nil {
-		if _, err := ef.SetBit(qcx, 0, colID, nil); err != nil {
+		inserted, err := ef.SetBit(qcx, 0, colID, nil)
+		if err != nil {
 			return false, errors.Wrap(err, "setting existence column")
+		} else if inserted {
+			op = ChangeOpInsert
 		}
 	}
 
+	// Every node the call is sent to sets the existence column, so the
+	// primary knows whether the record was inserted.
+	defer func() {
+		if err0 == nil && idx.changes != nil && e.Cluster.recordsChanges(index, colID/ShardWidth) {
+			qcx.OnCommit(func() {
+				idx.recordChanges(&ChangeEvent{
+					Index:   index,
+					Shard:   colID / ShardWidth,
+					Op:      op,
+					Fields:  []string{fieldName},
+					Columns: []uint64{colID},
+				})
+			})
+		}
+	}()
+
 	switch f.Type() {
 	case FieldTypeInt, FieldTypeDecimal, FieldTypeTimestamp:
 		// Fetch field
@@ -6251,6 +7517,13 @@ func (e *executor) executeSet(ctx context.Context, qcx *Qcx, index string, c *pq
 		}
 		return e.executeSetValueField(ctx, qcx, index, c, f, colID, rowVal, opt)
 
+	case FieldTypeGeo:
+		p, err := geoPointArg(c.Args[fieldName])
+		if err != nil {
+			return false, fmt.Errorf("reading Set() point: %v", err)
+		}
+		return e.executeSetGeoField(ctx, qcx, index, c, f, colID, p, opt)
+
 	default:
 		// Read row ID.
 		rowID, ok, err := c.UintArg(fieldName)
@@ -6312,6 +7585,44 @@ func (e *executor) executeSetBitField(ctx context.Context, qcx *Qcx, index strin
 	return ret, nil
 }
 
+// executeSetGeoField executes a Set() call for a specific geo field.
+func (e *executor) executeSetGeoField(ctx context.Context, qcx *Qcx, index string, c *pql.Call, f *Field, colID uint64, p GeoPoint, opt *ExecOptions) (bool, error) {
+	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeSetGeoField")
+	defer span.Finish()
+
+	shard := colID / ShardWidth
+	ret := false
+
+	// Create a snapshot of the cluster to use for node/partition calculations.
+	snap := e.Cluster.NewSnapshot()
+
+	for _, node := range snap.ShardNodes(index, shard) {
+		// Update locally if host matches.
+		if node.ID == e.Node.ID {
+			val, err := f.SetGeoPoint(qcx, colID, p)
+			if err != nil {
+				return false, err
+			} else if val {
+				ret = true
+			}
+			continue
+		}
+
+		// Do not forward call if this is already being forwarded.
+		if opt.Remote {
+			continue
+		}
+
+		// Forward call to remote node otherwise.
+		res, err := e.remoteExec(ctx, node, index, &pql.Query{Calls: []*pql.Call{c}}, nil, nil, 0)
+		if err != nil {
+			return false, err
+		}
+		ret = res[0].(bool)
+	}
+	return ret, nil
+}
+
 // executeSetValueField executes a Set() call for a specific int field.
 func (e *executor) executeSetValueField(ctx context.Context, qcx *Qcx, index string, c *pql.Call, f *Field, colID uint64, value int64, opt *ExecOptions) (_ bool, err0 error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "executor.executeSetValueField")
@@ -6421,6 +7732,9 @@ func (e *executor) shardsByNode(nodes []*disco.Node, index string, shards []uint
 	// the node states in order to ensure that we don't include an unavailable
 	// node in the map of nodes to which we distribute the query.
 	snap := disco.NewClusterSnapshot(disco.NewLocalNoder(e.Cluster.Nodes()), e.Cluster.Hasher, e.Cluster.partitionAssigner, e.Cluster.ReplicaN)
+	if e.Holder != nil {
+		snap.PartitionKey = e.Holder.partitionKey
+	}
 
 loop:
 	for _, shard := range shards {
@@ -6490,6 +7804,13 @@ func (e *executor) mapReduce(ctx context.Context, index string, shards []uint64,
 	for expected > 0 {
 		select {
 		case <-done:
+			// The errgroup cancels ctx when one of the mapper's goroutines
+			// fails, such as when results exceed the max query memory; report
+			// that failure rather than the cancellation.
+			cancel()
+			if err := eg.Wait(); err != nil {
+				return nil, err
+			}
 			return nil, ctx.Err()
 		case resp := <-ch:
 			// On error retry against remaining nodes. If an error returns then
@@ -6590,12 +7911,16 @@ func (e *executor) mapper(ctx context.Context, eg *errgroup.Group, ch chan mapRe
 	// Execute each node in a separate goroutine.
 	var memoryUsed int64
 	var mu sync.Mutex
+	pool := resourcePoolFromContext(ctx)
+	serial := c.Name == "Extract" && opt.MaxMemory > 0 && pool != nil && pool.MaxQueryMemory > 0
 	for n, nodeShards := range m {
 		n := n
 		nodeShards := nodeShards
 		eg.Go(func() error {
-			// Execute serially max memory is specified.
-			if opt.MaxMemory > 0 {
+			// Execute Extract() serially if the query's resource pool limits
+			// its memory, so each node is given the memory the others left
+			// available.
+			if serial {
 				mu.Lock()
 				defer mu.Unlock()
 			}
@@ -6626,7 +7951,7 @@ func (e *executor) mapper(ctx context.Context, eg *errgroup.Group, ch chan mapRe
 
 			// Track total memory used in response.
 			if v := atomic.AddInt64(&memoryUsed, calcResultMemory(resp.result)); opt.MaxMemory > 0 && v > opt.MaxMemory {
-				return fmt.Errorf("query result exceeded memory threshold")
+				return queryMemoryExceeded(ctx)
 			}
 
 			// Return response to the channel.
@@ -6702,6 +8027,24 @@ func calcResultMemory(v interface{}) (n int64) {
 		return n
 	case []uint64:
 		return 24 + int64(8*len(v)) // slice header + data size
+	case []GroupCount:
+		n += 24 // slice header
+		for _, gc := range v {
+			n += 24 + 8 + 8 + 8 // Group slice header + Count + Agg + DecimalAgg
+			for _, fr := range gc.Group {
+				n += 16 + int64(len(fr.Field)) + 8 + 16 + int64(len(fr.RowKey)) + 8 + 8
+			}
+		}
+		return n
+	case *PairsField:
+		if v == nil {
+			return 0
+		}
+		n += 24 + 16 + int64(len(v.Field)) // Pairs slice header + Field
+		for _, p := range v.Pairs {
+			n += 8 + 16 + int64(len(p.Key)) + 8
+		}
+		return n
 	case pql.Decimal:
 		return 16
 	case time.Time:
@@ -6721,7 +8064,7 @@ type job struct {
 
 // doOneJob had one job. *disappointed sigh*
 func (e *executor) doOneJob() {
-	j, ok := <-e.work
+	j, ok := e.nextJob()
 	if !ok {
 		return
 	}
@@ -6736,6 +8079,26 @@ func (e *executor) doOneJob() {
 	j.resultChan <- mapResponse{result: result, err: err}
 }
 
+// nextJob waits for a job, taking the jobs of higher priority queries ahead
+// of the others. It returns false once the executor has closed.
+func (e *executor) nextJob() (job, bool) {
+	for p := numQueryPriorities - 1; p >= 0; p-- {
+		select {
+		case j, ok := <-e.work[p]:
+			return j, ok
+		default:
+		}
+	}
+	select {
+	case j, ok := <-e.work[QueryPriorityHigh]:
+		return j, ok
+	case j, ok := <-e.work[QueryPriorityNormal]:
+		return j, ok
+	case j, ok := <-e.work[QueryPriorityLow]:
+		return j, ok
+	}
+}
+
 var errShutdown = errors.New("executor has shut down")
 
 // mapperLocal performs map & reduce entirely on the local node.
@@ -6761,6 +8124,7 @@ func (e *executor) mapperLocal(ctx context.Context, shards []uint64, mapFn mapFu
 	}
 
 	ch := make(chan mapResponse, len(shards))
+	work := e.work[queryPriority(ctx)]
 
 	expected := 0
 shardLoop:
@@ -6777,7 +8141,7 @@ shardLoop:
 			break shardLoop
 		case <-e.shutdown: // whole executor shutting down
 			break shardLoop
-		case e.work <- j:
+		case work <- j:
 			expected++
 		}
 	}
@@ -7026,6 +8390,15 @@ func (e *executor) collectCallKeys(dst *keyCollector, c *pql.Call, index string)
 			dst.FindColumns(index, keys...)
 		}
 
+	case "Similar":
+		field, err := c.FirstStringArg("_field", "field")
+		if err != nil {
+			return errors.Wrap(err, "finding field for Similar row translation")
+		}
+		if row, ok := c.Args["row"].(string); ok {
+			dst.FindRows(index, field, row)
+		}
+
 	case "Rows":
 		// Find the field.
 		var field string
@@ -7205,6 +8578,10 @@ func fieldValidateValue(f *Field, val interface{}) error {
 		default:
 			return errors.Errorf("invalid value %v for timestamp field %q", v, f.Name())
 		}
+	case FieldTypeGeo:
+		if _, err := geoPointArg(val); err != nil {
+			return errors.Wrapf(err, "invalid value for geo field %q", f.Name())
+		}
 	default:
 		return errors.Errorf("unsupported type %s of field %q", f.Type(), f.Name())
 	}
@@ -7396,6 +8773,29 @@ func (e *executor) translateCall(c *pql.Call, index string, columnKeys map[strin
 			c.Args["columns"] = out
 		}
 
+	case "Similar":
+		field, err := c.FirstStringArg("_field", "field")
+		if err != nil {
+			return nil, errors.Wrap(err, "finding field for Similar row translation")
+		}
+		// Translate the target row key.
+		if row, ok := c.Args["row"]; ok {
+			f := e.Holder.Field(index, field)
+			if f == nil {
+				return nil, errors.Wrapf(ErrFieldNotFound, "validating value for field %q", field)
+			}
+			if err := fieldValidateValue(f, row); err != nil {
+				return nil, errors.Wrap(err, "validating row value")
+			}
+			if row, ok := row.(string); ok {
+				if translation, ok := indexRows[field][row]; ok {
+					c.Args["row"] = translation
+				} else {
+					return nil, errors.Wrapf(ErrTranslatingKeyNotFound, "translating row key %q from field %q in index %q in Similar call", row, field, index)
+				}
+			}
+		}
+
 	case "Rows":
 		// Find the field.
 		var field string
@@ -7438,8 +8838,8 @@ func (e *executor) translateCall(c *pql.Call, index string, columnKeys map[strin
 			}
 		}
 
-		// Check if "like" argument is applied to keyed fields.
-		if _, found := c.Args["like"].(string); found {
+		// Check if a key pattern argument is applied to keyed fields.
+		if _, _, found, _ := RowsKeyPattern(c); found {
 			fieldName, err := c.FirstStringArg("_field", "field")
 			if err != nil || fieldName == "" {
 				return nil, fmt.Errorf("cannot read field name for Rows call")
@@ -7640,6 +9040,8 @@ func (e *executor) collectResultIDs(index string, idx *Index, call *pql.Call, re
 		for _, col := range result.Columns {
 			idSet[col.ColumnID] = struct{}{}
 		}
+	case *FunnelResult:
+		return e.collectResultIDs(index, idx, call, result.Row, idSet)
 	}
 
 	return nil
@@ -7659,6 +9061,12 @@ func (e *executor) preTranslateMatrixSet(ctx context.Context, mat ExtractedIDMat
 
 func (e *executor) translateResult(ctx context.Context, index string, idx *Index, call *pql.Call, result interface{}, idSet map[uint64]string, memoryAvailable *int64) (_ interface{}, err error) {
 	switch result := result.(type) {
+	case *FunnelResult:
+		row, err := e.translateResult(ctx, index, idx, call, result.Row, idSet, memoryAvailable)
+		if err != nil {
+			return nil, errors.Wrap(err, "translating funnel columns")
+		}
+		return &FunnelResult{Counts: result.Counts, Row: row.(*Row)}, nil
 	case *Row:
 		rowIdx, rowField, strategy, err := e.howToTranslate(idx, result)
 		if err != nil {
@@ -7791,6 +9199,27 @@ func (e *executor) translateResult(ctx context.Context, index string, idx *Index
 			}
 		}
 
+	case *SimilarResult:
+		field := idx.Field(result.Field)
+		if field == nil {
+			return nil, fmt.Errorf("field %q not found", result.Field)
+		}
+		if field.Keys() {
+			ids := make([]uint64, len(result.Rows))
+			for i := range result.Rows {
+				ids[i] = result.Rows[i].ID
+			}
+			keys, err := e.Cluster.translateFieldListIDs(ctx, field, ids)
+			if err != nil {
+				return nil, err
+			}
+			other := make([]SimilarRow, len(result.Rows))
+			for i, row := range result.Rows {
+				other[i] = SimilarRow{Key: keys[i], Score: row.Score, Intersection: row.Intersection, Count: row.Count}
+			}
+			return &SimilarResult{Field: result.Field, Count: result.Count, Rows: other}, nil
+		}
+
 	case *GroupCounts:
 		fieldIDs := make(map[*Field]map[uint64]struct{})
 		foreignIDs := make(map[*Field]map[uint64]struct{})
@@ -8043,6 +9472,15 @@ func (e *executor) translateResult(ctx context.Context, index string, idx *Index
 						return nil, errors.Errorf("BSI field %q has too many values: %v", field.Name(), ids)
 					}
 				}
+			case FieldTypeGeo:
+				datatype = "geo"
+				mapper = func(ids []uint64) (_ interface{}, err error) {
+					p, ok, err := geoPointFromRowIDs(ids)
+					if err != nil || !ok {
+						return nil, err
+					}
+					return p, nil
+				}
 			case FieldTypeTimestamp:
 				datatype = "timestamp"
 				unit := field.Options().TimeUnit
@@ -8103,7 +9541,7 @@ func (e *executor) translateResult(ctx context.Context, index string, idx *Index
 				Rows:   data,
 			}
 			if *memoryAvailable -= calcResultMemory(cols[i]); *memoryAvailable < 0 {
-				return nil, fmt.Errorf("table exceeds available memory")
+				return nil, queryMemoryExceeded(ctx)
 			}
 		}
 
@@ -8674,7 +10112,11 @@ func newGroupByIterator(executor *executor, qcx *Qcx, rowIDs []RowIDs, children
 	)
 	ignorePrev := false
 	for i, call := range children {
-		var isTimeField bool
+		var (
+			isTimeField bool
+			bucket      func(int64) int64
+			err         error
+		)
 		if fieldName, ok = call.Args["_field"].(string); !ok {
 			return nil, errors.Errorf("%s call must have field with valid (string) field name. Got %v of type %[2]T", call.Name, call.Args["_field"])
 		}
@@ -8730,6 +10172,9 @@ func newGroupByIterator(executor *executor, qcx *Qcx, rowIDs []RowIDs, children
 			}
 		case FieldTypeInt, FieldTypeTimestamp:
 			viewName = viewBSIGroupPrefix + fieldName
+			if bucket, err = groupByBucketFunc(field, call); err != nil {
+				return nil, err
+			}
 
 		default: // FieldTypeDecimal
 			return nil, errors.Errorf("%s call must have field of one of types: %s",
@@ -8771,7 +10216,11 @@ func newGroupByIterator(executor *executor, qcx *Qcx, rowIDs []RowIDs, children
 				return nil, nil
 			}
 
-			gbi.rowIters[i], err = frag.rowIterator(tx, i != 0, filters...)
+			if bucket != nil {
+				gbi.rowIters[i], err = frag.bucketRowIterator(tx, i != 0, bucket, filters...)
+			} else {
+				gbi.rowIters[i], err = frag.rowIterator(tx, i != 0, filters...)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -8933,6 +10382,71 @@ func (gbi *groupByIterator) Next(ctx context.Context) (ret GroupCount, done bool
 	return ret, false, err
 }
 
+// groupByBucketFunc returns a function which maps a stored value of the int
+// or timestamp field f to the stored value at the start of its bucket, as
+// given by the bucket argument of the Rows() call c. An integer bucket is a
+// fixed width, and a string bucket is a calendar unit of a timestamp field.
+// It returns nil if c has no bucket argument.
+func groupByBucketFunc(f *Field, c *pql.Call) (func(int64) int64, error) {
+	arg, ok := c.Args["bucket"]
+	if !ok {
+		return nil, nil
+	}
+	opts := f.Options()
+	if opts.Type != FieldTypeInt && opts.Type != FieldTypeTimestamp {
+		return nil, errors.Errorf("Rows() bucket requires an int or timestamp field, but %q is of type %s", f.name, opts.Type)
+	}
+	base := f.bsiGroup(f.name).Base
+
+	switch arg := arg.(type) {
+	case int64:
+		if arg <= 0 {
+			return nil, errors.Errorf("Rows() bucket width must be positive, got %d", arg)
+		}
+		return func(v int64) int64 {
+			v += base
+			start := v - v%arg
+			if v%arg < 0 {
+				start -= arg
+			}
+			return start - base
+		}, nil
+
+	case string:
+		if opts.Type != FieldTypeTimestamp {
+			return nil, errors.Errorf("Rows() bucket %q requires a timestamp field, but %q is of type %s", arg, f.name, opts.Type)
+		}
+		var trunc func(time.Time) time.Time
+		switch arg {
+		case "year":
+			trunc = func(t time.Time) time.Time { return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC) }
+		case "month":
+			trunc = func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC) }
+		case "day":
+			trunc = func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC) }
+		case "hour":
+			trunc = func(t time.Time) time.Time { return t.Truncate(time.Hour) }
+		case "minute":
+			trunc = func(t time.Time) time.Time { return t.Truncate(time.Minute) }
+		case "second":
+			trunc = func(t time.Time) time.Time { return t.Truncate(time.Second) }
+		default:
+			return nil, errors.Errorf("invalid Rows() bucket %q, must be one of year, month, day, hour, minute or second", arg)
+		}
+		unit := opts.TimeUnit
+		if _, err := ValToTimestamp(unit, 0); err != nil {
+			return nil, err
+		}
+		return func(v int64) int64 {
+			t, _ := ValToTimestamp(unit, v+base)
+			return TimestampToVal(unit, trunc(t)) - base
+		}, nil
+
+	default:
+		return nil, errors.Errorf("Rows() bucket must be an integer width or a calendar unit, got %v of type %[1]T", arg)
+	}
+}
+
 // getCondIntSlice looks at the field, the op type (which is
 // expected to be one of the BETWEEN ops types), and the values in the
 // conditional and returns a slice of int64 which is scaled for
@@ -9056,12 +10570,19 @@ func (e *executor) executeDeleteRecords(ctx context.Context, qcx *Qcx, index str
 	} else if len(c.Children) > 1 {
 		return false, errors.New("Delete() only accepts a single bitmap input")
 	}
-	qcx.Abort()
-	qcx.Reset() // release the qcx to allow for rbf checkpoint
+	// The records of a sql transaction are deleted through its qcx;
+	// otherwise the qcx is released to allow for rbf checkpoint.
+	var session *Qcx
+	if qcx.DefersWrites() {
+		session = qcx
+	} else {
+		qcx.Abort()
+		qcx.Reset()
+	}
 
 	// Execute calls in bulk on each remote node and merge.
 	mapFn := func(ctx context.Context, shard uint64, mopt *mapOptions) (_ interface{}, err error) {
-		return e.executeDeleteRecordFromShard(ctx, index, c.Children[0], shard)
+		return e.executeDeleteRecordFromShard(ctx, session, index, c.Children[0], shard)
 	}
 
 	// Merge returned results at coordinating node.
@@ -9101,7 +10622,10 @@ func transactExistRow(ctx context.Context, idx *Index, shard uint64, frag *fragm
 	return rowID, tx.Commit()
 }
 
-func (e *executor) executeDeleteRecordFromShard(ctx context.Context, index string, bmCall *pql.Call, shard uint64) (changed bool, err error) {
+// executeDeleteRecordFromShard deletes the records matching bmCall from a
+// shard. If session is set, it is the qcx of a sql transaction, and the
+// records are deleted through it.
+func (e *executor) executeDeleteRecordFromShard(ctx context.Context, session *Qcx, index string, bmCall *pql.Call, shard uint64) (changed bool, err error) {
 	span, _ := tracing.StartSpanFromContext(ctx, "Executor.executeDeleteRecordFromShard")
 	defer span.Finish()
 	// Fetch index.
@@ -9110,10 +10634,15 @@ func (e *executor) executeDeleteRecordFromShard(ctx context.Context, index strin
 		err = newNotFoundError(ErrIndexNotFound, index)
 		return
 	}
-	qcx := e.Holder.Txf().NewQcx()
+	qcx := session
+	if qcx == nil {
+		qcx = e.Holder.Txf().NewQcx()
+	}
 	// bmCall is a bitmap
 	row, err := e.executeBitmapCallShard(ctx, qcx, index, bmCall, shard)
-	qcx.Abort()
+	if session == nil {
+		qcx.Abort()
+	}
 	if err != nil {
 		return false, err
 	}
@@ -9125,18 +10654,96 @@ func (e *executor) executeDeleteRecordFromShard(ctx context.Context, index strin
 		return
 	}
 	src := NewRowFromBitmap(columns)
-	return DeleteRowsWithFlow(ctx, src, idx, shard, true)
+
+	// Deleting records deletes their keys, so they're translated first.
+	var deleted *ChangeEvent
+	if idx.changes != nil && e.Cluster.recordsChanges(index, shard) {
+		deleted = &ChangeEvent{
+			Index:   index,
+			Shard:   shard,
+			Op:      ChangeOpDelete,
+			Columns: src.Columns(),
+		}
+		if idx.Keys() {
+			if deleted.ColumnKeys, err = e.Cluster.translateIndexIDs(ctx, index, deleted.Columns); err != nil {
+				return false, errors.Wrap(err, "translating deleted records")
+			}
+		}
+	}
+
+	if session != nil {
+		changed, err = deleteRowsInQcx(session, src, idx, shard)
+		if err == nil && changed && deleted != nil {
+			session.OnCommit(func() { idx.recordChanges(deleted) })
+		}
+		return changed, err
+	}
+
+	changed, err = DeleteRowsWithFlow(ctx, src, idx, shard, true)
+	if err == nil && changed && deleted != nil {
+		idx.recordChanges(deleted)
+	}
+	return changed, err
+}
+
+// deleteRowsInQcx deletes the records in src from a shard through the write
+// Tx a sql transaction holds for it. The keys of the records are only deleted
+// once the transaction commits; the transaction holds the shard until then,
+// so the keyed delete doesn't need the existence row DeleteRowsWithFlow uses
+// to recover from a failure between the two.
+func deleteRowsInQcx(qcx *Qcx, src *Row, idx *Index, shard uint64) (changed bool, err error) {
+	if len(src.Segments) == 0 {
+		return false, nil
+	}
+	columns := src.Segments[0].data // should only be one segment
+	if columns.Count() == 0 {
+		return false, nil
+	}
+
+	tx, finisher, err := qcx.GetTx(Txo{Write: writable, Index: idx, Shard: shard})
+	if err != nil {
+		return false, err
+	}
+	defer finisher(&err)
+
+	for _, field := range idx.Fields() {
+		for _, view := range field.views() {
+			frag := view.Fragment(shard)
+			if frag == nil {
+				continue
+			}
+			c, err := frag.clearRecordsByBitmap(tx, columns)
+			if err != nil {
+				return false, err
+			}
+			if c {
+				changed = true
+			}
+		}
+	}
+
+	if idx.Keys() {
+		qcx.OnCommit(func() {
+			commitor, err := deleteKeyTranslation(context.Background(), idx, shard, columns)
+			if err == nil {
+				err = commitor.Commit()
+			}
+			if err != nil {
+				idx.holder.Logger.Errorf("deleting keys of deleted records in %s shard %d: %v", idx.Name(), shard, err)
+			}
+		})
+	}
+	return changed, nil
 }
 
 func DeleteRows(ctx context.Context, src *Row, idx *Index, shard uint64) (bool, error) {
 	return DeleteRowsWithFlow(ctx, src, idx, shard, false)
 }
 
-func DeleteRowsWithFlowWithKeys(ctx context.Context, columns *roaring.Bitmap, idx *Index, shard uint64, normalFlow bool) (bool, error) {
+func DeleteRowsWithFlowWithKeys(ctx context.Context, columns *roaring.Bitmap, idx *Index, shard uint64, normalFlow bool) (changed bool, err error) {
 	var existenceFragment *fragment
 	var deletedRowID uint64
 	var commitor Commitor = &NopCommitor{}
-	var err error // store columns in exits field ToBeDelete row commited
 	holder := idx.Holder()
 	if normalFlow { // normalFlow is the standard path, "not normal" is recoverory
 		existenceFragment = holder.fragment(idx.Name(), existenceFieldName, viewStandard, shard)
@@ -9159,7 +10766,6 @@ func DeleteRowsWithFlowWithKeys(ctx context.Context, columns *roaring.Bitmap, id
 		return false, err
 	}
 	defer writeTx.Rollback()
-	changed := false
 	defer func() {
 		// if there is an error on the bit clearing rollback the keys
 		if err != nil {
@@ -9225,12 +10831,14 @@ func DeleteRowsWithOutKeysFlow(ctx context.Context, columns *roaring.Bitmap, idx
 	defer func() {
 		// if there is an error in the key commit, then rollback the delete
 		// write records before keys to remove possiblity of unmatch keys=records
-		err := writeTx.Commit()
 		if err != nil {
 			changed = false
-			commitor.Rollback()
 			return
 		}
+		if err = writeTx.Commit(); err != nil {
+			changed = false
+			commitor.Rollback()
+		}
 	}()
 	for _, field := range idx.Fields() {
 		for _, view := range field.views() {
@@ -9313,7 +10921,7 @@ func (c *NopCommitor) Commit() error {
 
 func deleteKeyTranslation(ctx context.Context, idx *Index, shard uint64, records *roaring.Bitmap) (Commitor, error) {
 	// ShardToShardParition ...
-	paritionID := disco.ShardToShardPartition(idx.name, shard, idx.holder.partitionN)
+	paritionID := disco.ShardToShardPartition(idx.PartitionKey(), shard, idx.holder.partitionN)
 
 	return idx.TranslateStore(paritionID).Delete(records)
 }
@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"reflect"
 	"sort"
 	"strconv"
@@ -596,3 +597,39 @@ func TestExecutor_DeleteRows(t *testing.T) {
 		t.Fatalf("expected delete to not clear bit but it did")
 	}
 }
+
+func TestExecutor_ClearBitError(t *testing.T) {
+	holder := newTestHolder(t)
+	c := NewTestCluster(t, 2)
+	e := &executor{
+		Holder:  holder,
+		Cluster: c,
+		Node:    c.Node,
+		client:  NewInternalClientFromURI(nil, &http.Client{Timeout: time.Second}, WithSerializer(NopSerializer)),
+	}
+
+	idx, err := holder.CreateIndex("i", "", IndexOptions{})
+	if err != nil {
+		t.Fatalf("creating index: %v", err)
+	}
+	if _, err := idx.CreateField("f", ""); err != nil {
+		t.Fatalf("creating field: %v", err)
+	}
+
+	// Clear a bit in a shard owned by the other node, which can't be reached.
+	snap := c.NewSnapshot()
+	shard := uint64(0)
+	for snap.ShardNodes("i", shard)[0].ID == e.Node.ID {
+		shard++
+	}
+	query, err := pql.ParseString(fmt.Sprintf("Clear(%d, f=1)", shard*ShardWidth))
+	if err != nil {
+		t.Fatalf("parsing query: %v", err)
+	}
+
+	qcx := holder.Txf().NewWritableQcx()
+	defer qcx.Abort()
+	if changed, err := e.executeClearBit(context.Background(), qcx, "i", query.Calls[0], &ExecOptions{}); err == nil {
+		t.Fatalf("expected error clearing bit on unreachable node, got changed=%v", changed)
+	}
+}
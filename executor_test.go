@@ -1568,6 +1568,78 @@ func TestExecutor_Execute_SetBool(t *testing.T) {
 	})
 }
 
+// Ensure geo points can be set, filtered by region, and extracted.
+func TestExecutor_Execute_Geo(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+	hldr := c.GetHolder(0)
+
+	index := hldr.MustCreateIndexIfNotExists(c.Idx(), pilosa.IndexOptions{TrackExistence: true})
+	if _, err := index.CreateFieldIfNotExists("f", "", pilosa.OptFieldTypeGeo()); err != nil {
+		t.Fatal(err)
+	}
+
+	query := func(q string) (pilosa.QueryResponse, error) {
+		return c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: q})
+	}
+
+	// Set points; the third moves column 1 from New York to Newark.
+	for _, q := range []string{
+		`Set(1, f=[40.7128, -74.0060])`,
+		`Set(2, f=[51.5074, -0.1278])`,
+		`Set(3, f=[-17.7134, 178.0650])`,
+		`Set(1, f=[40.7357, -74.1724])`,
+	} {
+		if _, err := query(q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+
+	for _, tt := range []struct {
+		q   string
+		exp []uint64
+	}{
+		{q: `Within(field=f, bbox=[40, -75, 41, -73])`, exp: []uint64{1}},
+		{q: `Within(field=f, bbox=[-90, -180, 90, 180])`, exp: []uint64{1, 2, 3}},
+		{q: `Within(field=f, bbox=[-20, 170, -10, -170])`, exp: []uint64{3}},
+		{q: `Near(field=f, lat=51.5, lon=-0.12, radius=10000)`, exp: []uint64{2}},
+		{q: `Near(field=f, lat=40.7128, lon=-74.0060, radius=5000)`, exp: []uint64{}},
+		{q: `Near(field=f, lat=40.7128, lon=-74.0060, radius=20000)`, exp: []uint64{1}},
+	} {
+		res, err := query(tt.q)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.q, err)
+		}
+		if cols := res.Results[0].(*pilosa.Row).Columns(); !reflect.DeepEqual(cols, tt.exp) {
+			t.Fatalf("%s: expected %v, got %v", tt.q, tt.exp, cols)
+		}
+	}
+
+	res, err := query(`Extract(ConstRow(columns=[1]), Rows(f))`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbl := res.Results[0].(pilosa.ExtractedTable)
+	if tbl.Fields[0].Type != "geo" || len(tbl.Columns) != 1 {
+		t.Fatalf("unexpected extract result: %+v", tbl)
+	}
+	p := tbl.Columns[0].Rows[0].(pilosa.GeoPoint)
+	if d := p.Distance(pilosa.GeoPoint{Lat: 40.7357, Lon: -74.1724}); d > 100 {
+		t.Fatalf("extracted point %v is %fm from the point set", p, d)
+	}
+
+	for _, q := range []string{
+		`Set(4, f=[95, 0])`,
+		`Set(4, f=1)`,
+		`Within(field=f, bbox=[41, -75, 40, -73])`,
+		`Near(field=f, lat=40, lon=-74)`,
+	} {
+		if _, err := query(q); err == nil {
+			t.Fatalf("%s: expected error", q)
+		}
+	}
+}
+
 // Ensure a set query can be executed on a decimal field.
 func TestExecutor_Execute_SetDecimal(t *testing.T) {
 	t.Run("Basic", func(t *testing.T) {
@@ -3755,6 +3827,127 @@ func TestExecutor_Execute_Remote_Row(t *testing.T) {
 		}
 	})
 
+	t.Run("groupBy on ints with bucket", func(t *testing.T) {
+		c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "bint", pilosa.OptFieldTypeInt(-1000, 1000))
+		c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "bset")
+		c.Query(t, c.Idx(), fmt.Sprintf(`
+		Set(0, bint=-7)
+		Set(1, bint=-3)
+		Set(2, bint=3)
+		Set(3, bint=9)
+		Set(%[1]d, bint=11)
+		Set(%[2]d, bint=25)
+		Set(%[3]d, bint=-10)
+		Set(0, bset=1)
+		Set(2, bset=1)
+		Set(%[1]d, bset=1)
+		`, ShardWidth, ShardWidth+1, 2*ShardWidth))
+
+		var a, b, d, e int64 = -10, 0, 10, 20
+		for _, tt := range []struct {
+			query    string
+			expected []pilosa.GroupCount
+		}{
+			{
+				query: `GroupBy(Rows(bint, bucket=10))`,
+				expected: []pilosa.GroupCount{
+					{Group: []pilosa.FieldRow{{Field: "bint", Value: &a}}, Count: 3},
+					{Group: []pilosa.FieldRow{{Field: "bint", Value: &b}}, Count: 2},
+					{Group: []pilosa.FieldRow{{Field: "bint", Value: &d}}, Count: 1},
+					{Group: []pilosa.FieldRow{{Field: "bint", Value: &e}}, Count: 1},
+				},
+			},
+			{
+				query: `GroupBy(Rows(bint, bucket=10), filter=Row(bint > 0))`,
+				expected: []pilosa.GroupCount{
+					{Group: []pilosa.FieldRow{{Field: "bint", Value: &b}}, Count: 2},
+					{Group: []pilosa.FieldRow{{Field: "bint", Value: &d}}, Count: 1},
+					{Group: []pilosa.FieldRow{{Field: "bint", Value: &e}}, Count: 1},
+				},
+			},
+			{
+				query: `GroupBy(Rows(bset), Rows(bint, bucket=10))`,
+				expected: []pilosa.GroupCount{
+					{Group: []pilosa.FieldRow{{Field: "bset", RowID: 1}, {Field: "bint", Value: &a}}, Count: 1},
+					{Group: []pilosa.FieldRow{{Field: "bset", RowID: 1}, {Field: "bint", Value: &b}}, Count: 1},
+					{Group: []pilosa.FieldRow{{Field: "bset", RowID: 1}, {Field: "bint", Value: &d}}, Count: 1},
+				},
+			},
+		} {
+			results := c.Query(t, c.Idx(), tt.query).Results[0].(*pilosa.GroupCounts).Groups()
+			test.CheckGroupBy(t, tt.expected, results)
+		}
+
+		for _, q := range []string{
+			`GroupBy(Rows(bset, bucket=10))`,
+			`GroupBy(Rows(bint, bucket=0))`,
+			`GroupBy(Rows(bint, bucket="day"))`,
+			`GroupBy(Rows(bint, bucket=10, limit=2))`,
+			`GroupBy(Rows(bint, bucket=10), aggregate=Count(Distinct(field=bint)))`,
+			`Rows(bint, bucket=10)`,
+		} {
+			if _, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: q}); err == nil {
+				t.Fatalf("expected error for %s", q)
+			}
+		}
+	})
+
+	t.Run("groupBy on timestamps with bucket", func(t *testing.T) {
+		c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "bts", pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds))
+		c.Query(t, c.Idx(), fmt.Sprintf(`
+		Set(0, bts='2021-01-27T08:00:00Z')
+		Set(1, bts='2021-01-27T23:59:59Z')
+		Set(2, bts='2021-01-28T00:00:00Z')
+		Set(%d, bts='2021-03-01T12:00:00Z')
+		Set(%d, bts='2020-12-31T23:00:00Z')
+		`, ShardWidth, ShardWidth+2))
+
+		unix := func(s string) *int64 {
+			ts, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v := ts.Unix()
+			return &v
+		}
+		for _, tt := range []struct {
+			bucket   string
+			expected []pilosa.GroupCount
+		}{
+			{
+				bucket: "day",
+				expected: []pilosa.GroupCount{
+					{Group: []pilosa.FieldRow{{Field: "bts", Value: unix("2020-12-31T00:00:00Z")}}, Count: 1},
+					{Group: []pilosa.FieldRow{{Field: "bts", Value: unix("2021-01-27T00:00:00Z")}}, Count: 2},
+					{Group: []pilosa.FieldRow{{Field: "bts", Value: unix("2021-01-28T00:00:00Z")}}, Count: 1},
+					{Group: []pilosa.FieldRow{{Field: "bts", Value: unix("2021-03-01T00:00:00Z")}}, Count: 1},
+				},
+			},
+			{
+				bucket: "month",
+				expected: []pilosa.GroupCount{
+					{Group: []pilosa.FieldRow{{Field: "bts", Value: unix("2020-12-01T00:00:00Z")}}, Count: 1},
+					{Group: []pilosa.FieldRow{{Field: "bts", Value: unix("2021-01-01T00:00:00Z")}}, Count: 3},
+					{Group: []pilosa.FieldRow{{Field: "bts", Value: unix("2021-03-01T00:00:00Z")}}, Count: 1},
+				},
+			},
+			{
+				bucket: "year",
+				expected: []pilosa.GroupCount{
+					{Group: []pilosa.FieldRow{{Field: "bts", Value: unix("2020-01-01T00:00:00Z")}}, Count: 1},
+					{Group: []pilosa.FieldRow{{Field: "bts", Value: unix("2021-01-01T00:00:00Z")}}, Count: 4},
+				},
+			},
+		} {
+			results := c.Query(t, c.Idx(), fmt.Sprintf(`GroupBy(Rows(bts, bucket=%q))`, tt.bucket)).Results[0].(*pilosa.GroupCounts).Groups()
+			test.CheckGroupBy(t, tt.expected, results)
+		}
+
+		if _, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: `GroupBy(Rows(bts, bucket="week"))`}); err == nil {
+			t.Fatalf("expected error for unknown bucket unit")
+		}
+	})
+
 	t.Run("Row on ints with ASSIGN condition", func(t *testing.T) {
 		_, err := c.GetPrimary().API.CreateIndex(context.Background(), c.Idx("intidx"), pilosa.IndexOptions{})
 		if err != nil {
@@ -4295,6 +4488,175 @@ func TestExecutor_Execute_Limit(t *testing.T) {
 	})
 }
 
+func TestExecutor_Execute_Sample(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "f")
+	var bits [][2]uint64
+	var columns []uint64
+	for shard := uint64(0); shard < 3; shard++ {
+		for i := uint64(0); i < 100*(shard+1); i++ {
+			bits = append(bits, [2]uint64{1, shard*ShardWidth + i})
+			columns = append(columns, shard*ShardWidth+i)
+		}
+	}
+	c.ImportBits(t, c.Idx(), "f", bits)
+
+	for _, tt := range []struct {
+		args   string
+		sample pilosa.SampleOptions
+	}{
+		{`n=50, seed=7`, pilosa.SampleOptions{N: 50, Seed: 7}},
+		{`n=50, seed=8`, pilosa.SampleOptions{N: 50, Seed: 8}},
+		{`n=1000, seed=7`, pilosa.SampleOptions{N: 1000, Seed: 7}},
+		{`fraction=0.25, seed=7`, pilosa.SampleOptions{Fraction: 0.25, Seed: 7}},
+		{`fraction=1, seed=7`, pilosa.SampleOptions{Fraction: 1, Seed: 7}},
+	} {
+		t.Run(tt.args, func(t *testing.T) {
+			// Sampling across shards must match sampling the whole row at once.
+			expect := tt.sample.Apply(pilosa.NewRow(columns...)).Columns()
+			row, ok := c.Query(t, c.Idx(), fmt.Sprintf("Sample(Row(f=1), %s)", tt.args)).Results[0].(*pilosa.Row)
+			if !ok {
+				t.Fatalf("expected a row result")
+			}
+			if got := row.Columns(); !reflect.DeepEqual(expect, got) {
+				t.Fatalf("expected %v but got %v", expect, got)
+			}
+			count := c.Query(t, c.Idx(), fmt.Sprintf("Count(Sample(Row(f=1), %s))", tt.args)).Results[0]
+			if count != uint64(len(expect)) {
+				t.Fatalf("expected count %d but got %v", len(expect), count)
+			}
+		})
+	}
+
+	t.Run("Unseeded", func(t *testing.T) {
+		row, ok := c.Query(t, c.Idx(), "Sample(Row(f=1), n=10)").Results[0].(*pilosa.Row)
+		if !ok {
+			t.Fatalf("expected a row result")
+		}
+		if n := row.Count(); n != 10 {
+			t.Fatalf("expected 10 columns but got %d", n)
+		}
+		if n := row.Intersect(pilosa.NewRow(columns...)).Count(); n != 10 {
+			t.Fatalf("expected sample to be a subset of the row")
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, q := range []string{
+			`Sample(Row(f=1))`,
+			`Sample(Row(f=1), n=1, fraction=0.5)`,
+			`Sample(Row(f=1), fraction=1.5)`,
+			`Sample(Row(f=1), fraction="half")`,
+			`Sample(n=1)`,
+		} {
+			if _, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: q}); err == nil {
+				t.Fatalf("expected error for %s", q)
+			}
+		}
+	})
+}
+
+func TestExecutor_Execute_Similar(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "f")
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "g")
+	c.CreateField(t, c.Idx(), pilosa.IndexOptions{TrackExistence: true}, "n", pilosa.OptFieldTypeInt(0, 100))
+	c.ImportBits(t, c.Idx(), "f", [][2]uint64{
+		{1, 0}, {1, 1}, {1, 2}, {1, 3}, {1, ShardWidth}, {1, ShardWidth + 1},
+		{2, 0}, {2, 1}, {2, 2}, {2, ShardWidth},
+		{3, 3}, {3, ShardWidth + 1}, {3, ShardWidth + 2}, {3, ShardWidth + 3}, {3, 2*ShardWidth + 5},
+		{4, 2*ShardWidth + 1}, {4, 2*ShardWidth + 2},
+		{5, 0},
+	})
+	c.ImportBits(t, c.Idx(), "g", [][2]uint64{
+		{1, 0}, {1, 1}, {1, ShardWidth + 1}, {1, ShardWidth + 2},
+	})
+
+	for _, tt := range []struct {
+		args string
+		rows []pilosa.SimilarRow
+	}{
+		{`row=1`, []pilosa.SimilarRow{
+			{ID: 2, Score: 4.0 / 6, Intersection: 4, Count: 4},
+			{ID: 3, Score: 2.0 / 9, Intersection: 2, Count: 5},
+			{ID: 5, Score: 1.0 / 6, Intersection: 1, Count: 1},
+		}},
+		{`row=1, metric="cosine"`, []pilosa.SimilarRow{
+			{ID: 2, Score: 4 / math.Sqrt(24), Intersection: 4, Count: 4},
+			{ID: 5, Score: 1 / math.Sqrt(6), Intersection: 1, Count: 1},
+			{ID: 3, Score: 2 / math.Sqrt(30), Intersection: 2, Count: 5},
+		}},
+		{`row=1, metric="overlap"`, []pilosa.SimilarRow{
+			{ID: 2, Score: 1, Intersection: 4, Count: 4},
+			{ID: 5, Score: 1, Intersection: 1, Count: 1},
+			{ID: 3, Score: 2.0 / 5, Intersection: 2, Count: 5},
+		}},
+		{`row=1, k=1`, []pilosa.SimilarRow{
+			{ID: 2, Score: 4.0 / 6, Intersection: 4, Count: 4},
+		}},
+		{`row=1, filter=Row(g=1)`, []pilosa.SimilarRow{
+			{ID: 2, Score: 2.0 / 3, Intersection: 2, Count: 2},
+			{ID: 5, Score: 1.0 / 3, Intersection: 1, Count: 1},
+			{ID: 3, Score: 1.0 / 4, Intersection: 1, Count: 2},
+		}},
+		{`row=4`, []pilosa.SimilarRow{}},
+	} {
+		t.Run(tt.args, func(t *testing.T) {
+			result, ok := c.Query(t, c.Idx(), fmt.Sprintf("Similar(field=f, %s)", tt.args)).Results[0].(*pilosa.SimilarResult)
+			if !ok {
+				t.Fatalf("expected similar result")
+			}
+			if result.Field != "f" {
+				t.Fatalf("expected field f, got %q", result.Field)
+			}
+			if !reflect.DeepEqual(result.Rows, tt.rows) {
+				t.Fatalf("expected %v, got %v", tt.rows, result.Rows)
+			}
+		})
+	}
+
+	t.Run("Keys", func(t *testing.T) {
+		c.CreateField(t, "similarkeys", pilosa.IndexOptions{TrackExistence: true, Keys: true}, "items", pilosa.OptFieldKeys())
+		c.Query(t, "similarkeys", `
+			Set("c1", items="a") Set("c2", items="a") Set("c3", items="a")
+			Set("c1", items="b") Set("c2", items="b")
+			Set("c3", items="c") Set("c4", items="c")
+		`)
+		result, ok := c.Query(t, "similarkeys", `Similar(field=items, row="a")`).Results[0].(*pilosa.SimilarResult)
+		if !ok {
+			t.Fatalf("expected similar result")
+		}
+		exp := []pilosa.SimilarRow{
+			{Key: "b", Score: 2.0 / 3, Intersection: 2, Count: 2},
+			{Key: "c", Score: 1.0 / 4, Intersection: 1, Count: 2},
+		}
+		if !reflect.DeepEqual(result.Rows, exp) {
+			t.Fatalf("expected %v, got %v", exp, result.Rows)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, q := range []string{
+			`Similar(field=f)`,
+			`Similar(row=1)`,
+			`Similar(field=f, row=1, metric="euclidean")`,
+			`Similar(field=nope, row=1)`,
+			`Similar(field=n, row=1)`,
+		} {
+			if _, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: c.Idx(), Query: q}); err == nil {
+				t.Fatalf("expected error for %s", q)
+			}
+		}
+		if _, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: "similarkeys", Query: `Similar(field=items, row="zzz")`}); err == nil {
+			t.Fatalf("expected error for missing row key")
+		}
+	})
+}
+
 func TestExecutor_Sort(t *testing.T) {
 	t.Run("Sort", func(t *testing.T) {
 		c := test.MustRunCluster(t, 1)
@@ -5832,6 +6194,26 @@ func TestExecutor_Execute_Rows_Keys(t *testing.T) {
 			q:   `Rows(f, like="__")`,
 			exp: []string{"10", "11", "12", "13", "14", "15", "16", "17", "18"},
 		},
+		{
+			q:   `Rows(f, regexp="^1[2-4]$")`,
+			exp: []string{"12", "13", "14"},
+		},
+		{
+			q:   `Rows(f, glob="1?")`,
+			exp: []string{"10", "11", "12", "13", "14", "15", "16", "17", "18"},
+		},
+		{
+			q:   `Rows(f, glob="[^1]")`,
+			exp: []string{"0", "2", "3", "4", "5", "6", "7", "8", "9"},
+		},
+		{
+			q:      `Rows(f, regexp="(")`,
+			expErr: "executing: ",
+		},
+		{
+			q:      `Rows(f, like="1%", glob="1*")`,
+			expErr: "executing: ",
+		},
 		{
 			q:      `Rows(f_id, like=7)`,
 			expErr: "parsing:",
@@ -7206,6 +7588,146 @@ func TestExecutor_BareDistinct(t *testing.T) {
 	}
 }
 
+func TestExecutor_Execute_ApproxCountDistinct(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+	indexName := c.Idx()
+
+	c.CreateField(t, indexName, pilosa.IndexOptions{TrackExistence: true}, "ints",
+		pilosa.OptFieldTypeInt(-math.MaxInt32, math.MaxInt32),
+	)
+	c.CreateField(t, indexName, pilosa.IndexOptions{TrackExistence: true}, "set")
+	c.CreateField(t, indexName, pilosa.IndexOptions{TrackExistence: true}, "filter")
+
+	// 500 distinct values, positive and negative, each set in two different
+	// shards so the per-shard sketches overlap.
+	var sb strings.Builder
+	for i := 0; i < 1000; i++ {
+		v := i % 500
+		if v%2 == 1 {
+			v = -v
+		}
+		col := (i%4)*ShardWidth + i
+		fmt.Fprintf(&sb, "Set(%d, ints=%d)\n", col, v)
+		fmt.Fprintf(&sb, "Set(%d, set=%d)\n", col, i%300)
+		if i < 100 {
+			fmt.Fprintf(&sb, "Set(%d, filter=1)\n", col)
+		}
+	}
+	c.Query(t, indexName, sb.String())
+
+	for _, tt := range []struct {
+		pql string
+		exp uint64
+		tol float64
+	}{
+		{`ApproxCountDistinct(field=ints)`, 500, 0.02},
+		{`ApproxCountDistinct(field="ints", precision=10)`, 500, 0.1},
+		{`ApproxCountDistinct(field=set)`, 300, 0.02},
+		{`ApproxCountDistinct(Row(filter=1), field=ints)`, 100, 0.02},
+		{`ApproxCountDistinct(Row(filter=2), field=set)`, 0, 0},
+	} {
+		t.Run(tt.pql, func(t *testing.T) {
+			got, ok := c.Query(t, indexName, tt.pql).Results[0].(uint64)
+			if !ok {
+				t.Fatalf("expected uint64 result")
+			}
+			if math.Abs(float64(got)-float64(tt.exp)) > float64(tt.exp)*tt.tol {
+				t.Fatalf("expected about %d, got %d", tt.exp, got)
+			}
+		})
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, q := range []string{
+			`ApproxCountDistinct()`,
+			`ApproxCountDistinct(field=ints, precision=2)`,
+			`ApproxCountDistinct(field=nope)`,
+		} {
+			if _, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: indexName, Query: q}); err == nil {
+				t.Fatalf("expected error for %s", q)
+			}
+		}
+	})
+}
+
+func TestExecutor_Execute_Funnel(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+	indexName := c.Idx()
+
+	c.CreateField(t, indexName, pilosa.IndexOptions{TrackExistence: true, Keys: true}, "event",
+		pilosa.OptFieldTypeTime(pilosa.TimeQuantum("YMD"), "0"),
+		pilosa.OptFieldKeys(),
+	)
+	c.CreateField(t, indexName, pilosa.IndexOptions{TrackExistence: true, Keys: true}, "plain", pilosa.OptFieldKeys())
+	c.Query(t, indexName, `
+		Set("u1", event="signup", 2022-01-01T00:00)
+		Set("u1", event="view", 2022-01-03T00:00)
+		Set("u1", event="buy", 2022-01-05T00:00)
+		Set("u2", event="signup", 2022-01-01T00:00)
+		Set("u2", event="view", 2022-01-02T00:00)
+		Set("u2", event="buy", 2022-01-10T00:00)
+		Set("u3", event="view", 2022-01-01T00:00)
+		Set("u3", event="signup", 2022-01-02T00:00)
+		Set("u3", event="buy", 2022-01-03T00:00)
+		Set("u4", event="signup", 2022-01-05T00:00)
+		Set("u5", event="signup", 2022-01-01T00:00)
+		Set("u5", event="view", 2022-01-01T00:00)
+		Set("u5", event="buy", 2022-01-01T00:00)
+		Set("u1", plain="x")
+	`)
+
+	steps := `Row(event="signup"), Row(event="view"), Row(event="buy")`
+	for _, tt := range []struct {
+		args   string
+		counts []uint64
+		keys   []string
+	}{
+		{`window="7d"`, []uint64{5, 3, 2}, []string{"u1", "u5"}},
+		{`window="168h", ordered=true`, []uint64{5, 3, 2}, []string{"u1", "u5"}},
+		{``, []uint64{5, 3, 3}, []string{"u1", "u2", "u5"}},
+		{`window="7d", ordered=false`, []uint64{5, 4, 3}, []string{"u1", "u3", "u5"}},
+		{`window="1d"`, []uint64{5, 1, 1}, []string{"u5"}},
+		{`window="7d", from="2022-01-02T00:00"`, []uint64{2, 0, 0}, nil},
+		{`window="7d", to="2022-01-04T00:00"`, []uint64{4, 3, 1}, []string{"u5"}},
+	} {
+		t.Run(tt.args, func(t *testing.T) {
+			q := fmt.Sprintf("Funnel(%s, %s)", steps, tt.args)
+			if tt.args == "" {
+				q = fmt.Sprintf("Funnel(%s)", steps)
+			}
+			result, ok := c.Query(t, indexName, q).Results[0].(*pilosa.FunnelResult)
+			if !ok {
+				t.Fatalf("expected funnel result")
+			}
+			if !reflect.DeepEqual(result.Counts, tt.counts) {
+				t.Fatalf("expected counts %v, got %v", tt.counts, result.Counts)
+			}
+			keys := result.Row.Keys
+			sort.Strings(keys)
+			if !reflect.DeepEqual(keys, tt.keys) {
+				t.Fatalf("expected columns %v, got %v", tt.keys, keys)
+			}
+		})
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, q := range []string{
+			`Funnel()`,
+			`Funnel(Row(event="signup"), Row(plain="x"))`,
+			`Funnel(Row(plain="x"))`,
+			`Funnel(Row(event="signup"), window="soon")`,
+			`Funnel(Row(event="signup", from="2022-01-01T00:00"))`,
+			`Funnel(Union(Row(event="signup")))`,
+		} {
+			if _, err := c.GetNode(0).API.Query(context.Background(), &pilosa.QueryRequest{Index: indexName, Query: q}); err == nil {
+				t.Fatalf("expected error for %s", q)
+			}
+		}
+	})
+}
+
 func TestExecutor_Execute_TopNDistinct(t *testing.T) {
 	data, err := os.ReadFile("testdata/schema.json")
 	if err != nil {
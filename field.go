@@ -47,6 +47,7 @@ const (
 	FieldTypeBool      = "bool"
 	FieldTypeDecimal   = "decimal"
 	FieldTypeTimestamp = "timestamp"
+	FieldTypeGeo       = "geo"
 )
 
 type protected struct {
@@ -377,6 +378,18 @@ func OptFieldTypeBool() FieldOption {
 	}
 }
 
+// OptFieldTypeGeo is a functional option on FieldOptions
+// used to specify the field as being type `geo`.
+func OptFieldTypeGeo() FieldOption {
+	return func(fo *FieldOptions) error {
+		if fo.Type != "" {
+			return errors.Errorf("field type is already set to: %s", fo.Type)
+		}
+		fo.Type = FieldTypeGeo
+		return nil
+	}
+}
+
 // OptFieldTrackExistence exists mostly to allow the
 // FieldFromFieldOptions/FieldOptionsFromField round-trip to work.
 // If you are actually creating a field, via api.CreateField,
@@ -883,6 +896,18 @@ func (f *Field) applyOptions(opt FieldOptions) error {
 		f.options.TTL = 0
 		f.options.Keys = false
 		f.options.ForeignIndex = ""
+	case FieldTypeGeo:
+		f.options.Type = FieldTypeGeo
+		f.options.CacheType = CacheTypeNone
+		f.options.CacheSize = 0
+		f.options.Min = pql.Decimal{}
+		f.options.Max = pql.Decimal{}
+		f.options.Base = 0
+		f.options.BitDepth = 0
+		f.options.TimeQuantum = ""
+		f.options.TTL = 0
+		f.options.Keys = false
+		f.options.ForeignIndex = ""
 	default:
 		return errors.New("invalid field type")
 	}
@@ -1109,6 +1134,43 @@ func (f *Field) viewsByTimeRange(from, to time.Time) (views []string, err error)
 	return viewsByTimeRange(viewStandard, from, to, q), nil
 }
 
+// finestTimeViews returns the time views of the field at the finest
+// granularity of its time quantum which overlap the range [from, to), in
+// time order, along with the time each begins. A zero from or to leaves
+// that end of the range open.
+func (f *Field) finestTimeViews(from, to time.Time) (views []string, starts []time.Time, err error) {
+	q := f.TimeQuantum()
+	if q == "" {
+		return nil, nil, fmt.Errorf("field %s is not a time-field", f.name)
+	}
+	n := lengthsByQuantum[q.Granularity()]
+	prefix := viewStandard + "_"
+	for _, v := range f.views() {
+		if !strings.HasPrefix(v.name, prefix) || len(v.name) != len(prefix)+n || viewTimePart(v.name) == "" {
+			continue
+		}
+		start, err := timeOfView(v.name, false)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "getting time of view: %s", v.name)
+		}
+		end, err := timeOfView(v.name, true)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "getting time of view: %s", v.name)
+		}
+		if (!from.IsZero() && !end.After(from)) || (!to.IsZero() && !start.Before(to)) {
+			continue
+		}
+		views = append(views, v.name)
+	}
+	// View names sort in time order.
+	sort.Strings(views)
+	starts = make([]time.Time, len(views))
+	for i, v := range views {
+		starts[i], _ = timeOfView(v, false)
+	}
+	return views, starts, nil
+}
+
 // RowTime gets the row at the particular time with the granularity specified by
 // the quantum.
 func (f *Field) RowTime(qcx *Qcx, rowID uint64, time time.Time, quantum string) (*Row, error) {
@@ -2354,7 +2416,7 @@ func (o *FieldOptions) MarshalJSON() ([]byte, error) {
 			o.CacheSize,
 			o.Keys,
 		})
-	case FieldTypeBool:
+	case FieldTypeBool, FieldTypeGeo:
 		return json.Marshal(struct {
 			Type string `json:"type"`
 		}{
@@ -2698,6 +2698,30 @@ func (f *fragment) intRowIterator(tx Tx, wrap bool, filters ...roaring.BitmapFil
 	return &it, nil
 }
 
+// bucketRowIterator is like intRowIterator, but it groups the values into
+// buckets. The bucket function must map each value to the value at the start
+// of its bucket, and must not decrease as the value increases.
+func (f *fragment) bucketRowIterator(tx Tx, wrap bool, bucket func(int64) int64, filters ...roaring.BitmapFilter) (rowIterator, error) {
+	iter, err := f.intRowIterator(tx, wrap, filters...)
+	if err != nil {
+		return nil, err
+	}
+	it := iter.(*intRowIterator)
+
+	values := make(int64Slice, 0, len(it.values))
+	colIDs := make(map[int64][]uint64)
+	for _, val := range it.values {
+		start := bucket(val)
+		if n := len(values); n == 0 || values[n-1] != start {
+			values = append(values, start)
+		}
+		colIDs[start] = append(colIDs[start], it.colIDs[val]...)
+	}
+	it.values, it.colIDs = values, colIDs
+
+	return it, nil
+}
+
 func (f *fragment) foreachRow(tx Tx, filters []roaring.BitmapFilter, fn func(rid uint64) error) error {
 	filter := roaring.NewBitmapRowFilter(fn, filters...)
 	err := tx.ApplyFilter(f.index(), f.field(), f.view(), f.shard, 0, filter)
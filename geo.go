@@ -0,0 +1,655 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/roaring"
+	"github.com/pkg/errors"
+)
+
+// A geo field stores one point per record as a set of rows in its standard
+// view. The globe is divided into a quadtree of latitude/longitude cells:
+// level 0 is the whole world, and each level splits every cell of the level
+// above into four. A point sets one row per level, the row of the cell
+// containing it, so a region query is the union of the rows of the cells
+// covering that region.
+//
+// The row ID of a cell is its Z-order (interleaved latitude and longitude)
+// index at its level, with a marker bit above it identifying the level. The
+// cells below a cell at the finest level are therefore a contiguous range of
+// row IDs.
+const (
+	// geoMaxLevel is the finest level indexed. Its cells are about 19m
+	// (latitude) by 38m (longitude, at the equator).
+	geoMaxLevel = 20
+
+	// geoMaxCoverCells caps the number of cells used to cover a region.
+	geoMaxCoverCells = 512
+
+	// geoEarthRadius is the mean radius of the earth in meters.
+	geoEarthRadius = 6371008.8
+)
+
+// GeoPoint is a location, in degrees, as stored in a geo field.
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// String returns the point as "lat,lon", the form accepted by ParseGeoPoint.
+func (p GeoPoint) String() string {
+	return strconv.FormatFloat(p.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(p.Lon, 'f', -1, 64)
+}
+
+// Validate returns an error if the point is not a valid location.
+func (p GeoPoint) Validate() error {
+	if math.IsNaN(p.Lat) || p.Lat < -90 || p.Lat > 90 {
+		return errors.Errorf("latitude out of range: %v", p.Lat)
+	}
+	if math.IsNaN(p.Lon) || p.Lon < -180 || p.Lon > 180 {
+		return errors.Errorf("longitude out of range: %v", p.Lon)
+	}
+	return nil
+}
+
+// ParseGeoPoint parses a point of the form "lat,lon".
+func ParseGeoPoint(s string) (GeoPoint, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return GeoPoint{}, errors.Errorf("invalid point %q: expected lat,lon", s)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return GeoPoint{}, errors.Errorf("invalid latitude in point %q", s)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return GeoPoint{}, errors.Errorf("invalid longitude in point %q", s)
+	}
+	p := GeoPoint{Lat: lat, Lon: lon}
+	return p, p.Validate()
+}
+
+// GeoPointFromValue converts a point, a "lat,lon" string, or a [lat, lon]
+// list of numbers to a point.
+func GeoPointFromValue(v interface{}) (GeoPoint, error) {
+	switch v := v.(type) {
+	case GeoPoint:
+		return v, v.Validate()
+	case string:
+		return ParseGeoPoint(v)
+	default:
+		return geoPointArg(v)
+	}
+}
+
+// Distance returns the great-circle distance in meters between p and q.
+func (p GeoPoint) Distance(q GeoPoint) float64 {
+	return geoDistance(p, q)
+}
+
+// Within reports whether p lies in the given bounding box. A box whose
+// minimum longitude is greater than its maximum longitude crosses the
+// antimeridian.
+func (p GeoPoint) Within(minLat, minLon, maxLat, maxLon float64) bool {
+	return geoBox{minLat: minLat, minLon: minLon, maxLat: maxLat, maxLon: maxLon}.containsPoint(p)
+}
+
+// geoPointArg converts a [lat, lon] PQL list to a point.
+func geoPointArg(v interface{}) (GeoPoint, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		coords, err := geoFloats(v, 2)
+		if err != nil {
+			return GeoPoint{}, err
+		}
+		p := GeoPoint{Lat: coords[0], Lon: coords[1]}
+		return p, p.Validate()
+	default:
+		return GeoPoint{}, errors.Errorf("invalid point: %v", v)
+	}
+}
+
+// geoFloats converts a list of n PQL numbers to float64s.
+func geoFloats(vals []interface{}, n int) ([]float64, error) {
+	if len(vals) != n {
+		return nil, errors.Errorf("expected %d coordinates, got %d", n, len(vals))
+	}
+	fs := make([]float64, n)
+	for i, v := range vals {
+		f, err := geoFloat(v)
+		if err != nil {
+			return nil, err
+		}
+		fs[i] = f
+	}
+	return fs, nil
+}
+
+// geoFloat converts a PQL number to a float64.
+func geoFloat(v interface{}) (float64, error) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case pql.Decimal:
+		return v.Float64(), nil
+	default:
+		return 0, errors.Errorf("invalid coordinate: %v", v)
+	}
+}
+
+// geoCell is a cell of the quadtree; lat and lon are the indexes of the cell
+// along each axis at its level.
+type geoCell struct {
+	level    int
+	lat, lon uint64
+}
+
+// geoCellOf returns the cell containing p at the given level.
+func geoCellOf(p GeoPoint, level int) geoCell {
+	n := float64(uint64(1) << level)
+	lat := uint64((p.Lat + 90) / 180 * n)
+	lon := uint64((p.Lon + 180) / 360 * n)
+	// The northern and eastern edges belong to the last cell.
+	if max := uint64(1)<<level - 1; lat > max {
+		lat = max
+	}
+	if max := uint64(1)<<level - 1; lon > max {
+		lon = max
+	}
+	return geoCell{level: level, lat: lat, lon: lon}
+}
+
+// rowID returns the row ID of the cell.
+func (c geoCell) rowID() uint64 {
+	var z uint64
+	for i := 0; i < c.level; i++ {
+		z |= (c.lon>>i&1)<<(2*i) | (c.lat>>i&1)<<(2*i+1)
+	}
+	return 1<<(2*c.level) | z
+}
+
+// geoCellFromRowID is the inverse of geoCell.rowID.
+func geoCellFromRowID(rowID uint64) (geoCell, error) {
+	n := bits.Len64(rowID) - 1
+	if n < 0 || n%2 != 0 || n/2 > geoMaxLevel {
+		return geoCell{}, errors.Errorf("invalid geo row ID: %d", rowID)
+	}
+	c := geoCell{level: n / 2}
+	for i := 0; i < c.level; i++ {
+		c.lon |= (rowID >> (2 * i) & 1) << i
+		c.lat |= (rowID >> (2*i + 1) & 1) << i
+	}
+	return c, nil
+}
+
+// rect returns the bounds of the cell.
+func (c geoCell) rect() geoRect {
+	n := float64(uint64(1) << c.level)
+	return geoRect{
+		minLat: float64(c.lat)/n*180 - 90,
+		maxLat: float64(c.lat+1)/n*180 - 90,
+		minLon: float64(c.lon)/n*360 - 180,
+		maxLon: float64(c.lon+1)/n*360 - 180,
+	}
+}
+
+// center returns the center of the cell.
+func (c geoCell) center() GeoPoint {
+	r := c.rect()
+	return GeoPoint{Lat: (r.minLat + r.maxLat) / 2, Lon: (r.minLon + r.maxLon) / 2}
+}
+
+// children returns the four cells one level below the cell.
+func (c geoCell) children() [4]geoCell {
+	lat, lon := c.lat<<1, c.lon<<1
+	return [4]geoCell{
+		{level: c.level + 1, lat: lat, lon: lon},
+		{level: c.level + 1, lat: lat, lon: lon + 1},
+		{level: c.level + 1, lat: lat + 1, lon: lon},
+		{level: c.level + 1, lat: lat + 1, lon: lon + 1},
+	}
+}
+
+// leafRange returns the range [start, end) of the row IDs of the cells at
+// geoMaxLevel within the cell.
+func (c geoCell) leafRange() (start, end uint64) {
+	shift := 2 * (geoMaxLevel - c.level)
+	z := c.rowID() &^ (1 << (2 * c.level))
+	base := uint64(1) << (2 * geoMaxLevel)
+	return base | z<<shift, base | (z+1)<<shift
+}
+
+// geoRowIDs returns the rows to set for a point: one per level.
+func geoRowIDs(p GeoPoint) []uint64 {
+	rowIDs := make([]uint64, geoMaxLevel+1)
+	for level := range rowIDs {
+		rowIDs[level] = geoCellOf(p, level).rowID()
+	}
+	return rowIDs
+}
+
+// GeoRowIDs returns the row IDs a geo field stores for p. Importers use it
+// to write geo fields in bulk.
+func GeoRowIDs(p GeoPoint) ([]uint64, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return geoRowIDs(p), nil
+}
+
+// geoPointFromRowIDs returns the stored location of a record given the rows
+// it has set in a geo field: the center of its finest cell.
+func geoPointFromRowIDs(rowIDs []uint64) (GeoPoint, bool, error) {
+	var max uint64
+	for _, id := range rowIDs {
+		if id > max {
+			max = id
+		}
+	}
+	if max == 0 {
+		return GeoPoint{}, false, nil
+	}
+	c, err := geoCellFromRowID(max)
+	if err != nil {
+		return GeoPoint{}, false, err
+	}
+	return c.center(), true, nil
+}
+
+// geoRect is a latitude/longitude rectangle which does not cross the
+// antimeridian.
+type geoRect struct {
+	minLat, minLon, maxLat, maxLon float64
+}
+
+// geoRegion is an area which can be covered with cells.
+type geoRegion interface {
+	// relate reports whether r lies entirely within the region, and
+	// whether any of r lies within the region.
+	relate(r geoRect) (contains, intersects bool)
+
+	// containsPoint reports whether p lies within the region.
+	containsPoint(p GeoPoint) bool
+}
+
+// geoBox is a bounding box region. A box whose minimum longitude is greater
+// than its maximum longitude crosses the antimeridian.
+type geoBox struct {
+	minLat, minLon, maxLat, maxLon float64
+}
+
+// newGeoBox returns a box from a [minLat, minLon, maxLat, maxLon] list.
+func newGeoBox(vals []interface{}) (geoBox, error) {
+	fs, err := geoFloats(vals, 4)
+	if err != nil {
+		return geoBox{}, errors.Wrap(err, "bounding box must be [minLat, minLon, maxLat, maxLon]")
+	}
+	b := geoBox{minLat: fs[0], minLon: fs[1], maxLat: fs[2], maxLon: fs[3]}
+	for _, p := range []GeoPoint{{Lat: b.minLat, Lon: b.minLon}, {Lat: b.maxLat, Lon: b.maxLon}} {
+		if err := p.Validate(); err != nil {
+			return geoBox{}, errors.Wrap(err, "invalid bounding box")
+		}
+	}
+	if b.minLat > b.maxLat {
+		return geoBox{}, errors.New("invalid bounding box: minimum latitude is greater than maximum latitude")
+	}
+	return b, nil
+}
+
+func (b geoBox) lonRanges() [][2]float64 {
+	if b.minLon <= b.maxLon {
+		return [][2]float64{{b.minLon, b.maxLon}}
+	}
+	return [][2]float64{{b.minLon, 180}, {-180, b.maxLon}}
+}
+
+func (b geoBox) relate(r geoRect) (contains, intersects bool) {
+	if r.maxLat < b.minLat || r.minLat > b.maxLat {
+		return false, false
+	}
+	latContained := r.minLat >= b.minLat && r.maxLat <= b.maxLat
+	for _, lons := range b.lonRanges() {
+		if r.maxLon < lons[0] || r.minLon > lons[1] {
+			continue
+		}
+		intersects = true
+		if latContained && r.minLon >= lons[0] && r.maxLon <= lons[1] {
+			contains = true
+		}
+	}
+	return contains, intersects
+}
+
+func (b geoBox) containsPoint(p GeoPoint) bool {
+	if p.Lat < b.minLat || p.Lat > b.maxLat {
+		return false
+	}
+	for _, lons := range b.lonRanges() {
+		if p.Lon >= lons[0] && p.Lon <= lons[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// geoCircle is the region within radius meters of center, measured along
+// the surface of the earth.
+type geoCircle struct {
+	center GeoPoint
+	radius float64
+}
+
+func (c geoCircle) relate(r geoRect) (contains, intersects bool) {
+	if geoDistanceToRect(c.center, r) > c.radius {
+		return false, false
+	}
+	// For rectangles spanning at most half the globe, the farthest point
+	// from the center is one of the corners.
+	if r.maxLon-r.minLon > 180 {
+		return false, true
+	}
+	for _, corner := range []GeoPoint{
+		{Lat: r.minLat, Lon: r.minLon},
+		{Lat: r.minLat, Lon: r.maxLon},
+		{Lat: r.maxLat, Lon: r.minLon},
+		{Lat: r.maxLat, Lon: r.maxLon},
+	} {
+		if geoDistance(c.center, corner) > c.radius {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+func (c geoCircle) containsPoint(p GeoPoint) bool {
+	return geoDistance(c.center, p) <= c.radius
+}
+
+func geoRadians(deg float64) float64 { return deg * math.Pi / 180 }
+
+// geoDistance returns the great-circle distance in meters between a and b.
+func geoDistance(a, b GeoPoint) float64 {
+	lat1, lat2 := geoRadians(a.Lat), geoRadians(b.Lat)
+	dLat, dLon := lat2-lat1, geoRadians(b.Lon-a.Lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * geoEarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// geoDistanceToRect returns the distance in meters from p to the nearest
+// point of r, which is zero if p lies within r.
+func geoDistanceToRect(p GeoPoint, r geoRect) float64 {
+	lonInside := p.Lon >= r.minLon && p.Lon <= r.maxLon
+	if lonInside && p.Lat >= r.minLat && p.Lat <= r.maxLat {
+		return 0
+	}
+
+	// Along a parallel, the nearest point is the one nearest in longitude.
+	lon := p.Lon
+	if !lonInside {
+		lon = r.minLon
+		if math.Abs(geoLonDiff(p.Lon, r.maxLon)) < math.Abs(geoLonDiff(p.Lon, r.minLon)) {
+			lon = r.maxLon
+		}
+	}
+	d := math.Min(geoDistance(p, GeoPoint{Lat: r.minLat, Lon: lon}), geoDistance(p, GeoPoint{Lat: r.maxLat, Lon: lon}))
+
+	// Along a meridian, the distance has a single minimum, at the latitude
+	// computed below, so the nearest point is that latitude clamped to
+	// the edge.
+	phi := geoRadians(p.Lat)
+	for _, edge := range []float64{r.minLon, r.maxLon} {
+		lat := math.Atan2(math.Sin(phi), math.Cos(phi)*math.Cos(geoRadians(geoLonDiff(p.Lon, edge)))) * 180 / math.Pi
+		lat = math.Max(r.minLat, math.Min(r.maxLat, lat))
+		d = math.Min(d, geoDistance(p, GeoPoint{Lat: lat, Lon: edge}))
+	}
+	return d
+}
+
+// geoLonDiff returns the signed difference b-a in longitude, in (-180, 180].
+func geoLonDiff(a, b float64) float64 {
+	d := math.Mod(b-a, 360)
+	if d > 180 {
+		d -= 360
+	} else if d <= -180 {
+		d += 360
+	}
+	return d
+}
+
+// geoCover returns the cells covering region. Cells entirely within the
+// region are returned as inside; cells on its edge as boundary. The covering
+// descends the quadtree until it reaches geoMaxLevel or would use more than
+// maxCells cells.
+func geoCover(region geoRegion, maxCells int) (inside, boundary []geoCell) {
+	cells := []geoCell{{}}
+	for {
+		var partial []geoCell
+		for _, c := range cells {
+			contains, intersects := region.relate(c.rect())
+			if contains {
+				inside = append(inside, c)
+			} else if intersects {
+				partial = append(partial, c)
+			}
+		}
+		if len(partial) == 0 {
+			return inside, nil
+		}
+		if partial[0].level == geoMaxLevel || len(inside)+4*len(partial) > maxCells {
+			return inside, partial
+		}
+		cells = cells[:0:0]
+		for _, c := range partial {
+			children := c.children()
+			cells = append(cells, children[:]...)
+		}
+	}
+}
+
+// geoRegionArgs reads the region of a Within() or Near() call.
+func geoRegionArgs(c *pql.Call) (geoRegion, error) {
+	switch c.Name {
+	case "Within":
+		v, ok := c.Args["bbox"]
+		if !ok {
+			return nil, errors.New("Within() argument required: bbox")
+		}
+		vals, ok := v.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("Within() bbox must be a list, got %v", v)
+		}
+		return newGeoBox(vals)
+	case "Near":
+		var coords [3]float64
+		for i, name := range []string{"lat", "lon", "radius"} {
+			v, ok := c.Args[name]
+			if !ok {
+				return nil, errors.Errorf("Near() argument required: %s", name)
+			}
+			f, err := geoFloat(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading Near() %s", name)
+			}
+			coords[i] = f
+		}
+		center := GeoPoint{Lat: coords[0], Lon: coords[1]}
+		if err := center.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid Near() center")
+		}
+		if coords[2] < 0 {
+			return nil, errors.New("Near() radius must not be negative")
+		}
+		return geoCircle{center: center, radius: coords[2]}, nil
+	default:
+		return nil, errors.Errorf("not a geo call: %s", c.Name)
+	}
+}
+
+// geoRows returns the columns of the fragment (which belongs to a geo field)
+// located within region.
+//
+// Columns in cells entirely inside the region are found with a single union.
+// For each cell on the boundary of the region, the finest-level rows within
+// it are listed, and those whose centers lie within the region are added.
+// The result is therefore exact to the resolution of geoMaxLevel.
+func (f *fragment) geoRows(ctx context.Context, tx Tx, region geoRegion) (*Row, error) {
+	inside, boundary := geoCover(region, geoMaxCoverCells)
+	rowIDs := make([]uint64, 0, len(inside))
+	for _, c := range inside {
+		rowIDs = append(rowIDs, c.rowID())
+	}
+	for _, c := range boundary {
+		start, end := c.leafRange()
+		leaves, err := f.rowsInRange(ctx, tx, start, end)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing cells")
+		}
+		for _, id := range leaves {
+			leaf, err := geoCellFromRowID(id)
+			if err != nil {
+				return nil, err
+			}
+			if region.containsPoint(leaf.center()) {
+				rowIDs = append(rowIDs, id)
+			}
+		}
+	}
+	if len(rowIDs) == 0 {
+		return NewRow(), nil
+	}
+	return f.unionRows(ctx, tx, rowIDs)
+}
+
+// rowsInRange returns the IDs of the rows in [start, end) which have any
+// bits set.
+func (f *fragment) rowsInRange(ctx context.Context, tx Tx, start, end uint64) ([]uint64, error) {
+	var rows []uint64
+	filter := &rowRangeFilter{
+		BitmapRowFilterBase: roaring.NewBitmapRowFilterBase(func(row uint64) error {
+			rows = append(rows, row)
+			return nil
+		}),
+		end: end,
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if err := tx.ApplyFilter(f.index(), f.field(), f.view(), f.shard, rowToKey(start), filter); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// rowRangeFilter is a row filter which stops at the row end.
+type rowRangeFilter struct {
+	*roaring.BitmapRowFilterBase
+	end uint64
+}
+
+func (r *rowRangeFilter) ConsiderKey(key roaring.FilterKey, n int32) roaring.FilterResult {
+	if key.Row() >= r.end {
+		return key.Done()
+	}
+	return r.BitmapRowFilterBase.ConsiderKey(key, n)
+}
+
+// setGeoPoint replaces the point stored for a column.
+func (v *view) setGeoPoint(qcx *Qcx, columnID uint64, p GeoPoint) (changed bool, err error) {
+	shard := columnID / ShardWidth
+	tx, finisher, err := qcx.GetTx(Txo{Write: true, Index: v.idx, Shard: shard})
+	defer finisher(&err)
+	frag, err := v.CreateFragmentIfNotExists(shard)
+	if err != nil {
+		return false, err
+	}
+
+	// A column holds a single point, so if its finest cell is already set,
+	// so is every other row for the point.
+	rowIDs := geoRowIDs(p)
+	if set, err := frag.bit(tx, rowIDs[geoMaxLevel], columnID); err != nil {
+		return false, errors.Wrap(err, "reading current point")
+	} else if set {
+		return false, nil
+	}
+	if _, err := frag.ClearRecords(tx, []uint64{columnID}); err != nil {
+		return false, errors.Wrap(err, "clearing current point")
+	}
+	for _, rowID := range rowIDs {
+		if _, err := frag.setBit(tx, rowID, columnID); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// SetGeoPoint stores p as the location of a column in a geo field,
+// replacing any location the column had.
+func (f *Field) SetGeoPoint(qcx *Qcx, colID uint64, p GeoPoint) (changed bool, err error) {
+	if f.Type() != FieldTypeGeo {
+		return false, errors.Errorf("field %s is not a geo field", f.Name())
+	}
+	if err := p.Validate(); err != nil {
+		return false, err
+	}
+	view, err := f.createViewIfNotExists(viewStandard)
+	if err != nil {
+		return false, errors.Wrap(err, "creating view")
+	}
+	if changed, err = view.setGeoPoint(qcx, colID, p); err != nil {
+		return false, errors.Wrap(err, "setting point on view")
+	}
+	if f.options.TrackExistence {
+		view, err := f.createViewIfNotExists(viewExistence)
+		if err != nil {
+			return changed, errors.Wrap(err, "creating existence view")
+		}
+		if _, err := view.setBit(qcx, bsiExistsBit, colID); err != nil {
+			return changed, errors.Wrap(err, "setting existence on view")
+		}
+	}
+	return changed, nil
+}
+
+// executeGeoShard executes a Within() or Near() call for a shard.
+func (e *executor) executeGeoShard(ctx context.Context, qcx *Qcx, index string, c *pql.Call, shard uint64) (_ *Row, err0 error) {
+	fieldName, ok, err := c.StringArg("field")
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s() field", c.Name)
+	} else if !ok {
+		return nil, fmt.Errorf("%s() argument required: field", c.Name)
+	}
+	f := e.Holder.Field(index, fieldName)
+	if f == nil {
+		return nil, newNotFoundError(ErrFieldNotFound, fieldName)
+	}
+	if f.Type() != FieldTypeGeo {
+		return nil, errors.Errorf("%s() is not supported on %s fields", c.Name, f.Type())
+	}
+	region, err := geoRegionArgs(c)
+	if err != nil {
+		return nil, err
+	}
+
+	frag := e.Holder.fragment(index, fieldName, viewStandard, shard)
+	if frag == nil {
+		return NewRow(), nil
+	}
+	tx, finisher, err := qcx.GetTx(Txo{Write: !writable, Index: f.idx, Shard: shard})
+	if err != nil {
+		return nil, err
+	}
+	defer finisher(&err0)
+	return frag.geoRows(ctx, tx, region)
+}
@@ -0,0 +1,98 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"math"
+	"testing"
+)
+
+// Ensure cells can be recovered from their row IDs, and that each cell
+// contains the point it was computed from.
+func TestGeoCell_RowID(t *testing.T) {
+	for _, p := range []GeoPoint{
+		{Lat: 0, Lon: 0},
+		{Lat: 40.7128, Lon: -74.0060},
+		{Lat: -90, Lon: -180},
+		{Lat: 90, Lon: 180},
+	} {
+		ids := geoRowIDs(p)
+		if len(ids) != geoMaxLevel+1 {
+			t.Fatalf("expected %d rows for %v, got %d", geoMaxLevel+1, p, len(ids))
+		}
+		for level, id := range ids {
+			c, err := geoCellFromRowID(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c != geoCellOf(p, level) {
+				t.Fatalf("row %d: expected %+v, got %+v", id, geoCellOf(p, level), c)
+			}
+			if r := c.rect(); p.Lat < r.minLat || p.Lat > r.maxLat || p.Lon < r.minLon || p.Lon > r.maxLon {
+				t.Fatalf("cell %+v does not contain %v", c, p)
+			}
+		}
+		q, ok, err := geoPointFromRowIDs(ids)
+		if err != nil || !ok {
+			t.Fatalf("recovering point: %v, %v", ok, err)
+		}
+		if d := geoDistance(p, q); d > 50 {
+			t.Fatalf("recovered point %v is %fm from %v", q, d, p)
+		}
+	}
+
+	if _, err := geoCellFromRowID(0); err == nil {
+		t.Fatal("expected error for row 0")
+	}
+}
+
+// Ensure known distances are computed correctly.
+func TestGeoDistance(t *testing.T) {
+	nyc, london := GeoPoint{Lat: 40.7128, Lon: -74.0060}, GeoPoint{Lat: 51.5074, Lon: -0.1278}
+	if d := geoDistance(nyc, london); math.Abs(d-5570e3) > 10e3 {
+		t.Fatalf("unexpected distance: %f", d)
+	}
+	// across the antimeridian
+	if d := geoDistance(GeoPoint{Lat: 0, Lon: 179.5}, GeoPoint{Lat: 0, Lon: -179.5}); math.Abs(d-111195) > 100 {
+		t.Fatalf("unexpected distance: %f", d)
+	}
+}
+
+// Ensure a covering includes every cell containing a point in the region,
+// and no cell outside of it.
+func TestGeoCover(t *testing.T) {
+	for _, region := range []geoRegion{
+		geoBox{minLat: 40, minLon: -75, maxLat: 41, maxLon: -73},
+		geoBox{minLat: -20, minLon: 170, maxLat: -10, maxLon: -170},
+		geoCircle{center: GeoPoint{Lat: 51.5, Lon: -0.12}, radius: 10000},
+		geoCircle{center: GeoPoint{Lat: 89.9, Lon: 0}, radius: 50000},
+	} {
+		inside, boundary := geoCover(region, geoMaxCoverCells)
+		if len(inside)+len(boundary) > 4*geoMaxCoverCells {
+			t.Fatalf("%+v: covering too large: %d cells", region, len(inside)+len(boundary))
+		}
+		for _, c := range inside {
+			if !region.containsPoint(c.center()) {
+				t.Fatalf("%+v: inside cell %+v is not in region", region, c)
+			}
+		}
+		covered := func(p GeoPoint) bool {
+			for _, cells := range [][]geoCell{inside, boundary} {
+				for _, c := range cells {
+					if geoCellOf(p, c.level) == c {
+						return true
+					}
+				}
+			}
+			return false
+		}
+		for lat := -90.0; lat <= 90; lat += 0.25 {
+			for lon := -180.0; lon <= 180; lon += 0.25 {
+				p := GeoPoint{Lat: lat, Lon: lon}
+				if region.containsPoint(p) && !covered(p) {
+					t.Fatalf("%+v: point %v is not covered", region, p)
+				}
+			}
+		}
+	}
+}
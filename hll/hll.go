@@ -0,0 +1,167 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+
+// Package hll implements HyperLogLog sketches for estimating the number of
+// distinct values in a set without holding the values themselves. Sketches
+// of the same precision built over disjoint parts of a set (the shards of an
+// index, for example) can be merged into a sketch of the whole set.
+package hll
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+
+	"github.com/cespare/xxhash"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MinPrecision and MaxPrecision bound the precision of a sketch. A sketch
+	// of precision p has 2^p one byte registers, and a standard error of
+	// about 1.04/sqrt(2^p).
+	MinPrecision = 4
+	MaxPrecision = 18
+
+	// DefaultPrecision gives a standard error of about 0.8% in 16KB.
+	DefaultPrecision = 14
+)
+
+// ErrPrecisionMismatch is returned when merging sketches of different
+// precision.
+var ErrPrecisionMismatch = errors.New("cannot merge sketches of different precision")
+
+// Sketch is a HyperLogLog sketch.
+type Sketch struct {
+	precision uint8
+	registers []uint8
+}
+
+// New returns an empty sketch of the given precision.
+func New(precision int) (*Sketch, error) {
+	if precision < MinPrecision || precision > MaxPrecision {
+		return nil, errors.Errorf("precision must be between %d and %d: %d", MinPrecision, MaxPrecision, precision)
+	}
+	return &Sketch{
+		precision: uint8(precision),
+		registers: make([]uint8, 1<<precision),
+	}, nil
+}
+
+// Precision returns the precision of the sketch.
+func (s *Sketch) Precision() int {
+	return int(s.precision)
+}
+
+// Add adds a hashed value to the sketch. The hash should be uniformly
+// distributed over 64 bits; AddUint64, AddInt64 and AddString hash their
+// values before adding them.
+func (s *Sketch) Add(hash uint64) {
+	// the top precision bits choose the register, and the remaining bits
+	// give the rank: the position of their first set bit.
+	idx := hash >> (64 - s.precision)
+	rank := uint8(bits.LeadingZeros64(hash<<s.precision)) + 1
+	if max := 64 - s.precision + 1; rank > max {
+		rank = max
+	}
+	if rank > s.registers[idx] {
+		s.registers[idx] = rank
+	}
+}
+
+// AddUint64 hashes and adds v to the sketch.
+func (s *Sketch) AddUint64(v uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	s.Add(xxhash.Sum64(buf[:]))
+}
+
+// AddInt64 hashes and adds v to the sketch.
+func (s *Sketch) AddInt64(v int64) {
+	s.AddUint64(uint64(v))
+}
+
+// AddString hashes and adds v to the sketch.
+func (s *Sketch) AddString(v string) {
+	s.Add(xxhash.Sum64String(v))
+}
+
+// Merge merges other into s, so that s estimates the number of distinct
+// values added to either sketch.
+func (s *Sketch) Merge(other *Sketch) error {
+	if other == nil {
+		return nil
+	}
+	if s.precision != other.precision {
+		return ErrPrecisionMismatch
+	}
+	for i, r := range other.registers {
+		if r > s.registers[i] {
+			s.registers[i] = r
+		}
+	}
+	return nil
+}
+
+// Count returns the estimated number of distinct values added to the sketch.
+func (s *Sketch) Count() uint64 {
+	m := float64(len(s.registers))
+
+	var sum float64
+	var zeros int
+	for _, r := range s.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+	estimate := alpha(len(s.registers)) * m * m / sum
+
+	// small cardinalities are estimated better by linear counting over the
+	// empty registers. With 64 bit hashes there is no need for a large range
+	// correction.
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
+
+// alpha returns the bias correction constant for m registers.
+func alpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// MarshalBinary encodes the sketch as its precision followed by its
+// registers.
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 1+len(s.registers))
+	buf[0] = s.precision
+	copy(buf[1:], s.registers)
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a sketch encoded by MarshalBinary.
+func (s *Sketch) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("empty sketch")
+	}
+	precision := int(data[0])
+	if precision < MinPrecision || precision > MaxPrecision {
+		return errors.Errorf("invalid sketch precision: %d", precision)
+	}
+	if len(data)-1 != 1<<precision {
+		return errors.Errorf("sketch of precision %d has %d registers", precision, len(data)-1)
+	}
+	s.precision = uint8(precision)
+	s.registers = make([]uint8, len(data)-1)
+	copy(s.registers, data[1:])
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package hll_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/featurebasedb/featurebase/v3/hll"
+)
+
+func TestSketch_Count(t *testing.T) {
+	for _, n := range []uint64{0, 1, 10, 1000, 100000, 1000000} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			s, err := hll.New(hll.DefaultPrecision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// add every value twice; duplicates must not count
+			for i := uint64(0); i < n; i++ {
+				s.AddUint64(i)
+				s.AddUint64(i)
+			}
+			if got := s.Count(); math.Abs(float64(got)-float64(n)) > float64(n)*0.03 {
+				t.Fatalf("expected about %d, got %d", n, got)
+			}
+		})
+	}
+}
+
+func TestSketch_Merge(t *testing.T) {
+	a, _ := hll.New(12)
+	b, _ := hll.New(12)
+	for i := int64(0); i < 20000; i++ {
+		a.AddInt64(i)
+		b.AddInt64(i + 10000)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.Count(); math.Abs(float64(got)-30000) > 30000*0.05 {
+		t.Fatalf("expected about 30000, got %d", got)
+	}
+
+	c, _ := hll.New(14)
+	if err := a.Merge(c); err != hll.ErrPrecisionMismatch {
+		t.Fatalf("expected precision mismatch, got %v", err)
+	}
+}
+
+func TestSketch_Precision(t *testing.T) {
+	if _, err := hll.New(hll.MinPrecision - 1); err == nil {
+		t.Fatal("expected error for precision below minimum")
+	}
+	if _, err := hll.New(hll.MaxPrecision + 1); err == nil {
+		t.Fatal("expected error for precision above maximum")
+	}
+}
+
+func TestSketch_MarshalBinary(t *testing.T) {
+	s, _ := hll.New(10)
+	for i := 0; i < 5000; i++ {
+		s.AddString(fmt.Sprintf("key%d", i))
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var other hll.Sketch
+	if err := other.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	} else if other.Precision() != 10 {
+		t.Fatalf("expected precision 10, got %d", other.Precision())
+	} else if other.Count() != s.Count() {
+		t.Fatalf("expected count %d, got %d", s.Count(), other.Count())
+	}
+
+	if err := other.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Fatal("expected error for truncated sketch")
+	}
+}
@@ -257,6 +257,7 @@ type HolderConfig struct {
 
 	StorageConfig *storage.Config
 	RBFConfig     *rbfcfg.Config
+	ChangeCapture ChangeCaptureConfig
 
 	LookupDBDSN string
 }
@@ -280,6 +281,7 @@ func DefaultHolderConfig() *HolderConfig {
 		Logger:               logger.NopLogger,
 		StorageConfig:        storage.NewDefaultConfig(),
 		RBFConfig:            rbfcfg.NewDefaultConfig(),
+		ChangeCapture:        NewDefaultChangeCaptureConfig(),
 	}
 }
 
@@ -497,6 +499,9 @@ func (h *Holder) Open() error {
 		index.createdAt = cim.CreatedAt
 		index.owner = cim.Owner
 		index.description = cim.Meta.Description
+		index.retentionField = cim.Meta.RetentionField
+		index.retentionPeriod = cim.Meta.RetentionPeriod
+		index.partitionKey = cim.Meta.PartitionKey
 
 		err = index.OpenWithSchema(idx)
 		if err != nil {
@@ -995,6 +1000,9 @@ func (h *Holder) createIndex(cim *CreateIndexMessage, broadcast bool) (*Index, e
 	index.createdAt = cim.CreatedAt
 	index.owner = cim.Owner
 	index.description = cim.Meta.Description
+	index.retentionField = cim.Meta.RetentionField
+	index.retentionPeriod = cim.Meta.RetentionPeriod
+	index.partitionKey = cim.Meta.PartitionKey
 
 	if err = index.Open(); err != nil {
 		return nil, errors.Wrap(err, "opening")
@@ -1206,12 +1214,292 @@ func (h *Holder) deleteIndex(name string) error {
 	return h.translationSyncer.Reset()
 }
 
+// RenameIndex renames an index, moving its fields, data and translation
+// data to the new name.
+func (h *Holder) RenameIndex(name, newName string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.renameIndex(name, newName, true)
+}
+
+// RenameIndexLocal renames an index in the local holder without updating the
+// schemator. It is used when applying a rename which was already persisted by
+// another node.
+func (h *Holder) RenameIndexLocal(name, newName string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.renameIndex(name, newName, false)
+}
+
+// renameIndex is a non-locking version of RenameIndex().
+func (h *Holder) renameIndex(name, newName string, persist bool) error {
+	// Confirm index exists and the new name is free.
+	index := h.Index(name)
+	if index == nil {
+		return newNotFoundError(ErrIndexNotFound, name)
+	} else if h.Index(newName) != nil {
+		return ErrIndexExists
+	}
+	if err := ValidateName(newName); err != nil {
+		return errors.Wrap(err, "validating name")
+	}
+
+	// Fields in other indexes refer to their foreign index by name, so
+	// don't allow those to be left dangling.
+	for _, idx := range h.Indexes() {
+		for _, fld := range idx.Fields() {
+			if fld.options.ForeignIndex == name {
+				return errors.Errorf("index %s is the foreign index of field %s/%s", name, idx.Name(), fld.Name())
+			}
+		}
+	}
+
+	cim := &CreateIndexMessage{
+		Index:     newName,
+		CreatedAt: index.CreatedAt(),
+		Owner:     index.owner,
+		Meta:      index.Options(),
+	}
+	// Shards and keys stay in the partitions of the original name, so the
+	// rename doesn't move any data between nodes or translate stores.
+	cim.Meta.PartitionKey = index.PartitionKey()
+
+	// Hold on to the data which doesn't live in the index directory so it
+	// can be restored under the new name.
+	snapshots := make(map[string]*os.File)
+	keySnapshots := make(map[int]*os.File)
+	shards := make(map[string]*roaring.Bitmap)
+	defer func() {
+		for _, f := range snapshots {
+			f.Close()
+			os.Remove(f.Name())
+		}
+		for _, f := range keySnapshots {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if index.Keys() {
+		for partitionID := 0; partitionID < h.partitionN; partitionID++ {
+			f, err := snapshotTranslateStore(index.TranslateStore(partitionID), index.TranslateStorePath(partitionID))
+			if err != nil {
+				return errors.Wrapf(err, "snapshotting index translate store: partition=%d", partitionID)
+			} else if f != nil {
+				keySnapshots[partitionID] = f
+			}
+		}
+	}
+	for _, fld := range index.Fields() {
+		f, err := snapshotTranslateStore(fld.TranslateStore(), fld.TranslateStorePath())
+		if err != nil {
+			return errors.Wrapf(err, "snapshotting field translate store: %s", fld.Name())
+		} else if f != nil {
+			snapshots[fld.Name()] = f
+		}
+		shards[fld.Name()] = fld.protectedRemoteAvailableShards()
+	}
+
+	// The fields have to be rewritten in etcd under the new name.
+	var cfms []*CreateFieldMessage
+	for _, fld := range index.Fields() {
+		if fld.Name() == existenceFieldName {
+			continue
+		}
+		cfm := &CreateFieldMessage{
+			Index:     newName,
+			Field:     fld.Name(),
+			CreatedAt: fld.CreatedAt(),
+			Owner:     fld.owner,
+			Meta:      &FieldOptions{},
+		}
+		*cfm.Meta = fld.Options()
+		cfms = append(cfms, cfm)
+	}
+
+	// Close index.
+	if err := index.Close(); err != nil {
+		return errors.Wrap(err, "closing")
+	}
+
+	// Until the rename is persisted, any failure puts the directory back and
+	// reopens the index under its original name, so the holder keeps
+	// matching etcd.
+	moved := false
+	rollback := func(cause error) error {
+		if moved {
+			if err := os.Rename(h.IndexPath(newName), h.IndexPath(name)); err != nil {
+				h.Logger.Errorf("moving directory of index %s back after failed rename: %v", name, err)
+				return cause
+			}
+		}
+		if err := h.reopenIndex(index); err != nil {
+			h.Logger.Errorf("reopening index %s after failed rename: %v", name, err)
+		} else if err := restoreIndexData(index, keySnapshots, snapshots, shards); err != nil {
+			h.Logger.Errorf("restoring index %s after failed rename: %v", name, err)
+		}
+		return cause
+	}
+
+	// Bitmap keys don't include the index name, so the backing store only
+	// needs to be released before the directory is moved.
+	if err := h.txf.ReleaseIndex(name); err != nil {
+		return rollback(errors.Wrap(err, "releasing index"))
+	}
+
+	if err := os.Rename(h.IndexPath(name), h.IndexPath(newName)); err != nil {
+		return rollback(errors.Wrap(err, "renaming directory"))
+	}
+	moved = true
+
+	// Rename the index in etcd as the system of record. Once this succeeds,
+	// the rename has happened.
+	if persist {
+		if err := h.persistRenameIndex(index, cim, cfms); err != nil {
+			return rollback(err)
+		}
+	}
+
+	// Remove reference.
+	h.deleteIndexFromMap(name)
+
+	schema, err := h.Schemator.Schema(context.TODO())
+	if err != nil {
+		return errors.Wrap(err, "getting schema")
+	}
+
+	nidx, err := h.newIndex(h.IndexPath(newName), newName)
+	if err != nil {
+		return errors.Wrap(err, "creating")
+	}
+	nidx.owner = cim.Owner
+	nidx.description = cim.Meta.Description
+	nidx.retentionField = cim.Meta.RetentionField
+	nidx.retentionPeriod = cim.Meta.RetentionPeriod
+	nidx.partitionKey = cim.Meta.PartitionKey
+	if idx, ok := schema[newName]; ok {
+		err = nidx.OpenWithSchema(idx)
+	} else {
+		nidx.keys = cim.Meta.Keys
+		nidx.trackExistence = cim.Meta.TrackExistence
+		nidx.createdAt = cim.CreatedAt
+		err = nidx.Open()
+	}
+	if err != nil {
+		return errors.Wrap(err, "opening renamed index")
+	}
+
+	if err := restoreIndexData(nidx, keySnapshots, snapshots, shards); err != nil {
+		return err
+	}
+
+	return h.translationSyncer.Reset()
+}
+
+// restoreIndexData restores the translate store snapshots and remote
+// available shards taken of an index before it was closed into idx. Each
+// snapshot is removed from its map once it has been restored.
+func restoreIndexData(idx *Index, keySnapshots map[int]*os.File, snapshots map[string]*os.File, shards map[string]*roaring.Bitmap) error {
+	for partitionID, f := range keySnapshots {
+		if err := restoreTranslateStore(idx.TranslateStore(partitionID), f); err != nil {
+			return errors.Wrapf(err, "restoring index translate store: partition=%d", partitionID)
+		}
+		delete(keySnapshots, partitionID)
+	}
+	for fname, f := range snapshots {
+		fld := idx.Field(fname)
+		if fld == nil {
+			continue
+		}
+		if err := restoreTranslateStore(fld.TranslateStore(), f); err != nil {
+			return errors.Wrapf(err, "restoring field translate store: %s", fname)
+		}
+		delete(snapshots, fname)
+	}
+	for fname, bm := range shards {
+		if fld := idx.Field(fname); fld != nil {
+			if err := fld.AddRemoteAvailableShards(bm); err != nil {
+				return errors.Wrapf(err, "adding remote available shards: %s", fname)
+			}
+		}
+	}
+	return nil
+}
+
+// persistRenameIndex renames index in etcd and rewrites its fields under the
+// new name. If rewriting a field fails, the index is renamed back, and the
+// fields already rewritten are restored, so a failed rename leaves etcd as it
+// was.
+func (h *Holder) persistRenameIndex(index *Index, cim *CreateIndexMessage, cfms []*CreateFieldMessage) error {
+	name, newName := index.Name(), cim.Index
+
+	b, err := h.serializer.Marshal(cim)
+	if err != nil {
+		return errors.Wrap(err, "marshaling index")
+	}
+	if err := h.Schemator.RenameIndex(context.TODO(), name, newName, b); errors.Cause(err) == disco.ErrIndexExists {
+		return ErrIndexExists
+	} else if err != nil {
+		return errors.Wrapf(err, "renaming index in etcd: %s", name)
+	}
+
+	for n, cfm := range cfms {
+		err := index.persistUpdateField(context.TODO(), cfm)
+		if err == nil {
+			continue
+		}
+		err = errors.Wrapf(err, "updating field in etcd: %s/%s", newName, cfm.Field)
+
+		old := *cim
+		old.Index = name
+		ob, merr := h.serializer.Marshal(&old)
+		if merr != nil {
+			h.Logger.Errorf("marshaling index %s to undo rename: %v", name, merr)
+			return err
+		}
+		if rerr := h.Schemator.RenameIndex(context.TODO(), newName, name, ob); rerr != nil {
+			h.Logger.Errorf("renaming index %s back to %s in etcd: %v", newName, name, rerr)
+			return err
+		}
+		for _, done := range cfms[:n] {
+			undo := *done
+			undo.Index = name
+			if uerr := index.persistUpdateField(context.TODO(), &undo); uerr != nil {
+				h.Logger.Errorf("restoring field %s/%s in etcd: %v", name, done.Field, uerr)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// reopenIndex opens an index which was closed, using its schema in etcd if
+// there is one.
+func (h *Holder) reopenIndex(index *Index) error {
+	schema, err := h.Schemator.Schema(context.TODO())
+	if err != nil {
+		return errors.Wrap(err, "getting schema")
+	}
+	if idx, ok := schema[index.Name()]; ok {
+		return index.OpenWithSchema(idx)
+	}
+	return index.Open()
+}
+
 func (h *Holder) deleteIndexFromMap(index string) {
 	h.imu.Lock()
 	delete(h.indexes, index)
 	h.imu.Unlock()
 }
 
+// partitionKey returns the name the shards and keys of the named index are
+// partitioned by. See Index.PartitionKey().
+func (h *Holder) partitionKey(index string) string {
+	if idx := h.Index(index); idx != nil {
+		return idx.PartitionKey()
+	}
+	return index
+}
+
 // Field returns the field for an index and name.
 func (h *Holder) Field(index, name string) *Field {
 	idx := h.Index(index)
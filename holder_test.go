@@ -2,11 +2,16 @@
 package pilosa_test
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/disco"
 	"github.com/featurebasedb/featurebase/v3/test"
 	"github.com/pkg/errors"
 )
@@ -196,3 +201,151 @@ func TestHolder_DeleteIndex(t *testing.T) {
 		t.Fatal("expected i1 files to still exist", err)
 	}
 }
+
+func TestHolder_RenameIndex(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	ctx := context.Background()
+	api := c.GetNode(0).API
+	hldr := c.GetHolder(0)
+	i0, i1, i2 := c.Idx("0"), c.Idx("1"), c.Idx("2")
+
+	if _, err := api.CreateIndex(ctx, i0, pilosa.IndexOptions{TrackExistence: true}); err != nil {
+		t.Fatal(err)
+	} else if _, err := api.CreateIndex(ctx, i1, pilosa.IndexOptions{Keys: true}); err != nil {
+		t.Fatal(err)
+	} else if _, err := api.CreateField(ctx, i0, "f", pilosa.OptFieldKeys()); err != nil {
+		t.Fatal(err)
+	} else if _, err := api.Query(ctx, &pilosa.QueryRequest{Index: i0, Query: `Set(1, f="x") Set(2, f="x")`}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := api.RenameIndex(ctx, i0, i1); errors.Cause(err) != pilosa.ErrIndexExists {
+		t.Fatalf("expected index exists error, got: %v", err)
+	} else if err := api.RenameIndex(ctx, i0, i2); err != nil {
+		t.Fatal(err)
+	}
+
+	if hldr.Index(i0) != nil {
+		t.Fatal("expected old index to be gone")
+	} else if _, err := os.Stat(hldr.IndexPath(i0)); !os.IsNotExist(err) {
+		t.Fatal("expected old index directory to be moved")
+	}
+
+	resp, err := api.Query(ctx, &pilosa.QueryRequest{Index: i2, Query: `Row(f="x")`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cols := resp.Results[0].(*pilosa.Row).Columns(); len(cols) != 2 {
+		t.Fatalf("unexpected columns: %v", cols)
+	}
+}
+
+// failingRenameSchemator fails every attempt to rename an index.
+type failingRenameSchemator struct {
+	disco.Schemator
+}
+
+func (failingRenameSchemator) RenameIndex(ctx context.Context, name, newName string, val []byte) error {
+	return errors.New("rename failed")
+}
+
+// Ensure a rename which can't be persisted leaves the index as it was.
+func TestHolder_RenameIndex_Rollback(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	ctx := context.Background()
+	api := c.GetNode(0).API
+	hldr := c.GetHolder(0)
+	i0, i1 := c.Idx("0"), c.Idx("1")
+
+	c.CreateField(t, i0, pilosa.IndexOptions{Keys: true, TrackExistence: true}, "f")
+	c.Query(t, i0, `Set("a", f=1) Set("b", f=1)`)
+
+	schemator := hldr.Schemator
+	hldr.Schemator = failingRenameSchemator{schemator}
+	err := api.RenameIndex(ctx, i0, i1)
+	hldr.Schemator = schemator
+	if err == nil {
+		t.Fatal("expected rename to fail")
+	}
+
+	if hldr.Index(i1) != nil {
+		t.Fatal("expected no renamed index")
+	} else if _, err := os.Stat(hldr.IndexPath(i1)); !os.IsNotExist(err) {
+		t.Fatal("expected index directory not to be moved")
+	}
+	resp := c.Query(t, i0, `Row(f=1)`)
+	got := resp.Results[0].(*pilosa.Row).Keys
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Fatalf("unexpected keys: %v", got)
+	}
+
+	// The index can still be renamed once etcd is back.
+	if err := api.RenameIndex(ctx, i0, i1); err != nil {
+		t.Fatal(err)
+	}
+	if resp := c.Query(t, i1, `Count(Row(f=1))`); resp.Results[0] != uint64(2) {
+		t.Fatalf("unexpected count: %v", resp.Results[0])
+	}
+}
+
+// Ensure an index with keys can be renamed on a cluster where nodes only hold
+// some of its shards and keys.
+func TestHolder_RenameIndex_Keys(t *testing.T) {
+	c := test.MustRunCluster(t, 3)
+	defer c.Close()
+
+	ctx := context.Background()
+	i0, i1 := c.Idx("0"), c.Idx("1")
+
+	keys := make([]string, 100)
+	for n := range keys {
+		keys[n] = fmt.Sprintf("k%d", n)
+	}
+
+	c.CreateField(t, i0, pilosa.IndexOptions{Keys: true, TrackExistence: true}, "f")
+	var pql strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&pql, `Set("%s", f=1)`, key)
+	}
+	c.Query(t, i0, pql.String())
+
+	if err := c.GetPrimary().API.RenameIndex(ctx, i0, i1); err != nil {
+		t.Fatal(err)
+	}
+
+	for n := 0; n < 3; n++ {
+		api := c.GetNode(n).API
+		if _, err := api.Query(ctx, &pilosa.QueryRequest{Index: i0, Query: `Count(Row(f=1))`}); errors.Cause(err) != pilosa.ErrIndexNotFound {
+			t.Fatalf("node %d: expected index not found error, got: %v", n, err)
+		}
+
+		resp, err := api.Query(ctx, &pilosa.QueryRequest{Index: i1, Query: `Row(f=1)`})
+		if err != nil {
+			t.Fatalf("node %d: %v", n, err)
+		}
+		got := resp.Results[0].(*pilosa.Row).Keys
+		sort.Strings(got)
+		want := append([]string(nil), keys...)
+		sort.Strings(want)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("node %d: unexpected keys: %v", n, got)
+		}
+	}
+
+	// New keys are still translated, and existing keys keep their ids.
+	c.Query(t, i1, `Set("k0", f=2) Set("new", f=2)`)
+	resp := c.Query(t, i1, `Row(f=2)`)
+	got := resp.Results[0].(*pilosa.Row).Keys
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, []string{"k0", "new"}) {
+		t.Fatalf("unexpected keys: %v", got)
+	}
+	if resp := c.Query(t, i1, `Count(All())`); resp.Results[0] != uint64(101) {
+		t.Fatalf("unexpected count: %v", resp.Results[0])
+	}
+}
@@ -57,6 +57,14 @@ import (
 const (
 	// HeaderRequestUserID is request userid header
 	HeaderRequestUserID = "X-Request-Userid"
+
+	// HeaderSQLSessionID is the header which identifies the sql session a
+	// request belongs to; transactions span requests with the same session id
+	HeaderSQLSessionID = "X-Sql-Session"
+
+	// HeaderResourcePool is the header which names the resource pool of the
+	// query a node sends part of to another node.
+	HeaderResourcePool = "X-Resource-Pool"
 )
 
 // Handler represents an HTTP handler.
@@ -80,6 +88,11 @@ type Handler struct {
 
 	closeTimeout time.Duration
 
+	// the cursors of paginated /sql queries, and how long they can go unread
+	// before they are closed
+	sqlCursors       *sqlCursors
+	sqlCursorTimeout time.Duration
+
 	serializer        Serializer
 	roaringSerializer Serializer
 
@@ -224,6 +237,15 @@ func OptHandlerCloseTimeout(d time.Duration) handlerOption {
 	}
 }
 
+// OptHandlerSQLCursorTimeout controls how long the cursor of a paginated
+// /sql query can go unread before it is closed. Default is 5 minutes.
+func OptHandlerSQLCursorTimeout(d time.Duration) handlerOption {
+	return func(h *Handler) error {
+		h.sqlCursorTimeout = d
+		return nil
+	}
+}
+
 var (
 	makeImportOk sync.Once
 	importOk     []byte
@@ -232,9 +254,10 @@ var (
 // NewHandler returns a new instance of Handler with a default logger.
 func NewHandler(opts ...handlerOption) (*Handler, error) {
 	handler := &Handler{
-		fileSystem:   NopFileSystem,
-		logger:       logger.NopLogger,
-		closeTimeout: time.Second * 30,
+		fileSystem:       NopFileSystem,
+		logger:           logger.NopLogger,
+		closeTimeout:     time.Second * 30,
+		sqlCursorTimeout: defaultSQLCursorTimeout,
 	}
 
 	for _, opt := range opts {
@@ -243,6 +266,7 @@ func NewHandler(opts ...handlerOption) (*Handler, error) {
 			return nil, errors.Wrap(err, "applying option")
 		}
 	}
+	handler.sqlCursors = newSQLCursors(handler.sqlCursorTimeout, defaultMaxSQLCursors)
 	if handler.serializer == nil || handler.roaringSerializer == nil {
 		return nil, errors.New("must use serializer options when creating handler")
 	}
@@ -283,6 +307,7 @@ func (h *Handler) Serve() error {
 // Close tries to cleanly shutdown the HTTP server, and failing that, after a
 // timeout, calls Server.Close.
 func (h *Handler) Close() error {
+	h.sqlCursors.closeAll()
 	deadlineCtx, cancelFunc := context.WithDeadline(context.Background(), time.Now().Add(h.closeTimeout))
 	defer cancelFunc()
 	err := h.server.Shutdown(deadlineCtx)
@@ -327,6 +352,7 @@ func (h *Handler) populateValidators() {
 	h.validators["PostTransaction"] = queryValidationSpecRequired()
 	h.validators["PostFinishTransaction"] = queryValidationSpecRequired()
 	h.validators["DeleteDataframe"] = queryValidationSpecRequired()
+	h.validators["GetChanges"] = queryValidationSpecRequired().Optional("since", "limit", "timeout")
 }
 
 type contextKeyQuery int
@@ -503,10 +529,12 @@ func newRouter(handler *Handler) http.Handler {
 	router.HandleFunc("/index/{index}", handler.chkAuthZ(handler.handleGetIndex, authz.Read)).Methods("GET").Name("GetIndex")
 	router.HandleFunc("/index/{index}", handler.chkAuthZ(handler.handlePostIndex, authz.Admin)).Methods("POST").Name("PostIndex")
 	router.HandleFunc("/index/{index}", handler.chkAuthZ(handler.handleDeleteIndex, authz.Admin)).Methods("DELETE").Name("DeleteIndex")
+	router.HandleFunc("/index/{index}", handler.chkAuthZ(handler.handlePatchIndex, authz.Admin)).Methods("PATCH").Name("PatchIndex")
 	router.HandleFunc("/index/{index}/dataframe/{shard}", handler.chkAuthZ(handler.handlePostDataframe, authz.Write)).Methods("POST").Name("PostDataframe")
 	router.HandleFunc("/index/{index}/dataframe/{shard}", handler.chkAuthZ(handler.handleGetDataframe, authz.Read)).Methods("GET").Name("GetDataframe")
 	router.HandleFunc("/index/{index}/dataframe", handler.chkAuthZ(handler.handleGetDataframeSchema, authz.Read)).Methods("GET").Name("GetDataframeSchema")
 	router.HandleFunc("/index/{index}/dataframe", handler.chkAuthZ(handler.handleDeleteDataframe, authz.Write)).Methods("DELETE").Name("DeleteDataframe")
+	router.HandleFunc("/index/{index}/changes", handler.chkAuthZ(handler.handleGetChanges, authz.Read)).Methods("GET").Name("GetChanges")
 	router.HandleFunc("/index/{index}/field", handler.chkAuthZ(handler.handlePostField, authz.Write)).Methods("POST").Name("PostField")
 	router.HandleFunc("/index/{index}/field/", handler.chkAuthZ(handler.handlePostField, authz.Write)).Methods("POST").Name("PostField")
 	router.HandleFunc("/index/{index}/field/{field}/view", handler.chkAuthZ(handler.handleGetView, authz.Admin)).Methods("GET")
@@ -534,6 +562,8 @@ func newRouter(handler *Handler) http.Handler {
 	router.HandleFunc("/queries", handler.chkAuthZ(handler.handleGetActiveQueries, authz.Admin)).Methods("GET").Name("GetActiveQueries")
 
 	router.HandleFunc("/sql", handler.chkAuthZ(handler.handlePostSQL, authz.Admin)).Methods("POST").Name("PostSQL")
+	router.HandleFunc("/sql/cursor/{id}", handler.chkAuthZ(handler.handlePostSQLCursor, authz.Admin)).Methods("POST").Name("PostSQLCursor")
+	router.HandleFunc("/sql/cursor/{id}", handler.chkAuthZ(handler.handleDeleteSQLCursor, authz.Admin)).Methods("DELETE").Name("DeleteSQLCursor")
 	// internal endpoint
 	router.HandleFunc("/sql-exec-graph", handler.chkAuthZ(handler.handlePostSQLPlanOperator, authz.Admin)).Methods("POST").Name("PostSQLPlanOperator")
 
@@ -577,7 +607,7 @@ func newRouter(handler *Handler) http.Handler {
 	router.HandleFunc("/internal/translate/field/{index}/{field}", handler.chkAuthZ(handler.handlePostTranslateFieldDB, authz.Admin)).Methods("POST").Name("PostTranslateFieldDB")
 	router.HandleFunc("/internal/translate/field/{index}/{field}/keys/find", handler.chkAuthZ(handler.handleFindFieldKeys, authz.Admin)).Methods("POST").Name("FindFieldKeys")
 	router.HandleFunc("/internal/translate/field/{index}/{field}/keys/create", handler.chkAuthZ(handler.handleCreateFieldKeys, authz.Admin)).Methods("POST").Name("CreateFieldKeys")
-	router.HandleFunc("/internal/translate/field/{index}/{field}/keys/like", handler.chkAuthZ(handler.handleMatchField, authz.Read)).Methods("POST").Name("MatchFieldKeys")
+	router.HandleFunc("/internal/translate/field/{index}/{field}/keys/{syntax:like|regexp|glob}", handler.chkAuthZ(handler.handleMatchField, authz.Read)).Methods("POST").Name("MatchFieldKeys")
 
 	router.HandleFunc("/internal/idalloc/reserve", handler.chkAuthN(handler.handleReserveIDs)).Methods("POST").Name("ReserveIDs")
 	router.HandleFunc("/internal/idalloc/commit", handler.chkAuthN(handler.handleCommitIDs)).Methods("POST").Name("CommitIDs")
@@ -659,22 +689,57 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) chkInternal(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if h.auth != nil {
-			secret, ok := r.Header["X-Feature-Key"]
-			var secretString string
-			if ok {
-				secretString = secret[0]
-			}
-			decodedString, err := hex.DecodeString(secretString)
-			if err != nil || !ok || !bytes.Equal(decodedString, h.auth.SecretKey()) {
-				http.Error(w, "internal secret key validation failed", http.StatusUnauthorized)
-				return
-			}
+		if h.auth != nil && !h.isInternal(r) {
+			http.Error(w, "internal secret key validation failed", http.StatusUnauthorized)
+			return
 		}
 		handler.ServeHTTP(w, r)
 	}
 }
 
+// isInternal reports whether r was sent by another node: when auth is
+// enabled, by holding the internal secret key, and otherwise by coming from
+// the address of a node of the cluster.
+func (h *Handler) isInternal(r *http.Request) bool {
+	if h.auth == nil {
+		return h.isFromNode(r)
+	}
+	secret, ok := r.Header["X-Feature-Key"]
+	var secretString string
+	if ok {
+		secretString = secret[0]
+	}
+	decodedString, err := hex.DecodeString(secretString)
+	return err == nil && ok && bytes.Equal(decodedString, h.auth.SecretKey())
+}
+
+// isFromNode reports whether the peer address of r is one of the addresses
+// of the nodes of the cluster. Unlike GetIP, it ignores the headers naming
+// the original client, which anyone can set.
+func (h *Handler) isFromNode(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, node := range h.api.Hosts(r.Context()) {
+		addrs, err := net.DefaultResolver.LookupIPAddr(r.Context(), node.URI.Host)
+		if err != nil {
+			h.logger.Debugf("resolving address of node %s: %v", node.ID, err)
+			continue
+		}
+		for _, addr := range addrs {
+			if addr.IP.Equal(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (h *Handler) chkAllowedNetworks(r *http.Request) (bool, context.Context) {
 	// for every request, get IP of the request and check against configured IPs
 	reqIP := GetIP(r)
@@ -748,6 +813,7 @@ func (h *Handler) chkAuthZ(handler http.HandlerFunc, perm authz.Permission) http
 		allowedNetwork, ctx := h.chkAllowedNetworks(r)
 		if allowedNetwork {
 			ctx = context.WithValue(ctx, contextKeyGroupMembership, []string{AllowedNetworksGroupName, h.permissions.Admin})
+			ctx = authn.WithAdmin(ctx, true)
 			handler.ServeHTTP(w, r.WithContext(ctx))
 			return
 		}
@@ -819,6 +885,7 @@ func (h *Handler) chkAuthZ(handler http.HandlerFunc, perm authz.Permission) http
 
 		// if they're an admin, they can do whatever they want
 		if h.permissions.IsAdmin(uinfo.Groups) {
+			ctx = authn.WithAdmin(ctx, true)
 			handler.ServeHTTP(w, r.WithContext(ctx))
 			return
 		} else if lperm == authz.Admin {
@@ -828,6 +895,8 @@ func (h *Handler) chkAuthZ(handler http.HandlerFunc, perm authz.Permission) http
 			return
 		}
 
+		ctx = authn.WithAdmin(ctx, false)
+
 		// try to get the index name
 		indexName, ok := mux.Vars(r)["index"]
 		if !ok {
@@ -1331,11 +1400,13 @@ func (h *Handler) handlePostQuery(w http.ResponseWriter, r *http.Request) {
 	// TODO: Remove
 	req.Index = mux.Vars(r)["index"]
 
-	resp, err := h.api.Query(r.Context(), req)
+	resp, err := h.api.Query(h.queryContext(r, req), req)
 	if err != nil {
 		switch errors.Cause(err) {
 		case ErrTooManyWrites:
 			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		case ErrResourcePoolQueueFull, ErrResourcePoolQueueTimeout:
+			w.WriteHeader(http.StatusServiceUnavailable)
 		case ErrTranslateStoreReadOnly:
 			u := h.api.PrimaryReplicaNodeURL()
 			u.Path, u.RawQuery = r.URL.Path, r.URL.RawQuery
@@ -1369,6 +1440,17 @@ func (h *Handler) handlePostQuery(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// queryContext returns the context to execute the query of a /query request
+// in. The part of a query sent by the node which admitted it runs in the same
+// resource pool without being admitted again; remote queries from anyone else
+// are admitted to the pool of the caller like any other query.
+func (h *Handler) queryContext(r *http.Request, req *QueryRequest) context.Context {
+	if req.Remote && h.isInternal(r) {
+		return h.api.server.workload.withPool(r.Context(), r.Header.Get(HeaderResourcePool))
+	}
+	return r.Context()
+}
+
 func (h *Handler) writeBadRequest(w http.ResponseWriter, r *http.Request, err error) {
 	w.WriteHeader(http.StatusBadRequest)
 	e := h.writeQueryResponse(w, r, &QueryResponse{Err: err})
@@ -1434,9 +1516,24 @@ func (h *Handler) handlePostSQLPlanOperator(w http.ResponseWriter, r *http.Reque
 	writeError(rowErr)
 }
 
+// sqlRequest is the json form of the body of a POST /sql request, used to
+// give values for the positional parameters ($1, $2, ...) of the statement,
+// and to ask for its results a page at a time.
+type sqlRequest struct {
+	SQL        string        `json:"sql"`
+	Parameters []interface{} `json:"parameters"`
+	PageSize   int           `json:"page-size"`
+}
+
+// sqlResponseFlushRows is the number of data rows written between flushes of
+// a /sql response which isn't paginated, so that a large result is streamed
+// to the client as it is read rather than buffered.
+const sqlResponseFlushRows = 1000
+
 // handlePostSQL handles /sql requests
 // supports a ?plan=true|false parameter to send back the plan in the
-// query response
+// query response, and a ?page-size=n parameter to send back at most n rows,
+// along with a cursor for reading the rest from /sql/cursor/{id}
 func (h *Handler) handlePostSQL(w http.ResponseWriter, r *http.Request) {
 	includePlan := false
 	includePlanValue := r.URL.Query().Get("plan")
@@ -1449,6 +1546,12 @@ func (h *Handler) handlePostSQL(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	pageSize, err := sqlPageSize(r)
+	if err != nil {
+		h.writeBadRequest(w, r, err)
+		return
+	}
+
 	// get the body
 	b, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -1462,6 +1565,9 @@ func (h *Handler) handlePostSQL(w http.ResponseWriter, r *http.Request) {
 	}
 	// put the requestId in the context
 	ctx := fbcontext.WithRequestID(r.Context(), requestID.String())
+	if sessionID := r.Header.Get(HeaderSQLSessionID); sessionID != "" {
+		ctx = fbcontext.WithSessionID(ctx, sessionID)
+	}
 
 	// update the counter for requests
 	PerfCounterSQLRequestSec.Add(1)
@@ -1476,59 +1582,10 @@ func (h *Handler) handlePostSQL(w http.ResponseWriter, r *http.Request) {
 
 	// Write the closing bracket on any exit from this method.
 	defer func() {
-		var execTime int64
-		// we are going to make best effort here - don't actually care about the error
-		// if there was an error, the request.ElapsedTime will be zero
-		request, _ := h.api.server.SystemLayer.ExecutionRequests().GetRequest(requestID.String())
-		execTime = request.ElapsedTime.Microseconds()
-
-		var value []byte
-		value, err = json.Marshal(execTime)
-		if err != nil {
-			w.Write([]byte(`,"execution-time": 0`))
-		} else {
-			w.Write([]byte(`,"execution-time":`))
-			w.Write(value)
-		}
+		h.writeSQLExecutionTime(w, requestID.String())
 		w.Write([]byte("}"))
 	}()
 
-	// writeError is a helper function that can be called anywhere during the
-	// output handling to insert an error into the json output.
-	writeError := func(err error, withComma bool) {
-		if err != nil {
-			errMsg, err := json.Marshal(err.Error())
-			if err != nil {
-				errMsg = []byte(`"PROBLEM ENCODING ERROR MESSAGE"`)
-			}
-			if withComma {
-				w.Write([]byte(`,"error":`))
-			} else {
-				w.Write([]byte(`"error":`))
-			}
-			w.Write(errMsg)
-		}
-	}
-
-	// writeWarnings is a helper function that can be called anywhere during the
-	// output handling to insert warnings into the json output.
-	writeWarnings := func(warnings []string) {
-		if len(warnings) > 0 {
-			w.Write([]byte(`,"warnings": [`))
-			for i, warn := range warnings {
-				warnMsg, err := json.Marshal(warn)
-				if err != nil {
-					warnMsg = []byte(`"PROBLEM ENCODING WARNING"`)
-				}
-				w.Write(warnMsg)
-				if i < len(warnings)-1 {
-					w.Write([]byte(`,`))
-				}
-			}
-			w.Write([]byte(`]`))
-		}
-	}
-
 	writePlan := func(plan map[string]interface{}) {
 		if plan != nil && includePlan {
 			planBytes, err := json.Marshal(plan)
@@ -1540,18 +1597,68 @@ func (h *Handler) handlePostSQL(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// The body is either the statement itself, or a json object holding the
+	// statement and the values of its positional parameters.
 	sql := string(b)
-	rootOperator, err := h.api.CompilePlan(ctx, sql)
+	var parameters []interface{}
+	if body := bytes.TrimSpace(b); len(body) > 0 && body[0] == '{' {
+		var req sqlRequest
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&req); err != nil {
+			writeSQLError(w, errors.Wrap(err, "decoding request"), false)
+			return
+		}
+		if req.PageSize < 0 {
+			writeSQLError(w, errors.Errorf("invalid page-size %d", req.PageSize), false)
+			return
+		}
+		sql, parameters = req.SQL, req.Parameters
+		if req.PageSize > 0 {
+			pageSize = req.PageSize
+		}
+	}
+
+	// A paginated query outlives this request, so it executes in a context
+	// which isn't cancelled when the request completes. That context is
+	// cancelled on the way out, unless it is handed over to a cursor.
+	cancel := context.CancelFunc(func() {})
+	if pageSize > 0 {
+		ctx, cancel = context.WithCancel(detachedContext{parent: ctx})
+	}
+	var cur *sqlCursor
+	defer func() {
+		if cur == nil {
+			cancel()
+		}
+	}()
+
+	// Wait to be admitted to the resource pool of the request's user; the
+	// context of a cursor keeps the pool, so the pages it serves later are
+	// admitted to the same one.
+	ctx, release, err := h.api.server.workload.admit(ctx)
+	if err != nil {
+		writeSQLError(w, err, false)
+		return
+	}
+	defer release()
+
+	var rootOperator types.PlanOperator
+	if parameters != nil {
+		rootOperator, err = h.api.CompilePreparedPlan(ctx, sql, parameters)
+	} else {
+		rootOperator, err = h.api.CompilePlan(ctx, sql)
+	}
 	if err != nil {
-		writeError(err, false)
+		writeSQLError(w, err, false)
 		return
 	}
 
 	// Get a query iterator.
 	iter, err := rootOperator.Iterator(ctx, nil)
 	if err != nil {
-		writeError(err, false)
-		writeWarnings(rootOperator.Warnings())
+		writeSQLError(w, err, false)
+		writeSQLWarnings(w, rootOperator.Warnings())
 		return
 	}
 
@@ -1563,8 +1670,8 @@ func (h *Handler) handlePostSQL(w http.ResponseWriter, r *http.Request) {
 	for i, col := range columns {
 		btype, err := dax.BaseTypeFromString(col.Type.BaseTypeName())
 		if err != nil {
-			writeError(err, false)
-			writeWarnings(rootOperator.Warnings())
+			writeSQLError(w, err, false)
+			writeSQLWarnings(w, rootOperator.Warnings())
 			return
 		}
 		schema.Fields[i] = &WireQueryField{
@@ -1574,51 +1681,243 @@ func (h *Handler) handlePostSQL(w http.ResponseWriter, r *http.Request) {
 			TypeInfo: col.Type.TypeInfo(),
 		}
 	}
+	if err := h.writeSQLSchema(w, schema); err != nil {
+		writeSQLError(w, err, false)
+		writeSQLWarnings(w, rootOperator.Warnings())
+		return
+	}
+
+	// Write the data (rows).
+	next, rowErr := h.writeSQLData(ctx, w, iter, nil, pageSize)
+
+	// If there are rows left, hand the query over to a cursor.
+	if next != nil {
+		userID, _ := fbcontext.UserID(ctx)
+		c := &sqlCursor{
+			requestID: requestID.String(),
+			userID:    userID,
+			ctx:       ctx,
+			cancel:    cancel,
+			op:        rootOperator,
+			iter:      iter,
+			schema:    schema,
+			pageSize:  pageSize,
+			next:      next,
+		}
+		if err := h.sqlCursors.open(c); err != nil {
+			rowErr = err
+		} else {
+			cur = c
+			writeSQLCursor(w, cur.id)
+		}
+	}
+
+	writeSQLError(w, rowErr, true)
+	writeSQLWarnings(w, rootOperator.Warnings())
+	writePlan(rootOperator.Plan())
+}
+
+// handlePostSQLCursor handles /sql/cursor/{id} requests, sending back the
+// next page of the results of a paginated /sql query, along with the cursor
+// again if there are rows left; the cursor is closed once its last page has
+// been read. Supports a ?page-size=n parameter to change the size of the page.
+func (h *Handler) handlePostSQLCursor(w http.ResponseWriter, r *http.Request) {
+	pageSize, err := sqlPageSize(r)
+	if err != nil {
+		h.writeBadRequest(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	userID, _ := fbcontext.UserID(r.Context())
+	cur, err := h.sqlCursors.acquire(mux.Vars(r)["id"], userID)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("{"))
+		writeSQLError(w, err, false)
+		w.Write([]byte("}"))
+		return
+	}
+	if pageSize > 0 {
+		cur.pageSize = pageSize
+	}
+
+	ctx, release, err := h.api.server.workload.admit(cur.ctx)
+	if err != nil {
+		h.sqlCursors.release(cur)
+		w.Write([]byte("{"))
+		writeSQLError(w, err, false)
+		w.Write([]byte("}"))
+		return
+	}
+	defer release()
+
+	w.Write([]byte("{"))
+	defer func() {
+		h.writeSQLExecutionTime(w, cur.requestID)
+		w.Write([]byte("}"))
+	}()
+
+	var next types.Row
+	var rowErr error
+	if rowErr = h.writeSQLSchema(w, cur.schema); rowErr == nil {
+		next, rowErr = h.writeSQLData(ctx, w, cur.iter, cur.next, cur.pageSize)
+	}
+	if next != nil {
+		cur.next = next
+		h.sqlCursors.release(cur)
+		writeSQLCursor(w, cur.id)
+	} else {
+		h.sqlCursors.close(cur.id, cur.userID)
+	}
+
+	writeSQLError(w, rowErr, true)
+	writeSQLWarnings(w, cur.op.Warnings())
+}
+
+// handleDeleteSQLCursor handles DELETE /sql/cursor/{id} requests, closing the
+// cursor of a paginated /sql query whose remaining rows aren't wanted.
+func (h *Handler) handleDeleteSQLCursor(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	userID, _ := fbcontext.UserID(r.Context())
+	if !h.sqlCursors.close(id, userID) {
+		http.Error(w, fmt.Sprintf("cursor '%s' not found", id), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// sqlPageSize returns the value of the ?page-size parameter of a /sql
+// request, or 0 if there isn't one.
+func sqlPageSize(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("page-size")
+	if value == "" {
+		return 0, nil
+	}
+	pageSize, err := strconv.Atoi(value)
+	if err != nil || pageSize < 0 {
+		return 0, errors.Errorf("invalid page-size '%s'", value)
+	}
+	return pageSize, nil
+}
+
+// writeSQLSchema writes the schema of a /sql response.
+func (h *Handler) writeSQLSchema(w http.ResponseWriter, schema WireQuerySchema) error {
 	w.Write([]byte(`"schema":`))
 	jsonSchema, err := json.Marshal(schema)
 	if err != nil {
 		h.logger.Errorf("write schema response error: %s", err)
 		// Provide an empty list as the schema value to maintain valid json.
 		w.Write([]byte("[]"))
-		writeError(err, false)
-		writeWarnings(rootOperator.Warnings())
-		return
+		return err
 	}
 	w.Write(jsonSchema)
+	return nil
+}
 
-	// Write the data (rows).
+// writeSQLData writes the rows read from iter as the data of a /sql
+// response, preceded by next if it isn't nil. When pageSize is positive, at
+// most pageSize rows are written, and the first row of the next page is
+// returned, or nil if there are no rows left. Otherwise every row is written,
+// and the response is flushed as it goes.
+func (h *Handler) writeSQLData(ctx context.Context, w http.ResponseWriter, iter types.RowIterator, next types.Row, pageSize int) (types.Row, error) {
 	w.Write([]byte(`,"data":[`))
+	defer w.Write([]byte("]"))
+
+	flusher, _ := w.(http.Flusher)
+	for n := 0; ; n++ {
+		row := next
+		next = nil
+		if row == nil {
+			var err error
+			row, err = iter.Next(ctx)
+			if err == types.ErrNoMoreRows {
+				return nil, nil
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		if pageSize > 0 && n == pageSize {
+			return row, nil
+		}
 
-	var rowErr error
-	var currentRow types.Row
-	var nextErr error
-
-	rowCounter := 1
-	for currentRow, nextErr = iter.Next(ctx); nextErr == nil; currentRow, nextErr = iter.Next(ctx) {
-		jsonRow, err := json.Marshal(currentRow)
+		jsonRow, err := json.Marshal(row)
 		if err != nil {
 			h.logger.Errorf("json encoding error: %s", err)
-			rowErr = err
-			break
+			return nil, err
 		}
-
-		if rowCounter > 1 {
+		if n > 0 {
 			// Include a comma between data rows.
 			w.Write([]byte(","))
 		}
 		w.Write(jsonRow)
 
-		rowCounter++
+		if pageSize == 0 && flusher != nil && (n+1)%sqlResponseFlushRows == 0 {
+			flusher.Flush()
+		}
 	}
-	if nextErr != nil && nextErr != types.ErrNoMoreRows {
-		rowErr = nextErr
+}
+
+// writeSQLCursor writes the id of the cursor for reading the rest of the
+// rows of a /sql response.
+func writeSQLCursor(w http.ResponseWriter, id string) {
+	w.Write([]byte(`,"cursor":`))
+	value, _ := json.Marshal(id)
+	w.Write(value)
+}
+
+// writeSQLExecutionTime writes the execution time of the request with the
+// given id into a /sql response.
+func (h *Handler) writeSQLExecutionTime(w http.ResponseWriter, requestID string) {
+	// we are going to make best effort here - don't actually care about the error
+	// if there was an error, the request.ElapsedTime will be zero
+	request, _ := h.api.server.SystemLayer.ExecutionRequests().GetRequest(requestID)
+	execTime := request.ElapsedTime.Microseconds()
+
+	value, err := json.Marshal(execTime)
+	if err != nil {
+		w.Write([]byte(`,"execution-time": 0`))
+	} else {
+		w.Write([]byte(`,"execution-time":`))
+		w.Write(value)
 	}
+}
 
-	w.Write([]byte("]"))
+// writeSQLError can be called anywhere during the output handling of a /sql
+// request to insert an error into the json output.
+func writeSQLError(w http.ResponseWriter, err error, withComma bool) {
+	if err != nil {
+		errMsg, err := json.Marshal(err.Error())
+		if err != nil {
+			errMsg = []byte(`"PROBLEM ENCODING ERROR MESSAGE"`)
+		}
+		if withComma {
+			w.Write([]byte(`,"error":`))
+		} else {
+			w.Write([]byte(`"error":`))
+		}
+		w.Write(errMsg)
+	}
+}
 
-	writeError(rowErr, true)
-	writeWarnings(rootOperator.Warnings())
-	writePlan(rootOperator.Plan())
+// writeSQLWarnings can be called anywhere during the output handling of a
+// /sql request to insert warnings into the json output.
+func writeSQLWarnings(w http.ResponseWriter, warnings []string) {
+	if len(warnings) > 0 {
+		w.Write([]byte(`,"warnings": [`))
+		for i, warn := range warnings {
+			warnMsg, err := json.Marshal(warn)
+			if err != nil {
+				warnMsg = []byte(`"PROBLEM ENCODING WARNING"`)
+			}
+			w.Write(warnMsg)
+			if i < len(warnings)-1 {
+				w.Write([]byte(`,`))
+			}
+		}
+		w.Write([]byte(`]`))
+	}
 }
 
 func (h *Handler) handleCPUProfileStart(w http.ResponseWriter, r *http.Request) {
@@ -2072,6 +2371,8 @@ func fieldOptionsToFunctionalOpts(opt fieldOptions) []FieldOption {
 		fos = append(fos, OptFieldTypeMutex(*opt.CacheType, *opt.CacheSize))
 	case FieldTypeBool:
 		fos = append(fos, OptFieldTypeBool())
+	case FieldTypeGeo:
+		fos = append(fos, OptFieldTypeGeo())
 	}
 	if opt.Keys != nil {
 		if *opt.Keys {
@@ -2137,6 +2438,35 @@ func (h *Handler) handlePostField(w http.ResponseWriter, r *http.Request) {
 	resp.write(w, err)
 }
 
+// handlePatchIndex handles updates to index options at /index/{index}
+func (h *Handler) handlePatchIndex(w http.ResponseWriter, r *http.Request) {
+	if !validHeaderAcceptJSON(r.Header) {
+		http.Error(w, "JSON only acceptable response", http.StatusNotAcceptable)
+		return
+	}
+
+	indexName, ok := mux.Vars(r)["index"]
+	if !ok {
+		http.Error(w, "index name is required", http.StatusBadRequest)
+		return
+	}
+
+	resp := successResponse{h: h, Name: indexName}
+
+	// Decode request.
+	var req IndexUpdate
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(&req)
+	if err != nil && err != io.EOF {
+		resp.write(w, err)
+		return
+	}
+
+	err = h.api.UpdateIndex(r.Context(), indexName, req)
+	resp.write(w, err)
+}
+
 // handlePatchField handles updates to field schema at /index/{index}/field/{field}
 func (h *Handler) handlePatchField(w http.ResponseWriter, r *http.Request) {
 	if !validHeaderAcceptJSON(r.Header) {
@@ -2309,6 +2639,24 @@ func (o *fieldOptions) validate() error {
 		} else if o.ForeignIndex != nil {
 			return NewBadRequestError(errors.New("bool field cannot be a foreign key"))
 		}
+	case FieldTypeGeo:
+		if o.CacheType != nil {
+			return NewBadRequestError(errors.New("cacheType does not apply to field type geo"))
+		} else if o.CacheSize != nil {
+			return NewBadRequestError(errors.New("cacheSize does not apply to field type geo"))
+		} else if o.Min != nil {
+			return NewBadRequestError(errors.New("min does not apply to field type geo"))
+		} else if o.Max != nil {
+			return NewBadRequestError(errors.New("max does not apply to field type geo"))
+		} else if o.TimeQuantum != nil {
+			return NewBadRequestError(errors.New("timeQuantum does not apply to field type geo"))
+		} else if o.Keys != nil {
+			return NewBadRequestError(errors.New("keys does not apply to field type geo"))
+		} else if o.TTL != nil {
+			return NewBadRequestError(errors.New("ttl does not apply to field type geo"))
+		} else if o.ForeignIndex != nil {
+			return NewBadRequestError(errors.New("geo field cannot be a foreign key"))
+		}
 	default:
 		return errors.Errorf("invalid field type: %s", o.Type)
 	}
@@ -3006,6 +3354,150 @@ func (h *Handler) handlePostTranslateData(w http.ResponseWriter, r *http.Request
 	}
 }
 
+const (
+	// defaultChangesLimit is the number of change events returned by a
+	// changes request which doesn't set a limit.
+	defaultChangesLimit = 1000
+
+	// changesStreamWait is how long a stream of change events waits for
+	// an event before sending a keepalive comment.
+	changesStreamWait = 30 * time.Second
+)
+
+// changesResponse is the response to a long-polling changes request.
+type changesResponse struct {
+	Events []*ChangeEvent `json:"events"`
+	// Next is the since value for the next request.
+	Next uint64 `json:"next"`
+}
+
+// handleGetChanges handles GET /index/{index}/changes requests, which read the
+// change events recorded by this node after the offset since. The response is
+// the events as JSON, waiting up to timeout for an event if there are none
+// yet. If the client accepts text/event-stream, the events are streamed as
+// server-sent events until the client disconnects, resuming after the
+// Last-Event-ID header if it is set.
+func (h *Handler) handleGetChanges(w http.ResponseWriter, r *http.Request) {
+	indexName := mux.Vars(r)["index"]
+	q := r.URL.Query()
+
+	var since uint64
+	if s := q.Get("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseUint(s, 10, 64); err != nil {
+			http.Error(w, "since must be an offset", http.StatusBadRequest)
+			return
+		}
+	}
+	limit := defaultChangesLimit
+	if s := q.Get("limit"); s != "" {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil || limit <= 0 {
+			http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+			return
+		}
+	}
+	var timeout time.Duration
+	if s := q.Get("timeout"); s != "" {
+		var err error
+		if timeout, err = time.ParseDuration(s); err != nil {
+			http.Error(w, "timeout must be a duration", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		if id := r.Header.Get("Last-Event-ID"); id != "" {
+			var err error
+			if since, err = strconv.ParseUint(id, 10, 64); err != nil {
+				http.Error(w, "Last-Event-ID must be an offset", http.StatusBadRequest)
+				return
+			}
+		}
+		h.streamChanges(w, r, indexName, since, limit)
+		return
+	}
+
+	events, err := h.api.Changes(r.Context(), indexName, since, limit, timeout)
+	if err != nil {
+		http.Error(w, err.Error(), changesErrorStatus(err))
+		return
+	}
+	resp := changesResponse{Events: events, Next: since}
+	if len(events) > 0 {
+		resp.Next = events[len(events)-1].Offset
+	}
+	if resp.Events == nil {
+		resp.Events = []*ChangeEvent{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Errorf("writing changes response: %v", err)
+	}
+}
+
+// streamChanges writes the change events after since as server-sent events
+// until the client disconnects.
+func (h *Handler) streamChanges(w http.ResponseWriter, r *http.Request, indexName string, since uint64, limit int) {
+	ctx := r.Context()
+
+	// Errors before anything is written are returned as a status.
+	events, err := h.api.Changes(ctx, indexName, since, limit, 0)
+	if err != nil {
+		http.Error(w, err.Error(), changesErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+
+	for ctx.Err() == nil {
+		if len(events) == 0 {
+			// keep the connection from looking idle
+			if _, err := io.WriteString(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		for _, ev := range events {
+			data, err := json.Marshal(ev)
+			if err != nil {
+				h.logger.Errorf("encoding change event: %v", err)
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", ev.Offset, data); err != nil {
+				return
+			}
+			since = ev.Offset
+		}
+		w.(http.Flusher).Flush()
+
+		if events, err = h.api.Changes(ctx, indexName, since, limit, changesStreamWait); err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(w, "event: error\ndata: %s\n\n", err)
+				w.(http.Flusher).Flush()
+			}
+			return
+		}
+	}
+}
+
+// changesErrorStatus returns the HTTP status for an error reading changes.
+func changesErrorStatus(err error) int {
+	switch errors.Cause(err) {
+	case ErrIndexNotFound:
+		return http.StatusNotFound
+	case ErrChangeCaptureDisabled:
+		return http.StatusNotImplemented
+	case ErrChangeOffsetExpired:
+		// the consumer has fallen too far behind to catch up from the
+		// change log, and must resynchronize from the table
+		return http.StatusGone
+	}
+	return http.StatusInternalServerError
+}
+
 type queryValidationSpec struct {
 	required []string
 	args     map[string]struct{}
@@ -3713,7 +4205,7 @@ func (h *Handler) handleMatchField(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	matches, err := h.api.MatchField(r.Context(), indexName, fieldName, string(bd))
+	matches, err := h.api.MatchField(r.Context(), indexName, fieldName, mux.Vars(r)["syntax"], string(bd))
 	if err != nil {
 		http.Error(w, "failed to match pattern", http.StatusInternalServerError)
 		return
@@ -23,8 +23,11 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/featurebasedb/featurebase/v3/authz"
+	"github.com/featurebasedb/featurebase/v3/disco"
 	"github.com/featurebasedb/featurebase/v3/logger"
+	pnet "github.com/featurebasedb/featurebase/v3/net"
 	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/pkg/errors"
 )
 
 // Test custom UnmarshalJSON for postIndexRequest object
@@ -770,6 +773,86 @@ func TestChkInternal(t *testing.T) {
 	}
 }
 
+func TestHandlerQueryContext(t *testing.T) {
+	authKey := "DEADBEEFDEADBEEFDEADBEEFDEADBEEFDEADBEEFDEADBEEFDEADBEEFDEADBEEF"
+	workload, err := newWorkloadManager([]ResourcePool{
+		{Name: DefaultResourcePoolName, MaxConcurrency: 1, QueueTimeout: time.Millisecond},
+		{Name: "fast", Priority: QueryPriorityHigh},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newCluster()
+	c.noder = disco.NewLocalNoder([]*disco.Node{{ID: "node0", URI: pnet.URI{Scheme: "http", Host: "192.0.2.1", Port: 10101}}})
+	api := &API{server: &Server{workload: workload}, cluster: c}
+	h := Handler{
+		logger: logger.NewStandardLogger(os.Stdout),
+		auth:   NewTestAuth(t),
+		api:    api,
+	}
+	// Without auth, requests from the address of a node are internal.
+	noAuth := Handler{
+		logger: logger.NewStandardLogger(os.Stdout),
+		api:    api,
+	}
+
+	// Fill the default pool.
+	_, release, err := workload.admit(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	cases := []struct {
+		name      string
+		noAuth    bool
+		remote    bool
+		key       string
+		addr      string
+		forwarded string
+		priority  QueryPriority
+		err       error
+	}{
+		{name: "query", err: ErrResourcePoolQueueTimeout, priority: QueryPriorityNormal},
+		{name: "remote-unauthenticated", remote: true, err: ErrResourcePoolQueueTimeout, priority: QueryPriorityNormal},
+		{name: "remote-wrong-key", remote: true, key: "BEABBEEFBEABBEEFBEABBEEFBEABBEEFBEABBEEFBEABBEEFBEABBEEF", err: ErrResourcePoolQueueTimeout, priority: QueryPriorityNormal},
+		{name: "remote-internal", remote: true, key: authKey, priority: QueryPriorityHigh},
+		{name: "noauth-remote-client", noAuth: true, remote: true, addr: "198.51.100.1:5000", err: ErrResourcePoolQueueTimeout, priority: QueryPriorityNormal},
+		{name: "noauth-remote-forwarded", noAuth: true, remote: true, addr: "198.51.100.1:5000", forwarded: "192.0.2.1", err: ErrResourcePoolQueueTimeout, priority: QueryPriorityNormal},
+		{name: "noauth-remote-node", noAuth: true, remote: true, addr: "192.0.2.1:5000", priority: QueryPriorityHigh},
+	}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/index/i/query", nil)
+			r.Header.Set(HeaderResourcePool, "fast")
+			if test.key != "" {
+				r.Header.Set("X-Feature-Key", test.key)
+			}
+			if test.addr != "" {
+				r.RemoteAddr = test.addr
+			}
+			if test.forwarded != "" {
+				r.Header.Set(ForwardedIPHeader, test.forwarded)
+			}
+			h := h
+			if test.noAuth {
+				h = noAuth
+			}
+
+			ctx, release, err := workload.admit(h.queryContext(r, &QueryRequest{Remote: test.remote}))
+			if errors.Cause(err) != test.err {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			} else if err != nil {
+				return
+			}
+			defer release()
+			if got := queryPriority(ctx); got != test.priority {
+				t.Fatalf("expected priority %s, got %s", test.priority, got)
+			}
+		})
+	}
+}
+
 func NewTestAuth(t *testing.T) *authn.Auth {
 	t.Helper()
 	a, err := authn.NewAuth(
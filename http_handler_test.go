@@ -581,6 +581,18 @@ func TestHandlerSQL(t *testing.T) {
 			sql:     "invalid sql",
 			expKeys: []string{"error", "execution-time"},
 		},
+		{
+			name:    "sql-with-parameters",
+			url:     "/sql",
+			sql:     `{"sql": "select $1 + 1 as x", "parameters": [1]}`,
+			expKeys: []string{"schema", "data", "execution-time"},
+		},
+		{
+			name:    "invalid-json",
+			url:     "/sql",
+			sql:     `{"sql": "show tables"`,
+			expKeys: []string{"error", "execution-time"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -603,6 +615,143 @@ func TestHandlerSQL(t *testing.T) {
 	}
 }
 
+// TestHandlerSQLParameters tests that the values of a POST /sql request with
+// a json body are bound to the positional parameters of its statement.
+func TestHandlerSQLParameters(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	sqlURL := c.GetPrimary().URL() + "/sql"
+	resp := test.Do(t, "POST", sqlURL, `{"sql": "select $1 + 1 as x, $2 as y", "parameters": [41, "foo"]}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("post sql, status: %d, body=%s", resp.StatusCode, resp.Body)
+	}
+	var out struct {
+		Data  [][]interface{} `json:"data"`
+		Error string          `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(resp.Body), &out))
+	assert.Empty(t, out.Error)
+	assert.Equal(t, [][]interface{}{{float64(42), "foo"}}, out.Data)
+
+	resp = test.Do(t, "POST", sqlURL, `{"sql": "select $1 + 1 as x", "parameters": []}`)
+	assert.Contains(t, resp.Body, "statement takes 1 parameter(s), 0 given")
+}
+
+// TestHandlerSQLCursor tests reading the results of a POST /sql request a
+// page at a time.
+func TestHandlerSQLCursor(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	m := c.GetPrimary()
+	sqlURL := m.URL() + "/sql"
+	cursorURL := func(id string) string {
+		return m.URL() + "/sql/cursor/" + id
+	}
+
+	// the rows are spread over more shards than a table scan extracts at once
+	var values []string
+	var exp [][]interface{}
+	for i := int64(0); i < 7; i++ {
+		id := i*pilosa.ShardWidth + 1
+		values = append(values, fmt.Sprintf("(%d, %d)", id, i))
+		exp = append(exp, []interface{}{id, i})
+	}
+	for _, sql := range []string{
+		"create table cursortest (_id id, a int)",
+		"insert into cursortest values " + strings.Join(values, ", "),
+	} {
+		resp := test.Do(t, "POST", sqlURL, sql)
+		if resp.StatusCode != http.StatusOK || strings.Contains(resp.Body, `"error"`) {
+			t.Fatalf("post sql, status: %d, body=%s", resp.StatusCode, resp.Body)
+		}
+	}
+
+	decode := func(t *testing.T, body string) pilosa.WireQueryResponse {
+		t.Helper()
+		var out pilosa.WireQueryResponse
+		assert.NoError(t, json.Unmarshal([]byte(body), &out))
+		assert.Empty(t, out.Error)
+		return out
+	}
+
+	t.Run("pages", func(t *testing.T) {
+		resp := test.Do(t, "POST", sqlURL+"?page-size=3", "select _id, a from cursortest")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("post sql, status: %d, body=%s", resp.StatusCode, resp.Body)
+		}
+		out := decode(t, resp.Body)
+		assert.Equal(t, exp[:3], out.Data)
+		if out.Cursor == "" {
+			t.Fatalf("expected a cursor, body=%s", resp.Body)
+		}
+		id := out.Cursor
+
+		resp = test.Do(t, "POST", cursorURL(id), "")
+		out = decode(t, resp.Body)
+		assert.Equal(t, 2, len(out.Schema.Fields))
+		assert.Equal(t, exp[3:6], out.Data)
+		assert.Equal(t, id, out.Cursor)
+
+		resp = test.Do(t, "POST", cursorURL(id), "")
+		out = decode(t, resp.Body)
+		assert.Equal(t, exp[6:], out.Data)
+		assert.Empty(t, out.Cursor)
+
+		// the cursor is closed once its last page has been read
+		resp = test.Do(t, "POST", cursorURL(id), "")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Contains(t, resp.Body, "not found")
+	})
+
+	t.Run("exact-page", func(t *testing.T) {
+		resp := test.Do(t, "POST", sqlURL+"?page-size=7", "select _id, a from cursortest")
+		out := decode(t, resp.Body)
+		assert.Equal(t, exp, out.Data)
+		assert.Empty(t, out.Cursor)
+	})
+
+	t.Run("json-page-size", func(t *testing.T) {
+		resp := test.Do(t, "POST", sqlURL, `{"sql": "select _id, a from cursortest where a > $1", "parameters": [1], "page-size": 2}`)
+		out := decode(t, resp.Body)
+		assert.Equal(t, exp[2:4], out.Data)
+		id := out.Cursor
+
+		// the page size can be changed along the way
+		resp = test.Do(t, "POST", cursorURL(id)+"?page-size=10", "")
+		out = decode(t, resp.Body)
+		assert.Equal(t, exp[4:], out.Data)
+		assert.Empty(t, out.Cursor)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		resp := test.Do(t, "POST", sqlURL+"?page-size=1", "select _id, a from cursortest")
+		id := decode(t, resp.Body).Cursor
+
+		resp = test.Do(t, "DELETE", cursorURL(id), "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = test.Do(t, "POST", cursorURL(id), "")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		resp = test.Do(t, "DELETE", cursorURL(id), "")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("unpaged", func(t *testing.T) {
+		resp := test.Do(t, "POST", sqlURL, "select _id, a from cursortest")
+		out := decode(t, resp.Body)
+		assert.Equal(t, exp, out.Data)
+		assert.Empty(t, out.Cursor)
+	})
+
+	t.Run("invalid-page-size", func(t *testing.T) {
+		resp := test.Do(t, "POST", sqlURL+"?page-size=x", "select _id from cursortest")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp = test.Do(t, "POST", sqlURL, `{"sql": "select _id from cursortest", "page-size": -1}`)
+		assert.Contains(t, resp.Body, "invalid page-size -1")
+	})
+}
+
 func TestTranslationHandlers(t *testing.T) {
 	// reusable data for the tests
 	nameBytes, err := json.Marshal([]string{"a", "b", "c"})
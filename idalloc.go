@@ -11,6 +11,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/featurebasedb/featurebase/v3/encryption"
 	"github.com/pkg/errors"
 	bolt "go.etcd.io/bbolt"
 )
@@ -43,6 +44,9 @@ func (k IDAllocKey) String() string {
 type idAllocator struct {
 	db           *bolt.DB
 	fsyncEnabled bool
+
+	// cipher, if set, encrypts the stored reservations and ranges.
+	cipher *encryption.Cipher
 }
 
 type OpenIDAllocatorFunc func(path string, enableFsync bool) (*idAllocator, error) // whyyyyyyyyy
@@ -55,6 +59,39 @@ func OpenIDAllocator(path string, enableFsync bool) (*idAllocator, error) {
 	return &idAllocator{db: db, fsyncEnabled: enableFsync}, nil
 }
 
+// OpenEncryptedIDAllocator returns a function which opens ID allocators
+// whose values are encrypted with c.
+func OpenEncryptedIDAllocator(c *encryption.Cipher) OpenIDAllocatorFunc {
+	return func(path string, enableFsync bool) (*idAllocator, error) {
+		ida, err := OpenIDAllocator(path, enableFsync)
+		if err != nil {
+			return nil, err
+		}
+		ida.cipher = c
+		return ida, nil
+	}
+}
+
+// get returns the decrypted value of key in bkt, or nil if there is none.
+func (ida *idAllocator) get(bkt *bolt.Bucket, key []byte) ([]byte, error) {
+	value := bkt.Get(key)
+	if ida.cipher == nil || value == nil {
+		return value, nil
+	}
+	value, err := ida.cipher.Open(nil, value)
+	return value, errors.Wrap(err, "decrypting ID allocation entry")
+}
+
+// put encrypts value and stores it as key in bkt.
+func (ida *idAllocator) put(bkt *bolt.Bucket, key, value []byte) (err error) {
+	if ida.cipher != nil {
+		if value, err = ida.cipher.Seal(nil, value); err != nil {
+			return errors.Wrap(err, "encrypting ID allocation entry")
+		}
+	}
+	return bkt.Put(key, value)
+}
+
 func (ida *idAllocator) Replace(reader io.Reader) error {
 	newFile := ida.db.Path() + ".bak"
 	liveFile := ida.db.Path()
@@ -139,7 +176,11 @@ func (ida *idAllocator) reserve(key IDAllocKey, session [32]byte, offset, count
 
 		// Fetch the old reservation.
 		var res idReservation
-		err = res.decode(bkt.Get([]byte(key.Key + "\x00")))
+		prev, err := ida.get(bkt, []byte(key.Key+"\x00"))
+		if err != nil {
+			return err
+		}
+		err = res.decode(prev)
 		if err != nil {
 			return errors.Wrap(err, "decoding old reservation")
 		}
@@ -207,7 +248,7 @@ func (ida *idAllocator) reserve(key IDAllocKey, session [32]byte, offset, count
 
 		default:
 			// Reserve additional IDs.
-			reserved, err := doReserveIDs(bkt, count-preReserved)
+			reserved, err := ida.doReserveIDs(bkt, count-preReserved)
 			if err != nil {
 				return err
 			}
@@ -222,7 +263,7 @@ func (ida *idAllocator) reserve(key IDAllocKey, session [32]byte, offset, count
 		if err != nil {
 			return errors.Wrap(err, "encoding updated ID reservation entry")
 		}
-		err = bkt.Put([]byte(key.Key+"\x00"), encoded)
+		err = ida.put(bkt, []byte(key.Key+"\x00"), encoded)
 		if err != nil {
 			return errors.Wrap(err, "saving updated ID reservation entry")
 		}
@@ -248,8 +289,10 @@ func (ida *idAllocator) commit(key IDAllocKey, session [32]byte, count uint64) e
 		}
 
 		// Fetch the old reservation.
-		prev := bkt.Get([]byte(key.Key + "\x00"))
-		if prev == nil {
+		prev, err := ida.get(bkt, []byte(key.Key+"\x00"))
+		if err != nil {
+			return err
+		} else if prev == nil {
 			// There is nothing to commit.
 			return errors.New("nothing to commit")
 		}
@@ -292,7 +335,7 @@ func (ida *idAllocator) commit(key IDAllocKey, session [32]byte, count uint64) e
 
 		if res.offset == ^uint64(0) && len(res.ranges) > 0 {
 			// Return unused ranges to the central list.
-			err = doReleaseIDs(bkt, res.ranges...)
+			err = ida.doReleaseIDs(bkt, res.ranges...)
 			if err != nil {
 				return errors.Wrap(err, "releasing unused IDs")
 			}
@@ -313,7 +356,7 @@ func (ida *idAllocator) commit(key IDAllocKey, session [32]byte, count uint64) e
 		if err != nil {
 			return errors.Wrap(err, "encoding updated ID reservation entry")
 		}
-		err = bkt.Put([]byte(key.Key+"\x00"), encoded)
+		err = ida.put(bkt, []byte(key.Key+"\x00"), encoded)
 		if err != nil {
 			return errors.Wrap(err, "saving updated ID reservation entry")
 		}
@@ -345,6 +388,36 @@ func (ida *idAllocator) reset(index string) error {
 	return nil
 }
 
+func (ida *idAllocator) rename(index, newIndex string) error {
+	err := ida.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket([]byte(index))
+		if bkt == nil {
+			return nil
+		}
+
+		nbkt, err := tx.CreateBucket([]byte(newIndex))
+		if err != nil {
+			return errors.Wrap(err, "creating bucket")
+		}
+		if err := bkt.ForEach(func(k, v []byte) error {
+			return nbkt.Put(k, v)
+		}); err != nil {
+			return errors.Wrap(err, "copying bucket")
+		}
+
+		err = tx.DeleteBucket([]byte(index))
+		if err != nil {
+			return errors.Wrap(err, "deleting bucket")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "renaming ID allocation for index %q", index)
+	}
+	return nil
+}
+
 func (k IDAllocKey) findBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
 	ibkt, err := tx.CreateBucketIfNotExists([]byte(k.Index))
 	if err != nil {
@@ -447,9 +520,11 @@ func (r idReservation) encode() ([]byte, error) {
 	return append(append(odat[:], r.lock[:]...), rdat...), nil
 }
 
-func doReserveIDs(bkt *bolt.Bucket, count uint64) ([]IDRange, error) {
+func (ida *idAllocator) doReserveIDs(bkt *bolt.Bucket, count uint64) ([]IDRange, error) {
 	var avail []IDRange
-	if prev := bkt.Get([]byte{1}); prev != nil {
+	if prev, err := ida.get(bkt, []byte{1}); err != nil {
+		return nil, err
+	} else if prev != nil {
 		r, err := decodeRanges(prev)
 		if err != nil {
 			return nil, err
@@ -466,7 +541,7 @@ func doReserveIDs(bkt *bolt.Bucket, count uint64) ([]IDRange, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "encoding available ID ranges")
 	}
-	err = bkt.Put([]byte{1}, encoded)
+	err = ida.put(bkt, []byte{1}, encoded)
 	if err != nil {
 		return nil, errors.Wrap(err, "saving available ID ranges")
 	}
@@ -524,9 +599,11 @@ func reserveIDs(avail []IDRange, count uint64) (reserved []IDRange, unused []IDR
 	return reserved, avail, nil
 }
 
-func doReleaseIDs(bkt *bolt.Bucket, ranges ...IDRange) error {
+func (ida *idAllocator) doReleaseIDs(bkt *bolt.Bucket, ranges ...IDRange) error {
 	var avail []IDRange
-	if prev := bkt.Get([]byte{1}); prev != nil {
+	if prev, err := ida.get(bkt, []byte{1}); err != nil {
+		return err
+	} else if prev != nil {
 		r, err := decodeRanges(prev)
 		if err != nil {
 			return err
@@ -543,7 +620,7 @@ func doReleaseIDs(bkt *bolt.Bucket, ranges ...IDRange) error {
 	if err != nil {
 		return errors.Wrap(err, "encoding available ID ranges")
 	}
-	err = bkt.Put([]byte{1}, encoded)
+	err = ida.put(bkt, []byte{1}, encoded)
 	if err != nil {
 		return errors.Wrap(err, "saving available ID ranges")
 	}
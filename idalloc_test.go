@@ -3,12 +3,15 @@
 package pilosa
 
 import (
+	"bytes"
 	"crypto/rand"
 	"io"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/featurebasedb/featurebase/v3/encryption"
 	"github.com/featurebasedb/featurebase/v3/testhook"
 	bolt "go.etcd.io/bbolt"
 )
@@ -200,3 +203,44 @@ func TestIDAlloc(t *testing.T) {
 		t.Errorf("failed to commit reservation of 4 IDs: %v", err)
 	}
 }
+
+func TestIDAlloc_Encrypted(t *testing.T) {
+	keyring, err := encryption.NewStaticKeyring(map[uint32][]byte{1: bytes.Repeat([]byte{1}, 32)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := encryption.NewCipher(keyring)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "idalloc.db")
+	alloc, err := OpenEncryptedIDAllocator(c)(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer alloc.Close()
+
+	key := IDAllocKey{Index: "h", Key: "a"}
+	session := [32]byte{1}
+	ids, err := alloc.reserve(key, session, ^uint64(0), 10)
+	if err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(ids, []IDRange{{First: 1, Last: 10}}) {
+		t.Fatalf("unexpected ranges: %v", ids)
+	}
+	if err := alloc.commit(key, session, 5); err != nil {
+		t.Fatal(err)
+	}
+	if ids, err = alloc.reserve(key, [32]byte{2}, ^uint64(0), 5); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(ids, []IDRange{{First: 6, Last: 10}}) {
+		t.Fatalf("unexpected ranges: %v", ids)
+	}
+
+	// without the cipher, the stored values cannot be decoded.
+	plain := &idAllocator{db: alloc.db}
+	if _, err := plain.reserve(key, session, ^uint64(0), 1); err == nil {
+		t.Fatal("expected error reading encrypted reservation without a key")
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/featurebasedb/featurebase/v3/idk/ndjson"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/jaffee/commandeer/pflag"
+)
+
+func main() {
+	m := ndjson.NewMain()
+	if err := pflag.LoadEnv(m, "IDKNDJSON_", nil); err != nil {
+		log.Fatal(err)
+	}
+	m.Rename()
+	if err := m.Run(); err != nil {
+		log := m.Log()
+		if log == nil {
+			// if we fail before a logger was instantiated
+			logger.NewStandardLogger(os.Stderr).Errorf("Error running command: %v", err)
+			os.Exit(1)
+		}
+		log.Errorf("Error running command: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,193 @@
+package file
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/logger"
+)
+
+// lineReader reads a file with one integer per line.
+type lineReader struct {
+	lines []string
+}
+
+func openLines(name string, offset int64) (Reader, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Fields(string(b))
+	if offset > int64(len(lines)) {
+		offset = int64(len(lines))
+	}
+	return &lineReader{lines: lines[offset:]}, nil
+}
+
+func (r *lineReader) Schema() []idk.Field {
+	return []idk.Field{idk.IntField{NameVal: "a"}}
+}
+
+func (r *lineReader) Read() ([]interface{}, error) {
+	if len(r.lines) == 0 {
+		return nil, io.EOF
+	}
+	v, err := strconv.ParseInt(r.lines[0], 10, 64)
+	r.lines = r.lines[1:]
+	return []interface{}{v}, err
+}
+
+func (r *lineReader) Close() error { return nil }
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readAll reads from s until io.EOF, committing each record when s returns
+// idk.ErrFlush, and returns the values read.
+func readAll(t *testing.T, s *Source) []int64 {
+	t.Helper()
+	var vals []int64
+	var last idk.Record
+	for {
+		rec, err := s.Record()
+		if err == io.EOF {
+			return vals
+		} else if err == idk.ErrFlush {
+			if err := last.Commit(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		} else if err != nil && err != idk.ErrSchemaChange {
+			t.Fatal(err)
+		}
+		vals = append(vals, rec.Data()[0].(int64))
+		last = rec
+	}
+}
+
+func TestSource(t *testing.T) {
+	dir := t.TempDir()
+	progress := filepath.Join(t.TempDir(), "progress")
+	writeFile(t, filepath.Join(dir, "a.txt"), "1\n2\n")
+	writeFile(t, filepath.Join(dir, "b.txt"), "")
+	writeFile(t, filepath.Join(dir, "c.txt"), "3\n")
+	writeFile(t, filepath.Join(dir, "d.csv"), "4\n")
+
+	tracker, err := NewTracker([]string{dir}, "*.txt", progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSource(tracker, openLines, logger.NopLogger)
+	if got := readAll(t, s); !reflect.DeepEqual(got, []int64{1, 2, 3}) {
+		t.Fatalf("unexpected values: %v", got)
+	}
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		if p := tracker.Progress(filepath.Join(dir, name)); !p.Done {
+			t.Errorf("expected %s to be done, got %+v", name, p)
+		}
+	}
+
+	// After a restart, only new records are read.
+	writeFile(t, filepath.Join(dir, "e.txt"), "5\n")
+	tracker, err = NewTracker([]string{dir}, "*.txt", progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = NewSource(tracker, openLines, logger.NopLogger)
+	if got := readAll(t, s); !reflect.DeepEqual(got, []int64{5}) {
+		t.Fatalf("unexpected values after restart: %v", got)
+	}
+}
+
+func TestTrackerResume(t *testing.T) {
+	dir := t.TempDir()
+	progress := filepath.Join(t.TempDir(), "progress")
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	writeFile(t, a, "1\n2\n3\n")
+	writeFile(t, b, "4\n")
+
+	tracker, err := NewTracker([]string{dir}, "", progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, offset, ok, err := tracker.Next(); err != nil || !ok || name != a || offset != 0 {
+		t.Fatalf("unexpected next: %s, %d, %v, %v", name, offset, ok, err)
+	}
+	if err := tracker.Commit(a, 2, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Partially committed files resume from their offset.
+	tracker, err = NewTracker([]string{dir}, "", progress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, offset, ok, err := tracker.Next(); err != nil || !ok || name != a || offset != 2 {
+		t.Fatalf("unexpected next: %s, %d, %v, %v", name, offset, ok, err)
+	}
+	if name, offset, ok, err := tracker.Next(); err != nil || !ok || name != b || offset != 0 {
+		t.Fatalf("unexpected next: %s, %d, %v, %v", name, offset, ok, err)
+	}
+	// Committing a later file marks earlier ones done.
+	if err := tracker.Commit(b, 1, true); err != nil {
+		t.Fatal(err)
+	}
+	if p := tracker.Progress(a); p != (Progress{Offset: 2, Done: true}) {
+		t.Fatalf("unexpected progress for %s: %+v", a, p)
+	}
+	if _, _, ok, err := tracker.Next(); err != nil || ok {
+		t.Fatalf("expected no next file, got %v, %v", ok, err)
+	}
+}
+
+func TestSourceWatch(t *testing.T) {
+	dir := t.TempDir()
+	tracker, err := NewTracker([]string{dir}, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewSource(tracker, openLines, logger.NopLogger)
+	s.Watch = true
+	s.Interval = time.Millisecond
+
+	// Write the file elsewhere and move it into place, so that it isn't
+	// read before it has been written.
+	tmp := filepath.Join(t.TempDir(), "a")
+	writeFile(t, tmp, "7\n")
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		if err := os.Rename(tmp, filepath.Join(dir, "a")); err != nil {
+			t.Error(err)
+		}
+	}()
+	rec, err := s.Record()
+	if err != idk.ErrSchemaChange {
+		t.Fatalf("expected schema change, got %v", err)
+	}
+	if v := rec.Data()[0]; v != int64(7) {
+		t.Fatalf("unexpected value: %v", v)
+	}
+	if _, err := s.Record(); err != idk.ErrFlush {
+		t.Fatalf("expected flush, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		s.Close()
+	}()
+	if _, err := s.Record(); err != io.EOF {
+		t.Fatalf("expected EOF after close, got %v", err)
+	}
+}
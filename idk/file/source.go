@@ -0,0 +1,185 @@
+// Package file contains an idk.Source which reads records from files, and
+// optionally watches directories for new files. It is used by the sources
+// for particular file formats, which provide a Reader for each file.
+package file
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/pkg/errors"
+)
+
+// Reader reads the records of a single file.
+type Reader interface {
+	// Schema returns the fields of the file's records.
+	Schema() []idk.Field
+
+	// Read returns the values of the next record, or io.EOF.
+	Read() ([]interface{}, error)
+
+	Close() error
+}
+
+// Opener opens the named file, skipping the first offset records.
+type Opener func(name string, offset int64) (Reader, error)
+
+// Source is an idk.Source which reads the files found by a Tracker. Each
+// record's Commit updates the Tracker's progress. If Watch is set, once all
+// files have been read, Source waits for new files to appear rather than
+// returning io.EOF. Files should be moved into watched directories once
+// complete, since a file is not read again after reaching its end.
+type Source struct {
+	Tracker  *Tracker
+	Open     Opener
+	Watch    bool
+	Interval time.Duration
+	Log      logger.Logger
+
+	reader Reader
+	name   string
+	offset int64
+
+	// schema is the schema of the last record returned.
+	schema []idk.Field
+
+	// last is the last record returned. pending is true if records have
+	// been returned since the last idk.ErrFlush.
+	last    *Record
+	pending bool
+
+	idle    bool
+	closing chan struct{}
+}
+
+// NewSource returns a Source which reads the files found by tracker with
+// readers from open.
+func NewSource(tracker *Tracker, open Opener, log logger.Logger) *Source {
+	return &Source{
+		Tracker:  tracker,
+		Open:     open,
+		Interval: time.Second * 10,
+		Log:      log,
+		closing:  make(chan struct{}),
+	}
+}
+
+func (s *Source) Record() (idk.Record, error) {
+	for s.reader == nil {
+		name, offset, ok, err := s.Tracker.Next()
+		if err != nil {
+			return nil, errors.Wrap(err, "finding next file")
+		}
+		if !ok {
+			// Flush what we have so that it's committed before
+			// waiting, or before we finish.
+			if s.pending {
+				s.pending = false
+				return nil, idk.ErrFlush
+			}
+			// Everything returned has been committed, including any
+			// files which didn't produce records.
+			if err := s.Tracker.CommitAll(); err != nil {
+				return nil, errors.Wrap(err, "committing files")
+			}
+			if !s.Watch {
+				return nil, io.EOF
+			}
+			if !s.idle {
+				s.Log.Printf("waiting for new files")
+				s.idle = true
+			}
+			select {
+			case <-s.closing:
+				return nil, io.EOF
+			case <-time.After(s.Interval):
+			}
+			continue
+		}
+		s.idle = false
+
+		s.Log.Printf("processing file: %s", name)
+		reader, err := s.Open(name, offset)
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening %s", name)
+		}
+		if offset > 0 {
+			s.Log.Printf("resuming %s after %d records", name, offset)
+		}
+		s.reader, s.name, s.offset = reader, name, offset
+	}
+
+	vals, err := s.reader.Read()
+	if err == io.EOF {
+		s.reader.Close()
+		s.reader = nil
+		if s.last != nil && s.last.name == s.name {
+			s.last.done = true
+		}
+		return s.Record()
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", s.name)
+	}
+	s.offset++
+	s.last = &Record{tracker: s.Tracker, name: s.name, offset: s.offset, data: vals}
+	s.pending = true
+	if schema := s.reader.Schema(); !fieldsEqual(schema, s.schema) {
+		s.schema = schema
+		return s.last, idk.ErrSchemaChange
+	}
+	return s.last, nil
+}
+
+func (s *Source) Schema() []idk.Field {
+	return s.schema
+}
+
+func (s *Source) Close() error {
+	select {
+	case <-s.closing:
+		return nil
+	default:
+	}
+	close(s.closing)
+	if s.reader != nil {
+		return s.reader.Close()
+	}
+	return nil
+}
+
+// Record is a record read from a file. Committing it records that the file
+// has been committed up to and including the record.
+type Record struct {
+	tracker *Tracker
+	name    string
+	offset  int64
+	done    bool
+	data    []interface{}
+}
+
+func (r *Record) Commit(ctx context.Context) error {
+	return r.tracker.Commit(r.name, r.offset, r.done)
+}
+
+func (r *Record) Data() []interface{} {
+	return r.data
+}
+
+func (r *Record) Schema() interface{} {
+	return nil
+}
+
+func fieldsEqual(a, b []idk.Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !idk.FieldsEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,206 @@
+package file
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/featurebasedb/featurebase/v3/idk/internal"
+	"github.com/pkg/errors"
+)
+
+// Progress records how much of a file has been committed.
+type Progress struct {
+	// Offset is the number of records from the start of the file which
+	// have been committed.
+	Offset int64 `json:"offset"`
+	Done   bool  `json:"done"`
+}
+
+// Tracker finds the files to ingest, and tracks the progress of each as
+// records are committed. If ProgressPath is set, progress is written there on
+// each commit and read back by NewTracker, so that ingest resumes where it
+// left off after a restart.
+type Tracker struct {
+	// Paths are files or directories. Files are always ingested, while
+	// directories are walked for files whose names match Pattern.
+	Paths   []string
+	Pattern string
+
+	ProgressPath string
+
+	mu       sync.Mutex
+	progress map[string]*Progress
+
+	// started holds the files returned by Next which have not been marked
+	// done, in the order they were returned.
+	started []string
+}
+
+// NewTracker returns a Tracker, reading any progress from progressPath.
+func NewTracker(paths []string, pattern, progressPath string) (*Tracker, error) {
+	if pattern != "" {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern '%s'", pattern)
+		}
+	}
+	t := &Tracker{
+		Paths:        paths,
+		Pattern:      pattern,
+		ProgressPath: progressPath,
+		progress:     make(map[string]*Progress),
+	}
+	if progressPath == "" {
+		return t, nil
+	}
+	b, err := internal.ReadFileOrURL(progressPath, nil)
+	if err == internal.ErrFileOrURLNotFound {
+		return t, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "reading progress")
+	}
+	if err := json.Unmarshal(b, &t.progress); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling progress")
+	}
+	return t, nil
+}
+
+// Next returns the first file, in name order, which has not been returned by
+// Next and is not done, along with the number of records from it which have
+// already been committed. If there is no such file, ok is false.
+func (t *Tracker) Next() (name string, offset int64, ok bool, err error) {
+	files, err := t.files()
+	if err != nil {
+		return "", 0, false, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, name := range files {
+		if t.isStarted(name) {
+			continue
+		}
+		p := t.progress[name]
+		if p != nil && p.Done {
+			continue
+		}
+		t.started = append(t.started, name)
+		if p == nil {
+			return name, 0, true, nil
+		}
+		return name, p.Offset, true, nil
+	}
+	return "", 0, false, nil
+}
+
+func (t *Tracker) isStarted(name string) bool {
+	for _, s := range t.started {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// files lists the files in Paths, sorted by name.
+func (t *Tracker) files() ([]string, error) {
+	var files []string
+	for _, path := range t.Paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting info for %s", path)
+		}
+		if !fi.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.Walk(path, func(name string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !fi.Mode().IsRegular() {
+				return nil
+			}
+			if t.Pattern != "" {
+				if ok, _ := filepath.Match(t.Pattern, fi.Name()); !ok {
+					return nil
+				}
+			}
+			files = append(files, name)
+			return nil
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "walking %s", path)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Commit records that the first offset records of the named file, and every
+// file returned by Next before it, have been committed. If done is true,
+// the whole file has been committed.
+func (t *Tracker) Commit(name string, offset int64, done bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, s := range t.started {
+		if s != name {
+			continue
+		}
+		for _, prev := range t.started[:i] {
+			t.progress[prev] = &Progress{Offset: t.offset(prev), Done: true}
+		}
+		t.started = t.started[i:]
+		if done {
+			t.started = t.started[1:]
+		}
+		break
+	}
+	t.progress[name] = &Progress{Offset: offset, Done: done}
+	return t.write()
+}
+
+// CommitAll marks every file returned by Next as done.
+func (t *Tracker) CommitAll() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.started) == 0 {
+		return nil
+	}
+	for _, name := range t.started {
+		t.progress[name] = &Progress{Offset: t.offset(name), Done: true}
+	}
+	t.started = t.started[:0]
+	return t.write()
+}
+
+// offset returns the committed offset of the named file.
+func (t *Tracker) offset(name string) int64 {
+	if p := t.progress[name]; p != nil {
+		return p.Offset
+	}
+	return 0
+}
+
+// Progress returns the progress of the named file.
+func (t *Tracker) Progress(name string) Progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if p := t.progress[name]; p != nil {
+		return *p
+	}
+	return Progress{}
+}
+
+func (t *Tracker) write() error {
+	if t.ProgressPath == "" {
+		return nil
+	}
+	b, err := json.Marshal(t.progress)
+	if err != nil {
+		return errors.Wrap(err, "marshaling progress")
+	}
+	return internal.WriteFileOrURL(t.ProgressPath, b, nil)
+}
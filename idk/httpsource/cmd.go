@@ -0,0 +1,107 @@
+package httpsource
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/pkg/errors"
+)
+
+type Main struct {
+	idk.Main    `flag:"!embed"`
+	Listen      string   `help:"host:port on which to listen for ingest requests."`
+	Header      []string `help:"Fields of the records to accept, in the same format as a CSV header (e.g. 'name__String')."`
+	MaxBodySize int64    `help:"Maximum size in bytes of a request body. 0 means no limit."`
+
+	once   sync.Once
+	server *Server
+	addr   net.Addr
+}
+
+func NewMain() *Main {
+	m := &Main{
+		Main:        *idk.NewMain(),
+		Listen:      "localhost:8080",
+		MaxBodySize: 64 << 20,
+	}
+	m.Main.Namespace = "ingester_http"
+	m.NewSource = func() (idk.Source, error) {
+		var err error
+		m.once.Do(func() { err = m.start() })
+		if err != nil {
+			return nil, err
+		} else if m.server == nil {
+			return nil, errors.New("server failed to start")
+		}
+		return m.server.NewSource(), nil
+	}
+	return m
+}
+
+// Fields parses the header into the fields to accept.
+func (m *Main) Fields() ([]idk.Field, error) {
+	if len(m.Header) == 0 {
+		return nil, errors.New("must provide the fields to accept with --header")
+	}
+	fields := make([]idk.Field, 0, len(m.Header))
+	for _, h := range m.Header {
+		field, err := idk.HeaderToField(h, m.Log())
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid header (%v)", h)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// start starts the HTTP server.
+func (m *Main) start() error {
+	schema, err := m.Fields()
+	if err != nil {
+		return err
+	}
+	required := m.PrimaryKeyFields
+	if m.IDField != "" {
+		required = []string{m.IDField}
+	}
+	server, err := NewServer(schema, required, m.Log())
+	if err != nil {
+		return errors.Wrap(err, "creating server")
+	}
+	server.MaxBodySize = m.MaxBodySize
+
+	ln, err := net.Listen("tcp", m.Listen)
+	if err != nil {
+		return errors.Wrapf(err, "listening on %s", m.Listen)
+	}
+	srv := &http.Server{Handler: server}
+	server.OnClose(func() {
+		// Shut down in the background, since in-flight requests are
+		// waiting to be told the server has closed.
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+			defer cancel()
+			if err := srv.Shutdown(ctx); err != nil {
+				m.Log().Printf("shutting down HTTP server: %v", err)
+			}
+		}()
+	})
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			m.Log().Errorf("serving HTTP: %v", err)
+		}
+	}()
+	m.server, m.addr = server, ln.Addr()
+	m.Log().Printf("listening for ingest requests on %s", m.addr)
+	return nil
+}
+
+// Addr returns the address the server is listening on, once a source has
+// been created.
+func (m *Main) Addr() net.Addr {
+	return m.addr
+}
@@ -0,0 +1,340 @@
+// Package httpsource contains an IDK source which accepts batches of records
+// pushed over HTTP, so that applications can send events to FeatureBase
+// without running a message queue.
+//
+// Batches are POSTed to /ingest as newline delimited JSON objects, or as CSV
+// with a header row naming the fields. Each record is validated against the
+// configured fields; invalid records are reported in the response and
+// skipped. The response is only sent once the batch's valid records have
+// been imported and committed.
+package httpsource
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/idk/ndjson"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrClosed is returned for batches which were not committed because
+	// the server was closed.
+	ErrClosed = errors.New("ingest stopped")
+
+	errEmptyCSV = errors.New("CSV batch has no header row")
+)
+
+// Response is the body of the response to an ingest request.
+type Response struct {
+	// Accepted is the number of records which were committed.
+	Accepted int           `json:"accepted"`
+	Errors   []RecordError `json:"errors,omitempty"`
+	// Error is set if the batch as a whole failed.
+	Error string `json:"error,omitempty"`
+}
+
+// RecordError describes why a record was rejected.
+type RecordError struct {
+	// Record is the 0-based index of the record in the batch.
+	Record int    `json:"record"`
+	Error  string `json:"error"`
+}
+
+// batch is a set of valid records from a request. done receives the result
+// of committing them.
+type batch struct {
+	records [][]interface{}
+	done    chan error
+}
+
+// Server is an http.Handler which accepts batches of records and passes them
+// to the Sources created by NewSource.
+type Server struct {
+	schema []idk.Field
+	// required holds the indexes of fields which may not be null.
+	required []int
+
+	// MaxBodySize limits the size of request bodies. If it is 0, bodies
+	// are not limited.
+	MaxBodySize int64
+
+	log logger.Logger
+	mux *http.ServeMux
+
+	batches chan *batch
+
+	mu      sync.Mutex
+	sources int
+	closing chan struct{}
+	onClose func()
+}
+
+// NewServer returns a Server accepting records with the given fields. Records
+// with null values for any of the required fields are rejected.
+func NewServer(schema []idk.Field, required []string, log logger.Logger) (*Server, error) {
+	s := &Server{
+		schema:  schema,
+		log:     log,
+		mux:     http.NewServeMux(),
+		batches: make(chan *batch),
+		closing: make(chan struct{}),
+	}
+	for _, name := range required {
+		idx := -1
+		for i, f := range schema {
+			if f.Name() == name {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return nil, errors.Errorf("required field '%s' is not in the schema", name)
+		}
+		s.required = append(s.required, idx)
+	}
+	s.mux.HandleFunc("/ingest", s.handleIngest)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleIngest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeResponse(w, http.StatusMethodNotAllowed, Response{Error: "method not allowed"})
+		return
+	}
+	body := r.Body
+	if s.MaxBodySize > 0 {
+		body = http.MaxBytesReader(w, r.Body, s.MaxBodySize)
+	}
+
+	var records [][]interface{}
+	var recErrs []RecordError
+	var err error
+	switch format(r) {
+	case "csv":
+		records, recErrs, err = s.parseCSV(body)
+	case "ndjson":
+		records, recErrs, err = s.parseNDJSON(body)
+	default:
+		writeResponse(w, http.StatusUnsupportedMediaType, Response{Error: "unsupported format, use CSV or NDJSON"})
+		return
+	}
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, Response{Error: err.Error()})
+		return
+	}
+	if len(records) == 0 {
+		status := http.StatusOK
+		if len(recErrs) > 0 {
+			status = http.StatusBadRequest
+		}
+		writeResponse(w, status, Response{Errors: recErrs})
+		return
+	}
+
+	b := &batch{records: records, done: make(chan error, 1)}
+	select {
+	case s.batches <- b:
+	case <-s.closing:
+		writeResponse(w, http.StatusServiceUnavailable, Response{Errors: recErrs, Error: ErrClosed.Error()})
+		return
+	case <-r.Context().Done():
+		return
+	}
+	select {
+	case err := <-b.done:
+		if err != nil {
+			writeResponse(w, http.StatusInternalServerError, Response{Errors: recErrs, Error: err.Error()})
+			return
+		}
+	case <-r.Context().Done():
+		// The batch will still be ingested, but there's no one to tell.
+		return
+	}
+	writeResponse(w, http.StatusOK, Response{Accepted: len(records), Errors: recErrs})
+}
+
+// format returns the format of a request's body, from the format query
+// parameter or else the Content-Type. NDJSON is the default.
+func format(r *http.Request) string {
+	if f := r.URL.Query().Get("format"); f != "" {
+		return f
+	}
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return "ndjson"
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return ""
+	}
+	switch mt {
+	case "text/csv":
+		return "csv"
+	case "application/x-ndjson", "application/jsonl", "application/json", "text/plain":
+		return "ndjson"
+	}
+	return ""
+}
+
+func writeResponse(w http.ResponseWriter, status int, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// parseNDJSON reads one record from each non-empty line.
+func (s *Server) parseNDJSON(body io.Reader) ([][]interface{}, []RecordError, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var records [][]interface{}
+	var recErrs []RecordError
+	for i := 0; scanner.Scan(); {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		vals, err := ndjson.DecodeRecord(line, s.schema)
+		if err == nil {
+			err = s.validate(vals)
+		}
+		if err != nil {
+			recErrs = append(recErrs, RecordError{Record: i, Error: err.Error()})
+		} else {
+			records = append(records, vals)
+		}
+		i++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, errors.Wrap(err, "reading body")
+	}
+	return records, recErrs, nil
+}
+
+// parseCSV reads records from CSV whose first row names the fields of each
+// column. Fields without a column are null.
+func (s *Server) parseCSV(body io.Reader) ([][]interface{}, []RecordError, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, errEmptyCSV
+	} else if err != nil {
+		return nil, nil, errors.Wrap(err, "reading header")
+	}
+	columns := make([]int, len(header))
+	for i, name := range header {
+		columns[i] = -1
+		for j, f := range s.schema {
+			if f.Name() == name {
+				columns[i] = j
+				break
+			}
+		}
+		if columns[i] == -1 {
+			return nil, nil, errors.Errorf("unknown field '%s' in header", name)
+		}
+	}
+
+	var records [][]interface{}
+	var recErrs []RecordError
+	for i := 0; ; i++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			if _, ok := err.(*csv.ParseError); !ok {
+				return nil, nil, errors.Wrap(err, "reading body")
+			}
+			recErrs = append(recErrs, RecordError{Record: i, Error: err.Error()})
+			continue
+		}
+		if len(row) != len(columns) {
+			recErrs = append(recErrs, RecordError{Record: i, Error: fmt.Sprintf("expected %d columns, got %d", len(columns), len(row))})
+			continue
+		}
+		vals := make([]interface{}, len(s.schema))
+		for j, v := range row {
+			vals[columns[j]] = v
+		}
+		if err := s.validate(vals); err != nil {
+			recErrs = append(recErrs, RecordError{Record: i, Error: err.Error()})
+			continue
+		}
+		records = append(records, vals)
+	}
+	return records, recErrs, nil
+}
+
+// validate checks that each value can be interpreted by its field.
+func (s *Server) validate(vals []interface{}) error {
+	for _, idx := range s.required {
+		if vals[idx] == nil {
+			return errors.Errorf("field '%s' is required", s.schema[idx].Name())
+		}
+	}
+	for i, f := range s.schema {
+		if _, err := f.PilosafyVal(vals[i]); err != nil {
+			return errors.Wrapf(err, "field '%s'", f.Name())
+		}
+	}
+	return nil
+}
+
+// NewSource returns a Source which reads batches sent to the server. Each
+// batch is read by only one Source. Once every Source has been closed, the
+// server is closed.
+func (s *Server) NewSource() *Source {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sources++
+	return &Source{server: s, closing: make(chan struct{})}
+}
+
+func (s *Server) sourceClosed() {
+	s.mu.Lock()
+	s.sources--
+	last := s.sources == 0
+	s.mu.Unlock()
+	if last {
+		s.Close()
+	}
+}
+
+// Close stops the server accepting batches, and calls the function set by
+// OnClose.
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.closing:
+		return
+	default:
+	}
+	close(s.closing)
+	if s.onClose != nil {
+		s.onClose()
+	}
+}
+
+// OnClose sets a function to be called when the server is closed, e.g. to
+// shut down the http.Server it is serving from.
+func (s *Server) OnClose(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onClose = fn
+}
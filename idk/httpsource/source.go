@@ -0,0 +1,116 @@
+package httpsource
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+)
+
+// Source is an idk.Source which reads the batches sent to a Server. It
+// returns idk.ErrFlush after the last record of each batch, so that the batch
+// is imported and committed straight away.
+type Source struct {
+	server *Server
+
+	cur *batch
+	pos int
+
+	mu sync.Mutex
+	// pending holds the batches which have been read, in order, and whose
+	// requests haven't been answered.
+	pending []*batch
+
+	closeOnce sync.Once
+	closing   chan struct{}
+}
+
+func (s *Source) Record() (idk.Record, error) {
+	if s.cur != nil {
+		if s.pos < len(s.cur.records) {
+			rec := &Record{
+				src:  s,
+				b:    s.cur,
+				last: s.pos == len(s.cur.records)-1,
+				data: s.cur.records[s.pos],
+			}
+			s.pos++
+			return rec, nil
+		}
+		s.cur = nil
+		return nil, idk.ErrFlush
+	}
+
+	select {
+	case b := <-s.server.batches:
+		s.mu.Lock()
+		s.pending = append(s.pending, b)
+		s.mu.Unlock()
+		s.cur, s.pos = b, 0
+		return s.Record()
+	case <-s.server.closing:
+		return nil, io.EOF
+	case <-s.closing:
+		return nil, io.EOF
+	}
+}
+
+// commit answers the requests for the batches before b, and for b if last
+// is true.
+func (s *Source) commit(b *batch, last bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.pending) > 0 {
+		p := s.pending[0]
+		if p == b && !last {
+			return
+		}
+		p.done <- nil
+		s.pending = s.pending[1:]
+		if p == b {
+			return
+		}
+	}
+}
+
+func (s *Source) Schema() []idk.Field {
+	return s.server.schema
+}
+
+// Close fails the requests for any batches which haven't been committed.
+func (s *Source) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closing)
+		s.mu.Lock()
+		for _, b := range s.pending {
+			b.done <- ErrClosed
+		}
+		s.pending = nil
+		s.mu.Unlock()
+		s.server.sourceClosed()
+	})
+	return nil
+}
+
+// Record is a record from a batch. Committing it answers the requests for
+// the batches before it, and for its own batch if it is the last record.
+type Record struct {
+	src  *Source
+	b    *batch
+	last bool
+	data []interface{}
+}
+
+func (r *Record) Commit(ctx context.Context) error {
+	r.src.commit(r.b, r.last)
+	return nil
+}
+
+func (r *Record) Data() []interface{} {
+	return r.data
+}
+
+func (r *Record) Schema() interface{} {
+	return nil
+}
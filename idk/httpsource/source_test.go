@@ -0,0 +1,167 @@
+package httpsource
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/logger"
+)
+
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+	schema := []idk.Field{
+		idk.IDField{NameVal: "id"},
+		idk.StringField{NameVal: "name"},
+		idk.IntField{NameVal: "age"},
+	}
+	server, err := NewServer(schema, []string{"id"}, logger.NopLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	return server, ts
+}
+
+// ingest reads records from src like the ingest loop, committing the last
+// record on each flush, and sends the data of the records it commits.
+func ingest(src *Source, committed chan<- [][]interface{}) {
+	var pending [][]interface{}
+	var last idk.Record
+	for {
+		rec, err := src.Record()
+		switch err {
+		case nil:
+			pending = append(pending, rec.Data())
+			last = rec
+		case idk.ErrFlush:
+			if err := last.Commit(context.Background()); err != nil {
+				panic(err)
+			}
+			committed <- pending
+			pending = nil
+		default:
+			close(committed)
+			return
+		}
+	}
+}
+
+func post(t *testing.T, url, contentType, body string) (int, Response) {
+	t.Helper()
+	resp, err := http.Post(url+"/ingest", contentType, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var r Response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, r
+}
+
+func TestIngest(t *testing.T) {
+	server, ts := newTestServer(t)
+	committed := make(chan [][]interface{}, 10)
+	go ingest(server.NewSource(), committed)
+
+	status, resp := post(t, ts.URL, "application/x-ndjson", `{"id": 1, "name": "a", "age": 30}
+
+{"name": "b"}
+{"id": 3, "age": "old"}
+{"id": 4}
+`)
+	if status != http.StatusOK || resp.Accepted != 2 || len(resp.Errors) != 2 {
+		t.Fatalf("unexpected response: %d %+v", status, resp)
+	}
+	if resp.Errors[0].Record != 1 || !strings.Contains(resp.Errors[0].Error, "'id' is required") {
+		t.Errorf("unexpected error: %+v", resp.Errors[0])
+	}
+	if resp.Errors[1].Record != 2 || !strings.Contains(resp.Errors[1].Error, "field 'age'") {
+		t.Errorf("unexpected error: %+v", resp.Errors[1])
+	}
+	exp := [][]interface{}{{int64(1), "a", int64(30)}, {int64(4), nil, nil}}
+	if got := <-committed; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected records: %#v", got)
+	}
+
+	status, resp = post(t, ts.URL, "text/csv", "age,id\n5,6\n7\n")
+	if status != http.StatusOK || resp.Accepted != 1 || len(resp.Errors) != 1 {
+		t.Fatalf("unexpected response: %d %+v", status, resp)
+	}
+	exp = [][]interface{}{{"6", nil, "5"}}
+	if got := <-committed; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected records: %#v", got)
+	}
+
+	for _, test := range []struct {
+		contentType, body string
+		status            int
+	}{
+		{"text/csv", "nope\n1\n", http.StatusBadRequest},
+		{"text/csv", "", http.StatusBadRequest},
+		{"application/x-ndjson", `{"name": "no id"}`, http.StatusBadRequest},
+		{"application/xml", "<a/>", http.StatusUnsupportedMediaType},
+		{"application/x-ndjson", "", http.StatusOK},
+	} {
+		if status, resp := post(t, ts.URL, test.contentType, test.body); status != test.status {
+			t.Errorf("%s %q: expected status %d, got %d %+v", test.contentType, test.body, test.status, status, resp)
+		}
+	}
+
+	resp2, err := http.Get(ts.URL + "/ingest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp2.Body.Close()
+	if resp2.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for GET, got %d", resp2.StatusCode)
+	}
+}
+
+func TestIngestClose(t *testing.T) {
+	server, ts := newTestServer(t)
+	src := server.NewSource()
+
+	// Read a record without committing it, then close the source.
+	go func() {
+		if _, err := src.Record(); err != nil {
+			t.Error(err)
+		}
+		src.Close()
+	}()
+	status, resp := post(t, ts.URL, "", `{"id": 1}`)
+	if status != http.StatusInternalServerError || resp.Error != ErrClosed.Error() {
+		t.Fatalf("unexpected response: %d %+v", status, resp)
+	}
+
+	// Closing the last source closes the server.
+	status, resp = post(t, ts.URL, "", `{"id": 2}`)
+	if status != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected response after close: %d %+v", status, resp)
+	}
+}
+
+func TestCommitOrder(t *testing.T) {
+	src := &Source{}
+	b1 := &batch{records: make([][]interface{}, 2), done: make(chan error, 1)}
+	b2 := &batch{records: make([][]interface{}, 2), done: make(chan error, 1)}
+	src.pending = []*batch{b1, b2}
+
+	// Committing the first record of b2 answers b1 only.
+	(&Record{src: src, b: b2}).Commit(context.Background())
+	if len(b1.done) != 1 || len(b2.done) != 0 {
+		t.Fatalf("unexpected answers: %d, %d", len(b1.done), len(b2.done))
+	}
+	(&Record{src: src, b: b2, last: true}).Commit(context.Background())
+	if len(b2.done) != 1 || len(src.pending) != 0 {
+		t.Fatalf("expected b2 to be answered, pending: %d", len(src.pending))
+	}
+}
@@ -95,6 +95,8 @@ type Main struct {
 	AllowDecimalOutOfRange   bool          `help:"Allow ingest to continue when it encounters out of range decimals in DecimalFields. (default false)"`
 	AllowTimestampOutOfRange bool          `help:"Allow ingest to continue when it encounters out of range timestamps in TimestampFields. (default false)"`
 	SkipBadRows              int           `help:"If you fail to process the first n rows without processing one successfully, fail."`
+	Transforms               []string      `help:"Fields derived from expressions, each written as header=expression, where header is a field spec like those in CSV headers, e.g. 'full_name__String=concat(first, \" \", last)'. A derived field with the same name as a source field replaces it."`
+	TransformsFile           string        `help:"File or URL containing a JSON array of transforms, each an object with \"field\" and \"expr\" keys. Applied before --transforms."`
 
 	UseShardTransactionalEndpoint bool `flag:"use-shard-transactional-endpoint" help:"Use alternate import endpoint that ingests data for all fields in a shard in a single atomic request. No negative performance impact and better consistency. Recommended."`
 
@@ -136,6 +138,8 @@ type Main struct {
 	newNexter func(c int) (IDAllocator, error)
 	ra        RangeAllocator
 
+	transforms []Transform
+
 	metricsServer *http.Server
 
 	log logger.Logger
@@ -364,6 +368,9 @@ func (m *Main) ingest(ctx context.Context, source Source, nexter IDAllocator, so
 	var row *pilosabatch.Row
 	var errorCounter int // keeps track of consecuitive errors across records
 	var anyRecordSuccessful bool
+	if len(m.transforms) > 0 {
+		source = TransformSource(source, m.transforms)
+	}
 	if m.progress != nil {
 		source = m.progress.Track(source)
 	}
@@ -723,6 +730,10 @@ func (m *Main) basicSetup() (onFinishRun func(), err error) {
 		m.log = logger.NewStandardLogger(logOut)
 	}
 
+	if err := m.loadTransforms(); err != nil {
+		return nil, errors.Wrap(err, "loading transforms")
+	}
+
 	if m.TrackProgress {
 		m.progress = &ProgressTracker{}
 	}
@@ -817,6 +828,10 @@ func (m *Main) setup() (onFinishRun func(), err error) {
 		m.log = logger.NewStandardLogger(logOut)
 	}
 
+	if err := m.loadTransforms(); err != nil {
+		return nil, errors.Wrap(err, "loading transforms")
+	}
+
 	if m.LogPath != "" {
 		sighup := make(chan os.Signal, 1)
 		signal.Notify(sighup, syscall.SIGHUP)
@@ -1226,6 +1241,9 @@ func (m *Main) runDeleter(limitCounter *msgCounter) error {
 
 	}()
 
+	if len(m.transforms) > 0 {
+		source = TransformSource(source, m.transforms)
+	}
 	if m.progress != nil {
 		source = m.progress.Track(source)
 	}
@@ -0,0 +1,69 @@
+// Package ndjson contains an IDK source which ingests newline delimited JSON
+// files.
+package ndjson
+
+import (
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/idk/file"
+	"github.com/pkg/errors"
+)
+
+type Main struct {
+	idk.Main      `flag:"!embed"`
+	Files         []string      `help:"List of files or directories to ingest."`
+	Header        []string      `help:"Fields to ingest, in the same format as a CSV header (e.g. 'name__String'). Each is read from the object key of the same name."`
+	Pattern       string        `help:"Glob pattern matching the names of files to ingest from directories."`
+	Watch         bool          `help:"Once all files have been ingested, keep watching the directories for new files."`
+	WatchInterval time.Duration `help:"How often to look for new files when watching."`
+	ProgressPath  string        `help:"File or URL recording which files and lines have been committed, so that ingest can resume after a restart."`
+}
+
+func NewMain() *Main {
+	m := &Main{
+		Main:          *idk.NewMain(),
+		Pattern:       "*.ndjson",
+		WatchInterval: time.Second * 10,
+	}
+	m.Main.Namespace = "ingester_ndjson"
+	m.NewSource = func() (idk.Source, error) {
+		if len(m.Files) == 0 {
+			return nil, errors.New("must provide at least one file or directory with --files")
+		}
+		if m.Concurrency > 1 {
+			return nil, errors.New("concurrency is not supported for ndjson ingest")
+		}
+		schema, err := m.Fields()
+		if err != nil {
+			return nil, err
+		}
+		tracker, err := file.NewTracker(m.Files, m.Pattern, m.ProgressPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating tracker")
+		}
+		source := file.NewSource(tracker, func(name string, offset int64) (file.Reader, error) {
+			return NewReader(name, offset, schema)
+		}, m.Log())
+		source.Watch = m.Watch
+		source.Interval = m.WatchInterval
+		return source, nil
+	}
+	return m
+}
+
+// Fields parses the header into the fields to ingest.
+func (m *Main) Fields() ([]idk.Field, error) {
+	if len(m.Header) == 0 {
+		return nil, errors.New("must provide the fields to ingest with --header")
+	}
+	fields := make([]idk.Field, 0, len(m.Header))
+	for _, h := range m.Header {
+		field, err := idk.HeaderToField(h, m.Log())
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid header (%v)", h)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
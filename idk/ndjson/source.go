@@ -0,0 +1,126 @@
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/pkg/errors"
+)
+
+// maxLineSize is the longest line a Reader will accept.
+const maxLineSize = 16 * 1024 * 1024
+
+// Reader reads the lines of a newline delimited JSON file, each of which is
+// an object. It implements file.Reader.
+type Reader struct {
+	f       *os.File
+	scanner *bufio.Scanner
+	schema  []idk.Field
+}
+
+// NewReader opens the named file, skipping the first offset records. Each
+// record's values are taken from the object keys matching the names of the
+// fields in schema; missing keys are null.
+func NewReader(name string, offset int64, schema []idk.Field) (*Reader, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening file")
+	}
+	r := &Reader{
+		f:       f,
+		scanner: bufio.NewScanner(f),
+		schema:  schema,
+	}
+	r.scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for ; offset > 0; offset-- {
+		if _, err := r.next(); err == io.EOF {
+			break
+		} else if err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// next returns the next non-empty line.
+func (r *Reader) next() ([]byte, error) {
+	for r.scanner.Scan() {
+		if line := bytes.TrimSpace(r.scanner.Bytes()); len(line) > 0 {
+			return line, nil
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "scanning")
+	}
+	return nil, io.EOF
+}
+
+func (r *Reader) Schema() []idk.Field {
+	return r.schema
+}
+
+func (r *Reader) Read() ([]interface{}, error) {
+	line, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+	return DecodeRecord(line, r.schema)
+}
+
+// DecodeRecord decodes a JSON object into the values of the given fields,
+// taken from the keys matching their names. Missing keys are null.
+func DecodeRecord(line []byte, schema []idk.Field) ([]interface{}, error) {
+	obj := make(map[string]interface{})
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return nil, errors.Wrap(err, "decoding line")
+	}
+	vals := make([]interface{}, len(schema))
+	for i, fld := range schema {
+		v, err := convert(obj[fld.Name()])
+		if err != nil {
+			return nil, errors.Wrapf(err, "converting '%s'", fld.Name())
+		}
+		vals[i] = v
+	}
+	return vals, nil
+}
+
+func (r *Reader) Close() error {
+	return r.f.Close()
+}
+
+// convert returns a decoded JSON value as a type understood by idk fields.
+// Numbers become int64 if they are integers and float64 otherwise.
+func convert(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing number %s", v)
+		}
+		return f, nil
+	case []interface{}:
+		for i, e := range v {
+			c, err := convert(e)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = c
+		}
+		return v, nil
+	case map[string]interface{}:
+		return nil, errors.New("nested objects are not supported")
+	default:
+		return v, nil
+	}
+}
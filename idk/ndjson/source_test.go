@@ -0,0 +1,70 @@
+package ndjson
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+)
+
+func TestReader(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "a.ndjson")
+	data := `{"id": 1, "name": "a", "score": 1.5, "tags": ["x", "y"], "ids": [1, 2]}
+
+{"id": 2, "score": 3, "extra": true}
+{"id": 3, "name": "c", "score": -0.25}
+`
+	if err := os.WriteFile(name, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	schema := []idk.Field{
+		idk.IDField{NameVal: "id"},
+		idk.StringField{NameVal: "name"},
+		idk.DecimalField{NameVal: "score", Scale: 2},
+		idk.StringArrayField{NameVal: "tags"},
+		idk.IDArrayField{NameVal: "ids"},
+	}
+
+	r, err := NewReader(name, 0, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := [][]interface{}{
+		{int64(1), "a", 1.5, []interface{}{"x", "y"}, []interface{}{int64(1), int64(2)}},
+		{int64(2), nil, int64(3), nil, nil},
+		{int64(3), "c", -0.25, nil, nil},
+	}
+	var got [][]interface{}
+	for {
+		vals, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, vals)
+	}
+	r.Close()
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected records:\n%#v", got)
+	}
+
+	// The blank line doesn't count towards the offset.
+	r, err = NewReader(name, 2, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if vals, err := r.Read(); err != nil || !reflect.DeepEqual(vals, exp[2]) {
+		t.Fatalf("unexpected record after offset: %v, %v", vals, err)
+	}
+}
+
+func TestConvertNested(t *testing.T) {
+	if _, err := convert(map[string]interface{}{"a": 1}); err == nil {
+		t.Fatal("expected error for nested object")
+	}
+}
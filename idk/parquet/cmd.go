@@ -0,0 +1,51 @@
+// Package parquet contains an IDK source which ingests Parquet files.
+package parquet
+
+import (
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/idk/file"
+	"github.com/pkg/errors"
+)
+
+type Main struct {
+	idk.Main      `flag:"!embed"`
+	Files         []string      `help:"List of files or directories to ingest."`
+	Pattern       string        `help:"Glob pattern matching the names of files to ingest from directories."`
+	Watch         bool          `help:"Once all files have been ingested, keep watching the directories for new files."`
+	WatchInterval time.Duration `help:"How often to look for new files when watching."`
+	ProgressPath  string        `help:"File or URL recording which files and rows have been committed, so that ingest can resume after a restart."`
+	FloatScale    int64         `help:"Scale of the decimal fields created for floating point columns."`
+}
+
+func NewMain() *Main {
+	m := &Main{
+		Main:          *idk.NewMain(),
+		Pattern:       "*.parquet",
+		WatchInterval: time.Second * 10,
+		FloatScale:    6,
+	}
+	m.Main.Namespace = "ingester_parquet"
+	m.NewSource = func() (idk.Source, error) {
+		if len(m.Files) == 0 {
+			return nil, errors.New("must provide at least one file or directory with --files")
+		}
+		if m.Concurrency > 1 {
+			return nil, errors.New("concurrency is not supported for parquet ingest")
+		}
+		tracker, err := file.NewTracker(m.Files, m.Pattern, m.ProgressPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating tracker")
+		}
+		source := file.NewSource(tracker, m.open, m.Log())
+		source.Watch = m.Watch
+		source.Interval = m.WatchInterval
+		return source, nil
+	}
+	return m
+}
+
+func (m *Main) open(name string, offset int64) (file.Reader, error) {
+	return NewReader(name, offset, m.FloatScale, m.Log())
+}
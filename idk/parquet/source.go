@@ -0,0 +1,270 @@
+package parquet
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	"github.com/apache/arrow/go/v10/parquet/file"
+	"github.com/apache/arrow/go/v10/parquet/pqarrow"
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/pkg/errors"
+)
+
+// Reader reads the rows of a Parquet file one row group at a time. It
+// implements file.Reader.
+type Reader struct {
+	f      *os.File
+	pf     *file.Reader
+	fr     *pqarrow.FileReader
+	mem    memory.Allocator
+	schema []idk.Field
+
+	// rowGroup is the index of the next row group to read. cols holds the
+	// columns of the current row group, which has n rows, and row is the
+	// index of the next row in it.
+	rowGroup int
+	cols     []arrow.Array
+	n, row   int
+}
+
+// NewReader opens the named Parquet file, skipping the first offset rows.
+// The schema is derived from the file's metadata: columns named like CSV
+// headers (e.g. "name__String") are interpreted the same way, while the
+// fields for other columns are determined by their Parquet types. Floating
+// point columns become decimal fields with the given scale.
+func NewReader(name string, offset int64, floatScale int64, log logger.Logger) (*Reader, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening file")
+	}
+	r := &Reader{f: f, mem: memory.DefaultAllocator}
+	if err := r.open(offset, floatScale, log); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reader) open(offset int64, floatScale int64, log logger.Logger) (err error) {
+	if r.pf, err = file.NewParquetReader(r.f); err != nil {
+		return errors.Wrap(err, "reading parquet file")
+	}
+	if r.fr, err = pqarrow.NewFileReader(r.pf, pqarrow.ArrowReadProperties{}, r.mem); err != nil {
+		return errors.Wrap(err, "creating arrow reader")
+	}
+	sch, err := r.fr.Schema()
+	if err != nil {
+		return errors.Wrap(err, "reading schema")
+	}
+	if r.schema, err = schemaFromArrow(sch, floatScale, log); err != nil {
+		return err
+	}
+
+	// Skip the row groups which have been committed, then the rows of the
+	// next one.
+	for ; r.rowGroup < r.pf.NumRowGroups(); r.rowGroup++ {
+		n := r.pf.MetaData().RowGroup(r.rowGroup).NumRows()
+		if offset < n {
+			break
+		}
+		offset -= n
+	}
+	if offset > 0 {
+		if err := r.readRowGroup(); err != nil {
+			return err
+		}
+		r.row = int(offset)
+	}
+	return nil
+}
+
+// readRowGroup reads the next row group into r.cols.
+func (r *Reader) readRowGroup() error {
+	r.release()
+	indices := make([]int, r.pf.MetaData().Schema.NumColumns())
+	for i := range indices {
+		indices[i] = i
+	}
+	tbl, err := r.fr.ReadRowGroups(context.Background(), indices, []int{r.rowGroup})
+	if err != nil {
+		return errors.Wrapf(err, "reading row group %d", r.rowGroup)
+	}
+	defer tbl.Release()
+	r.cols = make([]arrow.Array, tbl.NumCols())
+	for i := range r.cols {
+		chunks := tbl.Column(i).Data().Chunks()
+		if len(chunks) == 1 {
+			chunks[0].Retain()
+			r.cols[i] = chunks[0]
+		} else if r.cols[i], err = array.Concatenate(chunks, r.mem); err != nil {
+			return errors.Wrapf(err, "concatenating column %d", i)
+		}
+	}
+	r.n, r.row = int(tbl.NumRows()), 0
+	r.rowGroup++
+	return nil
+}
+
+func (r *Reader) Schema() []idk.Field {
+	return r.schema
+}
+
+func (r *Reader) Read() ([]interface{}, error) {
+	for r.row >= r.n {
+		if r.rowGroup >= r.pf.NumRowGroups() {
+			return nil, io.EOF
+		}
+		if err := r.readRowGroup(); err != nil {
+			return nil, err
+		}
+	}
+	vals := make([]interface{}, len(r.cols))
+	for i, col := range r.cols {
+		val, err := arrowValue(col, r.row)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading column '%s'", r.schema[i].Name())
+		}
+		vals[i] = val
+	}
+	r.row++
+	return vals, nil
+}
+
+func (r *Reader) release() {
+	for _, col := range r.cols {
+		col.Release()
+	}
+	r.cols = nil
+}
+
+func (r *Reader) Close() error {
+	r.release()
+	return r.f.Close()
+}
+
+// schemaFromArrow returns the fields for the columns of a Parquet file.
+func schemaFromArrow(sch *arrow.Schema, floatScale int64, log logger.Logger) ([]idk.Field, error) {
+	schema := make([]idk.Field, len(sch.Fields()))
+	for i, f := range sch.Fields() {
+		fld, err := idk.HeaderToField(f.Name, log)
+		if err == nil {
+			schema[i] = fld
+			continue
+		} else if err != idk.ErrNoFieldSpec {
+			return nil, errors.Wrapf(err, "making field from '%s'", f.Name)
+		}
+		if schema[i], err = fieldFromArrow(f, floatScale); err != nil {
+			return nil, err
+		}
+	}
+	return schema, nil
+}
+
+// fieldFromArrow returns the field for a column with no field spec in its
+// name, based on its type.
+func fieldFromArrow(f arrow.Field, floatScale int64) (idk.Field, error) {
+	name, dest := f.Name, strings.ToLower(f.Name)
+	if dest == name {
+		dest = ""
+	}
+	switch typ := f.Type.(type) {
+	case *arrow.Int8Type, *arrow.Int16Type, *arrow.Int32Type, *arrow.Int64Type,
+		*arrow.Uint8Type, *arrow.Uint16Type, *arrow.Uint32Type, *arrow.Uint64Type:
+		return idk.IntField{NameVal: name, DestNameVal: dest}, nil
+	case *arrow.Float32Type, *arrow.Float64Type:
+		return idk.DecimalField{NameVal: name, DestNameVal: dest, Scale: floatScale}, nil
+	case *arrow.Decimal128Type:
+		return idk.DecimalField{NameVal: name, DestNameVal: dest, Scale: int64(typ.Scale)}, nil
+	case *arrow.BooleanType:
+		return idk.BoolField{NameVal: name, DestNameVal: dest}, nil
+	case *arrow.StringType, *arrow.BinaryType:
+		return idk.StringField{NameVal: name, DestNameVal: dest}, nil
+	case *arrow.TimestampType:
+		granularity := map[arrow.TimeUnit]idk.Unit{
+			arrow.Second:      idk.Second,
+			arrow.Millisecond: idk.Millisecond,
+			arrow.Microsecond: idk.Microsecond,
+			arrow.Nanosecond:  idk.Nanosecond,
+		}[typ.Unit]
+		return idk.TimestampField{NameVal: name, DestNameVal: dest, Granularity: string(granularity)}, nil
+	case *arrow.Date32Type, *arrow.Date64Type:
+		return idk.TimestampField{NameVal: name, DestNameVal: dest}, nil
+	case *arrow.ListType:
+		switch typ.Elem().(type) {
+		case *arrow.StringType, *arrow.BinaryType:
+			return idk.StringArrayField{NameVal: name, DestNameVal: dest}, nil
+		case *arrow.Int8Type, *arrow.Int16Type, *arrow.Int32Type, *arrow.Int64Type,
+			*arrow.Uint8Type, *arrow.Uint16Type, *arrow.Uint32Type, *arrow.Uint64Type:
+			return idk.IDArrayField{NameVal: name, DestNameVal: dest}, nil
+		}
+	}
+	return nil, errors.Errorf("unsupported type %s for column '%s'", f.Type, f.Name)
+}
+
+// arrowValue returns the value at index i of arr as a type understood by
+// idk fields.
+func arrowValue(arr arrow.Array, i int) (interface{}, error) {
+	if arr.IsNull(i) {
+		return nil, nil
+	}
+	switch a := arr.(type) {
+	case *array.Int8:
+		return int64(a.Value(i)), nil
+	case *array.Int16:
+		return int64(a.Value(i)), nil
+	case *array.Int32:
+		return int64(a.Value(i)), nil
+	case *array.Int64:
+		return a.Value(i), nil
+	case *array.Uint8:
+		return int64(a.Value(i)), nil
+	case *array.Uint16:
+		return int64(a.Value(i)), nil
+	case *array.Uint32:
+		return int64(a.Value(i)), nil
+	case *array.Uint64:
+		return a.Value(i), nil
+	case *array.Float32:
+		return float64(a.Value(i)), nil
+	case *array.Float64:
+		return a.Value(i), nil
+	case *array.Decimal128:
+		v := a.Value(i)
+		if !v.BigInt().IsInt64() {
+			return nil, errors.Errorf("decimal value %s out of range", v.BigInt())
+		}
+		return pql.NewDecimal(v.BigInt().Int64(), int64(a.DataType().(*arrow.Decimal128Type).Scale)), nil
+	case *array.Boolean:
+		return a.Value(i), nil
+	case *array.String:
+		return a.Value(i), nil
+	case *array.Binary:
+		return string(a.Value(i)), nil
+	case *array.Timestamp:
+		return a.Value(i).ToTime(a.DataType().(*arrow.TimestampType).Unit), nil
+	case *array.Date32:
+		return a.Value(i).ToTime(), nil
+	case *array.Date64:
+		return a.Value(i).ToTime(), nil
+	case *array.List:
+		start, end := a.ValueOffsets(i)
+		vals := make([]interface{}, 0, end-start)
+		for j := start; j < end; j++ {
+			v, err := arrowValue(a.ListValues(), int(j))
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, v)
+		}
+		return vals, nil
+	default:
+		return nil, errors.Errorf("unsupported type %s", arr.DataType())
+	}
+}
@@ -0,0 +1,132 @@
+package parquet
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	"github.com/apache/arrow/go/v10/parquet"
+	"github.com/apache/arrow/go/v10/parquet/pqarrow"
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/logger"
+)
+
+// writeParquet writes a file with 5 rows, in row groups of 2.
+func writeParquet(t *testing.T, name string) {
+	t.Helper()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "ID", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64},
+		{Name: "ts", Type: &arrow.TimestampType{Unit: arrow.Millisecond}},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+		{Name: "level__Int_0_10", Type: arrow.PrimitiveTypes.Int32},
+	}, nil)
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	tags := b.Field(4).(*array.ListBuilder)
+	for i := 0; i < 5; i++ {
+		b.Field(0).(*array.Int64Builder).Append(int64(i))
+		if i == 1 {
+			b.Field(1).AppendNull()
+		} else {
+			b.Field(1).(*array.StringBuilder).Append(string(rune('a' + i)))
+		}
+		b.Field(2).(*array.Float64Builder).Append(float64(i) + 0.5)
+		b.Field(3).(*array.TimestampBuilder).Append(arrow.Timestamp(int64(i) * 1000))
+		tags.Append(true)
+		tags.ValueBuilder().(*array.StringBuilder).Append("x")
+		b.Field(5).(*array.Int32Builder).Append(int32(i))
+	}
+	rec := b.NewRecord()
+	defer rec.Release()
+	tbl := array.NewTableFromRecords(schema, []arrow.Record{rec})
+	defer tbl.Release()
+
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	props := parquet.NewWriterProperties(parquet.WithMaxRowGroupLength(2))
+	if err := pqarrow.WriteTable(tbl, f, 2, props, pqarrow.DefaultWriterProps()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReader(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "a.parquet")
+	writeParquet(t, name)
+
+	r, err := NewReader(name, 0, 2, logger.NopLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	expSchema := []idk.Field{
+		idk.IntField{NameVal: "ID", DestNameVal: "id"},
+		idk.StringField{NameVal: "name"},
+		idk.DecimalField{NameVal: "score", Scale: 2},
+		idk.TimestampField{NameVal: "ts", Granularity: "ms"},
+		idk.StringArrayField{NameVal: "tags"},
+		idk.IntField{NameVal: "level", DestNameVal: "level", Min: intptr(0), Max: intptr(10)},
+	}
+	if !reflect.DeepEqual(r.Schema(), expSchema) {
+		t.Fatalf("unexpected schema:\n%#v", r.Schema())
+	}
+
+	var rows [][]interface{}
+	for {
+		vals, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		rows = append(rows, vals)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("expected 5 rows, got %d", len(rows))
+	}
+	exp := []interface{}{int64(1), nil, 1.5, time.UnixMilli(1000).UTC(), []interface{}{"x"}, int64(1)}
+	if got := rows[1]; !reflect.DeepEqual(got[:3], exp[:3]) || !got[3].(time.Time).Equal(exp[3].(time.Time)) || !reflect.DeepEqual(got[4:], exp[4:]) {
+		t.Fatalf("unexpected row:\n%#v", got)
+	}
+}
+
+func TestReaderOffset(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "a.parquet")
+	writeParquet(t, name)
+
+	for offset := int64(0); offset <= 5; offset++ {
+		r, err := NewReader(name, offset, 6, logger.NopLogger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for exp := offset; ; exp++ {
+			vals, err := r.Read()
+			if err == io.EOF {
+				if exp != 5 {
+					t.Fatalf("offset %d: unexpected EOF after %d", offset, exp)
+				}
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if vals[0] != exp {
+				t.Fatalf("offset %d: expected ID %d, got %v", offset, exp, vals[0])
+			}
+		}
+		r.Close()
+	}
+}
+
+func intptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,466 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/idk"
+	"github.com/featurebasedb/featurebase/v3/idk/internal"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/pkg/errors"
+)
+
+// IncrementalConfig configures an IncrementalSource.
+type IncrementalConfig struct {
+	// WatermarkColumn is the column whose values increase as rows are
+	// added or updated, e.g. a monotonic id or an updated_at timestamp.
+	WatermarkColumn string
+
+	// PollInterval is the minimum time between the start of each poll.
+	PollInterval time.Duration
+
+	// CheckpointPath, if set, is where the last committed watermark is
+	// written. Polling resumes from it after a restart.
+	CheckpointPath string
+
+	// SoftDeleteColumn, if set, is a column which is true for rows that
+	// have been deleted. Those rows are deleted from FeatureBase rather
+	// than ingested.
+	SoftDeleteColumn string
+
+	// IDField and PrimaryKeyFields identify the FeatureBase record for a
+	// row, and match the options of the same name on idk.Main. One of
+	// them is required with SoftDeleteColumn.
+	IDField          string
+	PrimaryKeyFields []string
+
+	// Deleter deletes the records with the given IDs or keys from
+	// FeatureBase.
+	Deleter func(ids []uint64, keys []string) error
+}
+
+// IncrementalSource repeatedly runs a query, reading only the rows whose
+// watermark column is at least the last complete watermark. Since rows may
+// be added with a watermark equal to one already read (e.g. rows with the
+// same updated_at timestamp), rows at the greatest watermark read are
+// remembered and skipped when they're read again. Rows are read in
+// watermark order, and the watermark is checkpointed as records are
+// committed; rows at the checkpointed watermark are read again after a
+// restart. Once all the rows from a poll have been read, Record returns
+// idk.ErrFlush so that they are ingested, and the next call to Record waits
+// for the poll interval before polling again.
+type IncrementalSource struct {
+	Source
+
+	conn    *sql.DB
+	driver  string
+	rowExpr string
+	cols    []string
+	cfg     IncrementalConfig
+
+	watermarkIdx  int
+	softDeleteIdx int
+	idIdxs        []int
+
+	vals     []interface{}
+	lastPoll time.Time
+	closing  chan struct{}
+
+	// current is the watermark of the last row read, and complete is the
+	// greatest watermark for which every row has been read.
+	current  *watermark
+	complete *watermark
+	last     *incrementalRecord
+
+	// seen holds the rows read at the current watermark.
+	seen map[string]struct{}
+
+	deleteIDs  []uint64
+	deleteKeys []string
+}
+
+// NewIncrementalSource returns an IncrementalSource which polls the results
+// of rowExpr. Polling starts from the checkpoint, if there is one.
+func NewIncrementalSource(driver, connString, rowExpr, separator string, cfg IncrementalConfig, log logger.Logger) (*IncrementalSource, error) {
+	if cfg.SoftDeleteColumn != "" {
+		if cfg.IDField == "" && len(cfg.PrimaryKeyFields) == 0 {
+			return nil, errors.New("soft deletes require an id field or primary key fields")
+		}
+		if cfg.Deleter == nil {
+			return nil, errors.New("soft deletes require a deleter")
+		}
+	}
+	conn, err := sql.Open(driver, connString)
+	if err != nil {
+		return nil, err
+	}
+	s := &IncrementalSource{
+		Source: Source{
+			separator: separator,
+			log:       log,
+		},
+		conn:          conn,
+		driver:        driver,
+		rowExpr:       rowExpr,
+		cfg:           cfg,
+		softDeleteIdx: -1,
+		closing:       make(chan struct{}),
+		seen:          make(map[string]struct{}),
+	}
+	if s.current, err = readCheckpoint(cfg.CheckpointPath); err != nil {
+		return nil, errors.Wrap(err, "reading checkpoint")
+	}
+	s.complete = s.current
+	if s.current != nil {
+		s.log.Printf("resuming from watermark %s", s.current.Value)
+	}
+
+	// The first poll determines the schema.
+	if err := s.poll(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if s.Source.schema, err = schemaFromColumns(s.cols, log); err != nil {
+		s.rows.Close()
+		conn.Close()
+		return nil, err
+	}
+	if err := s.findColumns(); err != nil {
+		s.rows.Close()
+		conn.Close()
+		return nil, err
+	}
+	s.vals = make([]interface{}, len(s.cols))
+	for i := range s.vals {
+		s.vals[i] = new(interface{})
+	}
+	return s, nil
+}
+
+// findColumns finds the indexes of the columns used for the watermark, soft
+// deletes, and record IDs.
+func (s *IncrementalSource) findColumns() error {
+	find := func(name string) int {
+		for i, fld := range s.schema {
+			if s.cols[i] == name || fld.Name() == name {
+				return i
+			}
+		}
+		return -1
+	}
+	if s.watermarkIdx = find(s.cfg.WatermarkColumn); s.watermarkIdx < 0 {
+		return errors.Errorf("watermark column '%s' not found in %v", s.cfg.WatermarkColumn, s.cols)
+	}
+	if s.cfg.SoftDeleteColumn == "" {
+		return nil
+	}
+	if s.softDeleteIdx = find(s.cfg.SoftDeleteColumn); s.softDeleteIdx < 0 {
+		return errors.Errorf("soft delete column '%s' not found in %v", s.cfg.SoftDeleteColumn, s.cols)
+	}
+	idFields := s.cfg.PrimaryKeyFields
+	if len(idFields) == 0 {
+		idFields = []string{s.cfg.IDField}
+	}
+	for _, name := range idFields {
+		idx := -1
+		for i, fld := range s.schema {
+			if fld.DestName() == strings.TrimSpace(name) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return errors.Errorf("id field '%s' not found in %v", name, s.cols)
+		}
+		s.idIdxs = append(s.idIdxs, idx)
+	}
+	return nil
+}
+
+// poll runs the query for rows from the complete watermark, waiting until
+// the poll interval has passed since the previous poll.
+func (s *IncrementalSource) poll() error {
+	if !s.lastPoll.IsZero() {
+		select {
+		case <-s.closing:
+			return io.EOF
+		case <-time.After(time.Until(s.lastPoll.Add(s.cfg.PollInterval))):
+		}
+	}
+	s.lastPoll = time.Now()
+
+	query, args := incrementalQuery(s.driver, s.rowExpr, s.cfg.WatermarkColumn, s.complete)
+	rows, err := s.conn.Query(query, args...)
+	if err != nil {
+		return errors.Wrap(err, "error executing query")
+	}
+	cols, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return err
+	}
+	if s.cols == nil {
+		s.cols = cols
+	} else if strings.Join(cols, ",") != strings.Join(s.cols, ",") {
+		rows.Close()
+		return errors.Errorf("columns changed from %v to %v", s.cols, cols)
+	}
+	s.rows = rows
+	return nil
+}
+
+func (s *IncrementalSource) Record() (idk.Record, error) {
+	for {
+		if s.rows == nil {
+			if err := s.poll(); err != nil {
+				return nil, err
+			}
+		}
+		if !s.rows.Next() {
+			err := s.rows.Err()
+			s.rows.Close()
+			s.rows = nil
+			if err != nil {
+				return nil, errors.Wrap(err, "reading rows")
+			}
+			if err := s.flushDeletes(); err != nil {
+				return nil, errors.Wrap(err, "deleting records")
+			}
+			// Every row up to the current watermark has been read, so the
+			// last record can checkpoint it once it's committed.
+			s.complete = s.current
+			if s.last != nil {
+				s.last.checkpoint = s.complete
+				s.last = nil
+			}
+			return nil, idk.ErrFlush
+		}
+		if err := s.rows.Scan(s.vals...); err != nil {
+			s.log.Printf("scanning row '%v'", err)
+			continue
+		}
+		row := s.convertRow(s.vals)
+
+		wm, err := newWatermark(row[s.watermarkIdx])
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading watermark column '%s'", s.cfg.WatermarkColumn)
+		}
+		if !s.advance(wm, row) {
+			continue
+		}
+
+		if s.softDeleteIdx >= 0 && isTrue(row[s.softDeleteIdx]) {
+			if err := s.addDelete(row); err != nil {
+				return nil, errors.Wrap(err, "getting id of deleted row")
+			}
+			continue
+		}
+
+		rec := &incrementalRecord{src: s, data: row, checkpoint: s.complete}
+		s.last = rec
+		return rec, nil
+	}
+}
+
+// advance moves the current watermark to wm, the watermark of row. It
+// returns false if row has already been read.
+func (s *IncrementalSource) advance(wm *watermark, row []interface{}) bool {
+	key := fmt.Sprintf("%#v", row)
+	if s.current != nil && *wm == *s.current {
+		if _, ok := s.seen[key]; ok {
+			return false
+		}
+	} else {
+		if s.current != nil {
+			s.complete = s.current
+		}
+		s.current = wm
+		for k := range s.seen {
+			delete(s.seen, k)
+		}
+	}
+	s.seen[key] = struct{}{}
+	return true
+}
+
+// addDelete queues the record for row to be deleted at the end of the poll.
+func (s *IncrementalSource) addDelete(row []interface{}) error {
+	if len(s.cfg.PrimaryKeyFields) == 0 {
+		id, err := strconv.ParseUint(fmt.Sprint(row[s.idIdxs[0]]), 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "parsing id '%v'", row[s.idIdxs[0]])
+		}
+		s.deleteIDs = append(s.deleteIDs, id)
+		return nil
+	}
+	// Primary key fields are joined as in idk's primary key recordizer.
+	parts := make([]string, len(s.idIdxs))
+	for i, idx := range s.idIdxs {
+		if row[idx] == nil {
+			return errors.New("data for primary key should not be nil or missing")
+		}
+		parts[i] = fmt.Sprint(row[idx])
+	}
+	s.deleteKeys = append(s.deleteKeys, strings.Join(parts, "|"))
+	return nil
+}
+
+func (s *IncrementalSource) flushDeletes() error {
+	if len(s.deleteIDs) == 0 && len(s.deleteKeys) == 0 {
+		return nil
+	}
+	if err := s.cfg.Deleter(s.deleteIDs, s.deleteKeys); err != nil {
+		return err
+	}
+	s.log.Printf("deleted %d records", len(s.deleteIDs)+len(s.deleteKeys))
+	s.deleteIDs, s.deleteKeys = s.deleteIDs[:0], s.deleteKeys[:0]
+	return nil
+}
+
+func (s *IncrementalSource) Close() error {
+	select {
+	case <-s.closing:
+		return nil
+	default:
+	}
+	close(s.closing)
+	if s.rows != nil {
+		s.rows.Close()
+	}
+	return s.conn.Close()
+}
+
+// incrementalRecord is a record read by an IncrementalSource. Committing
+// it writes its checkpoint, the greatest watermark for which this record and
+// every row before it had been read.
+type incrementalRecord struct {
+	src        *IncrementalSource
+	data       []interface{}
+	checkpoint *watermark
+}
+
+func (r *incrementalRecord) Commit(ctx context.Context) error {
+	if r.checkpoint == nil || r.src.cfg.CheckpointPath == "" {
+		return nil
+	}
+	b, err := json.Marshal(r.checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "marshaling checkpoint")
+	}
+	return internal.WriteFileOrURL(r.src.cfg.CheckpointPath, b, nil)
+}
+
+func (r *incrementalRecord) Schema() interface{} {
+	return nil
+}
+
+func (r *incrementalRecord) Data() []interface{} {
+	return r.data
+}
+
+// watermark is a value of a watermark column. Values are kept as strings
+// so that they can be compared and written to a checkpoint; Type
+// determines how they are passed back to the database.
+type watermark struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func newWatermark(v interface{}) (*watermark, error) {
+	switch v := v.(type) {
+	case int64:
+		return &watermark{Type: "int", Value: strconv.FormatInt(v, 10)}, nil
+	case int32:
+		return &watermark{Type: "int", Value: strconv.FormatInt(int64(v), 10)}, nil
+	case uint64:
+		return &watermark{Type: "uint", Value: strconv.FormatUint(v, 10)}, nil
+	case time.Time:
+		return &watermark{Type: "time", Value: v.UTC().Format(time.RFC3339Nano)}, nil
+	case string:
+		return &watermark{Type: "string", Value: v}, nil
+	case nil:
+		return nil, errors.New("watermark can't be null")
+	default:
+		return nil, errors.Errorf("unsupported watermark type %T", v)
+	}
+}
+
+// arg returns the watermark as a query argument.
+func (w *watermark) arg() (interface{}, error) {
+	switch w.Type {
+	case "int":
+		return strconv.ParseInt(w.Value, 10, 64)
+	case "uint":
+		return strconv.ParseUint(w.Value, 10, 64)
+	case "time":
+		return time.Parse(time.RFC3339Nano, w.Value)
+	case "string":
+		return w.Value, nil
+	default:
+		return nil, errors.Errorf("unknown watermark type '%s'", w.Type)
+	}
+}
+
+// readCheckpoint reads the watermark written at path. It returns nil if
+// path is empty or does not exist.
+func readCheckpoint(path string) (*watermark, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := internal.ReadFileOrURL(path, nil)
+	if err == internal.ErrFileOrURLNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	wm := &watermark{}
+	if err := json.Unmarshal(b, wm); err != nil {
+		return nil, errors.Wrap(err, "unmarshaling checkpoint")
+	}
+	if _, err := wm.arg(); err != nil {
+		return nil, errors.Wrap(err, "invalid checkpoint")
+	}
+	return wm, nil
+}
+
+// incrementalQuery returns the query for rows of rowExpr which are at or
+// past the watermark wm, in watermark order, along with its arguments.
+func incrementalQuery(driver, rowExpr, column string, wm *watermark) (string, []interface{}) {
+	quoted := `"` + strings.ReplaceAll(column, `"`, `""`) + `"`
+	placeholder := "$1"
+	switch driver {
+	case "mysql":
+		quoted = "`" + strings.ReplaceAll(column, "`", "``") + "`"
+		placeholder = "?"
+	case "sqlserver", "mssql":
+		placeholder = "@p1"
+	}
+	if wm == nil {
+		return fmt.Sprintf("SELECT * FROM (%s) AS idk_incremental ORDER BY %s", rowExpr, quoted), nil
+	}
+	// The checkpoint was validated when it was read, and other watermarks
+	// come from newWatermark, so arg can't fail here.
+	arg, _ := wm.arg()
+	return fmt.Sprintf("SELECT * FROM (%s) AS idk_incremental WHERE %s >= %s ORDER BY %[2]s", rowExpr, quoted, placeholder), []interface{}{arg}
+}
+
+// isTrue reports whether a soft delete column value marks a row as deleted.
+func isTrue(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case string:
+		b, err := strconv.ParseBool(v)
+		return err == nil && b
+	default:
+		return false
+	}
+}
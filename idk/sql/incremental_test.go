@@ -0,0 +1,162 @@
+package sql
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIncrementalQuery(t *testing.T) {
+	wm, err := newWatermark(int64(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		driver string
+		wm     *watermark
+		query  string
+		args   []interface{}
+	}{
+		{
+			driver: "postgres",
+			query:  `SELECT * FROM (select id as id__ID from t) AS idk_incremental ORDER BY "id__ID"`,
+		},
+		{
+			driver: "postgres",
+			wm:     wm,
+			query:  `SELECT * FROM (select id as id__ID from t) AS idk_incremental WHERE "id__ID" >= $1 ORDER BY "id__ID"`,
+			args:   []interface{}{int64(42)},
+		},
+		{
+			driver: "mysql",
+			wm:     wm,
+			query:  "SELECT * FROM (select id as id__ID from t) AS idk_incremental WHERE `id__ID` >= ? ORDER BY `id__ID`",
+			args:   []interface{}{int64(42)},
+		},
+		{
+			driver: "sqlserver",
+			wm:     wm,
+			query:  `SELECT * FROM (select id as id__ID from t) AS idk_incremental WHERE "id__ID" >= @p1 ORDER BY "id__ID"`,
+			args:   []interface{}{int64(42)},
+		},
+	}
+	for _, test := range tests {
+		query, args := incrementalQuery(test.driver, "select id as id__ID from t", "id__ID", test.wm)
+		if query != test.query {
+			t.Errorf("%s: expected query:\n%s\ngot:\n%s", test.driver, test.query, query)
+		}
+		if !reflect.DeepEqual(args, test.args) {
+			t.Errorf("%s: expected args %v, got %v", test.driver, test.args, args)
+		}
+	}
+}
+
+func TestWatermark(t *testing.T) {
+	ts := time.Date(2022, 3, 4, 5, 6, 7, 8, time.FixedZone("x", 3600))
+	for _, v := range []interface{}{int64(-3), uint64(7), ts, "abc"} {
+		wm, err := newWatermark(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		arg, err := wm.arg()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp, ok := v.(time.Time); ok {
+			if !exp.Equal(arg.(time.Time)) {
+				t.Errorf("expected %v, got %v", exp, arg)
+			}
+		} else if arg != v {
+			t.Errorf("expected %v, got %v", v, arg)
+		}
+	}
+	if _, err := newWatermark(nil); err == nil {
+		t.Error("expected error for null watermark")
+	}
+	if _, err := newWatermark(1.5); err == nil {
+		t.Error("expected error for float watermark")
+	}
+}
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	if wm, err := readCheckpoint(path); err != nil || wm != nil {
+		t.Fatalf("expected no checkpoint, got %v, %v", wm, err)
+	}
+
+	s := &IncrementalSource{cfg: IncrementalConfig{CheckpointPath: path}}
+	// A record with no complete watermark doesn't write a checkpoint.
+	if err := (&incrementalRecord{src: s}).Commit(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if wm, err := readCheckpoint(path); err != nil || wm != nil {
+		t.Fatalf("expected no checkpoint, got %v, %v", wm, err)
+	}
+
+	exp := &watermark{Type: "time", Value: "2022-03-04T05:06:07Z"}
+	if err := (&incrementalRecord{src: s, checkpoint: exp}).Commit(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	wm, err := readCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(wm, exp) {
+		t.Fatalf("expected checkpoint %v, got %v", exp, wm)
+	}
+}
+
+func TestIncrementalSource_Advance(t *testing.T) {
+	s := &IncrementalSource{seen: make(map[string]struct{})}
+	wm := func(v int64) *watermark {
+		w, err := newWatermark(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w
+	}
+
+	for i, test := range []struct {
+		wm       int64
+		row      []interface{}
+		exp      bool
+		complete *watermark
+	}{
+		{wm: 1, row: []interface{}{int64(1), "a"}, exp: true},
+		{wm: 1, row: []interface{}{int64(1), "b"}, exp: true},
+		{wm: 2, row: []interface{}{int64(2), "c"}, exp: true, complete: wm(1)},
+		// a later poll reads the rows at the last watermark again, along
+		// with any rows added at that watermark since
+		{wm: 2, row: []interface{}{int64(2), "c"}, exp: false, complete: wm(1)},
+		{wm: 2, row: []interface{}{int64(2), "d"}, exp: true, complete: wm(1)},
+		{wm: 3, row: []interface{}{int64(3), "c"}, exp: true, complete: wm(2)},
+	} {
+		if got := s.advance(wm(test.wm), test.row); got != test.exp {
+			t.Fatalf("%d: expected %v, got %v", i, test.exp, got)
+		}
+		if !reflect.DeepEqual(s.complete, test.complete) {
+			t.Fatalf("%d: expected complete watermark %v, got %v", i, test.complete, s.complete)
+		}
+	}
+}
+
+func TestIsTrue(t *testing.T) {
+	for v, exp := range map[interface{}]bool{
+		true:      true,
+		false:     false,
+		int64(1):  true,
+		int64(0):  false,
+		"t":       true,
+		"false":   false,
+		"deleted": false,
+	} {
+		if got := isTrue(v); got != exp {
+			t.Errorf("isTrue(%v): expected %v, got %v", v, exp, got)
+		}
+	}
+	if isTrue(nil) {
+		t.Error("isTrue(nil): expected false")
+	}
+}
@@ -0,0 +1,191 @@
+package idk
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/featurebasedb/featurebase/v3/idk/internal"
+	"github.com/featurebasedb/featurebase/v3/idk/transform"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/pkg/errors"
+)
+
+// Transform derives the values of a field from an expression over the
+// fields of each record. If Field has the same name as a field of the
+// source, it replaces that field; otherwise it is added after the source's
+// fields. Expressions may refer to source fields, and to fields produced by
+// earlier transforms, by name. See the transform package for the expression
+// language.
+type Transform struct {
+	Field Field
+	Expr  string
+}
+
+// ParseTransform parses a transform written as "header=expression", where
+// header is a field spec as used in CSV headers, e.g.
+// "full_name__String=concat(first, ' ', last)".
+func ParseTransform(spec string, log logger.Logger) (Transform, error) {
+	idx := strings.Index(spec, "=")
+	if idx == -1 {
+		return Transform{}, errors.Errorf("transform '%s' must be of the form header=expression", spec)
+	}
+	return newTransform(strings.TrimSpace(spec[:idx]), strings.TrimSpace(spec[idx+1:]), log)
+}
+
+// ParseTransforms parses a JSON array of transforms, each an object with
+// "field" (a field spec as used in CSV headers) and "expr" keys.
+func ParseTransforms(raw []byte, log logger.Logger) ([]Transform, error) {
+	var specs []struct {
+		Field string `json:"field"`
+		Expr  string `json:"expr"`
+	}
+	if err := json.Unmarshal(raw, &specs); err != nil {
+		return nil, errors.Wrap(err, "parsing transforms")
+	}
+	transforms := make([]Transform, len(specs))
+	for i, spec := range specs {
+		t, err := newTransform(spec.Field, spec.Expr, log)
+		if err != nil {
+			return nil, err
+		}
+		transforms[i] = t
+	}
+	return transforms, nil
+}
+
+func newTransform(header, expr string, log logger.Logger) (Transform, error) {
+	field, err := HeaderToField(header, log)
+	if err != nil {
+		return Transform{}, errors.Wrapf(err, "parsing transform field '%s'", header)
+	}
+	if _, err := transform.Parse(expr); err != nil {
+		return Transform{}, errors.Wrapf(err, "parsing transform expression for '%s'", header)
+	}
+	return Transform{Field: field, Expr: expr}, nil
+}
+
+// loadTransforms parses the transforms given by --transforms and
+// --transforms-file.
+func (m *Main) loadTransforms() error {
+	m.transforms = m.transforms[:0]
+	if m.TransformsFile != "" {
+		raw, err := internal.ReadFileOrURL(m.TransformsFile, nil)
+		if err != nil {
+			return errors.Wrap(err, "reading transforms file")
+		}
+		transforms, err := ParseTransforms(raw, m.log)
+		if err != nil {
+			return err
+		}
+		m.transforms = append(m.transforms, transforms...)
+	}
+	for _, spec := range m.Transforms {
+		t, err := ParseTransform(spec, m.log)
+		if err != nil {
+			return err
+		}
+		m.transforms = append(m.transforms, t)
+	}
+	return nil
+}
+
+// transformSource applies transforms to the records of a Source.
+type transformSource struct {
+	Source
+	transforms []Transform
+
+	schema []Field
+	exprs  []*transform.Expr
+	// indexes holds the index in the schema of each transform's field.
+	indexes []int
+}
+
+// TransformSource wraps src so that its records have the given transforms
+// applied.
+func TransformSource(src Source, transforms []Transform) Source {
+	return &transformSource{Source: src, transforms: transforms}
+}
+
+func (s *transformSource) Record() (Record, error) {
+	rec, err := s.Source.Record()
+	if err == ErrSchemaChange || err == nil && s.schema == nil {
+		if serr := s.compile(s.Source.Schema()); serr != nil {
+			return nil, serr
+		}
+		err = ErrSchemaChange
+	}
+	if rec == nil || err != nil && err != ErrSchemaChange {
+		return rec, err
+	}
+
+	data := rec.Data()
+	vals := make([]interface{}, len(s.schema))
+	copy(vals, data)
+	for i, expr := range s.exprs {
+		v, eerr := expr.Eval(vals)
+		if eerr != nil {
+			return nil, errors.Wrapf(eerr, "transforming field '%s'", s.transforms[i].Field.Name())
+		}
+		vals[s.indexes[i]] = v
+	}
+	if orec, ok := rec.(OffsetStreamRecord); ok {
+		return &transformedOffsetRecord{transformedRecord{rec, vals}, orec}, err
+	}
+	return &transformedRecord{rec, vals}, err
+}
+
+// compile works out the schema of transformed records and compiles each
+// transform's expression against it.
+func (s *transformSource) compile(schema []Field) error {
+	s.schema = append(make([]Field, 0, len(schema)+len(s.transforms)), schema...)
+	s.exprs = make([]*transform.Expr, len(s.transforms))
+	s.indexes = make([]int, len(s.transforms))
+	names := make(map[string]int, len(schema))
+	for i, f := range schema {
+		names[f.Name()] = i
+	}
+	for i, t := range s.transforms {
+		// Compile before adding the field, so that a transform which
+		// replaces a field refers to the original values.
+		expr, err := transform.Compile(t.Expr, func(name string) (int, bool) {
+			idx, ok := names[name]
+			return idx, ok
+		})
+		if err != nil {
+			return errors.Wrapf(err, "compiling transform for '%s'", t.Field.Name())
+		}
+		s.exprs[i] = expr
+		idx, ok := names[t.Field.Name()]
+		if ok {
+			s.schema[idx] = t.Field
+		} else {
+			idx = len(s.schema)
+			s.schema = append(s.schema, t.Field)
+			names[t.Field.Name()] = idx
+		}
+		s.indexes[i] = idx
+	}
+	return nil
+}
+
+func (s *transformSource) Schema() []Field {
+	return s.schema
+}
+
+type transformedRecord struct {
+	Record
+	data []interface{}
+}
+
+func (r *transformedRecord) Data() []interface{} {
+	return r.data
+}
+
+type transformedOffsetRecord struct {
+	transformedRecord
+	orec OffsetStreamRecord
+}
+
+func (r *transformedOffsetRecord) StreamOffset() (string, uint64) {
+	return r.orec.StreamOffset()
+}
@@ -0,0 +1,404 @@
+package transform
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/pkg/errors"
+)
+
+type node interface {
+	eval(vals []interface{}) (interface{}, error)
+}
+
+type literalNode struct {
+	val interface{}
+}
+
+func (n *literalNode) eval([]interface{}) (interface{}, error) {
+	return n.val, nil
+}
+
+type fieldNode struct {
+	name  string
+	index int
+}
+
+func (n *fieldNode) eval(vals []interface{}) (interface{}, error) {
+	if n.index >= len(vals) {
+		return nil, nil
+	}
+	return normalize(vals[n.index]), nil
+}
+
+type callNode struct {
+	name string
+	fn   func(args []interface{}) (interface{}, error)
+	args []node
+}
+
+func (n *callNode) eval(vals []interface{}) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(vals)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	v, err := n.fn(args)
+	return v, errors.Wrap(err, n.name)
+}
+
+// ifNode only evaluates the branch which is taken.
+type ifNode struct {
+	cond, then, els node
+}
+
+func (n *ifNode) eval(vals []interface{}) (interface{}, error) {
+	c, err := n.cond.eval(vals)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := truth(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "if")
+	}
+	if ok {
+		return n.then.eval(vals)
+	}
+	return n.els.eval(vals)
+}
+
+type andNode struct {
+	left, right node
+}
+
+func (n *andNode) eval(vals []interface{}) (interface{}, error) {
+	l, err := evalBool(n.left, vals)
+	if err != nil || !l {
+		return false, err
+	}
+	return evalBool(n.right, vals)
+}
+
+type orNode struct {
+	left, right node
+}
+
+func (n *orNode) eval(vals []interface{}) (interface{}, error) {
+	l, err := evalBool(n.left, vals)
+	if err != nil || l {
+		return l, err
+	}
+	return evalBool(n.right, vals)
+}
+
+func evalBool(n node, vals []interface{}) (bool, error) {
+	v, err := n.eval(vals)
+	if err != nil {
+		return false, err
+	}
+	return truth(v)
+}
+
+// truth interprets v as a condition. Null is false.
+func truth(v interface{}) (bool, error) {
+	switch v := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	}
+	return false, errors.Errorf("expected a boolean, got %v (%[1]T)", v)
+}
+
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(vals []interface{}) (interface{}, error) {
+	v, err := n.operand.eval(vals)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		b, err := truth(v)
+		return !b, err
+	}
+	switch v := v.(type) {
+	case int64:
+		return -v, nil
+	case float64:
+		return -v, nil
+	}
+	return nil, errors.Errorf("can't negate %v (%[1]T)", v)
+}
+
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(vals []interface{}) (interface{}, error) {
+	l, err := n.left.eval(vals)
+	if err != nil {
+		return nil, err
+	}
+	r, err := n.right.eval(vals)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equal(l, r), nil
+	case "!=":
+		return !equal(l, r), nil
+	}
+	if l == nil || r == nil {
+		return nil, nil
+	}
+	switch n.op {
+	case "<", "<=", ">", ">=":
+		c, err := compare(l, r)
+		if err != nil {
+			return nil, err
+		}
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		default:
+			return c >= 0, nil
+		}
+	}
+	return arithmetic(n.op, l, r)
+}
+
+// normalize converts the values produced by sources to the types used by
+// expressions: int64, float64, string, bool, time.Time, []interface{} and nil.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint:
+		return normalize(uint64(v))
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		if v > math.MaxInt64 {
+			return float64(v)
+		}
+		return int64(v)
+	case float32:
+		return float64(v)
+	case []byte:
+		return string(v)
+	case pql.Decimal:
+		return v.Float64()
+	case *pql.Decimal:
+		if v == nil {
+			return nil
+		}
+		return v.Float64()
+	case []string:
+		arr := make([]interface{}, len(v))
+		for i, s := range v {
+			arr[i] = s
+		}
+		return arr
+	case []uint64:
+		arr := make([]interface{}, len(v))
+		for i, u := range v {
+			arr[i] = normalize(u)
+		}
+		return arr
+	case []int64:
+		arr := make([]interface{}, len(v))
+		for i, u := range v {
+			arr[i] = u
+		}
+		return arr
+	case []interface{}:
+		arr := make([]interface{}, len(v))
+		for i, e := range v {
+			arr[i] = normalize(e)
+		}
+		return arr
+	}
+	return v
+}
+
+// equal reports whether two values are equal. Integers and floats are
+// compared numerically, and null only equals null.
+func equal(l, r interface{}) bool {
+	if l == nil || r == nil {
+		return l == nil && r == nil
+	}
+	if c, err := compare(l, r); err == nil {
+		return c == 0
+	}
+	if lb, ok := l.(bool); ok {
+		rb, ok := r.(bool)
+		return ok && lb == rb
+	}
+	return false
+}
+
+// compare returns -1, 0 or 1 as l is less than, equal to or greater than r.
+func compare(l, r interface{}) (int, error) {
+	switch lv := l.(type) {
+	case int64:
+		switch rv := r.(type) {
+		case int64:
+			return cmpInt(lv, rv), nil
+		case float64:
+			return cmpFloat(float64(lv), rv), nil
+		}
+	case float64:
+		switch rv := r.(type) {
+		case int64:
+			return cmpFloat(lv, float64(rv)), nil
+		case float64:
+			return cmpFloat(lv, rv), nil
+		}
+	case string:
+		if rv, ok := r.(string); ok {
+			return strings.Compare(lv, rv), nil
+		}
+	case time.Time:
+		if rv, ok := r.(time.Time); ok {
+			switch {
+			case lv.Before(rv):
+				return -1, nil
+			case lv.After(rv):
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, errors.Errorf("can't compare %v (%[1]T) with %v (%[2]T)", l, r)
+}
+
+func cmpInt(l, r int64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+
+func cmpFloat(l, r float64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+
+func arithmetic(op string, l, r interface{}) (interface{}, error) {
+	if op == "+" {
+		// + concatenates if either side is a string.
+		_, ls := l.(string)
+		_, rs := r.(string)
+		if ls || rs {
+			return toString(l) + toString(r), nil
+		}
+	}
+	li, lint := l.(int64)
+	ri, rint := r.(int64)
+	if lint && rint {
+		switch op {
+		case "+":
+			return li + ri, nil
+		case "-":
+			return li - ri, nil
+		case "*":
+			return li * ri, nil
+		case "/", "%":
+			if ri == 0 {
+				return nil, errors.New("division by zero")
+			}
+			if op == "/" {
+				return li / ri, nil
+			}
+			return li % ri, nil
+		}
+	}
+	lf, lok := toFloat(l)
+	rf, rok := toFloat(r)
+	if !lok || !rok {
+		return nil, errors.Errorf("invalid operands for %s: %v (%[2]T) and %v (%[3]T)", op, l, r)
+	}
+	switch op {
+	case "+":
+		return lf + rf, nil
+	case "-":
+		return lf - rf, nil
+	case "*":
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return lf / rf, nil
+	}
+	return math.Mod(lf, rf), nil
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// toString formats a value as a string.
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []interface{}:
+		s := make([]string, len(v))
+		for i, e := range v {
+			s[i] = toString(e)
+		}
+		return strings.Join(s, ",")
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,470 @@
+package transform
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type function struct {
+	// minArgs and maxArgs bound the number of arguments. A maxArgs of -1
+	// means any number.
+	minArgs, maxArgs int
+	fn               func(args []interface{}) (interface{}, error)
+}
+
+// functions are the functions available to expressions. Unless noted, a
+// null argument makes the result null.
+var functions = map[string]function{
+	// Strings.
+	"lower":   {1, 1, strFunc(strings.ToLower)},
+	"upper":   {1, 1, strFunc(strings.ToUpper)},
+	"trim":    {1, 1, strFunc(strings.TrimSpace)},
+	"str":     {1, 1, nullable(func(args []interface{}) (interface{}, error) { return toString(args[0]), nil })},
+	"len":     {1, 1, nullable(fnLen)},
+	"concat":  {1, -1, fnConcat},
+	"substr":  {2, 3, nullable(fnSubstr)},
+	"replace": {3, 3, nullable(fnReplace)},
+	"split":   {2, 2, nullable(fnSplit)},
+	"join":    {2, 2, nullable(fnJoin)},
+	"contains": {2, 2, nullable(func(args []interface{}) (interface{}, error) {
+		return strings.Contains(toString(args[0]), toString(args[1])), nil
+	})},
+	"match":   {2, 2, nullable(fnMatch)},
+	"extract": {2, 2, nullable(fnExtract)},
+
+	// Numbers.
+	"int":   {1, 1, nullable(fnInt)},
+	"float": {1, 1, nullable(fnFloat)},
+	"abs":   {1, 1, floatFunc(math.Abs)},
+	"floor": {1, 1, floatFunc(math.Floor)},
+	"ceil":  {1, 1, floatFunc(math.Ceil)},
+	"round": {1, 2, nullable(fnRound)},
+	"min":   {1, -1, nullable(func(args []interface{}) (interface{}, error) { return extreme(args, -1) })},
+	"max":   {1, -1, nullable(func(args []interface{}) (interface{}, error) { return extreme(args, 1) })},
+
+	// Times.
+	"from_epoch":  {1, 2, nullable(fnFromEpoch)},
+	"epoch":       {1, 2, nullable(fnEpoch)},
+	"parse_time":  {2, 2, nullable(fnParseTime)},
+	"format_time": {2, 2, nullable(fnFormatTime)},
+	"trunc_time":  {2, 2, nullable(fnTruncTime)},
+	"year":        {1, 1, timeFunc(func(t time.Time) int64 { return int64(t.Year()) })},
+	"month":       {1, 1, timeFunc(func(t time.Time) int64 { return int64(t.Month()) })},
+	"day":         {1, 1, timeFunc(func(t time.Time) int64 { return int64(t.Day()) })},
+	"hour":        {1, 1, timeFunc(func(t time.Time) int64 { return int64(t.Hour()) })},
+	"weekday":     {1, 1, timeFunc(func(t time.Time) int64 { return int64(t.Weekday()) })},
+
+	// Conditionals. if is evaluated lazily by ifNode, and is only listed
+	// here for its arity. Null arguments are passed through.
+	"if":       {3, 3, nil},
+	"coalesce": {1, -1, fnCoalesce},
+	"is_null":  {1, 1, func(args []interface{}) (interface{}, error) { return args[0] == nil, nil }},
+	"map":      {3, -1, fnMap},
+
+	// Hashes.
+	"hash":   {1, 1, nullable(fnHash)},
+	"sha256": {1, 1, nullable(fnSHA256)},
+}
+
+// nullable wraps fn so that it returns null if any argument is null.
+func nullable(fn func(args []interface{}) (interface{}, error)) func(args []interface{}) (interface{}, error) {
+	return func(args []interface{}) (interface{}, error) {
+		for _, arg := range args {
+			if arg == nil {
+				return nil, nil
+			}
+		}
+		return fn(args)
+	}
+}
+
+func strFunc(fn func(string) string) func(args []interface{}) (interface{}, error) {
+	return nullable(func(args []interface{}) (interface{}, error) {
+		return fn(toString(args[0])), nil
+	})
+}
+
+func floatFunc(fn func(float64) float64) func(args []interface{}) (interface{}, error) {
+	return nullable(func(args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case int64:
+			return int64(fn(float64(v))), nil
+		case float64:
+			return fn(v), nil
+		}
+		return nil, errors.Errorf("expected a number, got %v (%[1]T)", args[0])
+	})
+}
+
+func timeFunc(fn func(time.Time) int64) func(args []interface{}) (interface{}, error) {
+	return nullable(func(args []interface{}) (interface{}, error) {
+		t, err := toTime(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return fn(t), nil
+	})
+}
+
+func toInt(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		s := strings.TrimSpace(v)
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, errors.Errorf("can't convert '%s' to an integer", v)
+		}
+		return int64(f), nil
+	case time.Time:
+		return v.Unix(), nil
+	}
+	return 0, errors.Errorf("can't convert %v (%[1]T) to an integer", v)
+}
+
+func toTime(v interface{}) (time.Time, error) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, errors.Wrapf(err, "parsing time '%s'", v)
+	case int64:
+		return time.Unix(v, 0).UTC(), nil
+	}
+	return time.Time{}, errors.Errorf("expected a time, got %v (%[1]T)", v)
+}
+
+func fnLen(args []interface{}) (interface{}, error) {
+	if arr, ok := args[0].([]interface{}); ok {
+		return int64(len(arr)), nil
+	}
+	return int64(len([]rune(toString(args[0])))), nil
+}
+
+// fnConcat concatenates its arguments, skipping nulls.
+func fnConcat(args []interface{}) (interface{}, error) {
+	var sb strings.Builder
+	for _, arg := range args {
+		sb.WriteString(toString(arg))
+	}
+	return sb.String(), nil
+}
+
+// fnSubstr returns length runes of a string, starting at the 0-based index
+// start, or the rest of the string if length is omitted.
+func fnSubstr(args []interface{}) (interface{}, error) {
+	s := []rune(toString(args[0]))
+	start, err := toInt(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if start < 0 {
+		start = 0
+	} else if start > int64(len(s)) {
+		start = int64(len(s))
+	}
+	end := int64(len(s))
+	if len(args) > 2 {
+		n, err := toInt(args[2])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, errors.New("negative length")
+		}
+		if start+n < end {
+			end = start + n
+		}
+	}
+	return string(s[start:end]), nil
+}
+
+func fnReplace(args []interface{}) (interface{}, error) {
+	return strings.ReplaceAll(toString(args[0]), toString(args[1]), toString(args[2])), nil
+}
+
+// fnSplit splits a string on a separator, for use with array fields. An empty
+// string produces an empty array.
+func fnSplit(args []interface{}) (interface{}, error) {
+	s := toString(args[0])
+	if s == "" {
+		return []interface{}{}, nil
+	}
+	parts := strings.Split(s, toString(args[1]))
+	arr := make([]interface{}, len(parts))
+	for i, p := range parts {
+		arr[i] = p
+	}
+	return arr, nil
+}
+
+func fnJoin(args []interface{}) (interface{}, error) {
+	arr, ok := args[0].([]interface{})
+	if !ok {
+		return nil, errors.Errorf("expected an array, got %v (%[1]T)", args[0])
+	}
+	parts := make([]string, len(arr))
+	for i, e := range arr {
+		parts[i] = toString(e)
+	}
+	return strings.Join(parts, toString(args[1])), nil
+}
+
+var regexps sync.Map
+
+func compileRegexp(expr string) (*regexp.Regexp, error) {
+	if re, ok := regexps.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "compiling '%s'", expr)
+	}
+	regexps.Store(expr, re)
+	return re, nil
+}
+
+func fnMatch(args []interface{}) (interface{}, error) {
+	re, err := compileRegexp(toString(args[1]))
+	if err != nil {
+		return nil, err
+	}
+	return re.MatchString(toString(args[0])), nil
+}
+
+// fnExtract returns the first submatch of a regular expression, or the whole
+// match if it has no groups. It returns null if there is no match.
+func fnExtract(args []interface{}) (interface{}, error) {
+	re, err := compileRegexp(toString(args[1]))
+	if err != nil {
+		return nil, err
+	}
+	m := re.FindStringSubmatch(toString(args[0]))
+	switch {
+	case m == nil:
+		return nil, nil
+	case len(m) > 1:
+		return m[1], nil
+	}
+	return m[0], nil
+}
+
+func fnInt(args []interface{}) (interface{}, error) {
+	return toInt(args[0])
+}
+
+func fnFloat(args []interface{}) (interface{}, error) {
+	switch v := args[0].(type) {
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, errors.Errorf("can't convert '%s' to a float", v)
+		}
+		return f, nil
+	}
+	return nil, errors.Errorf("can't convert %v (%[1]T) to a float", args[0])
+}
+
+// fnRound rounds to the given number of decimal places, 0 by default.
+func fnRound(args []interface{}) (interface{}, error) {
+	var places int64
+	if len(args) > 1 {
+		var err error
+		if places, err = toInt(args[1]); err != nil {
+			return nil, err
+		}
+	}
+	switch v := args[0].(type) {
+	case int64:
+		return v, nil
+	case float64:
+		p := math.Pow(10, float64(places))
+		return math.Round(v*p) / p, nil
+	}
+	return nil, errors.Errorf("expected a number, got %v (%[1]T)", args[0])
+}
+
+// extreme returns the least argument if sign is -1, or the greatest if it is
+// 1.
+func extreme(args []interface{}, sign int) (interface{}, error) {
+	best := args[0]
+	for _, arg := range args[1:] {
+		c, err := compare(arg, best)
+		if err != nil {
+			return nil, err
+		}
+		if c == sign {
+			best = arg
+		}
+	}
+	return best, nil
+}
+
+func unitDuration(v interface{}) (time.Duration, error) {
+	switch u := toString(v); u {
+	case "s":
+		return time.Second, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "us":
+		return time.Microsecond, nil
+	case "ns":
+		return time.Nanosecond, nil
+	default:
+		return 0, errors.Errorf("unknown unit '%s', please choose from s/ms/us/ns", u)
+	}
+}
+
+// fnFromEpoch converts an integer number of units, seconds by default, since
+// the Unix epoch to a time.
+func fnFromEpoch(args []interface{}) (interface{}, error) {
+	n, err := toInt(args[0])
+	if err != nil {
+		return nil, err
+	}
+	unit := time.Second
+	if len(args) > 1 {
+		if unit, err = unitDuration(args[1]); err != nil {
+			return nil, err
+		}
+	}
+	return time.Unix(0, 0).Add(time.Duration(n) * unit).UTC(), nil
+}
+
+// fnEpoch converts a time to the number of units, seconds by default, since
+// the Unix epoch.
+func fnEpoch(args []interface{}) (interface{}, error) {
+	t, err := toTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+	unit := time.Second
+	if len(args) > 1 {
+		if unit, err = unitDuration(args[1]); err != nil {
+			return nil, err
+		}
+	}
+	return t.UnixNano() / int64(unit), nil
+}
+
+// layout returns a Go time layout, accepting the names of the layouts in the
+// time package.
+func layout(v interface{}) string {
+	switch l := toString(v); l {
+	case "RFC3339":
+		return time.RFC3339
+	case "RFC3339Nano":
+		return time.RFC3339Nano
+	case "RFC1123":
+		return time.RFC1123
+	case "DateOnly":
+		return "2006-01-02"
+	default:
+		return l
+	}
+}
+
+func fnParseTime(args []interface{}) (interface{}, error) {
+	t, err := time.Parse(layout(args[1]), toString(args[0]))
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func fnFormatTime(args []interface{}) (interface{}, error) {
+	t, err := toTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return t.Format(layout(args[1])), nil
+}
+
+// fnTruncTime truncates a time to the start of its year, month, day, hour,
+// minute or second.
+func fnTruncTime(args []interface{}) (interface{}, error) {
+	t, err := toTime(args[0])
+	if err != nil {
+		return nil, err
+	}
+	switch u := toString(args[1]); u {
+	case "year":
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location()), nil
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()), nil
+	case "day":
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()), nil
+	case "hour":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()), nil
+	case "minute":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location()), nil
+	case "second":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, t.Location()), nil
+	default:
+		return nil, errors.Errorf("unknown unit '%s', please choose from year/month/day/hour/minute/second", u)
+	}
+}
+
+// fnCoalesce returns the first argument which isn't null.
+func fnCoalesce(args []interface{}) (interface{}, error) {
+	for _, arg := range args {
+		if arg != nil {
+			return arg, nil
+		}
+	}
+	return nil, nil
+}
+
+// fnMap maps a value through pairs of keys and results:
+// map(v, k1, r1, k2, r2, ..., default). If no key equals v, it returns the
+// default, or null if there is none.
+func fnMap(args []interface{}) (interface{}, error) {
+	v, pairs := args[0], args[1:]
+	for ; len(pairs) >= 2; pairs = pairs[2:] {
+		if equal(v, pairs[0]) {
+			return pairs[1], nil
+		}
+	}
+	if len(pairs) == 1 {
+		return pairs[0], nil
+	}
+	return nil, nil
+}
+
+// fnHash returns a non-negative 63-bit FNV-1a hash of a value's string form,
+// suitable for use as an ID.
+func fnHash(args []interface{}) (interface{}, error) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(toString(args[0])))
+	return int64(h.Sum64() >> 1), nil
+}
+
+func fnSHA256(args []interface{}) (interface{}, error) {
+	sum := sha256.Sum256([]byte(toString(args[0])))
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,394 @@
+// Package transform implements the small expression language used to derive
+// field values during ingest.
+//
+// An expression combines the values of source fields, referred to by name,
+// with literals, operators and function calls:
+//
+//	concat(lower(first), " ", lower(last))
+//	if(score >= 90, "high", "low")
+//	trunc_time(from_epoch(created_ms, "ms"), "day")
+//
+// Literals are integers, floats, strings in single or double quotes, true,
+// false and null. Field names which aren't plain identifiers may be quoted
+// with backticks. The operators, from lowest to highest precedence, are
+// ||, &&, the comparisons == != < <= > >=, + -, * / %, and the unary - and !.
+// Null propagates through operators and most functions.
+package transform
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Expr is a compiled expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Parse parses an expression, returning the names of the fields it refers
+// to. The expression can't be evaluated until it has been compiled with
+// Compile.
+func Parse(src string) (vars []string, err error) {
+	p := &parser{lex: lexer{src: src}, resolve: func(name string) (int, bool) {
+		for _, v := range vars {
+			if v == name {
+				return 0, true
+			}
+		}
+		vars = append(vars, name)
+		return 0, true
+	}}
+	if _, err := p.parse(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// Compile parses an expression, using resolve to find the index of each
+// field it refers to in the values passed to Eval.
+func Compile(src string, resolve func(name string) (int, bool)) (*Expr, error) {
+	p := &parser{lex: lexer{src: src}, resolve: resolve}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// Eval evaluates the expression against the values of a record.
+func (e *Expr) Eval(vals []interface{}) (interface{}, error) {
+	return e.root.eval(vals)
+}
+
+func (e *Expr) String() string {
+	return e.src
+}
+
+type tokenType int
+
+const (
+	tokEOF tokenType = iota
+	tokIdent
+	tokQuotedIdent
+	tokInt
+	tokFloat
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	typ tokenType
+	val string
+	pos int
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+// operators are the operator tokens, with longer ones first.
+var operators = []string{"||", "&&", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!"}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) {
+		r, n := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		l.pos += n
+	}
+	start := l.pos
+	if l.pos >= len(l.src) {
+		return token{typ: tokEOF, pos: start}, nil
+	}
+	c := l.src[l.pos]
+	switch {
+	case c == '(':
+		l.pos++
+		return token{typ: tokLParen, val: "(", pos: start}, nil
+	case c == ')':
+		l.pos++
+		return token{typ: tokRParen, val: ")", pos: start}, nil
+	case c == ',':
+		l.pos++
+		return token{typ: tokComma, val: ",", pos: start}, nil
+	case c == '"' || c == '\'' || c == '`':
+		val, err := l.quoted(c)
+		if err != nil {
+			return token{}, err
+		}
+		if c == '`' {
+			return token{typ: tokQuotedIdent, val: val, pos: start}, nil
+		}
+		return token{typ: tokString, val: val, pos: start}, nil
+	case c >= '0' && c <= '9' || c == '.' && l.pos+1 < len(l.src) && l.src[l.pos+1] >= '0' && l.src[l.pos+1] <= '9':
+		return l.number(), nil
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for l.pos < len(l.src) {
+			c := l.src[l.pos]
+			if c != '_' && !unicode.IsLetter(rune(c)) && !unicode.IsDigit(rune(c)) {
+				break
+			}
+			l.pos++
+		}
+		return token{typ: tokIdent, val: l.src[start:l.pos], pos: start}, nil
+	}
+	for _, op := range operators {
+		if strings.HasPrefix(l.src[l.pos:], op) {
+			l.pos += len(op)
+			return token{typ: tokOp, val: op, pos: start}, nil
+		}
+	}
+	return token{}, errors.Errorf("unexpected character %q at position %d", c, start)
+}
+
+// quoted reads a string delimited by q. Within it, a backslash escapes the
+// following character.
+func (l *lexer) quoted(q byte) (string, error) {
+	start := l.pos
+	l.pos++
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch c {
+		case q:
+			l.pos++
+			return sb.String(), nil
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return "", errors.Errorf("unterminated string at position %d", start)
+			}
+			l.pos++
+			switch e := l.src[l.pos]; e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(e)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+		l.pos++
+	}
+	return "", errors.Errorf("unterminated string at position %d", start)
+}
+
+func (l *lexer) number() token {
+	start := l.pos
+	typ := tokInt
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == '.' || c == 'e' || c == 'E' {
+			typ = tokFloat
+		} else if (c == '-' || c == '+') && (l.src[l.pos-1] == 'e' || l.src[l.pos-1] == 'E') {
+			// exponent sign
+		} else if c < '0' || c > '9' {
+			break
+		}
+		l.pos++
+	}
+	return token{typ: typ, val: l.src[start:l.pos], pos: start}
+}
+
+type parser struct {
+	lex     lexer
+	tok     token
+	resolve func(name string) (int, bool)
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) parse() (node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	n, err := p.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.typ != tokEOF {
+		return nil, errors.Errorf("unexpected %q at position %d", p.tok.val, p.tok.pos)
+	}
+	return n, nil
+}
+
+// precedence lists the binary operators by increasing precedence.
+var precedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) binary(level int) (node, error) {
+	if level == len(precedence) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.typ == tokOp && contains(precedence[level], p.tok.val) {
+		op := p.tok.val
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case "&&":
+			left = &andNode{left, right}
+		case "||":
+			left = &orNode{left, right}
+		default:
+			left = &binaryNode{op: op, left: left, right: right}
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (node, error) {
+	if p.tok.typ == tokOp && (p.tok.val == "-" || p.tok.val == "!") {
+		op := p.tok.val
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	tok := p.tok
+	switch tok.typ {
+	case tokInt:
+		v, err := strconv.ParseInt(tok.val, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing integer at position %d", tok.pos)
+		}
+		return &literalNode{v}, p.advance()
+	case tokFloat:
+		v, err := strconv.ParseFloat(tok.val, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing float at position %d", tok.pos)
+		}
+		return &literalNode{v}, p.advance()
+	case tokString:
+		return &literalNode{tok.val}, p.advance()
+	case tokLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n, err := p.binary(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.typ != tokRParen {
+			return nil, errors.Errorf("expected ')' at position %d", p.tok.pos)
+		}
+		return n, p.advance()
+	case tokQuotedIdent:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return p.field(tok)
+	case tokIdent:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.typ == tokLParen {
+			return p.call(tok)
+		}
+		switch tok.val {
+		case "true":
+			return &literalNode{true}, nil
+		case "false":
+			return &literalNode{false}, nil
+		case "null":
+			return &literalNode{nil}, nil
+		}
+		return p.field(tok)
+	case tokEOF:
+		return nil, errors.New("unexpected end of expression")
+	}
+	return nil, errors.Errorf("unexpected %q at position %d", tok.val, tok.pos)
+}
+
+func (p *parser) field(tok token) (node, error) {
+	idx, ok := p.resolve(tok.val)
+	if !ok {
+		return nil, errors.Errorf("unknown field '%s' at position %d", tok.val, tok.pos)
+	}
+	return &fieldNode{name: tok.val, index: idx}, nil
+}
+
+func (p *parser) call(name token) (node, error) {
+	fn, ok := functions[name.val]
+	if !ok {
+		return nil, errors.Errorf("unknown function '%s' at position %d", name.val, name.pos)
+	}
+	// Skip '('.
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var args []node
+	for p.tok.typ != tokRParen {
+		if len(args) > 0 {
+			if p.tok.typ != tokComma {
+				return nil, errors.Errorf("expected ',' or ')' at position %d", p.tok.pos)
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.binary(0)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if len(args) < fn.minArgs || fn.maxArgs >= 0 && len(args) > fn.maxArgs {
+		return nil, errors.Errorf("wrong number of arguments to %s: %d", name.val, len(args))
+	}
+	if name.val == "if" {
+		return &ifNode{cond: args[0], then: args[1], els: args[2]}, nil
+	}
+	return &callNode{name: name.val, fn: fn.fn, args: args}, nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
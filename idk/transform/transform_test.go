@@ -0,0 +1,148 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEval(t *testing.T) {
+	names := []string{"first", "last", "score", "ts", "tags", "empty", "status", "odd name"}
+	vals := []interface{}{
+		"Ada", "Lovelace", int32(91), int64(1646370367123), []string{"a", "b"}, nil, "A", uint8(7),
+	}
+	resolve := func(name string) (int, bool) {
+		for i, n := range names {
+			if n == name {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	tests := []struct {
+		expr string
+		exp  interface{}
+	}{
+		{`concat(lower(first), " ", upper(last))`, "ada LOVELACE"},
+		{`first + '-' + score`, "Ada-91"},
+		{`score * 2 + 1`, int64(183)},
+		{`score / 2`, int64(45)},
+		{`score / 2.0`, 45.5},
+		{`score % 10`, int64(1)},
+		{`-score`, int64(-91)},
+		{`if(score >= 90, "high", "low")`, "high"},
+		{`if(score < 90 || empty == null, 1, 0)`, int64(1)},
+		{`if(empty, 1, 0)`, int64(0)},
+		{`if(false, 1 / 0, 2)`, int64(2)},
+		{`!(score > 100) && status == "A"`, true},
+		{`empty + 1`, nil},
+		{`empty == null`, true},
+		{`coalesce(empty, last)`, "Lovelace"},
+		{`is_null(empty)`, true},
+		{`map(status, "A", "active", "I", "inactive", "unknown")`, "active"},
+		{`map(status, "X", "x")`, nil},
+		{`map(score, 91, "yes", "no")`, "yes"},
+		{`substr(last, 0, 4)`, "Love"},
+		{`substr(last, 4)`, "lace"},
+		{`replace(last, "lace", "")`, "Love"},
+		{`split("a,b,c", ",")`, []interface{}{"a", "b", "c"}},
+		{`join(tags, "|")`, "a|b"},
+		{`len(tags)`, int64(2)},
+		{`len(first)`, int64(3)},
+		{`contains(last, "ace")`, true},
+		{`match(last, "^L.*e$")`, true},
+		{`extract("order-1234", "order-([0-9]+)")`, "1234"},
+		{`extract("none", "([0-9]+)")`, nil},
+		{`int("42")`, int64(42)},
+		{`int(3.9)`, int64(3)},
+		{`float("1.5")`, 1.5},
+		{`round(2.345, 2)`, 2.35},
+		{`abs(-3)`, int64(3)},
+		{`min(3, score, 5)`, int64(3)},
+		{`max(3, score, 5)`, int64(91)},
+		{`str(score)`, "91"},
+		{"`odd name` + 1", int64(8)},
+		{`from_epoch(ts, "ms")`, time.UnixMilli(1646370367123).UTC()},
+		{`epoch(from_epoch(ts, "ms"))`, int64(1646370367)},
+		{`trunc_time(from_epoch(ts, "ms"), "day")`, time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{`format_time(from_epoch(ts, "ms"), "2006-01-02")`, "2022-03-04"},
+		{`parse_time("2022-03-04", "DateOnly")`, time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{`year(from_epoch(ts, "ms")) * 100 + month(from_epoch(ts, "ms"))`, int64(202203)},
+		{`hash(first) == hash("Ada")`, true},
+		{`hash(first) >= 0`, true},
+		{`sha256("abc")`, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{`1.5e2`, 150.0},
+	}
+	for _, test := range tests {
+		expr, err := Compile(test.expr, resolve)
+		if err != nil {
+			t.Errorf("compiling %s: %v", test.expr, err)
+			continue
+		}
+		got, err := expr.Eval(vals)
+		if err != nil {
+			t.Errorf("evaluating %s: %v", test.expr, err)
+			continue
+		}
+		if exp, ok := test.exp.(time.Time); ok {
+			if tm, ok := got.(time.Time); !ok || !tm.Equal(exp) {
+				t.Errorf("%s: expected %v, got %v", test.expr, exp, got)
+			}
+		} else if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("%s: expected %#v, got %#v", test.expr, test.exp, got)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	resolve := func(name string) (int, bool) { return 0, name == "a" }
+	for expr, msg := range map[string]string{
+		`b + 1`:          "unknown field 'b'",
+		`nope(a)`:        "unknown function 'nope'",
+		`lower(a, a)`:    "wrong number of arguments",
+		`(a + 1`:         "expected ')'",
+		`a +`:            "unexpected end",
+		`"abc`:           "unterminated string",
+		`a $ 1`:          "unexpected character",
+		`concat(a a)`:    "expected ',' or ')'",
+		`if(a, 1)`:       "wrong number of arguments",
+		`a 1`:            "unexpected",
+		`trim(a) trim()`: "unexpected",
+	} {
+		_, err := Compile(expr, resolve)
+		if err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%s: expected error containing %q, got %v", expr, msg, err)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	resolve := func(name string) (int, bool) { return 0, true }
+	for _, expr := range []string{
+		`a / 0`,
+		`a < "x"`,
+		`if(a, 1, 2)`,
+		`int("x")`,
+		`from_epoch(a, "weeks")`,
+	} {
+		e, err := Compile(expr, resolve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := e.Eval([]interface{}{int64(1)}); err == nil {
+			t.Errorf("%s: expected error", expr)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	vars, err := Parse("concat(a, b, `c d`, a)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"a", "b", "c d"}; !reflect.DeepEqual(vars, exp) {
+		t.Fatalf("expected %v, got %v", exp, vars)
+	}
+}
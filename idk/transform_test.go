@@ -0,0 +1,108 @@
+package idk
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/featurebasedb/featurebase/v3/logger"
+)
+
+func TestTransformSource(t *testing.T) {
+	transforms := make([]Transform, 0, 3)
+	for _, spec := range []string{
+		"full__String=concat(first, ' ', last)",
+		"score__Int=int(score) * 10",
+		"grade__String_T=if(score >= 50, 'pass', 'fail')",
+	} {
+		tr, err := ParseTransform(spec, logger.NopLogger)
+		if err != nil {
+			t.Fatal(err)
+		}
+		transforms = append(transforms, tr)
+	}
+
+	src := newTestSource(
+		[]Field{StringField{NameVal: "first"}, StringField{NameVal: "last"}, StringField{NameVal: "score"}},
+		[][]interface{}{
+			{"Ada", "Lovelace", "9"},
+			{ErrFlush},
+			{"Alan", "Turing", "4"},
+		},
+	)
+	s := TransformSource(src, transforms)
+
+	rec, err := s.Record()
+	if err != ErrSchemaChange {
+		t.Fatalf("expected schema change, got %v", err)
+	}
+	expSchema := []Field{
+		StringField{NameVal: "first"},
+		StringField{NameVal: "last"},
+		IntField{NameVal: "score", DestNameVal: "score"},
+		StringField{NameVal: "full", DestNameVal: "full"},
+		StringField{NameVal: "grade", DestNameVal: "grade", Mutex: true},
+	}
+	if !reflect.DeepEqual(s.Schema(), expSchema) {
+		t.Fatalf("unexpected schema:\n%#v", s.Schema())
+	}
+	// The replaced score is used by the later transform.
+	if exp := []interface{}{"Ada", "Lovelace", int64(90), "Ada Lovelace", "pass"}; !reflect.DeepEqual(rec.Data(), exp) {
+		t.Fatalf("unexpected data: %#v", rec.Data())
+	}
+
+	if _, err := s.Record(); err != ErrFlush {
+		t.Fatalf("expected flush, got %v", err)
+	}
+	rec, err = s.Record()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []interface{}{"Alan", "Turing", int64(40), "Alan Turing", "fail"}; !reflect.DeepEqual(rec.Data(), exp) {
+		t.Fatalf("unexpected data: %#v", rec.Data())
+	}
+	if _, err := s.Record(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+}
+
+func TestTransformSourceOffsetRecord(t *testing.T) {
+	tr, err := ParseTransform("b__Int=a + 1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := &offsetTestSource{
+		schema:  []Field{IntField{NameVal: "a"}},
+		records: []*offsetRecord{{groupKey: "t:0", offset: 3, data: []interface{}{int64(1)}}},
+	}
+	rec, err := TransformSource(src, []Transform{tr}).Record()
+	if err != ErrSchemaChange {
+		t.Fatalf("expected schema change, got %v", err)
+	}
+	orec, ok := rec.(OffsetStreamRecord)
+	if !ok {
+		t.Fatalf("expected an OffsetStreamRecord, got %T", rec)
+	}
+	if key, offset := orec.StreamOffset(); key != "t:0" || offset != 3 {
+		t.Fatalf("unexpected offset: %s, %d", key, offset)
+	}
+	if exp := []interface{}{int64(1), int64(2)}; !reflect.DeepEqual(rec.Data(), exp) {
+		t.Fatalf("unexpected data: %#v", rec.Data())
+	}
+}
+
+func TestParseTransforms(t *testing.T) {
+	transforms, err := ParseTransforms([]byte(`[{"field": "day__Timestamp", "expr": "trunc_time(ts, 'day')"}]`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transforms) != 1 || transforms[0].Field.Name() != "day" || transforms[0].Expr != "trunc_time(ts, 'day')" {
+		t.Fatalf("unexpected transforms: %+v", transforms)
+	}
+
+	for _, spec := range []string{"no_equals", "nospec=a", "a__String=lower("} {
+		if _, err := ParseTransform(spec, nil); err == nil {
+			t.Errorf("%s: expected error", spec)
+		}
+	}
+}
@@ -76,6 +76,9 @@ func (i *onPremImporter) ImportRoaringShard(ctx context.Context, tid dax.TableID
 			eg.Go(func() error {
 				return i.api.ImportRoaringShard(ctx, string(tid), shard, request)
 			})
+		} else if sqlTransactionFromContext(ctx) != nil {
+			// a sql transaction can't hold back writes on another node
+			return ErrSQLTransactionsUnsupported
 		} else {
 			eg.Go(func() error { // forward on
 				return i.client.ImportRoaringShard(ctx, &node.URI, string(tid), shard, true, request)
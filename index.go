@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/featurebasedb/featurebase/v3/dax"
@@ -30,11 +31,20 @@ type Index struct {
 	owner       string
 	description string
 
+	// Records whose value in the retentionField timestamp field is older
+	// than retentionPeriod are deleted by the server's retention job.
+	retentionField  string
+	retentionPeriod time.Duration
+
 	path          string
 	name          string
 	qualifiedName string
 	keys          bool // use string keys
 
+	// partitionKey is the name the index was created with, if it has been
+	// renamed since. See PartitionKey().
+	partitionKey string
+
 	// Existence tracking.
 	trackExistence bool
 	existenceFld   *Field
@@ -62,6 +72,30 @@ type Index struct {
 
 	// indicate that we're closing and should wrap up and not allow new actions
 	closing chan struct{}
+
+	// the log of changes to the index's records, if change capture is enabled
+	changes *changeLog
+
+	// a version for each shard which changes whenever a write to the shard
+	// commits. See ShardVersion().
+	shardVersions shardVersions
+}
+
+// shardVersionSeq is the source of shard versions. It starts from the time the
+// process started, so versions aren't handed out again after a restart.
+var shardVersionSeq = uint64(time.Now().UnixNano())
+
+func nextShardVersion() uint64 {
+	return atomic.AddUint64(&shardVersionSeq, 1)
+}
+
+// shardVersions holds the versions of the shards of an index which have been
+// written to since the index was opened. Every other shard has the base
+// version the index was given when it was opened.
+type shardVersions struct {
+	mu       sync.Mutex
+	base     uint64
+	versions map[uint64]uint64
 }
 
 // NewIndex returns an existing (but possibly empty) instance of
@@ -131,6 +165,11 @@ func (i *Index) FieldsPath() string {
 	return filepath.Join(i.path, FieldsDir)
 }
 
+// ChangesPath returns the path of the change log directory for the index.
+func (i *Index) ChangesPath() string {
+	return filepath.Join(i.path, ChangesDir)
+}
+
 // TranslateStorePath returns the translation database path for a partition.
 func (i *Index) TranslateStorePath(partitionID int) string {
 	return filepath.Join(i.path, translateStoreDir, strconv.Itoa(partitionID))
@@ -146,6 +185,17 @@ func (i *Index) TranslateStore(partitionID int) TranslateStore {
 // Keys returns true if the index uses string keys.
 func (i *Index) Keys() bool { return i.keys }
 
+// PartitionKey returns the name the index's shards and keys are hashed by
+// when placing them in partitions. It is the name the index was created
+// with, so renaming an index doesn't move its data between nodes or its keys
+// between translate stores.
+func (i *Index) PartitionKey() string {
+	if i.partitionKey != "" {
+		return i.partitionKey
+	}
+	return i.name
+}
+
 // Options returns all options for this index.
 func (i *Index) Options() IndexOptions {
 	i.mu.RLock()
@@ -155,9 +205,12 @@ func (i *Index) Options() IndexOptions {
 
 func (i *Index) options() IndexOptions {
 	return IndexOptions{
-		Description:    i.description,
-		Keys:           i.keys,
-		TrackExistence: i.trackExistence,
+		Description:     i.description,
+		Keys:            i.keys,
+		TrackExistence:  i.trackExistence,
+		RetentionField:  i.retentionField,
+		RetentionPeriod: i.retentionPeriod,
+		PartitionKey:    i.partitionKey,
 	}
 }
 
@@ -209,6 +262,13 @@ func (i *Index) open(idx *disco.Index) (err error) {
 	i.closing = make(chan struct{})
 	// fmt.Printf("new channel %p for index %p\n", i.closing, i)
 
+	// writes from before the index was opened aren't known, so every shard
+	// gets a new version
+	i.shardVersions.mu.Lock()
+	i.shardVersions.base = nextShardVersion()
+	i.shardVersions.versions = nil
+	i.shardVersions.mu.Unlock()
+
 	// we don't want to open *all* the views for each shard, since
 	// most are empty when we are doing time quantums. It slows
 	// down startup dramatically. So we ask for the meta data
@@ -277,7 +337,7 @@ func (i *Index) open(idx *disco.Index) (err error) {
 			partitionID := partitionID
 
 			g.Go(func() error {
-				store, err := i.OpenTranslateStore(i.TranslateStorePath(partitionID), i.name, "", partitionID, i.holder.partitionN, i.holder.cfg.StorageConfig.FsyncEnabled)
+				store, err := i.OpenTranslateStore(i.TranslateStorePath(partitionID), i.PartitionKey(), "", partitionID, i.holder.partitionN, i.holder.cfg.StorageConfig.FsyncEnabled)
 				if err != nil {
 					return errors.Wrapf(err, "opening index translate store: partition=%d", partitionID)
 				}
@@ -293,6 +353,13 @@ func (i *Index) open(idx *disco.Index) (err error) {
 		}
 	}
 
+	if cfg := i.holder.cfg.ChangeCapture; cfg.Enabled {
+		i.changes, err = openChangeLog(i.ChangesPath(), cfg, i.holder.cfg.StorageConfig.FsyncEnabled)
+		if err != nil {
+			return errors.Wrap(err, "opening change log")
+		}
+	}
+
 	_ = testhook.Opened(i.holder.Auditor, i, nil)
 	return nil
 }
@@ -463,6 +530,13 @@ func (i *Index) Close() error {
 	}
 	i.translateStores = make(map[int]TranslateStore)
 
+	if i.changes != nil {
+		if err := i.changes.close(); err != nil {
+			return errors.Wrap(err, "closing change log")
+		}
+		i.changes = nil
+	}
+
 	// Close all fields.
 	for _, f := range i.fields {
 		if err := f.Close(); err != nil {
@@ -513,6 +587,31 @@ func (i *Index) AvailableShards(localOnly bool) *roaring.Bitmap {
 	return b
 }
 
+// ShardVersion returns the version of a shard on this node. The version
+// changes whenever a write to the shard commits on this node, and whenever
+// the index is opened, so two reads of the shard which see the same version
+// see the same data.
+func (i *Index) ShardVersion(shard uint64) uint64 {
+	i.shardVersions.mu.Lock()
+	defer i.shardVersions.mu.Unlock()
+	if v, ok := i.shardVersions.versions[shard]; ok {
+		return v
+	}
+	return i.shardVersions.base
+}
+
+// touchShard gives a shard a new version. It is called when a write to the
+// shard commits.
+func (i *Index) touchShard(shard uint64) {
+	v := nextShardVersion()
+	i.shardVersions.mu.Lock()
+	defer i.shardVersions.mu.Unlock()
+	if i.shardVersions.versions == nil {
+		i.shardVersions.versions = make(map[uint64]uint64)
+	}
+	i.shardVersions.versions[shard] = v
+}
+
 // Begin starts a transaction on a shard of the index.
 func (i *Index) BeginTx(writable bool, shard uint64) (Tx, error) {
 	return i.holder.txf.NewTx(Txo{Write: writable, Index: i, Shard: shard}), nil
@@ -901,6 +1000,71 @@ func (i *Index) UpdateFieldLocal(cfm *CreateFieldMessage, update FieldUpdate) er
 	return nil
 }
 
+// Update applies an IndexUpdate to the index's persisted options, returning
+// the updated CreateIndexMessage. The local index is not modified; that's
+// done by UpdateLocal.
+func (i *Index) Update(ctx context.Context, update IndexUpdate) (*CreateIndexMessage, error) {
+	buf, err := i.holder.Schemator.Index(ctx, i.name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting index '%s' from etcd", i.name)
+	}
+	cim, err := decodeCreateIndexMessage(i.holder.serializer, buf)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding CreateIndexMessage")
+	} else if cim == nil {
+		return nil, errors.New("got nil CreateIndexMessage when decoding")
+	}
+
+	// Handle the options we know how to update, or error.
+	switch update.Option {
+	case "retentionField":
+		if update.Value != "" {
+			f := i.Field(update.Value)
+			if f == nil {
+				return nil, NewBadRequestError(newNotFoundError(ErrFieldNotFound, update.Value))
+			}
+			if typ := f.Options().Type; typ != FieldTypeTimestamp {
+				return nil, NewBadRequestError(errors.Errorf("retention field must be a 'timestamp' type field, not '%s'", typ))
+			}
+		}
+		cim.Meta.RetentionField = update.Value
+	case "retentionPeriod":
+		var dur time.Duration
+		if update.Value != "" {
+			dur, err = time.ParseDuration(update.Value)
+			if err != nil {
+				return nil, NewBadRequestError(errors.Wrap(err, "parsing duration"))
+			}
+		}
+		if dur < 0 {
+			return nil, NewBadRequestError(errors.Errorf("retention period can't be negative: '%s'", update.Value))
+		}
+		cim.Meta.RetentionPeriod = dur
+	default:
+		return nil, NewBadRequestError(errors.Errorf("updates for option '%s' are not supported", update.Option))
+	}
+
+	// Persist the updated index to etcd.
+	if b, err := i.serializer.Marshal(cim); err != nil {
+		return nil, errors.Wrap(err, "marshaling index")
+	} else if err := i.holder.Schemator.UpdateIndex(ctx, cim.Index, b); errors.Cause(err) == disco.ErrIndexDoesNotExist {
+		return nil, newNotFoundError(ErrIndexNotFound, cim.Index)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "writing index to disco: %s", cim.Index)
+	}
+
+	return cim, nil
+}
+
+// UpdateLocal applies the options in cim, as returned by Update, to the
+// local index.
+func (i *Index) UpdateLocal(cim *CreateIndexMessage) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.retentionField = cim.Meta.RetentionField
+	i.retentionPeriod = cim.Meta.RetentionPeriod
+}
+
 // createFieldIfNotExists creates the field if it does not already exist in the
 // in-memory index structure. This is not related to whether or not the field
 // exists in etcd.
@@ -1016,6 +1180,125 @@ func (i *Index) DeleteField(name string) error {
 	return i.translationSyncer.Reset()
 }
 
+// RenameField renames a field in the index, moving its views, bitmaps and
+// translation data to the new name.
+func (i *Index) RenameField(name, newName string) error {
+	return i.renameField(name, newName, true)
+}
+
+// RenameFieldLocal renames a field in the local index structures without
+// updating the schemator. It is used when applying a rename which was
+// already persisted by another node.
+func (i *Index) RenameFieldLocal(name, newName string) error {
+	return i.renameField(name, newName, false)
+}
+
+func (i *Index) renameField(name, newName string, persist bool) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// Disallow renaming the existence field.
+	if name == existenceFieldName {
+		return newNotFoundError(ErrFieldNotFound, existenceFieldName)
+	}
+	if err := ValidateName(newName); err != nil {
+		return errors.Wrap(err, "validating name")
+	}
+
+	// Confirm field exists and the new name is free.
+	f := i.field(name)
+	if f == nil {
+		return newNotFoundError(ErrFieldNotFound, name)
+	} else if i.field(newName) != nil {
+		return ErrFieldExists
+	}
+
+	cfm := &CreateFieldMessage{
+		Index:     i.name,
+		Field:     newName,
+		CreatedAt: f.CreatedAt(),
+		Owner:     f.owner,
+		Meta:      &FieldOptions{},
+	}
+	*cfm.Meta = f.Options()
+
+	// Rename the field in etcd as the system of record.
+	if persist {
+		b, err := i.serializer.Marshal(cfm)
+		if err != nil {
+			return errors.Wrap(err, "marshaling field")
+		}
+		if err := i.holder.Schemator.RenameField(context.TODO(), i.name, name, newName, b); errors.Cause(err) == disco.ErrFieldExists {
+			return ErrFieldExists
+		} else if err != nil {
+			return errors.Wrapf(err, "renaming field in etcd: %s/%s", i.name, name)
+		}
+		for _, v := range f.views() {
+			newView := renamedView(name, newName, v.name)
+			if newView == v.name {
+				continue
+			}
+			if err := i.holder.Schemator.DeleteView(context.TODO(), i.name, newName, v.name); err != nil {
+				return errors.Wrapf(err, "deleting view from etcd: %s/%s/%s", i.name, newName, v.name)
+			}
+			if err := i.holder.Schemator.CreateView(context.TODO(), i.name, newName, newView); err != nil {
+				return errors.Wrapf(err, "creating view in etcd: %s/%s/%s", i.name, newName, newView)
+			}
+		}
+	}
+
+	// Hold on to the data which doesn't live in the field directory so it
+	// can be restored under the new name.
+	snapshot, err := snapshotTranslateStore(f.TranslateStore(), f.TranslateStorePath())
+	if err != nil {
+		return errors.Wrap(err, "snapshotting translate store")
+	}
+	shards := f.protectedRemoteAvailableShards()
+	views := fieldViewShards(f)
+
+	// Close field.
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "closing")
+	}
+
+	if err := i.holder.txf.RenameFieldInStore(i.name, name, newName); err != nil {
+		return errors.Wrap(err, "Txf.RenameFieldInStore")
+	}
+
+	// Move the field directory, including the BSI group view.
+	if _, err := os.Stat(f.path); err == nil {
+		newPath := i.fieldPath(newName)
+		if err := os.Rename(f.path, newPath); err != nil {
+			return errors.Wrap(err, "renaming field directory")
+		}
+		oldView := filepath.Join(newPath, "views", viewBSIGroupPrefix+name)
+		if _, err := os.Stat(oldView); err == nil {
+			if err := os.Rename(oldView, filepath.Join(newPath, "views", viewBSIGroupPrefix+newName)); err != nil {
+				return errors.Wrap(err, "renaming view directory")
+			}
+		}
+	}
+
+	// Remove reference and move shard metadata to the new name.
+	delete(i.fields, name)
+	i.fieldView2shard.renameField(name, newName, views)
+
+	var mu sync.Mutex
+	nf, err := i.openField(&mu, cfm, newName)
+	if err != nil {
+		return errors.Wrap(err, "opening renamed field")
+	}
+	nf.owner = cfm.Owner
+
+	if err := restoreTranslateStore(nf.TranslateStore(), snapshot); err != nil {
+		return errors.Wrap(err, "restoring translate store")
+	}
+	if err := nf.AddRemoteAvailableShards(shards); err != nil {
+		return errors.Wrap(err, "adding remote available shards")
+	}
+	return i.translationSyncer.Reset()
+}
+
 // SetTranslatePartitions sets the cached value: translatePartitions.
 //
 // There's already logic in api_directive.go which creates a new index with
@@ -1080,6 +1363,16 @@ type IndexOptions struct {
 	TrackExistence bool   `json:"trackExistence"`
 	PartitionN     int    `json:"partitionN"`
 	Description    string `json:"description"`
+
+	// RetentionField names a timestamp field. When it and RetentionPeriod
+	// are set, records whose value in the field is older than
+	// RetentionPeriod are deleted.
+	RetentionField  string        `json:"retentionField,omitempty"`
+	RetentionPeriod time.Duration `json:"retentionPeriod,omitempty"`
+
+	// PartitionKey is set when an index is renamed to the name it was
+	// created with, which its shards and keys are still partitioned by.
+	PartitionKey string `json:"partitionKey,omitempty"`
 }
 
 type importData struct {
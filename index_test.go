@@ -333,3 +333,82 @@ func TestIndex_RecreateFieldOnRestart(t *testing.T) {
 	}
 
 }
+
+// Ensure a field keeps its data, including keys and BSI values, after it is
+// renamed, and that the rename survives a restart.
+func TestIndex_RenameField(t *testing.T) {
+	c := test.MustRunUnsharedCluster(t, 1)
+	defer c.Close()
+
+	ctx := context.Background()
+	api := c.GetNode(0).API
+	indexName := c.Idx()
+
+	if _, err := api.CreateIndex(ctx, indexName, pilosa.IndexOptions{TrackExistence: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.CreateField(ctx, indexName, "s", pilosa.OptFieldKeys()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.CreateField(ctx, indexName, "r"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.CreateField(ctx, indexName, "n", pilosa.OptFieldTypeInt(0, 1000)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.Query(ctx, &pilosa.QueryRequest{
+		Index: indexName,
+		Query: fmt.Sprintf(`Set(1, s="x") Set(2, s="x") Set(%d, s="y")
+			Set(1, r=5) Set(%d, r=5)
+			Set(1, n=10) Set(%d, n=32)`, ShardWidth+1, ShardWidth+1, ShardWidth+1),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, names := range [][2]string{{"s", "t"}, {"r", "q"}, {"n", "m"}} {
+		if err := api.RenameField(ctx, indexName, names[0], names[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := api.RenameField(ctx, indexName, "t", "m"); errors.Cause(err) != pilosa.ErrFieldExists {
+		t.Fatalf("expected field exists error, got: %v", err)
+	} else if err := api.RenameField(ctx, indexName, "s", "u"); !isNotFoundError(err) {
+		t.Fatalf("expected 'field not found' error, got: %v", err)
+	}
+
+	check := func(query string, exp uint64) {
+		t.Helper()
+		resp, err := api.Query(ctx, &pilosa.QueryRequest{Index: indexName, Query: query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch res := resp.Results[0].(type) {
+		case uint64:
+			if res != exp {
+				t.Fatalf("%s: expected %d, got %d", query, exp, res)
+			}
+		case pilosa.ValCount:
+			if res.Val != int64(exp) {
+				t.Fatalf("%s: expected %d, got %d", query, exp, res.Val)
+			}
+		}
+	}
+	check(`Count(Row(t="x"))`, 2)
+	check(`Count(Row(q=5))`, 2)
+	check(`Sum(field=m)`, 42)
+	if api.Holder().Field(indexName, "s") != nil || api.Holder().Field(indexName, "n") != nil {
+		t.Fatal("expected old fields to be gone")
+	}
+
+	// Ensure the rename survives a restart. Keys aren't checked because the
+	// test cluster's translate stores don't persist.
+	if err := c.GetNode(0).Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AwaitState(disco.ClusterStateNormal, 10*time.Second); err != nil {
+		t.Fatalf("restarting cluster: %v", err)
+	}
+	api = c.GetNode(0).API
+	check(`Count(Row(q=5))`, 2)
+	check(`Sum(field=m)`, 42)
+}
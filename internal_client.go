@@ -627,6 +627,12 @@ func (c *InternalClient) QueryNode(ctx context.Context, addr dax.Address, index
 	req.Header.Set("Accept", "application/x-protobuf")
 	req.Header.Set("X-Pilosa-Row", "roaring")
 	req.Header.Set("User-Agent", "pilosa/"+Version)
+	if pool := resourcePoolFromContext(ctx); pool != nil {
+		req.Header.Set(HeaderResourcePool, pool.Name)
+	}
+	if c.secretKey != "" {
+		req.Header.Set("X-Feature-Key", c.secretKey)
+	}
 
 	// Execute request against the host.
 	resp, err := c.executeRequest(req.WithContext(ctx))
@@ -1075,7 +1081,7 @@ func (c *InternalClient) CreateFieldWithOptions(ctx context.Context, index, fiel
 		fieldOpt.TimeQuantum = &opt.TimeQuantum
 		ttlString := opt.TTL.String()
 		fieldOpt.TTL = &ttlString
-	case FieldTypeBool:
+	case FieldTypeBool, FieldTypeGeo:
 		// pass
 	case FieldTypeDecimal:
 		fieldOpt.Min = &opt.Min
@@ -1502,19 +1508,19 @@ func (c *InternalClient) CreateFieldKeysNode(ctx context.Context, uri *pnet.URI,
 	return transMap, nil
 }
 
-func (c *InternalClient) MatchFieldKeysNode(ctx context.Context, uri *pnet.URI, index string, field string, like string) (matches []uint64, err error) {
+func (c *InternalClient) MatchFieldKeysNode(ctx context.Context, uri *pnet.URI, index string, field string, syntax, pattern string) (matches []uint64, err error) {
 	span, ctx := tracing.StartSpanFromContext(ctx, "InternalClient.MatchFieldKeysNode")
 	defer span.Finish()
 
 	// Create HTTP request.
-	u := uriPathToURL(uri, fmt.Sprintf("%s/internal/translate/field/%s/%s/keys/like", c.prefix(), index, field))
-	req, err := http.NewRequest("POST", u.String(), strings.NewReader(like))
+	u := uriPathToURL(uri, fmt.Sprintf("%s/internal/translate/field/%s/%s/keys/%s", c.prefix(), index, field, syntax))
+	req, err := http.NewRequest("POST", u.String(), strings.NewReader(pattern))
 	if err != nil {
 		return nil, errors.Wrap(err, "creating request")
 	}
 
 	// Apply headers.
-	req.Header.Set("Content-Length", strconv.Itoa(len(like)))
+	req.Header.Set("Content-Length", strconv.Itoa(len(pattern)))
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "pilosa/"+Version)
 	AddAuthToken(ctx, &req.Header)
@@ -4,8 +4,12 @@ package pilosa
 
 import (
 	"bytes"
+	"regexp"
 	"strings"
 	"unicode/utf8"
+
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/pkg/errors"
 )
 
 // tokenizeLike turns a "like" pattern into a list of tokens.
@@ -221,3 +225,47 @@ func matchLike(key []byte, like ...filterStep) bool {
 	// If there is any unmatched data left, this is not a match.
 	return len(key) == 0
 }
+
+// keyPatternArgs are the arguments of Rows which filter rows by matching
+// their keys, named by the syntax of the pattern.
+var keyPatternArgs = []string{"like", "regexp", "glob"}
+
+// RowsKeyPattern returns the key pattern argument of a Rows call, if any, and
+// the syntax of the pattern: "like", "regexp" or "glob".
+func RowsKeyPattern(c *pql.Call) (syntax, pattern string, ok bool, err error) {
+	for _, arg := range keyPatternArgs {
+		p, found, err := c.StringArg(arg)
+		if err != nil {
+			return "", "", false, errors.Wrapf(err, "getting %s pattern", arg)
+		} else if !found {
+			continue
+		} else if ok {
+			return "", "", false, errors.Errorf("Rows call cannot have both %s and %s arguments", syntax, arg)
+		}
+		syntax, pattern, ok = arg, p, true
+	}
+	return syntax, pattern, ok, nil
+}
+
+// newKeyMatcher returns a function reporting whether a key matches a
+// pattern in the given syntax: "like", "regexp" or "glob".
+func newKeyMatcher(syntax, pattern string) (func(key []byte) bool, error) {
+	switch syntax {
+	case "like":
+		plan := planLike(pattern)
+		return func(key []byte) bool {
+			return matchLike(key, plan...)
+		}, nil
+	case "regexp", "glob":
+		if syntax == "glob" {
+			pattern = pql.GlobToRegexp(pattern)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s pattern", syntax)
+		}
+		return re.Match, nil
+	default:
+		return nil, errors.Errorf("unknown pattern syntax: %q", syntax)
+	}
+}
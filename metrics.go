@@ -54,6 +54,9 @@ const (
 	MetricPqlQueries                      = "pql_queries_total"
 	MetricSqlQueries                      = "sql_queries_total"
 	MetricDeleteDataframe                 = "delete_dataframe"
+	MetricResourcePoolRunning             = "resource_pool_running"
+	MetricResourcePoolQueued              = "resource_pool_queued"
+	MetricResourcePoolRejected            = "resource_pool_rejected_total"
 )
 
 const (
@@ -115,6 +118,39 @@ var CounterSQLQueries = prometheus.NewCounter(
 	},
 )
 
+var GaugeResourcePoolRunning = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "pilosa",
+		Name:      MetricResourcePoolRunning,
+		Help:      "TODO",
+	},
+	[]string{
+		"pool",
+	},
+)
+
+var GaugeResourcePoolQueued = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "pilosa",
+		Name:      MetricResourcePoolQueued,
+		Help:      "TODO",
+	},
+	[]string{
+		"pool",
+	},
+)
+
+var CounterResourcePoolRejected = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "pilosa",
+		Name:      MetricResourcePoolRejected,
+		Help:      "TODO",
+	},
+	[]string{
+		"pool",
+	},
+)
+
 var CounterGarbageCollection = prometheus.NewCounter(
 	prometheus.CounterOpts{
 		Namespace: "pilosa",
@@ -576,6 +612,28 @@ var CounterQueryDistinctTotal = prometheus.NewCounterVec(
 	},
 )
 
+var CounterQueryApproxCountDistinctTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "pilosa",
+		Name:      "query_approxcountdistinct_total",
+		Help:      "TODO",
+	},
+	[]string{
+		"index",
+	},
+)
+
+var CounterQueryFunnelTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "pilosa",
+		Name:      "query_funnel_total",
+		Help:      "TODO",
+	},
+	[]string{
+		"index",
+	},
+)
+
 var CounterQueryStoreTotal = prometheus.NewCounterVec(
 	prometheus.CounterOpts{
 		Namespace: "pilosa",
@@ -741,6 +799,39 @@ var CounterQueryConstRowTotal = prometheus.NewCounterVec(
 	},
 )
 
+var CounterQuerySimilarTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "pilosa",
+		Name:      "query_similar_total",
+		Help:      "TODO",
+	},
+	[]string{
+		"index",
+	},
+)
+
+var CounterQuerySampleTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "pilosa",
+		Name:      "query_sample_total",
+		Help:      "TODO",
+	},
+	[]string{
+		"index",
+	},
+)
+
+var CounterQueryShardVersionsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "pilosa",
+		Name:      "query_shard_versions_total",
+		Help:      "TODO",
+	},
+	[]string{
+		"index",
+	},
+)
+
 var CounterQueryLimitTotal = prometheus.NewCounterVec(
 	prometheus.CounterOpts{
 		Namespace: "pilosa",
@@ -959,6 +1050,9 @@ func init() {
 	prometheus.MustRegister(GaugeWorkerTotal)
 	prometheus.MustRegister(CounterPQLQueries)
 	prometheus.MustRegister(CounterSQLQueries)
+	prometheus.MustRegister(GaugeResourcePoolRunning)
+	prometheus.MustRegister(GaugeResourcePoolQueued)
+	prometheus.MustRegister(CounterResourcePoolRejected)
 	prometheus.MustRegister(CounterGarbageCollection)
 	prometheus.MustRegister(GaugeGoroutines)
 	prometheus.MustRegister(GaugeOpenFiles)
@@ -1012,6 +1106,8 @@ func init() {
 	prometheus.MustRegister(CounterQueryClearTotal)
 	prometheus.MustRegister(CounterQueryClearRowTotal)
 	prometheus.MustRegister(CounterQueryDistinctTotal)
+	prometheus.MustRegister(CounterQueryApproxCountDistinctTotal)
+	prometheus.MustRegister(CounterQueryFunnelTotal)
 	prometheus.MustRegister(CounterQueryStoreTotal)
 	prometheus.MustRegister(CounterQueryCountTotal)
 	prometheus.MustRegister(CounterQuerySetTotal)
@@ -1028,6 +1124,9 @@ func init() {
 	prometheus.MustRegister(CounterQueryUnionRowsTotal)
 	prometheus.MustRegister(CounterQueryConstRowTotal)
 	prometheus.MustRegister(CounterQueryLimitTotal)
+	prometheus.MustRegister(CounterQuerySampleTotal)
+	prometheus.MustRegister(CounterQueryShardVersionsTotal)
+	prometheus.MustRegister(CounterQuerySimilarTotal)
 	prometheus.MustRegister(CounterQueryPercentileTotal)
 	prometheus.MustRegister(CounterQueryDeleteTotal)
 	prometheus.MustRegister(CounterQuerySortTotal)
@@ -26,6 +26,9 @@ type IndexMeta struct {
 	Keys                 bool     `protobuf:"varint,3,opt,name=Keys,proto3" json:"Keys,omitempty"`
 	TrackExistence       bool     `protobuf:"varint,4,opt,name=TrackExistence,proto3" json:"TrackExistence,omitempty"`
 	Description          string   `protobuf:"bytes,5,opt,name=Description,proto3" json:"Description,omitempty"`
+	RetentionField       string   `protobuf:"bytes,6,opt,name=RetentionField,proto3" json:"RetentionField,omitempty"`
+	RetentionPeriod      string   `protobuf:"bytes,7,opt,name=RetentionPeriod,proto3" json:"RetentionPeriod,omitempty"`
+	PartitionKey         string   `protobuf:"bytes,8,opt,name=PartitionKey,proto3" json:"PartitionKey,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -85,6 +88,27 @@ func (m *IndexMeta) GetDescription() string {
 	return ""
 }
 
+func (m *IndexMeta) GetRetentionField() string {
+	if m != nil {
+		return m.RetentionField
+	}
+	return ""
+}
+
+func (m *IndexMeta) GetRetentionPeriod() string {
+	if m != nil {
+		return m.RetentionPeriod
+	}
+	return ""
+}
+
+func (m *IndexMeta) GetPartitionKey() string {
+	if m != nil {
+		return m.PartitionKey
+	}
+	return ""
+}
+
 type FieldOptions struct {
 	Type                 string   `protobuf:"bytes,8,opt,name=Type,proto3" json:"Type,omitempty"`
 	CacheType            string   `protobuf:"bytes,3,opt,name=CacheType,proto3" json:"CacheType,omitempty"`
@@ -645,6 +669,61 @@ func (m *DeleteIndexMessage) GetIndex() string {
 	return ""
 }
 
+type RenameIndexMessage struct {
+	Index                string   `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"`
+	NewIndex             string   `protobuf:"bytes,2,opt,name=NewIndex,proto3" json:"NewIndex,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenameIndexMessage) Reset()         { *m = RenameIndexMessage{} }
+func (m *RenameIndexMessage) String() string { return proto.CompactTextString(m) }
+func (*RenameIndexMessage) ProtoMessage()    {}
+func (*RenameIndexMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d2a91b51c7bdc125, []int{9}
+}
+func (m *RenameIndexMessage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RenameIndexMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RenameIndexMessage.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RenameIndexMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameIndexMessage.Merge(m, src)
+}
+func (m *RenameIndexMessage) XXX_Size() int {
+	return m.Size()
+}
+func (m *RenameIndexMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameIndexMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameIndexMessage proto.InternalMessageInfo
+
+func (m *RenameIndexMessage) GetIndex() string {
+	if m != nil {
+		return m.Index
+	}
+	return ""
+}
+
+func (m *RenameIndexMessage) GetNewIndex() string {
+	if m != nil {
+		return m.NewIndex
+	}
+	return ""
+}
+
 type CreateIndexMessage struct {
 	Index                string     `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"`
 	Meta                 *IndexMeta `protobuf:"bytes,2,opt,name=Meta,proto3" json:"Meta,omitempty"`
@@ -659,7 +738,7 @@ func (m *CreateIndexMessage) Reset()         { *m = CreateIndexMessage{} }
 func (m *CreateIndexMessage) String() string { return proto.CompactTextString(m) }
 func (*CreateIndexMessage) ProtoMessage()    {}
 func (*CreateIndexMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{9}
+	return fileDescriptor_d2a91b51c7bdc125, []int{10}
 }
 func (m *CreateIndexMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -731,7 +810,7 @@ func (m *CreateFieldMessage) Reset()         { *m = CreateFieldMessage{} }
 func (m *CreateFieldMessage) String() string { return proto.CompactTextString(m) }
 func (*CreateFieldMessage) ProtoMessage()    {}
 func (*CreateFieldMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{10}
+	return fileDescriptor_d2a91b51c7bdc125, []int{11}
 }
 func (m *CreateFieldMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -807,7 +886,7 @@ func (m *UpdateFieldMessage) Reset()         { *m = UpdateFieldMessage{} }
 func (m *UpdateFieldMessage) String() string { return proto.CompactTextString(m) }
 func (*UpdateFieldMessage) ProtoMessage()    {}
 func (*UpdateFieldMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{11}
+	return fileDescriptor_d2a91b51c7bdc125, []int{12}
 }
 func (m *UpdateFieldMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -862,7 +941,7 @@ func (m *FieldUpdate) Reset()         { *m = FieldUpdate{} }
 func (m *FieldUpdate) String() string { return proto.CompactTextString(m) }
 func (*FieldUpdate) ProtoMessage()    {}
 func (*FieldUpdate) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{12}
+	return fileDescriptor_d2a91b51c7bdc125, []int{13}
 }
 func (m *FieldUpdate) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -905,6 +984,116 @@ func (m *FieldUpdate) GetValue() string {
 	return ""
 }
 
+type UpdateIndexMessage struct {
+	CreateIndexMessage   *CreateIndexMessage `protobuf:"bytes,1,opt,name=CreateIndexMessage,proto3" json:"CreateIndexMessage,omitempty"`
+	Update               *IndexUpdate        `protobuf:"bytes,2,opt,name=Update,proto3" json:"Update,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *UpdateIndexMessage) Reset()         { *m = UpdateIndexMessage{} }
+func (m *UpdateIndexMessage) String() string { return proto.CompactTextString(m) }
+func (*UpdateIndexMessage) ProtoMessage()    {}
+func (*UpdateIndexMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d2a91b51c7bdc125, []int{14}
+}
+func (m *UpdateIndexMessage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *UpdateIndexMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_UpdateIndexMessage.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *UpdateIndexMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateIndexMessage.Merge(m, src)
+}
+func (m *UpdateIndexMessage) XXX_Size() int {
+	return m.Size()
+}
+func (m *UpdateIndexMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateIndexMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateIndexMessage proto.InternalMessageInfo
+
+func (m *UpdateIndexMessage) GetCreateIndexMessage() *CreateIndexMessage {
+	if m != nil {
+		return m.CreateIndexMessage
+	}
+	return nil
+}
+
+func (m *UpdateIndexMessage) GetUpdate() *IndexUpdate {
+	if m != nil {
+		return m.Update
+	}
+	return nil
+}
+
+type IndexUpdate struct {
+	Option               string   `protobuf:"bytes,1,opt,name=Option,proto3" json:"Option,omitempty"`
+	Value                string   `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IndexUpdate) Reset()         { *m = IndexUpdate{} }
+func (m *IndexUpdate) String() string { return proto.CompactTextString(m) }
+func (*IndexUpdate) ProtoMessage()    {}
+func (*IndexUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d2a91b51c7bdc125, []int{15}
+}
+func (m *IndexUpdate) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *IndexUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_IndexUpdate.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *IndexUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IndexUpdate.Merge(m, src)
+}
+func (m *IndexUpdate) XXX_Size() int {
+	return m.Size()
+}
+func (m *IndexUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_IndexUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IndexUpdate proto.InternalMessageInfo
+
+func (m *IndexUpdate) GetOption() string {
+	if m != nil {
+		return m.Option
+	}
+	return ""
+}
+
+func (m *IndexUpdate) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
 type DeleteFieldMessage struct {
 	Index                string   `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"`
 	Field                string   `protobuf:"bytes,2,opt,name=Field,proto3" json:"Field,omitempty"`
@@ -917,7 +1106,7 @@ func (m *DeleteFieldMessage) Reset()         { *m = DeleteFieldMessage{} }
 func (m *DeleteFieldMessage) String() string { return proto.CompactTextString(m) }
 func (*DeleteFieldMessage) ProtoMessage()    {}
 func (*DeleteFieldMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{13}
+	return fileDescriptor_d2a91b51c7bdc125, []int{16}
 }
 func (m *DeleteFieldMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -960,6 +1149,69 @@ func (m *DeleteFieldMessage) GetField() string {
 	return ""
 }
 
+type RenameFieldMessage struct {
+	Index                string   `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"`
+	Field                string   `protobuf:"bytes,2,opt,name=Field,proto3" json:"Field,omitempty"`
+	NewField             string   `protobuf:"bytes,3,opt,name=NewField,proto3" json:"NewField,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RenameFieldMessage) Reset()         { *m = RenameFieldMessage{} }
+func (m *RenameFieldMessage) String() string { return proto.CompactTextString(m) }
+func (*RenameFieldMessage) ProtoMessage()    {}
+func (*RenameFieldMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d2a91b51c7bdc125, []int{17}
+}
+func (m *RenameFieldMessage) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RenameFieldMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RenameFieldMessage.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RenameFieldMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RenameFieldMessage.Merge(m, src)
+}
+func (m *RenameFieldMessage) XXX_Size() int {
+	return m.Size()
+}
+func (m *RenameFieldMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_RenameFieldMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RenameFieldMessage proto.InternalMessageInfo
+
+func (m *RenameFieldMessage) GetIndex() string {
+	if m != nil {
+		return m.Index
+	}
+	return ""
+}
+
+func (m *RenameFieldMessage) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *RenameFieldMessage) GetNewField() string {
+	if m != nil {
+		return m.NewField
+	}
+	return ""
+}
+
 type DeleteAvailableShardMessage struct {
 	Index                string   `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"`
 	Field                string   `protobuf:"bytes,2,opt,name=Field,proto3" json:"Field,omitempty"`
@@ -973,7 +1225,7 @@ func (m *DeleteAvailableShardMessage) Reset()         { *m = DeleteAvailableShar
 func (m *DeleteAvailableShardMessage) String() string { return proto.CompactTextString(m) }
 func (*DeleteAvailableShardMessage) ProtoMessage()    {}
 func (*DeleteAvailableShardMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{14}
+	return fileDescriptor_d2a91b51c7bdc125, []int{18}
 }
 func (m *DeleteAvailableShardMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1037,7 +1289,7 @@ func (m *Field) Reset()         { *m = Field{} }
 func (m *Field) String() string { return proto.CompactTextString(m) }
 func (*Field) ProtoMessage()    {}
 func (*Field) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{15}
+	return fileDescriptor_d2a91b51c7bdc125, []int{19}
 }
 func (m *Field) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1105,7 +1357,7 @@ func (m *Schema) Reset()         { *m = Schema{} }
 func (m *Schema) String() string { return proto.CompactTextString(m) }
 func (*Schema) ProtoMessage()    {}
 func (*Schema) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{16}
+	return fileDescriptor_d2a91b51c7bdc125, []int{20}
 }
 func (m *Schema) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1155,7 +1407,7 @@ func (m *Index) Reset()         { *m = Index{} }
 func (m *Index) String() string { return proto.CompactTextString(m) }
 func (*Index) ProtoMessage()    {}
 func (*Index) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{17}
+	return fileDescriptor_d2a91b51c7bdc125, []int{21}
 }
 func (m *Index) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1225,7 +1477,7 @@ func (m *URI) Reset()         { *m = URI{} }
 func (m *URI) String() string { return proto.CompactTextString(m) }
 func (*URI) ProtoMessage()    {}
 func (*URI) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{18}
+	return fileDescriptor_d2a91b51c7bdc125, []int{22}
 }
 func (m *URI) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1290,7 +1542,7 @@ func (m *Node) Reset()         { *m = Node{} }
 func (m *Node) String() string { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()    {}
 func (*Node) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{19}
+	return fileDescriptor_d2a91b51c7bdc125, []int{23}
 }
 func (m *Node) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1366,7 +1618,7 @@ func (m *NodeStateMessage) Reset()         { *m = NodeStateMessage{} }
 func (m *NodeStateMessage) String() string { return proto.CompactTextString(m) }
 func (*NodeStateMessage) ProtoMessage()    {}
 func (*NodeStateMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{20}
+	return fileDescriptor_d2a91b51c7bdc125, []int{24}
 }
 func (m *NodeStateMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1421,7 +1673,7 @@ func (m *NodeEventMessage) Reset()         { *m = NodeEventMessage{} }
 func (m *NodeEventMessage) String() string { return proto.CompactTextString(m) }
 func (*NodeEventMessage) ProtoMessage()    {}
 func (*NodeEventMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{21}
+	return fileDescriptor_d2a91b51c7bdc125, []int{25}
 }
 func (m *NodeEventMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1477,7 +1729,7 @@ func (m *NodeStatus) Reset()         { *m = NodeStatus{} }
 func (m *NodeStatus) String() string { return proto.CompactTextString(m) }
 func (*NodeStatus) ProtoMessage()    {}
 func (*NodeStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{22}
+	return fileDescriptor_d2a91b51c7bdc125, []int{26}
 }
 func (m *NodeStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1540,7 +1792,7 @@ func (m *IndexStatus) Reset()         { *m = IndexStatus{} }
 func (m *IndexStatus) String() string { return proto.CompactTextString(m) }
 func (*IndexStatus) ProtoMessage()    {}
 func (*IndexStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{23}
+	return fileDescriptor_d2a91b51c7bdc125, []int{27}
 }
 func (m *IndexStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1603,7 +1855,7 @@ func (m *FieldStatus) Reset()         { *m = FieldStatus{} }
 func (m *FieldStatus) String() string { return proto.CompactTextString(m) }
 func (*FieldStatus) ProtoMessage()    {}
 func (*FieldStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{24}
+	return fileDescriptor_d2a91b51c7bdc125, []int{28}
 }
 func (m *FieldStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1667,7 +1919,7 @@ func (m *ClusterStatus) Reset()         { *m = ClusterStatus{} }
 func (m *ClusterStatus) String() string { return proto.CompactTextString(m) }
 func (*ClusterStatus) ProtoMessage()    {}
 func (*ClusterStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{25}
+	return fileDescriptor_d2a91b51c7bdc125, []int{29}
 }
 func (m *ClusterStatus) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1738,7 +1990,7 @@ func (m *BSIGroup) Reset()         { *m = BSIGroup{} }
 func (m *BSIGroup) String() string { return proto.CompactTextString(m) }
 func (*BSIGroup) ProtoMessage()    {}
 func (*BSIGroup) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{26}
+	return fileDescriptor_d2a91b51c7bdc125, []int{30}
 }
 func (m *BSIGroup) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1808,7 +2060,7 @@ func (m *CreateViewMessage) Reset()         { *m = CreateViewMessage{} }
 func (m *CreateViewMessage) String() string { return proto.CompactTextString(m) }
 func (*CreateViewMessage) ProtoMessage()    {}
 func (*CreateViewMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{27}
+	return fileDescriptor_d2a91b51c7bdc125, []int{31}
 }
 func (m *CreateViewMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1871,7 +2123,7 @@ func (m *DeleteViewMessage) Reset()         { *m = DeleteViewMessage{} }
 func (m *DeleteViewMessage) String() string { return proto.CompactTextString(m) }
 func (*DeleteViewMessage) ProtoMessage()    {}
 func (*DeleteViewMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{28}
+	return fileDescriptor_d2a91b51c7bdc125, []int{32}
 }
 func (m *DeleteViewMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1938,7 +2190,7 @@ func (m *ResizeInstruction) Reset()         { *m = ResizeInstruction{} }
 func (m *ResizeInstruction) String() string { return proto.CompactTextString(m) }
 func (*ResizeInstruction) ProtoMessage()    {}
 func (*ResizeInstruction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{29}
+	return fileDescriptor_d2a91b51c7bdc125, []int{33}
 }
 func (m *ResizeInstruction) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2031,7 +2283,7 @@ func (m *ResizeSource) Reset()         { *m = ResizeSource{} }
 func (m *ResizeSource) String() string { return proto.CompactTextString(m) }
 func (*ResizeSource) ProtoMessage()    {}
 func (*ResizeSource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{30}
+	return fileDescriptor_d2a91b51c7bdc125, []int{34}
 }
 func (m *ResizeSource) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2108,7 +2360,7 @@ func (m *TranslationResizeSource) Reset()         { *m = TranslationResizeSource
 func (m *TranslationResizeSource) String() string { return proto.CompactTextString(m) }
 func (*TranslationResizeSource) ProtoMessage()    {}
 func (*TranslationResizeSource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{31}
+	return fileDescriptor_d2a91b51c7bdc125, []int{35}
 }
 func (m *TranslationResizeSource) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2171,7 +2423,7 @@ func (m *ResizeInstructionComplete) Reset()         { *m = ResizeInstructionComp
 func (m *ResizeInstructionComplete) String() string { return proto.CompactTextString(m) }
 func (*ResizeInstructionComplete) ProtoMessage()    {}
 func (*ResizeInstructionComplete) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{32}
+	return fileDescriptor_d2a91b51c7bdc125, []int{36}
 }
 func (m *ResizeInstructionComplete) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2233,7 +2485,7 @@ func (m *Topology) Reset()         { *m = Topology{} }
 func (m *Topology) String() string { return proto.CompactTextString(m) }
 func (*Topology) ProtoMessage()    {}
 func (*Topology) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{33}
+	return fileDescriptor_d2a91b51c7bdc125, []int{37}
 }
 func (m *Topology) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2286,7 +2538,7 @@ func (m *RecalculateCaches) Reset()         { *m = RecalculateCaches{} }
 func (m *RecalculateCaches) String() string { return proto.CompactTextString(m) }
 func (*RecalculateCaches) ProtoMessage()    {}
 func (*RecalculateCaches) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{34}
+	return fileDescriptor_d2a91b51c7bdc125, []int{38}
 }
 func (m *RecalculateCaches) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2325,7 +2577,7 @@ func (m *LoadSchemaMessage) Reset()         { *m = LoadSchemaMessage{} }
 func (m *LoadSchemaMessage) String() string { return proto.CompactTextString(m) }
 func (*LoadSchemaMessage) ProtoMessage()    {}
 func (*LoadSchemaMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{35}
+	return fileDescriptor_d2a91b51c7bdc125, []int{39}
 }
 func (m *LoadSchemaMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2366,7 +2618,7 @@ func (m *TransactionMessage) Reset()         { *m = TransactionMessage{} }
 func (m *TransactionMessage) String() string { return proto.CompactTextString(m) }
 func (*TransactionMessage) ProtoMessage()    {}
 func (*TransactionMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{36}
+	return fileDescriptor_d2a91b51c7bdc125, []int{40}
 }
 func (m *TransactionMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2425,7 +2677,7 @@ func (m *Transaction) Reset()         { *m = Transaction{} }
 func (m *Transaction) String() string { return proto.CompactTextString(m) }
 func (*Transaction) ProtoMessage()    {}
 func (*Transaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{37}
+	return fileDescriptor_d2a91b51c7bdc125, []int{41}
 }
 func (m *Transaction) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2506,7 +2758,7 @@ func (m *TransactionStats) Reset()         { *m = TransactionStats{} }
 func (m *TransactionStats) String() string { return proto.CompactTextString(m) }
 func (*TransactionStats) ProtoMessage()    {}
 func (*TransactionStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{38}
+	return fileDescriptor_d2a91b51c7bdc125, []int{42}
 }
 func (m *TransactionStats) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2545,7 +2797,7 @@ func (m *ResizeAbortMessage) Reset()         { *m = ResizeAbortMessage{} }
 func (m *ResizeAbortMessage) String() string { return proto.CompactTextString(m) }
 func (*ResizeAbortMessage) ProtoMessage()    {}
 func (*ResizeAbortMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{39}
+	return fileDescriptor_d2a91b51c7bdc125, []int{43}
 }
 func (m *ResizeAbortMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2586,7 +2838,7 @@ func (m *ResizeNodeMessage) Reset()         { *m = ResizeNodeMessage{} }
 func (m *ResizeNodeMessage) String() string { return proto.CompactTextString(m) }
 func (*ResizeNodeMessage) ProtoMessage()    {}
 func (*ResizeNodeMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{40}
+	return fileDescriptor_d2a91b51c7bdc125, []int{44}
 }
 func (m *ResizeNodeMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2642,7 +2894,7 @@ func (m *FieldOperation) Reset()         { *m = FieldOperation{} }
 func (m *FieldOperation) String() string { return proto.CompactTextString(m) }
 func (*FieldOperation) ProtoMessage()    {}
 func (*FieldOperation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{41}
+	return fileDescriptor_d2a91b51c7bdc125, []int{45}
 }
 func (m *FieldOperation) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2706,7 +2958,7 @@ func (m *ShardIngestOperation) Reset()         { *m = ShardIngestOperation{} }
 func (m *ShardIngestOperation) String() string { return proto.CompactTextString(m) }
 func (*ShardIngestOperation) ProtoMessage()    {}
 func (*ShardIngestOperation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{42}
+	return fileDescriptor_d2a91b51c7bdc125, []int{46}
 }
 func (m *ShardIngestOperation) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2774,7 +3026,7 @@ func (m *ShardIngestOperations) Reset()         { *m = ShardIngestOperations{} }
 func (m *ShardIngestOperations) String() string { return proto.CompactTextString(m) }
 func (*ShardIngestOperations) ProtoMessage()    {}
 func (*ShardIngestOperations) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{43}
+	return fileDescriptor_d2a91b51c7bdc125, []int{47}
 }
 func (m *ShardIngestOperations) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2821,7 +3073,7 @@ func (m *ShardedIngestRequest) Reset()         { *m = ShardedIngestRequest{} }
 func (m *ShardedIngestRequest) String() string { return proto.CompactTextString(m) }
 func (*ShardedIngestRequest) ProtoMessage()    {}
 func (*ShardedIngestRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{44}
+	return fileDescriptor_d2a91b51c7bdc125, []int{48}
 }
 func (m *ShardedIngestRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2868,7 +3120,7 @@ func (m *DeleteDataframeMessage) Reset()         { *m = DeleteDataframeMessage{}
 func (m *DeleteDataframeMessage) String() string { return proto.CompactTextString(m) }
 func (*DeleteDataframeMessage) ProtoMessage()    {}
 func (*DeleteDataframeMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d2a91b51c7bdc125, []int{45}
+	return fileDescriptor_d2a91b51c7bdc125, []int{49}
 }
 func (m *DeleteDataframeMessage) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2915,11 +3167,15 @@ func init() {
 	proto.RegisterMapType((map[string]uint64)(nil), "pb.MaxShards.StandardEntry")
 	proto.RegisterType((*CreateShardMessage)(nil), "pb.CreateShardMessage")
 	proto.RegisterType((*DeleteIndexMessage)(nil), "pb.DeleteIndexMessage")
+	proto.RegisterType((*RenameIndexMessage)(nil), "pb.RenameIndexMessage")
 	proto.RegisterType((*CreateIndexMessage)(nil), "pb.CreateIndexMessage")
 	proto.RegisterType((*CreateFieldMessage)(nil), "pb.CreateFieldMessage")
 	proto.RegisterType((*UpdateFieldMessage)(nil), "pb.UpdateFieldMessage")
 	proto.RegisterType((*FieldUpdate)(nil), "pb.FieldUpdate")
+	proto.RegisterType((*UpdateIndexMessage)(nil), "pb.UpdateIndexMessage")
+	proto.RegisterType((*IndexUpdate)(nil), "pb.IndexUpdate")
 	proto.RegisterType((*DeleteFieldMessage)(nil), "pb.DeleteFieldMessage")
+	proto.RegisterType((*RenameFieldMessage)(nil), "pb.RenameFieldMessage")
 	proto.RegisterType((*DeleteAvailableShardMessage)(nil), "pb.DeleteAvailableShardMessage")
 	proto.RegisterType((*Field)(nil), "pb.Field")
 	proto.RegisterType((*Schema)(nil), "pb.Schema")
@@ -2959,117 +3215,123 @@ func init() {
 func init() { proto.RegisterFile("private.proto", fileDescriptor_d2a91b51c7bdc125) }
 
 var fileDescriptor_d2a91b51c7bdc125 = []byte{
-	// 1750 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0xcd, 0x6e, 0x24, 0x49,
-	0x11, 0xa6, 0x7e, 0xdc, 0x3f, 0xd1, 0x6e, 0x4f, 0x3b, 0xd7, 0x6b, 0x6a, 0xbc, 0x83, 0xd5, 0x93,
-	0xa0, 0x99, 0x66, 0x24, 0x8c, 0xf0, 0x1e, 0x16, 0xb1, 0x97, 0x1d, 0x77, 0x7b, 0x96, 0x66, 0x77,
-	0x7e, 0x36, 0xed, 0x99, 0x23, 0x28, 0x5d, 0x9d, 0xd8, 0xa5, 0xa9, 0xae, 0x6a, 0xaa, 0xaa, 0x3d,
-	0xdd, 0x7b, 0x40, 0x02, 0x09, 0xc1, 0x85, 0x3b, 0xe2, 0xc0, 0x5b, 0xf0, 0x0e, 0x5c, 0x90, 0x78,
-	0x04, 0x34, 0xdc, 0x78, 0x0a, 0x94, 0x91, 0x99, 0x55, 0xd9, 0xed, 0xb2, 0xbd, 0x58, 0x7b, 0xab,
-	0xf8, 0x22, 0x2b, 0xf2, 0x8b, 0x9f, 0x8c, 0x8c, 0x2a, 0xe8, 0xce, 0xb2, 0xe8, 0x92, 0x17, 0xe2,
-	0x60, 0x96, 0xa5, 0x45, 0x4a, 0xdc, 0xd9, 0xd9, 0xde, 0xe6, 0x6c, 0x7e, 0x16, 0x47, 0xa1, 0x42,
-	0x68, 0x04, 0xed, 0x71, 0x32, 0x11, 0x8b, 0xe7, 0xa2, 0xe0, 0x84, 0x80, 0xff, 0x85, 0x58, 0xe6,
-	0x81, 0xd7, 0x77, 0x06, 0x2d, 0x86, 0xcf, 0xe4, 0x11, 0x6c, 0x9d, 0x66, 0x3c, 0x7c, 0x7b, 0xbc,
-	0x88, 0xf2, 0x42, 0x24, 0xa1, 0x08, 0x7c, 0xd4, 0xae, 0xa1, 0xa4, 0x0f, 0x9d, 0x91, 0xc8, 0xc3,
-	0x2c, 0x9a, 0x15, 0x51, 0x9a, 0x04, 0x1b, 0x7d, 0x67, 0xd0, 0x66, 0x36, 0x44, 0xff, 0xeb, 0xc1,
-	0xe6, 0xb3, 0x48, 0xc4, 0x93, 0x97, 0x28, 0xe7, 0x72, 0xbb, 0xd3, 0xe5, 0x4c, 0x04, 0x2d, 0x5c,
-	0x8b, 0xcf, 0xe4, 0x01, 0xb4, 0x87, 0x3c, 0xbc, 0x10, 0xa8, 0xf0, 0x50, 0x51, 0x01, 0xa5, 0xf6,
-	0x24, 0xfa, 0x5a, 0xf1, 0xe8, 0xb2, 0x0a, 0x90, 0x14, 0x4e, 0xa3, 0xa9, 0xf8, 0x6a, 0xce, 0x93,
-	0x62, 0x3e, 0x35, 0x14, 0x2c, 0x88, 0xec, 0x42, 0xe3, 0x65, 0x3c, 0x79, 0x1e, 0x25, 0x41, 0xbb,
-	0xef, 0x0c, 0x3c, 0xa6, 0x25, 0x83, 0xf3, 0x45, 0x00, 0x15, 0xce, 0x17, 0x65, 0x40, 0x3a, 0xab,
-	0x01, 0x79, 0x91, 0x9e, 0x14, 0x3c, 0x99, 0xf0, 0x6c, 0xf2, 0x26, 0x12, 0xef, 0x82, 0x4d, 0x15,
-	0x90, 0x55, 0x54, 0xbe, 0x7b, 0xc4, 0x73, 0x11, 0x74, 0xd1, 0x22, 0x3e, 0x93, 0x3d, 0x68, 0x1d,
-	0x45, 0xc5, 0x48, 0xcc, 0x8a, 0x8b, 0x60, 0xab, 0xef, 0x0c, 0x7c, 0x56, 0xca, 0x64, 0x07, 0x36,
-	0x4e, 0x42, 0x1e, 0x8b, 0xe0, 0x1e, 0xbe, 0xa0, 0x04, 0x42, 0x61, 0xf3, 0x59, 0x9a, 0x89, 0xe8,
-	0x3c, 0xc1, 0x34, 0x05, 0x3d, 0x74, 0x6a, 0x05, 0x23, 0xdf, 0x03, 0x4f, 0xba, 0xb4, 0xdd, 0x77,
-	0x06, 0x9d, 0xc3, 0xce, 0xc1, 0xec, 0xec, 0x60, 0x24, 0xc2, 0x68, 0xca, 0x63, 0x26, 0x71, 0x54,
-	0xf3, 0x45, 0x40, 0xea, 0xd4, 0x7c, 0x21, 0x39, 0xc9, 0x10, 0xbd, 0x4e, 0xa2, 0x22, 0xf8, 0x00,
-	0xad, 0x97, 0x32, 0xe9, 0x81, 0x77, 0x7a, 0xfa, 0x65, 0xb0, 0x83, 0xb0, 0x7c, 0xac, 0x29, 0x87,
-	0x0f, 0xeb, 0xca, 0x81, 0x52, 0xd8, 0x1a, 0x4f, 0x67, 0x69, 0x56, 0x30, 0x91, 0xcf, 0xd2, 0x24,
-	0x17, 0xd2, 0xd6, 0x71, 0x96, 0x05, 0x8e, 0xb2, 0x75, 0x9c, 0x65, 0xf4, 0xb7, 0xd0, 0x3b, 0x8a,
-	0xd3, 0xf0, 0xed, 0x88, 0x17, 0x9c, 0x89, 0xdf, 0xcc, 0x45, 0x5e, 0xc8, 0x28, 0x28, 0x47, 0xd5,
-	0x3a, 0x25, 0x48, 0x14, 0x2b, 0x27, 0x70, 0x15, 0x8a, 0x82, 0x8c, 0x30, 0xc6, 0x5f, 0x25, 0x1a,
-	0x9f, 0x31, 0x8a, 0x17, 0x3c, 0x9b, 0x60, 0x75, 0xf8, 0x4c, 0x09, 0x12, 0xc5, 0x9d, 0xb0, 0xa2,
-	0x7c, 0xa6, 0x04, 0x3a, 0x86, 0x6d, 0x6b, 0x7f, 0x4d, 0x73, 0x17, 0x1a, 0x2c, 0x7d, 0x37, 0x1e,
-	0xe5, 0x81, 0xd3, 0xf7, 0x06, 0x3e, 0xd3, 0x12, 0x96, 0x5e, 0x1a, 0xcf, 0xa7, 0x89, 0x54, 0xb9,
-	0xa8, 0xaa, 0x00, 0x7a, 0x1f, 0x36, 0xb0, 0x0e, 0xa5, 0x97, 0xd5, 0xbb, 0xf2, 0x91, 0xfe, 0xce,
-	0x81, 0xf6, 0x73, 0xbe, 0x40, 0x22, 0x39, 0xf9, 0x04, 0x5a, 0xa6, 0x4a, 0x70, 0x51, 0xe7, 0xf0,
-	0x23, 0x99, 0x91, 0x72, 0xc1, 0x81, 0xd1, 0x1e, 0x27, 0x45, 0xb6, 0x64, 0xe5, 0xe2, 0xbd, 0x4f,
-	0xa1, 0xbb, 0xa2, 0x92, 0x3b, 0xbd, 0x15, 0x4b, 0x13, 0xcf, 0xb7, 0x62, 0x29, 0xbd, 0xbc, 0xe4,
-	0xf1, 0x5c, 0x60, 0x94, 0x7c, 0xa6, 0x84, 0x9f, 0xb9, 0x3f, 0x75, 0xe8, 0x1b, 0x20, 0xc3, 0x4c,
-	0xf0, 0x42, 0xe0, 0x26, 0xcf, 0x45, 0x9e, 0xf3, 0x73, 0x71, 0x5b, 0xac, 0x3d, 0x3b, 0xd6, 0x65,
-	0x5c, 0x5d, 0x2b, 0xae, 0xf4, 0x09, 0x90, 0x91, 0x88, 0x45, 0x21, 0x74, 0x0f, 0xb9, 0xc1, 0xae,
-	0x8c, 0x83, 0x26, 0x71, 0xfb, 0x62, 0xf2, 0x10, 0x7c, 0xd9, 0x91, 0x70, 0xb7, 0xce, 0x61, 0x57,
-	0x86, 0xa8, 0x6c, 0x53, 0x0c, 0x55, 0x98, 0x10, 0x34, 0x37, 0x79, 0x5a, 0x20, 0x57, 0x8f, 0x55,
-	0x80, 0x34, 0xfb, 0xf2, 0x5d, 0x22, 0x32, 0x5d, 0x1c, 0x4a, 0xa0, 0x7f, 0x2d, 0x39, 0xa0, 0x57,
-	0xdf, 0x30, 0x10, 0x2b, 0x45, 0xf7, 0x03, 0xcd, 0xcc, 0x43, 0x66, 0x3d, 0xc9, 0xcc, 0x6e, 0x6a,
-	0x75, 0xe4, 0xfc, 0x6f, 0x46, 0xee, 0x0f, 0x0e, 0x90, 0xd7, 0xb3, 0xc9, 0x3a, 0xb9, 0x67, 0x75,
-	0x94, 0x91, 0x69, 0xe7, 0x70, 0x57, 0x6e, 0x7f, 0x55, 0xcb, 0xea, 0x9c, 0x7c, 0x0c, 0x0d, 0x65,
-	0x5d, 0x07, 0xf5, 0x5e, 0x49, 0x5d, 0xc1, 0x4c, 0xab, 0xe9, 0xa7, 0xd0, 0xb1, 0x60, 0xec, 0x8d,
-	0xaa, 0xa7, 0xab, 0xe8, 0x68, 0x49, 0x3a, 0xf1, 0xa6, 0xac, 0xb6, 0x36, 0x53, 0x02, 0xfd, 0xcc,
-	0x54, 0xc4, 0x5d, 0x03, 0x4c, 0x43, 0xf8, 0x48, 0x59, 0x78, 0x7a, 0xc9, 0xa3, 0x98, 0x9f, 0xc5,
-	0xff, 0x57, 0xd1, 0xae, 0xe4, 0x2a, 0x80, 0x26, 0xbe, 0x3b, 0x1e, 0xe9, 0x83, 0x6f, 0x44, 0x3a,
-	0x87, 0xaa, 0x87, 0xbc, 0xe0, 0x53, 0xa1, 0xad, 0xe1, 0x73, 0x99, 0x62, 0xf7, 0xc6, 0x14, 0x4b,
-	0xff, 0x23, 0xf1, 0x4e, 0xde, 0x96, 0x1e, 0xfa, 0x2f, 0x85, 0x9b, 0x13, 0x4f, 0x7f, 0x04, 0x8d,
-	0x93, 0xf0, 0x42, 0x4c, 0x39, 0xf9, 0x3e, 0x34, 0x91, 0xb9, 0xc8, 0x75, 0x1b, 0x68, 0x97, 0x35,
-	0xce, 0x8c, 0x46, 0x56, 0x84, 0xf6, 0xaf, 0x8e, 0xe6, 0xca, 0x56, 0xee, 0x7a, 0x8d, 0x3d, 0x86,
-	0xa6, 0xe6, 0x8b, 0x55, 0x76, 0xe5, 0x10, 0x19, 0x2d, 0x79, 0x08, 0x0d, 0xf4, 0x2e, 0x0f, 0xfc,
-	0x8a, 0x08, 0x22, 0x4c, 0x2b, 0xe8, 0x31, 0x78, 0xaf, 0xd9, 0x58, 0x56, 0x02, 0xb2, 0x37, 0x34,
-	0xb4, 0x24, 0xc9, 0xfd, 0x3c, 0xcd, 0x0b, 0x1d, 0x7b, 0x7c, 0x96, 0xd8, 0xab, 0x34, 0x53, 0x07,
-	0xb3, 0xcb, 0xf0, 0x99, 0xfe, 0xc9, 0x01, 0xff, 0x45, 0x3a, 0x11, 0x64, 0x0b, 0xdc, 0xf1, 0x48,
-	0x1b, 0x71, 0xc7, 0x23, 0x72, 0x1f, 0xed, 0xeb, 0x78, 0x37, 0xe5, 0xfe, 0xaf, 0xd9, 0x98, 0xe1,
-	0x9e, 0x0f, 0xa0, 0x3d, 0xce, 0x5f, 0x65, 0xd1, 0x94, 0x67, 0x4b, 0x3d, 0x97, 0x54, 0x00, 0x76,
-	0xa5, 0x42, 0x96, 0xb4, 0xaf, 0xd2, 0x8e, 0x02, 0x79, 0x08, 0xcd, 0xcf, 0xd9, 0xab, 0xa1, 0x34,
-	0xb9, 0xb1, 0x6a, 0xd2, 0xe0, 0xf4, 0x33, 0xe8, 0x49, 0x26, 0xb8, 0xde, 0x54, 0xd6, 0x2e, 0x34,
-	0x24, 0x56, 0x32, 0xd3, 0x52, 0xb5, 0x89, 0x6b, 0x6d, 0x42, 0x9f, 0x29, 0x0b, 0xc7, 0x97, 0x22,
-	0x29, 0xac, 0xda, 0x44, 0x19, 0x0d, 0x74, 0x99, 0x12, 0xc8, 0x03, 0xe5, 0xb5, 0x76, 0xaf, 0x25,
-	0xb9, 0x48, 0x99, 0x21, 0x4a, 0x97, 0x00, 0x86, 0xc9, 0x3c, 0x2f, 0xd7, 0x3a, 0x75, 0x6b, 0x09,
-	0x35, 0xe5, 0xa3, 0xbb, 0x0f, 0x48, 0xbd, 0x42, 0x98, 0x29, 0xac, 0x1f, 0x56, 0x85, 0xa5, 0xf2,
-	0x79, 0xaf, 0xcc, 0xbb, 0xda, 0xa3, 0x2a, 0xaf, 0x0b, 0xe8, 0x58, 0x78, 0x6d, 0x8d, 0x3d, 0x2e,
-	0x8b, 0xc3, 0xad, 0x8c, 0x21, 0xa2, 0x8d, 0x69, 0xf5, 0xcd, 0xdd, 0x98, 0x46, 0xba, 0xa5, 0xdc,
-	0xb0, 0xd3, 0x00, 0xee, 0xad, 0x1e, 0x78, 0x73, 0xcb, 0xae, 0xc3, 0xb7, 0x6c, 0xf5, 0x47, 0x07,
-	0xba, 0xc3, 0x78, 0x9e, 0x17, 0x22, 0x2b, 0x63, 0xda, 0xd6, 0x40, 0x99, 0xda, 0x0a, 0xa8, 0xcf,
-	0x2e, 0xd9, 0x87, 0x0d, 0x19, 0x71, 0x75, 0xb8, 0xed, 0x44, 0x28, 0xd8, 0xca, 0x84, 0x7f, 0x5d,
-	0x26, 0xe8, 0x1b, 0x68, 0x1d, 0x9d, 0x8c, 0x3f, 0xcf, 0xd2, 0xf9, 0xac, 0xd6, 0x63, 0x33, 0xfe,
-	0xba, 0xd6, 0xf8, 0xdb, 0x53, 0xa3, 0x9c, 0xf2, 0x0a, 0xa7, 0xb7, 0x9e, 0x9a, 0xde, 0x7c, 0x8d,
-	0xf0, 0x05, 0x3d, 0x81, 0x6d, 0xe5, 0xae, 0xec, 0x38, 0x77, 0x69, 0x8b, 0x66, 0x6e, 0xf2, 0xaa,
-	0xb9, 0x49, 0x1a, 0x55, 0x5d, 0xf7, 0xdb, 0x34, 0xfa, 0x4f, 0x17, 0xb6, 0x99, 0xc8, 0xa3, 0xaf,
-	0xc5, 0x38, 0xc9, 0x8b, 0x6c, 0x1e, 0x9a, 0x8b, 0xe3, 0x17, 0xe9, 0x99, 0xce, 0x85, 0xc7, 0x94,
-	0x70, 0xf3, 0x29, 0x21, 0x14, 0x9a, 0x76, 0x13, 0xb0, 0x17, 0x18, 0x05, 0x79, 0x02, 0xcd, 0x93,
-	0x74, 0x9e, 0x85, 0x65, 0xe5, 0x63, 0xe7, 0x56, 0xfb, 0x2b, 0x05, 0x33, 0x0b, 0xc8, 0x17, 0x40,
-	0x4e, 0x33, 0x9e, 0xe4, 0x31, 0x97, 0x94, 0xcc, 0x6b, 0xad, 0x6a, 0x20, 0xb3, 0xb4, 0x2b, 0x16,
-	0x6a, 0x5e, 0x23, 0x07, 0xf6, 0x11, 0x0e, 0x9a, 0xc8, 0x6f, 0xcb, 0xf0, 0xd3, 0xe7, 0xc4, 0x3e,
-	0xe4, 0x9f, 0xac, 0x55, 0x68, 0xd0, 0xc0, 0x57, 0xb6, 0xf1, 0x32, 0xb7, 0x15, 0x6c, 0x75, 0x1d,
-	0xfd, 0xbd, 0x03, 0x9b, 0x36, 0x9b, 0x5b, 0xda, 0x45, 0x99, 0x3e, 0xf7, 0xf6, 0xf9, 0xce, 0xa4,
-	0xcf, 0xaf, 0x9b, 0xa5, 0x37, 0xec, 0x99, 0x2f, 0x85, 0xef, 0x5e, 0x13, 0x9c, 0x3b, 0xd1, 0xe9,
-	0x43, 0xe7, 0x15, 0xcf, 0x8a, 0x48, 0x1a, 0xd3, 0xf7, 0xf4, 0x06, 0xb3, 0x21, 0x2a, 0xe0, 0xfe,
-	0x95, 0x22, 0x1a, 0xa6, 0xd3, 0x99, 0xac, 0xd6, 0x3b, 0x15, 0x93, 0x6c, 0xd3, 0x59, 0x96, 0x66,
-	0x26, 0x02, 0x28, 0xd0, 0x23, 0x68, 0x9d, 0xa6, 0xb3, 0x34, 0x4e, 0xcf, 0x97, 0xb7, 0xb4, 0x8c,
-	0x00, 0x9a, 0xea, 0x6a, 0x50, 0x2d, 0xaa, 0xcd, 0x8c, 0x48, 0x3f, 0x90, 0xf5, 0x1e, 0xf2, 0x38,
-	0x9c, 0xc7, 0xbc, 0x10, 0xf8, 0x45, 0x80, 0xe0, 0x97, 0x29, 0x9f, 0xa8, 0xae, 0xa0, 0x8f, 0x16,
-	0xfd, 0x95, 0x2e, 0x40, 0x8e, 0xee, 0x58, 0x57, 0xd0, 0xd3, 0xd0, 0x9e, 0xb5, 0x94, 0x44, 0x7e,
-	0x02, 0x1d, 0x6b, 0xb5, 0x3d, 0xc0, 0x59, 0x30, 0xb3, 0xd7, 0xd0, 0xbf, 0x3b, 0x2b, 0xef, 0x5c,
-	0xb9, 0x73, 0xf5, 0x56, 0x97, 0x2a, 0x48, 0x2d, 0xa6, 0x25, 0xe9, 0xfa, 0xf1, 0x22, 0x8c, 0xe7,
-	0xb9, 0x54, 0xe9, 0x0b, 0xb7, 0x04, 0xa4, 0xeb, 0xf2, 0xe3, 0x30, 0x9d, 0x9b, 0xe1, 0xc6, 0x88,
-	0xf2, 0x33, 0x72, 0x24, 0xf8, 0x24, 0x8e, 0x12, 0x81, 0xf5, 0xe2, 0xb1, 0x52, 0x26, 0x4f, 0x54,
-	0x8f, 0x35, 0x85, 0xbe, 0xb3, 0x46, 0x1c, 0x75, 0xaa, 0xf3, 0xe6, 0x94, 0x40, 0x6f, 0x5d, 0x45,
-	0x77, 0x80, 0xa8, 0x0a, 0x78, 0x7a, 0x96, 0x66, 0xe6, 0xb6, 0xa5, 0x43, 0xd3, 0x5c, 0x64, 0xf4,
-	0x6f, 0xbb, 0xc4, 0xab, 0xc8, 0xba, 0x76, 0x64, 0xe9, 0x2f, 0x61, 0x4b, 0xcf, 0x76, 0x22, 0xc3,
-	0x82, 0x96, 0x01, 0x60, 0x22, 0x4c, 0xe5, 0x98, 0x68, 0xbe, 0xe3, 0x2a, 0x40, 0xda, 0xc1, 0x41,
-	0xd7, 0xdc, 0x4e, 0x5a, 0xc2, 0xd9, 0x28, 0x3a, 0x4f, 0xc4, 0x04, 0x6f, 0x0c, 0x8f, 0x69, 0x89,
-	0xfe, 0xd9, 0x85, 0x1d, 0x35, 0x74, 0x26, 0xe7, 0x22, 0x2f, 0xaa, 0x6d, 0x70, 0xac, 0xc6, 0xfe,
-	0x5f, 0x8e, 0xd5, 0x78, 0x03, 0x3c, 0x82, 0xad, 0x61, 0x2c, 0x78, 0x56, 0x71, 0x50, 0x1b, 0xad,
-	0xa1, 0xf2, 0xdc, 0x20, 0xa2, 0xaf, 0x67, 0x35, 0x84, 0xda, 0x10, 0x39, 0x82, 0x96, 0x76, 0xcd,
-	0x34, 0xc4, 0x47, 0x78, 0x4b, 0xd5, 0xb0, 0x31, 0xf3, 0x6d, 0xae, 0xbf, 0x3a, 0x8d, 0xb8, 0xf7,
-	0x12, 0xba, 0x2b, 0xaa, 0x9a, 0xaf, 0xce, 0x81, 0xfd, 0xd5, 0xd9, 0x39, 0x24, 0xd6, 0xb8, 0xac,
-	0xad, 0xdb, 0x5f, 0xa2, 0x43, 0xf8, 0xb0, 0x8e, 0x40, 0x4e, 0x9e, 0x80, 0x27, 0x89, 0xaa, 0x61,
-	0x38, 0xb8, 0x8e, 0x28, 0x93, 0x8b, 0xe8, 0xdf, 0x1c, 0x1d, 0x54, 0xa1, 0xf5, 0xe6, 0xef, 0xc1,
-	0xc7, 0xb6, 0x91, 0x87, 0xa5, 0x91, 0xb5, 0x65, 0x07, 0xa5, 0xa3, 0x72, 0xf5, 0xde, 0x57, 0xd0,
-	0xaa, 0x73, 0xcf, 0x57, 0xee, 0xfd, 0x78, 0xd5, 0xbd, 0xfb, 0xd7, 0x31, 0xcb, 0x6d, 0x2f, 0x0f,
-	0x60, 0x57, 0xdd, 0xa6, 0x23, 0x5e, 0xf0, 0x5f, 0x67, 0x7c, 0x2a, 0x6e, 0xbc, 0x52, 0x8f, 0x7a,
-	0xff, 0x78, 0xbf, 0xef, 0xfc, 0xeb, 0xfd, 0xbe, 0xf3, 0xef, 0xf7, 0xfb, 0xce, 0x5f, 0xfe, 0xb3,
-	0xff, 0x9d, 0xb3, 0x06, 0xfe, 0x9e, 0xfb, 0xf8, 0x7f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xe2, 0x0d,
-	0x5d, 0xdb, 0xc1, 0x13, 0x00, 0x00,
+	// 1846 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x4f, 0x6f, 0x23, 0x49,
+	0x15, 0xa7, 0x6d, 0x27, 0xb6, 0x9f, 0xe3, 0x4c, 0x52, 0x9b, 0x0d, 0x3d, 0xd9, 0x21, 0xca, 0x14,
+	0x68, 0xc7, 0x8c, 0x44, 0x10, 0xd9, 0xc3, 0x22, 0xf6, 0xb2, 0x93, 0x38, 0x59, 0xcc, 0xec, 0x4c,
+	0xb2, 0x95, 0xcc, 0x9c, 0x10, 0xa8, 0xd2, 0x2e, 0x92, 0xd6, 0xb4, 0xbb, 0x4d, 0x77, 0x3b, 0xb1,
+	0xf7, 0x80, 0x04, 0x12, 0x82, 0x0b, 0x77, 0xc4, 0x81, 0x6f, 0xc1, 0x77, 0xe0, 0x82, 0xc4, 0x37,
+	0x00, 0x0d, 0x37, 0x3e, 0x05, 0x7a, 0xaf, 0xaa, 0xba, 0xcb, 0x4e, 0x4f, 0x32, 0x1b, 0x71, 0xab,
+	0xf7, 0x7b, 0xaf, 0x5f, 0xbd, 0x7f, 0xf5, 0xea, 0x55, 0x43, 0x77, 0x9c, 0x86, 0x57, 0x32, 0x57,
+	0xbb, 0xe3, 0x34, 0xc9, 0x13, 0x56, 0x1b, 0x9f, 0x6f, 0xad, 0x8c, 0x27, 0xe7, 0x51, 0x18, 0x68,
+	0x84, 0xff, 0xcb, 0x83, 0xf6, 0x20, 0x1e, 0xaa, 0xe9, 0x0b, 0x95, 0x4b, 0xc6, 0xa0, 0xf1, 0x5c,
+	0xcd, 0x32, 0xbf, 0xbe, 0xe3, 0xf5, 0x5a, 0x82, 0xd6, 0xec, 0x63, 0x58, 0x3d, 0x4b, 0x65, 0xf0,
+	0xe6, 0x70, 0x1a, 0x66, 0xb9, 0x8a, 0x03, 0xe5, 0x37, 0x88, 0xbb, 0x80, 0xb2, 0x1d, 0xe8, 0xf4,
+	0x55, 0x16, 0xa4, 0xe1, 0x38, 0x0f, 0x93, 0xd8, 0x5f, 0xda, 0xf1, 0x7a, 0x6d, 0xe1, 0x42, 0xa8,
+	0x49, 0xa8, 0x5c, 0xc5, 0x48, 0x1c, 0x85, 0x2a, 0x1a, 0xfa, 0xcb, 0x24, 0xb4, 0x80, 0xb2, 0x1e,
+	0x3c, 0x28, 0x90, 0x13, 0x95, 0x86, 0xc9, 0xd0, 0x6f, 0x92, 0xe0, 0x22, 0xcc, 0x38, 0xac, 0x9c,
+	0xc8, 0x34, 0x0f, 0x11, 0x7a, 0xae, 0x66, 0x7e, 0x8b, 0xc4, 0xe6, 0x30, 0xfe, 0xdf, 0x3a, 0xac,
+	0x90, 0xde, 0x63, 0xb2, 0x22, 0x43, 0x27, 0xcf, 0x66, 0x63, 0x65, 0x84, 0x69, 0xcd, 0x1e, 0x41,
+	0xfb, 0x40, 0x06, 0x97, 0x8a, 0x18, 0x75, 0x62, 0x94, 0x40, 0xc1, 0x3d, 0x0d, 0xbf, 0xd6, 0xde,
+	0x77, 0x45, 0x09, 0xa0, 0xe3, 0x67, 0xe1, 0x48, 0x7d, 0x35, 0x91, 0x71, 0x3e, 0x19, 0x59, 0xc7,
+	0x1d, 0x88, 0x6d, 0xc2, 0xf2, 0x71, 0x34, 0x7c, 0x11, 0xc6, 0x7e, 0x7b, 0xc7, 0xeb, 0xd5, 0x85,
+	0xa1, 0x2c, 0x2e, 0xa7, 0x3e, 0x94, 0xb8, 0x9c, 0x16, 0x69, 0xe8, 0xcc, 0xa7, 0xe1, 0x65, 0x72,
+	0x9a, 0xcb, 0x78, 0x28, 0xd3, 0xe1, 0xeb, 0x50, 0x5d, 0xfb, 0x2b, 0x3a, 0x0d, 0xf3, 0x28, 0x7e,
+	0xbb, 0x2f, 0x33, 0xe5, 0x77, 0x49, 0x23, 0xad, 0xd9, 0x16, 0xb4, 0xf6, 0xc3, 0xbc, 0xaf, 0xc6,
+	0xf9, 0xa5, 0xbf, 0xba, 0xe3, 0xf5, 0x1a, 0xa2, 0xa0, 0xd9, 0x06, 0x2c, 0x9d, 0x06, 0x32, 0x52,
+	0xfe, 0x03, 0xfa, 0x40, 0x13, 0x18, 0xd8, 0xa3, 0x24, 0x55, 0xe1, 0x45, 0x4c, 0xc5, 0xe1, 0xaf,
+	0xe9, 0xc0, 0xba, 0x18, 0xfb, 0x0e, 0xd4, 0xd1, 0xa5, 0xf5, 0x1d, 0xaf, 0xd7, 0xd9, 0xeb, 0xec,
+	0x8e, 0xcf, 0x77, 0xfb, 0x2a, 0x08, 0x47, 0x32, 0x12, 0x88, 0x13, 0x5b, 0x4e, 0x7d, 0x56, 0xc5,
+	0x96, 0x53, 0xb4, 0x09, 0x43, 0xf4, 0x2a, 0x0e, 0x73, 0xff, 0x03, 0xd2, 0x5e, 0xd0, 0x6c, 0x0d,
+	0xea, 0x67, 0x67, 0x5f, 0xfa, 0x1b, 0x04, 0xe3, 0xb2, 0xa2, 0x08, 0x3f, 0xac, 0x2a, 0x42, 0xce,
+	0x61, 0x75, 0x30, 0x1a, 0x27, 0x69, 0x2e, 0x54, 0x36, 0x4e, 0xe2, 0x4c, 0xa1, 0xae, 0xc3, 0x34,
+	0xf5, 0x3d, 0xad, 0xeb, 0x30, 0x4d, 0xf9, 0x6f, 0x60, 0x6d, 0x3f, 0x4a, 0x82, 0x37, 0x7d, 0x99,
+	0x4b, 0xa1, 0x7e, 0x3d, 0x51, 0x59, 0x8e, 0x51, 0xd0, 0x8e, 0x6a, 0x39, 0x4d, 0x20, 0xaa, 0xeb,
+	0xb4, 0xa6, 0x51, 0x22, 0x30, 0xc2, 0x14, 0x7f, 0x9d, 0x68, 0x5a, 0x53, 0x14, 0x2f, 0x65, 0x3a,
+	0xa4, 0xea, 0x68, 0x08, 0x4d, 0x20, 0x4a, 0x3b, 0x51, 0x45, 0x35, 0x84, 0x26, 0xf8, 0x00, 0xd6,
+	0x9d, 0xfd, 0x8d, 0x99, 0x9b, 0xb0, 0x2c, 0x92, 0xeb, 0x41, 0x3f, 0xf3, 0xbd, 0x9d, 0x7a, 0xaf,
+	0x21, 0x0c, 0x45, 0xa5, 0x97, 0x44, 0x93, 0x51, 0x8c, 0xac, 0x1a, 0xb1, 0x4a, 0x80, 0x3f, 0x84,
+	0x25, 0xaa, 0x43, 0xf4, 0xb2, 0xfc, 0x16, 0x97, 0xfc, 0xb7, 0x1e, 0xb4, 0x5f, 0xc8, 0x29, 0x19,
+	0x92, 0xb1, 0x4f, 0xa1, 0x65, 0xab, 0x84, 0x84, 0x3a, 0x7b, 0x1f, 0x61, 0x46, 0x0a, 0x81, 0x5d,
+	0xcb, 0x3d, 0x8c, 0xf3, 0x74, 0x26, 0x0a, 0xe1, 0xad, 0xcf, 0xa0, 0x3b, 0xc7, 0xc2, 0x9d, 0xde,
+	0xa8, 0x99, 0x8d, 0xe7, 0x1b, 0x35, 0x43, 0x2f, 0xaf, 0x64, 0x34, 0x51, 0x14, 0xa5, 0x86, 0xd0,
+	0xc4, 0x4f, 0x6a, 0x3f, 0xf6, 0xf8, 0x6b, 0x60, 0x07, 0xa9, 0x92, 0xb9, 0xa2, 0x4d, 0x5e, 0xa8,
+	0x2c, 0x93, 0x17, 0xea, 0xae, 0x58, 0xd7, 0xdd, 0x58, 0x17, 0x71, 0xad, 0x39, 0x71, 0xe5, 0x4f,
+	0x81, 0xf5, 0x55, 0xa4, 0x72, 0x65, 0x3a, 0xd7, 0x2d, 0x7a, 0xf9, 0x11, 0x30, 0xa1, 0x62, 0x39,
+	0x7a, 0x0f, 0x59, 0xac, 0xc9, 0x97, 0xea, 0x5a, 0x33, 0x74, 0xca, 0x0b, 0x1a, 0xe3, 0x69, 0x9c,
+	0x79, 0x0f, 0x45, 0x8f, 0xa1, 0x81, 0xfd, 0x94, 0x94, 0x74, 0xf6, 0xba, 0x18, 0xea, 0xa2, 0xc9,
+	0x0a, 0x62, 0x51, 0x62, 0x49, 0xdd, 0xf0, 0x59, 0x4e, 0x3e, 0xd7, 0x45, 0x09, 0xa0, 0xda, 0xe3,
+	0xeb, 0x58, 0xa5, 0xa6, 0xc8, 0x34, 0xc1, 0xff, 0x52, 0xd8, 0x40, 0xd1, 0x79, 0xcf, 0x80, 0xce,
+	0x15, 0xef, 0xf7, 0x8c, 0x65, 0x75, 0xb2, 0x6c, 0x0d, 0x2d, 0x73, 0x9b, 0x63, 0x95, 0x71, 0x8d,
+	0xf7, 0x33, 0xee, 0xf7, 0x1e, 0xb0, 0x57, 0xe3, 0xe1, 0xa2, 0x71, 0x47, 0x55, 0x26, 0x93, 0xa5,
+	0x9d, 0xbd, 0x4d, 0xdc, 0xfe, 0x26, 0x57, 0x54, 0x39, 0xf9, 0x04, 0x96, 0xb5, 0x76, 0x13, 0xd4,
+	0x07, 0x85, 0xe9, 0x1a, 0x16, 0x86, 0xcd, 0x3f, 0x83, 0x8e, 0x03, 0x53, 0x8f, 0xd5, 0x37, 0x92,
+	0x8e, 0x8e, 0xa1, 0xd0, 0x89, 0xd7, 0x45, 0xd5, 0xb6, 0x85, 0x26, 0x1c, 0x27, 0xe6, 0xb2, 0x7c,
+	0x54, 0x95, 0xfb, 0x9b, 0x4e, 0xb8, 0x5c, 0x51, 0x55, 0x2d, 0x95, 0x4e, 0x90, 0xc4, 0x4d, 0x27,
+	0x1c, 0xf8, 0x1b, 0x3a, 0xf1, 0xb9, 0x3d, 0x1e, 0xf7, 0xad, 0x12, 0xfe, 0x73, 0x7b, 0x68, 0xee,
+	0x5d, 0x67, 0xfa, 0x28, 0xb9, 0x27, 0xba, 0xa0, 0x79, 0x00, 0x1f, 0x69, 0xfb, 0x9e, 0x5d, 0xc9,
+	0x30, 0x92, 0xe7, 0xd1, 0x37, 0xea, 0x0f, 0x73, 0xdb, 0xf8, 0xd0, 0xa4, 0x6f, 0x07, 0x7d, 0xd3,
+	0x63, 0x2d, 0xc9, 0x27, 0x50, 0xb6, 0xeb, 0x97, 0x72, 0xa4, 0x8c, 0x36, 0x5a, 0x17, 0xa7, 0xa0,
+	0x76, 0xeb, 0x29, 0xc0, 0xe8, 0x86, 0xea, 0x1a, 0xc7, 0xa1, 0x3a, 0x45, 0x17, 0x89, 0xdb, 0xcf,
+	0x06, 0xff, 0x01, 0x2c, 0x9f, 0x06, 0x97, 0x6a, 0x24, 0xd9, 0x77, 0xa1, 0x49, 0x96, 0xab, 0xcc,
+	0x74, 0xdc, 0x76, 0x91, 0x6c, 0x61, 0x39, 0x58, 0x6f, 0xc6, 0xbf, 0x2a, 0x33, 0xe7, 0xb6, 0xaa,
+	0x2d, 0x1e, 0xc3, 0x27, 0xd0, 0x34, 0xf6, 0xfa, 0x4b, 0x55, 0x7d, 0xc6, 0x72, 0xd9, 0x63, 0x58,
+	0x26, 0xef, 0x32, 0xbf, 0x51, 0x1a, 0x42, 0x88, 0x30, 0x0c, 0x7e, 0x08, 0xf5, 0x57, 0x62, 0xc0,
+	0x36, 0x8d, 0xf5, 0xd6, 0x0c, 0x43, 0xa1, 0x71, 0x3f, 0x4d, 0xb2, 0xdc, 0xc4, 0x9e, 0xd6, 0x88,
+	0x9d, 0x24, 0xa9, 0xee, 0x5d, 0x5d, 0x41, 0x6b, 0xfe, 0x47, 0x0f, 0x1a, 0x2f, 0x93, 0xa1, 0x62,
+	0xab, 0x50, 0x1b, 0xf4, 0x8d, 0x92, 0xda, 0xa0, 0xcf, 0x1e, 0x92, 0x7e, 0x13, 0xef, 0x26, 0xee,
+	0xff, 0x4a, 0x0c, 0x04, 0xed, 0xf9, 0x08, 0xda, 0x83, 0xec, 0x24, 0x0d, 0x47, 0x32, 0x9d, 0x99,
+	0xc1, 0xb3, 0x04, 0xe8, 0x02, 0xc8, 0xf1, 0xc0, 0x34, 0x74, 0xda, 0x89, 0x60, 0x8f, 0xa1, 0xf9,
+	0x85, 0x38, 0x39, 0x40, 0x95, 0x4b, 0xf3, 0x2a, 0x2d, 0xce, 0x3f, 0x87, 0x35, 0xb4, 0x84, 0xe4,
+	0x6d, 0x65, 0x6d, 0xc2, 0x32, 0x62, 0x85, 0x65, 0x86, 0x2a, 0x37, 0xa9, 0x39, 0x9b, 0xf0, 0x23,
+	0xad, 0xe1, 0xf0, 0x4a, 0xc5, 0xb9, 0x53, 0x9b, 0x44, 0x93, 0x82, 0xae, 0xd0, 0x04, 0x7b, 0xa4,
+	0xbd, 0x36, 0xee, 0xb5, 0xd0, 0x16, 0xa4, 0x05, 0xa1, 0x7c, 0x06, 0x60, 0x2d, 0x99, 0x64, 0x85,
+	0xac, 0x57, 0x25, 0xcb, 0xb8, 0x2d, 0x1f, 0xd3, 0xa0, 0x01, 0xf9, 0x1a, 0x31, 0xc9, 0x90, 0xec,
+	0xfb, 0x65, 0x61, 0xe9, 0x7c, 0x96, 0x5d, 0x44, 0xef, 0x51, 0x96, 0xd7, 0x25, 0x74, 0x1c, 0xbc,
+	0xb2, 0xc6, 0x9e, 0x14, 0xc5, 0x51, 0x2b, 0x95, 0x11, 0x62, 0x94, 0x19, 0xf6, 0xed, 0x17, 0x16,
+	0x0f, 0xa1, 0xe3, 0x7c, 0x54, 0xb9, 0x53, 0x0f, 0x1e, 0xcc, 0x1f, 0x78, 0x3b, 0xd0, 0x2c, 0xc2,
+	0x77, 0x6c, 0xf5, 0x07, 0x0f, 0xba, 0x07, 0xd1, 0x24, 0xcb, 0x55, 0x5a, 0xc4, 0xb4, 0x6d, 0x80,
+	0x22, 0xb5, 0x25, 0x50, 0x9d, 0x5d, 0xb6, 0x0d, 0x4b, 0x18, 0x71, 0x7d, 0xb8, 0xdd, 0x44, 0x68,
+	0xd8, 0xc9, 0x44, 0xe3, 0x5d, 0x99, 0xe0, 0xaf, 0xa1, 0xb5, 0x7f, 0x3a, 0xf8, 0x22, 0x4d, 0x26,
+	0xe3, 0x4a, 0x8f, 0xed, 0x4b, 0xa3, 0xe6, 0xbc, 0x34, 0xd6, 0xf4, 0xd4, 0xac, 0xbd, 0xc2, 0x25,
+	0x21, 0x72, 0x6a, 0x5a, 0x09, 0x2e, 0xf9, 0x29, 0xac, 0x6b, 0x77, 0xb1, 0xe3, 0xdc, 0xa7, 0x2d,
+	0xda, 0x11, 0xb5, 0x5e, 0x8e, 0xa8, 0xa8, 0x54, 0x77, 0xdd, 0xff, 0xa7, 0xd2, 0x7f, 0xd4, 0x60,
+	0x5d, 0xa8, 0x2c, 0xfc, 0x5a, 0x0d, 0xe2, 0x2c, 0x4f, 0x27, 0x81, 0xbd, 0x96, 0x7e, 0x96, 0x9c,
+	0x9b, 0x5c, 0xd4, 0x85, 0x26, 0x6e, 0x3f, 0x25, 0x8c, 0x43, 0xd3, 0x6d, 0x02, 0xae, 0x80, 0x65,
+	0xb0, 0xa7, 0xd0, 0x3c, 0x4d, 0x26, 0x69, 0x50, 0x54, 0x3e, 0x75, 0x6e, 0xbd, 0xbf, 0x66, 0x08,
+	0x2b, 0xc0, 0x9e, 0x03, 0x3b, 0x4b, 0x65, 0x9c, 0x45, 0x12, 0x4d, 0xb2, 0x9f, 0xb5, 0xca, 0xd9,
+	0xd7, 0xe1, 0xce, 0x69, 0xa8, 0xf8, 0x8c, 0xed, 0xba, 0x47, 0x98, 0x1e, 0xa3, 0x9d, 0xbd, 0x55,
+	0x6b, 0x9f, 0x46, 0x85, 0x7b, 0xc8, 0x3f, 0x5d, 0xa8, 0x50, 0x7a, 0xe8, 0x76, 0xf6, 0xd6, 0x69,
+	0x54, 0x70, 0x19, 0x62, 0x5e, 0x8e, 0xff, 0xce, 0x83, 0x15, 0xd7, 0x9a, 0x3b, 0xda, 0x45, 0x91,
+	0xbe, 0xda, 0xdd, 0xa3, 0xb4, 0x4d, 0x5f, 0xa3, 0xea, 0xd9, 0xb2, 0xe4, 0x8e, 0xd7, 0x09, 0x7c,
+	0xfb, 0x1d, 0xc1, 0xb9, 0x97, 0x39, 0x3b, 0xd0, 0x29, 0x1e, 0xe4, 0xe6, 0x9e, 0x5e, 0x12, 0x2e,
+	0xc4, 0x15, 0x3c, 0xbc, 0x51, 0x44, 0x07, 0xc9, 0x68, 0x8c, 0xd5, 0x7a, 0xaf, 0x62, 0xc2, 0x36,
+	0x9d, 0xa6, 0x49, 0x6a, 0x23, 0x40, 0x04, 0xdf, 0x87, 0xd6, 0x59, 0x32, 0x4e, 0xa2, 0xe4, 0x62,
+	0x76, 0x47, 0xcb, 0xf0, 0xa1, 0xa9, 0xaf, 0x06, 0xdd, 0xa2, 0xda, 0xc2, 0x92, 0xfc, 0x03, 0xac,
+	0xf7, 0x40, 0x46, 0xc1, 0x24, 0x92, 0xb9, 0xa2, 0xc7, 0x17, 0x81, 0x5f, 0x26, 0x72, 0xa8, 0xbb,
+	0x82, 0x39, 0x5a, 0xfc, 0x97, 0xa6, 0x00, 0x25, 0xb9, 0xe3, 0x5c, 0x41, 0xcf, 0x02, 0x77, 0x92,
+	0xd3, 0x14, 0xfb, 0x11, 0x74, 0x1c, 0x69, 0x77, 0x3c, 0x74, 0x60, 0xe1, 0xca, 0xf0, 0xbf, 0x79,
+	0x73, 0xdf, 0xdc, 0xb8, 0x73, 0xcd, 0x56, 0x57, 0x3a, 0x48, 0x2d, 0x61, 0x28, 0x74, 0xfd, 0x70,
+	0x1a, 0x44, 0x93, 0x0c, 0x59, 0xe6, 0xc2, 0x2d, 0x00, 0x74, 0x1d, 0xdf, 0xe1, 0xc9, 0xc4, 0x0e,
+	0x37, 0x96, 0xc4, 0x91, 0xae, 0xaf, 0xe4, 0x30, 0x0a, 0x63, 0x45, 0xf5, 0x52, 0x17, 0x05, 0xcd,
+	0x9e, 0xea, 0x1e, 0x6b, 0x0b, 0x7d, 0x63, 0xc1, 0x70, 0xe2, 0xe9, 0xce, 0x9b, 0x71, 0x06, 0x6b,
+	0x8b, 0x2c, 0xbe, 0x01, 0x4c, 0x57, 0xc0, 0xb3, 0xf3, 0x24, 0xb5, 0xb7, 0x2d, 0x3f, 0xb0, 0xcd,
+	0x05, 0xa3, 0x7f, 0xd7, 0x25, 0x5e, 0x46, 0xb6, 0xe6, 0x46, 0x96, 0xff, 0x02, 0x56, 0xcd, 0x6c,
+	0xa7, 0x52, 0x2a, 0x68, 0x0c, 0x80, 0x50, 0x41, 0x82, 0x63, 0xa2, 0x7d, 0x32, 0x97, 0x00, 0xea,
+	0xa1, 0x31, 0xda, 0xde, 0x4e, 0x86, 0x42, 0xfc, 0x34, 0xbc, 0x88, 0xd5, 0x90, 0x6e, 0x8c, 0xba,
+	0x30, 0x14, 0xff, 0x53, 0x0d, 0x36, 0xf4, 0xd0, 0x19, 0x5f, 0xa8, 0x2c, 0x2f, 0xb7, 0xa1, 0xa1,
+	0x9d, 0xfa, 0x7f, 0x31, 0xb4, 0x23, 0x85, 0xff, 0x32, 0x0e, 0x22, 0x25, 0xd3, 0xd2, 0x06, 0xbd,
+	0xd1, 0x02, 0x8a, 0xe7, 0x86, 0x10, 0x73, 0x3d, 0xeb, 0x21, 0xd4, 0x85, 0xd8, 0x3e, 0xb4, 0x8c,
+	0x6b, 0xb6, 0x21, 0x7e, 0x4c, 0xb7, 0x54, 0x85, 0x35, 0x76, 0xbe, 0xcd, 0xcc, 0x03, 0xdf, 0x92,
+	0x5b, 0xc7, 0xd0, 0x9d, 0x63, 0x55, 0x3c, 0xf0, 0x7b, 0xee, 0x03, 0xbf, 0xb3, 0xc7, 0x9c, 0x71,
+	0xd9, 0x68, 0x77, 0x1f, 0xfd, 0x07, 0xf0, 0x61, 0x95, 0x01, 0x19, 0x7b, 0x0a, 0xf5, 0xe3, 0xb1,
+	0x0e, 0x78, 0x67, 0xcf, 0x7f, 0x97, 0xa1, 0x02, 0x85, 0xf8, 0x5f, 0x3d, 0x13, 0x54, 0x65, 0xf8,
+	0xf6, 0x47, 0xcd, 0x27, 0xae, 0x92, 0xc7, 0x85, 0x92, 0x05, 0xb1, 0xdd, 0xc2, 0x51, 0x94, 0xde,
+	0xfa, 0x0a, 0x5a, 0x55, 0xee, 0x35, 0xb4, 0x7b, 0x3f, 0x9c, 0x77, 0xef, 0xe1, 0xbb, 0x2c, 0xcb,
+	0x5c, 0x2f, 0x77, 0x61, 0x53, 0xdf, 0xa6, 0xf8, 0x17, 0xe7, 0x57, 0xa9, 0x1c, 0xa9, 0x5b, 0xaf,
+	0xd4, 0xfd, 0xb5, 0xbf, 0xbf, 0xdd, 0xf6, 0xfe, 0xf9, 0x76, 0xdb, 0xfb, 0xf7, 0xdb, 0x6d, 0xef,
+	0xcf, 0xff, 0xd9, 0xfe, 0xd6, 0xf9, 0x32, 0xfd, 0x80, 0xfd, 0xe4, 0x7f, 0x03, 0x00, 0x4a, 0x00,
+	0x8d, 0xcb, 0xa3, 0x15, 0x00, 0x00,
 }
 
 func (m *IndexMeta) Marshal() (dAtA []byte, err error) {
@@ -3096,6 +3358,27 @@ func (m *IndexMeta) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.PartitionKey) > 0 {
+		i -= len(m.PartitionKey)
+		copy(dAtA[i:], m.PartitionKey)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.PartitionKey)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.RetentionPeriod) > 0 {
+		i -= len(m.RetentionPeriod)
+		copy(dAtA[i:], m.RetentionPeriod)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.RetentionPeriod)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.RetentionField) > 0 {
+		i -= len(m.RetentionField)
+		copy(dAtA[i:], m.RetentionField)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.RetentionField)))
+		i--
+		dAtA[i] = 0x32
+	}
 	if len(m.Description) > 0 {
 		i -= len(m.Description)
 		copy(dAtA[i:], m.Description)
@@ -3615,7 +3898,7 @@ func (m *DeleteIndexMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *CreateIndexMessage) Marshal() (dAtA []byte, err error) {
+func (m *RenameIndexMessage) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3625,12 +3908,12 @@ func (m *CreateIndexMessage) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *CreateIndexMessage) MarshalTo(dAtA []byte) (int, error) {
+func (m *RenameIndexMessage) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *CreateIndexMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *RenameIndexMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -3639,9 +3922,50 @@ func (m *CreateIndexMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.Owner) > 0 {
-		i -= len(m.Owner)
-		copy(dAtA[i:], m.Owner)
+	if len(m.NewIndex) > 0 {
+		i -= len(m.NewIndex)
+		copy(dAtA[i:], m.NewIndex)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.NewIndex)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Index) > 0 {
+		i -= len(m.Index)
+		copy(dAtA[i:], m.Index)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.Index)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CreateIndexMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CreateIndexMessage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CreateIndexMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Owner) > 0 {
+		i -= len(m.Owner)
+		copy(dAtA[i:], m.Owner)
 		i = encodeVarintPrivate(dAtA, i, uint64(len(m.Owner)))
 		i--
 		dAtA[i] = 0x2a
@@ -3830,6 +4154,98 @@ func (m *FieldUpdate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *UpdateIndexMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateIndexMessage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *UpdateIndexMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Update != nil {
+		{
+			size, err := m.Update.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPrivate(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.CreateIndexMessage != nil {
+		{
+			size, err := m.CreateIndexMessage.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPrivate(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *IndexUpdate) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *IndexUpdate) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *IndexUpdate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Option) > 0 {
+		i -= len(m.Option)
+		copy(dAtA[i:], m.Option)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.Option)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *DeleteFieldMessage) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -3871,6 +4287,54 @@ func (m *DeleteFieldMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *RenameFieldMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RenameFieldMessage) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RenameFieldMessage) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.NewField) > 0 {
+		i -= len(m.NewField)
+		copy(dAtA[i:], m.NewField)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.NewField)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Field) > 0 {
+		i -= len(m.Field)
+		copy(dAtA[i:], m.Field)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.Field)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Index) > 0 {
+		i -= len(m.Index)
+		copy(dAtA[i:], m.Index)
+		i = encodeVarintPrivate(dAtA, i, uint64(len(m.Index)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *DeleteAvailableShardMessage) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -4437,20 +4901,20 @@ func (m *FieldStatus) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		dAtA[i] = 0x18
 	}
 	if len(m.AvailableShards) > 0 {
-		dAtA21 := make([]byte, len(m.AvailableShards)*10)
-		var j20 int
+		dAtA23 := make([]byte, len(m.AvailableShards)*10)
+		var j22 int
 		for _, num := range m.AvailableShards {
 			for num >= 1<<7 {
-				dAtA21[j20] = uint8(uint64(num)&0x7f | 0x80)
+				dAtA23[j22] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
-				j20++
+				j22++
 			}
-			dAtA21[j20] = uint8(num)
-			j20++
+			dAtA23[j22] = uint8(num)
+			j22++
 		}
-		i -= j20
-		copy(dAtA[i:], dAtA21[:j20])
-		i = encodeVarintPrivate(dAtA, i, uint64(j20))
+		i -= j22
+		copy(dAtA[i:], dAtA23[:j22])
+		i = encodeVarintPrivate(dAtA, i, uint64(j22))
 		i--
 		dAtA[i] = 0x12
 	}
@@ -5292,28 +5756,10 @@ func (m *FieldOperation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
 	if len(m.Signed) > 0 {
-		dAtA33 := make([]byte, len(m.Signed)*10)
-		var j32 int
+		dAtA35 := make([]byte, len(m.Signed)*10)
+		var j34 int
 		for _, num1 := range m.Signed {
 			num := uint64(num1)
-			for num >= 1<<7 {
-				dAtA33[j32] = uint8(uint64(num)&0x7f | 0x80)
-				num >>= 7
-				j32++
-			}
-			dAtA33[j32] = uint8(num)
-			j32++
-		}
-		i -= j32
-		copy(dAtA[i:], dAtA33[:j32])
-		i = encodeVarintPrivate(dAtA, i, uint64(j32))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if len(m.Values) > 0 {
-		dAtA35 := make([]byte, len(m.Values)*10)
-		var j34 int
-		for _, num := range m.Values {
 			for num >= 1<<7 {
 				dAtA35[j34] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
@@ -5326,12 +5772,12 @@ func (m *FieldOperation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		copy(dAtA[i:], dAtA35[:j34])
 		i = encodeVarintPrivate(dAtA, i, uint64(j34))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x1a
 	}
-	if len(m.RecordIDs) > 0 {
-		dAtA37 := make([]byte, len(m.RecordIDs)*10)
+	if len(m.Values) > 0 {
+		dAtA37 := make([]byte, len(m.Values)*10)
 		var j36 int
-		for _, num := range m.RecordIDs {
+		for _, num := range m.Values {
 			for num >= 1<<7 {
 				dAtA37[j36] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
@@ -5344,6 +5790,24 @@ func (m *FieldOperation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		copy(dAtA[i:], dAtA37[:j36])
 		i = encodeVarintPrivate(dAtA, i, uint64(j36))
 		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.RecordIDs) > 0 {
+		dAtA39 := make([]byte, len(m.RecordIDs)*10)
+		var j38 int
+		for _, num := range m.RecordIDs {
+			for num >= 1<<7 {
+				dAtA39[j38] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j38++
+			}
+			dAtA39[j38] = uint8(num)
+			j38++
+		}
+		i -= j38
+		copy(dAtA[i:], dAtA39[:j38])
+		i = encodeVarintPrivate(dAtA, i, uint64(j38))
+		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
@@ -5409,20 +5873,20 @@ func (m *ShardIngestOperation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		}
 	}
 	if len(m.ClearRecordIDs) > 0 {
-		dAtA40 := make([]byte, len(m.ClearRecordIDs)*10)
-		var j39 int
+		dAtA42 := make([]byte, len(m.ClearRecordIDs)*10)
+		var j41 int
 		for _, num := range m.ClearRecordIDs {
 			for num >= 1<<7 {
-				dAtA40[j39] = uint8(uint64(num)&0x7f | 0x80)
+				dAtA42[j41] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
-				j39++
+				j41++
 			}
-			dAtA40[j39] = uint8(num)
-			j39++
+			dAtA42[j41] = uint8(num)
+			j41++
 		}
-		i -= j39
-		copy(dAtA[i:], dAtA40[:j39])
-		i = encodeVarintPrivate(dAtA, i, uint64(j39))
+		i -= j41
+		copy(dAtA[i:], dAtA42[:j41])
+		i = encodeVarintPrivate(dAtA, i, uint64(j41))
 		i--
 		dAtA[i] = 0x12
 	}
@@ -5589,6 +6053,18 @@ func (m *IndexMeta) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovPrivate(uint64(l))
 	}
+	l = len(m.RetentionField)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	l = len(m.RetentionPeriod)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	l = len(m.PartitionKey)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -5816,6 +6292,26 @@ func (m *DeleteIndexMessage) Size() (n int) {
 	return n
 }
 
+func (m *RenameIndexMessage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Index)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	l = len(m.NewIndex)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *CreateIndexMessage) Size() (n int) {
 	if m == nil {
 		return 0
@@ -5914,18 +6410,18 @@ func (m *FieldUpdate) Size() (n int) {
 	return n
 }
 
-func (m *DeleteFieldMessage) Size() (n int) {
+func (m *UpdateIndexMessage) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.Index)
-	if l > 0 {
+	if m.CreateIndexMessage != nil {
+		l = m.CreateIndexMessage.Size()
 		n += 1 + l + sovPrivate(uint64(l))
 	}
-	l = len(m.Field)
-	if l > 0 {
+	if m.Update != nil {
+		l = m.Update.Size()
 		n += 1 + l + sovPrivate(uint64(l))
 	}
 	if m.XXX_unrecognized != nil {
@@ -5934,69 +6430,133 @@ func (m *DeleteFieldMessage) Size() (n int) {
 	return n
 }
 
-func (m *DeleteAvailableShardMessage) Size() (n int) {
+func (m *IndexUpdate) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.Index)
+	l = len(m.Option)
 	if l > 0 {
 		n += 1 + l + sovPrivate(uint64(l))
 	}
-	l = len(m.Field)
+	l = len(m.Value)
 	if l > 0 {
 		n += 1 + l + sovPrivate(uint64(l))
 	}
-	if m.ShardID != 0 {
-		n += 1 + sovPrivate(uint64(m.ShardID))
-	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
 	return n
 }
 
-func (m *Field) Size() (n int) {
+func (m *DeleteFieldMessage) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.Name)
+	l = len(m.Index)
 	if l > 0 {
 		n += 1 + l + sovPrivate(uint64(l))
 	}
-	if m.Meta != nil {
-		l = m.Meta.Size()
+	l = len(m.Field)
+	if l > 0 {
 		n += 1 + l + sovPrivate(uint64(l))
 	}
-	if len(m.Views) > 0 {
-		for _, s := range m.Views {
-			l = len(s)
-			n += 1 + l + sovPrivate(uint64(l))
-		}
-	}
-	if m.CreatedAt != 0 {
-		n += 1 + sovPrivate(uint64(m.CreatedAt))
-	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
 	return n
 }
 
-func (m *Schema) Size() (n int) {
+func (m *RenameFieldMessage) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if len(m.Indexes) > 0 {
-		for _, e := range m.Indexes {
-			l = e.Size()
-			n += 1 + l + sovPrivate(uint64(l))
-		}
+	l = len(m.Index)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	l = len(m.NewField)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *DeleteAvailableShardMessage) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Index)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	l = len(m.Field)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	if m.ShardID != 0 {
+		n += 1 + sovPrivate(uint64(m.ShardID))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Field) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	if m.Meta != nil {
+		l = m.Meta.Size()
+		n += 1 + l + sovPrivate(uint64(l))
+	}
+	if len(m.Views) > 0 {
+		for _, s := range m.Views {
+			l = len(s)
+			n += 1 + l + sovPrivate(uint64(l))
+		}
+	}
+	if m.CreatedAt != 0 {
+		n += 1 + sovPrivate(uint64(m.CreatedAt))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Schema) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Indexes) > 0 {
+		for _, e := range m.Indexes {
+			l = e.Size()
+			n += 1 + l + sovPrivate(uint64(l))
+		}
 	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
@@ -6810,6 +7370,102 @@ func (m *IndexMeta) Unmarshal(dAtA []byte) error {
 			}
 			m.Description = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetentionField", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RetentionField = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RetentionPeriod", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RetentionPeriod = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PartitionKey", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PartitionKey = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPrivate(dAtA[iNdEx:])
@@ -8300,7 +8956,7 @@ func (m *DeleteIndexMessage) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CreateIndexMessage) Unmarshal(dAtA []byte) error {
+func (m *RenameIndexMessage) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8323,10 +8979,10 @@ func (m *CreateIndexMessage) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CreateIndexMessage: wiretype end group for non-group")
+			return fmt.Errorf("proto: RenameIndexMessage: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CreateIndexMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RenameIndexMessage: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -8363,62 +9019,7 @@ func (m *CreateIndexMessage) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Meta", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPrivate
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= int(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthPrivate
-			}
-			postIndex := iNdEx + msglen
-			if postIndex < 0 {
-				return ErrInvalidLengthPrivate
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Meta == nil {
-				m.Meta = &IndexMeta{}
-			}
-			if err := m.Meta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
-			}
-			m.CreatedAt = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPrivate
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.CreatedAt |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Owner", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field NewIndex", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -8446,7 +9047,7 @@ func (m *CreateIndexMessage) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Owner = string(dAtA[iNdEx:postIndex])
+			m.NewIndex = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -8470,7 +9071,7 @@ func (m *CreateIndexMessage) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CreateFieldMessage) Unmarshal(dAtA []byte) error {
+func (m *CreateIndexMessage) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8493,10 +9094,10 @@ func (m *CreateFieldMessage) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CreateFieldMessage: wiretype end group for non-group")
+			return fmt.Errorf("proto: CreateIndexMessage: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CreateFieldMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CreateIndexMessage: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -8533,9 +9134,9 @@ func (m *CreateFieldMessage) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Meta", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPrivate
@@ -8545,27 +9146,197 @@ func (m *CreateFieldMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthPrivate
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthPrivate
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Field = string(dAtA[iNdEx:postIndex])
+			if m.Meta == nil {
+				m.Meta = &IndexMeta{}
+			}
+			if err := m.Meta.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Meta", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+			}
+			m.CreatedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CreatedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Owner", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Owner = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPrivate(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CreateFieldMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPrivate
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CreateFieldMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CreateFieldMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Index = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Meta", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -8910,7 +9681,7 @@ func (m *FieldUpdate) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *DeleteFieldMessage) Unmarshal(dAtA []byte) error {
+func (m *UpdateIndexMessage) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8933,17 +9704,17 @@ func (m *DeleteFieldMessage) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: DeleteFieldMessage: wiretype end group for non-group")
+			return fmt.Errorf("proto: UpdateIndexMessage: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: DeleteFieldMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: UpdateIndexMessage: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateIndexMessage", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPrivate
@@ -8953,29 +9724,33 @@ func (m *DeleteFieldMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthPrivate
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthPrivate
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Index = string(dAtA[iNdEx:postIndex])
+			if m.CreateIndexMessage == nil {
+				m.CreateIndexMessage = &CreateIndexMessage{}
+			}
+			if err := m.CreateIndexMessage.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Update", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPrivate
@@ -8985,23 +9760,404 @@ func (m *DeleteFieldMessage) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthPrivate
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthPrivate
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Field = string(dAtA[iNdEx:postIndex])
+			if m.Update == nil {
+				m.Update = &IndexUpdate{}
+			}
+			if err := m.Update.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPrivate(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *IndexUpdate) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPrivate
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: IndexUpdate: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: IndexUpdate: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Option", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Option = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPrivate(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DeleteFieldMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPrivate
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DeleteFieldMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DeleteFieldMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Index = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPrivate(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RenameFieldMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPrivate
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RenameFieldMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RenameFieldMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Index = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Field", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Field = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewField", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPrivate
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPrivate
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NewField = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1663,6 +1663,9 @@ type QueryResult struct {
 	DataFrame               *DataFrame               `protobuf:"bytes,18,opt,name=DataFrame,proto3" json:"DataFrame,omitempty"`
 	ArrowTable              *ArrowTable              `protobuf:"bytes,19,opt,name=ArrowTable,proto3" json:"ArrowTable,omitempty"`
 	ExtractedIDMatrixSorted *ExtractedIDMatrixSorted `protobuf:"bytes,20,opt,name=ExtractedIDMatrixSorted,proto3" json:"ExtractedIDMatrixSorted,omitempty"`
+	DistinctSketch          []byte                   `protobuf:"bytes,21,opt,name=DistinctSketch,proto3" json:"DistinctSketch,omitempty"`
+	Funnel                  []byte                   `protobuf:"bytes,22,opt,name=Funnel,proto3" json:"Funnel,omitempty"`
+	Similar                 []byte                   `protobuf:"bytes,23,opt,name=Similar,proto3" json:"Similar,omitempty"`
 	XXX_NoUnkeyedLiteral    struct{}                 `json:"-"`
 	XXX_unrecognized        []byte                   `json:"-"`
 	XXX_sizecache           int32                    `json:"-"`
@@ -1841,6 +1844,27 @@ func (m *QueryResult) GetExtractedIDMatrixSorted() *ExtractedIDMatrixSorted {
 	return nil
 }
 
+func (m *QueryResult) GetDistinctSketch() []byte {
+	if m != nil {
+		return m.DistinctSketch
+	}
+	return nil
+}
+
+func (m *QueryResult) GetFunnel() []byte {
+	if m != nil {
+		return m.Funnel
+	}
+	return nil
+}
+
+func (m *QueryResult) GetSimilar() []byte {
+	if m != nil {
+		return m.Similar
+	}
+	return nil
+}
+
 type ImportRequest struct {
 	Index                string   `protobuf:"bytes,1,opt,name=Index,proto3" json:"Index,omitempty"`
 	Field                string   `protobuf:"bytes,2,opt,name=Field,proto3" json:"Field,omitempty"`
@@ -2921,123 +2945,125 @@ func init() {
 func init() { proto.RegisterFile("public.proto", fileDescriptor_413a91106d7bcce8) }
 
 var fileDescriptor_413a91106d7bcce8 = []byte{
-	// 1844 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x18, 0x4d, 0x73, 0x23, 0x47,
-	0xd5, 0xa3, 0xd1, 0xe7, 0x93, 0xec, 0xb5, 0x7b, 0x9d, 0xcd, 0x64, 0xe3, 0x38, 0xda, 0x29, 0x2a,
-	0x28, 0x18, 0x36, 0x85, 0xa1, 0x52, 0x54, 0xaa, 0x20, 0x65, 0xaf, 0xbc, 0xac, 0xca, 0xbb, 0xce,
-	0xd2, 0xda, 0x88, 0x4b, 0x2e, 0x63, 0xa9, 0x51, 0xa6, 0x18, 0x69, 0xc4, 0xcc, 0x28, 0xb2, 0x8f,
-	0x1c, 0x28, 0xf8, 0x09, 0x9c, 0xe0, 0xd7, 0x50, 0x70, 0x83, 0x23, 0x47, 0x6a, 0xf9, 0x23, 0xd4,
-	0x7b, 0xaf, 0x7b, 0xa6, 0x47, 0x92, 0xb7, 0xc2, 0x56, 0x6e, 0xfd, 0x3e, 0xfa, 0xf5, 0xfb, 0x7e,
-	0x6f, 0x06, 0x3a, 0x8b, 0xe5, 0x75, 0x14, 0x8e, 0x1f, 0x2f, 0x92, 0x38, 0x8b, 0x45, 0x65, 0x71,
-	0xed, 0xdf, 0x82, 0x2b, 0xe3, 0x95, 0xf0, 0xa0, 0xf1, 0x24, 0x8e, 0x96, 0xb3, 0x79, 0xea, 0x39,
-	0x5d, 0xb7, 0x57, 0x95, 0x06, 0x14, 0x02, 0xaa, 0x97, 0xea, 0x36, 0xf5, 0xdc, 0xae, 0xdb, 0x6b,
-	0x49, 0x3a, 0x23, 0xb7, 0x8c, 0x83, 0x24, 0x9c, 0x4f, 0xbd, 0x6a, 0xd7, 0xe9, 0x75, 0xa4, 0x01,
-	0xc5, 0x21, 0xd4, 0x06, 0xf3, 0x89, 0xba, 0xf1, 0x6a, 0x5d, 0xa7, 0xd7, 0x92, 0x0c, 0x20, 0xf6,
-	0x69, 0xa8, 0xa2, 0x89, 0x57, 0x67, 0x2c, 0x01, 0x7e, 0x0f, 0x5a, 0x32, 0x5e, 0xbd, 0x08, 0xb2,
-	0x24, 0xbc, 0x11, 0xef, 0x43, 0x55, 0xc6, 0x2b, 0x7e, 0xbd, 0x7d, 0xda, 0x78, 0xbc, 0xb8, 0x7e,
-	0x2c, 0xe3, 0x95, 0x24, 0xa4, 0x7f, 0x06, 0xad, 0x61, 0x38, 0x9d, 0xab, 0x09, 0xaa, 0xfa, 0x1e,
-	0xb8, 0x2f, 0x63, 0x64, 0x74, 0x6c, 0x46, 0xc4, 0x21, 0xe9, 0x4a, 0x4d, 0xbd, 0xca, 0x1a, 0xe9,
-	0x4a, 0x4d, 0xfd, 0x9f, 0xc1, 0x9e, 0x8c, 0x57, 0x83, 0x89, 0x9a, 0x67, 0xe1, 0x6f, 0x42, 0x95,
-	0x90, 0x61, 0xf9, 0x8b, 0x55, 0x7e, 0x28, 0x37, 0xb6, 0x52, 0x18, 0xeb, 0x3f, 0x84, 0xfa, 0xa0,
-	0xff, 0x3c, 0x4c, 0x33, 0xb1, 0x0f, 0xee, 0xa0, 0x6f, 0x2e, 0xe0, 0xd1, 0x7f, 0x02, 0x07, 0x17,
-	0x37, 0x59, 0x12, 0x8c, 0x33, 0x35, 0x19, 0xf4, 0xd9, 0x65, 0x62, 0x0f, 0x2a, 0x83, 0x3e, 0xe9,
-	0x57, 0x95, 0x95, 0x41, 0x5f, 0x1c, 0x43, 0x75, 0x14, 0x44, 0x2c, 0xb4, 0x7d, 0x0a, 0xa8, 0x16,
-	0x0b, 0x94, 0x84, 0xf7, 0x7f, 0xef, 0xc0, 0xbb, 0x96, 0x14, 0x76, 0xc8, 0x30, 0x4e, 0x32, 0x35,
-	0x11, 0xe5, 0x07, 0x98, 0xa4, 0x4d, 0x7f, 0x07, 0x05, 0x6d, 0x10, 0xe5, 0x26, 0xbf, 0x78, 0x04,
-	0x75, 0x19, 0xaf, 0x2e, 0x47, 0x46, 0x85, 0x96, 0xf6, 0xcc, 0xe5, 0x48, 0x6a, 0x82, 0xff, 0x1c,
-	0x6a, 0x74, 0xc2, 0x50, 0xa1, 0x9f, 0x8c, 0xfe, 0x0c, 0x88, 0x1f, 0x41, 0x6d, 0x14, 0x44, 0x4b,
-	0xa5, 0x5d, 0xfb, 0x6e, 0xe9, 0xe9, 0x57, 0xc1, 0x75, 0xa4, 0x88, 0x2c, 0x99, 0xcb, 0xff, 0x6a,
-	0x8b, 0xd6, 0xe2, 0x01, 0xd4, 0x29, 0xee, 0xec, 0xc0, 0x96, 0xd4, 0x90, 0xf8, 0xa4, 0x48, 0x3d,
-	0x56, 0x6f, 0xdd, 0x30, 0xa6, 0xe6, 0x19, 0xe9, 0x7f, 0x00, 0x8d, 0x4b, 0x75, 0x4b, 0x11, 0x31,
-	0xf1, 0x72, 0xac, 0x78, 0xfd, 0xd3, 0x81, 0xfb, 0x5b, 0x74, 0x13, 0xc7, 0x26, 0x7a, 0x4e, 0x39,
-	0x0a, 0xcf, 0x76, 0x28, 0x96, 0xe2, 0x51, 0x1e, 0x7b, 0x64, 0x68, 0x23, 0x83, 0x7e, 0xe6, 0xd9,
-	0x8e, 0xce, 0xfb, 0x23, 0x68, 0x9e, 0x0f, 0x07, 0xec, 0x09, 0xb7, 0xeb, 0xf4, 0xdc, 0x67, 0x3b,
-	0x32, 0xc7, 0x88, 0x87, 0xd0, 0x78, 0xb1, 0xcc, 0xd4, 0xcd, 0xa0, 0x4f, 0x55, 0x51, 0x7d, 0xb6,
-	0x23, 0x0d, 0x02, 0x6f, 0xd2, 0xf1, 0x52, 0xdd, 0x72, 0x69, 0xe0, 0x4d, 0x83, 0x11, 0x87, 0x50,
-	0x3d, 0x8f, 0xe3, 0x88, 0xca, 0xa3, 0x89, 0xaf, 0x21, 0x74, 0xde, 0xd0, 0x4e, 0xf7, 0x6f, 0xe0,
-	0xb0, 0x6c, 0x90, 0x4e, 0x34, 0x01, 0x2e, 0xca, 0x73, 0xb4, 0x3c, 0x04, 0xc4, 0x3e, 0x25, 0x5f,
-	0x45, 0xbf, 0x8f, 0xe9, 0xf7, 0x09, 0xd4, 0x49, 0x0c, 0x97, 0xf0, 0x1b, 0x82, 0xa7, 0xd9, 0xce,
-	0x5b, 0xe4, 0xdf, 0x2f, 0x92, 0x41, 0xdf, 0xff, 0xf9, 0xba, 0x2b, 0x29, 0x66, 0xe8, 0xf6, 0xab,
-	0x60, 0xa6, 0xf8, 0x65, 0x49, 0x67, 0xc4, 0xbd, 0xba, 0x5d, 0x70, 0x86, 0xb4, 0x24, 0x9d, 0xfd,
-	0x25, 0xec, 0x95, 0xaf, 0xa3, 0x32, 0x56, 0x12, 0x6c, 0x55, 0x86, 0xe8, 0x79, 0x76, 0x9c, 0xae,
-	0x67, 0x87, 0xb7, 0x79, 0x63, 0x3d, 0x41, 0x7e, 0x01, 0xd5, 0x97, 0x41, 0x98, 0x6c, 0x14, 0xe2,
-	0x3e, 0xfb, 0xcb, 0x25, 0x0d, 0x5d, 0x76, 0x7c, 0xed, 0x49, 0xbc, 0x9c, 0x67, 0xec, 0x30, 0xc9,
-	0x80, 0xff, 0x39, 0xb4, 0xf0, 0x3e, 0xdb, 0x7a, 0xc4, 0xc2, 0x74, 0xde, 0x34, 0xf1, 0x75, 0x84,
-	0x25, 0x3f, 0x91, 0x77, 0xb6, 0x8a, 0xdd, 0xd9, 0xce, 0x01, 0x90, 0x9a, 0xb2, 0x84, 0x63, 0xa8,
-	0x11, 0xa4, 0x4d, 0x2e, 0x44, 0x30, 0xfa, 0x0e, 0x19, 0x1f, 0x60, 0x27, 0xcd, 0x3e, 0xfd, 0x29,
-	0x92, 0x39, 0xe3, 0x50, 0x03, 0xd7, 0x94, 0x58, 0x0c, 0x4d, 0x76, 0x54, 0xbc, 0x2a, 0x04, 0x38,
-	0x96, 0x80, 0xa2, 0x92, 0x2b, 0x76, 0x25, 0x3f, 0xe0, 0x5e, 0x90, 0xbb, 0x41, 0x43, 0xe2, 0x43,
-	0xf3, 0x4a, 0x95, 0xec, 0xa4, 0x16, 0x41, 0xef, 0x9b, 0x07, 0xff, 0xe0, 0x00, 0xfc, 0x32, 0x89,
-	0x97, 0x0b, 0xf2, 0x91, 0xf0, 0xa1, 0x46, 0x90, 0x36, 0xaa, 0x83, 0xfc, 0x46, 0x21, 0xc9, 0xa4,
-	0xed, 0xde, 0xc5, 0x28, 0x9c, 0x4d, 0xa7, 0x5c, 0x3f, 0x12, 0x8f, 0xe2, 0x04, 0xa0, 0xaf, 0xc6,
-	0xe1, 0x2c, 0x88, 0x90, 0x50, 0x2d, 0xea, 0x4f, 0x63, 0xa5, 0x45, 0xf6, 0xff, 0xea, 0x40, 0x73,
-	0x14, 0x44, 0xb9, 0xac, 0x51, 0x10, 0x69, 0xcf, 0xe0, 0xb1, 0xfc, 0xa6, 0x6b, 0xde, 0x7c, 0x08,
-	0xcd, 0xa7, 0x51, 0x1c, 0x64, 0xc8, 0x8c, 0x0f, 0x3b, 0x32, 0x87, 0xad, 0xd7, 0x91, 0xfa, 0x86,
-	0xd7, 0x91, 0xd9, 0x87, 0xce, 0xab, 0x70, 0xa6, 0xd2, 0x2c, 0x98, 0x2d, 0x90, 0x9d, 0xc7, 0x5c,
-	0x09, 0x87, 0x9e, 0x6a, 0xe8, 0x2b, 0xdb, 0x83, 0x87, 0xd8, 0xe1, 0x38, 0x88, 0x94, 0x51, 0x92,
-	0x00, 0x71, 0x0c, 0x70, 0xa5, 0x56, 0x23, 0x95, 0xa4, 0x61, 0x3c, 0x27, 0x35, 0x9b, 0xd2, 0xc2,
-	0x60, 0xe8, 0x46, 0x41, 0x74, 0x76, 0x9d, 0xea, 0xa1, 0xab, 0x21, 0x8d, 0xc7, 0xc1, 0x57, 0xa3,
-	0x3b, 0x1a, 0xf2, 0x3f, 0x87, 0x83, 0x7e, 0x98, 0x66, 0xe1, 0x7c, 0x9c, 0xe5, 0xfa, 0x69, 0x66,
-	0xec, 0x06, 0xba, 0x0b, 0x33, 0x94, 0x97, 0x74, 0xa5, 0x28, 0x69, 0xff, 0x6f, 0x0e, 0x74, 0x7e,
-	0xb5, 0x54, 0xc9, 0xad, 0x54, 0xbf, 0x5b, 0xaa, 0x34, 0x43, 0xbd, 0x09, 0x36, 0x89, 0x46, 0x00,
-	0x8a, 0x1c, 0x7e, 0x1d, 0x24, 0x13, 0xae, 0xd0, 0xaa, 0xd4, 0x10, 0xa5, 0x9a, 0x9a, 0xc5, 0x99,
-	0x32, 0x7a, 0x31, 0x24, 0x4e, 0xa0, 0x73, 0x31, 0xbb, 0x56, 0x93, 0x89, 0x9a, 0xf4, 0x83, 0x2c,
-	0xf0, 0x9a, 0xe5, 0x91, 0x5f, 0x22, 0x8a, 0xef, 0xc1, 0xee, 0xcb, 0x44, 0xbd, 0x4a, 0x82, 0x79,
-	0x1a, 0x05, 0x99, 0x9a, 0x78, 0x2d, 0x92, 0x55, 0x46, 0x8a, 0x23, 0x68, 0xbd, 0x08, 0x6e, 0x5e,
-	0xa8, 0x59, 0x9c, 0xdc, 0x7a, 0x40, 0x4e, 0x2d, 0x10, 0xfe, 0x73, 0xd8, 0xd5, 0x66, 0xa4, 0x8b,
-	0x78, 0x9e, 0x2a, 0x4c, 0x9b, 0x8b, 0x24, 0xd1, 0x56, 0xe0, 0x51, 0x7c, 0x0c, 0x0d, 0xa9, 0xd2,
-	0x65, 0x94, 0x99, 0x36, 0x73, 0x0f, 0xd5, 0x31, 0xb7, 0x96, 0x51, 0x26, 0x0d, 0xdd, 0xff, 0x4b,
-	0x03, 0xda, 0x16, 0x21, 0x6f, 0x7c, 0xd8, 0xbc, 0x77, 0xb9, 0xf1, 0xe1, 0x22, 0x22, 0xe3, 0xd5,
-	0xc6, 0x8e, 0x82, 0xc5, 0xda, 0x01, 0xe7, 0x4a, 0x17, 0x84, 0x73, 0x55, 0xf4, 0x06, 0x77, 0x7b,
-	0x6f, 0xc0, 0xbd, 0xec, 0xeb, 0x60, 0x3e, 0x55, 0x13, 0x0a, 0x7a, 0x53, 0x1a, 0x50, 0xf4, 0x8a,
-	0x32, 0x20, 0xff, 0xea, 0x1a, 0x34, 0x38, 0x59, 0x14, 0x09, 0x97, 0x3c, 0xce, 0xbe, 0x06, 0xc7,
-	0x87, 0x21, 0xf1, 0x29, 0xec, 0x7d, 0x11, 0x4d, 0x8a, 0x9a, 0x4e, 0x75, 0x24, 0xf6, 0x50, 0x4e,
-	0x81, 0x96, 0x6b, 0x5c, 0xe2, 0xb3, 0xf5, 0x55, 0x8a, 0x62, 0xd2, 0x3e, 0x15, 0xda, 0x4e, 0x8b,
-	0x22, 0xd7, 0x97, 0xae, 0x13, 0x6b, 0x93, 0xa3, 0x40, 0xb5, 0x4f, 0x77, 0xf1, 0x5a, 0x8e, 0x94,
-	0xd6, 0xa6, 0xf7, 0xd8, 0x6e, 0xa3, 0x5e, 0x9b, 0xb8, 0xf7, 0x8c, 0x87, 0x18, 0x2b, 0xed, 0x46,
-	0x7b, 0x62, 0xf5, 0x6d, 0xaf, 0x53, 0x08, 0xcf, 0x91, 0xd2, 0xea, 0xeb, 0x5b, 0x37, 0xab, 0xdd,
-	0xff, 0x73, 0xb3, 0xfa, 0x6c, 0x7d, 0xc0, 0x79, 0x7b, 0x85, 0x2b, 0xca, 0x14, 0xb9, 0x3e, 0x0a,
-	0x4f, 0xac, 0xf5, 0xd7, 0xbb, 0x57, 0x68, 0x9b, 0x23, 0xa5, 0xb5, 0x1e, 0xff, 0x18, 0xda, 0x76,
-	0xa0, 0xf6, 0x89, 0xfd, 0x5e, 0x39, 0x50, 0xa9, 0xb4, 0x79, 0xd0, 0xc0, 0x8d, 0xf2, 0xf7, 0x0e,
-	0x0a, 0x03, 0x37, 0x88, 0x72, 0x4b, 0xbb, 0x38, 0x81, 0x16, 0x96, 0xe1, 0xd3, 0x04, 0x7b, 0x83,
-	0x28, 0x94, 0xcc, 0x91, 0xb2, 0xa0, 0x63, 0xbc, 0xce, 0x92, 0x24, 0x5e, 0xb1, 0x27, 0xee, 0x17,
-	0xf1, 0x2a, 0xb0, 0xd2, 0xe2, 0x10, 0x5f, 0xde, 0xb9, 0xf7, 0x7a, 0x87, 0x74, 0xf9, 0xfd, 0xad,
-	0x81, 0x60, 0x16, 0x79, 0xd7, 0x5d, 0xff, 0xef, 0x15, 0xd8, 0x1d, 0xcc, 0x16, 0x71, 0x92, 0x59,
-	0x7d, 0x8b, 0xbf, 0x4a, 0x9c, 0xad, 0x5f, 0x25, 0x95, 0xb5, 0xb1, 0x49, 0xfd, 0x8b, 0x1a, 0x70,
-	0x55, 0x32, 0x60, 0xd5, 0x50, 0xb5, 0x54, 0x43, 0x47, 0xd0, 0xe2, 0xad, 0x03, 0x49, 0x35, 0x22,
-	0x15, 0x08, 0xfe, 0x4e, 0x5a, 0xd1, 0x56, 0xd9, 0xa0, 0x6e, 0x6b, 0x40, 0xec, 0xf5, 0xcc, 0x46,
-	0xc4, 0x26, 0x11, 0x2d, 0x0c, 0xd2, 0xf3, 0x20, 0xa4, 0x5e, 0xbd, 0xeb, 0xf6, 0x5c, 0x69, 0x61,
-	0xc4, 0x47, 0xb0, 0x47, 0x46, 0x3c, 0x49, 0x14, 0x36, 0xc0, 0xb3, 0x8c, 0x6a, 0xd0, 0x95, 0x6b,
-	0x58, 0xe4, 0x23, 0xb3, 0x0a, 0x3e, 0xee, 0x8e, 0x6b, 0x58, 0x1a, 0x9b, 0x91, 0x0a, 0x12, 0xaa,
-	0xb2, 0xa6, 0x64, 0xc0, 0xff, 0x77, 0x05, 0x04, 0x7b, 0x92, 0x37, 0xc4, 0xef, 0xcc, 0x9d, 0x6f,
-	0x76, 0x5b, 0xd9, 0x39, 0x8d, 0x0d, 0xe7, 0x14, 0x33, 0x8c, 0x1d, 0x63, 0x66, 0x58, 0x17, 0xda,
-	0x66, 0xaa, 0x23, 0x11, 0xbd, 0xea, 0x48, 0x1b, 0x85, 0xe3, 0x7b, 0x98, 0xe1, 0x87, 0xaa, 0x66,
-	0x69, 0x91, 0xec, 0x12, 0x6e, 0x8b, 0x6b, 0xe1, 0x5b, 0xba, 0xb6, 0xfd, 0x66, 0xd7, 0x76, 0x6c,
-	0xd7, 0xfe, 0xd1, 0x81, 0xce, 0x59, 0x16, 0xcf, 0xc2, 0xb1, 0x54, 0xe3, 0x38, 0x99, 0xdc, 0xed,
-	0x54, 0x76, 0x5f, 0xc5, 0x76, 0x5f, 0x0f, 0xdc, 0xc1, 0x37, 0x89, 0x9e, 0x19, 0x0f, 0x68, 0x55,
-	0xdb, 0x88, 0x92, 0x44, 0x16, 0xf1, 0x08, 0x2a, 0x83, 0x84, 0x72, 0xb6, 0x7d, 0x7a, 0x50, 0x30,
-	0x1a, 0x9e, 0xca, 0x20, 0xf1, 0x7f, 0x08, 0x87, 0xac, 0x88, 0x21, 0xe9, 0x21, 0x79, 0x08, 0xb5,
-	0x8b, 0x24, 0x89, 0xcd, 0x98, 0x64, 0x00, 0xbf, 0x45, 0xf2, 0xb9, 0x8b, 0xc1, 0x78, 0x9b, 0x9c,
-	0xd8, 0xf6, 0x4b, 0xa1, 0x0b, 0xed, 0xab, 0x38, 0xfb, 0x75, 0x12, 0x66, 0xd4, 0x3c, 0x78, 0xd8,
-	0xd9, 0x28, 0xff, 0x63, 0x78, 0x67, 0xed, 0xe5, 0x62, 0x9a, 0x63, 0x1a, 0xb9, 0xc5, 0x67, 0xf9,
-	0x10, 0xee, 0xe7, 0xac, 0x83, 0xfe, 0x5b, 0xe9, 0xb8, 0x29, 0xf4, 0x07, 0x96, 0xe5, 0x24, 0x54,
-	0x3f, 0xbf, 0xc5, 0x1a, 0xff, 0x1c, 0x3c, 0xed, 0x4d, 0xfe, 0x2f, 0xa2, 0x35, 0x18, 0x85, 0x6a,
-	0x75, 0xd7, 0xc7, 0x13, 0xad, 0x42, 0x15, 0x5a, 0xec, 0xe8, 0xec, 0xff, 0xa9, 0x02, 0x87, 0xdb,
-	0x84, 0x14, 0x09, 0xe5, 0x58, 0x09, 0x25, 0x4e, 0xa1, 0xf6, 0x4d, 0xa8, 0x56, 0x66, 0x7f, 0x39,
-	0xb2, 0x82, 0xbd, 0xa1, 0x83, 0x64, 0x56, 0x2c, 0xa4, 0xb3, 0x71, 0x66, 0xb6, 0xcd, 0x96, 0xd4,
-	0x10, 0xbe, 0x70, 0x1e, 0xc5, 0xe3, 0xdf, 0xf2, 0x77, 0xac, 0x64, 0x60, 0x4b, 0x61, 0xd4, 0xbe,
-	0x65, 0x61, 0xd4, 0xb7, 0x16, 0x46, 0x0f, 0xee, 0x7d, 0xb9, 0x98, 0x04, 0x99, 0xba, 0xb8, 0x09,
-	0xd3, 0x4c, 0xcd, 0xc7, 0xca, 0x6b, 0x90, 0x45, 0xeb, 0x68, 0xdc, 0xa8, 0x77, 0xb5, 0x15, 0x4c,
-	0xba, 0xe3, 0x93, 0x47, 0x40, 0x15, 0xcd, 0x33, 0x4b, 0x2c, 0xb9, 0x3b, 0xf7, 0x96, 0x4b, 0xbe,
-	0xd5, 0xde, 0xda, 0x07, 0x77, 0xa8, 0x32, 0xbd, 0x48, 0xe3, 0x11, 0x5b, 0x03, 0x91, 0xb8, 0x1c,
-	0x53, 0xbd, 0xb3, 0x96, 0x70, 0xfe, 0x57, 0xf0, 0x5e, 0xc9, 0xa5, 0x54, 0x8d, 0x26, 0x2c, 0xc5,
-	0xba, 0xeb, 0x94, 0xd6, 0xdd, 0xef, 0x43, 0x6d, 0x64, 0x05, 0xe6, 0x80, 0x67, 0xbc, 0x65, 0x8c,
-	0x64, 0xba, 0x3f, 0x2c, 0xcd, 0x78, 0xec, 0x91, 0x67, 0xd3, 0x69, 0xa2, 0xa6, 0x41, 0x66, 0x92,
-	0xa5, 0x40, 0x88, 0x8f, 0xa0, 0x4e, 0xcc, 0x46, 0xec, 0xfa, 0xd2, 0xa6, 0xa9, 0xfe, 0x87, 0xd6,
-	0x00, 0xcf, 0xd3, 0xcc, 0xb1, 0xd2, 0xac, 0x6b, 0x0f, 0xed, 0x6d, 0x1c, 0xe7, 0xfb, 0xff, 0x78,
-	0x7d, 0xec, 0xfc, 0xeb, 0xf5, 0xb1, 0xf3, 0x9f, 0xd7, 0xc7, 0xce, 0x9f, 0xff, 0x7b, 0xbc, 0x73,
-	0x5d, 0xa7, 0xff, 0x87, 0x3f, 0xf9, 0x5f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x49, 0x89, 0x63, 0x75,
-	0x4f, 0x14, 0x00, 0x00,
+	// 1878 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x58, 0x4f, 0x73, 0xe3, 0x48,
+	0x15, 0x8f, 0x24, 0xff, 0x7d, 0x76, 0x32, 0x49, 0x4f, 0x26, 0xa3, 0x9d, 0xcd, 0x66, 0x3d, 0x2a,
+	0x6a, 0xf1, 0x32, 0x30, 0x5b, 0x04, 0x6a, 0x8b, 0xda, 0x2a, 0xd8, 0x8a, 0xe3, 0x0c, 0xe3, 0xca,
+	0x4c, 0x76, 0x68, 0xcf, 0x9a, 0xcb, 0x5e, 0x14, 0xbb, 0xf1, 0xa8, 0x56, 0xb6, 0x8c, 0x24, 0xaf,
+	0x93, 0x23, 0x07, 0x0a, 0x3e, 0x02, 0x37, 0x3e, 0x0d, 0x05, 0x37, 0x38, 0x72, 0xa4, 0x86, 0x2f,
+	0xc1, 0x91, 0x7a, 0xfd, 0xba, 0xa5, 0x96, 0xac, 0x4c, 0x2d, 0x5b, 0xdc, 0xfa, 0xfd, 0xe9, 0xd7,
+	0xaf, 0x7f, 0xef, 0x4f, 0x3f, 0x09, 0xba, 0xab, 0xf5, 0x75, 0x18, 0x4c, 0x9f, 0xae, 0xe2, 0x28,
+	0x8d, 0x98, 0xbd, 0xba, 0xf6, 0x6e, 0xc1, 0xe1, 0xd1, 0x86, 0xb9, 0xd0, 0x3c, 0x8f, 0xc2, 0xf5,
+	0x62, 0x99, 0xb8, 0x56, 0xcf, 0xe9, 0xd7, 0xb8, 0x26, 0x19, 0x83, 0xda, 0xa5, 0xb8, 0x4d, 0x5c,
+	0xa7, 0xe7, 0xf4, 0xdb, 0x5c, 0xae, 0x51, 0x9b, 0x47, 0x7e, 0x1c, 0x2c, 0xe7, 0x6e, 0xad, 0x67,
+	0xf5, 0xbb, 0x5c, 0x93, 0xec, 0x10, 0xea, 0xa3, 0xe5, 0x4c, 0xdc, 0xb8, 0xf5, 0x9e, 0xd5, 0x6f,
+	0x73, 0x22, 0x90, 0xfb, 0x2c, 0x10, 0xe1, 0xcc, 0x6d, 0x10, 0x57, 0x12, 0x5e, 0x1f, 0xda, 0x3c,
+	0xda, 0xbc, 0xf4, 0xd3, 0x38, 0xb8, 0x61, 0xef, 0x43, 0x8d, 0x47, 0x1b, 0x3a, 0xbd, 0x73, 0xda,
+	0x7c, 0xba, 0xba, 0x7e, 0xca, 0xa3, 0x0d, 0x97, 0x4c, 0xef, 0x0c, 0xda, 0xe3, 0x60, 0xbe, 0x14,
+	0x33, 0x74, 0xf5, 0x3d, 0x70, 0x5e, 0x45, 0xa8, 0x68, 0x99, 0x8a, 0xc8, 0x43, 0xd1, 0x95, 0x98,
+	0xbb, 0x76, 0x49, 0x74, 0x25, 0xe6, 0xde, 0xcf, 0x60, 0x8f, 0x47, 0x9b, 0xd1, 0x4c, 0x2c, 0xd3,
+	0xe0, 0x37, 0x81, 0x88, 0xe5, 0xc5, 0xb2, 0x13, 0x6b, 0x74, 0x50, 0x76, 0x59, 0x3b, 0xbf, 0xac,
+	0xf7, 0x08, 0x1a, 0xa3, 0xe1, 0x8b, 0x20, 0x49, 0xd9, 0x3e, 0x38, 0xa3, 0xa1, 0xde, 0x80, 0x4b,
+	0xef, 0x1c, 0x0e, 0x2e, 0x6e, 0xd2, 0xd8, 0x9f, 0xa6, 0x62, 0x36, 0x1a, 0x12, 0x64, 0x6c, 0x0f,
+	0xec, 0xd1, 0x50, 0xfa, 0x57, 0xe3, 0xf6, 0x68, 0xc8, 0x4e, 0xa0, 0x36, 0xf1, 0x43, 0x32, 0xda,
+	0x39, 0x05, 0x74, 0x8b, 0x0c, 0x72, 0xc9, 0xf7, 0x7e, 0x67, 0xc1, 0x43, 0xc3, 0x0a, 0x01, 0x32,
+	0x8e, 0xe2, 0x54, 0xcc, 0x58, 0xf1, 0x00, 0x12, 0xa9, 0xab, 0x3f, 0x40, 0x43, 0x5b, 0x42, 0xbe,
+	0xad, 0xcf, 0x1e, 0x43, 0x83, 0x47, 0x9b, 0xcb, 0x89, 0x76, 0xa1, 0xad, 0x90, 0xb9, 0x9c, 0x70,
+	0x25, 0xf0, 0x5e, 0x40, 0x5d, 0xae, 0x30, 0x54, 0x88, 0x93, 0xf6, 0x9f, 0x08, 0xf6, 0x23, 0xa8,
+	0x4f, 0xfc, 0x70, 0x2d, 0x14, 0xb4, 0x0f, 0x0b, 0x47, 0xbf, 0xf6, 0xaf, 0x43, 0x21, 0xc5, 0x9c,
+	0xb4, 0xbc, 0xaf, 0x2a, 0xbc, 0x66, 0x47, 0xd0, 0x90, 0x71, 0x27, 0x00, 0xdb, 0x5c, 0x51, 0xec,
+	0x93, 0x3c, 0xf5, 0xc8, 0xbd, 0xf2, 0xc5, 0x48, 0x9a, 0x65, 0xa4, 0xf7, 0x01, 0x34, 0x2f, 0xc5,
+	0xad, 0x8c, 0x88, 0x8e, 0x97, 0x65, 0xc4, 0xeb, 0xef, 0x16, 0xdc, 0xaf, 0xf0, 0x8d, 0x9d, 0xe8,
+	0xe8, 0x59, 0xc5, 0x28, 0x3c, 0xdf, 0x91, 0xb1, 0x64, 0x8f, 0xb3, 0xd8, 0xa3, 0x42, 0x07, 0x15,
+	0xd4, 0x31, 0xcf, 0x77, 0x54, 0xde, 0x1f, 0x43, 0x6b, 0x30, 0x1e, 0x11, 0x12, 0x4e, 0xcf, 0xea,
+	0x3b, 0xcf, 0x77, 0x78, 0xc6, 0x61, 0x8f, 0xa0, 0xf9, 0x72, 0x9d, 0x8a, 0x9b, 0xd1, 0x50, 0x56,
+	0x45, 0xed, 0xf9, 0x0e, 0xd7, 0x0c, 0xdc, 0x29, 0x97, 0x97, 0xe2, 0x96, 0x4a, 0x03, 0x77, 0x6a,
+	0x0e, 0x3b, 0x84, 0xda, 0x20, 0x8a, 0x42, 0x59, 0x1e, 0x2d, 0x3c, 0x0d, 0xa9, 0x41, 0x53, 0x81,
+	0xee, 0xdd, 0xc0, 0x61, 0xf1, 0x42, 0x2a, 0xd1, 0x18, 0x38, 0x68, 0xcf, 0x52, 0xf6, 0x90, 0x60,
+	0xfb, 0x32, 0xf9, 0x6c, 0x75, 0x3e, 0xa6, 0xdf, 0x27, 0xd0, 0x90, 0x66, 0xa8, 0x84, 0xdf, 0x11,
+	0x3c, 0xa5, 0x36, 0x68, 0x4b, 0x7c, 0xbf, 0x88, 0x47, 0x43, 0xef, 0xe7, 0x65, 0x28, 0x65, 0xcc,
+	0x10, 0xf6, 0x2b, 0x7f, 0x21, 0xe8, 0x64, 0x2e, 0xd7, 0xc8, 0x7b, 0x7d, 0xbb, 0xa2, 0x0c, 0x69,
+	0x73, 0xb9, 0xf6, 0xd6, 0xb0, 0x57, 0xdc, 0x8e, 0xce, 0x18, 0x49, 0x50, 0xe9, 0x8c, 0x94, 0x67,
+	0xd9, 0x71, 0x5a, 0xce, 0x0e, 0x77, 0x7b, 0x47, 0x39, 0x41, 0x7e, 0x01, 0xb5, 0x57, 0x7e, 0x10,
+	0x6f, 0x15, 0xe2, 0x3e, 0xe1, 0xe5, 0x48, 0x0f, 0x1d, 0x02, 0xbe, 0x7e, 0x1e, 0xad, 0x97, 0x29,
+	0x01, 0xc6, 0x89, 0xf0, 0x3e, 0x87, 0x36, 0xee, 0xa7, 0xbb, 0x1e, 0x93, 0x31, 0x95, 0x37, 0x2d,
+	0x3c, 0x1d, 0x69, 0x4e, 0x47, 0x64, 0x9d, 0xcd, 0x36, 0x3b, 0xdb, 0x00, 0x00, 0xa5, 0x09, 0x59,
+	0x38, 0x81, 0xba, 0xa4, 0xd4, 0x95, 0x73, 0x13, 0xc4, 0xbe, 0xc3, 0xc6, 0x07, 0xd8, 0x49, 0xd3,
+	0x4f, 0x7f, 0x8a, 0x62, 0xca, 0x38, 0xf4, 0xc0, 0xd1, 0x25, 0x16, 0x41, 0x8b, 0x80, 0x8a, 0x36,
+	0xb9, 0x01, 0xcb, 0x30, 0x90, 0x57, 0xb2, 0x6d, 0x56, 0xf2, 0x11, 0xf5, 0x82, 0x0c, 0x06, 0x45,
+	0xb1, 0x0f, 0xf5, 0x29, 0xb5, 0x9e, 0xa5, 0x5b, 0x84, 0x3c, 0x5f, 0x1f, 0xf8, 0x7b, 0x0b, 0xe0,
+	0x97, 0x71, 0xb4, 0x5e, 0x49, 0x8c, 0x98, 0x07, 0x75, 0x49, 0xa9, 0x4b, 0x75, 0x51, 0x5f, 0x3b,
+	0xc4, 0x49, 0x54, 0x8d, 0x2e, 0x46, 0xe1, 0x6c, 0x3e, 0xa7, 0xfa, 0xe1, 0xb8, 0x64, 0x4f, 0x00,
+	0x86, 0x62, 0x1a, 0x2c, 0xfc, 0x10, 0x05, 0xb5, 0xbc, 0xfe, 0x14, 0x97, 0x1b, 0x62, 0xef, 0xcf,
+	0x16, 0xb4, 0x26, 0x7e, 0x98, 0xd9, 0x9a, 0xf8, 0xa1, 0x42, 0x06, 0x97, 0xc5, 0x33, 0x1d, 0x7d,
+	0xe6, 0x23, 0x68, 0x3d, 0x0b, 0x23, 0x3f, 0x45, 0x65, 0x3c, 0xd8, 0xe2, 0x19, 0x6d, 0x9c, 0x8e,
+	0xd2, 0x77, 0x9c, 0x8e, 0xca, 0x1e, 0x74, 0x5f, 0x07, 0x0b, 0x91, 0xa4, 0xfe, 0x62, 0x85, 0xea,
+	0xf4, 0xcc, 0x15, 0x78, 0x88, 0x54, 0x53, 0x6d, 0xa9, 0x0e, 0x1e, 0x72, 0xc7, 0x53, 0x3f, 0x14,
+	0xda, 0x49, 0x49, 0xb0, 0x13, 0x80, 0x2b, 0xb1, 0x99, 0x88, 0x38, 0x09, 0xa2, 0xa5, 0x74, 0xb3,
+	0xc5, 0x0d, 0x0e, 0x86, 0x6e, 0xe2, 0x87, 0x67, 0xd7, 0x89, 0x7a, 0x74, 0x15, 0xa5, 0xf8, 0xf8,
+	0xf0, 0xd5, 0xe5, 0x1e, 0x45, 0x79, 0x9f, 0xc3, 0xc1, 0x30, 0x48, 0xd2, 0x60, 0x39, 0x4d, 0x33,
+	0xff, 0xd8, 0x51, 0xd6, 0x0d, 0x54, 0x17, 0x26, 0x2a, 0x2b, 0x69, 0x3b, 0x2f, 0x69, 0xef, 0x2f,
+	0x16, 0x74, 0x7f, 0xb5, 0x16, 0xf1, 0x2d, 0x17, 0xbf, 0x5d, 0x8b, 0x24, 0x45, 0xbf, 0x25, 0xad,
+	0x13, 0x4d, 0x12, 0x68, 0x72, 0xfc, 0xc6, 0x8f, 0x67, 0x54, 0xa1, 0x35, 0xae, 0x28, 0xe4, 0x73,
+	0xb1, 0x88, 0x52, 0xa1, 0xfd, 0x22, 0x8a, 0x3d, 0x81, 0xee, 0xc5, 0xe2, 0x5a, 0xcc, 0x66, 0x62,
+	0x36, 0xf4, 0x53, 0xdf, 0x6d, 0x15, 0x9f, 0xfc, 0x82, 0x90, 0x7d, 0x0f, 0x76, 0x5f, 0xc5, 0xe2,
+	0x75, 0xec, 0x2f, 0x93, 0xd0, 0x4f, 0xc5, 0xcc, 0x6d, 0x4b, 0x5b, 0x45, 0x26, 0x3b, 0x86, 0xf6,
+	0x4b, 0xff, 0xe6, 0xa5, 0x58, 0x44, 0xf1, 0xad, 0x0b, 0x12, 0xd4, 0x9c, 0xe1, 0xbd, 0x80, 0x5d,
+	0x75, 0x8d, 0x64, 0x15, 0x2d, 0x13, 0x81, 0x69, 0x73, 0x11, 0xc7, 0xea, 0x16, 0xb8, 0x64, 0x1f,
+	0x43, 0x93, 0x8b, 0x64, 0x1d, 0xa6, 0xba, 0xcd, 0xdc, 0x43, 0x77, 0xf4, 0xae, 0x75, 0x98, 0x72,
+	0x2d, 0xf7, 0xfe, 0xd3, 0x84, 0x8e, 0x21, 0xc8, 0x1a, 0x1f, 0x36, 0xef, 0x5d, 0x6a, 0x7c, 0x38,
+	0x88, 0xf0, 0x68, 0xb3, 0x35, 0xa3, 0x60, 0xb1, 0x76, 0xc1, 0xba, 0x52, 0x05, 0x61, 0x5d, 0xe5,
+	0xbd, 0xc1, 0xa9, 0xee, 0x0d, 0x38, 0x97, 0xbd, 0xf1, 0x97, 0x73, 0x31, 0x93, 0x41, 0x6f, 0x71,
+	0x4d, 0xb2, 0x7e, 0x5e, 0x06, 0x12, 0x5f, 0x55, 0x83, 0x9a, 0xc7, 0x33, 0xa9, 0x2a, 0x79, 0x7c,
+	0xfb, 0x9a, 0x14, 0x1f, 0xa2, 0xd8, 0xa7, 0xb0, 0xf7, 0x45, 0x38, 0xcb, 0x6b, 0x3a, 0x51, 0x91,
+	0xd8, 0x43, 0x3b, 0x39, 0x9b, 0x97, 0xb4, 0xd8, 0x67, 0xe5, 0x51, 0x4a, 0xc6, 0xa4, 0x73, 0xca,
+	0xd4, 0x3d, 0x0d, 0x09, 0x2f, 0x69, 0xb2, 0x27, 0xc6, 0x24, 0x27, 0x03, 0xd5, 0x39, 0xdd, 0xc5,
+	0x6d, 0x19, 0x93, 0xe7, 0x72, 0xf6, 0xd4, 0x6c, 0xa3, 0x6e, 0xa7, 0x67, 0x69, 0xe7, 0x72, 0x2e,
+	0x37, 0x34, 0xd0, 0x78, 0xd6, 0xb7, 0xdd, 0x6e, 0x6e, 0x3c, 0x63, 0xf2, 0x5c, 0x5e, 0x3d, 0x59,
+	0xed, 0xfe, 0x8f, 0x93, 0xd5, 0x67, 0xe5, 0x07, 0xce, 0xdd, 0xcb, 0xa1, 0x28, 0x4a, 0x78, 0x49,
+	0x93, 0x3d, 0x31, 0xc6, 0x5f, 0xf7, 0x5e, 0xee, 0x6d, 0xc6, 0xe4, 0xb9, 0x9c, 0xfd, 0x18, 0x3a,
+	0x66, 0xa0, 0xf6, 0x7b, 0x96, 0xce, 0x51, 0x83, 0xcd, 0x4d, 0x1d, 0x76, 0x5e, 0x51, 0xfe, 0xee,
+	0x41, 0x7e, 0xc1, 0x2d, 0x21, 0xdf, 0xd6, 0x47, 0x27, 0xb1, 0x0c, 0x9f, 0xc5, 0xd8, 0x1b, 0x58,
+	0xee, 0x64, 0xc6, 0xe4, 0xb9, 0x1c, 0xe3, 0x75, 0x16, 0xc7, 0xd1, 0x86, 0x90, 0xb8, 0x9f, 0xc7,
+	0x2b, 0xe7, 0x72, 0x43, 0x83, 0x7d, 0x79, 0xe7, 0xdc, 0xeb, 0x1e, 0xca, 0xcd, 0xef, 0x57, 0x06,
+	0x82, 0x54, 0xf8, 0x5d, 0x7b, 0xd9, 0x47, 0xb0, 0xa7, 0x2f, 0x32, 0xfe, 0x5a, 0xa4, 0xd3, 0x37,
+	0xee, 0x03, 0xd9, 0x2f, 0x4b, 0x5c, 0x39, 0x90, 0xae, 0x97, 0x4b, 0x11, 0xba, 0x47, 0xd4, 0x4f,
+	0x89, 0xc2, 0x9a, 0x1b, 0x07, 0x8b, 0x20, 0xf4, 0x63, 0xf7, 0xa1, 0x14, 0x68, 0xd2, 0xfb, 0xab,
+	0x0d, 0xbb, 0xa3, 0xc5, 0x2a, 0x8a, 0x53, 0xa3, 0x23, 0xd2, 0xf7, 0x8e, 0x55, 0xf9, 0xbd, 0x63,
+	0x97, 0x1e, 0x64, 0xd9, 0x19, 0x65, 0x6b, 0xaf, 0x71, 0x22, 0x8c, 0xea, 0xac, 0x15, 0xaa, 0xf3,
+	0x18, 0xda, 0x34, 0xcf, 0xa0, 0xa8, 0x2e, 0x45, 0x39, 0x83, 0xbe, 0xc0, 0x36, 0x72, 0x5e, 0x6d,
+	0xca, 0x3e, 0xae, 0x49, 0x7c, 0x45, 0x48, 0x4d, 0x0a, 0x5b, 0x52, 0x68, 0x70, 0x50, 0x9e, 0x85,
+	0x37, 0x71, 0x1b, 0x3d, 0xa7, 0xef, 0x70, 0x83, 0x83, 0xe8, 0xc9, 0x4b, 0x9c, 0xc7, 0x02, 0x5b,
+	0xeb, 0x59, 0x2a, 0xab, 0xdb, 0xe1, 0x25, 0x2e, 0xea, 0xc9, 0x6b, 0xe5, 0x7a, 0xd4, 0x77, 0x4b,
+	0x5c, 0xf9, 0x20, 0x87, 0xc2, 0x8f, 0x65, 0xfd, 0xb6, 0x38, 0x11, 0xde, 0x3f, 0x6d, 0x60, 0x84,
+	0x24, 0xcd, 0x9e, 0xff, 0x37, 0x38, 0xdf, 0x0d, 0x5b, 0x11, 0x9c, 0xe6, 0x16, 0x38, 0xf9, 0xeb,
+	0x48, 0xc0, 0x28, 0x8a, 0xf5, 0xa0, 0xa3, 0xe7, 0x85, 0xb5, 0x20, 0x54, 0x2d, 0x6e, 0xb2, 0x70,
+	0x30, 0x18, 0xa7, 0xf8, 0x09, 0xac, 0x54, 0xda, 0xd2, 0x76, 0x81, 0x57, 0x01, 0x2d, 0x7c, 0x4b,
+	0x68, 0x3b, 0xef, 0x86, 0xb6, 0x6b, 0x42, 0xfb, 0x07, 0x0b, 0xba, 0x67, 0x69, 0xb4, 0x08, 0xa6,
+	0x5c, 0x4c, 0xa3, 0x78, 0x76, 0x37, 0xa8, 0x04, 0x9f, 0x6d, 0xc2, 0xd7, 0x07, 0x67, 0xf4, 0x4d,
+	0xac, 0x5e, 0xa3, 0x23, 0x39, 0x04, 0x6e, 0x45, 0x89, 0xa3, 0x0a, 0x7b, 0x0c, 0xf6, 0x28, 0x96,
+	0x39, 0xdb, 0x39, 0x3d, 0xc8, 0x15, 0xb5, 0x8e, 0x3d, 0x8a, 0xbd, 0x1f, 0xc2, 0x21, 0x39, 0xa2,
+	0x45, 0xea, 0xf9, 0x3d, 0x84, 0xfa, 0x45, 0x1c, 0x47, 0xfa, 0x01, 0x26, 0x02, 0xbf, 0x72, 0xb2,
+	0x17, 0x1d, 0x83, 0xf1, 0x5d, 0x72, 0xa2, 0xea, 0x67, 0x45, 0x0f, 0x3a, 0x57, 0x51, 0xfa, 0xeb,
+	0x38, 0x48, 0x65, 0x5b, 0xa2, 0x67, 0xd4, 0x64, 0x79, 0x1f, 0xc3, 0x83, 0xd2, 0xc9, 0xf9, 0x9c,
+	0x30, 0x1a, 0x92, 0x35, 0xf5, 0xc1, 0x3f, 0x86, 0xfb, 0x99, 0xea, 0x68, 0xf8, 0x9d, 0x7c, 0xdc,
+	0x36, 0xfa, 0x03, 0x38, 0x2c, 0x1a, 0x55, 0xc7, 0x57, 0xdc, 0xc6, 0x1b, 0x80, 0xab, 0xd0, 0xa4,
+	0x3f, 0x2e, 0xca, 0x83, 0x49, 0x20, 0x36, 0x77, 0x7d, 0x96, 0xc9, 0x21, 0xcb, 0x96, 0x9d, 0x4c,
+	0xae, 0xbd, 0x3f, 0xda, 0x70, 0x58, 0x65, 0x24, 0x4f, 0x28, 0xcb, 0x48, 0x28, 0x76, 0x0a, 0xf5,
+	0x6f, 0x02, 0xb1, 0xd1, 0x93, 0xd1, 0xb1, 0x11, 0xec, 0x2d, 0x1f, 0x38, 0xa9, 0x62, 0x21, 0x9d,
+	0x4d, 0x53, 0x3d, 0xc7, 0xb6, 0xb9, 0xa2, 0xf0, 0x84, 0x41, 0x18, 0x4d, 0xbf, 0xa6, 0x2f, 0x64,
+	0x4e, 0x44, 0x45, 0x61, 0xd4, 0xbf, 0x65, 0x61, 0x34, 0x2a, 0x0b, 0xa3, 0x0f, 0xf7, 0xbe, 0x5c,
+	0xcd, 0xfc, 0x54, 0x5c, 0xdc, 0x04, 0x49, 0x2a, 0x96, 0x53, 0xe1, 0x36, 0xe5, 0x8d, 0xca, 0x6c,
+	0x9c, 0xd5, 0x77, 0xd5, 0x2d, 0x48, 0x74, 0xc7, 0xc7, 0x14, 0x83, 0x1a, 0x5e, 0x4f, 0x8f, 0xc7,
+	0xb8, 0xce, 0xd1, 0x72, 0x24, 0xb6, 0x44, 0x60, 0x78, 0xc7, 0x22, 0x55, 0x23, 0x3a, 0x2e, 0xb1,
+	0x35, 0x48, 0x11, 0x95, 0x63, 0xa2, 0xa6, 0xe1, 0x02, 0xcf, 0xfb, 0x0a, 0xde, 0x2b, 0x40, 0x2a,
+	0xab, 0x51, 0x87, 0x25, 0x1f, 0xa4, 0xad, 0xc2, 0x20, 0xfd, 0x7d, 0xa8, 0x4f, 0x8c, 0xc0, 0x1c,
+	0xd0, 0xf4, 0x60, 0x5c, 0x86, 0x93, 0xdc, 0x1b, 0x17, 0xa6, 0x07, 0xec, 0x91, 0x67, 0xf3, 0x79,
+	0x2c, 0xe6, 0x7e, 0xaa, 0x93, 0x25, 0x67, 0xb0, 0x8f, 0xa0, 0x21, 0x95, 0xb5, 0xd9, 0xf2, 0x38,
+	0xa8, 0xa4, 0xde, 0x87, 0xc6, 0x68, 0x90, 0xa5, 0x99, 0x65, 0xa4, 0x59, 0xcf, 0x1c, 0x07, 0xaa,
+	0x34, 0x06, 0xfb, 0x7f, 0x7b, 0x7b, 0x62, 0xfd, 0xe3, 0xed, 0x89, 0xf5, 0xaf, 0xb7, 0x27, 0xd6,
+	0x9f, 0xfe, 0x7d, 0xb2, 0x73, 0xdd, 0x90, 0x7f, 0x26, 0x7f, 0xf2, 0xdf, 0x01, 0x00, 0x5a, 0xac,
+	0x46, 0xcc, 0xa9, 0x14, 0x00, 0x00,
 }
 
 func (m *Row) Marshal() (dAtA []byte, err error) {
@@ -4463,6 +4489,33 @@ func (m *QueryResult) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Similar) > 0 {
+		i -= len(m.Similar)
+		copy(dAtA[i:], m.Similar)
+		i = encodeVarintPublic(dAtA, i, uint64(len(m.Similar)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xba
+	}
+	if len(m.Funnel) > 0 {
+		i -= len(m.Funnel)
+		copy(dAtA[i:], m.Funnel)
+		i = encodeVarintPublic(dAtA, i, uint64(len(m.Funnel)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb2
+	}
+	if len(m.DistinctSketch) > 0 {
+		i -= len(m.DistinctSketch)
+		copy(dAtA[i:], m.DistinctSketch)
+		i = encodeVarintPublic(dAtA, i, uint64(len(m.DistinctSketch)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xaa
+	}
 	if m.ExtractedIDMatrixSorted != nil {
 		{
 			size, err := m.ExtractedIDMatrixSorted.MarshalToSizedBuffer(dAtA[:i])
@@ -6394,6 +6447,18 @@ func (m *QueryResult) Size() (n int) {
 		l = m.ExtractedIDMatrixSorted.Size()
 		n += 2 + l + sovPublic(uint64(l))
 	}
+	l = len(m.DistinctSketch)
+	if l > 0 {
+		n += 2 + l + sovPublic(uint64(l))
+	}
+	l = len(m.Funnel)
+	if l > 0 {
+		n += 2 + l + sovPublic(uint64(l))
+	}
+	l = len(m.Similar)
+	if l > 0 {
+		n += 2 + l + sovPublic(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -11008,6 +11073,108 @@ func (m *QueryResult) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DistinctSketch", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPublic
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DistinctSketch = append(m.DistinctSketch[:0], dAtA[iNdEx:postIndex]...)
+			if m.DistinctSketch == nil {
+				m.DistinctSketch = []byte{}
+			}
+			iNdEx = postIndex
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Funnel", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPublic
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Funnel = append(m.Funnel[:0], dAtA[iNdEx:postIndex]...)
+			if m.Funnel == nil {
+				m.Funnel = []byte{}
+			}
+			iNdEx = postIndex
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Similar", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPublic
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPublic
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPublic
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Similar = append(m.Similar[:0], dAtA[iNdEx:postIndex]...)
+			if m.Similar == nil {
+				m.Similar = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPublic(dAtA[iNdEx:])
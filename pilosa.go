@@ -56,6 +56,20 @@ var (
 	ErrQueryTimeout     = errors.New("query timeout")
 	ErrTooManyWrites    = errors.New("too many write commands")
 
+	// ErrSQLTransactionsUnsupported is returned when a query of a sql
+	// transaction runs on a cluster of more than one node.
+	ErrSQLTransactionsUnsupported = errors.New("sql transactions are only supported on a single node")
+
+	// ErrQueryMemoryExceeded is returned when the results of a query need
+	// more memory than its max query memory allows.
+	ErrQueryMemoryExceeded = errors.New("query results exceeded the max query memory")
+
+	// ErrResourcePoolQueueFull is returned when a query can't wait for its
+	// resource pool because too many queries are waiting already, and
+	// ErrResourcePoolQueueTimeout when it waits longer than the pool allows.
+	ErrResourcePoolQueueFull    = errors.New("resource pool queue is full")
+	ErrResourcePoolQueueTimeout = errors.New("timed out waiting in resource pool queue")
+
 	// TODO(2.0) poorly named - used when a *node* doesn't own a shard. Probably
 	// we won't need this error at all by 2.0 though.
 	ErrClusterDoesNotOwnShard = errors.New("node does not own shard")
@@ -70,6 +84,11 @@ var (
 	ErrFieldsArgumentRequired    = errors.New("fields argument required")
 	ErrExpectedFieldListArgument = errors.New("expected field list argument")
 
+	ErrChangeCaptureDisabled = errors.New("change data capture is not enabled")
+	// ErrChangeOffsetExpired is returned when the change events after an
+	// offset have already been dropped from the change log.
+	ErrChangeOffsetExpired = errors.New("change offset has expired")
+
 	ErrIntFieldWithKeys       = errors.New("int field cannot be created with 'keys=true' option")
 	ErrDecimalFieldWithKeys   = errors.New("decimal field cannot be created with 'keys=true' option")
 	ErrTimestampFieldWithKeys = errors.New("timestamp field cannot be created with 'keys=true' option")
@@ -459,6 +459,25 @@ var callInfoByFunc = map[string]callInfo{
 	"Distinct":  {allowUnknown: true, callType: PrecallGlobal},
 	"Condition": {allowUnknown: true},
 
+	"ApproxCountDistinct": {
+		allowUnknown: false,
+		prototypes: map[string]interface{}{
+			"_field":    stringOrVariable,
+			"field":     stringOrVariable,
+			"precision": int64(0),
+		},
+	},
+
+	"Funnel": {
+		allowUnknown: false,
+		prototypes: map[string]interface{}{
+			"window":  "",
+			"from":    nil,
+			"to":      nil,
+			"ordered": true,
+		},
+	},
+
 	// allow only "field=X" cases with string field names
 	"Max": allowField,
 	"Min": allowField,
@@ -475,6 +494,22 @@ var callInfoByFunc = map[string]callInfo{
 			"column": stringOrInt64,
 		},
 	},
+	"Within": {
+		allowUnknown: false,
+		prototypes: map[string]interface{}{
+			"field": "",
+			"bbox":  []interface{}{},
+		},
+	},
+	"Near": {
+		allowUnknown: false,
+		prototypes: map[string]interface{}{
+			"field":  "",
+			"lat":    nil,
+			"lon":    nil,
+			"radius": nil,
+		},
+	},
 	"All": {
 		allowUnknown: false,
 		prototypes: map[string]interface{}{
@@ -497,8 +532,11 @@ var callInfoByFunc = map[string]callInfo{
 			"from":     nil,
 			"to":       nil,
 			"like":     "",
+			"regexp":   "",
+			"glob":     "",
 			"valueidx": int64(0),
 			"in":       nil,
+			"bucket":   nil,
 		},
 	},
 	"InnerUnionRows": {
@@ -535,7 +573,17 @@ var callInfoByFunc = map[string]callInfo{
 		},
 		callType: PrecallGlobal,
 	},
-	"Xor": {allowUnknown: false},
+	"Sample": {
+		allowUnknown: false,
+		prototypes: map[string]interface{}{
+			"n":        int64(0),
+			"fraction": nil,
+			"seed":     int64(0),
+		},
+		callType: PrecallGlobal,
+	},
+	"ShardVersions": {allowUnknown: false},
+	"Xor":           {allowUnknown: false},
 
 	"ConstRow": {
 		allowUnknown: false,
@@ -564,6 +612,18 @@ var callInfoByFunc = map[string]callInfo{
 			"field":  stringOrVariable,
 		},
 	},
+	"Similar": {
+		allowUnknown: false,
+		prototypes: map[string]interface{}{
+			"_field": stringOrVariable,
+			"field":  stringOrVariable,
+			"row":    nil,
+			"k":      int64(0),
+			"metric": "",
+			"filter": nil,
+			"ids":    nil,
+		},
+	},
 	"Percentile": {
 		allowUnknown: false,
 		prototypes: map[string]interface{}{
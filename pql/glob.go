@@ -0,0 +1,69 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pql
+
+import (
+	"regexp"
+	"strings"
+)
+
+// GlobToRegexp converts a glob pattern, as used by the glob argument of Rows
+// and the SQL GLOB operator, to an anchored regular expression. In a glob, *
+// matches any sequence of characters, ? matches a single character and
+// [...] matches one character from a set, which is negated by a leading ^.
+// Matching is case sensitive.
+func GlobToRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("(?s)^")
+
+	for runes := []rune(glob); len(runes) > 0; {
+		r := runes[0]
+		runes = runes[1:]
+
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			// A ] directly after the opening bracket (or its negation) is
+			// part of the set rather than the end of it.
+			i := 0
+			if i < len(runes) && runes[i] == '^' {
+				i++
+			}
+			if i < len(runes) && runes[i] == ']' {
+				i++
+			}
+			for i < len(runes) && runes[i] != ']' {
+				i++
+			}
+			if i >= len(runes) {
+				// unterminated set; match the bracket literally.
+				b.WriteString(`\[`)
+				continue
+			}
+
+			set := runes[:i]
+			runes = runes[i+1:]
+			b.WriteString("[")
+			if len(set) > 0 && set[0] == '^' {
+				b.WriteString("^")
+				set = set[1:]
+			}
+			for _, c := range set {
+				switch c {
+				case '\\', '[', ']', '^':
+					b.WriteRune('\\')
+				}
+				b.WriteRune(c)
+			}
+			b.WriteString("]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	b.WriteString("$")
+	return b.String()
+}
@@ -0,0 +1,43 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pql_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/featurebasedb/featurebase/v3/pql"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	for _, tt := range []struct {
+		glob    string
+		matches []string
+		misses  []string
+	}{
+		{glob: "abc", matches: []string{"abc"}, misses: []string{"ABC", "abcd", "xabc"}},
+		{glob: "a*", matches: []string{"a", "abc", "a\nb"}, misses: []string{"ba"}},
+		{glob: "a?c", matches: []string{"abc", "aéc"}, misses: []string{"ac", "abbc"}},
+		{glob: "[ab]x", matches: []string{"ax", "bx"}, misses: []string{"cx"}},
+		{glob: "[^ab]x", matches: []string{"cx"}, misses: []string{"ax", "bx"}},
+		{glob: "[a-c]*", matches: []string{"b", "cat"}, misses: []string{"dog"}},
+		{glob: "[]]", matches: []string{"]"}, misses: []string{"a"}},
+		{glob: "a.b+", matches: []string{"a.b+"}, misses: []string{"axbb"}},
+		{glob: "[abc", matches: []string{"[abc"}, misses: []string{"a"}},
+	} {
+		re, err := regexp.Compile(pql.GlobToRegexp(tt.glob))
+		if err != nil {
+			t.Fatalf("glob %q: %v", tt.glob, err)
+		}
+		for _, s := range tt.matches {
+			if !re.MatchString(s) {
+				t.Errorf("expected glob %q to match %q", tt.glob, s)
+			}
+		}
+		for _, s := range tt.misses {
+			if re.MatchString(s) {
+				t.Errorf("expected glob %q not to match %q", tt.glob, s)
+			}
+		}
+	}
+}
@@ -189,6 +189,9 @@ func (tx *RBFTx) Rollback() {
 func (tx *RBFTx) Commit() (err error) {
 	err = tx.tx.Commit()
 	tx.Db.CleanupTx(tx)
+	if err == nil && tx.o.Write && tx.o.Index != nil {
+		tx.o.Index.touchShard(tx.o.Shard)
+	}
 	return err
 }
 
@@ -537,6 +540,36 @@ func (w *RbfDBWrapper) DeleteField(index, field, fieldPath string) error {
 	return tx.Commit()
 }
 
+// RenameField renames the bitmaps belonging to field so they belong to
+// newField instead.
+func (w *RbfDBWrapper) RenameField(index, field, newField string) error {
+	w.muDb.Lock()
+	defer w.muDb.Unlock()
+
+	tx, err := w.db.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	names, err := tx.BitmapNames()
+	if err != nil {
+		return err
+	}
+	prefix := rbfFieldPrefix(index, field)
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		_, view := txkey.SplitPrefix([]byte(name))
+		newName := rbfName(index, newField, renamedView(field, newField, view), 0)
+		if err := tx.RenameBitmap(name, newName); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
 func (w *RbfDBWrapper) DeleteIndex(indexName string) error {
 
 	if strings.Contains(indexName, "'") {
@@ -3,6 +3,7 @@
 package cfg
 
 import (
+	"github.com/featurebasedb/featurebase/v3/encryption"
 	"github.com/featurebasedb/featurebase/v3/logger"
 	"github.com/spf13/pflag"
 )
@@ -10,6 +11,9 @@ import (
 const (
 	DefaultMinWALCheckpointSize = 1 * (1 << 20) // 1MB
 	DefaultMaxWALCheckpointSize = DefaultMaxWALSize / 2
+
+	DefaultCompactionFreeRatio = 0.5
+	DefaultMaxCompactionPages  = 1024
 )
 
 // Config defines externally configurable rbf options.
@@ -46,6 +50,21 @@ type Config struct {
 
 	// The maximum number of bits to be deleted in a single transaction default(65536)
 	MaxDelete int `toml:"max-delete"`
+
+	// CompactionFreeRatio is the fraction of the database's pages which must
+	// be free before a checkpoint moves in-use pages toward the front of
+	// the file so that it can truncate it. Zero disables online
+	// compaction.
+	CompactionFreeRatio float64 `toml:"compaction-free-ratio"`
+
+	// MaxCompactionPages is the maximum number of pages moved by online
+	// compaction in a single checkpoint.
+	MaxCompactionPages int `toml:"max-compaction-pages"`
+
+	// Cipher, if set, encrypts every page written to the database and WAL
+	// files. It cannot be set from toml; see the server's encryption
+	// settings.
+	Cipher *encryption.Cipher `toml:"-"`
 }
 
 func NewDefaultConfig() *Config {
@@ -57,6 +76,8 @@ func NewDefaultConfig() *Config {
 		FsyncEnabled:         true,
 		FsyncWALEnabled:      true,
 		MaxDelete:            DefaultMaxDelete,
+		CompactionFreeRatio:  DefaultCompactionFreeRatio,
+		MaxCompactionPages:   DefaultMaxCompactionPages,
 
 		// CI passed with 20. 50 was too big for CI, even on X-large instances.
 		// For now we default to 0, which means use sync.Pool.
@@ -78,6 +99,8 @@ func (cfg *Config) DefineFlags(flags *pflag.FlagSet, prefix string) {
 	flags.Int64Var(&cfg.MaxWALSize, pre("rbf.max-wal-size"), default0.MaxWALSize, "RBF maximum size in bytes of a WAL file (distinct from a DB file)")
 	flags.Int64Var(&cfg.MinWALCheckpointSize, pre("rbf.min-wal-checkpoint-size"), default0.MinWALCheckpointSize, "RBF minimum size in bytes of a WAL file before attempting checkpoint")
 	flags.Int64Var(&cfg.MaxWALCheckpointSize, pre("rbf.max-wal-checkpoint-size"), default0.MaxWALCheckpointSize, "RBF maximum size in bytes of a WAL file before forcing checkpoint")
+	flags.Float64Var(&cfg.CompactionFreeRatio, pre("rbf.compaction-free-ratio"), default0.CompactionFreeRatio, "RBF fraction of free pages in a database file before checkpoints move pages so the file can shrink; 0 disables")
+	flags.IntVar(&cfg.MaxCompactionPages, pre("rbf.max-compaction-pages"), default0.MaxCompactionPages, "RBF maximum number of pages moved by compaction in a single checkpoint")
 
 	// renamed from --rbf-fsync to just --fsync because now it applies to all Tx backends.
 	flags.BoolVar(&cfg.FsyncEnabled, pre("fsync"), default0.FsyncEnabled, "enable fsync fully safe flush-to-disk")
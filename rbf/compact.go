@@ -0,0 +1,387 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package rbf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+const (
+	// compactMinFreePages is the smallest freelist worth compacting
+	// automatically on checkpoint.
+	compactMinFreePages = 64
+
+	// compactReservePages is the number of WAL pages left free for the rest
+	// of a commit when deciding whether another page can be moved.
+	compactReservePages = 16
+
+	// compactMaxFreelistMoves bounds the attempts to move freelist pages off
+	// the end of the file. Moving a freelist page changes the freelist
+	// itself, which can occasionally allocate another page at the end.
+	compactMaxFreelistMoves = 32
+)
+
+// compactPage is an in-use page found while walking the b-trees.
+type compactPage struct {
+	pgno   uint32
+	parent uint32 // zero for the root of a tree
+	typ    uint32
+	name   string // bitmap name, for roots
+}
+
+// Compact moves in-use pages from the end of the file into free pages nearer
+// the front so that the free space left behind can be truncated. At most
+// maxPages b-tree pages are moved; zero or less moves as many as the
+// transaction can hold. Returns the number of b-tree pages moved.
+//
+// The data file only shrinks once the transaction is committed and the WAL
+// is checkpointed.
+func (tx *Tx) Compact(maxPages int) (int, error) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.db == nil {
+		return 0, ErrTxClosed
+	} else if !tx.writable {
+		return 0, ErrTxNotWritable
+	}
+	return tx.compact(maxPages)
+}
+
+// autoCompact moves pages toward the front of the file before a checkpoint
+// when the freelist exceeds the configured fraction of the file, so the
+// checkpoint can truncate the data file. The moved pages are written to the
+// WAL by an internal write transaction.
+//
+// Must be called while holding both db.mu and db.rwmu. Compaction is skipped
+// while other transactions are open because the checkpoint would overwrite
+// pages they may still read from the data file. It is also skipped by the
+// checkpoint which opens the database, because the pages already in the WAL
+// are not in the page map yet.
+func (db *DB) autoCompact() error {
+	ratio := db.cfg.CompactionFreeRatio
+	if ratio <= 0 || len(db.txs) != 0 || db.pageMap.size == 0 {
+		return nil
+	}
+
+	tx := &Tx{
+		db:               db,
+		rootRecords:      db.rootRecords,
+		pageMap:          db.pageMap,
+		walPageN:         db.walPageN,
+		writable:         true,
+		dirtyPages:       make(map[uint32][]byte),
+		dirtyBitmapPages: make(map[uint32][]byte),
+
+		DeleteEmptyContainer: true,
+	}
+	page, err := db.readMetaPage()
+	if err != nil {
+		return err
+	}
+	copy(tx.meta[:], page)
+	tx.walID = readMetaWALID(tx.meta[:])
+
+	freeN, err := tx.freePageN()
+	if err != nil {
+		return err
+	} else if freeN < compactMinFreePages || float64(freeN) < ratio*float64(readMetaPageN(tx.meta[:])) {
+		return nil
+	}
+
+	if _, err := tx.compact(db.cfg.MaxCompactionPages); err != nil {
+		return err
+	} else if !tx.dirty() {
+		return nil
+	} else if err := tx.flush(); err != nil {
+		return err
+	}
+
+	db.rootRecords = tx.rootRecords
+	db.pageMap = tx.pageMap
+	db.walPageN = tx.walPageN
+	return nil
+}
+
+func (tx *Tx) compact(maxPages int) (moved int, err error) {
+	if err := tx.truncateFreelist(); err != nil {
+		return 0, err
+	}
+
+	freeN, err := tx.freePageN()
+	if err != nil {
+		return 0, err
+	} else if freeN == 0 {
+		return 0, nil
+	}
+
+	// Once compacted, every in-use page fits below the boundary, so only
+	// pages at or above it need to move.
+	boundary := readMetaPageN(tx.meta[:]) - uint32(freeN)
+
+	records, err := tx.RootRecords()
+	if err != nil {
+		return 0, err
+	}
+
+	var pages []compactPage
+	for itr := records.Iterator(); !itr.Done(); {
+		name, root, _ := itr.Next()
+		if err := tx.walkTree(root, 0, func(pgno, parent, typ uint32, err error) error {
+			if err != nil {
+				return err
+			} else if pgno >= boundary {
+				pages = append(pages, compactPage{pgno: pgno, parent: parent, typ: typ, name: name})
+			}
+			return nil
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	// Move the last pages first since they block truncation. Parents are
+	// not necessarily moved before their children, so track where moved
+	// pages went in order to update references to them.
+	sort.Slice(pages, func(i, j int) bool { return pages[i].pgno > pages[j].pgno })
+	relocated := make(map[uint32]uint32)
+	rootsChanged := false
+	for _, p := range pages {
+		if maxPages > 0 && moved >= maxPages {
+			break
+		} else if !tx.hasCompactRoom() {
+			break
+		}
+
+		pgno, err := tx.allocatePgno()
+		if err != nil {
+			return moved, err
+		} else if pgno >= p.pgno {
+			// No free page closer to the front of the file.
+			if err := tx.freePgno(pgno); err != nil {
+				return moved, err
+			}
+			break
+		}
+
+		if err := tx.copyPage(p.pgno, pgno, p.typ); err != nil {
+			return moved, err
+		}
+
+		if p.parent == 0 {
+			records = records.Set(p.name, pgno)
+			rootsChanged = true
+		} else {
+			parent := p.parent
+			if v, ok := relocated[parent]; ok {
+				parent = v
+			}
+			if err := tx.updateChildPgno(parent, p.pgno, pgno); err != nil {
+				return moved, err
+			}
+		}
+		relocated[p.pgno] = pgno
+
+		if err := tx.freePgno(p.pgno); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+
+	// Rewriting the root records frees their pages and reallocates them from
+	// the front of the freelist.
+	if !rootsChanged {
+		for pgno := readMetaRootRecordPageNo(tx.meta[:]); pgno != 0; {
+			if pgno >= boundary {
+				rootsChanged = true
+				break
+			}
+			page, _, err := tx.readPage(pgno)
+			if err != nil {
+				return moved, err
+			}
+			pgno = WalkRootRecordPages(page)
+		}
+	}
+	if rootsChanged {
+		if err := tx.writeRootRecordPages(records); err != nil {
+			return moved, err
+		}
+	}
+
+	if err := tx.compactFreelist(); err != nil {
+		return moved, err
+	}
+	return moved, tx.truncateFreelist()
+}
+
+// compactFreelist moves freelist pages which sit at the end of the file into
+// free pages nearer the front.
+func (tx *Tx) compactFreelist() error {
+	for i := 0; i < compactMaxFreelistMoves && tx.hasCompactRoom(); i++ {
+		if err := tx.truncateFreelist(); err != nil {
+			return err
+		}
+
+		p, err := tx.lastFreelistPage()
+		if err != nil {
+			return err
+		} else if p.pgno != readMetaPageN(tx.meta[:])-1 {
+			return nil // the last page is not part of the freelist
+		}
+
+		pgno, err := tx.allocatePgno()
+		if err != nil {
+			return err
+		} else if pgno >= p.pgno {
+			return tx.freePgno(pgno)
+		}
+
+		// Allocating changes the freelist, so find the page again. If it
+		// has moved or been released, give the allocation back and retry.
+		if q, err := tx.lastFreelistPage(); err != nil {
+			return err
+		} else if q.pgno != p.pgno {
+			if err := tx.freePgno(pgno); err != nil {
+				return err
+			}
+			continue
+		} else {
+			p = q
+		}
+
+		if err := tx.copyPage(p.pgno, pgno, p.typ); err != nil {
+			return err
+		}
+		if p.parent == 0 {
+			writeMetaFreelistPageNo(tx.meta[:], pgno)
+		} else if err := tx.updateChildPgno(p.parent, p.pgno, pgno); err != nil {
+			return err
+		}
+		if err := tx.freePgno(p.pgno); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastFreelistPage returns the highest numbered page used by the freelist.
+func (tx *Tx) lastFreelistPage() (last compactPage, err error) {
+	err = tx.walkTree(readMetaFreelistPageNo(tx.meta[:]), 0, func(pgno, parent, typ uint32, err error) error {
+		if err != nil {
+			return err
+		} else if pgno > last.pgno {
+			last = compactPage{pgno: pgno, parent: parent, typ: typ}
+		}
+		return nil
+	})
+	return last, err
+}
+
+// copyPage writes the contents of page src to page dst.
+func (tx *Tx) copyPage(src, dst, typ uint32) error {
+	page, _, err := tx.readPage(src)
+	if err != nil {
+		return err
+	}
+	buf := allocPage()
+	copy(buf, page)
+
+	if typ == PageTypeBitmap {
+		return tx.writeBitmapPage(dst, buf)
+	}
+	writePageNo(buf, dst)
+	return tx.writePage(buf)
+}
+
+// updateChildPgno replaces the reference to child page old with new on a
+// branch or leaf page.
+func (tx *Tx) updateChildPgno(pgno, old, new uint32) error {
+	page, isHeap, err := tx.readPage(pgno)
+	if err != nil {
+		return err
+	} else if !isHeap {
+		buf := allocPage()
+		copy(buf, page)
+		page = buf
+	}
+
+	switch typ := readFlags(page); typ {
+	case PageTypeBranch:
+		for i, n := 0, readCellN(page); i < n; i++ {
+			if cell := readBranchCell(page, i); cell.ChildPgno == old {
+				cell.ChildPgno = new
+				writeBranchCell(page, i, readCellOffset(page, i), cell)
+				return tx.writePage(page)
+			}
+		}
+	case PageTypeLeaf:
+		for i, n := 0, readCellN(page); i < n; i++ {
+			if cell := readLeafCell(page, i); cell.Type == ContainerTypeBitmapPtr && toPgno(cell.Data) == old {
+				copy(cell.Data, fromPgno(new))
+				return tx.writePage(page)
+			}
+		}
+	default:
+		return fmt.Errorf("rbf: invalid parent page type: pgno=%d type=%d", pgno, typ)
+	}
+	return fmt.Errorf("rbf: page %d does not reference page %d", pgno, old)
+}
+
+// hasCompactRoom returns true if the WAL can hold more moved pages.
+func (tx *Tx) hasCompactRoom() bool {
+	pageN := tx.walPageN + len(tx.dirtyPages) + (len(tx.dirtyBitmapPages) * 2) + compactReservePages
+	return int64(pageN)*tx.db.pageSize() < int64(len(tx.db.wal))
+}
+
+// freePageN returns the number of pages in the freelist.
+func (tx *Tx) freePageN() (n int, err error) {
+	c := Cursor{tx: tx}
+	c.stack.elems[0] = stackElem{pgno: readMetaFreelistPageNo(tx.meta[:])}
+	if err := c.First(); err == io.EOF {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	for {
+		if err := c.Next(); err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			return n, err
+		}
+
+		elem := &c.stack.elems[c.stack.top]
+		leafPage, _, err := tx.readPage(elem.pgno)
+		if err != nil {
+			return n, err
+		}
+		n += readLeafCell(leafPage, elem.index).BitN
+	}
+}
+
+// Vacuum compacts the database and checkpoints it so the data file is
+// truncated to the pages in use. It repeatedly compacts in transactions
+// sized to fit the WAL until no more pages can be moved.
+func (db *DB) Vacuum() (moved int, err error) {
+	for {
+		tx, err := db.Begin(true)
+		if err != nil {
+			return moved, err
+		}
+
+		n, err := tx.Compact(0)
+		if err != nil {
+			tx.Rollback()
+			return moved, err
+		} else if err := tx.Commit(); err != nil {
+			return moved, err
+		}
+		moved += n
+
+		if n == 0 {
+			return moved, db.Checkpoint()
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/benbjohnson/immutable"
+	"github.com/featurebasedb/featurebase/v3/encryption"
 	"github.com/featurebasedb/featurebase/v3/logger"
 	rbfcfg "github.com/featurebasedb/featurebase/v3/rbf/cfg"
 	"github.com/featurebasedb/featurebase/v3/syswrap"
@@ -136,12 +137,26 @@ func (db *DB) Open() (err error) {
 	// Initialize file if it is too small.
 	if fi, err := db.file.Stat(); err != nil {
 		return fmt.Errorf("stat: %w", err)
-	} else if fi.Size() < PageSize {
+	} else if fi.Size() < db.pageSize() {
 		if err := db.init(); err != nil {
 			return fmt.Errorf("init: %w", err)
 		}
 	}
 
+	// Verify the meta page so that opening a database with the wrong key,
+	// or without one, fails here rather than on some later read.
+	if page, err := db.readDBPage(0); err != nil {
+		if db.cfg.Cipher != nil {
+			return fmt.Errorf("read meta page (database may be unencrypted or use a different key): %w", err)
+		}
+		return fmt.Errorf("read meta page: %w", err)
+	} else if !IsMetaPage(page) {
+		if db.cfg.Cipher == nil {
+			return fmt.Errorf("invalid meta page (database may be encrypted)")
+		}
+		return fmt.Errorf("invalid meta page")
+	}
+
 	// TODO(BBJ): Obtain advisory lock on file.
 
 	db.opened = true
@@ -183,12 +198,16 @@ func (db *DB) openWAL() (err error) {
 		return fmt.Errorf("wal stat: %w", err)
 	} else {
 		fileSize = fi.Size()
-		pageN = int(fileSize / PageSize)
+		pageN = int(fileSize / db.pageSize())
 	}
 
 	// Read backwards through the WAL to find the last valid meta page.
 	for ; pageN > 0; pageN-- {
-		if page, err := db.readWALPageAt(pageN - 1); err != nil {
+		if page, err := db.readWALPageAt(pageN - 1); err == encryption.ErrDecrypt {
+			// An encrypted page that fails authentication was only partly
+			// written, so it cannot be the last valid meta page.
+			continue
+		} else if err != nil {
 			return err
 		} else if IsMetaPage(page) {
 			// We now face a challenge. Probably this is a meta page.
@@ -225,16 +244,18 @@ func (db *DB) openWAL() (err error) {
 		}
 	}
 
-	if fileSize != int64(pageN*PageSize) {
-		if err := db.walFile.Truncate(int64(pageN) * PageSize); err != nil {
+	if fileSize != int64(pageN)*db.pageSize() {
+		if err := db.walFile.Truncate(int64(pageN) * db.pageSize()); err != nil {
 			return fmt.Errorf("wal truncate: %w", err)
 		}
 	}
-	if _, err := db.walFile.Seek(int64(pageN)*PageSize, io.SeekStart); err != nil {
+	if _, err := db.walFile.Seek(int64(pageN)*db.pageSize(), io.SeekStart); err != nil {
 		return fmt.Errorf("wal seek: %w", err)
 	}
 	db.walPageN = pageN
-	db.baseWALID = readMetaWALID(db.data)
+	if db.baseWALID, err = db.readDBMetaWALID(); err != nil {
+		return fmt.Errorf("read meta page: %w", err)
+	}
 
 	return nil
 }
@@ -299,6 +320,14 @@ func (db *DB) checkpoint() (err error) {
 		db.haltCond.Broadcast()
 	}()
 
+	// Move pages off the end of the file first so the copy below can shrink
+	// the data file. Compaction only saves space, so if it fails the
+	// checkpoint goes ahead without it; none of its pages are in the page
+	// map, and anything it wrote to the WAL is truncated with the rest.
+	if err := db.autoCompact(); err != nil {
+		db.logger.Warnf("rbf: skipping compaction of %s: %v", db.Path, err)
+	}
+
 	// Copy the pages from the WAL back to the database outside of the lock.
 	var pageN uint32
 	if err := func() error {
@@ -388,7 +417,9 @@ func (db *DB) checkpoint() (err error) {
 
 	db.afterCurrentTx(func() {
 		defer db.rwmu.Unlock()
-		db.baseWALID = readMetaWALID(db.data)
+		if db.baseWALID, err = db.readDBMetaWALID(); err != nil {
+			db.logger.Errorf("read meta page: %w", err)
+		}
 		db.mu.Unlock()
 		defer db.mu.Lock()
 
@@ -403,7 +434,7 @@ func (db *DB) checkpoint() (err error) {
 		// Truncate data file if it has shrunk.
 		if fi, err := db.file.Stat(); err != nil {
 			db.logger.Errorf("stat db file: %w", err)
-		} else if sz := int64(pageN) * PageSize; sz > 0 && fi.Size() > sz {
+		} else if sz := int64(pageN) * db.pageSize(); sz > 0 && fi.Size() > sz {
 			if err := db.file.Truncate(sz); err != nil {
 				db.logger.Errorf("truncate db file: %w", err)
 			}
@@ -559,7 +590,42 @@ func (db *DB) WALSize() int64 {
 }
 
 func (db *DB) walSize() int64 {
-	return int64(db.walPageN) * PageSize
+	return int64(db.walPageN) * db.pageSize()
+}
+
+// pageSize returns the size of a page in the database and WAL files, which
+// is larger than PageSize when pages are encrypted.
+func (db *DB) pageSize() int64 {
+	if db.cfg.Cipher != nil {
+		return PageSize + encryption.Overhead
+	}
+	return PageSize
+}
+
+// sealPage returns page as it should be written to disk.
+func (db *DB) sealPage(page []byte) ([]byte, error) {
+	if db.cfg.Cipher == nil {
+		return page, nil
+	}
+	return db.cfg.Cipher.Seal(make([]byte, 0, db.pageSize()), page)
+}
+
+// openPage returns the contents of a page read from disk. Encrypted pages
+// are decrypted into a new buffer, so the result never aliases the mmap.
+func (db *DB) openPage(buf []byte) ([]byte, error) {
+	if db.cfg.Cipher == nil {
+		return buf, nil
+	}
+	return db.cfg.Cipher.Open(make([]byte, 0, PageSize), buf)
+}
+
+// readDBMetaWALID returns the WAL ID recorded in the data file's meta page.
+func (db *DB) readDBMetaWALID() (int64, error) {
+	page, err := db.readDBPage(0)
+	if err != nil {
+		return 0, err
+	}
+	return readMetaWALID(page), nil
 }
 
 // init initializes a new database file.
@@ -581,8 +647,7 @@ func (db *DB) initMetaPage() error {
 	writeMetaPageN(page, 3)
 	writeMetaRootRecordPageNo(page, 1)
 	writeMetaFreelistPageNo(page, 2)
-	_, err := db.file.WriteAt(page, 0*PageSize)
-	return err
+	return db.writeDBPage(0, page)
 }
 
 // initRootRecordPage initializes the initial root record page.
@@ -590,8 +655,7 @@ func (db *DB) initRootRecordPage() error {
 	page := allocPage()
 	writePageNo(page, 1)
 	writeFlags(page, PageTypeRootRecord)
-	_, err := db.file.WriteAt(page, 1*PageSize)
-	return err
+	return db.writeDBPage(1, page)
 }
 
 // initFreelistPage initializes the initial freelist btree page.
@@ -599,8 +663,7 @@ func (db *DB) initFreelistPage() error {
 	page := allocPage()
 	writePageNo(page, 2)
 	writeFlags(page, PageTypeLeaf)
-	_, err := db.file.WriteAt(page, 2*PageSize)
-	return err
+	return db.writeDBPage(2, page)
 }
 
 // Begin starts a new transaction.
@@ -634,7 +697,7 @@ func (db *DB) Begin(writable bool) (_ *Tx, err error) {
 	// Wait for WAL size to be below threshold, if we're going to write.
 	// Reads don't care.
 	if writable {
-		for int64(db.walPageN)*PageSize > db.cfg.MaxWALCheckpointSize {
+		for db.walSize() > db.cfg.MaxWALCheckpointSize {
 			if db.isDead != nil {
 				err := db.isDead
 				cleanup()
@@ -791,21 +854,25 @@ func (db *DB) Check() error {
 
 // writeDBPage writes a page to the data file.
 func (db *DB) writeDBPage(pgno uint32, page []byte) error {
-	_, err := db.file.WriteAt(page, int64(pgno)*PageSize)
+	buf, err := db.sealPage(page)
+	if err != nil {
+		return err
+	}
+	_, err = db.file.WriteAt(buf, int64(pgno)*db.pageSize())
 	return err
 }
 
 func (db *DB) readDBPage(pgno uint32) ([]byte, error) {
-	offset := int64(pgno) * PageSize
+	offset := int64(pgno) * db.pageSize()
 
 	// FB-1381
 	// Verify page number requested is within the current size of database.
-	bound := offset + PageSize
+	bound := offset + db.pageSize()
 	if sz := int64(len(db.data)); bound >= sz {
 		return nil, fmt.Errorf("rbf: page read out of bounds, pgno=%d upper-bound=%d file-size=%d", pgno, bound, sz)
 	}
 
-	return db.data[offset:bound], nil
+	return db.openPage(db.data[offset:bound])
 }
 
 // readWALPageByID reads a WAL page by WAL ID.
@@ -815,8 +882,8 @@ func (db *DB) readWALPageByID(id int64) ([]byte, error) {
 
 // readWALPageAt reads the i-th page in the WAL file.
 func (db *DB) readWALPageAt(i int) ([]byte, error) {
-	offset := int64(i) * PageSize
-	return db.wal[offset : offset+PageSize], nil
+	offset := int64(i) * db.pageSize()
+	return db.openPage(db.wal[offset : offset+db.pageSize()])
 }
 
 func (db *DB) readMetaPage() ([]byte, error) {
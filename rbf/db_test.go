@@ -3,6 +3,7 @@
 package rbf_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 
 	_ "net/http/pprof"
 
+	"github.com/featurebasedb/featurebase/v3/encryption"
 	"github.com/featurebasedb/featurebase/v3/rbf"
 	rbfcfg "github.com/featurebasedb/featurebase/v3/rbf/cfg"
 	"github.com/felixge/fgprof"
@@ -299,6 +301,122 @@ func TestDB_Recovery(t *testing.T) {
 	})
 }
 
+func TestDB_Encryption(t *testing.T) {
+	newCipher := func(t *testing.T, keys map[uint32][]byte) *encryption.Cipher {
+		keyring, err := encryption.NewStaticKeyring(keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := encryption.NewCipher(keyring)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	key1, key2 := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	c1 := newCipher(t, map[uint32][]byte{1: key1})
+	c2 := newCipher(t, map[uint32][]byte{1: key1, 2: key2})
+	other := newCipher(t, map[uint32][]byte{1: key2})
+
+	open := func(path string, c *encryption.Cipher) (*rbf.DB, error) {
+		cfg := rbfcfg.NewDefaultConfig()
+		cfg.Cipher = c
+		db := rbf.NewDB(path, cfg)
+		return db, db.Open()
+	}
+	verify := func(t *testing.T, path string, c *encryption.Cipher) {
+		t.Helper()
+		db, err := open(path, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if err := db.Check(); err != nil {
+			t.Fatal(err)
+		}
+		tx := MustBegin(t, db, false)
+		defer tx.Rollback()
+		for _, v := range []uint64{1, 100000, 1 << 20} {
+			if ok, err := tx.Contains("x", v); err != nil || !ok {
+				t.Fatalf("Contains(%d)=<%v,%v>", v, ok, err)
+			}
+		}
+	}
+
+	path := t.TempDir()
+	db, err := open(path, c1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// write some data, and leave part of it in the WAL.
+	a := make([]uint64, rbf.ArrayMaxSize+100)
+	for i := range a {
+		a[i] = uint64(i)
+	}
+	tx := MustBegin(t, db, true)
+	if err := tx.CreateBitmap("x"); err != nil {
+		t.Fatal(err)
+	} else if _, err := tx.Add("x", a...); err != nil {
+		t.Fatal(err)
+	} else if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	} else if err := db.Checkpoint(); err != nil {
+		t.Fatal(err)
+	}
+	tx = MustBegin(t, db, true)
+	if _, err := tx.Add("x", 100000, 1<<20); err != nil {
+		t.Fatal(err)
+	} else if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	} else if db.WALSize()%(rbf.PageSize+encryption.Overhead) != 0 || db.WALSize() == 0 {
+		t.Fatalf("unexpected WAL size %d", db.WALSize())
+	} else if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Reopen", func(t *testing.T) {
+		verify(t, path, c1)
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		if db, err := open(path, other); err == nil {
+			db.Close()
+			t.Fatal("expected error opening with a different key")
+		}
+		if db, err := open(path, nil); err == nil {
+			db.Close()
+			t.Fatal("expected error opening without a key")
+		}
+	})
+
+	t.Run("Reencrypt", func(t *testing.T) {
+		// rotate to key 2.
+		if err := rbf.Reencrypt(path, c2, c2); err != nil {
+			t.Fatal(err)
+		}
+		verify(t, path, c2)
+		if db, err := open(path, c1); err == nil {
+			db.Close()
+			t.Fatal("expected error opening with a keyring missing the current key")
+		}
+
+		// decrypt to plaintext, and back.
+		if err := rbf.Reencrypt(path, c2, nil); err != nil {
+			t.Fatal(err)
+		}
+		verify(t, path, nil)
+		if db, err := open(path, c2); err == nil {
+			db.Close()
+			t.Fatal("expected error opening a plaintext database with a key")
+		}
+		if err := rbf.Reencrypt(path, nil, c1); err != nil {
+			t.Fatal(err)
+		}
+		verify(t, path, c1)
+	})
+}
+
 func TestDB_HasData(t *testing.T) {
 
 	db := MustOpenDB(t)
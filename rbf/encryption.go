@@ -0,0 +1,101 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package rbf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/featurebasedb/featurebase/v3/encryption"
+	rbfcfg "github.com/featurebasedb/featurebase/v3/rbf/cfg"
+)
+
+// CopyPages copies plaintext pages from src, such as the output of
+// Tx.SnapshotReader, to dst in the on-disk format of a database encrypted
+// with c. If c is nil the pages are copied unchanged.
+func CopyPages(dst io.Writer, src io.Reader, c *encryption.Cipher) (n int64, err error) {
+	if c == nil {
+		return io.Copy(dst, src)
+	}
+
+	page := make([]byte, PageSize)
+	buf := make([]byte, 0, PageSize+encryption.Overhead)
+	for {
+		if _, err := io.ReadFull(src, page); err == io.EOF {
+			return n, nil
+		} else if err == io.ErrUnexpectedEOF {
+			return n, fmt.Errorf("rbf: partial page at offset %d", n)
+		} else if err != nil {
+			return n, err
+		}
+
+		if buf, err = c.Seal(buf[:0], page); err != nil {
+			return n, err
+		}
+		m, err := dst.Write(buf)
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+}
+
+// Reencrypt rewrites the database at path, decrypting its pages with from
+// and encrypting them with to. Either cipher may be nil to convert a
+// database from or to plaintext, and from and to may be the same cipher to
+// re-encrypt every page with the keyring's current key after a rotation.
+// The database must not be open elsewhere.
+func Reencrypt(path string, from, to *encryption.Cipher) error {
+	cfg := rbfcfg.NewDefaultConfig()
+	cfg.Cipher = from
+	db := NewDB(path, cfg)
+
+	// Opening the database checkpoints the WAL, so the data file holds
+	// every page and the WAL can be discarded afterwards.
+	if err := db.Open(); err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+
+	tmpPath := db.DataPath() + ".reencrypt"
+	if err := func() error {
+		defer db.Close()
+
+		tx, err := db.Begin(false)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		r, err := tx.SnapshotReader()
+		if err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w := bufio.NewWriter(f)
+		if _, err := CopyPages(w, bufio.NewReaderSize(r, PageSize), to); err != nil {
+			return fmt.Errorf("copy pages: %w", err)
+		} else if err := w.Flush(); err != nil {
+			return err
+		} else if err := f.Sync(); err != nil {
+			return err
+		}
+		return f.Close()
+	}(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Truncate(db.WALPath(), 0); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("truncate wal: %w", err)
+	}
+	return os.Rename(tmpPath, db.DataPath())
+}
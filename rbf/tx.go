@@ -1289,7 +1289,7 @@ func (tx *Tx) writeBitmapPage(pgno uint32, page []byte) error {
 
 func (tx *Tx) checkTxSize() error {
 	pageN := tx.walPageN + len(tx.dirtyPages) + (len(tx.dirtyBitmapPages) * 2)
-	if pageN*PageSize >= len(tx.db.wal) {
+	if int64(pageN)*tx.db.pageSize() >= int64(len(tx.db.wal)) {
 		return ErrTxTooLarge
 	}
 	return nil
@@ -2025,7 +2025,11 @@ func (tx *Tx) writeToWAL(w io.Writer, page []byte) (walID int64, err error) {
 	writeMetaWALID(tx.meta[:], walID)
 
 	// Append to WAL and increment WAL size.
-	if _, err := w.Write(page); err != nil {
+	buf, err := tx.db.sealPage(page)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := w.Write(buf); err != nil {
 		return 0, err
 	}
 	tx.walPageN++
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/featurebasedb/featurebase/v3/rbf"
+	rbfcfg "github.com/featurebasedb/featurebase/v3/rbf/cfg"
 	"github.com/featurebasedb/featurebase/v3/roaring"
 	"github.com/stretchr/testify/assert"
 )
@@ -1029,6 +1030,181 @@ func TestTx_ReclaimAfterDelete(t *testing.T) {
 	}
 }
 
+// Ensure pages are moved off the end of the file so that it can be truncated
+// after bitmaps at the front of the file are deleted.
+func TestTx_Compact(t *testing.T) {
+	// populate writes bitmaps "a" then "b" with bitmap & array containers
+	// and deletes "a", leaving its pages free at the front of the file.
+	populate := func(t *testing.T, db *rbf.DB) (pageN int) {
+		t.Helper()
+		bits := make([]uint64, 1024)
+		for i := range bits {
+			bits[i] = 0x5555555555555555
+		}
+		for _, name := range []string{"a", "b"} {
+			tx := MustBegin(t, db, true)
+			for key := uint64(0); key < 1000; key++ {
+				c := roaring.NewContainerBitmap(-1, bits)
+				if key%3 == 0 {
+					c = roaring.NewContainerArray(convenientPrepopulatedArray)
+				}
+				if err := tx.PutContainer(name, key, c); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := tx.Commit(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		tx := MustBegin(t, db, true)
+		defer tx.Rollback()
+		if err := tx.DeleteBitmap("a"); err != nil {
+			t.Fatal(err)
+		}
+		pageN = tx.PageN()
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		return pageN
+	}
+
+	// verify checks that "b" is intact and the file has shrunk.
+	verify := func(t *testing.T, db *rbf.DB, pageN int) {
+		t.Helper()
+		tx := MustBegin(t, db, false)
+		defer tx.Rollback()
+
+		if n := tx.PageN(); n > pageN*2/3 {
+			t.Fatalf("expected fewer pages than %d, got %d", pageN*2/3, n)
+		} else if exists, err := tx.BitmapExists("a"); err != nil {
+			t.Fatal(err)
+		} else if exists {
+			t.Fatal("expected bitmap a to be deleted")
+		} else if count, err := tx.Count("b"); err != nil {
+			t.Fatal(err)
+		} else if exp := uint64(334*len(convenientPrepopulatedArray) + 666*32768); count != exp {
+			t.Fatalf("Count(b)=%d, want %d", count, exp)
+		} else if err := tx.Check(); err != nil {
+			t.Fatal(err)
+		}
+		pageN = tx.PageN()
+		tx.Rollback()
+
+		if err := db.Checkpoint(); err != nil {
+			t.Fatal(err)
+		} else if fi, err := os.Stat(db.DataPath()); err != nil {
+			t.Fatal(err)
+		} else if fi.Size() != int64(pageN)*rbf.PageSize {
+			t.Fatalf("data file size=%d, want %d", fi.Size(), int64(pageN)*rbf.PageSize)
+		}
+	}
+
+	t.Run("Online", func(t *testing.T) {
+		cfg := rbfcfg.NewDefaultConfig()
+		cfg.CompactionFreeRatio = 0.25
+		db := MustOpenDB(t, cfg)
+		defer MustCloseDB(t, db)
+		pageN := populate(t, db)
+
+		// Compaction runs as part of checkpointing.
+		if err := db.Checkpoint(); err != nil {
+			t.Fatal(err)
+		}
+		verify(t, db, pageN)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cfg := rbfcfg.NewDefaultConfig()
+		cfg.CompactionFreeRatio = 0
+		db := MustOpenDB(t, cfg)
+		defer MustCloseDB(t, db)
+		pageN := populate(t, db)
+
+		if err := db.Checkpoint(); err != nil {
+			t.Fatal(err)
+		}
+		tx := MustBegin(t, db, false)
+		defer tx.Rollback()
+		if tx.PageN() != pageN {
+			t.Fatalf("expected no compaction, got %d pages, want %d", tx.PageN(), pageN)
+		}
+	})
+
+	t.Run("Open", func(t *testing.T) {
+		// The data file has a long freelist, and the WAL has writes which
+		// haven't been checkpointed yet when the database is opened.
+		cfg := rbfcfg.NewDefaultConfig()
+		cfg.CompactionFreeRatio = 0
+		db := MustOpenDB(t, cfg)
+		defer MustCloseDB(t, db)
+		populate(t, db)
+		if err := db.Checkpoint(); err != nil {
+			t.Fatal(err)
+		}
+		tx := MustBegin(t, db, true)
+		if _, err := tx.Add("c", 1, 2, 3); err != nil {
+			t.Fatal(err)
+		} else if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		} else if err := db.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg.CompactionFreeRatio = 0.25
+		db = rbf.NewDB(db.Path, cfg)
+		if err := db.Open(); err != nil {
+			t.Fatal(err)
+		}
+		tx = MustBegin(t, db, false)
+		defer tx.Rollback()
+		if count, err := tx.Count("c"); err != nil {
+			t.Fatal(err)
+		} else if count != 3 {
+			t.Fatalf("Count(c)=%d, want 3", count)
+		} else if err := tx.Check(); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Manual", func(t *testing.T) {
+		cfg := rbfcfg.NewDefaultConfig()
+		cfg.CompactionFreeRatio = 0
+		db := MustOpenDB(t, cfg)
+		defer MustCloseDB(t, db)
+		pageN := populate(t, db)
+
+		tx := MustBegin(t, db, true)
+		defer tx.Rollback()
+		if tx.PageN() != pageN {
+			t.Fatalf("expected no compaction, got %d pages, want %d", tx.PageN(), pageN)
+		} else if moved, err := tx.Compact(0); err != nil {
+			t.Fatal(err)
+		} else if moved == 0 {
+			t.Fatal("expected pages to be moved")
+		} else if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		verify(t, db, pageN)
+	})
+
+	t.Run("Vacuum", func(t *testing.T) {
+		cfg := rbfcfg.NewDefaultConfig()
+		cfg.CompactionFreeRatio = 0
+		cfg.MaxCompactionPages = 10
+		db := MustOpenDB(t, cfg)
+		defer MustCloseDB(t, db)
+		pageN := populate(t, db)
+
+		if moved, err := db.Vacuum(); err != nil {
+			t.Fatal(err)
+		} else if moved == 0 {
+			t.Fatal("expected pages to be moved")
+		}
+		verify(t, db, pageN)
+	})
+}
+
 func BenchmarkTx_Add(b *testing.B) {
 	for _, n := range []int{1, 10, 1000} {
 		b.Run(fmt.Sprint(n), func(b *testing.B) {
@@ -131,6 +131,18 @@ func (s *onPremSchema) DeleteField(ctx context.Context, tname dax.TableName, fna
 	return s.api.DeleteField(ctx, string(tname), string(fname))
 }
 
+func (s *onPremSchema) RenameTable(ctx context.Context, tname dax.TableName, newName dax.TableName) error {
+	return s.api.RenameIndex(ctx, string(tname), string(newName))
+}
+
+func (s *onPremSchema) RenameField(ctx context.Context, tname dax.TableName, fname dax.FieldName, newName dax.FieldName) error {
+	return s.api.RenameField(ctx, string(tname), string(fname), string(newName))
+}
+
+func (s *onPremSchema) SetTableOption(ctx context.Context, tname dax.TableName, option string, value string) error {
+	return s.api.UpdateIndex(ctx, string(tname), IndexUpdate{Option: option, Value: value})
+}
+
 //////////////////////////////////////////////////////////////////////////////
 // The following are helper functions which convert between
 // featurebase.IndexInfo and dax.Table, and between featurebase.FieldInfo and
@@ -238,6 +250,8 @@ func FieldInfoToField(fi *FieldInfo) *dax.Field {
 		fieldType = dax.BaseTypeTimestamp
 	case FieldTypeBool:
 		fieldType = dax.BaseTypeBool
+	case FieldTypeGeo:
+		fieldType = dax.BaseTypeGeo
 	case FieldTypeTime:
 		if fo.Keys {
 			fieldType = dax.BaseTypeStringSetQ
@@ -452,6 +466,10 @@ func FieldOptionsFromField(fld *dax.Field) ([]FieldOption, error) {
 		opts = append(opts,
 			OptFieldTypeBool(),
 		)
+	case dax.BaseTypeGeo:
+		opts = append(opts,
+			OptFieldTypeGeo(),
+		)
 	case dax.BaseTypeDecimal:
 		opts = append(opts,
 			OptFieldTypeDecimal(fld.Options.Scale, fld.Options.Min, fld.Options.Max),
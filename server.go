@@ -20,6 +20,7 @@ import (
 
 	daxstorage "github.com/featurebasedb/featurebase/v3/dax/storage"
 	"github.com/featurebasedb/featurebase/v3/disco"
+	"github.com/featurebasedb/featurebase/v3/encryption"
 	"github.com/featurebasedb/featurebase/v3/logger"
 	pnet "github.com/featurebasedb/featurebase/v3/net"
 	rbfcfg "github.com/featurebasedb/featurebase/v3/rbf/cfg"
@@ -76,12 +77,18 @@ type Server struct { // nolint: maligned
 	metricInterval       time.Duration
 	diagnosticInterval   time.Duration
 	viewsRemovalInterval time.Duration
+	retentionInterval    time.Duration
 	maxWritesPerRequest  int
 	confirmDownSleep     time.Duration
 	confirmDownRetries   int
 	syncer               holderSyncer
 	maxQueryMemory       int64
 
+	// resourcePools configure the workload manager, which admits queries to
+	// the pools.
+	resourcePools []ResourcePool
+	workload      *workloadManager
+
 	translationSyncer      TranslationSyncer
 	resetTranslationSyncCh chan struct{}
 	// HolderConfig stashes server options that are really Holder options.
@@ -102,6 +109,13 @@ type Server struct { // nolint: maligned
 
 	dataframeEnabled    bool
 	dataframeUseParquet bool
+
+	// where sql exports can be written; see SystemAPI.ExportDirectory and
+	// SystemAPI.ExportURLs
+	exportDirectory string
+	exportURLs      []string
+
+	cipher *encryption.Cipher
 }
 
 type ExecutionPlannerFn func(executor Executor, api *API, sql string) sql3.CompilePlanner
@@ -196,6 +210,15 @@ func OptServerViewsRemovalInterval(interval time.Duration) ServerOption {
 	}
 }
 
+// OptServerRetentionInterval is a functional option on Server
+// used to set how often index retention policies are enforced.
+func OptServerRetentionInterval(interval time.Duration) ServerOption {
+	return func(s *Server) error {
+		s.retentionInterval = interval
+		return nil
+	}
+}
+
 // OptServerLongQueryTime is a functional option on Server
 // used to set long query duration.
 func OptServerLongQueryTime(dur time.Duration) ServerOption {
@@ -389,6 +412,17 @@ func OptServerRBFConfig(cfg *rbfcfg.Config) ServerOption {
 	}
 }
 
+// OptServerCipher is a functional option on Server used to encrypt
+// RBF databases and dataframe files at rest. Translation stores and the ID
+// allocator are encrypted by their open functions; see
+// OpenEncryptedTranslateStore and OpenEncryptedIDAllocator.
+func OptServerCipher(c *encryption.Cipher) ServerOption {
+	return func(s *Server) error {
+		s.cipher = c
+		return nil
+	}
+}
+
 // OptServerQueryHistoryLength is a functional option on Server
 // used to specify the length of the query history buffer that maintains
 // the information returned at /query-history.
@@ -407,6 +441,15 @@ func OptServerMaxQueryMemory(v int64) ServerOption {
 	}
 }
 
+// OptServerResourcePools sets the resource pools queries are admitted to
+// according to their user or groups.
+func OptServerResourcePools(pools []ResourcePool) ServerOption {
+	return func(s *Server) error {
+		s.resourcePools = pools
+		return nil
+	}
+}
+
 // OptServerDisCo is a functional option on Server
 // used to set the Distributed Consensus implementation.
 func OptServerDisCo(disCo disco.DisCo,
@@ -423,6 +466,15 @@ func OptServerDisCo(disCo disco.DisCo,
 	}
 }
 
+// OptServerChangeCapture configures the change log each index keeps of the
+// changes to its records.
+func OptServerChangeCapture(cfg ChangeCaptureConfig) ServerOption {
+	return func(s *Server) error {
+		s.holderConfig.ChangeCapture = cfg
+		return nil
+	}
+}
+
 // OptServerLookupDB configures a connection to an external postgres database for ExternalLookup queries.
 func OptServerLookupDB(dsn string) ServerOption {
 	return func(s *Server) error {
@@ -475,6 +527,23 @@ func OptServerDataframeUseParquet(is bool) ServerOption {
 	}
 }
 
+// OptServerExportDirectory sets the directory sql FILE exports are written
+// under.
+func OptServerExportDirectory(dir string) ServerOption {
+	return func(s *Server) error {
+		s.exportDirectory = dir
+		return nil
+	}
+}
+
+// OptServerExportURLs sets the urls sql URL exports can be posted to.
+func OptServerExportURLs(urls []string) ServerOption {
+	return func(s *Server) error {
+		s.exportURLs = urls
+		return nil
+	}
+}
+
 // NewServer returns a new instance of Server.
 func NewServer(opts ...ServerOption) (*Server, error) {
 	cluster := newCluster()
@@ -491,6 +560,7 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 		metricInterval:       0,
 		diagnosticInterval:   0,
 		viewsRemovalInterval: time.Hour,
+		retentionInterval:    time.Hour,
 
 		disCo:      disco.NopDisCo,
 		noder:      disco.NewEmptyLocalNoder(),
@@ -536,6 +606,11 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 		maxQueryMemory = int64(float64(memTotal) * .20)
 	}
 
+	s.workload, err = newWorkloadManager(s.resourcePools)
+	if err != nil {
+		return nil, errors.Wrap(err, "resource pools")
+	}
+
 	// set up executor after server opts have been processed
 	executorOpts := []executorOption{
 		optExecutorInternalQueryClient(s.defaultClient),
@@ -547,6 +622,10 @@ func NewServer(opts ...ServerOption) (*Server, error) {
 	s.executor = newExecutor(executorOpts...)
 	s.executor.dataframeEnabled = s.dataframeEnabled
 	s.executor.datafameUseParquet = s.dataframeUseParquet
+	s.executor.cipher = s.cipher
+	if s.cipher != nil {
+		s.holderConfig.RBFConfig.Cipher = s.cipher
+	}
 
 	path, err := expandDirName(s.dataDir)
 	if err != nil {
@@ -709,12 +788,13 @@ func (s *Server) Open() error {
 		return errors.Wrap(err, "setting nodeState")
 	}
 
-	if ok := s.addToWaitGroup(3); !ok {
+	if ok := s.addToWaitGroup(4); !ok {
 		return fmt.Errorf("closing server while opening server is NOT allowed")
 	}
 	go func() { defer s.wg.Done(); s.monitorRuntime() }()
 	go func() { defer s.wg.Done(); s.monitorDiagnostics() }()
 	go func() { defer s.wg.Done(); s.monitorViewsRemoval() }()
+	go func() { defer s.wg.Done(); s.monitorRetention() }()
 
 	toSend := func() []Message {
 		s.holder.startMsgsMu.Lock()
@@ -991,6 +1071,75 @@ func (s *Server) ViewsRemoval(ctx context.Context) {
 	}
 }
 
+func (s *Server) monitorRetention() {
+	ctx := context.Background()
+	ticker := time.NewTicker(s.retentionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+			s.RetentionRemoval(ctx)
+		}
+	}
+}
+
+// RetentionRemoval deletes records from each index with a retention policy
+// whose value in the index's retention field is older than its retention
+// period. Records are deleted with a PQL Delete query, so their keys are
+// freed as well. The query is run a shard at a time, so that each delete
+// only holds the writes of a single shard. Only the primary node does this,
+// since the query is distributed across the cluster.
+func (s *Server) RetentionRemoval(ctx context.Context) {
+	if !s.cluster.NewSnapshot().IsPrimaryFieldTranslationNode(s.nodeID) {
+		return
+	}
+	for _, index := range s.holder.Indexes() {
+		opts := index.Options()
+		if opts.RetentionField == "" || opts.RetentionPeriod <= 0 {
+			continue
+		}
+		field := index.Field(opts.RetentionField)
+		if field == nil || field.Options().Type != FieldTypeTimestamp {
+			s.logger.Errorf("index: %s, retention field %s is not a timestamp field", index.Name(), opts.RetentionField)
+			continue
+		}
+		shards, err := s.defaultClient.api.AvailableShards(ctx, index.Name())
+		if err != nil {
+			s.logger.Errorf("index: %s, retention shards: %s", index.Name(), err)
+			continue
+		}
+		cutoff := time.Now().Add(-opts.RetentionPeriod).UTC()
+		query := fmt.Sprintf("Delete(Row(%s < %q))", opts.RetentionField, cutoff.Format(time.RFC3339Nano))
+		var deleted bool
+		for _, shard := range shards.Slice() {
+			select {
+			case <-s.closing:
+				return
+			default:
+			}
+			resp, err := s.defaultClient.api.Query(ctx, &QueryRequest{
+				Index:  index.Name(),
+				Query:  query,
+				Shards: []uint64{shard},
+			})
+			if err != nil {
+				s.logger.Errorf("index: %s, shard: %d, retention delete: %s", index.Name(), shard, err)
+				continue
+			}
+			if len(resp.Results) == 1 {
+				if changed, ok := resp.Results[0].(bool); ok && changed {
+					deleted = true
+				}
+			}
+		}
+		if deleted {
+			s.logger.Infof("retention deleted - index: %s, records older than: %s", index.Name(), cutoff)
+		}
+	}
+}
+
 // receiveMessage represents an implementation of BroadcastHandler.
 func (s *Server) receiveMessage(m Message) error {
 	switch obj := m.(type) {
@@ -1024,12 +1173,33 @@ func (s *Server) receiveMessage(m Message) error {
 			return err
 		}
 
+	case *UpdateIndexMessage:
+		idx := s.holder.Index(obj.CreateIndexMessage.Index)
+		if idx == nil {
+			return newNotFoundError(ErrIndexNotFound, obj.CreateIndexMessage.Index)
+		}
+		idx.UpdateLocal(&obj.CreateIndexMessage)
+
 	case *DeleteFieldMessage:
 		idx := s.holder.Index(obj.Index)
 		if err := idx.DeleteField(obj.Field); err != nil {
 			return err
 		}
 
+	case *RenameFieldMessage:
+		idx := s.holder.Index(obj.Index)
+		if idx == nil {
+			return newNotFoundError(ErrIndexNotFound, obj.Index)
+		}
+		if err := idx.RenameFieldLocal(obj.Field, obj.NewField); err != nil {
+			return err
+		}
+
+	case *RenameIndexMessage:
+		if err := s.holder.RenameIndexLocal(obj.Index, obj.NewIndex); err != nil {
+			return err
+		}
+
 	case *DeleteAvailableShardMessage:
 		f := s.holder.Field(obj.Index, obj.Field)
 		if err := f.RemoveAvailableShard(obj.ShardID); err != nil {
@@ -1453,6 +1623,14 @@ func (s *Server) CompileExecutionPlan(ctx context.Context, q string) (planner_ty
 	return s.executionPlannerFn(s.executor, s.executor.client.api, q).CompilePlan(ctx, st)
 }
 
+// CompilePreparedExecutionPlan compiles an execution plan from a SQL statement
+// with positional parameters, binding parameters to them. The analysis of the
+// statement is cached, so that it is not parsed and analyzed for every
+// execution.
+func (s *Server) CompilePreparedExecutionPlan(ctx context.Context, q string, parameters []interface{}) (planner_types.PlanOperator, error) {
+	return s.executionPlannerFn(s.executor, s.executor.client.api, q).CompilePreparedPlan(ctx, q, parameters)
+}
+
 func (s *Server) RehydratePlanOperator(ctx context.Context, reader io.Reader) (planner_types.PlanOperator, error) {
 	return s.executionPlannerFn(s.executor, s.executor.client.api, "").RehydratePlanOp(ctx, reader)
 }
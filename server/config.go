@@ -16,7 +16,9 @@ import (
 	"strings"
 	"time"
 
+	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/authz"
+	"github.com/featurebasedb/featurebase/v3/encryption"
 	petcd "github.com/featurebasedb/featurebase/v3/etcd"
 	rbfcfg "github.com/featurebasedb/featurebase/v3/rbf/cfg"
 	"github.com/featurebasedb/featurebase/v3/storage"
@@ -262,6 +264,78 @@ type Config struct {
 		Enable     bool `toml:"enable"`
 		UseParquet bool `toml:"use-parquet"`
 	} `toml:"dataframe"`
+
+	// Encryption configures encryption at rest of the files under DataDir.
+	Encryption encryption.Config `toml:"encryption"`
+
+	// ChangeCapture configures the change log of each table, which clients
+	// read to follow the changes to its records.
+	ChangeCapture pilosa.ChangeCaptureConfig `toml:"change-capture"`
+
+	// ResourcePools share the node's query resources between users; see
+	// ResourcePoolConfig.
+	ResourcePools []ResourcePoolConfig `toml:"resource-pool"`
+
+	// Export restricts where the sql COPY ... TO statement can write.
+	Export struct {
+		// Directory is the directory FILE exports are written under. FILE
+		// exports are refused if it is empty.
+		Directory string `toml:"directory"`
+		// AllowedURLs are the urls URL exports can be posted to, or under.
+		// URL exports are refused if it is empty.
+		AllowedURLs []string `toml:"allowed-urls"`
+	} `toml:"export"`
+}
+
+// ResourcePoolConfig configures a resource pool, which limits the queries of
+// the users it is mapped to. A query runs in the first pool listing its user
+// or one of its groups, or otherwise in the "default" pool, which doesn't
+// limit its queries unless it is configured too.
+type ResourcePoolConfig struct {
+	Name string `toml:"name"`
+
+	// Users and Groups are the user ids and group ids or names of the
+	// authenticated users whose queries run in the pool.
+	Users  []string `toml:"users"`
+	Groups []string `toml:"groups"`
+
+	// MaxConcurrency is the number of the pool's queries which can run at
+	// once, MaxQueued the number which can wait for them to finish, and
+	// QueueTimeout how long they can wait. Zero means no limit.
+	MaxConcurrency int           `toml:"max-concurrency"`
+	MaxQueued      int           `toml:"max-queued"`
+	QueueTimeout   toml.Duration `toml:"queue-timeout"`
+
+	// Priority is "low", "normal" or "high"; the executor works on the
+	// shards of higher priority queries first.
+	Priority string `toml:"priority"`
+
+	// MaxQueryMemory is the most memory, in bytes, the results of each of the
+	// pool's queries can use. Zero means the node's max-query-memory, which
+	// applies to Extract() and SELECT queries only.
+	MaxQueryMemory int64 `toml:"max-query-memory"`
+}
+
+// resourcePools returns the configured resource pools.
+func (c *Config) resourcePools() ([]pilosa.ResourcePool, error) {
+	pools := make([]pilosa.ResourcePool, 0, len(c.ResourcePools))
+	for _, pc := range c.ResourcePools {
+		priority, err := pilosa.ParseQueryPriority(pc.Priority)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resource pool '%s'", pc.Name)
+		}
+		pools = append(pools, pilosa.ResourcePool{
+			Name:           pc.Name,
+			Users:          pc.Users,
+			Groups:         pc.Groups,
+			MaxConcurrency: pc.MaxConcurrency,
+			MaxQueued:      pc.MaxQueued,
+			QueueTimeout:   time.Duration(pc.QueueTimeout),
+			Priority:       priority,
+			MaxQueryMemory: pc.MaxQueryMemory,
+		})
+	}
+	return pools, nil
 }
 
 type Auth struct {
@@ -382,6 +456,8 @@ func NewConfig() *Config {
 		Storage:   storage.NewDefaultConfig(),
 		RBFConfig: rbfcfg.NewDefaultConfig(),
 
+		ChangeCapture: pilosa.NewDefaultChangeCaptureConfig(),
+
 		QueryHistoryLength: 100,
 
 		LongQueryTime: toml.Duration(-time.Minute),
@@ -9,6 +9,9 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
 )
 
 type addrs struct{ bind, advertise string }
@@ -622,3 +625,43 @@ func TestConfig_validatePermissionsFilename(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_resourcePools(t *testing.T) {
+	c, err := ParseConfig(`
+[[resource-pool]]
+name = "etl"
+users = ["loader"]
+max-concurrency = 2
+max-queued = 10
+queue-timeout = "30s"
+priority = "low"
+max-query-memory = 1000
+
+[[resource-pool]]
+name = "dashboards"
+groups = ["analysts"]
+priority = "high"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pools, err := c.resourcePools()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pools) != 2 {
+		t.Fatalf("expected 2 pools, got %d", len(pools))
+	}
+	if p := pools[0]; p.Name != "etl" || p.Users[0] != "loader" || p.MaxConcurrency != 2 || p.MaxQueued != 10 ||
+		p.QueueTimeout != 30*time.Second || p.Priority != pilosa.QueryPriorityLow || p.MaxQueryMemory != 1000 {
+		t.Fatalf("unexpected pool: %+v", p)
+	}
+	if p := pools[1]; p.Name != "dashboards" || p.Groups[0] != "analysts" || p.Priority != pilosa.QueryPriorityHigh {
+		t.Fatalf("unexpected pool: %+v", p)
+	}
+
+	c.ResourcePools[1].Priority = "urgent"
+	if _, err := c.resourcePools(); err == nil || !strings.Contains(err.Error(), "invalid query priority") {
+		t.Fatalf("expected invalid priority error, got %v", err)
+	}
+}
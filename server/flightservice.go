@@ -0,0 +1,186 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package server
+
+import (
+	"context"
+
+	"github.com/apache/arrow/go/v10/arrow/flight"
+	"google.golang.org/grpc"
+)
+
+// The generated code registering the Flight service with a grpc server is
+// internal to the arrow module, which only registers it with a grpc server
+// of its own. The service description below is equivalent, and lets the
+// Flight service share the FeatureBase gRPC listener (and its interceptors).
+
+// registerFlightService registers srv with s as the Arrow Flight service.
+func registerFlightService(s *grpc.Server, srv flight.FlightServer) {
+	s.RegisterService(&flightServiceDesc, srv)
+}
+
+var flightServiceDesc = grpc.ServiceDesc{
+	ServiceName: "arrow.flight.protocol.FlightService",
+	HandlerType: (*flight.FlightServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFlightInfo",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				return flightUnary(srv, ctx, dec, interceptor, "GetFlightInfo", func(ctx context.Context, desc *flight.FlightDescriptor) (interface{}, error) {
+					return srv.(flight.FlightServer).GetFlightInfo(ctx, desc)
+				})
+			},
+		},
+		{
+			MethodName: "GetSchema",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				return flightUnary(srv, ctx, dec, interceptor, "GetSchema", func(ctx context.Context, desc *flight.FlightDescriptor) (interface{}, error) {
+					return srv.(flight.FlightServer).GetSchema(ctx, desc)
+				})
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "Handshake",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(flight.FlightServer).Handshake(&flightHandshakeServer{stream})
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName: "ListFlights",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				m := new(flight.Criteria)
+				if err := stream.RecvMsg(m); err != nil {
+					return err
+				}
+				return srv.(flight.FlightServer).ListFlights(m, &flightListFlightsServer{stream})
+			},
+			ServerStreams: true,
+		},
+		{
+			StreamName: "DoGet",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				m := new(flight.Ticket)
+				if err := stream.RecvMsg(m); err != nil {
+					return err
+				}
+				return srv.(flight.FlightServer).DoGet(m, &flightDoGetServer{stream})
+			},
+			ServerStreams: true,
+		},
+		{
+			StreamName: "DoPut",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(flight.FlightServer).DoPut(&flightDoPutServer{stream})
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName: "DoExchange",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(flight.FlightServer).DoExchange(&flightDoExchangeServer{stream})
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName: "DoAction",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				m := new(flight.Action)
+				if err := stream.RecvMsg(m); err != nil {
+					return err
+				}
+				return srv.(flight.FlightServer).DoAction(m, &flightDoActionServer{stream})
+			},
+			ServerStreams: true,
+		},
+		{
+			StreamName: "ListActions",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				m := new(flight.Empty)
+				if err := stream.RecvMsg(m); err != nil {
+					return err
+				}
+				return srv.(flight.FlightServer).ListActions(m, &flightListActionsServer{stream})
+			},
+			ServerStreams: true,
+		},
+	},
+	Metadata: "Flight.proto",
+}
+
+// flightUnary handles a unary Flight method taking a FlightDescriptor.
+func flightUnary(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor, method string, fn func(context.Context, *flight.FlightDescriptor) (interface{}, error)) (interface{}, error) {
+	in := new(flight.FlightDescriptor)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return fn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/arrow.flight.protocol.FlightService/" + method,
+	}
+	return interceptor(ctx, in, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return fn(ctx, req.(*flight.FlightDescriptor))
+	})
+}
+
+// The server side of each of the Flight streams.
+
+type flightHandshakeServer struct{ grpc.ServerStream }
+
+func (x *flightHandshakeServer) Send(m *flight.HandshakeResponse) error { return x.SendMsg(m) }
+
+func (x *flightHandshakeServer) Recv() (*flight.HandshakeRequest, error) {
+	m := new(flight.HandshakeRequest)
+	if err := x.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type flightListFlightsServer struct{ grpc.ServerStream }
+
+func (x *flightListFlightsServer) Send(m *flight.FlightInfo) error { return x.SendMsg(m) }
+
+type flightDoGetServer struct{ grpc.ServerStream }
+
+func (x *flightDoGetServer) Send(m *flight.FlightData) error { return x.SendMsg(m) }
+
+type flightDoPutServer struct{ grpc.ServerStream }
+
+func (x *flightDoPutServer) Send(m *flight.PutResult) error { return x.SendMsg(m) }
+
+func (x *flightDoPutServer) Recv() (*flight.FlightData, error) {
+	m := new(flight.FlightData)
+	if err := x.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type flightDoExchangeServer struct{ grpc.ServerStream }
+
+func (x *flightDoExchangeServer) Send(m *flight.FlightData) error { return x.SendMsg(m) }
+
+func (x *flightDoExchangeServer) Recv() (*flight.FlightData, error) {
+	m := new(flight.FlightData)
+	if err := x.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type flightDoActionServer struct{ grpc.ServerStream }
+
+func (x *flightDoActionServer) Send(m *flight.Result) error { return x.SendMsg(m) }
+
+type flightListActionsServer struct{ grpc.ServerStream }
+
+func (x *flightListActionsServer) Send(m *flight.ActionType) error { return x.SendMsg(m) }
@@ -0,0 +1,781 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package server
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/decimal128"
+	"github.com/apache/arrow/go/v10/arrow/flight"
+	"github.com/apache/arrow/go/v10/arrow/flight/flightsql"
+	"github.com/apache/arrow/go/v10/arrow/flight/flightsql/schema_ref"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/authn"
+	"github.com/featurebasedb/featurebase/v3/authz"
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
+	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	planner_types "github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// flightSQLBatchSize is the number of rows sent in each arrow record batch.
+const flightSQLBatchSize = 4096
+
+// flightSQLTableType is the only table type reported to Flight SQL clients.
+const flightSQLTableType = "TABLE"
+
+// flightSQLMaxPreparedStatements is the number of prepared statements which
+// can be open at once, across all clients.
+const flightSQLMaxPreparedStatements = 1000
+
+// flightSQLPreparedStatementIdleTimeout is how long a prepared statement is
+// kept without being used before it is closed.
+const flightSQLPreparedStatementIdleTimeout = time.Hour
+
+// FlightSQLServer implements the Arrow Flight SQL protocol. Queries are run
+// by the sql3 planner, and their results are streamed to the client as arrow
+// record batches, so that dataframe libraries (pandas, Polars, DuckDB, Spark
+// ...) can read them in columnar form.
+//
+// A query is run when its ticket is fetched (DoGet); GetFlightInfo only
+// compiles it to find the schema of its results. The sql3 session of a
+// request, if any, is taken from the X-Sql-Session gRPC metadata, as it is
+// from the header of the same name over http.
+type FlightSQLServer struct {
+	flightsql.BaseServer
+
+	api    *pilosa.API
+	perms  *authz.GroupPermissions
+	logger logger.Logger
+	mem    memory.Allocator
+
+	mu sync.Mutex
+	// the prepared statements created by clients, keyed by handle
+	prepared map[string]*flightSQLPreparedStatement
+	// prepared statements which go unused for longer than idleTimeout are
+	// closed, so that statements clients never close aren't kept forever
+	idleTimeout time.Duration
+	now         func() time.Time
+}
+
+// flightSQLPreparedStatement is a statement prepared by a Flight SQL client,
+// along with the values last bound to its positional parameters. Only the
+// user which created it can use it.
+type flightSQLPreparedStatement struct {
+	sql        string
+	parameters []interface{}
+	userID     string
+	lastUsed   time.Time
+}
+
+func NewFlightSQLServer(api *pilosa.API) *FlightSQLServer {
+	s := &FlightSQLServer{
+		api:         api,
+		logger:      logger.NopLogger,
+		mem:         memory.DefaultAllocator,
+		prepared:    make(map[string]*flightSQLPreparedStatement),
+		idleTimeout: flightSQLPreparedStatementIdleTimeout,
+		now:         time.Now,
+	}
+	s.Alloc = s.mem
+
+	for id, value := range map[flightsql.SqlInfo]interface{}{
+		flightsql.SqlInfoFlightSqlServerName:         "FeatureBase",
+		flightsql.SqlInfoFlightSqlServerVersion:      pilosa.Version,
+		flightsql.SqlInfoFlightSqlServerArrowVersion: "10.0.0",
+		flightsql.SqlInfoFlightSqlServerReadOnly:     false,
+	} {
+		// only fails for value types other than the above
+		_ = s.RegisterSqlInfo(id, value)
+	}
+	return s
+}
+
+func (s *FlightSQLServer) WithLogger(logger logger.Logger) *FlightSQLServer {
+	s.logger = logger
+	return s
+}
+
+func (s *FlightSQLServer) WithPerms(perms *authz.GroupPermissions) *FlightSQLServer {
+	s.perms = perms
+	return s
+}
+
+// authorize returns the context to run a request with. As on the http /sql
+// endpoint, running sql3 statements requires admin permission.
+func (s *FlightSQLServer) authorize(ctx context.Context) (context.Context, error) {
+	if uinfo, ok := authn.GetUserInfo(ctx); ok && uinfo != nil && s.perms != nil {
+		if !s.perms.IsAdmin(uinfo.Groups) {
+			return nil, status.Error(codes.PermissionDenied, "insufficient permissions to run sql queries")
+		}
+		ctx = authn.WithAdmin(ctx, true)
+		ctx = fbcontext.WithUserID(ctx, uinfo.UserID)
+	}
+
+	requestID, err := uuid.NewV4()
+	if err != nil {
+		return nil, errors.Wrap(err, "generating request id")
+	}
+	ctx = fbcontext.WithRequestID(ctx, requestID.String())
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if sessionID := md.Get(pilosa.HeaderSQLSessionID); len(sessionID) > 0 && sessionID[0] != "" {
+			ctx = fbcontext.WithSessionID(ctx, sessionID[0])
+		}
+	}
+	return ctx, nil
+}
+
+// compile compiles sql, binding parameters to its positional parameters if
+// there are any.
+func (s *FlightSQLServer) compile(ctx context.Context, sql string, parameters []interface{}) (planner_types.PlanOperator, error) {
+	var op planner_types.PlanOperator
+	var err error
+	if parameters != nil {
+		op, err = s.api.CompilePreparedPlan(ctx, sql, parameters)
+	} else {
+		op, err = s.api.CompilePlan(ctx, sql)
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return op, nil
+}
+
+// flightInfo returns the FlightInfo for a query with a single endpoint,
+// fetched with ticket.
+func (s *FlightSQLServer) flightInfo(schema *arrow.Schema, desc *flight.FlightDescriptor, ticket []byte) *flight.FlightInfo {
+	return &flight.FlightInfo{
+		Schema:           flight.SerializeSchema(schema, s.mem),
+		FlightDescriptor: desc,
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: ticket}}},
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}
+}
+
+// GetFlightInfoStatement compiles a query to find the schema of its results,
+// and returns a ticket (holding the query text) to run it with.
+func (s *FlightSQLServer) GetFlightInfoStatement(ctx context.Context, cmd flightsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	op, err := s.compile(ctx, cmd.GetQuery(), nil)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := arrowSchema(op.Schema())
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := flightsql.CreateStatementQueryTicket([]byte(cmd.GetQuery()))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating ticket")
+	}
+	return s.flightInfo(schema, desc, ticket), nil
+}
+
+// GetSchemaStatement returns the schema of the results of a query.
+func (s *FlightSQLServer) GetSchemaStatement(ctx context.Context, cmd flightsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.SchemaResult, error) {
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	op, err := s.compile(ctx, cmd.GetQuery(), nil)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := arrowSchema(op.Schema())
+	if err != nil {
+		return nil, err
+	}
+	return &flight.SchemaResult{Schema: flight.SerializeSchema(schema, s.mem)}, nil
+}
+
+// DoGetStatement runs the query held in a ticket, streaming its results.
+func (s *FlightSQLServer) DoGetStatement(ctx context.Context, cmd flightsql.StatementQueryTicket) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	op, err := s.compile(ctx, string(cmd.GetStatementHandle()), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.stream(ctx, op)
+}
+
+// DoPutCommandStatementUpdate runs a statement which returns no results
+// (INSERT, DELETE, CREATE TABLE ...). sql3 does not count the records a
+// statement changes, so the count returned is always -1 (unknown).
+func (s *FlightSQLServer) DoPutCommandStatementUpdate(ctx context.Context, cmd flightsql.StatementUpdate) (int64, error) {
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return 0, err
+	}
+	op, err := s.compile(ctx, cmd.GetQuery(), nil)
+	if err != nil {
+		return 0, err
+	}
+	iter, err := op.Iterator(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if _, err := iter.Next(ctx); err == planner_types.ErrNoMoreRows {
+			return -1, nil
+		} else if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// CreatePreparedStatement creates a prepared statement for a query. Its
+// positional parameters ($1, $2, ...) are bound with
+// DoPutPreparedStatementQuery, and the analysis of the query is cached by
+// the planner across executions.
+func (s *FlightSQLServer) CreatePreparedStatement(ctx context.Context, req flightsql.ActionCreatePreparedStatementRequest) (flightsql.ActionCreatePreparedStatementResult, error) {
+	var result flightsql.ActionCreatePreparedStatementResult
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return result, err
+	}
+	handle, err := uuid.NewV4()
+	if err != nil {
+		return result, errors.Wrap(err, "generating handle")
+	}
+	userID, _ := fbcontext.UserID(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireIdle()
+
+	if len(s.prepared) >= flightSQLMaxPreparedStatements {
+		return result, status.Errorf(codes.ResourceExhausted, "too many prepared statements (max %d)", flightSQLMaxPreparedStatements)
+	}
+	s.prepared[handle.String()] = &flightSQLPreparedStatement{
+		sql:      req.GetQuery(),
+		userID:   userID,
+		lastUsed: s.now(),
+	}
+
+	result.Handle = []byte(handle.String())
+	return result, nil
+}
+
+// ClosePreparedStatement removes a prepared statement.
+func (s *FlightSQLServer) ClosePreparedStatement(ctx context.Context, req flightsql.ActionClosePreparedStatementRequest) error {
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	handle := req.GetPreparedStatementHandle()
+	if _, err := s.unprotectedPreparedStatement(ctx, handle); err != nil {
+		return err
+	}
+	delete(s.prepared, string(handle))
+	return nil
+}
+
+// preparedStatement returns the text of a prepared statement and the values
+// bound to its parameters.
+func (s *FlightSQLServer) preparedStatement(ctx context.Context, handle []byte) (string, []interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prepared, err := s.unprotectedPreparedStatement(ctx, handle)
+	if err != nil {
+		return "", nil, err
+	}
+	return prepared.sql, prepared.parameters, nil
+}
+
+// unprotectedPreparedStatement returns a prepared statement created by the
+// user of ctx, marking it used. Statements of other users are reported as not
+// found. Call only when holding s.mu.
+func (s *FlightSQLServer) unprotectedPreparedStatement(ctx context.Context, handle []byte) (*flightSQLPreparedStatement, error) {
+	s.expireIdle()
+
+	userID, _ := fbcontext.UserID(ctx)
+	prepared, ok := s.prepared[string(handle)]
+	if !ok || prepared.userID != userID {
+		return nil, status.Errorf(codes.NotFound, "prepared statement %s not found", handle)
+	}
+	prepared.lastUsed = s.now()
+	return prepared, nil
+}
+
+// expireIdle closes the prepared statements which have not been used for
+// longer than the idle timeout. Call only when holding s.mu.
+func (s *FlightSQLServer) expireIdle() {
+	if s.idleTimeout <= 0 {
+		return
+	}
+	now := s.now()
+	for handle, prepared := range s.prepared {
+		if now.Sub(prepared.lastUsed) > s.idleTimeout {
+			delete(s.prepared, handle)
+		}
+	}
+}
+
+// DoPutPreparedStatementQuery binds the values in the first row of the
+// record batches sent to the positional parameters of a prepared statement;
+// the first column is $1, the second $2, and so on.
+func (s *FlightSQLServer) DoPutPreparedStatementQuery(ctx context.Context, cmd flightsql.PreparedStatementQuery, r flight.MessageReader, w flight.MetadataWriter) error {
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return err
+	}
+	var parameters []interface{}
+	for r.Next() {
+		rec := r.Record()
+		if rec.NumRows() == 0 || parameters != nil {
+			continue
+		}
+		parameters = make([]interface{}, rec.NumCols())
+		for i, col := range rec.Columns() {
+			value, err := arrowParameterValue(col, 0)
+			if err != nil {
+				return status.Errorf(codes.InvalidArgument, "parameter $%d: %v", i+1, err)
+			}
+			parameters[i] = value
+		}
+	}
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prepared, err := s.unprotectedPreparedStatement(ctx, cmd.GetPreparedStatementHandle())
+	if err != nil {
+		return err
+	}
+	prepared.parameters = parameters
+	return nil
+}
+
+// GetFlightInfoPreparedStatement compiles a prepared statement with the
+// values bound to its parameters to find the schema of its results.
+func (s *FlightSQLServer) GetFlightInfoPreparedStatement(ctx context.Context, cmd flightsql.PreparedStatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sql, parameters, err := s.preparedStatement(ctx, cmd.GetPreparedStatementHandle())
+	if err != nil {
+		return nil, err
+	}
+	op, err := s.compile(ctx, sql, parameters)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := arrowSchema(op.Schema())
+	if err != nil {
+		return nil, err
+	}
+	return s.flightInfo(schema, desc, desc.Cmd), nil
+}
+
+// DoGetPreparedStatement runs a prepared statement with the values bound to
+// its parameters, streaming its results.
+func (s *FlightSQLServer) DoGetPreparedStatement(ctx context.Context, cmd flightsql.PreparedStatementQuery) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sql, parameters, err := s.preparedStatement(ctx, cmd.GetPreparedStatementHandle())
+	if err != nil {
+		return nil, nil, err
+	}
+	op, err := s.compile(ctx, sql, parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.stream(ctx, op)
+}
+
+// GetFlightInfoTables returns the FlightInfo for a listing of tables.
+func (s *FlightSQLServer) GetFlightInfoTables(ctx context.Context, cmd flightsql.GetTables, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	schema := schema_ref.Tables
+	if cmd.GetIncludeSchema() {
+		schema = schema_ref.TablesWithIncludedSchema
+	}
+	return s.flightInfo(schema, desc, desc.Cmd), nil
+}
+
+// DoGetTables lists the tables matching the filters of cmd. FeatureBase has
+// no catalogs or database schemas, so those filters must be empty.
+func (s *FlightSQLServer) DoGetTables(ctx context.Context, cmd flightsql.GetTables) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	ctx, err := s.authorize(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schema := schema_ref.Tables
+	if cmd.GetIncludeSchema() {
+		schema = schema_ref.TablesWithIncludedSchema
+	}
+	ch := make(chan flight.StreamChunk, 1)
+
+	if c := cmd.GetCatalog(); c != nil && *c != "" {
+		close(ch)
+		return schema, ch, nil
+	} else if pattern := cmd.GetDBSchemaFilterPattern(); pattern != nil && *pattern != "" && *pattern != "%" {
+		close(ch)
+		return schema, ch, nil
+	}
+	if types := cmd.GetTableTypes(); len(types) > 0 {
+		found := false
+		for _, typ := range types {
+			found = found || strings.EqualFold(typ, flightSQLTableType)
+		}
+		if !found {
+			close(ch)
+			return schema, ch, nil
+		}
+	}
+
+	var match *regexp.Regexp
+	if pattern := cmd.GetTableNameFilterPattern(); pattern != nil {
+		if match, err = likePattern(*pattern); err != nil {
+			return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	indexes, err := s.api.Schema(ctx, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
+
+	b := array.NewRecordBuilder(s.mem, schema)
+	defer b.Release()
+	for _, idx := range indexes {
+		if match != nil && !match.MatchString(idx.Name) {
+			continue
+		}
+		b.Field(0).AppendNull()
+		b.Field(1).AppendNull()
+		b.Field(2).(*array.StringBuilder).Append(idx.Name)
+		b.Field(3).(*array.StringBuilder).Append(flightSQLTableType)
+		if cmd.GetIncludeSchema() {
+			op, err := s.compile(ctx, "SELECT * FROM "+(&parser.Ident{Name: idx.Name, Quoted: true}).String(), nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			tblSchema, err := arrowSchema(op.Schema())
+			if err != nil {
+				return nil, nil, err
+			}
+			b.Field(4).(*array.BinaryBuilder).Append(flight.SerializeSchema(tblSchema, s.mem))
+		}
+	}
+	ch <- flight.StreamChunk{Data: b.NewRecord()}
+	close(ch)
+	return schema, ch, nil
+}
+
+// GetFlightInfoTableTypes returns the FlightInfo for a listing of table
+// types.
+func (s *FlightSQLServer) GetFlightInfoTableTypes(ctx context.Context, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	return s.flightInfo(schema_ref.TableTypes, desc, desc.Cmd), nil
+}
+
+// DoGetTableTypes lists the table types, of which there is only one.
+func (s *FlightSQLServer) DoGetTableTypes(ctx context.Context) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	b := array.NewRecordBuilder(s.mem, schema_ref.TableTypes)
+	defer b.Release()
+	b.Field(0).(*array.StringBuilder).Append(flightSQLTableType)
+
+	ch := make(chan flight.StreamChunk, 1)
+	ch <- flight.StreamChunk{Data: b.NewRecord()}
+	close(ch)
+	return schema_ref.TableTypes, ch, nil
+}
+
+// stream runs op, returning the schema of its results and a channel of
+// record batches holding them.
+//
+// The Flight SQL server drops the error of a chunk without reporting it to
+// the client, so the first batch is read before returning; most errors
+// happen there, when the query is first run, and are returned to the client
+// as the error of the request. Errors after that are logged, and end the
+// stream.
+func (s *FlightSQLServer) stream(ctx context.Context, op planner_types.PlanOperator) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	schema, err := arrowSchema(op.Schema())
+	if err != nil {
+		return nil, nil, err
+	}
+	iter, err := op.Iterator(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	b := array.NewRecordBuilder(s.mem, schema)
+	rec, done, err := nextRecordBatch(ctx, iter, op.Schema(), b)
+	if err != nil {
+		b.Release()
+		return nil, nil, err
+	}
+
+	ch := make(chan flight.StreamChunk)
+	go func() {
+		defer close(ch)
+		defer b.Release()
+		for {
+			if rec != nil {
+				select {
+				case ch <- flight.StreamChunk{Data: rec}:
+				case <-ctx.Done():
+					rec.Release()
+					return
+				}
+			}
+			if done {
+				return
+			}
+			rec, done, err = nextRecordBatch(ctx, iter, op.Schema(), b)
+			if err != nil {
+				s.logger.Errorf("flight sql: streaming results: %v", err)
+				select {
+				case ch <- flight.StreamChunk{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+		}
+	}()
+	return schema, ch, nil
+}
+
+// nextRecordBatch reads up to flightSQLBatchSize rows from iter into a
+// record batch, which is nil if there are no rows left. done is true once
+// iter has no more rows.
+func nextRecordBatch(ctx context.Context, iter planner_types.RowIterator, schema planner_types.Schema, b *array.RecordBuilder) (rec arrow.Record, done bool, err error) {
+	var n int
+	for n < flightSQLBatchSize {
+		row, err := iter.Next(ctx)
+		if err == planner_types.ErrNoMoreRows {
+			done = true
+			break
+		} else if err != nil {
+			return nil, false, err
+		}
+		for i := range schema {
+			if err := appendArrowValue(b.Field(i), row[i]); err != nil {
+				return nil, false, errors.Wrapf(err, "column '%s'", schema[i].ColumnName)
+			}
+		}
+		n++
+	}
+	if n == 0 {
+		return nil, done, nil
+	}
+	return b.NewRecord(), done, nil
+}
+
+// arrowSchema returns the arrow schema for the results of a plan operator.
+func arrowSchema(schema planner_types.Schema) (*arrow.Schema, error) {
+	fields := make([]arrow.Field, len(schema))
+	for i, col := range schema {
+		dt, err := arrowType(col.Type)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = arrow.Field{Name: col.ColumnName, Type: dt, Nullable: true}
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+// arrowType returns the arrow type of a sql3 type. Decimals keep their
+// scale, and timestamps are in microseconds, which covers the full range
+// of sql3 timestamps.
+func arrowType(typ parser.ExprDataType) (arrow.DataType, error) {
+	switch t := typ.(type) {
+	case *parser.DataTypeID, *parser.DataTypeInt:
+		return arrow.PrimitiveTypes.Int64, nil
+	case *parser.DataTypeBool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case *parser.DataTypeDecimal:
+		return &arrow.Decimal128Type{Precision: 19, Scale: int32(t.Scale)}, nil
+	case *parser.DataTypeString, *parser.DataTypeGeo:
+		return arrow.BinaryTypes.String, nil
+	case *parser.DataTypeTimestamp:
+		return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, nil
+	case *parser.DataTypeIDSet, *parser.DataTypeIDSetQuantum:
+		return arrow.ListOf(arrow.PrimitiveTypes.Int64), nil
+	case *parser.DataTypeStringSet, *parser.DataTypeStringSetQuantum:
+		return arrow.ListOf(arrow.BinaryTypes.String), nil
+	case *parser.DataTypeVoid:
+		return arrow.Null, nil
+	default:
+		return nil, errors.Errorf("unsupported type '%s'", typ.TypeDescription())
+	}
+}
+
+// appendArrowValue appends a sql3 row value to the builder for its column.
+func appendArrowValue(fb array.Builder, value interface{}) error {
+	if value == nil {
+		fb.AppendNull()
+		return nil
+	}
+	switch b := fb.(type) {
+	case *array.Int64Builder:
+		switch v := value.(type) {
+		case int64:
+			b.Append(v)
+			return nil
+		case uint64:
+			b.Append(int64(v))
+			return nil
+		}
+	case *array.BooleanBuilder:
+		if v, ok := value.(bool); ok {
+			b.Append(v)
+			return nil
+		}
+	case *array.Decimal128Builder:
+		scale := int64(b.Type().(*arrow.Decimal128Type).Scale)
+		switch v := value.(type) {
+		case pql.Decimal:
+			b.Append(decimal128.FromI64(v.ToInt64(scale)))
+			return nil
+		case *pql.Decimal:
+			b.Append(decimal128.FromI64(v.ToInt64(scale)))
+			return nil
+		}
+	case *array.StringBuilder:
+		switch v := value.(type) {
+		case string:
+			b.Append(v)
+			return nil
+		case pilosa.GeoPoint:
+			b.Append(v.String())
+			return nil
+		}
+	case *array.TimestampBuilder:
+		if v, ok := value.(time.Time); ok {
+			b.Append(arrow.Timestamp(v.UnixMicro()))
+			return nil
+		}
+	case *array.ListBuilder:
+		switch vb := b.ValueBuilder().(type) {
+		case *array.Int64Builder:
+			switch v := value.(type) {
+			case []int64:
+				b.Append(true)
+				vb.AppendValues(v, nil)
+				return nil
+			case []uint64:
+				b.Append(true)
+				for _, id := range v {
+					vb.Append(int64(id))
+				}
+				return nil
+			}
+		case *array.StringBuilder:
+			if v, ok := value.([]string); ok {
+				b.Append(true)
+				vb.AppendValues(v, nil)
+				return nil
+			}
+		}
+	case *array.NullBuilder:
+		b.AppendNull()
+		return nil
+	}
+	return errors.Errorf("unexpected value '%T' for arrow type '%s'", value, fb.Type())
+}
+
+// arrowParameterValue returns the value at row i of an arrow array, in the
+// form the planner takes for the value of a positional parameter.
+func arrowParameterValue(arr arrow.Array, i int) (interface{}, error) {
+	if arr.IsNull(i) {
+		return nil, nil
+	}
+	switch a := arr.(type) {
+	case *array.Boolean:
+		return a.Value(i), nil
+	case *array.Int8:
+		return int64(a.Value(i)), nil
+	case *array.Int16:
+		return int64(a.Value(i)), nil
+	case *array.Int32:
+		return int64(a.Value(i)), nil
+	case *array.Int64:
+		return a.Value(i), nil
+	case *array.Uint8:
+		return int64(a.Value(i)), nil
+	case *array.Uint16:
+		return int64(a.Value(i)), nil
+	case *array.Uint32:
+		return int64(a.Value(i)), nil
+	case *array.Uint64:
+		if a.Value(i) > math.MaxInt64 {
+			return nil, errors.Errorf("value %d out of range", a.Value(i))
+		}
+		return int64(a.Value(i)), nil
+	case *array.Float32:
+		return float64(a.Value(i)), nil
+	case *array.Float64:
+		return a.Value(i), nil
+	case *array.String:
+		return a.Value(i), nil
+	case *array.Timestamp:
+		unit := a.DataType().(*arrow.TimestampType).Unit
+		return a.Value(i).ToTime(unit).UTC().Format(time.RFC3339Nano), nil
+	case *array.List:
+		offsets := a.Offsets()
+		values := make([]interface{}, 0, offsets[i+1]-offsets[i])
+		for j := offsets[i]; j < offsets[i+1]; j++ {
+			v, err := arrowParameterValue(a.ListValues(), int(j))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		return values, nil
+	}
+	return nil, errors.Errorf("unsupported arrow type '%s'", arr.DataType())
+}
+
+// likePattern returns a regular expression for a sql LIKE pattern, in which
+// '%' matches any string and '_' any single character.
+func likePattern(pattern string) (*regexp.Regexp, error) {
+	var buf strings.Builder
+	buf.WriteString("(?i)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			buf.WriteString(".*")
+		case '_':
+			buf.WriteString(".")
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	buf.WriteString("$")
+	return regexp.Compile(buf.String())
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v10/arrow/flight/flightsql"
+	"github.com/featurebasedb/featurebase/v3/authn"
+	"github.com/featurebasedb/featurebase/v3/authz"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flightSQLPrepareRequest is a request to prepare or close a statement.
+type flightSQLPrepareRequest struct {
+	query  string
+	handle []byte
+}
+
+func (r flightSQLPrepareRequest) GetQuery() string                   { return r.query }
+func (r flightSQLPrepareRequest) GetPreparedStatementHandle() []byte { return r.handle }
+
+var (
+	_ flightsql.ActionCreatePreparedStatementRequest = flightSQLPrepareRequest{}
+	_ flightsql.ActionClosePreparedStatementRequest  = flightSQLPrepareRequest{}
+)
+
+func TestFlightSQLServer_PreparedStatements(t *testing.T) {
+	now := time.Now()
+	s := NewFlightSQLServer(nil).WithPerms(&authz.GroupPermissions{Admin: "admins"})
+	s.now = func() time.Time { return now }
+
+	userCtx := func(userID string) context.Context {
+		ctx, err := s.authorize(authn.WithUserInfo(context.Background(), &authn.UserInfo{
+			UserID: userID,
+			Groups: []authn.Group{{GroupID: "admins"}},
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ctx
+	}
+	alice, bob := userCtx("alice"), userCtx("bob")
+
+	result, err := s.CreatePreparedStatement(alice, flightSQLPrepareRequest{query: "select 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handle := result.Handle
+
+	t.Run("Owner", func(t *testing.T) {
+		if _, _, err := s.preparedStatement(bob, handle); status.Code(err) != codes.NotFound {
+			t.Fatalf("expected another user's statement to be not found, got %v", err)
+		} else if err := s.ClosePreparedStatement(bob, flightSQLPrepareRequest{handle: handle}); status.Code(err) != codes.NotFound {
+			t.Fatalf("expected another user to be unable to close the statement, got %v", err)
+		} else if sql, _, err := s.preparedStatement(alice, handle); err != nil || sql != "select 1" {
+			t.Fatalf("expected alice's statement, got %q, %v", sql, err)
+		}
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		ctx := authn.WithUserInfo(context.Background(), &authn.UserInfo{UserID: "alice"})
+		if err := s.ClosePreparedStatement(ctx, flightSQLPrepareRequest{handle: handle}); status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("expected permission denied, got %v", err)
+		}
+	})
+
+	t.Run("Max", func(t *testing.T) {
+		for len(s.prepared) < flightSQLMaxPreparedStatements {
+			if _, err := s.CreatePreparedStatement(bob, flightSQLPrepareRequest{query: "select 2"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := s.CreatePreparedStatement(bob, flightSQLPrepareRequest{query: "select 2"}); status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("expected too many prepared statements, got %v", err)
+		}
+	})
+
+	t.Run("IdleTimeout", func(t *testing.T) {
+		// using a statement keeps it
+		now = now.Add(flightSQLPreparedStatementIdleTimeout / 2)
+		if _, _, err := s.preparedStatement(alice, handle); err != nil {
+			t.Fatal(err)
+		}
+
+		now = now.Add(flightSQLPreparedStatementIdleTimeout/2 + time.Second)
+		if _, _, err := s.preparedStatement(alice, handle); err != nil {
+			t.Fatalf("expected the statement to still be prepared, got %v", err)
+		} else if len(s.prepared) != 1 {
+			t.Fatalf("expected idle statements to be closed, got %d", len(s.prepared))
+		} else if _, err := s.CreatePreparedStatement(bob, flightSQLPrepareRequest{query: "select 2"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/flight"
+	"github.com/apache/arrow/go/v10/arrow/flight/flightsql"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	"github.com/featurebasedb/featurebase/v3/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestFlightSQL(t *testing.T) {
+	m := test.RunCommand(t)
+	defer m.Close()
+
+	uri := m.Server.GRPCURI()
+	client, err := flightsql.NewClient(fmt.Sprintf("%s:%d", uri.Host, uri.Port), nil, nil, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	// readAll fetches the results of a FlightInfo, returning their schema
+	// and record batches.
+	readAll := func(t *testing.T, info *flight.FlightInfo) (*arrow.Schema, []arrow.Record) {
+		t.Helper()
+		rdr, err := client.DoGet(ctx, info.Endpoint[0].Ticket)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rdr.Release()
+		var recs []arrow.Record
+		for rdr.Next() {
+			rec := rdr.Record()
+			rec.Retain()
+			recs = append(recs, rec)
+		}
+		if err := rdr.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return rdr.Schema(), recs
+	}
+
+	if _, err := client.ExecuteUpdate(ctx, `create table flight_test (_id id, a int, d decimal(2), s string, ss stringset)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ExecuteUpdate(ctx, `insert into flight_test (_id, a, d, s, ss) values (1, 10, 1.25, 'x', ['p', 'q']), (2, 20, 2.5, 'y', ['q']), (3, 30, null, 'x', ['r'])`); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Statement", func(t *testing.T) {
+		info, err := client.Execute(ctx, `select _id, a, d, s, ss from flight_test`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		schema, recs := readAll(t, info)
+		defer func() {
+			for _, rec := range recs {
+				rec.Release()
+			}
+		}()
+
+		if got, exp := schema.Field(2).Type.String(), (&arrow.Decimal128Type{Precision: 19, Scale: 2}).String(); got != exp {
+			t.Fatalf("expected decimal type %s, got %s", exp, got)
+		}
+		if len(recs) != 1 || recs[0].NumRows() != 3 {
+			t.Fatalf("expected a single batch of 3 rows, got %d batches", len(recs))
+		}
+		rec := recs[0]
+		if ids := rec.Column(0).(*array.Int64).Int64Values(); ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+			t.Fatalf("unexpected ids: %v", ids)
+		}
+		if d := rec.Column(2).(*array.Decimal128); d.Value(0).LowBits() != 125 || !d.IsNull(2) {
+			t.Fatalf("unexpected decimals: %v", d)
+		}
+		if s := rec.Column(3).(*array.String); s.Value(1) != "y" {
+			t.Fatalf("unexpected strings: %v", s)
+		}
+		if ss := rec.Column(4).(*array.List); ss.Len() != 3 || ss.ListValues().Len() != 4 {
+			t.Fatalf("unexpected string sets: %v", ss)
+		}
+	})
+
+	t.Run("StatementError", func(t *testing.T) {
+		if _, err := client.Execute(ctx, `select * from flight_missing`); err == nil {
+			t.Fatal("expected error for unknown table")
+		}
+	})
+
+	t.Run("PreparedStatement", func(t *testing.T) {
+		prep, err := client.Prepare(ctx, memory.DefaultAllocator, `select _id from flight_test where a > $1 and s = $2`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer prep.Close(ctx)
+
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "a", Type: arrow.PrimitiveTypes.Int64},
+			{Name: "s", Type: arrow.BinaryTypes.String},
+		}, nil)
+		b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+		defer b.Release()
+		b.Field(0).(*array.Int64Builder).Append(5)
+		b.Field(1).(*array.StringBuilder).Append("x")
+		params := b.NewRecord()
+		defer params.Release()
+		prep.SetParameters(params)
+
+		info, err := prep.Execute(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, recs := readAll(t, info)
+		var n int64
+		for _, rec := range recs {
+			n += rec.NumRows()
+			rec.Release()
+		}
+		if n != 2 {
+			t.Fatalf("expected 2 rows, got %d", n)
+		}
+	})
+
+	t.Run("Tables", func(t *testing.T) {
+		pattern := "flight%"
+		info, err := client.GetTables(ctx, &flightsql.GetTablesOpts{TableNameFilterPattern: &pattern, IncludeSchema: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, recs := readAll(t, info)
+		defer func() {
+			for _, rec := range recs {
+				rec.Release()
+			}
+		}()
+		if len(recs) != 1 || recs[0].NumRows() != 1 {
+			t.Fatalf("expected one table, got %v", recs)
+		}
+		if name := recs[0].Column(2).(*array.String).Value(0); name != "flight_test" {
+			t.Fatalf("expected table flight_test, got %s", name)
+		}
+		tblSchema, err := flight.DeserializeSchema(recs[0].Column(4).(*array.Binary).Value(0), memory.DefaultAllocator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tblSchema.Fields()) != 5 {
+			t.Fatalf("expected 5 columns, got %v", tblSchema)
+		}
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/apache/arrow/go/v10/arrow/flight/flightsql"
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/authn"
 	"github.com/featurebasedb/featurebase/v3/authz"
@@ -132,9 +133,45 @@ func errToStatusError(err error) error {
 	return status.Error(codes.Unknown, err.Error())
 }
 
-func (h *GRPCHandler) execSQL(ctx context.Context, queryStr string) (pb.ToRowser, error) {
+func (h *GRPCHandler) execSQL(ctx context.Context, req *pb.QuerySQLRequest) (pb.ToRowser, error) {
 	pilosa.CounterSQLQueries.Inc()
-	return execSQL(ctx, h.api, h.logger, queryStr)
+	if len(req.Parameters) > 0 {
+		return execPreparedSQL(ctx, h.api, req.Sql, req.Parameters)
+	}
+	return execSQL(ctx, h.api, h.logger, req.Sql)
+}
+
+// authorizeSQL returns the context to execute req with for the user in ctx.
+// Parameterized queries are run by the sql3 planner, which, as on the http
+// endpoint, requires admin permission.
+func (h *GRPCHandler) authorizeSQL(ctx context.Context, uinfo *authn.UserInfo, req *pb.QuerySQLRequest) (context.Context, error) {
+	if len(req.Parameters) > 0 {
+		if !h.perms.IsAdmin(uinfo.Groups) {
+			return nil, status.Error(codes.PermissionDenied, "insufficient permissions to run parameterized queries")
+		}
+		return authn.WithAdmin(ctx, true), nil
+	}
+
+	m := sql.NewMapper()
+	parsed, err := m.MapSQL(req.Sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing SQL")
+	}
+
+	perm := authz.Read
+	switch parsed.Statement.(type) {
+	case *sqlparser.DDL: // currently only used for DropTable
+		perm = authz.Admin
+	}
+
+	allowed := h.perms.GetAuthorizedIndexList(uinfo.Groups, perm)
+	if !h.perms.IsAdmin(uinfo.Groups) {
+		if !isAllowed(parsed.Tables, allowed) {
+			return nil, status.Error(codes.PermissionDenied, "insufficient permissions to access requested tables")
+		}
+		ctx = authn.WithIndexes(ctx, allowed)
+	}
+	return ctx, nil
 }
 
 func isAllowed(requested []string, allowed []string) bool {
@@ -161,24 +198,9 @@ func (h *GRPCHandler) QuerySQL(req *pb.QuerySQLRequest, stream pb.Pilosa_QuerySQ
 	ctx := stream.Context()
 	if uinfo, ok := authn.GetUserInfo(ctx); ok && uinfo != nil {
 		// authz
-		m := sql.NewMapper()
-		parsed, err := m.MapSQL(req.Sql)
-		if err != nil {
-			return errors.Wrap(err, "parsing SQL")
-		}
-
-		perm := authz.Read
-		switch parsed.Statement.(type) {
-		case *sqlparser.DDL: // currently only used for DropTable
-			perm = authz.Admin
-		}
-
-		allowed := h.perms.GetAuthorizedIndexList(uinfo.Groups, perm)
-		if !h.perms.IsAdmin(uinfo.Groups) {
-			if !isAllowed(parsed.Tables, allowed) {
-				return status.Error(codes.PermissionDenied, "insufficient permissions to access requested tables")
-			}
-			ctx = authn.WithIndexes(ctx, allowed)
+		var err error
+		if ctx, err = h.authorizeSQL(ctx, uinfo, req); err != nil {
+			return err
 		}
 		LogQuery(ctx, "QuerySQL", req, h.queryLogger)
 	}
@@ -187,7 +209,7 @@ func (h *GRPCHandler) QuerySQL(req *pb.QuerySQLRequest, stream pb.Pilosa_QuerySQ
 
 	span.SetTag("SQL Query", req.Sql)
 	start := time.Now()
-	results, err := h.execSQL(ctx, req.Sql)
+	results, err := h.execSQL(ctx, req)
 	duration := time.Since(start)
 	monitor.Finish(span)
 	if err != nil {
@@ -224,28 +246,13 @@ func (h *GRPCHandler) QuerySQLUnary(ctx context.Context, req *pb.QuerySQLRequest
 	start := time.Now()
 	if uinfo, _ := authn.GetUserInfo(ctx); uinfo != nil {
 		// authz
-		m := sql.NewMapper()
-		parsed, err := m.MapSQL(req.Sql)
-		if err != nil {
-			return nil, errors.Wrap(err, "parsing SQL")
-		}
-
-		perm := authz.Read
-		switch parsed.Statement.(type) {
-		case *sqlparser.DDL: // currently only used for DropTable
-			perm = authz.Admin
-		}
-
-		allowed := h.perms.GetAuthorizedIndexList(uinfo.Groups, perm)
-		if !h.perms.IsAdmin(uinfo.Groups) {
-			if !isAllowed(parsed.Tables, allowed) {
-				return nil, status.Error(codes.PermissionDenied, "insufficient permissions to access requested tables")
-			}
-			ctx = authn.WithIndexes(ctx, allowed)
+		var err error
+		if ctx, err = h.authorizeSQL(ctx, uinfo, req); err != nil {
+			return nil, err
 		}
 	}
 
-	results, err := h.execSQL(ctx, req.Sql)
+	results, err := h.execSQL(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -1629,6 +1636,13 @@ func NewGRPCServer(opts ...grpcServerOption) (*grpcServer, error) {
 	pb.RegisterPilosaServer(server.grpcServer, grpcHandler)
 	vdsm_pb.RegisterMoleculaServer(server.grpcServer, NewVDSMGRPCHandler(grpcHandler, server.api).WithLogger(server.logger))
 
+	// serve Arrow Flight SQL on the same listener
+	flightSQLServer := NewFlightSQLServer(server.api).WithLogger(server.logger)
+	if server.perms != nil {
+		flightSQLServer = flightSQLServer.WithPerms(server.perms)
+	}
+	registerFlightService(server.grpcServer, flightsql.NewFlightServerWithAllocator(flightSQLServer, flightSQLServer.mem))
+
 	// register the server so its services are available to grpc_cli and others
 	reflection.Register(server.grpcServer)
 
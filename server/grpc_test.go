@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -1078,6 +1079,69 @@ func TestQuerySQL(t *testing.T) {
 	}
 }
 
+func TestQuerySQLParameters(t *testing.T) {
+	stream := &MockServerTransportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	m := test.RunCommand(t)
+	defer m.Close()
+	gh := server.NewGRPCHandler(m.API)
+
+	idx := m.MustCreateIndex(t, "params", pilosa.IndexOptions{Keys: false, TrackExistence: true})
+	m.MustCreateField(t, idx.Name(), "age", pilosa.OptFieldTypeInt(0, 100))
+	for id, age := range map[int]int{1: 27, 2: 16, 5: 16, 8: 16, 9: 16, 10: 31} {
+		if _, err := gh.QueryPQLUnary(ctx, &pb.QueryPQLRequest{
+			Index: idx.Name(),
+			Pql:   fmt.Sprintf(`Set(%d, age=%d)`, id, age),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Bound", func(t *testing.T) {
+		resp, err := gh.QuerySQLUnary(ctx, &pb.QuerySQLRequest{
+			Sql:        "select _id, age from params where age = $1 and _id > $2",
+			Parameters: []string{"16", "2"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]int64, 0)
+		for _, row := range resp.Rows {
+			if age := row.Columns[1].GetInt64Val(); age != 16 {
+				t.Fatalf("expected age 16, got %d", age)
+			}
+			ids = append(ids, row.Columns[0].GetInt64Val())
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		if !reflect.DeepEqual(ids, []int64{5, 8, 9}) {
+			t.Fatalf("unexpected ids: %v", ids)
+		}
+		if len(resp.Headers) != 2 || resp.Headers[0].Name != "_id" || resp.Headers[1].Name != "age" {
+			t.Fatalf("unexpected headers: %v", resp.Headers)
+		}
+	})
+
+	t.Run("InvalidParameter", func(t *testing.T) {
+		_, err := gh.QuerySQLUnary(ctx, &pb.QuerySQLRequest{
+			Sql:        "select _id from params where age = $1",
+			Parameters: []string{"{"},
+		})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument, got %v", err)
+		}
+	})
+
+	t.Run("ParameterCount", func(t *testing.T) {
+		_, err := gh.QuerySQLUnary(ctx, &pb.QuerySQLRequest{
+			Sql:        "select _id from params where age = $1 and _id = $2",
+			Parameters: []string{"16"},
+		})
+		if err == nil || !strings.Contains(err.Error(), "statement takes 2 parameter(s), 1 given") {
+			t.Fatalf("expected parameter count error, got %v", err)
+		}
+	})
+}
+
 func TestQuerySQLWithError(t *testing.T) {
 
 	stream := &MockServerTransportStream{}
@@ -1439,6 +1439,32 @@ func TestClusterTranslator(t *testing.T) {
 	}
 }
 
+func TestResourcePoolMaxQueryMemory(t *testing.T) {
+	cluster := test.MustRunUnsharedCluster(t, 1, []server.CommandOption{
+		server.OptCommandServerOptions(
+			pilosa.OptServerResourcePools([]pilosa.ResourcePool{
+				{Name: pilosa.DefaultResourcePoolName, MaxQueryMemory: 64},
+			}),
+		),
+	})
+	defer cluster.Close()
+
+	node := cluster.GetNode(0)
+	test.Do(t, "POST", node.URL()+"/index/i0", "")
+	test.Do(t, "POST", node.URL()+"/index/i0/field/f0", "")
+	test.Do(t, "POST", node.URL()+"/index/i0/query", "Set(1, f0=1) Set(2, f0=2) Set(3, f0=3)")
+
+	// The results of counting fit in the max query memory, those of grouping
+	// don't.
+	if resp := test.Do(t, "POST", node.URL()+"/index/i0/query", "Count(All())"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", resp.StatusCode, resp.Body)
+	}
+	resp := test.Do(t, "POST", node.URL()+"/index/i0/query", "GroupBy(Rows(f0))")
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(resp.Body, "query results exceeded the max query memory") {
+		t.Fatalf("expected max query memory error, got %d: %s", resp.StatusCode, resp.Body)
+	}
+}
+
 func TestQueryHistory(t *testing.T) {
 	cluster := test.MustRunCluster(t, 3,
 		[]server.CommandOption{
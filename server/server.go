@@ -34,6 +34,7 @@ import (
 	"github.com/featurebasedb/featurebase/v3/dax/storage"
 	"github.com/featurebasedb/featurebase/v3/disco"
 	"github.com/featurebasedb/featurebase/v3/encoding/proto"
+	"github.com/featurebasedb/featurebase/v3/encryption"
 	petcd "github.com/featurebasedb/featurebase/v3/etcd"
 	"github.com/featurebasedb/featurebase/v3/gcnotify"
 	"github.com/featurebasedb/featurebase/v3/gopsutil"
@@ -132,6 +133,9 @@ func OptCommandConfig(config *Config) CommandOption {
 			c.Config.Auth = config.Auth
 			c.Config.TLS = config.TLS
 			c.Config.ControllerAddress = config.ControllerAddress
+			c.Config.Encryption = config.Encryption
+			c.Config.ChangeCapture = config.ChangeCapture
+			c.Config.ResourcePools = config.ResourcePools
 			return nil
 		}
 		c.Config = config
@@ -578,6 +582,21 @@ func (m *Command) setupServer() error {
 		return planner.NewExecutionPlanner(e, fapi, fsapi, m.Server.SystemLayer, imp, m.logger, sql)
 	}
 
+	cipher, err := encryption.Open(m.Config.Encryption)
+	if err != nil {
+		return errors.Wrap(err, "opening encryption keyring")
+	}
+	resourcePools, err := m.Config.resourcePools()
+	if err != nil {
+		return errors.Wrap(err, "configuring resource pools")
+	}
+
+	openTranslateStore, openIDAllocator := pilosa.OpenTranslateStore, pilosa.OpenIDAllocator
+	if cipher != nil {
+		openTranslateStore = pilosa.OpenEncryptedTranslateStore(cipher)
+		openIDAllocator = pilosa.OpenEncryptedIDAllocator(cipher)
+	}
+
 	serverOptions := []pilosa.ServerOption{
 		pilosa.OptServerLongQueryTime(time.Duration(longQueryTime)),
 		pilosa.OptServerDataDir(m.Config.DataDir),
@@ -586,9 +605,9 @@ func (m *Command) setupServer() error {
 		pilosa.OptServerMetricInterval(time.Duration(m.Config.Metric.PollInterval)),
 		pilosa.OptServerDiagnosticsInterval(diagnosticsInterval),
 		pilosa.OptServerExecutorPoolSize(m.Config.WorkerPoolSize),
-		pilosa.OptServerOpenTranslateStore(pilosa.OpenTranslateStore),
+		pilosa.OptServerOpenTranslateStore(openTranslateStore),
 		pilosa.OptServerOpenTranslateReader(pilosa.GetOpenTranslateReaderWithLockerFunc(c, &sync.Mutex{})),
-		pilosa.OptServerOpenIDAllocator(pilosa.OpenIDAllocator),
+		pilosa.OptServerOpenIDAllocator(openIDAllocator),
 		pilosa.OptServerLogger(m.logger),
 		pilosa.OptServerQueryLogger(m.queryLogger),
 		pilosa.OptServerSystemInfo(gopsutil.NewSystemInfo()),
@@ -599,13 +618,18 @@ func (m *Command) setupServer() error {
 		pilosa.OptServerSerializer(proto.Serializer{}),
 		pilosa.OptServerStorageConfig(m.Config.Storage),
 		pilosa.OptServerRBFConfig(m.Config.RBFConfig),
+		pilosa.OptServerCipher(cipher),
+		pilosa.OptServerChangeCapture(m.Config.ChangeCapture),
 		pilosa.OptServerMaxQueryMemory(m.Config.MaxQueryMemory),
+		pilosa.OptServerResourcePools(resourcePools),
 		pilosa.OptServerQueryHistoryLength(m.Config.QueryHistoryLength),
 		pilosa.OptServerPartitionAssigner(m.Config.Cluster.PartitionToNodeAssignment),
 		pilosa.OptServerExecutionPlannerFn(executionPlannerFn),
 		pilosa.OptServerServerlessStorage(m.serverlessStorage),
 		pilosa.OptServerIsDataframeEnabled(m.Config.Dataframe.Enable),
 		pilosa.OptServerDataframeUseParquet(m.Config.Dataframe.UseParquet),
+		pilosa.OptServerExportDirectory(m.Config.Export.Directory),
+		pilosa.OptServerExportURLs(m.Config.Export.AllowedURLs),
 		pilosa.OptServerVerChkAddress(m.Config.VerChkAddress),
 		pilosa.OptServerUUIDFile(m.Config.UUIDFile),
 	}
@@ -4,12 +4,20 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
 	"github.com/featurebasedb/featurebase/v3/logger"
+	"github.com/featurebasedb/featurebase/v3/pql"
 	pb "github.com/featurebasedb/featurebase/v3/proto"
 	"github.com/featurebasedb/featurebase/v3/sql"
+	planner_types "github.com/featurebasedb/featurebase/v3/sql3/planner/types"
 	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -37,3 +45,95 @@ func execSQL(ctx context.Context, api *pilosa.API, logger logger.Logger, querySt
 	}
 	return results, errors.Wrap(err, "failed to start SQL query")
 }
+
+// execPreparedSQL compiles queryStr, binding parameters to its positional
+// parameters, with the sql3 planner. Each parameter is the json encoding of
+// its value.
+func execPreparedSQL(ctx context.Context, api *pilosa.API, queryStr string, parameters []string) (pb.ToRowser, error) {
+	values := make([]interface{}, len(parameters))
+	for i, parameter := range parameters {
+		dec := json.NewDecoder(strings.NewReader(parameter))
+		dec.UseNumber()
+		if err := dec.Decode(&values[i]); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "decoding parameter $%d: %v", i+1, err)
+		}
+	}
+	requestID, err := uuid.NewV4()
+	if err != nil {
+		return nil, errors.Wrap(err, "generating request id")
+	}
+	ctx = fbcontext.WithRequestID(ctx, requestID.String())
+	op, err := api.CompilePreparedPlan(ctx, queryStr, values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compile SQL query")
+	}
+	return &planOperatorRowser{ctx: ctx, op: op}, nil
+}
+
+// planOperatorRowser implements pb.ToRowser for the rows of a sql3 plan
+// operator.
+type planOperatorRowser struct {
+	ctx context.Context
+	op  planner_types.PlanOperator
+}
+
+// ToRows implements the ToRowser interface.
+func (r *planOperatorRowser) ToRows(callback func(*pb.RowResponse) error) error {
+	iter, err := r.op.Iterator(r.ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "getting iterator")
+	}
+	schema := r.op.Schema()
+	headers := make([]*pb.ColumnInfo, len(schema))
+	for i, col := range schema {
+		headers[i] = &pb.ColumnInfo{Name: col.ColumnName, Datatype: col.Type.TypeDescription()}
+	}
+	for {
+		row, err := iter.Next(r.ctx)
+		if err == planner_types.ErrNoMoreRows {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		columns := make([]*pb.ColumnResponse, len(row))
+		for i, value := range row {
+			columns[i] = columnResponse(value)
+		}
+		if err := callback(&pb.RowResponse{Headers: headers, Columns: columns}); err != nil {
+			return err
+		}
+		// Only the first row carries the headers.
+		headers = nil
+	}
+}
+
+// columnResponse converts a sql3 row value to a ColumnResponse.
+func columnResponse(value interface{}) *pb.ColumnResponse {
+	switch v := value.(type) {
+	case nil:
+		return &pb.ColumnResponse{}
+	case bool:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_BoolVal{BoolVal: v}}
+	case int64:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_Int64Val{Int64Val: v}}
+	case float64:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_Float64Val{Float64Val: v}}
+	case string:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_StringVal{StringVal: v}}
+	case pql.Decimal:
+		value := v.Value()
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_DecimalVal{DecimalVal: &pb.Decimal{Value: value.Int64(), Scale: v.Scale}}}
+	case time.Time:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_TimestampVal{TimestampVal: v.UTC().Format(time.RFC3339Nano)}}
+	case []int64:
+		vals := make([]uint64, len(v))
+		for i := range v {
+			vals[i] = uint64(v[i])
+		}
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_Uint64ArrayVal{Uint64ArrayVal: &pb.Uint64Array{Vals: vals}}}
+	case []string:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_StringArrayVal{StringArrayVal: &pb.StringArray{Vals: v}}}
+	default:
+		return &pb.ColumnResponse{ColumnVal: &pb.ColumnResponse_StringVal{StringVal: fmt.Sprint(v)}}
+	}
+}
@@ -3,14 +3,22 @@
 package pilosa_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/rbf"
+	"github.com/featurebasedb/featurebase/v3/server"
 	"github.com/featurebasedb/featurebase/v3/test"
 )
 
@@ -283,3 +291,235 @@ func TestViewsRemovalStandard(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_EncryptionAtRest(t *testing.T) {
+	config := server.NewConfig()
+	config.Encryption.Keyfile = filepath.Join(t.TempDir(), "keys")
+	if err := os.WriteFile(config.Encryption.Keyfile, []byte("1 000102030405060708090a0b0c0d0e0f\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := test.MustRunUnsharedCluster(t, 1, []server.CommandOption{server.OptCommandConfig(config)})
+	defer c.Close()
+
+	c.CreateField(t, "i", pilosa.IndexOptions{}, "f")
+	c.Query(t, "i", `Set(1, f=10) Set(2, f=10)`)
+	if res := c.Query(t, "i", `Count(Row(f=10))`); res.Results[0] != uint64(2) {
+		t.Fatalf("unexpected count: %v", res.Results[0])
+	}
+
+	// every RBF data file is encrypted, so none starts with the RBF magic.
+	var n int
+	if err := filepath.Walk(c.GetPrimary().Server.Holder().Path(), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.Name() != "data" || !strings.HasPrefix(filepath.Base(filepath.Dir(path)), "shard.") {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		} else if bytes.HasPrefix(data, []byte(rbf.Magic)) {
+			t.Fatalf("expected %s to be encrypted", path)
+		}
+		n++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	} else if n == 0 {
+		t.Fatal("expected to find RBF data files")
+	}
+}
+
+func TestServer_ChangeCapture(t *testing.T) {
+	config := server.NewConfig()
+	config.ChangeCapture.Enabled = true
+
+	c := test.MustRunUnsharedCluster(t, 1, []server.CommandOption{server.OptCommandConfig(config)})
+	defer c.Close()
+
+	c.CreateField(t, "i", pilosa.IndexOptions{TrackExistence: true}, "f")
+	c.Query(t, "i", `Set(1, f=10)`)
+	c.Query(t, "i", `Set(1, f=11)`)
+	c.Query(t, "i", `Clear(1, f=10)`)
+	c.Query(t, "i", `Set(2, f=10)`)
+	c.Query(t, "i", `Delete(Row(f=10))`)
+
+	api := c.GetPrimary().API
+	events, err := api.Changes(context.Background(), "i", 0, 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []pilosa.ChangeOp{pilosa.ChangeOpInsert, pilosa.ChangeOpUpdate, pilosa.ChangeOpClear, pilosa.ChangeOpInsert, pilosa.ChangeOpDelete}
+	var ops []pilosa.ChangeOp
+	for _, ev := range events {
+		ops = append(ops, ev.Op)
+	}
+	if !reflect.DeepEqual(ops, exp) {
+		t.Fatalf("unexpected ops: %v, expected %v", ops, exp)
+	}
+	if cols := events[4].Columns; !reflect.DeepEqual(cols, []uint64{2}) {
+		t.Fatalf("unexpected deleted columns: %v", cols)
+	}
+
+	t.Run("HTTP", func(t *testing.T) {
+		resp, err := http.Get(c.GetPrimary().URL() + "/index/i/changes?since=3&limit=1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body struct {
+			Events []*pilosa.ChangeEvent `json:"events"`
+			Next   uint64                `json:"next"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if len(body.Events) != 1 || body.Events[0].Offset != 4 || body.Next != 4 {
+			t.Fatalf("unexpected response: %+v", body)
+		}
+	})
+
+	t.Run("SQL", func(t *testing.T) {
+		for _, sql := range []string{
+			"create table cdc (_id string, s string)",
+			"insert into cdc (_id, s) values ('a', 'x'), ('b', 'y')",
+			"insert into cdc (_id, s) values ('a', 'z')",
+		} {
+			if resp := test.Do(t, "POST", c.GetPrimary().URL()+"/sql", sql); resp.StatusCode != http.StatusOK || strings.Contains(resp.Body, `"error"`) {
+				t.Fatalf("%s: %d %s", sql, resp.StatusCode, resp.Body)
+			}
+		}
+		events, err := api.Changes(context.Background(), "cdc", 0, 100, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the keys are in different shards, so each insert is an event
+		if len(events) != 3 {
+			t.Fatalf("unexpected events: %+v", events)
+		}
+		inserted := map[string]bool{}
+		for _, ev := range events[:2] {
+			if ev.Op != pilosa.ChangeOpInsert || !reflect.DeepEqual(ev.Fields, []string{"s"}) || len(ev.ColumnKeys) != 1 {
+				t.Fatalf("unexpected insert: %+v", ev)
+			}
+			inserted[ev.ColumnKeys[0]] = true
+		}
+		if !inserted["a"] || !inserted["b"] {
+			t.Fatalf("unexpected inserted keys: %v", inserted)
+		}
+		if ev := events[2]; ev.Op != pilosa.ChangeOpUpdate || !reflect.DeepEqual(ev.ColumnKeys, []string{"a"}) {
+			t.Fatalf("unexpected update: %+v", ev)
+		}
+	})
+
+	t.Run("Import", func(t *testing.T) {
+		c.CreateField(t, "imp", pilosa.IndexOptions{TrackExistence: true}, "f")
+		c.ImportBits(t, "imp", "f", [][2]uint64{{1, 1}, {2, 2}})
+		events, err := api.Changes(context.Background(), "imp", 0, 100, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 || !reflect.DeepEqual(events[0].Columns, []uint64{1, 2}) {
+			t.Fatalf("unexpected events: %+v", events)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		c := test.MustRunCluster(t, 1)
+		defer c.Close()
+		c.CreateField(t, c.Idx(), pilosa.IndexOptions{}, "f")
+
+		resp, err := http.Get(c.GetPrimary().URL() + "/index/" + c.Idx() + "/changes")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotImplemented {
+			t.Fatalf("unexpected status: %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestServer_RetentionRemoval(t *testing.T) {
+	c := test.MustRunUnsharedCluster(t, 1)
+	defer c.Close()
+
+	c.CreateField(t, "i", pilosa.IndexOptions{Keys: true, TrackExistence: true}, "ts", pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds))
+	c.CreateField(t, "i", pilosa.IndexOptions{Keys: true, TrackExistence: true}, "f")
+	recent := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	c.Query(t, "i", `Set("old", ts="2001-02-03T04:05:06Z") Set("old", f=1)`)
+	c.Query(t, "i", fmt.Sprintf(`Set("new", ts=%q) Set("new", f=1)`, recent))
+	c.Query(t, "i", `Set("none", f=1)`)
+
+	node := c.GetPrimary()
+	for _, update := range []pilosa.IndexUpdate{
+		{Option: "retentionField", Value: "f"},
+		{Option: "retentionField", Value: "missing"},
+		{Option: "retentionPeriod", Value: "-1h"},
+		{Option: "retentionPeriod", Value: "soon"},
+		{Option: "keys", Value: "false"},
+	} {
+		if err := node.API.UpdateIndex(context.Background(), "i", update); err == nil {
+			t.Fatalf("expected error for update %+v", update)
+		}
+	}
+
+	// Without a full policy, nothing is removed.
+	if err := node.API.UpdateIndex(context.Background(), "i", pilosa.IndexUpdate{Option: "retentionField", Value: "ts"}); err != nil {
+		t.Fatal(err)
+	}
+	node.Server.RetentionRemoval(context.Background())
+	if n := c.Query(t, "i", `Count(All())`).Results[0].(uint64); n != 3 {
+		t.Fatalf("expected 3 records, got %d", n)
+	}
+
+	if err := node.API.UpdateIndex(context.Background(), "i", pilosa.IndexUpdate{Option: "retentionPeriod", Value: "24h"}); err != nil {
+		t.Fatal(err)
+	}
+	if opts := node.API.Holder().Index("i").Options(); opts.RetentionField != "ts" || opts.RetentionPeriod != 24*time.Hour {
+		t.Fatalf("unexpected index options: %+v", opts)
+	}
+	node.Server.RetentionRemoval(context.Background())
+
+	row := c.Query(t, "i", `Row(f=1)`).Results[0].(*pilosa.Row)
+	keys := row.Keys
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"new", "none"}) {
+		t.Fatalf("unexpected records after retention removal: %v", keys)
+	}
+
+	t.Run("SQL", func(t *testing.T) {
+		sql := func(t *testing.T, sql string) string {
+			t.Helper()
+			resp := test.Do(t, "POST", node.URL()+"/sql", sql)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("%s: %d %s", sql, resp.StatusCode, resp.Body)
+			}
+			return resp.Body
+		}
+		if body := sql(t, "create table rt (_id id, ts timestamp, n int) retention ts '24h'"); strings.Contains(body, `"error"`) {
+			t.Fatal(body)
+		}
+		if opts := node.API.Holder().Index("rt").Options(); opts.RetentionField != "ts" || opts.RetentionPeriod != 24*time.Hour {
+			t.Fatalf("unexpected index options: %+v", opts)
+		}
+
+		if body := sql(t, "alter table rt with retention ts '1h'"); strings.Contains(body, `"error"`) {
+			t.Fatal(body)
+		}
+		if opts := node.API.Holder().Index("rt").Options(); opts.RetentionPeriod != time.Hour {
+			t.Fatalf("unexpected index options: %+v", opts)
+		}
+
+		for stmt, exp := range map[string]string{
+			"alter table rt with retention n '1h'":                            "must be a timestamp column",
+			"alter table rt with retention missing '1h'":                      "not found",
+			"alter table rt with retention ts 'soon'":                         "not a valid time duration",
+			"create table rt2 (_id id, ts timestamp) retention ts '-1h'":      "not a valid time duration",
+			"create table rt2 (_id id, ts timestamp) retention missing '24h'": "not found",
+		} {
+			if body := sql(t, stmt); !strings.Contains(body, exp) {
+				t.Fatalf("%s: expected error containing %q, got %s", stmt, exp, body)
+			}
+		}
+	})
+}
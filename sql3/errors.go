@@ -27,6 +27,8 @@ const (
 	ErrTypeIncompatibleWithArithmeticOperator            errors.Code = "ErrTypeIncompatibleWithArithmeticOperator"
 	ErrTypeIncompatibleWithConcatOperator                errors.Code = "ErrTypeIncompatibleWithConcatOperator"
 	ErrTypeIncompatibleWithLikeOperator                  errors.Code = "ErrTypeIncompatibleWithLikeOperator"
+	ErrTypeIncompatibleWithPatternOperator               errors.Code = "ErrTypeIncompatibleWithPatternOperator"
+	ErrInvalidPattern                                    errors.Code = "ErrInvalidPattern"
 	ErrTypeIncompatibleWithBetweenOperator               errors.Code = "ErrTypeIncompatibleWithBetweenOperator"
 	ErrTypeCannotBeUsedAsRangeSubscript                  errors.Code = "ErrTypeCannotBeUsedAsRangeSubscript"
 	ErrTypesAreNotEquatable                              errors.Code = "ErrTypesAreNotEquatable"
@@ -71,6 +73,8 @@ const (
 	ErrInvalidTimeQuantum errors.Code = "ErrInvalidTimeQuantum"
 	ErrInvalidDuration    errors.Code = "ErrInvalidDuration"
 
+	ErrInvalidRetentionColumn errors.Code = "ErrInvalidRetentionColumn"
+
 	ErrInsertExprTargetCountMismatch   errors.Code = "ErrInsertExprTargetCountMismatch"
 	ErrInsertMustHaveIDColumn          errors.Code = "ErrInsertMustHaveIDColumn"
 	ErrInsertMustAtLeastOneNonIDColumn errors.Code = "ErrInsertMustAtLeastOneNonIDColumn"
@@ -95,9 +99,19 @@ const (
 	ErrViewExists   errors.Code = "ErrViewExists"
 	ErrViewNotFound errors.Code = "ErrViewNotFound"
 
+	ErrViewIsMaterialized                 errors.Code = "ErrViewIsMaterialized"
+	ErrViewIsNotMaterialized              errors.Code = "ErrViewIsNotMaterialized"
+	ErrMaterializedViewColumnNameExpected errors.Code = "ErrMaterializedViewColumnNameExpected"
+
 	ErrModelExists   errors.Code = "ErrModelExists"
 	ErrModelNotFound errors.Code = "ErrModelNotFound"
 
+	ErrFunctionExists             errors.Code = "ErrFunctionExists"
+	ErrFunctionBodyInvalid        errors.Code = "ErrFunctionBodyInvalid"
+	ErrFunctionReturnType         errors.Code = "ErrFunctionReturnType"
+	ErrFunctionRecursiveCall      errors.Code = "ErrFunctionRecursiveCall"
+	ErrDuplicateFunctionParameter errors.Code = "ErrDuplicateFunctionParameter"
+
 	ErrBadColumnConstraint         errors.Code = "ErrBadColumnConstraint"
 	ErrConflictingColumnConstraint errors.Code = "ErrConflictingColumnConstraint"
 
@@ -131,6 +145,12 @@ const (
 	ErrParsingJSON             errors.Code = "ErrParsingJSON"
 	ErrEvaluatingJSONPathExpr  errors.Code = "ErrEvaluatingJSONPathExpr"
 
+	// export errors
+	ErrOutputSpecifierExpected errors.Code = "ErrOutputSpecifierExpected"
+	ErrInvalidOutputSpecifier  errors.Code = "ErrInvalidOutputSpecifier"
+	ErrWritingDestination      errors.Code = "ErrWritingDestination"
+	ErrExportNotAllowed        errors.Code = "ErrExportNotAllowed"
+
 	// optimizer errors
 	ErrAggregateNotAllowedInGroupBy errors.Code = "ErrIdPercentileNotAllowedInGroupBy"
 
@@ -153,8 +173,28 @@ const (
 	ErrUnknownQueryHint               errors.Code = "ErrInvalidQueryHint"
 	ErrInvalidQueryHintParameterCount errors.Code = "ErrInvalidQueryHintParameterCount"
 
+	// table samples
+	ErrInvalidTableSampleSize  errors.Code = "ErrInvalidTableSampleSize"
+	ErrTableSampleNotSupported errors.Code = "ErrTableSampleNotSupported"
+
 	// show options
 	ErrUnknownShowOption errors.Code = "ErrUnknownShowOption"
+
+	// transactions
+	ErrTransactionSessionRequired       errors.Code = "ErrTransactionSessionRequired"
+	ErrTransactionInProgress            errors.Code = "ErrTransactionInProgress"
+	ErrNoTransactionInProgress          errors.Code = "ErrNoTransactionInProgress"
+	ErrTransactionAborted               errors.Code = "ErrTransactionAborted"
+	ErrStatementNotAllowedInTransaction errors.Code = "ErrStatementNotAllowedInTransaction"
+
+	// prepared statements
+	ErrPreparedStatementSessionRequired errors.Code = "ErrPreparedStatementSessionRequired"
+	ErrPreparedStatementExists          errors.Code = "ErrPreparedStatementExists"
+	ErrPreparedStatementNotFound        errors.Code = "ErrPreparedStatementNotFound"
+	ErrParametersNotAllowed             errors.Code = "ErrParametersNotAllowed"
+	ErrParameterCount                   errors.Code = "ErrParameterCount"
+	ErrParameterValueLiteralExpected    errors.Code = "ErrParameterValueLiteralExpected"
+	ErrInvalidParameterValue            errors.Code = "ErrInvalidParameterValue"
 )
 
 func NewErrDuplicateColumn(line int, col int, column string) error {
@@ -391,6 +431,20 @@ func NewErrTypeIncompatibleWithLikeOperator(line, col int, operator, type1 strin
 	)
 }
 
+func NewErrTypeIncompatibleWithPatternOperator(line, col int, operator, type1 string) error {
+	return errors.New(
+		ErrTypeIncompatibleWithPatternOperator,
+		fmt.Sprintf("[%d:%d] operator '%s' incompatible with type '%s'", line, col, operator, type1),
+	)
+}
+
+func NewErrInvalidPattern(line, col int, operator, pattern string) error {
+	return errors.New(
+		ErrInvalidPattern,
+		fmt.Sprintf("[%d:%d] invalid pattern '%s' for operator '%s'", line, col, pattern, operator),
+	)
+}
+
 func NewErrTypeIncompatibleWithBetweenOperator(line, col int, operator, type1 string) error {
 	return errors.New(
 		ErrTypeIncompatibleWithBetweenOperator,
@@ -535,6 +589,13 @@ func NewErrInvalidDuration(line, col int, duration string) error {
 	)
 }
 
+func NewErrInvalidRetentionColumn(line, col int, columnName string) error {
+	return errors.New(
+		ErrInvalidRetentionColumn,
+		fmt.Sprintf("[%d:%d] retention column '%s' must be a timestamp column", line, col, columnName),
+	)
+}
+
 func NewErrInsertExprTargetCountMismatch(line int, col int) error {
 	return errors.New(
 		ErrInsertExprTargetCountMismatch,
@@ -591,6 +652,13 @@ func NewErrTableIDColumnAlter(line, col int) error {
 	)
 }
 
+func NewErrTableIDColumnRename(line, col int) error {
+	return errors.New(
+		ErrTableIDColumnAlter,
+		fmt.Sprintf("[%d:%d] _id column cannot be renamed", line, col),
+	)
+}
+
 func NewErrDatabaseNotFound(line, col int, databaseName string) error {
 	return errors.New(
 		ErrDatabaseNotFound,
@@ -668,6 +736,62 @@ func NewErrViewExists(line, col int, viewName string) error {
 	)
 }
 
+func NewErrFunctionExists(line, col int, functionName string) error {
+	return errors.New(
+		ErrFunctionExists,
+		fmt.Sprintf("[%d:%d] function '%s' already exists", line, col, functionName),
+	)
+}
+
+func NewErrFunctionBodyInvalid(line, col int) error {
+	return errors.New(
+		ErrFunctionBodyInvalid,
+		fmt.Sprintf("[%d:%d] function body must be a single RETURN of an expression over the function parameters", line, col),
+	)
+}
+
+func NewErrFunctionReturnType(line, col int, functionName, type1, type2 string) error {
+	return errors.New(
+		ErrFunctionReturnType,
+		fmt.Sprintf("[%d:%d] function '%s' returns an expression of type '%s' which cannot be assigned to type '%s'", line, col, functionName, type1, type2),
+	)
+}
+
+func NewErrFunctionRecursiveCall(line, col int, functionName string) error {
+	return errors.New(
+		ErrFunctionRecursiveCall,
+		fmt.Sprintf("[%d:%d] function '%s' cannot call itself", line, col, functionName),
+	)
+}
+
+func NewErrDuplicateFunctionParameter(line, col int, parameterName string) error {
+	return errors.New(
+		ErrDuplicateFunctionParameter,
+		fmt.Sprintf("[%d:%d] duplicate parameter '%s'", line, col, parameterName),
+	)
+}
+
+func NewErrViewIsMaterialized(line, col int, viewName string) error {
+	return errors.New(
+		ErrViewIsMaterialized,
+		fmt.Sprintf("[%d:%d] '%s' is a materialized view", line, col, viewName),
+	)
+}
+
+func NewErrViewIsNotMaterialized(line, col int, viewName string) error {
+	return errors.New(
+		ErrViewIsNotMaterialized,
+		fmt.Sprintf("[%d:%d] view '%s' is not a materialized view", line, col, viewName),
+	)
+}
+
+func NewErrMaterializedViewColumnNameExpected(line, col int, columnIndex int) error {
+	return errors.New(
+		ErrMaterializedViewColumnNameExpected,
+		fmt.Sprintf("[%d:%d] materialized view column %d must have a name", line, col, columnIndex),
+	)
+}
+
 func NewErrModelNotFound(line, col int, viewName string) error {
 	return errors.New(
 		ErrModelNotFound,
@@ -816,6 +940,34 @@ func NewErrInvalidInputSpecifier(line, col int, specifier string) error {
 	)
 }
 
+func NewErrOutputSpecifierExpected(line, col int) error {
+	return errors.New(
+		ErrOutputSpecifierExpected,
+		fmt.Sprintf("[%d:%d] output specifier expected", line, col),
+	)
+}
+
+func NewErrInvalidOutputSpecifier(line, col int, specifier string) error {
+	return errors.New(
+		ErrInvalidOutputSpecifier,
+		fmt.Sprintf("[%d:%d] invalid output specifier '%s'", line, col, specifier),
+	)
+}
+
+func NewErrWritingDestination(line, col int, destination string, errorText string) error {
+	return errors.New(
+		ErrWritingDestination,
+		fmt.Sprintf("[%d:%d] unable to write destination '%s': %s", line, col, destination, errorText),
+	)
+}
+
+func NewErrExportNotAllowed(line, col int, destination string, reason string) error {
+	return errors.New(
+		ErrExportNotAllowed,
+		fmt.Sprintf("[%d:%d] export to '%s' not allowed: %s", line, col, destination, reason),
+	)
+}
+
 func NewErrInvalidBatchSize(line, col int, batchSize int) error {
 	return errors.New(
 		ErrInvalidBatchSize,
@@ -935,6 +1087,29 @@ func NewErrInvalidQueryHintParameterCount(line, col int, hintName string, desire
 	)
 }
 
+// table samples
+
+func NewErrInvalidTableSamplePercent(line, col int) error {
+	return errors.New(
+		ErrInvalidTableSampleSize,
+		fmt.Sprintf("[%d:%d] table sample percent must be greater than 0 and at most 100", line, col),
+	)
+}
+
+func NewErrInvalidTableSampleRows(line, col int) error {
+	return errors.New(
+		ErrInvalidTableSampleSize,
+		fmt.Sprintf("[%d:%d] table sample row count must be greater than 0", line, col),
+	)
+}
+
+func NewErrTableSampleNotSupported(line, col int, name string) error {
+	return errors.New(
+		ErrTableSampleNotSupported,
+		fmt.Sprintf("[%d:%d] TABLESAMPLE is not supported on '%s'", line, col, name),
+	)
+}
+
 // show options
 
 func NewErrUnknownShowOption(line, col int, optionName string) error {
@@ -943,3 +1118,91 @@ func NewErrUnknownShowOption(line, col int, optionName string) error {
 		fmt.Sprintf("[%d:%d] unknown show option '%s'", line, col, optionName),
 	)
 }
+
+// transactions
+
+func NewErrTransactionSessionRequired(line, col int) error {
+	return errors.New(
+		ErrTransactionSessionRequired,
+		fmt.Sprintf("[%d:%d] transactions require a session", line, col),
+	)
+}
+
+func NewErrTransactionInProgress(line, col int) error {
+	return errors.New(
+		ErrTransactionInProgress,
+		fmt.Sprintf("[%d:%d] there is already a transaction in progress", line, col),
+	)
+}
+
+func NewErrNoTransactionInProgress(line, col int) error {
+	return errors.New(
+		ErrNoTransactionInProgress,
+		fmt.Sprintf("[%d:%d] there is no transaction in progress", line, col),
+	)
+}
+
+func NewErrTransactionAborted(line, col int, cause error) error {
+	return errors.New(
+		ErrTransactionAborted,
+		fmt.Sprintf("[%d:%d] current transaction is aborted, commands ignored until end of transaction block (%s)", line, col, cause),
+	)
+}
+
+func NewErrStatementNotAllowedInTransaction(line, col int) error {
+	return errors.New(
+		ErrStatementNotAllowedInTransaction,
+		fmt.Sprintf("[%d:%d] only SELECT, SHOW, INSERT, BULK INSERT and DELETE statements can run inside a transaction", line, col),
+	)
+}
+
+// prepared statements
+
+func NewErrPreparedStatementSessionRequired(line, col int) error {
+	return errors.New(
+		ErrPreparedStatementSessionRequired,
+		fmt.Sprintf("[%d:%d] prepared statements require a session", line, col),
+	)
+}
+
+func NewErrPreparedStatementExists(line, col int, name string) error {
+	return errors.New(
+		ErrPreparedStatementExists,
+		fmt.Sprintf("[%d:%d] prepared statement '%s' already exists", line, col, name),
+	)
+}
+
+func NewErrPreparedStatementNotFound(line, col int, name string) error {
+	return errors.New(
+		ErrPreparedStatementNotFound,
+		fmt.Sprintf("[%d:%d] prepared statement '%s' does not exist", line, col, name),
+	)
+}
+
+func NewErrParametersNotAllowed(line, col int) error {
+	return errors.New(
+		ErrParametersNotAllowed,
+		fmt.Sprintf("[%d:%d] parameters are only allowed in SELECT, INSERT, REPLACE and DELETE statements", line, col),
+	)
+}
+
+func NewErrParameterCount(line, col int, expected, actual int) error {
+	return errors.New(
+		ErrParameterCount,
+		fmt.Sprintf("[%d:%d] statement takes %d parameter(s), %d given", line, col, expected, actual),
+	)
+}
+
+func NewErrParameterValueLiteralExpected(line, col int) error {
+	return errors.New(
+		ErrParameterValueLiteralExpected,
+		fmt.Sprintf("[%d:%d] literal expected for parameter value", line, col),
+	)
+}
+
+func NewErrInvalidParameterValue(line, col int, name string, value interface{}, typ string) error {
+	return errors.New(
+		ErrInvalidParameterValue,
+		fmt.Sprintf("[%d:%d] value '%v' for parameter '%s' is not a valid %s", line, col, value, name, typ),
+	)
+}
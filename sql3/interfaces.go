@@ -11,6 +11,7 @@ import (
 
 type CompilePlanner interface {
 	CompilePlan(context.Context, parser.Statement) (types.PlanOperator, error)
+	CompilePreparedPlan(ctx context.Context, sql string, parameters []interface{}) (types.PlanOperator, error)
 	RehydratePlanOp(context.Context, io.Reader) (types.PlanOperator, error)
 }
 
@@ -28,6 +29,10 @@ func (p *NopCompilePlanner) CompilePlan(ctx context.Context, stmt parser.Stateme
 	return nil, nil
 }
 
+func (p *NopCompilePlanner) CompilePreparedPlan(ctx context.Context, sql string, parameters []interface{}) (types.PlanOperator, error) {
+	return nil, nil
+}
+
 func (p *NopCompilePlanner) RehydratePlanOp(ctx context.Context, reader io.Reader) (types.PlanOperator, error) {
 	return nil, nil
 }
@@ -4,6 +4,7 @@ package parser
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -35,6 +36,7 @@ func (*CastExpr) node()                 {}
 func (*CheckConstraint) node()          {}
 func (*ColumnDefinition) node()         {}
 func (*CopyStatement) node()            {}
+func (*ExportStatement) node()          {}
 func (*CommitStatement) node()          {}
 func (*CreateDatabaseStatement) node()  {}
 func (*CreateIndexStatement) node()     {}
@@ -43,6 +45,7 @@ func (*CreateFunctionStatement) node()  {}
 func (*CreateModelStatement) node()     {}
 func (*CreateViewStatement) node()      {}
 func (*DateLit) node()                  {}
+func (*DeallocateStatement) node()      {}
 func (*DefaultConstraint) node()        {}
 func (*DeleteStatement) node()          {}
 func (*DropDatabaseStatement) node()    {}
@@ -51,11 +54,14 @@ func (*DropTableStatement) node()       {}
 func (*DropFunctionStatement) node()    {}
 func (*DropViewStatement) node()        {}
 func (*DropModelStatement) node()       {}
+func (*RefreshViewStatement) node()     {}
+func (*ExecuteStatement) node()         {}
 func (*Exists) node()                   {}
 func (*ExplainStatement) node()         {}
 func (*ExprList) node()                 {}
 func (*FilterClause) node()             {}
 func (*FloatLit) node()                 {}
+func (*PrepareStatement) node()         {}
 func (*ForeignKeyArg) node()            {}
 func (*ForeignKeyConstraint) node()     {}
 func (*FrameSpec) node()                {}
@@ -67,6 +73,7 @@ func (*InsertStatement) node()          {}
 func (*JoinClause) node()               {}
 func (*JoinOperator) node()             {}
 func (*KeyPartitionsOption) node()      {}
+func (*RetentionOption) node()          {}
 func (*MinConstraint) node()            {}
 func (*MaxConstraint) node()            {}
 func (*NotNullConstraint) node()        {}
@@ -118,6 +125,7 @@ func (*AlterViewStatement) stmt()       {}
 func (*AnalyzeStatement) stmt()         {}
 func (*BeginStatement) stmt()           {}
 func (*CopyStatement) stmt()            {}
+func (*ExportStatement) stmt()          {}
 func (*BulkInsertStatement) stmt()      {}
 func (*ShowDatabasesStatement) stmt()   {}
 func (*ShowTablesStatement) stmt()      {}
@@ -130,6 +138,7 @@ func (*CreateTableStatement) stmt()     {}
 func (*CreateFunctionStatement) stmt()  {}
 func (*CreateModelStatement) stmt()     {}
 func (*CreateViewStatement) stmt()      {}
+func (*DeallocateStatement) stmt()      {}
 func (*DeleteStatement) stmt()          {}
 func (*DropDatabaseStatement) stmt()    {}
 func (*DropIndexStatement) stmt()       {}
@@ -137,7 +146,10 @@ func (*DropTableStatement) stmt()       {}
 func (*DropFunctionStatement) stmt()    {}
 func (*DropViewStatement) stmt()        {}
 func (*DropModelStatement) stmt()       {}
+func (*RefreshViewStatement) stmt()     {}
 func (*PredictStatement) stmt()         {}
+func (*PrepareStatement) stmt()         {}
+func (*ExecuteStatement) stmt()         {}
 func (*ExplainStatement) stmt()         {}
 func (*InsertStatement) stmt()          {}
 func (*ReleaseStatement) stmt()         {}
@@ -176,6 +188,8 @@ func CloneStatement(stmt Statement) Statement {
 		return stmt.Clone()
 	case *CreateViewStatement:
 		return stmt.Clone()
+	case *DeallocateStatement:
+		return stmt.Clone()
 	case *DeleteStatement:
 		return stmt.Clone()
 	case *DropDatabaseStatement:
@@ -190,14 +204,24 @@ func CloneStatement(stmt Statement) Statement {
 		return stmt.Clone()
 	case *DropModelStatement:
 		return stmt.Clone()
+	case *RefreshViewStatement:
+		return stmt.Clone()
+	case *ExecuteStatement:
+		return stmt.Clone()
 	case *ExplainStatement:
 		return stmt.Clone()
+	case *PrepareStatement:
+		return stmt.Clone()
 	case *InsertStatement:
 		return stmt.Clone()
 	case *BulkInsertStatement:
 		return stmt.Clone()
+	case *ExportStatement:
+		return stmt.Clone()
 	case *ReleaseStatement:
 		return stmt.Clone()
+	case *ReturnStatement:
+		return stmt.Clone()
 	case *RollbackStatement:
 		return stmt.Clone()
 	case *SavepointStatement:
@@ -753,6 +777,97 @@ func (s *ReleaseStatement) String() string {
 	return buf.String()
 }
 
+type PrepareStatement struct {
+	Prepare Pos       // position of PREPARE keyword
+	Name    *Ident    // name of prepared statement
+	As      Pos       // position of AS keyword
+	Stmt    Statement // statement being prepared
+}
+
+// Clone returns a deep copy of s.
+func (s *PrepareStatement) Clone() *PrepareStatement {
+	if s == nil {
+		return s
+	}
+	other := *s
+	other.Name = s.Name.Clone()
+	other.Stmt = CloneStatement(s.Stmt)
+	return &other
+}
+
+// String returns the string representation of the statement.
+func (s *PrepareStatement) String() string {
+	return fmt.Sprintf("PREPARE %s AS %s", s.Name.String(), s.Stmt.String())
+}
+
+type ExecuteStatement struct {
+	Execute Pos    // position of EXECUTE keyword
+	Name    *Ident // name of prepared statement
+	Lparen  Pos    // position of left paren (optional)
+	Args    []Expr // parameter values
+	Rparen  Pos    // position of right paren (optional)
+}
+
+// Clone returns a deep copy of s.
+func (s *ExecuteStatement) Clone() *ExecuteStatement {
+	if s == nil {
+		return s
+	}
+	other := *s
+	other.Name = s.Name.Clone()
+	other.Args = cloneExprs(s.Args)
+	return &other
+}
+
+// String returns the string representation of the statement.
+func (s *ExecuteStatement) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "EXECUTE %s", s.Name.String())
+	if s.Lparen.IsValid() {
+		buf.WriteString("(")
+		for i, arg := range s.Args {
+			if i != 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(arg.String())
+		}
+		buf.WriteString(")")
+	}
+	return buf.String()
+}
+
+type DeallocateStatement struct {
+	Deallocate Pos    // position of DEALLOCATE keyword
+	Prepare    Pos    // position of PREPARE keyword (optional)
+	All        Pos    // position of ALL keyword
+	Name       *Ident // name of prepared statement
+}
+
+// Clone returns a deep copy of s.
+func (s *DeallocateStatement) Clone() *DeallocateStatement {
+	if s == nil {
+		return s
+	}
+	other := *s
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// String returns the string representation of the statement.
+func (s *DeallocateStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("DEALLOCATE")
+	if s.Prepare.IsValid() {
+		buf.WriteString(" PREPARE")
+	}
+	if s.All.IsValid() {
+		buf.WriteString(" ALL")
+	} else {
+		fmt.Fprintf(&buf, " %s", s.Name.String())
+	}
+	return buf.String()
+}
+
 type CreateDatabaseStatement struct {
 	Create      Pos    // position of CREATE keyword
 	Database    Pos    // position of DATABASE keyword
@@ -955,6 +1070,8 @@ func CloneTableOption(opt TableOption) TableOption {
 		return cons.Clone()
 	case *CommentOption:
 		return cons.Clone()
+	case *RetentionOption:
+		return cons.Clone()
 	default:
 		panic(fmt.Sprintf("invalid table option type: %T", cons))
 	}
@@ -962,6 +1079,7 @@ func CloneTableOption(opt TableOption) TableOption {
 
 func (*KeyPartitionsOption) option() {}
 func (*CommentOption) option()       {}
+func (*RetentionOption) option()     {}
 
 type KeyPartitionsOption struct {
 	KeyPartitions Pos  // position of KEYPARTITIONS keyword
@@ -999,6 +1117,30 @@ func (o *CommentOption) Clone() *CommentOption {
 	return &other
 }
 
+// RetentionOption is a retention policy for a table: records whose value in
+// the (timestamp) column is older than the period are deleted.
+type RetentionOption struct {
+	Retention Pos    // position of RETENTION keyword
+	Column    *Ident // timestamp column
+	Expr      Expr   // period
+}
+
+func (o *RetentionOption) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("RETENTION ")
+	buf.WriteString(o.Column.String())
+	buf.WriteString(" ")
+	buf.WriteString(o.Expr.String())
+	return buf.String()
+}
+
+func (o *RetentionOption) Clone() *RetentionOption {
+	other := *o
+	other.Column = o.Column.Clone()
+	other.Expr = CloneExpr(o.Expr)
+	return &other
+}
+
 type Constraint interface {
 	Node
 	constraint()
@@ -1598,9 +1740,9 @@ type AlterTableStatement struct {
 	Table Pos    // position of TABLE keyword
 	Name  *Ident // table name
 
-	Rename Pos // position of RENAME keyword
-	//RenameTo Pos    // position of TO keyword after RENAME
-	//NewName  *Ident // new table name
+	Rename   Pos    // position of RENAME keyword
+	RenameTo Pos    // position of TO keyword after RENAME
+	NewName  *Ident // new table name
 
 	RenameColumn  Pos    // position of COLUMN keyword after RENAME
 	OldColumnName *Ident // old column name
@@ -1614,6 +1756,9 @@ type AlterTableStatement struct {
 	Drop           Pos    // position of ADD keyword
 	DropColumn     Pos    // position of COLUMN keyword after ADD
 	DropColumnName *Ident // drop column name
+
+	With   Pos         // position of WITH keyword
+	Option TableOption // table option to set
 }
 
 // Clone returns a deep copy of s.
@@ -1623,11 +1768,12 @@ func (s *AlterTableStatement) Clone() *AlterTableStatement {
 	}
 	other := *s
 	other.Name = other.Name.Clone()
-	//other.NewName = s.NewName.Clone()
+	other.NewName = s.NewName.Clone()
 	other.OldColumnName = s.OldColumnName.Clone()
 	other.NewColumnName = s.NewColumnName.Clone()
 	other.ColumnDef = s.ColumnDef.Clone()
 	other.DropColumnName = s.DropColumnName.Clone()
+	other.Option = CloneTableOption(s.Option)
 	return &other
 }
 
@@ -1637,7 +1783,10 @@ func (s *AlterTableStatement) String() string {
 	buf.WriteString("ALTER TABLE ")
 	buf.WriteString(s.Name.String())
 
-	if s.OldColumnName != nil {
+	if s.NewName != nil {
+		buf.WriteString(" RENAME TO ")
+		buf.WriteString(s.NewName.String())
+	} else if s.OldColumnName != nil {
 		buf.WriteString(" RENAME ")
 		if s.RenameColumn.IsValid() {
 			buf.WriteString("COLUMN ")
@@ -1657,6 +1806,9 @@ func (s *AlterTableStatement) String() string {
 			buf.WriteString("COLUMN ")
 		}
 		buf.WriteString(s.ColumnDef.String())
+	} else if s.Option != nil {
+		buf.WriteString(" WITH ")
+		buf.WriteString(s.Option.String())
 	}
 	return buf.String()
 }
@@ -1786,6 +1938,18 @@ func (i *Variable) VarName() string {
 	return i.Name[1:]
 }
 
+// IsParameter returns true if the variable is a positional parameter ($1, $2,
+// ...), whose value is bound when the statement is executed.
+func (i *Variable) IsParameter() bool {
+	return strings.HasPrefix(i.Name, "$")
+}
+
+// ParameterIndex returns the zero-based position of a positional parameter.
+func (i *Variable) ParameterIndex() int {
+	n, _ := strconv.Atoi(i.Name[1:])
+	return n - 1
+}
+
 type Type struct {
 	Name      *Ident      // type name
 	Lparen    Pos         // position of left paren (optional)
@@ -2806,12 +2970,13 @@ func (s *DropTableStatement) String() string {
 }
 
 type CreateViewStatement struct {
-	Create      Pos    // position of CREATE keyword
-	View        Pos    // position of VIEW keyword
-	If          Pos    // position of IF keyword
-	IfNot       Pos    // position of NOT keyword after IF
-	IfNotExists Pos    // position of EXISTS keyword after IF NOT
-	Name        *Ident // view name
+	Create       Pos    // position of CREATE keyword
+	Materialized Pos    // position of MATERIALIZED keyword
+	View         Pos    // position of VIEW keyword
+	If           Pos    // position of IF keyword
+	IfNot        Pos    // position of NOT keyword after IF
+	IfNotExists  Pos    // position of EXISTS keyword after IF NOT
+	Name         *Ident // view name
 	// TODO(pok) - we'll do this later - see note in parseCompileView()
 	// Lparen      Pos              // position of column list left paren
 	// Columns     []*Ident         // column list
@@ -2835,7 +3000,11 @@ func (s *CreateViewStatement) Clone() *CreateViewStatement {
 // String returns the string representation of the statement.
 func (s *CreateViewStatement) String() string {
 	var buf bytes.Buffer
-	buf.WriteString("CREATE VIEW")
+	buf.WriteString("CREATE")
+	if s.Materialized.IsValid() {
+		buf.WriteString(" MATERIALIZED")
+	}
+	buf.WriteString(" VIEW")
 	if s.IfNotExists.IsValid() {
 		buf.WriteString(" IF NOT EXISTS")
 	}
@@ -2903,11 +3072,12 @@ func (s *AlterViewStatement) String() string {
 }
 
 type DropViewStatement struct {
-	Drop     Pos    // position of DROP keyword
-	View     Pos    // position of VIEW keyword
-	If       Pos    // position of IF keyword
-	IfExists Pos    // position of EXISTS keyword after IF
-	Name     *Ident // view name
+	Drop         Pos    // position of DROP keyword
+	Materialized Pos    // position of MATERIALIZED keyword
+	View         Pos    // position of VIEW keyword
+	If           Pos    // position of IF keyword
+	IfExists     Pos    // position of EXISTS keyword after IF
+	Name         *Ident // view name
 }
 
 // Clone returns a deep copy of s.
@@ -2923,7 +3093,11 @@ func (s *DropViewStatement) Clone() *DropViewStatement {
 // String returns the string representation of the statement.
 func (s *DropViewStatement) String() string {
 	var buf bytes.Buffer
-	buf.WriteString("DROP VIEW")
+	buf.WriteString("DROP")
+	if s.Materialized.IsValid() {
+		buf.WriteString(" MATERIALIZED")
+	}
+	buf.WriteString(" VIEW")
 	if s.IfExists.IsValid() {
 		buf.WriteString(" IF EXISTS")
 	}
@@ -2931,6 +3105,31 @@ func (s *DropViewStatement) String() string {
 	return buf.String()
 }
 
+type RefreshViewStatement struct {
+	Refresh      Pos    // position of REFRESH keyword
+	Materialized Pos    // position of MATERIALIZED keyword
+	View         Pos    // position of VIEW keyword
+	Name         *Ident // view name
+}
+
+// Clone returns a deep copy of s.
+func (s *RefreshViewStatement) Clone() *RefreshViewStatement {
+	if s == nil {
+		return nil
+	}
+	other := *s
+	other.Name = s.Name.Clone()
+	return &other
+}
+
+// String returns the string representation of the statement.
+func (s *RefreshViewStatement) String() string {
+	var buf bytes.Buffer
+	buf.WriteString("REFRESH MATERIALIZED VIEW")
+	fmt.Fprintf(&buf, " %s", s.Name.String())
+	return buf.String()
+}
+
 type DropModelStatement struct {
 	Drop     Pos    // position of DROP keyword
 	Model    Pos    // position of MODEL keyword
@@ -3109,7 +3308,7 @@ func (s *CreateFunctionStatement) String() string {
 			if idx > 0 {
 				buf.WriteString(", ")
 			}
-			fmt.Fprintf(&buf, "%s %s", p.Name.Name, p.Type.Name)
+			fmt.Fprintf(&buf, "%s %s", p.Name.Name, p.Type.String())
 		}
 		buf.WriteString(")")
 	}
@@ -3118,22 +3317,15 @@ func (s *CreateFunctionStatement) String() string {
 	fmt.Fprintf(&buf, "%s", s.ReturnType.String())
 
 	if s.With.IsValid() {
-		buf.WriteString(" WITH ")
-		if len(s.Options) > 0 {
-			buf.WriteString(" (")
-			for idx, p := range s.Options {
-				if idx > 0 {
-					buf.WriteString(", ")
-				}
-				fmt.Fprintf(&buf, "%s %s", p.Name.Name, p.OptionExpr.String())
-			}
-			buf.WriteString(")")
+		buf.WriteString(" WITH")
+		for _, p := range s.Options {
+			fmt.Fprintf(&buf, " %s %s", p.Name.Name, p.OptionExpr.String())
 		}
 	}
 
 	buf.WriteString(" AS BEGIN")
 	for i := range s.Body {
-		fmt.Fprintf(&buf, " %s;", s.Body[i].String())
+		fmt.Fprintf(&buf, " %s", s.Body[i].String())
 	}
 	buf.WriteString(" END")
 
@@ -3842,6 +4034,54 @@ func (c *CopyStatement) String() string {
 	return buf.String()
 }
 
+// ExportStatement represents a COPY statement writing the results of a query
+// to a file or URL, e.g. COPY (SELECT ...) TO 'path' WITH FORMAT 'CSV' OUTPUT 'FILE'
+type ExportStatement struct {
+	Copy   Pos              // position of COPY keyword
+	Lparen Pos              // position of left paren
+	Query  *SelectStatement // query to export
+	Rparen Pos              // position of right paren
+	To     Pos              // position of TO keyword
+	Target Expr             // file path or url to export to
+	With   Pos              // position of WITH keyword
+
+	Format    Expr
+	Output    Expr
+	HeaderRow Expr // write a header row
+}
+
+func (c *ExportStatement) Clone() *ExportStatement {
+	if c == nil {
+		return nil
+	}
+	other := *c
+	other.Query = c.Query.Clone()
+	other.Target = CloneExpr(c.Target)
+	other.Format = CloneExpr(c.Format)
+	other.Output = CloneExpr(c.Output)
+	other.HeaderRow = CloneExpr(c.HeaderRow)
+	return &other
+}
+
+func (c *ExportStatement) String() string {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "COPY (%s) TO %s", c.Query.String(), c.Target.String())
+	if c.With.IsValid() {
+		buf.WriteString(" WITH")
+		if c.Format != nil {
+			fmt.Fprintf(&buf, " FORMAT %s", c.Format.String())
+		}
+		if c.Output != nil {
+			fmt.Fprintf(&buf, " OUTPUT %s", c.Output.String())
+		}
+		if c.HeaderRow != nil {
+			buf.WriteString(" HEADER_ROW")
+		}
+	}
+	return buf.String()
+}
+
 type PredictStatement struct {
 	Predict   Pos    // position of PREDICT keyword
 	Using     Pos    // position of USING keyword
@@ -4179,6 +4419,46 @@ func (n *TableQueryOption) String() string {
 	return buf.String()
 }
 
+// TableSample is the TABLESAMPLE clause of a table in a FROM clause.
+type TableSample struct {
+	TableSample      Pos  // position of TABLESAMPLE keyword
+	LParen           Pos  // position of left paren
+	Size             Expr // sample size
+	Percent          Pos  // position of PERCENT keyword
+	Rows             Pos  // position of ROWS keyword
+	RParen           Pos  // position of right paren
+	Repeatable       Pos  // position of REPEATABLE keyword
+	RepeatableLParen Pos  // position of left paren
+	Seed             Expr // optional seed
+	RepeatableRParen Pos  // position of right paren
+}
+
+// Clone returns a deep copy of n.
+func (n *TableSample) Clone() *TableSample {
+	if n == nil {
+		return nil
+	}
+	other := *n
+	other.Size = CloneExpr(n.Size)
+	other.Seed = CloneExpr(n.Seed)
+	return &other
+}
+
+// String returns the string representation of the clause.
+func (n *TableSample) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "TABLESAMPLE (%s", n.Size.String())
+	if n.Rows.IsValid() {
+		buf.WriteString(" ROWS)")
+	} else {
+		buf.WriteString(" PERCENT)")
+	}
+	if n.Seed != nil {
+		fmt.Fprintf(&buf, " REPEATABLE (%s)", n.Seed.String())
+	}
+	return buf.String()
+}
+
 type QualifiedTableName struct {
 	Name          *Ident // table name
 	As            Pos    // position of AS keyword
@@ -4187,6 +4467,7 @@ type QualifiedTableName struct {
 	LParen        Pos
 	QueryOptions  []*TableQueryOption
 	RParen        Pos
+	Sample        *TableSample          // optional TABLESAMPLE clause
 	OutputColumns []*SourceOutputColumn // output columns - populated during analysis
 }
 
@@ -4223,6 +4504,7 @@ func (n *QualifiedTableName) Clone() *QualifiedTableName {
 	other.Name = n.Name.Clone()
 	other.Alias = n.Alias.Clone()
 	other.QueryOptions = cloneQueryOptions(n.QueryOptions)
+	other.Sample = n.Sample.Clone()
 	return &other
 }
 
@@ -4247,6 +4529,9 @@ func (n *QualifiedTableName) String() string {
 		}
 		buf.WriteString(")")
 	}
+	if n.Sample != nil {
+		fmt.Fprintf(&buf, " %s", n.Sample.String())
+	}
 	return buf.String()
 }
 
@@ -79,9 +79,38 @@ func TestAlterTableStatement_String(t *testing.T) {
 	}, `ALTER TABLE foo DROP COLUMN bar`)
 }
 
-/*func TestAnalyzeStatement_String(t *testing.T) {
-	AssertStatementStringer(t, &parser.AnalyzeStatement{Name: &parser.Ident{Name: "foo"}}, `ANALYZE "foo"`)
-}*/
+func TestAnalyzeStatement_String(t *testing.T) {
+	AssertStatementStringer(t, &parser.AnalyzeStatement{Name: &parser.Ident{Name: "foo"}}, `ANALYZE foo`)
+}
+
+func TestPrepareStatement_String(t *testing.T) {
+	AssertStatementStringer(t, &parser.PrepareStatement{
+		Name: &parser.Ident{Name: "q"},
+		Stmt: &parser.SelectStatement{
+			Columns: []*parser.ResultColumn{{Star: pos(0)}},
+			Source:  &parser.QualifiedTableName{Name: &parser.Ident{Name: "tbl"}},
+			WhereExpr: &parser.BinaryExpr{
+				X:  &parser.Ident{Name: "a"},
+				Op: parser.EQ,
+				Y:  &parser.Variable{Name: "$1"},
+			},
+		},
+	}, `PREPARE q AS SELECT * FROM tbl WHERE a = $1`)
+}
+
+func TestExecuteStatement_String(t *testing.T) {
+	AssertStatementStringer(t, &parser.ExecuteStatement{Name: &parser.Ident{Name: "q"}}, `EXECUTE q`)
+	AssertStatementStringer(t, &parser.ExecuteStatement{
+		Name:   &parser.Ident{Name: "q"},
+		Lparen: pos(0),
+		Args:   []parser.Expr{&parser.IntegerLit{Value: "1"}, &parser.StringLit{Value: "a"}},
+	}, `EXECUTE q(1, 'a')`)
+}
+
+func TestDeallocateStatement_String(t *testing.T) {
+	AssertStatementStringer(t, &parser.DeallocateStatement{Name: &parser.Ident{Name: "q"}}, `DEALLOCATE q`)
+	AssertStatementStringer(t, &parser.DeallocateStatement{Prepare: pos(0), All: pos(0)}, `DEALLOCATE PREPARE ALL`)
+}
 
 func TestBeginStatement_String(t *testing.T) {
 	t.Skip("BEGIN is currently disabled in the parser")
@@ -11,6 +11,7 @@ func IsValidTypeName(typeName string) bool {
 	switch strings.ToLower(typeName) {
 	case dax.BaseTypeBool,
 		dax.BaseTypeDecimal,
+		dax.BaseTypeGeo,
 		dax.BaseTypeID,
 		dax.BaseTypeIDSet,
 		dax.BaseTypeIDSetQ,
@@ -46,6 +47,7 @@ func (*DataTypeTuple) exprDataType()            {}
 func (*DataTypeSubtable) exprDataType()         {}
 func (*DataTypeBool) exprDataType()             {}
 func (*DataTypeDecimal) exprDataType()          {}
+func (*DataTypeGeo) exprDataType()              {}
 func (*DataTypeID) exprDataType()               {}
 func (*DataTypeIDSet) exprDataType()            {}
 func (*DataTypeIDSetQuantum) exprDataType()     {}
@@ -178,6 +180,25 @@ func (*DataTypeBool) TypeInfo() map[string]interface{} {
 	return nil
 }
 
+type DataTypeGeo struct {
+}
+
+func NewDataTypeGeo() *DataTypeGeo {
+	return &DataTypeGeo{}
+}
+
+func (*DataTypeGeo) BaseTypeName() string {
+	return dax.BaseTypeGeo
+}
+
+func (dt *DataTypeGeo) TypeDescription() string {
+	return dt.BaseTypeName()
+}
+
+func (*DataTypeGeo) TypeInfo() map[string]interface{} {
+	return nil
+}
+
 type DataTypeDecimal struct {
 	Scale int64
 }
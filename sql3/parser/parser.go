@@ -93,28 +93,42 @@ func (p *Parser) parseExplainStatement() (_ *ExplainStatement, err error) {
 // parseStmt parses all statement types.
 func (p *Parser) parseNonExplainStatement() (Statement, error) {
 	switch p.peek() {
-	//case ANALYZE:
-	//	return p.parseAnalyzeStatement()
+	case ANALYZE:
+		return p.parseAnalyzeStatement()
 	case ALTER:
 		return p.parseAlterStatement()
+	case BEGIN:
+		return p.parseBeginStatement()
 	case BULK:
 		return p.parseBulkInsertStatement()
+	case COMMIT, END:
+		return p.parseCommitStatement()
 	case CREATE:
 		return p.parseCreateStatement()
 	case COPY:
 		return p.parseCopyStatement()
+	case DEALLOCATE:
+		return p.parseDeallocateStatement()
 	case DROP:
 		return p.parseDropStatement()
+	case EXECUTE:
+		return p.parseExecuteStatement()
 	case SELECT:
 		return p.parseSelectStatement(false, nil)
 	case PREDICT:
 		return p.parsePredictStatement()
+	case PREPARE:
+		return p.parsePrepareStatement()
+	case REFRESH:
+		return p.parseRefreshStatement()
 	case INSERT, REPLACE:
 		return p.parseInsertStatement(nil)
 	case UPDATE:
 		return p.parseUpdateStatement(nil)
 	case DELETE:
 		return p.parseDeleteStatement()
+	case ROLLBACK:
+		return p.parseRollbackStatement()
 		//	case WITH:
 		//		return p.parseWithStatement()
 	case SHOW:
@@ -241,7 +255,7 @@ func (p *Parser) parseShowCreateTableStatement(showPos Pos, createPos Pos) (_ *S
 	return &stmt, nil
 }
 
-/*func (p *Parser) parseBeginStatement() (*BeginStatement, error) {
+func (p *Parser) parseBeginStatement() (*BeginStatement, error) {
 	assert(p.peek() == BEGIN)
 
 	var stmt BeginStatement
@@ -262,9 +276,82 @@ func (p *Parser) parseShowCreateTableStatement(showPos Pos, createPos Pos) (_ *S
 		stmt.Transaction, _, _ = p.scan()
 	}
 	return &stmt, nil
-}*/
+}
 
-/*func (p *Parser) parseCommitStatement() (*CommitStatement, error) {
+func (p *Parser) parsePrepareStatement() (_ *PrepareStatement, err error) {
+	assert(p.peek() == PREPARE)
+
+	var stmt PrepareStatement
+	stmt.Prepare, _, _ = p.scan()
+	if stmt.Name, err = p.parseIdent("prepared statement name"); err != nil {
+		return &stmt, err
+	}
+	if p.peek() != AS {
+		return &stmt, p.errorExpected(p.pos, p.tok, "AS")
+	}
+	stmt.As, _, _ = p.scan()
+
+	switch p.peek() {
+	case SELECT, INSERT, REPLACE, DELETE:
+		if stmt.Stmt, err = p.parseNonExplainStatement(); err != nil {
+			return &stmt, err
+		}
+	default:
+		return &stmt, p.errorExpected(p.pos, p.tok, "SELECT, INSERT, REPLACE or DELETE")
+	}
+	return &stmt, nil
+}
+
+func (p *Parser) parseExecuteStatement() (_ *ExecuteStatement, err error) {
+	assert(p.peek() == EXECUTE)
+
+	var stmt ExecuteStatement
+	stmt.Execute, _, _ = p.scan()
+	if stmt.Name, err = p.parseIdent("prepared statement name"); err != nil {
+		return &stmt, err
+	}
+
+	// Parse optional parameter values.
+	if p.peek() == LP {
+		stmt.Lparen, _, _ = p.scan()
+		for p.peek() != RP {
+			arg, err := p.ParseExpr()
+			if err != nil {
+				return &stmt, err
+			}
+			stmt.Args = append(stmt.Args, arg)
+
+			if p.peek() == RP {
+				break
+			} else if p.peek() != COMMA {
+				return &stmt, p.errorExpected(p.pos, p.tok, "comma or right paren")
+			}
+			p.scan()
+		}
+		stmt.Rparen, _, _ = p.scan()
+	}
+	return &stmt, nil
+}
+
+func (p *Parser) parseDeallocateStatement() (_ *DeallocateStatement, err error) {
+	assert(p.peek() == DEALLOCATE)
+
+	var stmt DeallocateStatement
+	stmt.Deallocate, _, _ = p.scan()
+	if p.peek() == PREPARE {
+		stmt.Prepare, _, _ = p.scan()
+	}
+	if p.peek() == ALL {
+		stmt.All, _, _ = p.scan()
+		return &stmt, nil
+	}
+	if stmt.Name, err = p.parseIdent("prepared statement name"); err != nil {
+		return &stmt, err
+	}
+	return &stmt, nil
+}
+
+func (p *Parser) parseCommitStatement() (*CommitStatement, error) {
 	assert(p.peek() == COMMIT || p.peek() == END)
 
 	var stmt CommitStatement
@@ -278,9 +365,9 @@ func (p *Parser) parseShowCreateTableStatement(showPos Pos, createPos Pos) (_ *S
 		stmt.Transaction, _, _ = p.scan()
 	}
 	return &stmt, nil
-}*/
+}
 
-/*func (p *Parser) parseRollbackStatement() (_ *RollbackStatement, err error) {
+func (p *Parser) parseRollbackStatement() (_ *RollbackStatement, err error) {
 	assert(p.peek() == ROLLBACK)
 
 	var stmt RollbackStatement
@@ -302,7 +389,7 @@ func (p *Parser) parseShowCreateTableStatement(showPos Pos, createPos Pos) (_ *S
 		}
 	}
 	return &stmt, nil
-}*/
+}
 
 /*func (p *Parser) parseSavepointStatement() (_ *SavepointStatement, err error) {
 	assert(p.peek() == SAVEPOINT)
@@ -342,6 +429,14 @@ func (p *Parser) parseCreateStatement() (Statement, error) {
 		return p.parseCreateTableStatement(pos)
 	case VIEW:
 		return p.parseCreateViewStatement(pos)
+	case MATERIALIZED:
+		materialized, _, _ := p.scan()
+		if p.peek() != VIEW {
+			return nil, p.errorExpected(p.pos, p.tok, "VIEW")
+		}
+		stmt, err := p.parseCreateViewStatement(pos)
+		stmt.Materialized = materialized
+		return stmt, err
 		/*case INDEX, UNIQUE:
 		return p.parseCreateIndexStatement(pos)*/
 	case FUNCTION:
@@ -380,6 +475,14 @@ func (p *Parser) parseDropStatement() (Statement, error) {
 		return p.parseDropTableStatement(pos)
 	case VIEW:
 		return p.parseDropViewStatement(pos)
+	case MATERIALIZED:
+		materialized, _, _ := p.scan()
+		if p.peek() != VIEW {
+			return nil, p.errorExpected(p.pos, p.tok, "VIEW")
+		}
+		stmt, err := p.parseDropViewStatement(pos)
+		stmt.Materialized = materialized
+		return stmt, err
 		/* case INDEX:
 		return p.parseDropIndexStatement(pos)*/
 	case FUNCTION:
@@ -574,6 +677,8 @@ func (p *Parser) parseTableOption() (_ TableOption, err error) {
 	switch p.peek() {
 	case KEYPARTITIONS:
 		return p.parseKeyPartitionsOption(optionPos)
+	case RETENTION:
+		return p.parseRetentionOption()
 	default:
 		assert(p.peek() == COMMENT)
 		return p.parseCommentOption()
@@ -611,6 +716,25 @@ func (p *Parser) parseKeyPartitionsOption(optionPos Pos) (_ *KeyPartitionsOption
 	return &opt, nil
 }
 
+func (p *Parser) parseRetentionOption() (_ *RetentionOption, err error) {
+	assert(p.peek() == RETENTION)
+
+	var opt RetentionOption
+	opt.Retention, _, _ = p.scan()
+
+	if opt.Column, err = p.parseIdent("column name"); err != nil {
+		return &opt, err
+	}
+
+	if isLiteralToken(p.peek()) {
+		opt.Expr = p.mustParseLiteral()
+	} else {
+		return &opt, p.errorExpected(p.pos, p.tok, "literal")
+	}
+
+	return &opt, nil
+}
+
 func (p *Parser) parseColumnDefinitions() (_ []*ColumnDefinition, err error) {
 	var columns []*ColumnDefinition
 	for {
@@ -1171,11 +1295,16 @@ func (p *Parser) parseDropTableStatement(dropPos Pos) (_ *DropTableStatement, er
 	return &stmt, nil
 }
 
-func (p *Parser) parseCopyStatement() (_ *CopyStatement, err error) {
+func (p *Parser) parseCopyStatement() (_ Statement, err error) {
 	assert(p.peek() == COPY)
 
+	copyPos, _, _ := p.scan()
+	if p.peek() == LP {
+		return p.parseExportStatement(copyPos)
+	}
+
 	var stmt CopyStatement
-	stmt.Copy, _, _ = p.scan()
+	stmt.Copy = copyPos
 
 	ident, err := p.parseIdent("table name")
 	if err != nil {
@@ -1237,6 +1366,81 @@ func (p *Parser) parseCopyStatement() (_ *CopyStatement, err error) {
 	return &stmt, nil
 }
 
+func (p *Parser) parseExportStatement(copyPos Pos) (_ *ExportStatement, err error) {
+	assert(p.peek() == LP)
+
+	var stmt ExportStatement
+	stmt.Copy = copyPos
+	stmt.Lparen, _, _ = p.scan()
+
+	if p.peek() != SELECT {
+		return &stmt, p.errorExpected(p.pos, p.tok, "SELECT")
+	}
+	if stmt.Query, err = p.parseSelectStatement(false, nil); err != nil {
+		return &stmt, err
+	}
+
+	if p.peek() != RP {
+		return &stmt, p.errorExpected(p.pos, p.tok, ")")
+	}
+	stmt.Rparen, _, _ = p.scan()
+
+	if p.peek() != TO {
+		return &stmt, p.errorExpected(p.pos, p.tok, "TO")
+	}
+	stmt.To, _, _ = p.scan()
+
+	if isLiteralToken(p.peek()) {
+		stmt.Target = p.mustParseLiteral()
+	} else {
+		return &stmt, p.errorExpected(p.pos, p.tok, "literal")
+	}
+
+	if p.peek() != WITH {
+		return &stmt, p.errorExpected(p.pos, p.tok, "WITH")
+	}
+	stmt.With, _, _ = p.scan()
+	if !isExportOptionStartToken(p.peek(), p) {
+		return &stmt, p.errorExpected(p.pos, p.tok, "FORMAT, OUTPUT or HEADER_ROW")
+	}
+	for {
+		if err := p.parseExportOption(&stmt); err != nil {
+			return &stmt, err
+		}
+		if !isExportOptionStartToken(p.peek(), p) {
+			break
+		}
+	}
+	return &stmt, nil
+}
+
+func (p *Parser) parseExportOption(stmt *ExportStatement) error {
+	ident, err := p.parseIdent("export option")
+	if err != nil {
+		return err
+	}
+	switch strings.ToUpper(ident.Name) {
+	case "FORMAT":
+		if !isLiteralToken(p.peek()) {
+			return p.errorExpected(p.pos, p.tok, "literal")
+		}
+		stmt.Format = p.mustParseLiteral()
+		return nil
+
+	case "OUTPUT":
+		if !isLiteralToken(p.peek()) {
+			return p.errorExpected(p.pos, p.tok, "literal")
+		}
+		stmt.Output = p.mustParseLiteral()
+		return nil
+
+	case "HEADER_ROW":
+		stmt.HeaderRow = ident
+		return nil
+	}
+	return p.errorExpected(ident.NamePos, IDENT, "FORMAT, OUTPUT or HEADER_ROW")
+}
+
 func (p *Parser) parseCreateViewStatement(createPos Pos) (_ *CreateViewStatement, err error) {
 	assert(p.peek() == VIEW)
 
@@ -1342,6 +1546,28 @@ func (p *Parser) parseAlterViewStatement(alterPos Pos) (_ *AlterViewStatement, e
 	return &stmt, nil
 }
 
+func (p *Parser) parseRefreshStatement() (_ *RefreshViewStatement, err error) {
+	assert(p.peek() == REFRESH)
+
+	var stmt RefreshViewStatement
+	stmt.Refresh, _, _ = p.scan()
+
+	if p.peek() != MATERIALIZED {
+		return &stmt, p.errorExpected(p.pos, p.tok, "MATERIALIZED")
+	}
+	stmt.Materialized, _, _ = p.scan()
+
+	if p.peek() != VIEW {
+		return &stmt, p.errorExpected(p.pos, p.tok, "VIEW")
+	}
+	stmt.View, _, _ = p.scan()
+
+	if stmt.Name, err = p.parseIdent("view name"); err != nil {
+		return &stmt, err
+	}
+	return &stmt, nil
+}
+
 func (p *Parser) parseDropViewStatement(dropPos Pos) (_ *DropViewStatement, err error) {
 	assert(p.peek() == VIEW)
 
@@ -1629,6 +1855,11 @@ func (p *Parser) parseFunctionBodyStatement(cf *CreateFunctionStatement) (stmt S
 		}
 		cf.Body = append(cf.Body, s)
 
+		// a body statement may be terminated by a semicolon
+		if p.peek() == SEMI {
+			p.scan()
+		}
+
 	case END:
 		break
 	default:
@@ -2618,7 +2849,16 @@ func (p *Parser) parseUnarySource() (source Source, err error) {
 		if p.peek() == LP {
 			return p.parseTableValuedFunction(ident)
 		}
-		return p.parseQualifiedTableName(ident)
+		tbl, err := p.parseQualifiedTableName(ident)
+		if err != nil {
+			return tbl, err
+		}
+		if p.peek() == TABLESAMPLE {
+			if tbl.Sample, err = p.parseTableSample(); err != nil {
+				return tbl, err
+			}
+		}
+		return tbl, nil
 	default:
 		return nil, p.errorExpected(p.pos, p.tok, "table name or left paren")
 	}
@@ -2799,6 +3039,54 @@ func (p *Parser) parseQualifiedTableName(ident *Ident) (_ *QualifiedTableName, e
 	return &tbl, nil
 }
 
+// parseTableSample parses "TABLESAMPLE (size [PERCENT | ROWS]) [REPEATABLE (seed)]".
+func (p *Parser) parseTableSample() (_ *TableSample, err error) {
+	assert(p.peek() == TABLESAMPLE)
+
+	var sample TableSample
+	sample.TableSample, _, _ = p.scan()
+
+	if p.peek() != LP {
+		return &sample, p.errorExpected(p.pos, p.tok, "left paren")
+	}
+	sample.LParen, _, _ = p.scan()
+
+	if sample.Size, err = p.ParseExpr(); err != nil {
+		return &sample, err
+	}
+
+	switch p.peek() {
+	case PERCENT:
+		sample.Percent, _, _ = p.scan()
+	case ROWS:
+		sample.Rows, _, _ = p.scan()
+	}
+
+	if p.peek() != RP {
+		return &sample, p.errorExpected(p.pos, p.tok, "right paren")
+	}
+	sample.RParen, _, _ = p.scan()
+
+	if p.peek() == REPEATABLE {
+		sample.Repeatable, _, _ = p.scan()
+
+		if p.peek() != LP {
+			return &sample, p.errorExpected(p.pos, p.tok, "left paren")
+		}
+		sample.RepeatableLParen, _, _ = p.scan()
+
+		if sample.Seed, err = p.ParseExpr(); err != nil {
+			return &sample, err
+		}
+
+		if p.peek() != RP {
+			return &sample, p.errorExpected(p.pos, p.tok, "right paren")
+		}
+		sample.RepeatableRParen, _, _ = p.scan()
+	}
+	return &sample, nil
+}
+
 func (p *Parser) parseTableQueryOption() (_ *TableQueryOption, err error) {
 	var opt TableQueryOption
 	opt.OptionParams = make([]*Ident, 0)
@@ -3699,14 +3987,14 @@ func (p *Parser) parseAlterTableStatement(alterPos Pos) (_ *AlterTableStatement,
 	case RENAME:
 		stmt.Rename, _, _ = p.scan()
 
-		/*// Parse "RENAME TO new-table-name".
+		// Parse "RENAME TO new-table-name".
 		if p.peek() == TO {
 			stmt.RenameTo, _, _ = p.scan()
 			if stmt.NewName, err = p.parseIdent("new table name"); err != nil {
 				return &stmt, err
 			}
 			return &stmt, nil
-		}*/
+		}
 
 		// Otherwise parse "RENAME [COLUMN] column-name TO new-column-name".
 		if p.peek() == COLUMN {
@@ -3750,12 +4038,24 @@ func (p *Parser) parseAlterTableStatement(alterPos Pos) (_ *AlterTableStatement,
 		}
 		return &stmt, nil
 
+	case WITH:
+		stmt.With, _, _ = p.scan()
+
+		// look for table option
+		if p.peek() != RETENTION {
+			return &stmt, p.errorExpected(p.pos, p.tok, "RETENTION")
+		}
+		if stmt.Option, err = p.parseTableOption(); err != nil {
+			return &stmt, err
+		}
+		return &stmt, nil
+
 	default:
-		return &stmt, p.errorExpected(p.pos, p.tok, "ADD, DROP or RENAME")
+		return &stmt, p.errorExpected(p.pos, p.tok, "ADD, DROP, RENAME or WITH")
 	}
 }
 
-/*func (p *Parser) parseAnalyzeStatement() (_ *AnalyzeStatement, err error) {
+func (p *Parser) parseAnalyzeStatement() (_ *AnalyzeStatement, err error) {
 	assert(p.peek() == ANALYZE)
 
 	var stmt AnalyzeStatement
@@ -3764,7 +4064,7 @@ func (p *Parser) parseAlterTableStatement(alterPos Pos) (_ *AlterTableStatement,
 		return &stmt, err
 	}
 	return &stmt, nil
-}*/
+}
 
 func (p *Parser) scan() (Pos, Token, string) {
 	if p.full {
@@ -3867,7 +4167,7 @@ func isDatabaseOptionStartToken(tok Token) bool {
 // isTableOptionStartToken returns true if tok is the initial token of a table option.
 func isTableOptionStartToken(tok Token) bool {
 	switch tok {
-	case KEYPARTITIONS, COMMENT:
+	case KEYPARTITIONS, COMMENT, RETENTION:
 		return true
 	default:
 		return false
@@ -3891,6 +4191,22 @@ func isBulkInsertOptionStartToken(tok Token, p *Parser) bool {
 	return false
 }
 
+func isExportOptionStartToken(tok Token, p *Parser) bool {
+	switch tok {
+	case IDENT:
+		ident, err := p.parseIdent("export option")
+		defer p.unscan()
+		if err != nil {
+			return false
+		}
+		switch strings.ToUpper(ident.Name) {
+		case "FORMAT", "OUTPUT", "HEADER_ROW":
+			return true
+		}
+	}
+	return false
+}
+
 func isCopyOptionStartToken(tok Token, p *Parser) bool {
 	switch tok {
 	case IDENT:
@@ -376,14 +376,14 @@ func TestParser_ParseAlterStatement(t *testing.T) {
 	})
 
 	t.Run("AlterTable", func(t *testing.T) {
-		/*AssertParseStatement(t, `ALTER TABLE tbl RENAME TO new_tbl`, &parser.AlterTableStatement{
+		AssertParseStatement(t, `ALTER TABLE tbl RENAME TO new_tbl`, &parser.AlterTableStatement{
 			Alter:    pos(0),
 			Table:    pos(6),
 			Name:     &parser.Ident{NamePos: pos(12), Name: "tbl"},
 			Rename:   pos(16),
 			RenameTo: pos(23),
 			NewName:  &parser.Ident{NamePos: pos(26), Name: "new_tbl"},
-		})*/
+		})
 		AssertParseStatement(t, `ALTER TABLE tbl RENAME COLUMN col TO new_col`, &parser.AlterTableStatement{
 			Alter:         pos(0),
 			Table:         pos(6),
@@ -452,11 +452,27 @@ func TestParser_ParseAlterStatement(t *testing.T) {
 			DropColumnName: &parser.Ident{NamePos: pos(28), Name: "col"},
 		})
 
+		AssertParseStatement(t, `ALTER TABLE tbl WITH RETENTION ts '720h'`, &parser.AlterTableStatement{
+			Alter: pos(0),
+			Table: pos(6),
+			Name:  &parser.Ident{NamePos: pos(12), Name: "tbl"},
+			With:  pos(16),
+			Option: &parser.RetentionOption{
+				Retention: pos(21),
+				Column:    &parser.Ident{NamePos: pos(31), Name: "ts"},
+				Expr:      &parser.StringLit{ValuePos: pos(34), Value: "720h"},
+			},
+		})
+
 		AssertParseStatementError(t, `ALTER`, `1:1: expected DATABASE, TABLE or VIEW`)
 		AssertParseStatementError(t, `ALTER TABLE`, `1:11: expected table name, found 'EOF'`)
-		AssertParseStatementError(t, `ALTER TABLE tbl`, `1:15: expected ADD, DROP or RENAME, found 'EOF'`)
+		AssertParseStatementError(t, `ALTER TABLE tbl`, `1:15: expected ADD, DROP, RENAME or WITH, found 'EOF'`)
+		AssertParseStatementError(t, `ALTER TABLE tbl WITH`, `1:20: expected RETENTION, found 'EOF'`)
+		AssertParseStatementError(t, `ALTER TABLE tbl WITH COMMENT 'foo'`, `1:22: expected RETENTION, found 'COMMENT'`)
+		AssertParseStatementError(t, `ALTER TABLE tbl WITH RETENTION`, `1:30: expected column name, found 'EOF'`)
+		AssertParseStatementError(t, `ALTER TABLE tbl WITH RETENTION ts`, `1:33: expected literal, found 'EOF'`)
 		AssertParseStatementError(t, `ALTER TABLE tbl RENAME`, `1:22: expected COLUMN keyword or column name, found 'EOF'`)
-		//AssertParseStatementError(t, `ALTER TABLE tbl RENAME TO`, `1:25: expected new table name, found 'EOF'`)
+		AssertParseStatementError(t, `ALTER TABLE tbl RENAME TO`, `1:25: expected new table name, found 'EOF'`)
 		AssertParseStatementError(t, `ALTER TABLE tbl RENAME COLUMN`, `1:29: expected column name, found 'EOF'`)
 		AssertParseStatementError(t, `ALTER TABLE tbl RENAME COLUMN col`, `1:33: expected TO, found 'EOF'`)
 		AssertParseStatementError(t, `ALTER TABLE tbl RENAME COLUMN col TO`, `1:36: expected new column name, found 'EOF'`)
@@ -470,6 +486,36 @@ func TestParser_ParseAlterStatement(t *testing.T) {
 	})
 }
 
+func TestParser_ParseExportStatement(t *testing.T) {
+	t.Run("Export", func(t *testing.T) {
+		AssertParseStatement(t, `COPY (SELECT * FROM tbl) TO '/tmp/tbl.csv' WITH FORMAT 'CSV' OUTPUT 'FILE' HEADER_ROW`, &parser.ExportStatement{
+			Copy:   pos(0),
+			Lparen: pos(5),
+			Query: &parser.SelectStatement{
+				Select: pos(6),
+				Columns: []*parser.ResultColumn{
+					{Star: pos(13)},
+				},
+				From: pos(15),
+				Source: &parser.QualifiedTableName{
+					Name: &parser.Ident{NamePos: pos(20), Name: "tbl"},
+				},
+			},
+			Rparen:    pos(23),
+			To:        pos(25),
+			Target:    &parser.StringLit{ValuePos: pos(28), Value: "/tmp/tbl.csv"},
+			With:      pos(43),
+			Format:    &parser.StringLit{ValuePos: pos(55), Value: "CSV"},
+			Output:    &parser.StringLit{ValuePos: pos(68), Value: "FILE"},
+			HeaderRow: &parser.Ident{NamePos: pos(75), Name: "HEADER_ROW"},
+		})
+		AssertParseStatementError(t, `COPY (tbl) TO 'x'`, `1:7: expected SELECT, found tbl`)
+		AssertParseStatementError(t, `COPY (SELECT * FROM tbl) 'x'`, `1:26: expected TO, found x`)
+		AssertParseStatementError(t, `COPY (SELECT * FROM tbl) TO 'x'`, `1:31: expected WITH, found 'EOF'`)
+		AssertParseStatementError(t, `COPY (SELECT * FROM tbl) TO 'x' WITH BATCHSIZE 10`, `1:38: expected FORMAT, OUTPUT or HEADER_ROW, found BATCHSIZE`)
+	})
+}
+
 func TestParser_ParseFunctionStatement(t *testing.T) {
 	t.Run("CreateFunction", func(t *testing.T) {
 		AssertParseStatement(t, `CREATE FUNCTION IF NOT EXISTS func (@param1 int, @param2 string) returns int as begin end`, &parser.CreateFunctionStatement{
@@ -732,7 +778,7 @@ func TestParser_ParseStatement(t *testing.T) {
 			})*/
 	})
 
-	/*t.Run("Begin", func(t *testing.T) {
+	t.Run("Begin", func(t *testing.T) {
 		t.Run("", func(t *testing.T) {
 			AssertParseStatement(t, `BEGIN`, &parser.BeginStatement{
 				Begin: pos(0),
@@ -752,7 +798,7 @@ func TestParser_ParseStatement(t *testing.T) {
 			})
 		})
 		t.Run("Immediate", func(t *testing.T) {
-			AssertParseStatement(t, `BEGIN IMMEDIATE;`, &parser.BeginStatement{
+			AssertParseStatement(t, `BEGIN IMMEDIATE`, &parser.BeginStatement{
 				Begin:     pos(0),
 				Immediate: pos(6),
 			})
@@ -766,9 +812,9 @@ func TestParser_ParseStatement(t *testing.T) {
 		t.Run("ErrOverrun", func(t *testing.T) {
 			AssertParseStatementError(t, `BEGIN COMMIT`, `1:7: expected semicolon or EOF, found 'COMMIT'`)
 		})
-	})*/
+	})
 
-	/*t.Run("Commit", func(t *testing.T) {
+	t.Run("Commit", func(t *testing.T) {
 		t.Run("", func(t *testing.T) {
 			AssertParseStatement(t, `COMMIT`, &parser.CommitStatement{
 				Commit: pos(0),
@@ -780,9 +826,9 @@ func TestParser_ParseStatement(t *testing.T) {
 				Transaction: pos(7),
 			})
 		})
-	})*/
+	})
 
-	/*t.Run("End", func(t *testing.T) {
+	t.Run("End", func(t *testing.T) {
 		t.Run("", func(t *testing.T) {
 			AssertParseStatement(t, `END`, &parser.CommitStatement{
 				End: pos(0),
@@ -794,9 +840,9 @@ func TestParser_ParseStatement(t *testing.T) {
 				Transaction: pos(4),
 			})
 		})
-	})*/
+	})
 
-	/*t.Run("Rollback", func(t *testing.T) {
+	t.Run("Rollback", func(t *testing.T) {
 		t.Run("", func(t *testing.T) {
 			AssertParseStatement(t, `ROLLBACK`, &parser.RollbackStatement{
 				Rollback: pos(0),
@@ -834,7 +880,7 @@ func TestParser_ParseStatement(t *testing.T) {
 		t.Run("ErrSavepointName", func(t *testing.T) {
 			AssertParseStatementError(t, `ROLLBACK TO SAVEPOINT 123`, `1:23: expected savepoint name, found 123`)
 		})
-	})*/
+	})
 
 	/*t.Run("Savepoint", func(t *testing.T) {
 		t.Run("Ident", func(t *testing.T) {
@@ -975,6 +1021,31 @@ func TestParser_ParseStatement(t *testing.T) {
 	})
 
 	t.Run("CreateTable", func(t *testing.T) {
+		AssertParseStatement(t, `CREATE TABLE tbl (ts TIMESTAMP) RETENTION ts '24h'`, &parser.CreateTableStatement{
+			Create: pos(0),
+			Table:  pos(7),
+			Name: &parser.Ident{
+				Name:    "tbl",
+				NamePos: pos(13),
+			},
+			Lparen: pos(17),
+			Options: []parser.TableOption{
+				&parser.RetentionOption{
+					Retention: pos(32),
+					Column:    &parser.Ident{NamePos: pos(42), Name: "ts"},
+					Expr:      &parser.StringLit{ValuePos: pos(45), Value: "24h"},
+				},
+			},
+			Columns: []*parser.ColumnDefinition{
+				{
+					Name: &parser.Ident{NamePos: pos(18), Name: "ts"},
+					Type: &parser.Type{
+						Name: &parser.Ident{NamePos: pos(21), Name: "TIMESTAMP"},
+					},
+				},
+			},
+			Rparen: pos(30),
+		})
 		AssertParseStatement(t, `CREATE TABLE tbl (col1 TEXT, col2 DECIMAL(2)) KEYPARTITIONS 12 COMMENT 'foo'`, &parser.CreateTableStatement{
 			Create: pos(0),
 			Table:  pos(7),
@@ -1758,6 +1829,20 @@ func TestParser_ParseStatement(t *testing.T) {
 		AssertParseStatementError(t, `CREATE VIEW IF`, `1:14: expected NOT, found 'EOF'`)
 		AssertParseStatementError(t, `CREATE VIEW IF NOT`, `1:18: expected EXISTS, found 'EOF'`)
 		AssertParseStatementError(t, `CREATE VIEW vw`, `1:14: expected AS, found 'EOF'`)
+		AssertParseStatement(t, `CREATE MATERIALIZED VIEW vw AS SELECT x`, &parser.CreateViewStatement{
+			Create:       pos(0),
+			Materialized: pos(7),
+			View:         pos(20),
+			Name:         &parser.Ident{NamePos: pos(25), Name: "vw"},
+			As:           pos(28),
+			Select: &parser.SelectStatement{
+				Select: pos(31),
+				Columns: []*parser.ResultColumn{
+					{Expr: &parser.Ident{NamePos: pos(38), Name: "x"}},
+				},
+			},
+		})
+		AssertParseStatementError(t, `CREATE MATERIALIZED`, `1:19: expected VIEW, found 'EOF'`)
 		//AssertParseStatementError(t, `CREATE VIEW vw (`, `1:16: expected column name, found 'EOF'`)
 		//AssertParseStatementError(t, `CREATE VIEW vw (x`, `1:17: expected comma or right paren, found 'EOF'`)
 		AssertParseStatementError(t, `CREATE VIEW vw AS`, `1:17: expected SELECT, found 'EOF'`)
@@ -1781,6 +1866,26 @@ func TestParser_ParseStatement(t *testing.T) {
 		AssertParseStatementError(t, `DROP VIEW`, `1:9: expected view name, found 'EOF'`)
 		AssertParseStatementError(t, `DROP VIEW IF`, `1:12: expected EXISTS, found 'EOF'`)
 		AssertParseStatementError(t, `DROP VIEW IF EXISTS`, `1:19: expected view name, found 'EOF'`)
+		AssertParseStatement(t, `DROP MATERIALIZED VIEW vw`, &parser.DropViewStatement{
+			Drop:         pos(0),
+			Materialized: pos(5),
+			View:         pos(18),
+			Name:         &parser.Ident{NamePos: pos(23), Name: "vw"},
+		})
+		AssertParseStatementError(t, `DROP MATERIALIZED`, `1:17: expected VIEW, found 'EOF'`)
+	})
+
+	t.Run("RefreshView", func(t *testing.T) {
+		AssertParseStatement(t, `REFRESH MATERIALIZED VIEW vw`, &parser.RefreshViewStatement{
+			Refresh:      pos(0),
+			Materialized: pos(8),
+			View:         pos(21),
+			Name:         &parser.Ident{NamePos: pos(26), Name: "vw"},
+		})
+		AssertParseStatementError(t, `REFRESH`, `1:7: expected MATERIALIZED, found 'EOF'`)
+		AssertParseStatementError(t, `REFRESH MATERIALIZED`, `1:20: expected VIEW, found 'EOF'`)
+		AssertParseStatementError(t, `REFRESH MATERIALIZED VIEW`, `1:25: expected view name, found 'EOF'`)
+		AssertParseStatementError(t, `REFRESH MATERIALIZED VIEW vw FULL`, `1:30: expected semicolon or EOF, found 'FULL'`)
 	})
 
 	/*t.Run("CreateIndex", func(t *testing.T) {
@@ -1894,6 +1999,47 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		})
 
+		AssertParseStatement(t, `SELECT * FROM tbl t TABLESAMPLE (10 ROWS) REPEATABLE (3)`, &parser.SelectStatement{
+			Select: pos(0),
+			Columns: []*parser.ResultColumn{
+				{Star: pos(7)},
+			},
+			From: pos(9),
+			Source: &parser.QualifiedTableName{
+				Name:  &parser.Ident{NamePos: pos(14), Name: "tbl"},
+				Alias: &parser.Ident{NamePos: pos(18), Name: "t"},
+				Sample: &parser.TableSample{
+					TableSample:      pos(20),
+					LParen:           pos(32),
+					Size:             &parser.IntegerLit{ValuePos: pos(33), Value: "10"},
+					Rows:             pos(36),
+					RParen:           pos(40),
+					Repeatable:       pos(42),
+					RepeatableLParen: pos(53),
+					Seed:             &parser.IntegerLit{ValuePos: pos(54), Value: "3"},
+					RepeatableRParen: pos(55),
+				},
+			},
+		})
+
+		AssertParseStatement(t, `SELECT * FROM tbl TABLESAMPLE (2.5 PERCENT)`, &parser.SelectStatement{
+			Select: pos(0),
+			Columns: []*parser.ResultColumn{
+				{Star: pos(7)},
+			},
+			From: pos(9),
+			Source: &parser.QualifiedTableName{
+				Name: &parser.Ident{NamePos: pos(14), Name: "tbl"},
+				Sample: &parser.TableSample{
+					TableSample: pos(18),
+					LParen:      pos(30),
+					Size:        &parser.FloatLit{ValuePos: pos(31), Value: "2.5"},
+					Percent:     pos(35),
+					RParen:      pos(42),
+				},
+			},
+		})
+
 		AssertParseStatement(t, `SELECT foo AS FOO, bar baz, tbl.* FROM tbl`, &parser.SelectStatement{
 			Select: pos(0),
 			Columns: []*parser.ResultColumn{
@@ -3467,6 +3613,9 @@ func TestParser_ParseStatement(t *testing.T) {
 		AssertParseStatementError(t, `WITH cte AS (SELECT foo`, `1:23: expected right paren, found 'EOF'`)
 		AssertParseStatementError(t, `WITH cte AS (SELECT foo)`, `1:24: expected SELECT, VALUES, INSERT, REPLACE, UPDATE, or DELETE, found 'EOF'`)*/
 		AssertParseStatementError(t, `SELECT `, `1:7: expected expression, found 'EOF'`)
+		AssertParseStatementError(t, `SELECT * FROM tbl TABLESAMPLE 10`, `1:31: expected left paren, found 10`)
+		AssertParseStatementError(t, `SELECT * FROM tbl TABLESAMPLE (10 ROWS`, `1:38: expected right paren, found 'EOF'`)
+		AssertParseStatementError(t, `SELECT * FROM tbl TABLESAMPLE (10) REPEATABLE 1`, `1:47: expected left paren, found 1`)
 		AssertParseStatementError(t, `SELECT 1+`, `1:9: expected expression, found 'EOF'`)
 		AssertParseStatementError(t, `SELECT foo,`, `1:11: expected expression, found 'EOF'`)
 		AssertParseStatementError(t, `SELECT foo AS`, `1:13: expected column alias, found 'EOF'`)
@@ -4184,13 +4333,72 @@ func TestParser_ParseStatement(t *testing.T) {
 		//AssertParseStatementError(t, `DELETE FROM tbl LIMIT 1 OFFSET`, `1:30: expected expression, found 'EOF'`)
 	})
 
-	/*t.Run("Analyze", func(t *testing.T) {
+	t.Run("Analyze", func(t *testing.T) {
 		AssertParseStatement(t, `ANALYZE tbl`, &parser.AnalyzeStatement{
 			Analyze: pos(0),
 			Name:    &parser.Ident{NamePos: pos(8), Name: "tbl"},
 		})
 		AssertParseStatementError(t, `ANALYZE`, `1:7: expected table or index name, found 'EOF'`)
-	})*/
+	})
+
+	t.Run("Prepare", func(t *testing.T) {
+		AssertParseStatement(t, `PREPARE q AS SELECT a FROM tbl WHERE b = $1`, &parser.PrepareStatement{
+			Prepare: pos(0),
+			Name:    &parser.Ident{NamePos: pos(8), Name: "q"},
+			As:      pos(10),
+			Stmt: &parser.SelectStatement{
+				Select: pos(13),
+				Columns: []*parser.ResultColumn{
+					{Expr: &parser.Ident{NamePos: pos(20), Name: "a"}},
+				},
+				From: pos(22),
+				Source: &parser.QualifiedTableName{
+					Name: &parser.Ident{NamePos: pos(27), Name: "tbl"},
+				},
+				Where: pos(31),
+				WhereExpr: &parser.BinaryExpr{
+					X:     &parser.Ident{NamePos: pos(37), Name: "b"},
+					OpPos: pos(39), Op: parser.EQ,
+					Y: &parser.Variable{NamePos: pos(41), Name: "$1"},
+				},
+			},
+		})
+		AssertParseStatementError(t, `PREPARE`, `1:7: expected prepared statement name, found 'EOF'`)
+		AssertParseStatementError(t, `PREPARE q SELECT 1`, `1:11: expected AS, found 'SELECT'`)
+		AssertParseStatementError(t, `PREPARE q AS DROP TABLE tbl`, `1:14: expected SELECT, INSERT, REPLACE or DELETE, found 'DROP'`)
+	})
+
+	t.Run("Execute", func(t *testing.T) {
+		AssertParseStatement(t, `EXECUTE q`, &parser.ExecuteStatement{
+			Execute: pos(0),
+			Name:    &parser.Ident{NamePos: pos(8), Name: "q"},
+		})
+		AssertParseStatement(t, `EXECUTE q(1, 'a')`, &parser.ExecuteStatement{
+			Execute: pos(0),
+			Name:    &parser.Ident{NamePos: pos(8), Name: "q"},
+			Lparen:  pos(9),
+			Args: []parser.Expr{
+				&parser.IntegerLit{ValuePos: pos(10), Value: "1"},
+				&parser.StringLit{ValuePos: pos(13), Value: "a"},
+			},
+			Rparen: pos(16),
+		})
+		AssertParseStatementError(t, `EXECUTE`, `1:7: expected prepared statement name, found 'EOF'`)
+		AssertParseStatementError(t, `EXECUTE q(1`, `1:11: expected comma or right paren, found 'EOF'`)
+	})
+
+	t.Run("Deallocate", func(t *testing.T) {
+		AssertParseStatement(t, `DEALLOCATE q`, &parser.DeallocateStatement{
+			Deallocate: pos(0),
+			Name:       &parser.Ident{NamePos: pos(11), Name: "q"},
+		})
+		AssertParseStatement(t, `DEALLOCATE PREPARE ALL`, &parser.DeallocateStatement{
+			Deallocate: pos(0),
+			Prepare:    pos(11),
+			All:        pos(19),
+		})
+		AssertParseStatementError(t, `DEALLOCATE`, `1:10: expected prepared statement name, found 'EOF'`)
+	})
 }
 
 func TestParser_ParseExpr(t *testing.T) {
@@ -39,6 +39,8 @@ func (s *Scanner) Scan() (pos Pos, token Token, lit string) {
 			return s.scanUnquotedIdent(s.pos, "")
 		} else if ch == '@' {
 			return s.scanVariable(s.pos)
+		} else if ch == '$' {
+			return s.scanParameter(s.pos)
 		} else if ch == '"' {
 			return s.scanQuotedIdent()
 		} else if ch == '\'' {
@@ -155,6 +157,26 @@ func (s *Scanner) scanVariable(pos Pos) (Pos, Token, string) {
 	return pos, tok, lit
 }
 
+// scanParameter scans a positional parameter ($1, $2, ...), which is returned
+// as a VARIABLE.
+func (s *Scanner) scanParameter(pos Pos) (Pos, Token, string) {
+	assert(s.peek() == '$')
+	ch, _ := s.read()
+
+	s.buf.Reset()
+	s.buf.WriteRune(ch)
+	for ch, _ := s.read(); isDigit(ch); ch, _ = s.read() {
+		s.buf.WriteRune(ch)
+	}
+	s.unread()
+
+	lit := s.buf.String()
+	if len(lit) == 1 {
+		return pos, ILLEGAL, lit
+	}
+	return pos, VARIABLE, lit
+}
+
 func (s *Scanner) scanQuotedIdent() (Pos, Token, string) {
 	ch, pos := s.read()
 	assert(ch == '"')
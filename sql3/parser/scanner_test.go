@@ -34,6 +34,18 @@ func TestScanner_Scan(t *testing.T) {
 		AssertScan(t, `BEGIN`, parser.BEGIN, `BEGIN`)
 	})
 
+	t.Run("VARIABLE", func(t *testing.T) {
+		t.Run("Named", func(t *testing.T) {
+			AssertScan(t, `@foo_bar`, parser.VARIABLE, `@foo_bar`)
+		})
+		t.Run("Parameter", func(t *testing.T) {
+			AssertScan(t, `$12`, parser.VARIABLE, `$12`)
+		})
+		t.Run("NoParameterNumber", func(t *testing.T) {
+			AssertScan(t, `$a`, parser.ILLEGAL, `$`)
+		})
+	})
+
 	t.Run("STRING", func(t *testing.T) {
 		t.Run("OK", func(t *testing.T) {
 			AssertScan(t, `'this is ''a'' string'`, parser.STRING, `this is 'a' string`)
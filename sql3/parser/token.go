@@ -112,6 +112,7 @@ const (
 	CURRENT_TIMESTAMP
 	DATABASE
 	DATABASES
+	DEALLOCATE
 	DEFAULT
 	DEFERRABLE
 	DEFERRED
@@ -129,6 +130,7 @@ const (
 	EXCEPT
 	EXCLUDE
 	EXCLUSIVE
+	EXECUTE
 	EXISTS
 	EXPLAIN
 	FAIL
@@ -169,6 +171,7 @@ const (
 	LRU
 	MAP
 	MATCH
+	MATERIALIZED
 	MAX
 	MIN
 	MODEL
@@ -191,23 +194,28 @@ const (
 	OUTER
 	OVER
 	PARTITION
+	PERCENT
 	PLAN
 	PRAGMA
 	PRECEDING
 	PREDICT
+	PREPARE
 	PRIMARY
 	QUERY
 	RANGE
 	RANKED
 	RECURSIVE
 	REFERENCES
+	REFRESH
 	REGEXP
 	REGISTER
 	REINDEX
 	RELEASE
 	RENAME
+	REPEATABLE
 	REPLACE
 	RESTRICT
+	RETENTION
 	RETURNS
 	RETURN
 	RIGHT
@@ -223,6 +231,7 @@ const (
 	SPAN
 	TABLE
 	TABLES
+	TABLESAMPLE
 	TEMP
 	THEN
 	TIES
@@ -339,6 +348,7 @@ var tokens = [...]string{
 	CURRENT_TIMESTAMP: "CURRENT_TIMESTAMP",
 	DATABASE:          "DATABASE",
 	DATABASES:         "DATABASES",
+	DEALLOCATE:        "DEALLOCATE",
 	DEFAULT:           "DEFAULT",
 	DEFERRABLE:        "DEFERRABLE",
 	DEFERRED:          "DEFERRED",
@@ -356,6 +366,7 @@ var tokens = [...]string{
 	EXCEPT:            "EXCEPT",
 	EXCLUDE:           "EXCLUDE",
 	EXCLUSIVE:         "EXCLUSIVE",
+	EXECUTE:           "EXECUTE",
 	EXISTS:            "EXISTS",
 	EXPLAIN:           "EXPLAIN",
 	FAIL:              "FAIL",
@@ -396,6 +407,7 @@ var tokens = [...]string{
 	MAP:               "MAP",
 	LRU:               "LRU",
 	MATCH:             "MATCH",
+	MATERIALIZED:      "MATERIALIZED",
 	MAX:               "MAX",
 	MIN:               "MIN",
 	MODEL:             "MODEL",
@@ -418,23 +430,28 @@ var tokens = [...]string{
 	OUTER:             "OUTER",
 	OVER:              "OVER",
 	PARTITION:         "PARTITION",
+	PERCENT:           "PERCENT",
 	PLAN:              "PLAN",
 	PRAGMA:            "PRAGMA",
 	PRECEDING:         "PRECEDING",
 	PREDICT:           "PREDICT",
+	PREPARE:           "PREPARE",
 	PRIMARY:           "PRIMARY",
 	QUERY:             "QUERY",
 	RANGE:             "RANGE",
 	RANKED:            "RANKED",
 	RECURSIVE:         "RECURSIVE",
 	REFERENCES:        "REFERENCES",
+	REFRESH:           "REFRESH",
 	REGEXP:            "REGEXP",
 	REGISTER:          "REGISTER",
 	REINDEX:           "REINDEX",
 	RELEASE:           "RELEASE",
 	RENAME:            "RENAME",
+	REPEATABLE:        "REPEATABLE",
 	REPLACE:           "REPLACE",
 	RESTRICT:          "RESTRICT",
+	RETENTION:         "RETENTION",
 	RETURNS:           "RETURNS",
 	RETURN:            "RETURN",
 	RIGHT:             "RIGHT",
@@ -450,6 +467,7 @@ var tokens = [...]string{
 	SPAN:              "SPAN",
 	TABLE:             "TABLE",
 	TABLES:            "TABLES",
+	TABLESAMPLE:       "TABLESAMPLE",
 	TEMP:              "TEMP",
 	THEN:              "THEN",
 	TIES:              "TIES",
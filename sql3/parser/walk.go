@@ -48,6 +48,33 @@ func walk(v Visitor, node Node) (_ Node, err error) {
 			}
 		}
 
+	case *PrepareStatement:
+		if err := walkIdent(v, &n.Name); err != nil {
+			return node, err
+		}
+		if n.Stmt != nil {
+			if stmt, err := walk(v, n.Stmt); err != nil {
+				return node, err
+			} else if stmt == nil {
+				n.Stmt = nil
+			} else {
+				n.Stmt = stmt.(Statement)
+			}
+		}
+
+	case *ExecuteStatement:
+		if err := walkIdent(v, &n.Name); err != nil {
+			return node, err
+		}
+		if err := walkExprList(v, n.Args); err != nil {
+			return node, err
+		}
+
+	case *DeallocateStatement:
+		if err := walkIdent(v, &n.Name); err != nil {
+			return node, err
+		}
+
 	case *RollbackStatement:
 		if err := walkIdent(v, &n.SavepointName); err != nil {
 			return node, err
@@ -87,6 +114,9 @@ func walk(v Visitor, node Node) (_ Node, err error) {
 		if err := walkIdent(v, &n.Name); err != nil {
 			return node, err
 		}
+		if err := walkIdent(v, &n.NewName); err != nil {
+			return node, err
+		}
 		if err := walkIdent(v, &n.OldColumnName); err != nil {
 			return node, err
 		}
@@ -138,6 +168,11 @@ func walk(v Visitor, node Node) (_ Node, err error) {
 			return node, err
 		}
 
+	case *RefreshViewStatement:
+		if err := walkIdent(v, &n.Name); err != nil {
+			return node, err
+		}
+
 	case *DropIndexStatement:
 		if err := walkIdent(v, &n.Name); err != nil {
 			return node, err
@@ -476,6 +511,16 @@ func walk(v Visitor, node Node) (_ Node, err error) {
 			return node, err
 		}
 
+	case *SetLiteralExpr:
+		if err := walkExprList(v, n.Members); err != nil {
+			return node, err
+		}
+
+	case *TupleLiteralExpr:
+		if err := walkExprList(v, n.Members); err != nil {
+			return node, err
+		}
+
 	case *QualifiedRef:
 		if err := walkIdent(v, &n.Table); err != nil {
 			return node, err
@@ -585,6 +630,14 @@ func walk(v Visitor, node Node) (_ Node, err error) {
 		if err := walkTableQueryOptionList(v, n.QueryOptions); err != nil {
 			return node, err
 		}
+		if n.Sample != nil {
+			if err := walkExpr(v, &n.Sample.Size); err != nil {
+				return node, err
+			}
+			if err := walkExpr(v, &n.Sample.Seed); err != nil {
+				return node, err
+			}
+		}
 
 	case *TableQueryOption:
 		if err := walkIdent(v, &n.OptionName); err != nil {
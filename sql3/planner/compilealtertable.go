@@ -19,6 +19,7 @@ const (
 	alterOpDrop
 	alterOpRename
 	alterOpSet
+	alterOpRenameTable
 )
 
 // compileAlterTableStatement compiles an ALTER TABLE statement into a
@@ -51,7 +52,7 @@ func (p *ExecutionPlanner) compileAlterTableStatement(ctx context.Context, stmt
 			return nil, sql3.NewErrColumnNotFound(stmt.DropColumnName.NamePos.Line, stmt.DropColumnName.NamePos.Column, columnName)
 		}
 
-		return NewPlanOpQuery(p, NewPlanOpAlterTable(p, tableName, alterOpDrop, columnName, "", nil), p.sql), nil
+		return NewPlanOpQuery(p, NewPlanOpAlterTable(p, tableName, alterOpDrop, columnName, "", nil, nil), p.sql), nil
 	} else if stmt.Add.IsValid() {
 		col := stmt.ColumnDef
 		columnName := strings.ToLower(parser.IdentName(col.Name))
@@ -67,12 +68,81 @@ func (p *ExecutionPlanner) compileAlterTableStatement(ctx context.Context, stmt
 		if err != nil {
 			return nil, err
 		}
-		return NewPlanOpQuery(p, NewPlanOpAlterTable(p, tableName, alterOpAdd, "", columnName, column), p.sql), nil
+		return NewPlanOpQuery(p, NewPlanOpAlterTable(p, tableName, alterOpAdd, "", columnName, column, nil), p.sql), nil
 
+	} else if stmt.RenameTo.IsValid() {
+		newTableName := strings.ToLower(parser.IdentName(stmt.NewName))
+
+		// system tables can't be renamed, and neither can the table for a
+		// materialized view
+		if _, ok := systemTables.table(tableName); ok {
+			return nil, sql3.NewErrTableNotFound(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, tableName)
+		}
+		v, err := p.materializedViewForTable(ctx, tableName)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			return nil, sql3.NewErrViewIsMaterialized(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, v.name)
+		}
+
+		// does the new table name already exist
+		if _, err := p.schemaAPI.TableByName(ctx, dax.TableName(newTableName)); err == nil {
+			return nil, sql3.NewErrTableExists(stmt.NewName.NamePos.Line, stmt.NewName.NamePos.Column, newTableName)
+		} else if !isTableNotFoundError(err) {
+			return nil, err
+		}
+		v, err = p.getViewByName(ctx, newTableName)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			return nil, sql3.NewErrTableExists(stmt.NewName.NamePos.Line, stmt.NewName.NamePos.Column, newTableName)
+		}
+
+		return NewPlanOpQuery(p, NewPlanOpAlterTable(p, tableName, alterOpRenameTable, "", newTableName, nil, nil), p.sql), nil
 	} else if stmt.Rename.IsValid() {
 		oldColumnName := strings.ToLower(parser.IdentName(stmt.OldColumnName))
 		newColumnName := strings.ToLower(parser.IdentName(stmt.NewColumnName))
-		return NewPlanOpQuery(p, NewPlanOpAlterTable(p, tableName, alterOpRename, oldColumnName, newColumnName, nil), p.sql), nil
+
+		// does the old column exist and the new one not
+		found := false
+		for _, f := range tbl.Fields {
+			if strings.EqualFold(string(f.Name), oldColumnName) {
+				found = true
+			}
+			if strings.EqualFold(string(f.Name), newColumnName) {
+				return nil, sql3.NewErrDuplicateColumn(stmt.NewColumnName.NamePos.Line, stmt.NewColumnName.NamePos.Column, newColumnName)
+			}
+		}
+		if !found {
+			return nil, sql3.NewErrColumnNotFound(stmt.OldColumnName.NamePos.Line, stmt.OldColumnName.NamePos.Column, oldColumnName)
+		}
+
+		return NewPlanOpQuery(p, NewPlanOpAlterTable(p, tableName, alterOpRename, oldColumnName, newColumnName, nil, nil), p.sql), nil
+	} else if stmt.With.IsValid() {
+		o, ok := stmt.Option.(*parser.RetentionOption)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unhandled table option type '%T'", stmt.Option)
+		}
+		retention := newTableRetention(o)
+
+		// the column must be one of the table's timestamp columns
+		var fld *dax.Field
+		for _, f := range tbl.Fields {
+			if strings.EqualFold(string(f.Name), retention.column) {
+				fld = f
+				break
+			}
+		}
+		if fld == nil {
+			return nil, sql3.NewErrColumnNotFound(o.Column.NamePos.Line, o.Column.NamePos.Column, retention.column)
+		}
+		if fld.Type != dax.BaseTypeTimestamp {
+			return nil, sql3.NewErrInvalidRetentionColumn(o.Column.NamePos.Line, o.Column.NamePos.Column, retention.column)
+		}
+
+		return NewPlanOpQuery(p, NewPlanOpAlterTable(p, tableName, alterOpSet, "", "", nil, retention), p.sql), nil
 	} else {
 		return nil, sql3.NewErrInternal("unhandled alter operation")
 	}
@@ -101,6 +171,13 @@ func (p *ExecutionPlanner) analyzeAlterTableStatement(stmt *parser.AlterTableSta
 		if err != nil {
 			return err
 		}
+	} else if stmt.RenameTo.IsValid() {
+		//check the new and old are not the same
+		tableName := strings.ToLower(parser.IdentName(stmt.Name))
+		newTableName := strings.ToLower(parser.IdentName(stmt.NewName))
+		if strings.EqualFold(tableName, newTableName) {
+			return sql3.NewErrTableExists(stmt.NewName.NamePos.Line, stmt.NewName.NamePos.Column, newTableName)
+		}
 	} else if stmt.Rename.IsValid() {
 		//check the new and old are not the same
 		oldColumnName := strings.ToLower(parser.IdentName(stmt.OldColumnName))
@@ -108,6 +185,18 @@ func (p *ExecutionPlanner) analyzeAlterTableStatement(stmt *parser.AlterTableSta
 		if strings.EqualFold(oldColumnName, newColumnName) {
 			return sql3.NewErrDuplicateColumn(stmt.NewColumnName.NamePos.Line, stmt.NewColumnName.NamePos.Column, newColumnName)
 		}
+
+		//not allowed to rename the _id column, or to rename a column to _id
+		if oldColumnName == string(dax.PrimaryKeyFieldName) {
+			return sql3.NewErrTableIDColumnRename(stmt.OldColumnName.NamePos.Line, stmt.OldColumnName.NamePos.Column)
+		}
+		if newColumnName == string(dax.PrimaryKeyFieldName) {
+			return sql3.NewErrTableIDColumnAlter(stmt.NewColumnName.NamePos.Line, stmt.NewColumnName.NamePos.Column)
+		}
+	} else if stmt.With.IsValid() {
+		if o, ok := stmt.Option.(*parser.RetentionOption); ok {
+			return analyzeRetentionOption(o)
+		}
 	}
 	return nil
 }
@@ -0,0 +1,37 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// compileAnalyzeStatement compiles an ANALYZE statement into a PlanOperator.
+func (p *ExecutionPlanner) compileAnalyzeStatement(ctx context.Context, stmt *parser.AnalyzeStatement) (types.PlanOperator, error) {
+	tableName := strings.ToLower(parser.IdentName(stmt.Name))
+	return NewPlanOpQuery(p, NewPlanOpAnalyze(p, tableName), p.sql), nil
+}
+
+func (p *ExecutionPlanner) analyzeAnalyzeStatement(ctx context.Context, stmt *parser.AnalyzeStatement) error {
+	tableName := strings.ToLower(parser.IdentName(stmt.Name))
+
+	// system tables aren't backed by data that can be queried with PQL
+	if _, ok := systemTables.table(tableName); ok {
+		return sql3.NewErrUnsupported(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, true, "ANALYZE on system tables")
+	}
+
+	_, err := p.schemaAPI.TableByName(ctx, dax.TableName(tableName))
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return sql3.NewErrTableNotFound(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, tableName)
+		}
+		return err
+	}
+	return nil
+}
@@ -3,6 +3,7 @@
 package planner
 
 import (
+	"context"
 	"strings"
 
 	"github.com/featurebasedb/featurebase/v3/sql3"
@@ -12,68 +13,104 @@ import (
 
 // compileCreateFunctionStatement compiles a parser.CreateFunctionStatement AST into a PlanOperator
 func (p *ExecutionPlanner) compileCreateFunctionStatement(stmt *parser.CreateFunctionStatement) (types.PlanOperator, error) {
-	functionName := parser.IdentName(stmt.Name)
-	function := &functionSystemObject{
-		name: functionName,
-	}
+	// the definition is stored in its canonical form, without IF NOT EXISTS,
+	// and parsed again each time the function is called
+	def := parser.CloneStatement(stmt).(*parser.CreateFunctionStatement)
+	def.If, def.IfNot, def.IfNotExists = parser.Pos{}, parser.Pos{}, parser.Pos{}
 
-	lang := "sql"
-	if len(stmt.Options) > 0 {
-		for _, o := range stmt.Options {
-			switch strings.ToLower(o.Name.String()) {
-			case "language":
-				lit, ok := o.OptionExpr.(*parser.StringLit)
-				if !ok {
-					return nil, sql3.NewErrStringLiteral(o.OptionExpr.Pos().Line, o.OptionExpr.Pos().Column)
-				}
-				l := strings.ToLower(lit.Value)
-				switch l {
-				case "python":
-					lang = l
-				default:
-					return nil, sql3.NewErrInternalf("unsupported language '%s'", l)
-				}
-			}
-		}
+	function := &functionSystemObject{
+		name:     strings.ToLower(parser.IdentName(stmt.Name)),
+		language: "sql",
+		body:     def.String(),
 	}
-	function.language = lang
 
-	// TODO(pok) - hobble user defined functions for now
+	fn := NewPlanOpCreateFunction(p, stmt.IfNotExists.IsValid(), function)
 
-	switch lang {
-	case "sql":
-		return nil, sql3.NewErrInternalf("unsupported language '%s'", lang)
-	case "python":
-		// return nil, sql3.NewErrInternalf("unsupported language '%s'", lang)
+	query := NewPlanOpQuery(p, fn, p.sql)
+	return query, nil
+}
 
-		// function body is in the return statement
-		if len(stmt.Body) != 1 {
-			return nil, sql3.NewErrInternalf("unexpected body len '%d'", len(stmt.Body))
+// analyzeCreateFunctionStatement analyzes a CREATE FUNCTION statement. The
+// body of a function must be a single RETURN of an expression over the
+// function parameters; the expression is type checked against the return type
+// of the function here, so that a function which would not compile is never
+// stored.
+func (p *ExecutionPlanner) analyzeCreateFunctionStatement(ctx context.Context, stmt *parser.CreateFunctionStatement) error {
+	for _, o := range stmt.Options {
+		switch strings.ToLower(o.Name.String()) {
+		case "language":
+			lit, ok := o.OptionExpr.(*parser.StringLit)
+			if !ok {
+				return sql3.NewErrStringLiteral(o.OptionExpr.Pos().Line, o.OptionExpr.Pos().Column)
+			}
+			if !strings.EqualFold(lit.Value, "sql") {
+				return sql3.NewErrUnsupported(lit.ValuePos.Line, lit.ValuePos.Column, true, "language '"+lit.Value+"'")
+			}
+		default:
+			return sql3.NewErrUnknownIdentifier(o.Name.NamePos.Line, o.Name.NamePos.Column, o.Name.Name)
 		}
+	}
 
-		rs, ok := stmt.Body[0].(*parser.ReturnStatement)
-		if !ok {
-			return nil, sql3.NewErrInternalf("unexpected statement type '%T'", stmt.Body[0])
+	params := make(map[string]struct{})
+	for _, param := range stmt.Parameters {
+		name := strings.ToLower(param.Name.Name)
+		if _, ok := params[name]; ok {
+			return sql3.NewErrDuplicateFunctionParameter(param.Name.NamePos.Line, param.Name.NamePos.Column, param.Name.Name)
 		}
+		params[name] = struct{}{}
 
-		bexpr, ok := rs.ReturnExpr.(*parser.StringLit)
-		if !ok {
-			return nil, sql3.NewErrInternalf("unexpected expression type '%T'", rs.ReturnExpr)
+		if _, err := dataTypeFromParserType(param.Type); err != nil {
+			return sql3.NewErrUnknownType(param.Type.Name.NamePos.Line, param.Type.Name.NamePos.Column, param.Type.String())
 		}
+	}
 
-		function.body = bexpr.Value
-	default:
-		return nil, sql3.NewErrInternalf("unsupported language '%s'", lang)
+	returnType, err := dataTypeFromParserType(stmt.ReturnType)
+	if err != nil {
+		return sql3.NewErrUnknownType(stmt.ReturnType.Name.NamePos.Line, stmt.ReturnType.Name.NamePos.Column, stmt.ReturnType.String())
 	}
 
-	fn := NewPlanOpCreateFunction(p, stmt.IfNotExists.IsValid(), function)
-	fn.AddWarning("🦖 here there be dragons! CREATE FUNCTION statement is experimental.")
+	ret, err := functionReturnStatement(stmt)
+	if err != nil {
+		return err
+	}
 
-	query := NewPlanOpQuery(p, fn, p.sql)
-	return query, nil
+	// analyze a copy of the body, so the stored definition keeps any calls to
+	// other functions rather than their inlined bodies
+	expr, err := p.analyzeExpression(ctx, parser.CloneExpr(ret.ReturnExpr), stmt)
+	if err != nil {
+		return err
+	}
+	if !typesAreAssignmentCompatible(returnType, expr.DataType()) {
+		return sql3.NewErrFunctionReturnType(ret.ReturnExpr.Pos().Line, ret.ReturnExpr.Pos().Column, parser.IdentName(stmt.Name), expr.DataType().TypeDescription(), returnType.TypeDescription())
+	}
+	return nil
 }
 
-func (p *ExecutionPlanner) analyzeCreateFunctionStatement(stmt *parser.CreateFunctionStatement) error {
+// functionReturnStatement returns the RETURN statement making up the body of a
+// function, making sure its expression refers to nothing but the parameters
+// of the function.
+func functionReturnStatement(stmt *parser.CreateFunctionStatement) (*parser.ReturnStatement, error) {
+	if len(stmt.Body) != 1 {
+		return nil, sql3.NewErrFunctionBodyInvalid(stmt.Begin.Line, stmt.Begin.Column)
+	}
+	ret, ok := stmt.Body[0].(*parser.ReturnStatement)
+	if !ok {
+		return nil, sql3.NewErrFunctionBodyInvalid(stmt.Begin.Line, stmt.Begin.Column)
+	}
 
-	return nil
+	_, err := parser.Walk(parser.VisitFunc(func(node parser.Node) (parser.Node, error) {
+		switch n := node.(type) {
+		case *parser.SelectStatement:
+			return nil, sql3.NewErrFunctionBodyInvalid(n.Select.Line, n.Select.Column)
+		case *parser.Exists:
+			return nil, sql3.NewErrFunctionBodyInvalid(n.Exists.Line, n.Exists.Column)
+		case *parser.QualifiedRef:
+			return nil, sql3.NewErrColumnNotFound(n.Column.NamePos.Line, n.Column.NamePos.Column, n.String())
+		}
+		return node, nil
+	}), ret.ReturnExpr)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
 }
@@ -23,6 +23,31 @@ type createTableField struct {
 	fos      []pilosa.FieldOption
 }
 
+// tableRetention is the retention policy of a table: records whose value in
+// the (timestamp) column is older than the period are deleted.
+type tableRetention struct {
+	column string
+	period string
+}
+
+// newTableRetention returns the retention policy of a RETENTION option.
+func newTableRetention(o *parser.RetentionOption) *tableRetention {
+	return &tableRetention{
+		column: strings.ToLower(parser.IdentName(o.Column)),
+		period: o.Expr.(*parser.StringLit).Value,
+	}
+}
+
+// setTableRetention sets the retention policy of a table. The column is set
+// before the period, so that the policy only takes effect once both are set.
+func (p *ExecutionPlanner) setTableRetention(ctx context.Context, tableName string, retention *tableRetention) error {
+	tname := dax.TableName(tableName)
+	if err := p.schemaAPI.SetTableOption(ctx, tname, "retentionField", retention.column); err != nil {
+		return err
+	}
+	return p.schemaAPI.SetTableOption(ctx, tname, "retentionPeriod", retention.period)
+}
+
 // compileCreateTableStatement compiles a CREATE TABLE statement into a
 // PlanOperator.
 func (p *ExecutionPlanner) compileCreateTableStatement(ctx context.Context, stmt *parser.CreateTableStatement) (_ types.PlanOperator, err error) {
@@ -32,6 +57,7 @@ func (p *ExecutionPlanner) compileCreateTableStatement(ctx context.Context, stmt
 	// apply table options
 	keyPartitions := 0
 	description := ""
+	var retention *tableRetention
 	for _, option := range stmt.Options {
 		switch o := option.(type) {
 		case *parser.KeyPartitionsOption:
@@ -44,6 +70,8 @@ func (p *ExecutionPlanner) compileCreateTableStatement(ctx context.Context, stmt
 		case *parser.CommentOption:
 			e := o.Expr.(*parser.StringLit)
 			description = e.Value
+		case *parser.RetentionOption:
+			retention = newTableRetention(o)
 		}
 	}
 
@@ -68,7 +96,7 @@ func (p *ExecutionPlanner) compileCreateTableStatement(ctx context.Context, stmt
 
 		columns = append(columns, column)
 	}
-	cop := NewPlanOpCreateTable(p, tableName, failIfExists, isKeyed, keyPartitions, description, columns)
+	cop := NewPlanOpCreateTable(p, tableName, failIfExists, isKeyed, keyPartitions, description, retention, columns)
 	if keyPartitions > 0 {
 		cop.AddWarning("The value of KEYPARTITIONS is currently ignored")
 	}
@@ -208,6 +236,9 @@ func (p *ExecutionPlanner) compileColumn(ctx context.Context, col *parser.Column
 		}
 		column.fos = append(column.fos, pilosa.OptFieldTypeDecimal(scale, min, max))
 
+	case dax.BaseTypeGeo:
+		column.fos = append(column.fos, pilosa.OptFieldTypeGeo())
+
 	case dax.BaseTypeID:
 		column.fos = append(column.fos, pilosa.OptFieldTypeMutex(cacheType, cacheSize))
 
@@ -303,6 +334,26 @@ func (p *ExecutionPlanner) analyzeCreateTableStatement(stmt *parser.CreateTableS
 				return sql3.NewErrStringLiteral(o.Expr.Pos().Line, o.Expr.Pos().Column)
 			}
 
+		case *parser.RetentionOption:
+			if err := analyzeRetentionOption(o); err != nil {
+				return err
+			}
+			//the column must be one of the table's timestamp columns
+			columnName := strings.ToLower(parser.IdentName(o.Column))
+			var col *parser.ColumnDefinition
+			for _, c := range stmt.Columns {
+				if strings.EqualFold(parser.IdentName(c.Name), columnName) {
+					col = c
+					break
+				}
+			}
+			if col == nil {
+				return sql3.NewErrColumnNotFound(o.Column.NamePos.Line, o.Column.NamePos.Column, columnName)
+			}
+			if !strings.EqualFold(parser.IdentName(col.Type.Name), dax.BaseTypeTimestamp) {
+				return sql3.NewErrInvalidRetentionColumn(o.Column.NamePos.Line, o.Column.NamePos.Column, columnName)
+			}
+
 		default:
 			return sql3.NewErrInternalf("unhandled table option type '%T'", option)
 		}
@@ -311,6 +362,19 @@ func (p *ExecutionPlanner) analyzeCreateTableStatement(stmt *parser.CreateTableS
 	return nil
 }
 
+// analyzeRetentionOption checks the period of a RETENTION option for a CREATE
+// or ALTER TABLE; the column is checked by the caller.
+func analyzeRetentionOption(o *parser.RetentionOption) error {
+	period, ok := o.Expr.(*parser.StringLit)
+	if !ok {
+		return sql3.NewErrStringLiteral(o.Expr.Pos().Line, o.Expr.Pos().Column)
+	}
+	if d, err := time.ParseDuration(period.Value); err != nil || d < 0 {
+		return sql3.NewErrInvalidDuration(o.Expr.Pos().Line, o.Expr.Pos().Column, period.Value)
+	}
+	return nil
+}
+
 // analyze the column def for a CREATE or ALTER TABLE
 func (p *ExecutionPlanner) analyzeColumn(typeName string, col *parser.ColumnDefinition) error {
 	// handledConstraints keeps track of the constraints which have been
@@ -14,7 +14,8 @@ import (
 func (p *ExecutionPlanner) compileCreateViewStatement(stmt *parser.CreateViewStatement) (types.PlanOperator, error) {
 	viewName := strings.ToLower(parser.IdentName(stmt.Name))
 	view := &viewSystemObject{
-		name: viewName,
+		name:         viewName,
+		materialized: stmt.Materialized.IsValid(),
 	}
 
 	// compile select
@@ -17,6 +17,16 @@ import (
 // PlanOperator.
 func (p *ExecutionPlanner) compileDropTableStatement(ctx context.Context, stmt *parser.DropTableStatement) (_ types.PlanOperator, err error) {
 	tableName := strings.ToLower(parser.IdentName(stmt.Name))
+
+	// the table for a materialized view has to be dropped with the view
+	v, err := p.materializedViewForTable(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if v != nil {
+		return nil, sql3.NewErrViewIsMaterialized(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, v.name)
+	}
+
 	tname := dax.TableName(tableName)
 	tbl, err := p.schemaAPI.TableByName(ctx, tname)
 	if err != nil {
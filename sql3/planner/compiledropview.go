@@ -21,6 +21,9 @@ func (p *ExecutionPlanner) compileDropViewStatement(ctx context.Context, stmt *p
 	if v == nil && !stmt.IfExists.IsValid() {
 		return nil, sql3.NewErrViewNotFound(0, 0, viewName)
 	}
+	if v != nil && stmt.Materialized.IsValid() && !v.materialized {
+		return nil, sql3.NewErrViewIsNotMaterialized(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, viewName)
+	}
 
 	return NewPlanOpQuery(p, NewPlanOpDropView(p, stmt.IfExists.IsValid(), viewName), p.sql), nil
 }
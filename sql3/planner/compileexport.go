@@ -0,0 +1,70 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// compileExportStatement compiles a parser.ExportStatement AST into a
+// PlanOperator.
+func (p *ExecutionPlanner) compileExportStatement(stmt *parser.ExportStatement) (types.PlanOperator, error) {
+	// analysis has already checked these are all string literals
+	options := &exportOptions{
+		target:       stmt.Target.(*parser.StringLit).Value,
+		format:       strings.ToUpper(stmt.Format.(*parser.StringLit).Value),
+		output:       strings.ToUpper(stmt.Output.(*parser.StringLit).Value),
+		hasHeaderRow: stmt.HeaderRow != nil,
+	}
+
+	selOp, err := p.compileSelectStatement(stmt.Query, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPlanOpQuery(p, NewPlanOpExport(p, options, selOp), p.sql), nil
+}
+
+// analyzeExportStatement analyzes a parser.ExportStatement AST.
+func (p *ExecutionPlanner) analyzeExportStatement(ctx context.Context, stmt *parser.ExportStatement) error {
+	if _, err := p.analyzeSelectStatement(ctx, stmt.Query); err != nil {
+		return err
+	}
+
+	// target should be a string literal
+	if _, ok := stmt.Target.(*parser.StringLit); !ok {
+		return sql3.NewErrStringLiteral(stmt.Target.Pos().Line, stmt.Target.Pos().Column)
+	}
+
+	// check we have a format specifier, and that it is one we can write
+	if stmt.Format == nil {
+		return sql3.NewErrFormatSpecifierExpected(stmt.With.Line, stmt.With.Column)
+	}
+	format, ok := stmt.Format.(*parser.StringLit)
+	if !ok {
+		return sql3.NewErrStringLiteral(stmt.Format.Pos().Line, stmt.Format.Pos().Column)
+	}
+	switch strings.ToUpper(format.Value) {
+	case "CSV", "NDJSON", "PARQUET":
+	default:
+		return sql3.NewErrInvalidFormatSpecifier(stmt.Format.Pos().Line, stmt.Format.Pos().Column, format.Value)
+	}
+
+	// check we have an output specifier, either FILE or URL
+	if stmt.Output == nil {
+		return sql3.NewErrOutputSpecifierExpected(stmt.With.Line, stmt.With.Column)
+	}
+	output, ok := stmt.Output.(*parser.StringLit)
+	if !ok {
+		return sql3.NewErrStringLiteral(stmt.Output.Pos().Line, stmt.Output.Pos().Column)
+	}
+	if !(strings.EqualFold(output.Value, "FILE") || strings.EqualFold(output.Value, "URL")) {
+		return sql3.NewErrInvalidOutputSpecifier(stmt.Output.Pos().Line, stmt.Output.Pos().Column, output.Value)
+	}
+	return nil
+}
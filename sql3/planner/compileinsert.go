@@ -171,6 +171,7 @@ func (p *ExecutionPlanner) analyzeInsertStatement(ctx context.Context, stmt *par
 	// Check each of the expressions.
 	for _, tuple := range stmt.TupleList {
 		for i, expr := range tuple.Exprs {
+			typeParameters(expr, typeNames[i])
 			e, err := p.analyzeExpression(ctx, expr, stmt)
 			if err != nil {
 				return err
@@ -0,0 +1,369 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// CompilePreparedPlan compiles the statement sql into a query plan, binding
+// parameters to its positional parameters ($1, $2, ...). The analysis of the
+// statement is cached by its text, and reused for as long as the schema of
+// the tables it refers to does not change.
+func (p *ExecutionPlanner) CompilePreparedPlan(ctx context.Context, sql string, parameters []interface{}) (types.PlanOperator, error) {
+	prepared, err := p.preparedStatement(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	if len(parameters) != prepared.ParameterCount {
+		return nil, sql3.NewErrParameterCount(0, 0, prepared.ParameterCount, len(parameters))
+	}
+	p.parameters = parameters
+
+	// the cached statement is shared, and compiling can rewrite it
+	stmt := parser.CloneStatement(prepared.Statement)
+	if txn, ok := p.sessionTransaction(ctx); ok {
+		return p.compileTransactionStatement(ctx, txn, stmt, p.compileAnalyzedPlan)
+	}
+	return p.compileAnalyzedPlan(ctx, stmt)
+}
+
+// compilePrepareStatement compiles a PREPARE statement into a PlanOperator.
+// The statement is analyzed straight away, so that errors in it are reported
+// by PREPARE rather than by the first EXECUTE.
+func (p *ExecutionPlanner) compilePrepareStatement(ctx context.Context, stmt *parser.PrepareStatement) (types.PlanOperator, error) {
+	sessionID, ok := fbcontext.SessionID(ctx)
+	if !ok {
+		return nil, sql3.NewErrPreparedStatementSessionRequired(stmt.Prepare.Line, stmt.Prepare.Column)
+	}
+	userID, _ := fbcontext.UserID(ctx)
+	name := parser.IdentName(stmt.Name)
+	if _, ok := p.systemLayerAPI.PreparedStatements().GetPrepared(sessionID, userID, name); ok {
+		return nil, sql3.NewErrPreparedStatementExists(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, name)
+	}
+	sql := stmt.Stmt.String()
+	if _, err := p.preparedStatement(ctx, sql); err != nil {
+		return nil, err
+	}
+	return NewPlanOpQuery(p, NewPlanOpPrepare(p, sessionID, userID, name, sql), p.sql), nil
+}
+
+// compileExecuteStatement compiles the statement an EXECUTE statement names,
+// with the values it gives for the parameters.
+func (p *ExecutionPlanner) compileExecuteStatement(ctx context.Context, stmt *parser.ExecuteStatement) (types.PlanOperator, error) {
+	sessionID, ok := fbcontext.SessionID(ctx)
+	if !ok {
+		return nil, sql3.NewErrPreparedStatementSessionRequired(stmt.Execute.Line, stmt.Execute.Column)
+	}
+	userID, _ := fbcontext.UserID(ctx)
+	name := parser.IdentName(stmt.Name)
+	sql, ok := p.systemLayerAPI.PreparedStatements().GetPrepared(sessionID, userID, name)
+	if !ok {
+		return nil, sql3.NewErrPreparedStatementNotFound(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, name)
+	}
+
+	parameters := make([]interface{}, len(stmt.Args))
+	for i, arg := range stmt.Args {
+		value, err := parameterValue(arg)
+		if err != nil {
+			return nil, err
+		}
+		parameters[i] = value
+	}
+	return p.CompilePreparedPlan(ctx, sql, parameters)
+}
+
+// compileDeallocateStatement compiles a DEALLOCATE statement into a
+// PlanOperator.
+func (p *ExecutionPlanner) compileDeallocateStatement(ctx context.Context, stmt *parser.DeallocateStatement) (types.PlanOperator, error) {
+	sessionID, ok := fbcontext.SessionID(ctx)
+	if !ok {
+		return nil, sql3.NewErrPreparedStatementSessionRequired(stmt.Deallocate.Line, stmt.Deallocate.Column)
+	}
+	userID, _ := fbcontext.UserID(ctx)
+	if stmt.All.IsValid() {
+		return NewPlanOpQuery(p, NewPlanOpDeallocate(p, sessionID, userID, ""), p.sql), nil
+	}
+	name := parser.IdentName(stmt.Name)
+	if _, ok := p.systemLayerAPI.PreparedStatements().GetPrepared(sessionID, userID, name); !ok {
+		return nil, sql3.NewErrPreparedStatementNotFound(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, name)
+	}
+	return NewPlanOpQuery(p, NewPlanOpDeallocate(p, sessionID, userID, name), p.sql), nil
+}
+
+// preparedStatement returns the analysis of the statement sql, from the cache
+// if the schema it was analyzed against has not changed since, otherwise by
+// parsing and analyzing it again.
+func (p *ExecutionPlanner) preparedStatement(ctx context.Context, sql string) (*pilosa.PreparedStatement, error) {
+	cache := p.systemLayerAPI.PreparedStatements()
+	if prepared, ok := cache.GetStatement(sql); ok {
+		tables := make(map[dax.TableName]*dax.Table, len(prepared.Tables))
+		for _, name := range prepared.Tables {
+			tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(name))
+			if err != nil && !isTableNotFoundError(err) {
+				return nil, err
+			}
+			tables[dax.TableName(name)] = tbl
+		}
+		if version, err := schemaVersion(tables); err != nil {
+			return nil, err
+		} else if version == prepared.SchemaVersion {
+			return prepared, nil
+		}
+	}
+
+	stmt, err := parser.NewParser(strings.NewReader(sql)).ParseStatement()
+	if err != nil {
+		return nil, err
+	}
+	n, err := parameterCount(stmt)
+	if err != nil {
+		return nil, err
+	}
+	switch stmt.(type) {
+	case *parser.SelectStatement, *parser.InsertStatement, *parser.DeleteStatement:
+	default:
+		return nil, sql3.NewErrParametersNotAllowed(0, 0)
+	}
+
+	// analyze the statement, recording the tables it looks up
+	recorder := &schemaRecorder{
+		SchemaAPI: p.schemaAPI,
+		tables:    make(map[dax.TableName]*dax.Table),
+	}
+	schemaAPI := p.schemaAPI
+	p.schemaAPI = recorder
+	err = p.analyzePlan(ctx, stmt)
+	p.schemaAPI = schemaAPI
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := schemaVersion(recorder.tables)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, 0, len(recorder.tables))
+	for name := range recorder.tables {
+		tables = append(tables, string(name))
+	}
+	sort.Strings(tables)
+
+	prepared := &pilosa.PreparedStatement{
+		SQL:            sql,
+		Statement:      stmt,
+		ParameterCount: n,
+		Tables:         tables,
+		SchemaVersion:  version,
+	}
+	cache.AddStatement(prepared)
+	return prepared, nil
+}
+
+// schemaRecorder wraps a SchemaAPI, recording the tables looked up through it
+// (nil for those which do not exist).
+type schemaRecorder struct {
+	pilosa.SchemaAPI
+	tables map[dax.TableName]*dax.Table
+}
+
+func (s *schemaRecorder) TableByName(ctx context.Context, tname dax.TableName) (*dax.Table, error) {
+	tbl, err := s.SchemaAPI.TableByName(ctx, tname)
+	if err == nil || isTableNotFoundError(err) {
+		s.tables[tname] = tbl
+	}
+	return tbl, err
+}
+
+// schemaVersion returns a version for the schema of tables, which changes
+// whenever any of the tables is created, dropped or altered.
+func schemaVersion(tables map[dax.TableName]*dax.Table) (string, error) {
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	h := fnv.New64a()
+	for _, name := range names {
+		buf, err := json.Marshal(tables[dax.TableName(name)])
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte(name))
+		h.Write(buf)
+	}
+	return strconv.FormatUint(h.Sum64(), 16), nil
+}
+
+// parameterCount returns the number of positional parameters stmt takes,
+// which is the highest parameter number used in it.
+func parameterCount(stmt parser.Statement) (int, error) {
+	var n int
+	_, err := parser.Walk(parser.VisitFunc(func(node parser.Node) (parser.Node, error) {
+		if v, ok := node.(*parser.Variable); ok && v.IsParameter() {
+			if v.ParameterIndex() < 0 {
+				return nil, sql3.NewErrUnknownIdentifier(v.NamePos.Line, v.NamePos.Column, v.Name)
+			}
+			if v.ParameterIndex() >= n {
+				n = v.ParameterIndex() + 1
+			}
+		}
+		return node, nil
+	}), stmt)
+	return n, err
+}
+
+// compileParameter compiles a positional parameter into a literal of its
+// (analyzed) type holding the value bound to it.
+func (p *ExecutionPlanner) compileParameter(param *parser.Variable) (types.PlanExpression, error) {
+	idx := param.ParameterIndex()
+	if idx < 0 || idx >= len(p.parameters) {
+		return nil, sql3.NewErrParameterCount(param.NamePos.Line, param.NamePos.Column, idx+1, len(p.parameters))
+	}
+	lit, err := parameterLiteral(param, p.parameters[idx])
+	if err != nil {
+		return nil, err
+	}
+	return p.compileExpr(lit)
+}
+
+// parameterLiteral returns a literal of the type of param holding value.
+// Values are those decoded from json (with numbers as json.Number), or those
+// of the literals given to EXECUTE.
+func parameterLiteral(param *parser.Variable, value interface{}) (parser.Expr, error) {
+	pos := param.NamePos
+	invalid := sql3.NewErrInvalidParameterValue(pos.Line, pos.Column, param.Name, value, param.DataType().TypeDescription())
+	if value == nil {
+		return &parser.NullLit{ValuePos: pos}, nil
+	}
+
+	switch param.DataType().(type) {
+	case *parser.DataTypeBool:
+		if v, ok := value.(bool); ok {
+			return &parser.BoolLit{ValuePos: pos, Value: v}, nil
+		}
+
+	case *parser.DataTypeID, *parser.DataTypeInt:
+		if v, ok := parameterNumber(value); ok {
+			if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return &parser.IntegerLit{ValuePos: pos, Value: v}, nil
+			}
+		}
+
+	case *parser.DataTypeDecimal:
+		if v, ok := parameterNumber(value); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return &parser.FloatLit{ValuePos: pos, Value: strconv.FormatFloat(f, 'f', -1, 64)}, nil
+			}
+		}
+
+	case *parser.DataTypeString:
+		if v, ok := value.(string); ok {
+			return &parser.StringLit{ValuePos: pos, Value: v}, nil
+		}
+
+	case *parser.DataTypeTimestamp:
+		if v, ok := value.(string); ok {
+			if lit := (&parser.StringLit{ValuePos: pos, Value: v}).ConvertToTimestamp(); lit != nil {
+				return lit, nil
+			}
+		}
+
+	case *parser.DataTypeIDSet, *parser.DataTypeIDSetQuantum:
+		if values, ok := value.([]interface{}); ok && len(values) > 0 {
+			set := &parser.SetLiteralExpr{Lbracket: pos, Rbracket: pos, ResultDataType: parser.NewDataTypeIDSet()}
+			for _, value := range values {
+				v, ok := parameterNumber(value)
+				if !ok {
+					return nil, invalid
+				} else if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+					return nil, invalid
+				}
+				set.Members = append(set.Members, &parser.IntegerLit{ValuePos: pos, Value: v})
+			}
+			return set, nil
+		}
+
+	case *parser.DataTypeStringSet, *parser.DataTypeStringSetQuantum:
+		if values, ok := value.([]interface{}); ok && len(values) > 0 {
+			set := &parser.SetLiteralExpr{Lbracket: pos, Rbracket: pos, ResultDataType: parser.NewDataTypeStringSet()}
+			for _, value := range values {
+				v, ok := value.(string)
+				if !ok {
+					return nil, invalid
+				}
+				set.Members = append(set.Members, &parser.StringLit{ValuePos: pos, Value: v})
+			}
+			return set, nil
+		}
+	}
+	return nil, invalid
+}
+
+// parameterNumber returns the text of a numeric parameter value.
+func parameterNumber(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String(), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case int:
+		return strconv.Itoa(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// parameterValue returns the value of a literal given to EXECUTE, in the form
+// parameterLiteral expects.
+func parameterValue(expr parser.Expr) (interface{}, error) {
+	switch e := expr.(type) {
+	case *parser.NullLit:
+		return nil, nil
+	case *parser.BoolLit:
+		return e.Value, nil
+	case *parser.IntegerLit:
+		return json.Number(e.Value), nil
+	case *parser.FloatLit:
+		return json.Number(e.Value), nil
+	case *parser.StringLit:
+		return e.Value, nil
+	case *parser.DateLit:
+		return e.Value.Format(time.RFC3339Nano), nil
+	case *parser.UnaryExpr:
+		if e.Op == parser.MINUS {
+			switch x := e.X.(type) {
+			case *parser.IntegerLit:
+				return json.Number("-" + x.Value), nil
+			case *parser.FloatLit:
+				return json.Number("-" + x.Value), nil
+			}
+		}
+	case *parser.SetLiteralExpr:
+		values := make([]interface{}, len(e.Members))
+		for i, member := range e.Members {
+			value, err := parameterValue(member)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	}
+	return nil, sql3.NewErrParameterValueLiteralExpected(expr.Pos().Line, expr.Pos().Column)
+}
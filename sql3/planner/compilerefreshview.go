@@ -0,0 +1,35 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// compileRefreshViewStatement compiles a REFRESH MATERIALIZED VIEW statement
+// into a PlanOperator.
+func (p *ExecutionPlanner) compileRefreshViewStatement(ctx context.Context, stmt *parser.RefreshViewStatement) (types.PlanOperator, error) {
+	viewName := strings.ToLower(parser.IdentName(stmt.Name))
+	query := NewPlanOpQuery(p, NewPlanOpRefreshView(p, viewName), p.sql)
+	return query, nil
+}
+
+func (p *ExecutionPlanner) analyzeRefreshViewStatement(ctx context.Context, stmt *parser.RefreshViewStatement) error {
+	viewName := strings.ToLower(parser.IdentName(stmt.Name))
+	v, err := p.getViewByName(ctx, viewName)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return sql3.NewErrViewNotFound(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, viewName)
+	}
+	if !v.materialized {
+		return sql3.NewErrViewIsNotMaterialized(stmt.Name.NamePos.Line, stmt.Name.NamePos.Column, viewName)
+	}
+	return nil
+}
@@ -40,7 +40,11 @@ func (p *ExecutionPlanner) compileSelectStatement(stmt *parser.SelectStatement,
 		case *parser.QualifiedRef:
 			groupByExprs = append(groupByExprs, newQualifiedRefPlanExpression(expr.Table.Name, expr.Column.Name, expr.ColumnIndex, expr.DataType()))
 		default:
-			return nil, sql3.NewErrInternalf("unsupported expression type in GROUP BY clause: %T", expr)
+			planExpr, err := p.compileExpr(expr)
+			if err != nil {
+				return nil, err
+			}
+			groupByExprs = append(groupByExprs, planExpr)
 		}
 	}
 
@@ -175,6 +179,10 @@ func (p *ExecutionPlanner) compileSelectStatement(stmt *parser.SelectStatement,
 		nonAggregateReferences := make([]*qualifiedRefPlanExpression, 0)
 		for _, expr := range projections {
 			InspectExpression(expr, func(expr types.PlanExpression) bool {
+				if isGroupByExpression(expr, groupByExprs) {
+					// references inside a grouped expression are grouped too
+					return false
+				}
 				switch ex := expr.(type) {
 				case types.Aggregable:
 					//return false for these, because thats as far down we want to inspect
@@ -416,6 +424,9 @@ func (p *ExecutionPlanner) compileSource(scope *PlanOpQuery, source parser.Sourc
 		// doing this check here because we don't have a 'system' flag that exists in the FB schema
 		st, ok := systemTables.table(tableName)
 		if ok {
+			if sourceExpr.Sample != nil {
+				return nil, sql3.NewErrTableSampleNotSupported(sourceExpr.Sample.TableSample.Line, sourceExpr.Sample.TableSample.Column, tableName)
+			}
 			var op types.PlanOperator
 			op = NewPlanOpSystemTable(p, st)
 			if st.requiresFanout {
@@ -449,12 +460,21 @@ func (p *ExecutionPlanner) compileSource(scope *PlanOpQuery, source parser.Sourc
 			extractColumns = append(extractColumns, oc.ColumnName)
 		}
 
+		scan := NewPlanOpPQLTableScan(p, tableName, extractColumns, queryHints)
+		if sourceExpr.Sample != nil {
+			sample, err := newTableSample(sourceExpr.Sample)
+			if err != nil {
+				return nil, err
+			}
+			scan.sample = sample
+		}
+
 		if sourceExpr.Alias != nil {
 			aliasName := parser.IdentName(sourceExpr.Alias)
 
-			return NewPlanOpRelAlias(aliasName, NewPlanOpPQLTableScan(p, tableName, extractColumns, queryHints)), nil
+			return NewPlanOpRelAlias(aliasName, scan), nil
 		}
-		return NewPlanOpPQLTableScan(p, tableName, extractColumns, queryHints), nil
+		return scan, nil
 
 	case *parser.TableValuedFunction:
 		callExpr, err := p.compileCallExpr(sourceExpr.Call)
@@ -541,8 +561,12 @@ func (p *ExecutionPlanner) analyzeSource(ctx context.Context, source parser.Sour
 			return nil, err
 		}
 
-		// if view is not null, it exists
-		if view != nil {
+		// if view is not null, it exists; materialized views are read from
+		// their table like any other table
+		if view != nil && !view.materialized {
+			if source.Sample != nil {
+				return nil, sql3.NewErrTableSampleNotSupported(source.Sample.TableSample.Line, source.Sample.TableSample.Column, objectName)
+			}
 			// parse the select statement
 			ast, err := parser.NewParser(strings.NewReader(view.statement)).ParseStatement()
 			if err != nil {
@@ -570,6 +594,16 @@ func (p *ExecutionPlanner) analyzeSource(ctx context.Context, source parser.Sour
 			return paren, nil
 		}
 
+		// materialized views are read from the table holding their result,
+		// under the name of the view
+		if view != nil {
+			if source.Alias == nil {
+				source.Alias = &parser.Ident{NamePos: source.Name.NamePos, Name: objectName}
+			}
+			objectName = view.table()
+			source.Name = &parser.Ident{NamePos: source.Name.NamePos, Name: objectName}
+		}
+
 		// if we got to here, not a view, so do table stuff
 
 		// check table exists
@@ -622,10 +656,16 @@ func (p *ExecutionPlanner) analyzeSource(ctx context.Context, source parser.Sour
 			}
 		}
 
+		if source.Sample != nil {
+			if _, err := newTableSample(source.Sample); err != nil {
+				return nil, err
+			}
+		}
+
 		return source, nil
 
 	case *parser.TableValuedFunction:
-		return nil, sql3.NewErrUnsupported(0, 0, true, "table valued function")
+		return p.analyzeTableValuedFunction(ctx, source, scope)
 
 	case *parser.SelectStatement:
 		expr, err := p.analyzeSelectStatement(ctx, source)
@@ -731,6 +771,25 @@ func (p *ExecutionPlanner) analyzeSelectStatement(ctx context.Context, stmt *par
 	return stmt, nil
 }
 
+// isGroupByExpression returns true if expr is one of the group by
+// expressions which isn't a column reference. Expressions are matched by
+// their string form, the same way aggregates are matched to the columns of
+// a group by operator.
+func isGroupByExpression(expr types.PlanExpression, groupByExprs []types.PlanExpression) bool {
+	if expr == nil {
+		return false
+	}
+	for _, gbe := range groupByExprs {
+		if _, ok := gbe.(*qualifiedRefPlanExpression); ok {
+			continue
+		}
+		if strings.EqualFold(expr.String(), gbe.String()) {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *ExecutionPlanner) analyzeSelectStatementWildcards(stmt *parser.SelectStatement) error {
 	if !stmt.HasWildcard() {
 		return nil
@@ -805,7 +864,7 @@ func (p *ExecutionPlanner) columnsFromSource(source parser.Source) ([]*parser.Re
 		}
 		return result, nil
 
-	case *parser.QualifiedTableName:
+	case *parser.QualifiedTableName, *parser.TableValuedFunction:
 		for _, oc := range src.PossibleOutputColumns() {
 			result = append(result, &parser.ResultColumn{
 				Expr: &parser.QualifiedRef{
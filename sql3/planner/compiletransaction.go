@@ -0,0 +1,114 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"strings"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// sessionTransaction returns the open transaction of the session the request
+// in ctx belongs to, if there is one. Sessions belong to the user making the
+// request, so the transaction of another user's session is never returned.
+func (p *ExecutionPlanner) sessionTransaction(ctx context.Context) (*pilosa.SQLTransaction, bool) {
+	sessionID, ok := fbcontext.SessionID(ctx)
+	if !ok {
+		return nil, false
+	}
+	userID, _ := fbcontext.UserID(ctx)
+	return p.systemLayerAPI.Transactions().GetTransaction(sessionID, userID)
+}
+
+// compileBeginStatement compiles a BEGIN statement into a PlanOperator.
+func (p *ExecutionPlanner) compileBeginStatement(ctx context.Context, stmt *parser.BeginStatement) (types.PlanOperator, error) {
+	sessionID, ok := fbcontext.SessionID(ctx)
+	if !ok {
+		return nil, sql3.NewErrTransactionSessionRequired(stmt.Begin.Line, stmt.Begin.Column)
+	}
+	userID, _ := fbcontext.UserID(ctx)
+	if _, ok := p.systemLayerAPI.Transactions().GetTransaction(sessionID, userID); ok {
+		return nil, sql3.NewErrTransactionInProgress(stmt.Begin.Line, stmt.Begin.Column)
+	}
+	return NewPlanOpQuery(p, NewPlanOpBeginTransaction(p, sessionID, userID), p.sql), nil
+}
+
+// compileCommitStatement compiles a COMMIT statement into a PlanOperator.
+func (p *ExecutionPlanner) compileCommitStatement(ctx context.Context, stmt *parser.CommitStatement) (types.PlanOperator, error) {
+	pos := stmt.Commit
+	if stmt.End.IsValid() {
+		pos = stmt.End
+	}
+	txn, ok := p.sessionTransaction(ctx)
+	if !ok {
+		return nil, sql3.NewErrNoTransactionInProgress(pos.Line, pos.Column)
+	}
+	return NewPlanOpQuery(p, NewPlanOpCommitTransaction(p, txn.SessionID, txn.UserID), p.sql), nil
+}
+
+// compileRollbackStatement compiles a ROLLBACK statement into a PlanOperator.
+func (p *ExecutionPlanner) compileRollbackStatement(ctx context.Context, stmt *parser.RollbackStatement) (types.PlanOperator, error) {
+	if stmt.SavepointName != nil {
+		return nil, sql3.NewErrUnsupported(stmt.To.Line, stmt.To.Column, false, "savepoints")
+	}
+	txn, ok := p.sessionTransaction(ctx)
+	if !ok {
+		return nil, sql3.NewErrNoTransactionInProgress(stmt.Rollback.Line, stmt.Rollback.Column)
+	}
+	return NewPlanOpQuery(p, NewPlanOpRollbackTransaction(p, txn.SessionID, txn.UserID), p.sql), nil
+}
+
+// compileTransactionStatement compiles a statement issued while txn is open.
+// Statements run immediately in the transaction; reads see the transaction's
+// own writes, and writes are held by the transaction until it commits. Any
+// error fails the transaction, after which every statement other than COMMIT
+// or ROLLBACK is refused. compile compiles the statement itself.
+func (p *ExecutionPlanner) compileTransactionStatement(ctx context.Context, txn *pilosa.SQLTransaction, stmt parser.Statement, compile func(context.Context, parser.Statement) (types.PlanOperator, error)) (types.PlanOperator, error) {
+	switch stmt := stmt.(type) {
+	case *parser.BeginStatement:
+		return nil, sql3.NewErrTransactionInProgress(stmt.Begin.Line, stmt.Begin.Column)
+	case *parser.CommitStatement:
+		return p.compileCommitStatement(ctx, stmt)
+	case *parser.RollbackStatement:
+		return p.compileRollbackStatement(ctx, stmt)
+	}
+
+	if err := txn.Err(); err != nil {
+		return nil, sql3.NewErrTransactionAborted(0, 0, err)
+	}
+
+	var tableName string
+	switch stmt := stmt.(type) {
+	case *parser.SelectStatement, *parser.ShowDatabasesStatement, *parser.ShowTablesStatement,
+		*parser.ShowColumnsStatement, *parser.ShowCreateTableStatement:
+	case *parser.InsertStatement:
+		tableName = parser.IdentName(stmt.Table)
+	case *parser.BulkInsertStatement:
+		tableName = parser.IdentName(stmt.Table)
+	case *parser.DeleteStatement:
+		tableName = parser.IdentName(stmt.TableName.Name)
+	default:
+		err := sql3.NewErrStatementNotAllowedInTransaction(0, 0)
+		txn.Fail(err)
+		return nil, err
+	}
+
+	op, err := compile(ctx, stmt)
+	if err != nil {
+		txn.Fail(err)
+		return nil, err
+	}
+
+	// Run the statement itself in the transaction, rather than the PlanOpQuery
+	// wrapping it, so the query keeps its warnings.
+	if query, ok := op.(*PlanOpQuery); ok {
+		query.ChildOp = NewPlanOpTransactionStatement(p, txn, strings.ToLower(tableName), query.ChildOp)
+		return query, nil
+	}
+	return NewPlanOpTransactionStatement(p, txn, strings.ToLower(tableName), op), nil
+}
@@ -37,6 +37,16 @@ type ExecutionPlanner struct {
 	importer       pilosa.Importer
 	logger         logger.Logger
 	sql            string
+
+	// the names of the user defined functions whose calls are being inlined
+	inlinedFunctions []string
+
+	// the statistics collected by ANALYZE for the tables in the query, read
+	// once per table by the optimizer
+	statistics map[string]*tableStatistics
+
+	// the values of the positional parameters of a prepared statement
+	parameters []interface{}
 }
 
 func NewExecutionPlanner(executor pilosa.Executor, schemaAPI pilosa.SchemaAPI, systemAPI pilosa.SystemAPI, systemLayerAPI pilosa.SystemLayerAPI, importer pilosa.Importer, logger logger.Logger, sql string) *ExecutionPlanner {
@@ -56,21 +66,54 @@ func NewExecutionPlanner(executor pilosa.Executor, schemaAPI pilosa.SchemaAPI, s
 // The act of compiling includes an analysis step that does semantic analysis of the AST, this includes
 // type checking, and sometimes AST rewriting. The compile phase uses the type-checked and rewritten AST
 // to produce a query plan.
+// Statements issued while the request's session has an open transaction are
+// compiled in the context of that transaction.
+// Prepared statements belong to the session rather than to its transaction.
 func (p *ExecutionPlanner) CompilePlan(ctx context.Context, stmt parser.Statement) (types.PlanOperator, error) {
+	switch stmt := stmt.(type) {
+	case *parser.ExecuteStatement:
+		return p.compileExecuteStatement(ctx, stmt)
+	case *parser.PrepareStatement, *parser.DeallocateStatement:
+		// the parameters of the statement a PREPARE names are bound when it
+		// is executed, so these skip the check for unbound parameters
+		return p.compileAnalyzedPlan(ctx, stmt)
+	}
+	if txn, ok := p.sessionTransaction(ctx); ok {
+		return p.compileTransactionStatement(ctx, txn, stmt, p.compilePlan)
+	}
+	return p.compilePlan(ctx, stmt)
+}
+
+func (p *ExecutionPlanner) compilePlan(ctx context.Context, stmt parser.Statement) (types.PlanOperator, error) {
+	// parameters can only be bound through a prepared statement
+	if n, err := parameterCount(stmt); err != nil {
+		return nil, err
+	} else if n > 0 {
+		return nil, sql3.NewErrParameterCount(0, 0, n, 0)
+	}
+
 	// call analyze first
 	err := p.analyzePlan(ctx, stmt)
 	if err != nil {
 		return nil, err
 	}
+	return p.compileAnalyzedPlan(ctx, stmt)
+}
 
+// compileAnalyzedPlan compiles an analyzed statement into a query plan.
+func (p *ExecutionPlanner) compileAnalyzedPlan(ctx context.Context, stmt parser.Statement) (_ types.PlanOperator, err error) {
 	var rootOperator types.PlanOperator
 	switch stmt := stmt.(type) {
 	case *parser.SelectStatement:
 		rootOperator, err = p.compileSelectStatement(stmt, false)
+	case *parser.AnalyzeStatement:
+		rootOperator, err = p.compileAnalyzeStatement(ctx, stmt)
 	case *parser.ShowDatabasesStatement:
 		rootOperator, err = p.compileShowDatabasesStatement(ctx, stmt)
 	case *parser.CopyStatement:
 		rootOperator, err = p.compileCopyStatement(stmt)
+	case *parser.ExportStatement:
+		rootOperator, err = p.compileExportStatement(stmt)
 	case *parser.PredictStatement:
 		rootOperator, err = p.compilePredictStatement(ctx, stmt)
 	case *parser.ShowTablesStatement:
@@ -99,6 +142,8 @@ func (p *ExecutionPlanner) CompilePlan(ctx context.Context, stmt parser.Statemen
 		rootOperator, err = p.compileDropViewStatement(ctx, stmt)
 	case *parser.DropModelStatement:
 		rootOperator, err = p.compileDropModelStatement(stmt)
+	case *parser.RefreshViewStatement:
+		rootOperator, err = p.compileRefreshViewStatement(ctx, stmt)
 	case *parser.InsertStatement:
 		rootOperator, err = p.compileInsertStatement(ctx, stmt)
 	case *parser.BulkInsertStatement:
@@ -109,6 +154,16 @@ func (p *ExecutionPlanner) CompilePlan(ctx context.Context, stmt parser.Statemen
 		rootOperator, err = p.compileCreateModelStatement(stmt)
 	case *parser.CreateFunctionStatement:
 		rootOperator, err = p.compileCreateFunctionStatement(stmt)
+	case *parser.BeginStatement:
+		rootOperator, err = p.compileBeginStatement(ctx, stmt)
+	case *parser.CommitStatement:
+		rootOperator, err = p.compileCommitStatement(ctx, stmt)
+	case *parser.RollbackStatement:
+		rootOperator, err = p.compileRollbackStatement(ctx, stmt)
+	case *parser.PrepareStatement:
+		rootOperator, err = p.compilePrepareStatement(ctx, stmt)
+	case *parser.DeallocateStatement:
+		rootOperator, err = p.compileDeallocateStatement(ctx, stmt)
 
 	default:
 		return nil, sql3.NewErrInternalf("cannot plan statement: %T", stmt)
@@ -139,10 +194,14 @@ func (p *ExecutionPlanner) analyzePlan(ctx context.Context, stmt parser.Statemen
 	case *parser.SelectStatement:
 		_, err := p.analyzeSelectStatement(ctx, stmt)
 		return err
+	case *parser.AnalyzeStatement:
+		return p.analyzeAnalyzeStatement(ctx, stmt)
 	case *parser.ShowDatabasesStatement:
 		return nil
 	case *parser.CopyStatement:
 		return p.analyzeCopyStatement(ctx, stmt)
+	case *parser.ExportStatement:
+		return p.analyzeExportStatement(ctx, stmt)
 	case *parser.PredictStatement:
 		return p.analyzePredictStatement(ctx, stmt)
 	case *parser.ShowTablesStatement:
@@ -171,6 +230,8 @@ func (p *ExecutionPlanner) analyzePlan(ctx context.Context, stmt parser.Statemen
 		return nil
 	case *parser.DropModelStatement:
 		return nil
+	case *parser.RefreshViewStatement:
+		return p.analyzeRefreshViewStatement(ctx, stmt)
 	case *parser.InsertStatement:
 		return p.analyzeInsertStatement(ctx, stmt)
 	case *parser.BulkInsertStatement:
@@ -180,7 +241,11 @@ func (p *ExecutionPlanner) analyzePlan(ctx context.Context, stmt parser.Statemen
 	case *parser.CreateModelStatement:
 		return p.analyzeCreateModelStatement(ctx, stmt)
 	case *parser.CreateFunctionStatement:
-		return p.analyzeCreateFunctionStatement(stmt)
+		return p.analyzeCreateFunctionStatement(ctx, stmt)
+	case *parser.BeginStatement, *parser.CommitStatement, *parser.RollbackStatement:
+		return nil
+	case *parser.PrepareStatement, *parser.DeallocateStatement:
+		return nil
 
 	default:
 		return sql3.NewErrInternalf("cannot analyze statement: %T", stmt)
@@ -106,6 +106,18 @@ func (s *systemTableDefinitionsWrapper) DeleteField(ctx context.Context, tname d
 	return s.schemaAPI.DeleteField(ctx, tname, fname)
 }
 
+func (s *systemTableDefinitionsWrapper) RenameTable(ctx context.Context, tname dax.TableName, newName dax.TableName) error {
+	return s.schemaAPI.RenameTable(ctx, tname, newName)
+}
+
+func (s *systemTableDefinitionsWrapper) RenameField(ctx context.Context, tname dax.TableName, fname dax.FieldName, newName dax.FieldName) error {
+	return s.schemaAPI.RenameField(ctx, tname, fname, newName)
+}
+
+func (s *systemTableDefinitionsWrapper) SetTableOption(ctx context.Context, tname dax.TableName, option string, value string) error {
+	return s.schemaAPI.SetTableOption(ctx, tname, option, value)
+}
+
 func indexInfoFromSystemTableB(st *systemTable) (*dax.Table, error) {
 	fields := make([]*dax.Field, 0)
 
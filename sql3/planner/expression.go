@@ -12,6 +12,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/featurebasedb/featurebase/v3/hll"
 	"github.com/featurebasedb/featurebase/v3/pql"
 	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/parser"
@@ -308,15 +309,24 @@ type binOpPlanExpression struct {
 	rhs types.PlanExpression
 
 	resultDataType parser.ExprDataType
+
+	// pattern is the compiled rhs of a REGEXP or GLOB operator when the rhs
+	// is a literal
+	pattern *regexp.Regexp
 }
 
 func newBinOpPlanExpression(lhs types.PlanExpression, op parser.Token, rhs types.PlanExpression, dataType parser.ExprDataType) *binOpPlanExpression {
-	return &binOpPlanExpression{
+	n := &binOpPlanExpression{
 		lhs:            lhs,
 		op:             op,
 		rhs:            rhs,
 		resultDataType: dataType,
 	}
+	if lit, ok := rhs.(*stringLiteralPlanExpression); ok && isPatternOp(op) {
+		// an invalid pattern is reported when evaluated
+		n.pattern, _ = compilePattern(op, lit.value)
+	}
+	return n
 }
 
 func (n *binOpPlanExpression) Evaluate(currentRow []interface{}) (interface{}, error) {
@@ -324,6 +334,13 @@ func (n *binOpPlanExpression) Evaluate(currentRow []interface{}) (interface{}, e
 	if err != nil {
 		return nil, err
 	}
+
+	// false AND anything is false, so don't evaluate the rhs; the optimizer
+	// orders filter predicates so the most selective are evaluated first
+	if n.op == parser.AND && evalLhs == false {
+		return false, nil
+	}
+
 	evalRhs, err := n.rhs.Evaluate(currentRow)
 	if err != nil {
 		return nil, err
@@ -337,6 +354,10 @@ func (n *binOpPlanExpression) Evaluate(currentRow []interface{}) (interface{}, e
 		return isNull, nil
 	}
 
+	if isPatternOp(n.op) {
+		return n.evaluatePattern(evalLhs, evalRhs)
+	}
+
 	coercedDataType, err := typeCoerceType(n.lhs.Type(), n.rhs.Type(), parser.Pos{Line: 0, Column: 0})
 	if err != nil {
 		return nil, err
@@ -1514,18 +1535,16 @@ func (n *inOpPlanExpression) WithChildren(children ...types.PlanExpression) (typ
 
 // callPlanExpression is a function call
 type callPlanExpression struct {
-	name         string
-	args         []types.PlanExpression
-	dataType     parser.ExprDataType
-	udfReference *functionSystemObject
+	name     string
+	args     []types.PlanExpression
+	dataType parser.ExprDataType
 }
 
-func newCallPlanExpression(name string, args []types.PlanExpression, dataType parser.ExprDataType, udfReference *functionSystemObject) *callPlanExpression {
+func newCallPlanExpression(name string, args []types.PlanExpression, dataType parser.ExprDataType) *callPlanExpression {
 	return &callPlanExpression{
-		name:         name,
-		args:         args,
-		dataType:     dataType,
-		udfReference: udfReference,
+		name:     name,
+		args:     args,
+		dataType: dataType,
 	}
 }
 
@@ -1592,10 +1611,12 @@ func (n *callPlanExpression) Evaluate(currentRow []interface{}) (interface{}, er
 		return n.EvaluateDatetimeAdd(currentRow)
 	case "DATETIMEDIFF":
 		return n.EvaluateDatetimeDiff(currentRow)
+		// geo functions
+	case "ST_WITHIN":
+		return n.EvaluateStWithin(currentRow)
+	case "ST_DWITHIN":
+		return n.EvaluateStDWithin(currentRow)
 	default:
-		if n.udfReference != nil {
-			return n.evaluateUserDefinedFunction(currentRow)
-		}
 		return nil, sql3.NewErrInternalf("unhandled function name '%s'", n.name)
 	}
 }
@@ -1637,7 +1658,7 @@ func (n *callPlanExpression) WithChildren(children ...types.PlanExpression) (typ
 	if len(children) != len(n.args) {
 		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
 	}
-	return newCallPlanExpression(n.name, children, n.dataType, n.udfReference), nil
+	return newCallPlanExpression(n.name, children, n.dataType), nil
 }
 
 // aliasPlanExpression is a alias ref
@@ -2170,6 +2191,10 @@ func (n *castPlanExpression) Evaluate(currentRow []interface{}) (interface{}, er
 	if err != nil {
 		return nil, err
 	}
+	// nil if anything is nil
+	if evalLhs == nil {
+		return nil, nil
+	}
 	switch sourceType := n.lhs.Type().(type) {
 	case *parser.DataTypeInt:
 		nl, nlok := evalLhs.(int64)
@@ -2679,6 +2704,9 @@ func (p *ExecutionPlanner) compileExpr(expr parser.Expr) (_ types.PlanExpression
 		return p.compileExpr(expr.X)
 
 	case *parser.Variable:
+		if expr.IsParameter() {
+			return p.compileParameter(expr)
+		}
 		ref := newVariableRefPlanExpression(expr.Name, expr.VariableIndex, expr.DataType())
 		return ref, nil
 
@@ -2860,6 +2888,9 @@ func (p *ExecutionPlanner) compileBinaryExpr(expr *parser.BinaryExpr) (_ types.P
 	case parser.LIKE, parser.NOTLIKE:
 		return newBinOpPlanExpression(x, expr.Op, y, expr.ResultDataType), nil
 
+	case parser.REGEXP, parser.NOTREGEXP, parser.GLOB, parser.NOTGLOB:
+		return newBinOpPlanExpression(x, expr.Op, y, expr.ResultDataType), nil
+
 	default:
 		return nil, sql3.NewErrInternalf("unexpected binary expression operator: %s", expr.Op)
 	}
@@ -2890,6 +2921,19 @@ func (p *ExecutionPlanner) compileCallExpr(expr *parser.Call) (_ types.PlanExpre
 		}
 		return agg, nil
 
+	case "APPROX_COUNT_DISTINCT":
+		precision := hll.DefaultPrecision
+		if len(args) == 2 {
+			// analysis has already checked this is an integer literal in range
+			v, err := args[1].Evaluate(nil)
+			if err != nil {
+				return nil, err
+			}
+			precision = int(v.(int64))
+		}
+		agg := newApproxCountDistinctPlanExpression(args[0], precision, expr.ResultDataType)
+		return agg, nil
+
 	case "SUM":
 		agg := newSumPlanExpression(args[0], expr.ResultDataType)
 		return agg, nil
@@ -2919,12 +2963,8 @@ func (p *ExecutionPlanner) compileCallExpr(expr *parser.Call) (_ types.PlanExpre
 		return agg, nil
 
 	default:
-		// could be a udf - try to look it up in functions
-		fn, err := p.getFunctionByName(strings.ToLower(callName))
-		if err != nil {
-			return nil, err
-		}
-		return newCallPlanExpression(parser.IdentName(expr.Name), args, expr.ResultDataType, fn), nil
+		// calls to user defined functions have already been inlined by analysis
+		return newCallPlanExpression(parser.IdentName(expr.Name), args, expr.ResultDataType), nil
 	}
 }
 
@@ -3000,6 +3040,66 @@ func wildCardToRegexp(pattern string) string {
 	return result.String()
 }
 
+// isPatternOp returns true for the REGEXP and GLOB operators and their
+// negations.
+func isPatternOp(op parser.Token) bool {
+	switch op {
+	case parser.REGEXP, parser.NOTREGEXP, parser.GLOB, parser.NOTGLOB:
+		return true
+	default:
+		return false
+	}
+}
+
+// compilePattern compiles the pattern of a REGEXP or GLOB operator.
+func compilePattern(op parser.Token, pattern string) (*regexp.Regexp, error) {
+	if op == parser.GLOB || op == parser.NOTGLOB {
+		pattern = pql.GlobToRegexp(pattern)
+	}
+	return regexp.Compile(pattern)
+}
+
+// evaluatePattern evaluates a REGEXP or GLOB operator. A string set matches
+// if any of its members match.
+func (n *binOpPlanExpression) evaluatePattern(evalLhs, evalRhs interface{}) (interface{}, error) {
+	// if either side is nil, return nil
+	if evalLhs == nil || evalRhs == nil {
+		return nil, nil
+	}
+
+	re := n.pattern
+	if re == nil {
+		pattern, ok := evalRhs.(string)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected pattern type '%T'", evalRhs)
+		}
+		var err error
+		if re, err = compilePattern(n.op, pattern); err != nil {
+			return nil, sql3.NewErrInvalidPattern(0, 0, n.op.String(), pattern)
+		}
+	}
+
+	var matched bool
+	switch v := evalLhs.(type) {
+	case string:
+		matched = re.MatchString(v)
+	case []string:
+		for _, m := range v {
+			if re.MatchString(m) {
+				matched = true
+				break
+			}
+		}
+	default:
+		return nil, sql3.NewErrInternalf("unexpected type '%T'", evalLhs)
+	}
+
+	if n.op == parser.NOTREGEXP || n.op == parser.NOTGLOB {
+		matched = !matched
+	}
+	return matched, nil
+}
+
 // timeFromString attempts to parse the string to a time.Time using a series of
 // time formats.
 func timestampFromString(s string) (time.Time, error) {
@@ -40,7 +40,7 @@ func TestExpressions(t *testing.T) {
 		iop := newInOpPlanExpression(newIntLiteralPlanExpression(10), parser.IN, newIntLiteralPlanExpression(20))
 		assert.Equal(t, iop.String(), "10 in (20)")
 
-		callop := newCallPlanExpression("foo", []types.PlanExpression{newIntLiteralPlanExpression(10)}, parser.NewDataTypeInt(), nil)
+		callop := newCallPlanExpression("foo", []types.PlanExpression{newIntLiteralPlanExpression(10)}, parser.NewDataTypeInt())
 		assert.Equal(t, callop.String(), "foo(10)")
 
 		alop := newAliasPlanExpression("frobny", newIntLiteralPlanExpression(10))
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/featurebasedb/featurebase/v3/hll"
 	"github.com/featurebasedb/featurebase/v3/pql"
 	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/parser"
@@ -77,6 +78,43 @@ func (c *aggregateCountDistinct) Eval(ctx context.Context) (interface{}, error)
 	return int64(len(c.valueSeen)), nil
 }
 
+// aggregator for the APPROX_COUNT_DISTINCT function
+type aggregateApproxCountDistinct struct {
+	sketch *hll.Sketch
+	expr   types.PlanExpression
+}
+
+func NewAggApproxCountDistinctBuffer(child types.PlanExpression, precision int) (*aggregateApproxCountDistinct, error) {
+	sketch, err := hll.New(precision)
+	if err != nil {
+		return nil, err
+	}
+	return &aggregateApproxCountDistinct{sketch, child}, nil
+}
+
+func (c *aggregateApproxCountDistinct) Update(ctx context.Context, row types.Row) error {
+	v, err := c.expr.Evaluate(row)
+	if err != nil {
+		return err
+	}
+
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case int64:
+		c.sketch.AddInt64(value)
+	case string:
+		c.sketch.AddString(value)
+	default:
+		c.sketch.AddString(fmt.Sprintf("%v", value))
+	}
+	return nil
+}
+
+func (c *aggregateApproxCountDistinct) Eval(ctx context.Context) (interface{}, error) {
+	return int64(c.sketch.Count()), nil
+}
+
 // countStarPlanExpression handles COUNT(*)
 type countStarPlanExpression struct {
 	arg            types.PlanExpression
@@ -263,6 +301,70 @@ func (n *countDistinctPlanExpression) WithChildren(children ...types.PlanExpress
 	return newCountDistinctPlanExpression(children[0], n.returnDataType), nil
 }
 
+// approxCountDistinctPlanExpression handles APPROX_COUNT_DISTINCT()
+type approxCountDistinctPlanExpression struct {
+	arg            types.PlanExpression
+	precision      int
+	returnDataType parser.ExprDataType
+}
+
+var _ types.Aggregable = (*approxCountDistinctPlanExpression)(nil)
+
+func newApproxCountDistinctPlanExpression(arg types.PlanExpression, precision int, returnDataType parser.ExprDataType) *approxCountDistinctPlanExpression {
+	return &approxCountDistinctPlanExpression{
+		arg:            arg,
+		precision:      precision,
+		returnDataType: returnDataType,
+	}
+}
+
+func (n *approxCountDistinctPlanExpression) Evaluate(currentRow []interface{}) (interface{}, error) {
+	arg, ok := n.arg.(*qualifiedRefPlanExpression)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected aggregate function arg type '%T'", n.arg)
+	}
+	return currentRow[arg.columnIndex], nil
+}
+
+func (n *approxCountDistinctPlanExpression) NewBuffer() (types.AggregationBuffer, error) {
+	return NewAggApproxCountDistinctBuffer(n, n.precision)
+}
+
+func (n *approxCountDistinctPlanExpression) FirstChildExpr() types.PlanExpression {
+	return n.arg
+}
+
+func (n *approxCountDistinctPlanExpression) Type() parser.ExprDataType {
+	return n.returnDataType
+}
+
+func (n *approxCountDistinctPlanExpression) String() string {
+	return fmt.Sprintf("approx_count_distinct(%s, %d)", n.arg.String(), n.precision)
+}
+
+func (n *approxCountDistinctPlanExpression) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_expr"] = fmt.Sprintf("%T", n)
+	result["description"] = n.String()
+	result["dataType"] = n.Type().TypeDescription()
+	result["arg"] = n.arg.Plan()
+	result["precision"] = n.precision
+	return result
+}
+
+func (n *approxCountDistinctPlanExpression) Children() []types.PlanExpression {
+	return []types.PlanExpression{
+		n.arg,
+	}
+}
+
+func (n *approxCountDistinctPlanExpression) WithChildren(children ...types.PlanExpression) (types.PlanExpression, error) {
+	if len(children) != 1 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return newApproxCountDistinctPlanExpression(children[0], n.precision, n.returnDataType), nil
+}
+
 // aggregator for the SUM function
 type aggregateSum struct {
 	sum  interface{}
@@ -83,7 +83,7 @@ func (p *ExecutionPlanner) analyzeExpression(ctx context.Context, expr parser.Ex
 			}
 			return p.analyzeExpression(ctx, ident, scope)
 
-		case *parser.InsertStatement:
+		case *parser.InsertStatement, *parser.CreateFunctionStatement:
 			return nil, sql3.NewErrColumnNotFound(e.NamePos.Line, e.NamePos.Column, e.Name)
 
 		case *parser.DeleteStatement:
@@ -114,6 +114,9 @@ func (p *ExecutionPlanner) analyzeExpression(ctx context.Context, expr parser.Ex
 		}
 
 	case *parser.Variable:
+		if e.IsParameter() {
+			return p.analyzeParameter(e)
+		}
 		switch sc := scope.(type) {
 		case *parser.BulkInsertStatement:
 			// get the name of the variable without the @
@@ -132,6 +135,20 @@ func (p *ExecutionPlanner) analyzeExpression(ctx context.Context, expr parser.Ex
 				}
 			}
 			return nil, sql3.NewErrUnknownIdentifier(e.NamePos.Line, e.NamePos.Column, varname)
+		case *parser.CreateFunctionStatement:
+			for idx, param := range sc.Parameters {
+				if strings.EqualFold(e.Name, param.Name.Name) {
+					e.VariableIndex = idx
+
+					dataType, err := dataTypeFromParserType(param.Type)
+					if err != nil {
+						return nil, sql3.NewErrUnknownType(e.NamePos.Line, e.NamePos.Column, param.Type.String())
+					}
+					e.VarDataType = dataType
+					return e, nil
+				}
+			}
+			return nil, sql3.NewErrUnknownIdentifier(e.NamePos.Line, e.NamePos.Column, e.Name)
 		default:
 			return nil, sql3.NewErrInternalf("unhandled scope type '%T'", sc)
 		}
@@ -396,17 +413,26 @@ func (p *ExecutionPlanner) analyzeUnaryExpression(ctx context.Context, expr *par
 
 func (p *ExecutionPlanner) analyzeBinaryExpression(ctx context.Context, expr *parser.BinaryExpr, scope parser.Statement) (parser.Expr, error) {
 
-	//analyze both sides first
-	x, err := p.analyzeExpression(ctx, expr.X, scope)
+	//analyze both sides first; a parameter takes its type from the other
+	//side, so if only the lhs is a parameter the rhs is analyzed first
+	first, second := &expr.X, &expr.Y
+	if isParameter(expr.X) && !isParameter(expr.Y) {
+		first, second = &expr.Y, &expr.X
+	}
+	analyzed, err := p.analyzeExpression(ctx, *first, scope)
 	if err != nil {
 		return nil, err
 	}
-	expr.X = x
-	y, err := p.analyzeExpression(ctx, expr.Y, scope)
+	*first = analyzed
+	if analyzed != nil {
+		typeParameters(*second, analyzed.DataType())
+	}
+	analyzed, err = p.analyzeExpression(ctx, *second, scope)
 	if err != nil {
 		return nil, err
 	}
-	expr.Y = y
+	*second = analyzed
+	x, y := expr.X, expr.Y
 
 	// check nil for either of these expressions after they were ananlyzed, they may have been eliminated
 	// in which case we return the remaining one or nil if both have been eliminated
@@ -769,11 +795,73 @@ func (p *ExecutionPlanner) analyzeBinaryExpression(ctx context.Context, expr *pa
 		expr.ResultDataType = parser.NewDataTypeBool()
 		return expr, nil
 
+	case parser.REGEXP, parser.NOTREGEXP, parser.GLOB, parser.NOTGLOB:
+		if !typeIsCompatibleWithPatternOperator(x.DataType()) {
+			return nil, sql3.NewErrTypeIncompatibleWithPatternOperator(x.Pos().Line, x.Pos().Column, op.String(), x.DataType().TypeDescription())
+		}
+		if !typeIsCompatibleWithLikeOperator(y.DataType()) {
+			return nil, sql3.NewErrTypeIncompatibleWithPatternOperator(y.Pos().Line, y.Pos().Column, op.String(), y.DataType().TypeDescription())
+		}
+		// check literal patterns up front rather than on every row
+		if lit, ok := y.(*parser.StringLit); ok {
+			if _, err := compilePattern(op, lit.Value); err != nil {
+				return nil, sql3.NewErrInvalidPattern(y.Pos().Line, y.Pos().Column, op.String(), lit.Value)
+			}
+		}
+		expr.ResultDataType = parser.NewDataTypeBool()
+		return expr, nil
+
 	default:
 		return nil, sql3.NewErrInternalf("unexpected binary expression operator: %s", op)
 	}
 }
 
+// analyzeParameter analyzes a positional parameter. Its type is normally set
+// from the expression it is compared with or assigned to before it is
+// analyzed; a parameter used anywhere else is a string.
+func (p *ExecutionPlanner) analyzeParameter(expr *parser.Variable) (parser.Expr, error) {
+	if expr.ParameterIndex() < 0 {
+		return nil, sql3.NewErrUnknownIdentifier(expr.NamePos.Line, expr.NamePos.Column, expr.Name)
+	}
+	if expr.VarDataType == nil {
+		expr.VarDataType = parser.NewDataTypeString()
+	}
+	return expr, nil
+}
+
+// isParameter returns true if expr is a positional parameter.
+func isParameter(expr parser.Expr) bool {
+	v, ok := expr.(*parser.Variable)
+	return ok && v.IsParameter()
+}
+
+// typeParameters sets the type of the positional parameters in expr which
+// have not been typed yet to dataType. It looks through parens, lists and
+// ranges, so that `a IN ($1, $2)` and `a BETWEEN $1 AND $2` work.
+func typeParameters(expr parser.Expr, dataType parser.ExprDataType) {
+	if dataType == nil {
+		return
+	}
+	if _, ok := dataType.(*parser.DataTypeVoid); ok {
+		return
+	}
+	switch e := expr.(type) {
+	case *parser.Variable:
+		if e.IsParameter() && e.VarDataType == nil {
+			e.VarDataType = dataType
+		}
+	case *parser.ParenExpr:
+		typeParameters(e.X, dataType)
+	case *parser.ExprList:
+		for _, ex := range e.Exprs {
+			typeParameters(ex, dataType)
+		}
+	case *parser.Range:
+		typeParameters(e.X, dataType)
+		typeParameters(e.Y, dataType)
+	}
+}
+
 func (p *ExecutionPlanner) analyzeRangeExpression(ctx context.Context, expr *parser.Range, scope parser.Statement) (parser.Expr, error) {
 	//analyze subscripts
 	x, err := p.analyzeExpression(ctx, expr.X, scope)
@@ -4,9 +4,11 @@ package planner
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/hll"
 	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/parser"
 )
@@ -37,6 +39,36 @@ func (p *ExecutionPlanner) analyzeCallExpression(ctx context.Context, call *pars
 		//COUNT always returns int
 		call.ResultDataType = parser.NewDataTypeInt()
 
+	case "APPROX_COUNT_DISTINCT":
+		// can't do this on a *
+		if call.Star.IsValid() && len(call.Args) == 0 {
+			return nil, sql3.NewErrExpectedColumnReference(call.Star.Line, call.Star.Column)
+		}
+
+		// a column, and optionally the precision of the sketch
+		if len(call.Args) < 1 || len(call.Args) > 2 {
+			return nil, sql3.NewErrCallParameterCountMismatch(call.Rparen.Line, call.Rparen.Column, call.Name.Name, 2, len(call.Args))
+		}
+
+		//make sure it's a qualified ref
+		if _, ok := call.Args[0].(*parser.QualifiedRef); !ok {
+			return nil, sql3.NewErrExpectedColumnReference(call.Args[0].Pos().Line, call.Args[0].Pos().Column)
+		}
+
+		// precision must be an integer literal in the range hll supports
+		if len(call.Args) == 2 {
+			lit, ok := call.Args[1].(*parser.IntegerLit)
+			if !ok {
+				return nil, sql3.NewErrIntegerLiteral(call.Args[1].Pos().Line, call.Args[1].Pos().Column)
+			}
+			precision, err := strconv.Atoi(lit.Value)
+			if err != nil || precision < hll.MinPrecision || precision > hll.MaxPrecision {
+				return nil, sql3.NewErrValueOutOfRange(lit.ValuePos.Line, lit.ValuePos.Column, lit.Value)
+			}
+		}
+
+		call.ResultDataType = parser.NewDataTypeInt()
+
 	case "SUM":
 		// can't do a sum on *
 		if call.Star.IsValid() && len(call.Args) == 0 {
@@ -334,17 +366,139 @@ func (p *ExecutionPlanner) analyzeCallExpression(ctx context.Context, call *pars
 		return p.analyzeFunctionDatetimeAdd(call, scope)
 	case "DATETIMEDIFF":
 		return p.analyzeFunctionDateTimeDiff(call, scope)
+	// geo functions
+	case "ST_WITHIN":
+		return p.analyzeFunctionStWithin(call, scope)
+	case "ST_DWITHIN":
+		return p.analyzeFunctionStDWithin(call, scope)
 	default:
 		// could be a udf - try to look it up in functions
-		fn, err := p.getFunctionByName(call.Name.Name)
+		fn, err := p.getFunctionByName(strings.ToLower(call.Name.Name))
 		if err != nil {
 			return nil, err
 		}
 		if fn != nil {
-			return p.analyzeUserDefinedFunction(call, scope, fn)
+			return p.analyzeUserDefinedFunction(ctx, call, scope, fn)
 		}
 
 		return nil, sql3.NewErrCallUnknownFunction(call.Name.NamePos.Line, call.Name.NamePos.Column, call.Name.Name)
 	}
 	return call, nil
 }
+
+// analyzeTableValuedFunction analyzes a function used as a source in a FROM
+// clause, and populates its output columns.
+func (p *ExecutionPlanner) analyzeTableValuedFunction(ctx context.Context, source *parser.TableValuedFunction, scope parser.Statement) (parser.Source, error) {
+	call := source.Call
+	switch strings.ToUpper(call.Name.Name) {
+	case "FUNNEL":
+		if err := p.analyzeFunnelFunction(ctx, call, scope); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, sql3.NewErrCallUnknownFunction(call.Name.NamePos.Line, call.Name.NamePos.Column, call.Name.Name)
+	}
+
+	tvfResultType, ok := call.ResultDataType.(*parser.DataTypeSubtable)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected table valued function type '%T'", call.ResultDataType)
+	}
+	source.OutputColumns = source.OutputColumns[:0]
+	for i, col := range tvfResultType.Columns {
+		source.OutputColumns = append(source.OutputColumns, &parser.SourceOutputColumn{
+			TableName:   source.TableName(),
+			ColumnName:  col.Name,
+			ColumnIndex: i,
+			Datatype:    col.DataType,
+		})
+	}
+	return source, nil
+}
+
+// analyzeFunnelFunction analyzes a call to
+// funnel(table, column, steps, window [, from [, to [, ordered]]]), which
+// returns the number of records reaching each of the steps, a set literal of
+// values of the time quantum column, within the window. It is executed as a
+// PQL Funnel() call.
+func (p *ExecutionPlanner) analyzeFunnelFunction(ctx context.Context, call *parser.Call, scope parser.Statement) error {
+	if len(call.Args) < 4 || len(call.Args) > 7 {
+		return sql3.NewErrCallParameterCountMismatch(call.Rparen.Line, call.Rparen.Column, call.Name.Name, 4, len(call.Args))
+	}
+
+	// the table and column may be given as identifiers or strings, and are
+	// passed on as strings
+	for i := 0; i < 2; i++ {
+		if id, ok := call.Args[i].(*parser.Ident); ok {
+			call.Args[i] = &parser.StringLit{ValuePos: id.NamePos, Value: id.Name}
+		}
+	}
+	for i, a := range call.Args {
+		arg, err := p.analyzeExpression(ctx, a, scope)
+		if err != nil {
+			return err
+		}
+		call.Args[i] = arg
+	}
+
+	tableLit, ok := call.Args[0].(*parser.StringLit)
+	if !ok {
+		return sql3.NewErrStringLiteral(call.Args[0].Pos().Line, call.Args[0].Pos().Column)
+	}
+	columnLit, ok := call.Args[1].(*parser.StringLit)
+	if !ok {
+		return sql3.NewErrStringLiteral(call.Args[1].Pos().Line, call.Args[1].Pos().Column)
+	}
+	tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(tableLit.Value))
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return sql3.NewErrTableOrViewNotFound(tableLit.ValuePos.Line, tableLit.ValuePos.Column, tableLit.Value)
+		}
+		return err
+	}
+	fld, ok := tbl.Field(dax.FieldName(columnLit.Value))
+	if !ok {
+		return sql3.NewErrColumnNotFound(columnLit.ValuePos.Line, columnLit.ValuePos.Column, columnLit.Value)
+	}
+
+	// the steps must be values of the column
+	steps, ok := call.Args[2].(*parser.SetLiteralExpr)
+	if !ok {
+		return sql3.NewErrSetExpressionExpected(call.Args[2].Pos().Line, call.Args[2].Pos().Column)
+	}
+	var valueType parser.ExprDataType
+	switch fld.Type {
+	case dax.BaseTypeStringSetQ:
+		valueType = parser.NewDataTypeString()
+		if _, ok := steps.ResultDataType.(*parser.DataTypeStringSet); !ok {
+			return sql3.NewErrTypeMismatch(steps.Lbracket.Line, steps.Lbracket.Column, steps.ResultDataType.TypeDescription(), parser.NewDataTypeStringSet().TypeDescription())
+		}
+	case dax.BaseTypeIDSetQ:
+		valueType = parser.NewDataTypeID()
+		if _, ok := steps.ResultDataType.(*parser.DataTypeIDSet); !ok {
+			return sql3.NewErrTypeMismatch(steps.Lbracket.Line, steps.Lbracket.Column, steps.ResultDataType.TypeDescription(), parser.NewDataTypeIDSet().TypeDescription())
+		}
+	default:
+		return sql3.NewErrTimeQuantumExpressionExpected(columnLit.ValuePos.Line, columnLit.ValuePos.Column)
+	}
+
+	// window, from and to are strings, or null to leave them unbounded
+	for i := 3; i < len(call.Args) && i < 6; i++ {
+		switch call.Args[i].(type) {
+		case *parser.StringLit, *parser.NullLit:
+		default:
+			return sql3.NewErrStringLiteral(call.Args[i].Pos().Line, call.Args[i].Pos().Column)
+		}
+	}
+	if len(call.Args) == 7 {
+		if _, ok := call.Args[6].(*parser.BoolLit); !ok {
+			return sql3.NewErrBoolLiteral(call.Args[6].Pos().Line, call.Args[6].Pos().Column)
+		}
+	}
+
+	call.ResultDataType = parser.NewDataTypeSubtable([]*parser.SubtableColumn{
+		{Name: "step", DataType: parser.NewDataTypeInt()},
+		{Name: "value", DataType: valueType},
+		{Name: "count", DataType: parser.NewDataTypeInt()},
+	})
+	return nil
+}
@@ -175,6 +175,44 @@ func (p *ExecutionPlanner) generatePQLCallFromExpr(ctx context.Context, expr typ
 
 			return call, nil
 
+		case "ST_WITHIN", "ST_DWITHIN":
+			// only geo columns can be pushed down; points in other forms
+			// (e.g. strings) are handled by evaluating the call
+			col, ok := expr.args[0].(*qualifiedRefPlanExpression)
+			if !ok {
+				return nil, sql3.NewErrInternalf("unexpected argument type '%T'", expr.args[0])
+			}
+			if _, ok := col.dataType.(*parser.DataTypeGeo); !ok {
+				return nil, sql3.NewErrInternalf("unexpected argument type '%s'", col.dataType.TypeDescription())
+			}
+			vals := make([]interface{}, len(expr.args)-1)
+			for i, arg := range expr.args[1:] {
+				v, err := geoLiteralValue(arg)
+				if err != nil {
+					return nil, err
+				}
+				vals[i] = v
+			}
+
+			if strings.ToUpper(expr.name) == "ST_WITHIN" {
+				return &pql.Call{
+					Name: "Within",
+					Args: map[string]interface{}{
+						"field": strings.ToLower(col.columnName),
+						"bbox":  vals,
+					},
+				}, nil
+			}
+			return &pql.Call{
+				Name: "Near",
+				Args: map[string]interface{}{
+					"field":  strings.ToLower(col.columnName),
+					"lat":    vals[0],
+					"lon":    vals[1],
+					"radius": vals[2],
+				},
+			}, nil
+
 		default:
 			return nil, sql3.NewErrInternalf("unsupported scalar function '%s'", expr.name)
 		}
@@ -445,6 +483,39 @@ func (p *ExecutionPlanner) generatePQLCallFromBinaryExpr(ctx context.Context, ex
 			return nil, sql3.NewErrInternalf("unsupported type for binary expression: %v (%T)", typ, typ)
 		}
 
+	case parser.REGEXP, parser.GLOB:
+		lhs, ok := expr.lhs.(*qualifiedRefPlanExpression)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected lhs %T", expr.lhs)
+		}
+		if strings.EqualFold(lhs.columnName, string(dax.PrimaryKeyFieldName)) {
+			return nil, sql3.NewErrUnsupported(0, 0, true, "pattern matching on the _id column")
+		}
+		pattern, ok := expr.rhs.(*stringLiteralPlanExpression)
+		if !ok {
+			return nil, sql3.NewErrUnsupported(0, 0, true, "pattern matching with a non-literal pattern")
+		}
+
+		// match the field's keys against the pattern and union the
+		// matching rows.
+		arg := "regexp"
+		if op == parser.GLOB {
+			arg = "glob"
+		}
+		return &pql.Call{
+			Name: "UnionRows",
+			Children: []*pql.Call{
+				{
+					Name: "Rows",
+					Args: map[string]interface{}{
+						"field": strings.ToLower(lhs.columnName),
+						arg:     pattern.value,
+					},
+				},
+			},
+			Type: pql.PrecallGlobal,
+		}, nil
+
 	case parser.LT, parser.LE, parser.GT, parser.GE:
 		lhs, ok := expr.lhs.(*qualifiedRefPlanExpression)
 		if !ok {
@@ -58,6 +58,9 @@ func fieldSQLDataType(f *pilosa.FieldInfo) parser.ExprDataType {
 	case pilosa.FieldTypeDecimal:
 		return parser.NewDataTypeDecimal(f.Options.Scale)
 
+	case pilosa.FieldTypeGeo:
+		return parser.NewDataTypeGeo()
+
 	case pilosa.FieldTypeTime:
 		if f.Options.Keys {
 			return parser.NewDataTypeStringSetQuantum()
@@ -90,6 +93,9 @@ func dataTypeFromParserType(typ *parser.Type) (parser.ExprDataType, error) {
 		}
 		return parser.NewDataTypeDecimal(int64(scale)), nil
 
+	case dax.BaseTypeGeo:
+		return parser.NewDataTypeGeo(), nil
+
 	case dax.BaseTypeID:
 		return parser.NewDataTypeID(), nil
 
@@ -225,6 +231,17 @@ func typeIsCompatibleWithLikeOperator(testType parser.ExprDataType) bool {
 	}
 }
 
+// returns true if type is compatible with the (NOT) REGEXP and (NOT) GLOB
+// operators
+func typeIsCompatibleWithPatternOperator(testType parser.ExprDataType) bool {
+	switch testType.(type) {
+	case *parser.DataTypeString, *parser.DataTypeStringSet:
+		return true
+	default:
+		return false
+	}
+}
+
 // returns true if type is compatible with bitwise operators (&, |, <<, >>)
 func typeIsCompatibleWithBitwiseOperator(testType parser.ExprDataType) bool {
 	switch testType.(type) {
@@ -271,6 +288,26 @@ func typesAreAssignmentCompatible(targetType parser.ExprDataType, sourceType par
 		default:
 			return false
 		}
+	case *parser.DataTypeGeo:
+		switch source := sourceType.(type) {
+		case *parser.DataTypeGeo, *parser.DataTypeString:
+			return true
+		case *parser.DataTypeTuple:
+			// a point can be given as a (lat, lon) tuple of numbers
+			if len(source.Members) != 2 {
+				return false
+			}
+			for _, m := range source.Members {
+				switch m.(type) {
+				case *parser.DataTypeInt, *parser.DataTypeDecimal:
+				default:
+					return false
+				}
+			}
+			return true
+		default:
+			return false
+		}
 	case *parser.DataTypeStringSet:
 		switch sourceType.(type) {
 		case *parser.DataTypeStringSet:
@@ -599,6 +599,11 @@ func (n *callPlanExpression) EvaluateDateTrunc(currentRow []interface{}) (interf
 		return nil, sql3.NewErrInternalf("unable to convert value")
 	}
 
+	return dateTrunc(interval, date)
+}
+
+// dateTrunc formats date truncated to interval, as returned by DATE_TRUNC.
+func dateTrunc(interval string, date time.Time) (interface{}, error) {
 	switch strings.ToUpper(interval) {
 	case intervalYear:
 		return date.Format("2006"), nil
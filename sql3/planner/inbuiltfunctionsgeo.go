@@ -0,0 +1,120 @@
+package planner
+
+import (
+	"fmt"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// analyzeFunctionStWithin analyzes ST_WITHIN(point, minLat, minLon, maxLat, maxLon)
+func (p *ExecutionPlanner) analyzeFunctionStWithin(call *parser.Call, scope parser.Statement) (parser.Expr, error) {
+	return p.analyzeGeoFunction(call, 5)
+}
+
+// analyzeFunctionStDWithin analyzes ST_DWITHIN(point, lat, lon, radius), where
+// radius is in meters
+func (p *ExecutionPlanner) analyzeFunctionStDWithin(call *parser.Call, scope parser.Statement) (parser.Expr, error) {
+	return p.analyzeGeoFunction(call, 4)
+}
+
+func (p *ExecutionPlanner) analyzeGeoFunction(call *parser.Call, nargs int) (parser.Expr, error) {
+	if len(call.Args) != nargs {
+		return nil, sql3.NewErrCallParameterCountMismatch(call.Rparen.Line, call.Rparen.Column, call.Name.Name, nargs, len(call.Args))
+	}
+
+	// first arg should be a point
+	targetType := parser.NewDataTypeGeo()
+	if !typesAreAssignmentCompatible(targetType, call.Args[0].DataType()) {
+		return nil, sql3.NewErrTypeAssignmentIncompatible(call.Args[0].Pos().Line, call.Args[0].Pos().Column, call.Args[0].DataType().TypeDescription(), targetType.TypeDescription())
+	}
+
+	// the rest are coordinates (or a distance)
+	for _, arg := range call.Args[1:] {
+		switch arg.DataType().(type) {
+		case *parser.DataTypeInt, *parser.DataTypeDecimal:
+		default:
+			return nil, sql3.NewErrTypeAssignmentIncompatible(arg.Pos().Line, arg.Pos().Column, arg.DataType().TypeDescription(), parser.NewDataTypeDecimal(0).BaseTypeName())
+		}
+	}
+
+	call.ResultDataType = parser.NewDataTypeBool()
+
+	return call, nil
+}
+
+// EvaluateStWithin evaluates ST_WITHIN() for rows where it could not be
+// pushed down to a Within() call
+func (n *callPlanExpression) EvaluateStWithin(currentRow []interface{}) (interface{}, error) {
+	pt, coords, err := n.evaluateGeoArgs(currentRow)
+	if err != nil || pt == nil {
+		return nil, err
+	}
+	return pt.Within(coords[0], coords[1], coords[2], coords[3]), nil
+}
+
+// EvaluateStDWithin evaluates ST_DWITHIN() for rows where it could not be
+// pushed down to a Near() call
+func (n *callPlanExpression) EvaluateStDWithin(currentRow []interface{}) (interface{}, error) {
+	pt, coords, err := n.evaluateGeoArgs(currentRow)
+	if err != nil || pt == nil {
+		return nil, err
+	}
+	center := pilosa.GeoPoint{Lat: coords[0], Lon: coords[1]}
+	if err := center.Validate(); err != nil {
+		return nil, sql3.NewErrCallParameterValueInvalid(0, 0, center.String(), "point")
+	}
+	return pt.Distance(center) <= coords[2], nil
+}
+
+// evaluateGeoArgs evaluates the point and numeric arguments of a geo
+// function. A nil point is returned if any argument is null.
+func (n *callPlanExpression) evaluateGeoArgs(currentRow []interface{}) (*pilosa.GeoPoint, []float64, error) {
+	ptEval, err := n.args[0].Evaluate(currentRow)
+	if err != nil || ptEval == nil {
+		return nil, nil, err
+	}
+	pt, err := pilosa.GeoPointFromValue(ptEval)
+	if err != nil {
+		return nil, nil, sql3.NewErrCallParameterValueInvalid(0, 0, fmt.Sprintf("%v", ptEval), "point")
+	}
+	coords := make([]float64, len(n.args)-1)
+	for i, arg := range n.args[1:] {
+		eval, err := arg.Evaluate(currentRow)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch v := eval.(type) {
+		case nil:
+			return nil, nil, nil
+		case int64:
+			coords[i] = float64(v)
+		case pql.Decimal:
+			coords[i] = v.Float64()
+		default:
+			return nil, nil, sql3.NewErrInternalf("unexpected type '%T'", eval)
+		}
+	}
+	return &pt, coords, nil
+}
+
+// geoLiteralValue returns the value of a constant numeric argument to a geo
+// function, for use in a PQL call. Only literals (possibly negated) are
+// supported.
+func geoLiteralValue(expr types.PlanExpression) (interface{}, error) {
+	switch e := expr.(type) {
+	case *intLiteralPlanExpression, *floatLiteralPlanExpression:
+	case *unaryOpPlanExpression:
+		switch e.rhs.(type) {
+		case *intLiteralPlanExpression, *floatLiteralPlanExpression:
+		default:
+			return nil, sql3.NewErrInternalf("cannot convert SQL expression %T to a literal value", e.rhs)
+		}
+	default:
+		return nil, sql3.NewErrInternalf("cannot convert SQL expression %T to a literal value", expr)
+	}
+	return expr.Evaluate(nil)
+}
@@ -8,7 +8,6 @@ import (
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/dax"
-	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
 )
 
@@ -20,10 +19,11 @@ type PlanOpAlterTable struct {
 	oldColumnName string
 	newColumnName string
 	columnDef     *createTableField
+	retention     *tableRetention
 	warnings      []string
 }
 
-func NewPlanOpAlterTable(p *ExecutionPlanner, tableName string, operation alterOperation, oldColumnName string, newColumnName string, columnDef *createTableField) *PlanOpAlterTable {
+func NewPlanOpAlterTable(p *ExecutionPlanner, tableName string, operation alterOperation, oldColumnName string, newColumnName string, columnDef *createTableField, retention *tableRetention) *PlanOpAlterTable {
 	return &PlanOpAlterTable{
 		planner:       p,
 		tableName:     tableName,
@@ -31,6 +31,7 @@ func NewPlanOpAlterTable(p *ExecutionPlanner, tableName string, operation alterO
 		oldColumnName: oldColumnName,
 		newColumnName: newColumnName,
 		columnDef:     columnDef,
+		retention:     retention,
 		warnings:      make([]string, 0),
 	}
 }
@@ -69,6 +70,8 @@ func (p *PlanOpAlterTable) Iterator(ctx context.Context, row types.Row) (types.R
 		tableName:     p.tableName,
 		columnDef:     p.columnDef,
 		oldColumnName: p.oldColumnName,
+		newColumnName: p.newColumnName,
+		retention:     p.retention,
 	}, nil
 }
 
@@ -82,6 +85,8 @@ type alterTableRowIter struct {
 	tableName     string
 	columnDef     *createTableField
 	oldColumnName string
+	newColumnName string
+	retention     *tableRetention
 }
 
 var _ types.RowIterator = (*alterTableRowIter)(nil)
@@ -111,7 +116,21 @@ func (i *alterTableRowIter) Next(ctx context.Context) (types.Row, error) {
 		}
 
 	case alterOpRename:
-		return nil, sql3.NewErrInternal("column rename is unimplemented")
+		err := i.planner.schemaAPI.RenameField(ctx, dax.TableName(i.tableName), dax.FieldName(i.oldColumnName), dax.FieldName(i.newColumnName))
+		if err != nil {
+			return nil, err
+		}
+
+	case alterOpRenameTable:
+		err := i.planner.schemaAPI.RenameTable(ctx, dax.TableName(i.tableName), dax.TableName(i.newColumnName))
+		if err != nil {
+			return nil, err
+		}
+
+	case alterOpSet:
+		if err := i.planner.setTableRetention(ctx, i.tableName, i.retention); err != nil {
+			return nil, err
+		}
 
 	}
 	return nil, types.ErrNoMoreRows
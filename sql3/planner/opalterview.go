@@ -87,6 +87,11 @@ func (i *alterViewIter) Next(ctx context.Context) (types.Row, error) {
 	if v == nil {
 		return nil, sql3.NewErrViewNotFound(0, 0, i.view.name)
 	}
+	// the table for a materialized view is shaped by its statement, so it
+	// has to be dropped and created again to change it
+	if v.materialized {
+		return nil, sql3.NewErrViewIsMaterialized(0, 0, i.view.name)
+	}
 
 	// now store the view into fb_views
 	err = i.planner.updateView(ctx, i.view)
@@ -0,0 +1,279 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// PlanOpAnalyze implements the ANALYZE operator, which collects statistics
+// for the columns of a table and stores them in fb_column_stats for use by the
+// optimizer.
+type PlanOpAnalyze struct {
+	planner   *ExecutionPlanner
+	tableName string
+	warnings  []string
+}
+
+func NewPlanOpAnalyze(planner *ExecutionPlanner, tableName string) *PlanOpAnalyze {
+	return &PlanOpAnalyze{
+		planner:   planner,
+		tableName: tableName,
+		warnings:  make([]string, 0),
+	}
+}
+
+func (p *PlanOpAnalyze) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpAnalyze) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &analyzeRowIter{
+		planner:   p.planner,
+		tableName: p.tableName,
+	}, nil
+}
+
+func (p *PlanOpAnalyze) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpAnalyze) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 0 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return NewPlanOpAnalyze(p.planner, p.tableName), nil
+}
+
+func (p *PlanOpAnalyze) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["_schema"] = p.Schema().Plan()
+	result["tableName"] = p.tableName
+	return result
+}
+
+func (p *PlanOpAnalyze) String() string {
+	return ""
+}
+
+func (p *PlanOpAnalyze) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpAnalyze) Warnings() []string {
+	var w []string
+	w = append(w, p.warnings...)
+	return w
+}
+
+type analyzeRowIter struct {
+	planner   *ExecutionPlanner
+	tableName string
+}
+
+var _ types.RowIterator = (*analyzeRowIter)(nil)
+
+func (i *analyzeRowIter) Next(ctx context.Context) (types.Row, error) {
+	err := i.planner.checkAccess(ctx, i.tableName, accessTypeReadData)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := i.planner.collectColumnStatistics(ctx, i.tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = i.planner.replaceColumnStatistics(ctx, i.tableName, stats)
+	if err != nil {
+		return nil, err
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+// isBSIBaseType returns true for the column types stored as bit-sliced
+// integers, which have a min and max value.
+func isBSIBaseType(typ dax.BaseType) bool {
+	switch typ {
+	case dax.BaseTypeInt, dax.BaseTypeDecimal, dax.BaseTypeTimestamp:
+		return true
+	default:
+		return false
+	}
+}
+
+// collectColumnStatistics computes the statistics for every column of a table
+// with a single PQL query.
+func (p *ExecutionPlanner) collectColumnStatistics(ctx context.Context, tableName string) ([]*columnStatisticsSystemObject, error) {
+	tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(tableName))
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return nil, sql3.NewErrTableNotFound(0, 0, tableName)
+		}
+		return nil, err
+	}
+
+	// the first call counts the rows in the table, then each column has a
+	// call to count its non-null values and a call to count its distinct
+	// values, and BSI columns have two more for the min and max.
+	calls := []*pql.Call{
+		{Name: "Count", Children: []*pql.Call{{Name: "All"}}},
+	}
+	fields := make([]*dax.Field, 0, len(tbl.Fields))
+	for _, fld := range tbl.Fields {
+		if fld.IsPrimaryKey() {
+			continue
+		}
+		fields = append(fields, fld)
+		name := string(fld.Name)
+
+		var notNull *pql.Call
+		switch {
+		case isBSIBaseType(fld.Type):
+			notNull = &pql.Call{
+				Name: "Row",
+				Args: map[string]interface{}{
+					name: &pql.Condition{Op: pql.NEQ, Value: nil},
+				},
+			}
+		case fld.Type == dax.BaseTypeBool:
+			// bool fields don't support Rows()
+			notNull = &pql.Call{
+				Name: "Union",
+				Children: []*pql.Call{
+					{Name: "Row", Args: map[string]interface{}{name: true}},
+					{Name: "Row", Args: map[string]interface{}{name: false}},
+				},
+			}
+		default:
+			notNull = &pql.Call{
+				Name: "UnionRows",
+				Children: []*pql.Call{
+					{Name: "Rows", Args: map[string]interface{}{"field": name}},
+				},
+				Type: pql.PrecallGlobal,
+			}
+		}
+		calls = append(calls,
+			&pql.Call{Name: "Count", Children: []*pql.Call{notNull}},
+			&pql.Call{
+				Name: "Count",
+				Children: []*pql.Call{{
+					Name: "Distinct",
+					Args: map[string]interface{}{"field": name},
+					Type: pql.PrecallGlobal,
+				}},
+			},
+		)
+		if isBSIBaseType(fld.Type) {
+			calls = append(calls,
+				&pql.Call{Name: "Min", Args: map[string]interface{}{"field": name}, Children: []*pql.Call{{Name: "All"}}},
+				&pql.Call{Name: "Max", Args: map[string]interface{}{"field": name}, Children: []*pql.Call{{Name: "All"}}},
+			)
+		}
+	}
+
+	queryResponse, err := p.executor.Execute(ctx, tbl, &pql.Query{Calls: calls}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	results := queryResponse.Results
+	if len(results) != len(calls) {
+		return nil, sql3.NewErrInternalf("unexpected number of results '%d'", len(results))
+	}
+
+	nextCount := func() (int64, error) {
+		n, ok := results[0].(uint64)
+		if !ok {
+			return 0, sql3.NewErrInternalf("unexpected result type '%T'", results[0])
+		}
+		results = results[1:]
+		return int64(n), nil
+	}
+
+	rowCount, err := nextCount()
+	if err != nil {
+		return nil, err
+	}
+
+	// every _id is distinct and none are null
+	stats := []*columnStatisticsSystemObject{
+		{
+			tableName:     tableName,
+			columnName:    string(dax.PrimaryKeyFieldName),
+			rowCount:      rowCount,
+			distinctCount: rowCount,
+		},
+	}
+
+	for _, fld := range fields {
+		s := &columnStatisticsSystemObject{
+			tableName:  tableName,
+			columnName: string(fld.Name),
+			rowCount:   rowCount,
+		}
+
+		notNullCount, err := nextCount()
+		if err != nil {
+			return nil, err
+		}
+		if rowCount > 0 {
+			s.nullFraction = float64(rowCount-notNullCount) / float64(rowCount)
+		}
+
+		s.distinctCount, err = nextCount()
+		if err != nil {
+			return nil, err
+		}
+
+		if isBSIBaseType(fld.Type) {
+			s.minValue, s.hasRange, err = formatStatisticsValue(fld.Type, results[0])
+			if err != nil {
+				return nil, err
+			}
+			s.maxValue, _, err = formatStatisticsValue(fld.Type, results[1])
+			if err != nil {
+				return nil, err
+			}
+			results = results[2:]
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// formatStatisticsValue formats the result of a Min or Max call on a BSI
+// column. It returns false if the column has no values.
+func formatStatisticsValue(typ dax.BaseType, result interface{}) (string, bool, error) {
+	vc, ok := result.(pilosa.ValCount)
+	if !ok {
+		return "", false, sql3.NewErrInternalf("unexpected result type '%T'", result)
+	}
+	if vc.Count == 0 {
+		return "", false, nil
+	}
+
+	switch typ {
+	case dax.BaseTypeInt:
+		return strconv.FormatInt(vc.Val, 10), true, nil
+	case dax.BaseTypeDecimal:
+		if vc.DecimalVal == nil {
+			return "", false, nil
+		}
+		return vc.DecimalVal.String(), true, nil
+	case dax.BaseTypeTimestamp:
+		return vc.TimestampVal.UTC().Format(time.RFC3339Nano), true, nil
+	default:
+		return "", false, sql3.NewErrInternalf("unexpected column type '%s'", typ)
+	}
+}
@@ -181,68 +181,9 @@ func (i *copyIterator) Next(ctx context.Context) (types.Row, error) {
 
 			irow := make([]types.PlanExpression, len(row))
 			for i, s := range i.copySchema {
-				switch ty := s.Type.(type) {
-				case *parser.DataTypeID, *parser.DataTypeInt:
-					val, ok := row[i].(int64)
-					if !ok {
-						return nil, sql3.NewErrInternalf("unexpected type '%T'", row[i])
-					}
-					irow[i] = newIntLiteralPlanExpression(val)
-
-				case *parser.DataTypeDecimal:
-					val, ok := row[i].(pql.Decimal)
-					if !ok {
-						return nil, sql3.NewErrInternalf("unexpected type '%T'", row[i])
-					}
-					irow[i] = newFloatLiteralPlanExpression(val.String())
-
-				case *parser.DataTypeString:
-					val, ok := row[i].(string)
-					if !ok {
-						return nil, sql3.NewErrInternalf("unexpected type '%T'", row[i])
-					}
-					irow[i] = newStringLiteralPlanExpression(val)
-
-				case *parser.DataTypeBool:
-					val, ok := row[i].(bool)
-					if !ok {
-						return nil, sql3.NewErrInternalf("unexpected type '%T'", row[i])
-					}
-					irow[i] = newBoolLiteralPlanExpression(val)
-
-				case *parser.DataTypeTimestamp:
-					val, ok := row[i].(time.Time)
-					if !ok {
-						return nil, sql3.NewErrInternalf("unexpected type '%T'", row[i])
-					}
-					irow[i] = newTimestampLiteralPlanExpression(val)
-
-				case *parser.DataTypeStringSet:
-					val, ok := row[i].([]string)
-					if !ok {
-						return nil, sql3.NewErrInternalf("unexpected type '%T'", row[i])
-					}
-
-					members := make([]types.PlanExpression, 0)
-					for _, m := range val {
-						members = append(members, newStringLiteralPlanExpression(m))
-					}
-					irow[i] = newExprSetLiteralPlanExpression(members, parser.NewDataTypeStringSet())
-
-				case *parser.DataTypeIDSet:
-					val, ok := row[i].([]int64)
-					if !ok {
-						return nil, sql3.NewErrInternalf("unexpected type '%T'", row[i])
-					}
-
-					members := make([]types.PlanExpression, 0)
-					for _, m := range val {
-						members = append(members, newIntLiteralPlanExpression(m))
-					}
-					irow[i] = newExprSetLiteralPlanExpression(members, parser.NewDataTypeIDSet())
-
-				default:
-					return nil, sql3.NewErrInternalf("unhandled type '%T'", ty)
+				irow[i], err = newLiteralPlanExpressionFromValue(s.Type, row[i])
+				if err != nil {
+					return nil, err
 				}
 			}
 			insertBatch = append(insertBatch, irow)
@@ -275,6 +216,74 @@ func (i *copyIterator) Next(ctx context.Context) (types.Row, error) {
 	return nil, types.ErrNoMoreRows
 }
 
+// newLiteralPlanExpressionFromValue returns a literal plan expression for a
+// value of the given data type as it would be returned from a row iterator.
+func newLiteralPlanExpressionFromValue(dataType parser.ExprDataType, value interface{}) (types.PlanExpression, error) {
+	switch ty := dataType.(type) {
+	case *parser.DataTypeID, *parser.DataTypeInt:
+		val, ok := value.(int64)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected type '%T'", value)
+		}
+		return newIntLiteralPlanExpression(val), nil
+
+	case *parser.DataTypeDecimal:
+		val, ok := value.(pql.Decimal)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected type '%T'", value)
+		}
+		return newFloatLiteralPlanExpression(val.String()), nil
+
+	case *parser.DataTypeString:
+		val, ok := value.(string)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected type '%T'", value)
+		}
+		return newStringLiteralPlanExpression(val), nil
+
+	case *parser.DataTypeBool:
+		val, ok := value.(bool)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected type '%T'", value)
+		}
+		return newBoolLiteralPlanExpression(val), nil
+
+	case *parser.DataTypeTimestamp:
+		val, ok := value.(time.Time)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected type '%T'", value)
+		}
+		return newTimestampLiteralPlanExpression(val), nil
+
+	case *parser.DataTypeStringSet:
+		val, ok := value.([]string)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected type '%T'", value)
+		}
+
+		members := make([]types.PlanExpression, 0)
+		for _, m := range val {
+			members = append(members, newStringLiteralPlanExpression(m))
+		}
+		return newExprSetLiteralPlanExpression(members, parser.NewDataTypeStringSet()), nil
+
+	case *parser.DataTypeIDSet:
+		val, ok := value.([]int64)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected type '%T'", value)
+		}
+
+		members := make([]types.PlanExpression, 0)
+		for _, m := range val {
+			members = append(members, newIntLiteralPlanExpression(m))
+		}
+		return newExprSetLiteralPlanExpression(members, parser.NewDataTypeIDSet()), nil
+
+	default:
+		return nil, sql3.NewErrInternalf("unhandled type '%T'", ty)
+	}
+}
+
 type remoteCopyIterator struct {
 	planner         *ExecutionPlanner
 	targetTableName string
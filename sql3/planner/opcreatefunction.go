@@ -92,10 +92,10 @@ func (i *createFunctionIter) Next(ctx context.Context) (types.Row, error) {
 		if i.ifNotExists {
 			return nil, types.ErrNoMoreRows
 		}
-		return nil, sql3.NewErrViewExists(0, 0, i.function.name)
+		return nil, sql3.NewErrFunctionExists(0, 0, i.function.name)
 	}
 
-	// now store the view into fb_functions
+	// now store the function into fb_functions
 	err = i.planner.insertFunction(i.function)
 	if err != nil {
 		return nil, err
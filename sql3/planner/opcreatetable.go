@@ -21,12 +21,13 @@ type PlanOpCreateTable struct {
 	isKeyed       bool
 	keyPartitions int
 	description   string
+	retention     *tableRetention
 	columns       []*createTableField
 	warnings      []string
 }
 
 // NewPlanOpCreateTable returns a new PlanOpCreateTable planoperator
-func NewPlanOpCreateTable(p *ExecutionPlanner, tableName string, failIfExists bool, isKeyed bool, keyPartitions int, description string, columns []*createTableField) *PlanOpCreateTable {
+func NewPlanOpCreateTable(p *ExecutionPlanner, tableName string, failIfExists bool, isKeyed bool, keyPartitions int, description string, retention *tableRetention, columns []*createTableField) *PlanOpCreateTable {
 	return &PlanOpCreateTable{
 		planner:       p,
 		tableName:     tableName,
@@ -35,6 +36,7 @@ func NewPlanOpCreateTable(p *ExecutionPlanner, tableName string, failIfExists bo
 		keyPartitions: keyPartitions,
 		columns:       columns,
 		description:   description,
+		retention:     retention,
 		warnings:      make([]string, 0),
 	}
 }
@@ -76,6 +78,7 @@ func (p *PlanOpCreateTable) Iterator(ctx context.Context, row types.Row) (types.
 		keyPartitions: p.keyPartitions,
 		columns:       p.columns,
 		description:   p.description,
+		retention:     p.retention,
 	}, nil
 }
 
@@ -90,6 +93,7 @@ type createTableRowIter struct {
 	isKeyed       bool
 	keyPartitions int
 	description   string
+	retention     *tableRetention
 	columns       []*createTableField
 }
 
@@ -135,8 +139,15 @@ func (i *createTableRowIter) Next(ctx context.Context) (types.Row, error) {
 			if i.failIfExists {
 				return nil, sql3.NewErrTableExists(0, 0, i.tableName)
 			}
-		} else {
-			return nil, err
+			// the existing table is left as it is
+			return nil, types.ErrNoMoreRows
+		}
+		return nil, err
+	}
+
+	if i.retention != nil {
+		if err := i.planner.setTableRetention(ctx, i.tableName, i.retention); err != nil {
+			return nil, errors.Wrap(err, "setting retention")
 		}
 	}
 	return nil, types.ErrNoMoreRows
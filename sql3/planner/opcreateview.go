@@ -52,6 +52,7 @@ func (p *PlanOpCreateView) Plan() map[string]interface{} {
 	result["_op"] = fmt.Sprintf("%T", p)
 	result["_schema"] = p.Schema().Plan()
 	result["model"] = p.view.name
+	result["materialized"] = p.view.materialized
 	return result
 }
 
@@ -116,5 +117,20 @@ func (i *createViewIter) Next(ctx context.Context) (types.Row, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// materialized views get a table to hold the result
+	if i.view.materialized {
+		err = i.planner.createMaterializedView(ctx, i.view)
+		if err != nil {
+			// don't leave a half created view behind
+			if derr := i.planner.dropMaterializedView(ctx, i.view); derr != nil {
+				i.planner.logger.Errorf("dropping table for materialized view '%s': %v", i.view.name, derr)
+			}
+			if derr := i.planner.deleteView(ctx, i.view.name); derr != nil {
+				i.planner.logger.Errorf("deleting materialized view '%s': %v", i.view.name, derr)
+			}
+			return nil, err
+		}
+	}
 	return nil, types.ErrNoMoreRows
 }
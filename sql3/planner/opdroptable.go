@@ -81,5 +81,12 @@ func (i *dropTableRowIter) Next(ctx context.Context) (types.Row, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	// statistics for the table would otherwise be used for any table created
+	// later with the same name
+	err = i.planner.deleteColumnStatistics(ctx, i.index.Name)
+	if err != nil {
+		return nil, err
+	}
 	return nil, types.ErrNoMoreRows
 }
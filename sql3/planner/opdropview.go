@@ -81,6 +81,11 @@ func (i *dropViewRowIter) Next(ctx context.Context) (types.Row, error) {
 		return nil, err
 	}
 
+	// a refresh in flight would otherwise replace the view's table after it
+	// has been dropped
+	unlock := i.planner.systemLayerAPI.MaterializedViews().Lock(i.viewName)
+	defer unlock()
+
 	// check in the views table to see if it exists
 	v, err := i.planner.getViewByName(ctx, i.viewName)
 	if err != nil {
@@ -93,6 +98,13 @@ func (i *dropViewRowIter) Next(ctx context.Context) (types.Row, error) {
 		return nil, sql3.NewErrViewNotFound(0, 0, i.viewName)
 	}
 
+	if v.materialized {
+		err = i.planner.dropMaterializedView(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = i.planner.deleteView(ctx, i.viewName)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,654 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow/go/v10/arrow"
+	"github.com/apache/arrow/go/v10/arrow/array"
+	"github.com/apache/arrow/go/v10/arrow/memory"
+	"github.com/apache/arrow/go/v10/parquet"
+	"github.com/apache/arrow/go/v10/parquet/pqarrow"
+	"github.com/featurebasedb/featurebase/v3/authn"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// exportParquetBatchSize is the number of rows written to each parquet row
+// group.
+const exportParquetBatchSize = 10000
+
+type exportOptions struct {
+	// file path or url to export to
+	target string
+	// CSV, NDJSON or PARQUET
+	format string
+	// FILE or URL
+	output string
+	// write a header row (CSV only)
+	hasHeaderRow bool
+}
+
+// PlanOpExport plan operator that writes the rows of its child to a file or
+// url.
+type PlanOpExport struct {
+	planner  *ExecutionPlanner
+	options  *exportOptions
+	ChildOp  types.PlanOperator
+	warnings []string
+}
+
+func NewPlanOpExport(planner *ExecutionPlanner, options *exportOptions, child types.PlanOperator) *PlanOpExport {
+	return &PlanOpExport{
+		planner:  planner,
+		options:  options,
+		ChildOp:  child,
+		warnings: make([]string, 0),
+	}
+}
+
+func (p *PlanOpExport) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["_schema"] = p.Schema().Plan()
+	result["target"] = p.options.target
+	result["format"] = p.options.format
+	result["output"] = p.options.output
+	result["hasHeaderRow"] = p.options.hasHeaderRow
+	result["child"] = p.ChildOp.Plan()
+	return result
+}
+
+func (p *PlanOpExport) String() string {
+	return ""
+}
+
+func (p *PlanOpExport) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpExport) Warnings() []string {
+	var w []string
+	w = append(w, p.warnings...)
+	w = append(w, p.ChildOp.Warnings()...)
+	return w
+}
+
+func (p *PlanOpExport) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpExport) Children() []types.PlanOperator {
+	return []types.PlanOperator{
+		p.ChildOp,
+	}
+}
+
+func (p *PlanOpExport) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	child, err := p.ChildOp.Iterator(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	return &exportRowIter{
+		planner: p.planner,
+		options: p.options,
+		schema:  p.ChildOp.Schema(),
+		child:   child,
+	}, nil
+}
+
+func (p *PlanOpExport) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 1 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return NewPlanOpExport(p.planner, p.options, children[0]), nil
+}
+
+type exportRowIter struct {
+	planner *ExecutionPlanner
+	options *exportOptions
+	schema  types.Schema
+	child   types.RowIterator
+}
+
+var _ types.RowIterator = (*exportRowIter)(nil)
+
+func (i *exportRowIter) Next(ctx context.Context) (types.Row, error) {
+	dest, err := i.openDestination(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = i.export(ctx, dest)
+	if cerr := dest.close(err); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+// export writes every row of the child iterator to dest.
+func (i *exportRowIter) export(ctx context.Context, dest io.Writer) error {
+	var w exportWriter
+	var err error
+	switch i.options.format {
+	case "CSV":
+		w, err = newCSVExportWriter(dest, i.schema, i.options.hasHeaderRow)
+	case "NDJSON":
+		w = newNDJSONExportWriter(dest, i.schema)
+	case "PARQUET":
+		w, err = newParquetExportWriter(dest, i.schema)
+	default:
+		err = sql3.NewErrInvalidFormatSpecifier(0, 0, i.options.format)
+	}
+	if err != nil {
+		return err
+	}
+
+	for {
+		row, err := i.child.Next(ctx)
+		if err != nil {
+			if err == types.ErrNoMoreRows {
+				break
+			}
+			return err
+		}
+		if err := w.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// exportDestination is the file or http request an export is written to.
+type exportDestination struct {
+	io.Writer
+	// close finishes writing to the destination; err is the error (if any)
+	// which stopped the export, in which case the destination is abandoned
+	close func(err error) error
+}
+
+// exportHTTPClient posts URL exports. It doesn't follow redirects, which
+// could take an export to a url which is not allowed.
+var exportHTTPClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// openDestination opens the destination of the export. Exports write files
+// on, and make requests from, the server, so they are restricted to admins,
+// and to the export directory and urls the server is configured with.
+func (i *exportRowIter) openDestination(ctx context.Context) (*exportDestination, error) {
+	target := i.options.target
+	if admin, ok := authn.GetAdmin(ctx); ok && !admin {
+		return nil, sql3.NewErrExportNotAllowed(0, 0, target, "admin permission required")
+	}
+
+	switch i.options.output {
+	case "FILE":
+		filePath, err := exportFilePath(i.planner.systemAPI.ExportDirectory(), target)
+		if err != nil {
+			return nil, err
+		}
+
+		// The export is written to a temporary file which replaces the
+		// target once it is complete, so a failed export leaves neither a
+		// partial file nor a damaged copy of a file already at the target.
+		f, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".*")
+		if err != nil {
+			return nil, sql3.NewErrWritingDestination(0, 0, target, err.Error())
+		}
+		return &exportDestination{
+			Writer: f,
+			close: func(err error) error {
+				if err != nil {
+					f.Close()
+					os.Remove(f.Name())
+					return err
+				}
+				if err := replaceWithFile(f, filePath); err != nil {
+					os.Remove(f.Name())
+					return sql3.NewErrWritingDestination(0, 0, target, err.Error())
+				}
+				return nil
+			},
+		}, nil
+
+	case "URL":
+		if err := checkExportURL(i.planner.systemAPI.ExportURLs(), target); err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", target, nil)
+		if err != nil {
+			return nil, sql3.NewErrWritingDestination(0, 0, target, err.Error())
+		}
+		pr, pw := io.Pipe()
+		req.Body = pr
+		req.Header.Set("Content-Type", exportContentType(i.options.format))
+
+		done := make(chan error, 1)
+		go func() {
+			resp, err := exportHTTPClient.Do(req)
+			if err != nil {
+				pr.CloseWithError(err)
+				done <- err
+				return
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				err = fmt.Errorf("%d, %s", resp.StatusCode, string(body))
+				pr.CloseWithError(err)
+			}
+			done <- err
+		}()
+
+		return &exportDestination{
+			Writer: pw,
+			close: func(err error) error {
+				if err != nil {
+					pw.CloseWithError(err)
+					<-done
+					return err
+				}
+				pw.Close()
+				if err := <-done; err != nil {
+					return sql3.NewErrWritingDestination(0, 0, target, err.Error())
+				}
+				return nil
+			},
+		}, nil
+
+	default:
+		return nil, sql3.NewErrInvalidOutputSpecifier(0, 0, i.options.output)
+	}
+}
+
+// exportFilePath returns the path a FILE export to target is written to,
+// which is target under the export directory dir.
+func exportFilePath(dir, target string) (string, error) {
+	if dir == "" {
+		return "", sql3.NewErrExportNotAllowed(0, 0, target, "FILE exports are not enabled")
+	}
+	if filepath.IsAbs(target) || filepath.VolumeName(target) != "" {
+		return "", sql3.NewErrExportNotAllowed(0, 0, target, "path must be relative to the export directory")
+	}
+	for _, elem := range strings.FieldsFunc(target, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if elem == ".." {
+			return "", sql3.NewErrExportNotAllowed(0, 0, target, "path must not contain '..'")
+		}
+	}
+	return filepath.Join(dir, target), nil
+}
+
+// checkExportURL checks that target is one of the allowed urls, or under
+// one of them: it has the same scheme and host, and its path is the allowed
+// url's path or below it.
+func checkExportURL(allowed []string, target string) error {
+	u, err := url.Parse(target)
+	if err != nil {
+		return sql3.NewErrWritingDestination(0, 0, target, err.Error())
+	}
+	if u.User != nil {
+		return sql3.NewErrExportNotAllowed(0, 0, target, "url must not contain user information")
+	}
+	p := path.Clean("/" + u.Path)
+	for _, a := range allowed {
+		au, err := url.Parse(a)
+		if err != nil {
+			continue
+		}
+		if !strings.EqualFold(u.Scheme, au.Scheme) || !strings.EqualFold(u.Host, au.Host) {
+			continue
+		}
+		prefix := strings.TrimSuffix(path.Clean("/"+au.Path), "/")
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return nil
+		}
+	}
+	return sql3.NewErrExportNotAllowed(0, 0, target, "url is not an allowed export url")
+}
+
+// replaceWithFile closes f and moves it to path, replacing any file there.
+func replaceWithFile(f *os.File, path string) error {
+	err := f.Chmod(0o644)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func exportContentType(format string) string {
+	switch format {
+	case "CSV":
+		return "text/csv"
+	case "NDJSON":
+		return "application/x-ndjson"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// exportColumnName returns the name a column is exported with.
+func exportColumnName(idx int, col *types.PlannerColumn) string {
+	if col.ColumnName == "" {
+		return fmt.Sprintf("_%d", idx)
+	}
+	return col.ColumnName
+}
+
+// exportWriter writes rows in an export format.
+type exportWriter interface {
+	WriteRow(row types.Row) error
+	Close() error
+}
+
+// csvExportWriter writes rows as CSV. Null values are written as empty
+// fields, and sets as JSON arrays.
+type csvExportWriter struct {
+	w      *csv.Writer
+	schema types.Schema
+	record []string
+}
+
+func newCSVExportWriter(w io.Writer, schema types.Schema, hasHeaderRow bool) (*csvExportWriter, error) {
+	cw := &csvExportWriter{
+		w:      csv.NewWriter(w),
+		schema: schema,
+		record: make([]string, len(schema)),
+	}
+	if hasHeaderRow {
+		for i, col := range schema {
+			cw.record[i] = exportColumnName(i, col)
+		}
+		if err := cw.w.Write(cw.record); err != nil {
+			return nil, err
+		}
+	}
+	return cw, nil
+}
+
+func (cw *csvExportWriter) WriteRow(row types.Row) error {
+	for i, col := range cw.schema {
+		v, err := exportValue(col.Type, row[i])
+		if err != nil {
+			return err
+		}
+		switch v := v.(type) {
+		case nil:
+			cw.record[i] = ""
+		case string:
+			cw.record[i] = v
+		case int64:
+			cw.record[i] = strconv.FormatInt(v, 10)
+		case bool:
+			cw.record[i] = strconv.FormatBool(v)
+		case pql.Decimal:
+			cw.record[i] = v.String()
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			cw.record[i] = string(b)
+		}
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvExportWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// ndjsonExportWriter writes each row as a JSON object keyed by column name.
+type ndjsonExportWriter struct {
+	w      *bufio.Writer
+	schema types.Schema
+	keys   [][]byte
+}
+
+func newNDJSONExportWriter(w io.Writer, schema types.Schema) *ndjsonExportWriter {
+	nw := &ndjsonExportWriter{
+		w:      bufio.NewWriter(w),
+		schema: schema,
+		keys:   make([][]byte, len(schema)),
+	}
+	for i, col := range schema {
+		// marshalling a string can't fail
+		nw.keys[i], _ = json.Marshal(exportColumnName(i, col))
+	}
+	return nw
+}
+
+func (nw *ndjsonExportWriter) WriteRow(row types.Row) error {
+	nw.w.WriteByte('{')
+	for i, col := range nw.schema {
+		if i > 0 {
+			nw.w.WriteByte(',')
+		}
+		nw.w.Write(nw.keys[i])
+		nw.w.WriteByte(':')
+
+		v, err := exportValue(col.Type, row[i])
+		if err != nil {
+			return err
+		}
+		var b []byte
+		if d, ok := v.(pql.Decimal); ok {
+			// write decimals as numbers, without losing precision
+			b = []byte(d.String())
+		} else if b, err = json.Marshal(v); err != nil {
+			return err
+		}
+		nw.w.Write(b)
+	}
+	nw.w.WriteByte('}')
+	return nw.w.WriteByte('\n')
+}
+
+func (nw *ndjsonExportWriter) Close() error {
+	return nw.w.Flush()
+}
+
+// parquetExportWriter writes rows to a parquet file, using the same arrow
+// types BULK INSERT reads: decimals are written as doubles and timestamps as
+// RFC3339 strings.
+type parquetExportWriter struct {
+	fw      *pqarrow.FileWriter
+	builder *array.RecordBuilder
+	schema  types.Schema
+	rows    int
+}
+
+func newParquetExportWriter(w io.Writer, schema types.Schema) (*parquetExportWriter, error) {
+	fields := make([]arrow.Field, len(schema))
+	for i, col := range schema {
+		dt, err := exportArrowType(col.Type)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = arrow.Field{Name: exportColumnName(i, col), Type: dt, Nullable: true}
+	}
+	arrowSchema := arrow.NewSchema(fields, nil)
+
+	// hide any Close method of w from the file writer; closing the
+	// destination is up to the caller
+	fw, err := pqarrow.NewFileWriter(arrowSchema, struct{ io.Writer }{w}, parquet.NewWriterProperties(), pqarrow.DefaultWriterProps())
+	if err != nil {
+		return nil, err
+	}
+	return &parquetExportWriter{
+		fw:      fw,
+		builder: array.NewRecordBuilder(memory.NewGoAllocator(), arrowSchema),
+		schema:  schema,
+	}, nil
+}
+
+func exportArrowType(typ parser.ExprDataType) (arrow.DataType, error) {
+	switch typ.(type) {
+	case *parser.DataTypeID, *parser.DataTypeInt:
+		return arrow.PrimitiveTypes.Int64, nil
+	case *parser.DataTypeBool:
+		return arrow.FixedWidthTypes.Boolean, nil
+	case *parser.DataTypeDecimal:
+		return arrow.PrimitiveTypes.Float64, nil
+	case *parser.DataTypeString, *parser.DataTypeTimestamp, *parser.DataTypeVoid:
+		return arrow.BinaryTypes.String, nil
+	case *parser.DataTypeIDSet, *parser.DataTypeIDSetQuantum:
+		return arrow.ListOf(arrow.PrimitiveTypes.Int64), nil
+	case *parser.DataTypeStringSet, *parser.DataTypeStringSetQuantum:
+		return arrow.ListOf(arrow.BinaryTypes.String), nil
+	default:
+		return nil, sql3.NewErrInternalf("unhandled type '%T'", typ)
+	}
+}
+
+func (pw *parquetExportWriter) WriteRow(row types.Row) error {
+	for i, col := range pw.schema {
+		v, err := exportValue(col.Type, row[i])
+		if err != nil {
+			return err
+		}
+		fb := pw.builder.Field(i)
+		if v == nil {
+			fb.AppendNull()
+			continue
+		}
+		switch b := fb.(type) {
+		case *array.Int64Builder:
+			b.Append(v.(int64))
+		case *array.BooleanBuilder:
+			b.Append(v.(bool))
+		case *array.Float64Builder:
+			b.Append(v.(pql.Decimal).Float64())
+		case *array.StringBuilder:
+			b.Append(v.(string))
+		case *array.ListBuilder:
+			b.Append(true)
+			switch vb := b.ValueBuilder().(type) {
+			case *array.Int64Builder:
+				vb.AppendValues(v.([]int64), nil)
+			case *array.StringBuilder:
+				vb.AppendValues(v.([]string), nil)
+			}
+		default:
+			return sql3.NewErrInternalf("unhandled builder type '%T'", fb)
+		}
+	}
+	pw.rows++
+	if pw.rows == exportParquetBatchSize {
+		return pw.flush()
+	}
+	return nil
+}
+
+func (pw *parquetExportWriter) flush() error {
+	rec := pw.builder.NewRecord()
+	defer rec.Release()
+	pw.rows = 0
+	return pw.fw.Write(rec)
+}
+
+func (pw *parquetExportWriter) Close() error {
+	defer pw.builder.Release()
+	if pw.rows > 0 {
+		if err := pw.flush(); err != nil {
+			return err
+		}
+	}
+	return pw.fw.Close()
+}
+
+// exportValue normalizes a value of a column of type typ to one of nil,
+// int64, bool, pql.Decimal, string, []int64 or []string. Timestamps become
+// RFC3339 strings.
+func exportValue(typ parser.ExprDataType, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch typ.(type) {
+	case *parser.DataTypeID, *parser.DataTypeInt:
+		switch v := v.(type) {
+		case int64:
+			return v, nil
+		case uint64:
+			return int64(v), nil
+		}
+
+	case *parser.DataTypeBool:
+		if v, ok := v.(bool); ok {
+			return v, nil
+		}
+
+	case *parser.DataTypeDecimal:
+		switch v := v.(type) {
+		case pql.Decimal:
+			return v, nil
+		case *pql.Decimal:
+			return *v, nil
+		}
+
+	case *parser.DataTypeString:
+		if v, ok := v.(string); ok {
+			return v, nil
+		}
+
+	case *parser.DataTypeTimestamp:
+		if v, ok := v.(time.Time); ok {
+			return v.UTC().Format(time.RFC3339Nano), nil
+		}
+
+	// Sets are exported with their members in order, so the same set is
+	// always exported the same way.
+	case *parser.DataTypeIDSet, *parser.DataTypeIDSetQuantum:
+		var ids []int64
+		switch v := v.(type) {
+		case []int64:
+			ids = append(ids, v...)
+		case []uint64:
+			ids = make([]int64, len(v))
+			for i := range v {
+				ids[i] = int64(v[i])
+			}
+		default:
+			return nil, sql3.NewErrInternalf("unexpected value '%T' for type '%s'", v, typ.TypeDescription())
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids, nil
+
+	case *parser.DataTypeStringSet, *parser.DataTypeStringSetQuantum:
+		if v, ok := v.([]string); ok {
+			set := append([]string(nil), v...)
+			sort.Strings(set)
+			return set, nil
+		}
+
+	case *parser.DataTypeVoid:
+		return nil, nil
+	}
+	return nil, sql3.NewErrInternalf("unexpected value '%T' for type '%s'", v, typ.TypeDescription())
+}
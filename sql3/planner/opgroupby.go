@@ -34,16 +34,7 @@ func NewPlanOpGroupBy(aggregates []types.PlanExpression, groupByExprs []types.Pl
 func (p *PlanOpGroupBy) Schema() types.Schema {
 	result := make(types.Schema, len(p.GroupByExprs)+len(p.Aggregates))
 	for idx, expr := range p.GroupByExprs {
-		ref, ok := expr.(*qualifiedRefPlanExpression)
-		if !ok {
-			continue
-		}
-		s := &types.PlannerColumn{
-			ColumnName:   ref.columnName,
-			RelationName: ref.tableName,
-			Type:         expr.Type(),
-		}
-		result[idx] = s
+		result[idx] = groupByColumn(expr)
 	}
 	offset := len(p.GroupByExprs)
 	for idx, agg := range p.Aggregates {
@@ -300,6 +291,24 @@ func evalBuffers(ctx context.Context, aggregationBuffers *keysAndAggregations) (
 	return row, nil
 }
 
+// groupByColumn returns the schema column for a group by expression. A column
+// reference keeps its name, and any other expression is named by its string
+// form.
+func groupByColumn(expr types.PlanExpression) *types.PlannerColumn {
+	if ref, ok := expr.(*qualifiedRefPlanExpression); ok {
+		return &types.PlannerColumn{
+			ColumnName:   ref.columnName,
+			RelationName: ref.tableName,
+			Type:         expr.Type(),
+		}
+	}
+	return &types.PlannerColumn{
+		ColumnName:   expr.String(),
+		RelationName: "",
+		Type:         expr.Type(),
+	}
+}
+
 func groupingKey(ctx context.Context, groupByExprs []types.PlanExpression, row types.Row) (string, types.Row, error) {
 	var buf bytes.Buffer
 	rowKeys := make([]interface{}, len(groupByExprs))
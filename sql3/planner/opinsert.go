@@ -164,6 +164,7 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 
 	batch, err := fbbatch.NewBatch(i.planner.importer, batchSize, tbl, idxInfo.Fields,
 		fbbatch.OptUseShardTransactionalEndpoint(true),
+		fbbatch.OptContext(ctx),
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "setting up batch")
@@ -351,6 +352,22 @@ func (i *insertRowIter) Next(ctx context.Context) (types.Row, error) {
 				}
 				row.Values[posVals[idx]] = eval
 
+			case pilosa.FieldTypeGeo:
+				// a point is stored as the set of cells containing it
+				if eval == nil {
+					row.Values[posVals[idx]] = eval
+					break
+				}
+				p, err := pilosa.GeoPointFromValue(eval)
+				if err != nil {
+					return nil, sql3.NewErrInsertValueOutOfRange(0, 0, columnName, rowNumber+1, eval)
+				}
+				rowIDs, err := pilosa.GeoRowIDs(p)
+				if err != nil {
+					return nil, err
+				}
+				row.Values[posVals[idx]] = rowIDs
+
 			case pilosa.FieldTypeTimestamp:
 				switch v := eval.(type) {
 
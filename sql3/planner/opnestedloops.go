@@ -18,6 +18,10 @@ type PlanOpNestedLoops struct {
 	cond     types.PlanExpression
 	jType    joinType
 	warnings []string
+
+	// if true, the bottom input is scanned in the outer loop; rows are still
+	// built with the top columns first. Only used for inner joins.
+	bottomOuter bool
 }
 
 func NewPlanOpNestedLoops(top, bottom types.PlanOperator, jType joinType, condition types.PlanExpression) *PlanOpNestedLoops {
@@ -39,6 +43,9 @@ func (p *PlanOpNestedLoops) Plan() map[string]interface{} {
 	if p.cond != nil {
 		result["condition"] = p.cond.Plan()
 	}
+	if p.bottomOuter {
+		result["bottomOuter"] = true
+	}
 	return result
 }
 
@@ -69,20 +76,39 @@ func (p *PlanOpNestedLoops) Children() []types.PlanOperator {
 }
 
 func (p *PlanOpNestedLoops) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
-	topIter, err := p.top.Iterator(ctx, row)
+	outer, inner := p.top, p.bottom
+	if p.bottomOuter {
+		outer, inner = p.bottom, p.top
+	}
+	outerIter, err := outer.Iterator(ctx, row)
 	if err != nil {
 		return nil, err
 	}
 
 	rowWidth := len(row) + len(p.top.Schema()) + len(p.bottom.Schema())
-	return newNestedLoopsIter(ctx, p.jType, topIter, p.bottom, row, p.cond, rowWidth, row), nil
+	iter := newNestedLoopsIter(ctx, p.jType, outerIter, inner, row, p.cond, rowWidth, row)
+	iter.swapped = p.bottomOuter
+	return iter, nil
 }
 
 func (p *PlanOpNestedLoops) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
 	if len(children) != 2 {
 		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
 	}
-	return NewPlanOpNestedLoops(children[0], children[1], p.jType, p.cond), nil
+	op := NewPlanOpNestedLoops(children[0], children[1], p.jType, p.cond)
+	op.bottomOuter = p.bottomOuter
+	return op, nil
+}
+
+// withBottomOuter returns a copy of an inner join that scans the bottom input
+// in the outer loop.
+func (p *PlanOpNestedLoops) withBottomOuter() (*PlanOpNestedLoops, error) {
+	if p.jType != joinTypeInner {
+		return nil, sql3.NewErrInternalf("unexpected join type %d", p.jType)
+	}
+	op := NewPlanOpNestedLoops(p.top, p.bottom, p.jType, p.cond)
+	op.bottomOuter = true
+	return op, nil
 }
 
 func (p *PlanOpNestedLoops) Expressions() []types.PlanExpression {
@@ -124,6 +150,10 @@ type nestedLoopsIter struct {
 	foundMatch bool
 	rowSize    int
 
+	// if true, top is the bottom input of the join, so rows are built with
+	// the secondary columns first
+	swapped bool
+
 	originalRow types.Row
 }
 
@@ -198,6 +228,9 @@ func (i *nestedLoopsIter) buildRow(primary, secondary types.Row) (types.Row, err
 	case joinTypeInner:
 		first = primary
 		second = secondary
+		if i.swapped {
+			first, second = second, first
+		}
 		secondOffset = len(first)
 
 	default:
@@ -5,6 +5,7 @@ package planner
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/dax"
@@ -119,7 +120,7 @@ func (i *pqlAggregateRowIter) Next(ctx context.Context) (types.Row, error) {
 			return nil, sql3.NewErrInternalf("unexpected aggregate expression type '%T'", i.aggregate.FirstChildExpr())
 		}
 
-		switch i.aggregate.(type) {
+		switch agg := i.aggregate.(type) {
 		case *countDistinctPlanExpression:
 			//make a distinct call
 			distinctCond := &pql.Call{
@@ -135,6 +136,26 @@ func (i *pqlAggregateRowIter) Next(ctx context.Context) (types.Row, error) {
 
 			call = &pql.Call{Name: "Count", Children: []*pql.Call{cond}}
 
+		case *approxCountDistinctPlanExpression:
+			if strings.EqualFold(expr.columnName, string(dax.PrimaryKeyFieldName)) {
+				// every _id is distinct, so there is nothing to estimate
+				if cond == nil {
+					cond = &pql.Call{Name: "All"}
+				}
+				call = &pql.Call{Name: "Count", Children: []*pql.Call{cond}}
+				break
+			}
+			call = &pql.Call{
+				Name: "ApproxCountDistinct",
+				Args: map[string]interface{}{
+					"field":     expr.columnName,
+					"precision": int64(agg.precision),
+				},
+			}
+			if cond != nil {
+				call.Children = []*pql.Call{cond}
+			}
+
 		case *countPlanExpression, *countStarPlanExpression:
 			if cond == nil {
 				// COUNT() should ignore null values
@@ -5,6 +5,7 @@ package planner
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/dax"
@@ -68,16 +69,7 @@ func (p *PlanOpPQLGroupBy) Warnings() []string {
 func (p *PlanOpPQLGroupBy) Schema() types.Schema {
 	result := make(types.Schema, len(p.groupByExprs)+1)
 	for idx, expr := range p.groupByExprs {
-		ref, ok := expr.(*qualifiedRefPlanExpression)
-		if !ok {
-			continue
-		}
-		s := &types.PlannerColumn{
-			ColumnName:   ref.columnName,
-			RelationName: ref.tableName,
-			Type:         expr.Type(),
-		}
-		result[idx] = s
+		result[idx] = groupByColumn(expr)
 	}
 	s := &types.PlannerColumn{
 		ColumnName:   p.aggregate.String(),
@@ -118,6 +110,7 @@ type pqlGroupByRowIter struct {
 	aggregate      types.Aggregable
 
 	result []pilosa.GroupCount
+	table  *dax.Table
 }
 
 var _ types.RowIterator = (*pqlGroupByRowIter)(nil)
@@ -144,9 +137,18 @@ func (i *pqlGroupByRowIter) Next(ctx context.Context) (types.Row, error) {
 		}
 		for _, c := range i.groupByColumns {
 			ref, ok := c.(types.IdentifiableByName)
-			if !ok {
+			if !ok && newPQLGroupByBucket(c) == nil {
 				return nil, sql3.NewErrInternalf("unexpected expression type in group by list '%T'", c)
 			}
+			if bucket := newPQLGroupByBucket(c); bucket != nil {
+				call.Children = append(call.Children,
+					&pql.Call{
+						Name: "Rows",
+						Args: map[string]interface{}{"_field": bucket.ref.columnName, "bucket": bucket.arg()},
+					},
+				)
+				continue
+			}
 			//don't ask for the _id field
 			if ref.Name() != string(dax.PrimaryKeyFieldName) {
 				call.Children = append(call.Children,
@@ -168,7 +170,9 @@ func (i *pqlGroupByRowIter) Next(ctx context.Context) (types.Row, error) {
 		case *countPlanExpression, *countStarPlanExpression:
 			//nop
 
-		case *countDistinctPlanExpression:
+		case *countDistinctPlanExpression, *approxCountDistinctPlanExpression:
+			// GroupBy has no approximate aggregate, so each group gets an
+			// exact distinct count instead
 			aggregate := &pql.Call{
 				Name: "Count",
 				Children: []*pql.Call{{
@@ -206,7 +210,8 @@ func (i *pqlGroupByRowIter) Next(ctx context.Context) (types.Row, error) {
 			return nil, sql3.NewErrTableNotFound(0, 0, i.tableName)
 		}
 
-		queryResponse, err := i.planner.executor.Execute(ctx, tbl, &pql.Query{Calls: []*pql.Call{call}}, nil, nil)
+		shards, _ := queryShardsFromContext(ctx, i.tableName)
+		queryResponse, err := i.planner.executor.Execute(ctx, tbl, &pql.Query{Calls: []*pql.Call{call}}, shards, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -217,6 +222,7 @@ func (i *pqlGroupByRowIter) Next(ctx context.Context) (types.Row, error) {
 		}
 
 		i.result = gcs.Groups()
+		i.table = tbl
 	}
 
 	if len(i.result) > 0 {
@@ -229,7 +235,13 @@ func (i *pqlGroupByRowIter) Next(ctx context.Context) (types.Row, error) {
 		for idx, c := range i.groupByColumns {
 
 			g := group.Group[idx]
-			if g.Value != nil {
+			if bucket := newPQLGroupByBucket(c); bucket != nil && g.Value != nil {
+				v, err := bucket.value(i.table, *g.Value)
+				if err != nil {
+					return nil, err
+				}
+				row[idx] = v
+			} else if g.Value != nil {
 				row[idx] = *g.Value
 			} else if g.RowKey != "" {
 				switch c.Type().(type) {
@@ -253,7 +265,7 @@ func (i *pqlGroupByRowIter) Next(ctx context.Context) (types.Row, error) {
 		case *countPlanExpression, *countStarPlanExpression:
 			row[aggIdx] = int64(group.Count)
 
-		case *countDistinctPlanExpression:
+		case *countDistinctPlanExpression, *approxCountDistinctPlanExpression:
 			row[aggIdx] = int64(group.Agg)
 
 		case *sumPlanExpression:
@@ -288,3 +300,111 @@ func (i *pqlGroupByRowIter) Next(ctx context.Context) (types.Row, error) {
 	}
 	return nil, types.ErrNoMoreRows
 }
+
+// pqlGroupByBucket is a group by expression which PQL computes by grouping
+// an int or timestamp field into buckets with Rows(field, bucket=...).
+type pqlGroupByBucket struct {
+	ref *qualifiedRefPlanExpression
+
+	// interval is the DATE_TRUNC interval for a timestamp field
+	interval string
+
+	// width is the divisor for an int field
+	width int64
+}
+
+// pqlBucketUnits maps DATE_TRUNC intervals to the calendar units of a PQL
+// bucket.
+var pqlBucketUnits = map[string]string{
+	intervalYear:   "year",
+	intervalMonth:  "month",
+	intervalDay:    "day",
+	intervalHour:   "hour",
+	intervalMinute: "minute",
+	intervalSecond: "second",
+}
+
+// newPQLGroupByBucket returns a bucket for a group by expression of the form
+// DATE_TRUNC(interval, timestamp column) or int column / integer, or nil if
+// the expression has neither form.
+func newPQLGroupByBucket(expr types.PlanExpression) *pqlGroupByBucket {
+	switch e := expr.(type) {
+	case *callPlanExpression:
+		if !strings.EqualFold(e.name, "DATE_TRUNC") || len(e.args) != 2 {
+			return nil
+		}
+		interval, ok := e.args[0].(*stringLiteralPlanExpression)
+		if !ok {
+			return nil
+		}
+		if _, ok := pqlBucketUnits[strings.ToUpper(interval.value)]; !ok {
+			return nil
+		}
+		ref, ok := e.args[1].(*qualifiedRefPlanExpression)
+		if !ok {
+			return nil
+		}
+		if _, ok := ref.Type().(*parser.DataTypeTimestamp); !ok {
+			return nil
+		}
+		return &pqlGroupByBucket{ref: ref, interval: strings.ToUpper(interval.value)}
+
+	case *binOpPlanExpression:
+		if e.op != parser.SLASH {
+			return nil
+		}
+		ref, ok := e.lhs.(*qualifiedRefPlanExpression)
+		if !ok {
+			return nil
+		}
+		if _, ok := ref.Type().(*parser.DataTypeInt); !ok {
+			return nil
+		}
+		width, ok := e.rhs.(*intLiteralPlanExpression)
+		if !ok || width.value <= 0 {
+			return nil
+		}
+		return &pqlGroupByBucket{ref: ref, width: width.value}
+	}
+	return nil
+}
+
+// arg returns the bucket argument of the Rows() call.
+func (b *pqlGroupByBucket) arg() interface{} {
+	if b.interval != "" {
+		return pqlBucketUnits[b.interval]
+	}
+	return b.width
+}
+
+// supported returns true if PQL buckets the field the same way the
+// expression evaluates. Integer division truncates towards zero while
+// buckets start at a multiple of the width below the value, so only fields
+// without negative values can be bucketed by width.
+func (b *pqlGroupByBucket) supported(tbl *dax.Table) bool {
+	if b.interval != "" {
+		return true
+	}
+	fld, ok := tbl.Field(dax.FieldName(b.ref.columnName))
+	if !ok {
+		return false
+	}
+	return fld.Options.Min.GreaterThanOrEqualTo(pql.NewDecimal(0, 0))
+}
+
+// value returns the value of the expression for a bucket which starts at
+// the field value v.
+func (b *pqlGroupByBucket) value(tbl *dax.Table, v int64) (interface{}, error) {
+	if b.interval == "" {
+		return v / b.width, nil
+	}
+	fld, ok := tbl.Field(dax.FieldName(b.ref.columnName))
+	if !ok {
+		return nil, sql3.NewErrColumnNotFound(0, 0, b.ref.columnName)
+	}
+	ts, err := pilosa.ValToTimestamp(fld.Options.TimeUnit, v)
+	if err != nil {
+		return nil, err
+	}
+	return dateTrunc(b.interval, ts)
+}
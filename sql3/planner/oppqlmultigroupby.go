@@ -62,16 +62,7 @@ func (p *PlanOpPQLMultiGroupBy) Warnings() []string {
 func (p *PlanOpPQLMultiGroupBy) Schema() types.Schema {
 	result := make(types.Schema, len(p.groupByExprs)+len(p.operators))
 	for idx, expr := range p.groupByExprs {
-		ref, ok := expr.(*qualifiedRefPlanExpression)
-		if !ok {
-			continue
-		}
-		s := &types.PlannerColumn{
-			ColumnName:   ref.columnName,
-			RelationName: ref.tableName,
-			Type:         expr.Type(),
-		}
-		result[idx] = s
+		result[idx] = groupByColumn(expr)
 	}
 	offset := len(p.groupByExprs)
 	for idx, aggOp := range p.operators {
@@ -5,6 +5,7 @@ package planner
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
@@ -20,6 +21,96 @@ type TableQueryHint struct {
 	params []string
 }
 
+// tableSample is the TABLESAMPLE clause of a table scan. Exactly one of rows
+// and percent is set.
+type tableSample struct {
+	rows    int64
+	percent float64
+	seed    *int64
+}
+
+// newTableSample checks the values of a TABLESAMPLE clause and returns them.
+func newTableSample(s *parser.TableSample) (*tableSample, error) {
+	sample := &tableSample{}
+	if s.Rows.IsValid() {
+		lit, ok := s.Size.(*parser.IntegerLit)
+		if !ok {
+			return nil, sql3.NewErrIntegerLiteral(s.Size.Pos().Line, s.Size.Pos().Column)
+		}
+		rows, err := strconv.ParseInt(lit.Value, 10, 64)
+		if err != nil || rows <= 0 {
+			return nil, sql3.NewErrInvalidTableSampleRows(s.Size.Pos().Line, s.Size.Pos().Column)
+		}
+		sample.rows = rows
+	} else {
+		var value string
+		switch lit := s.Size.(type) {
+		case *parser.IntegerLit:
+			value = lit.Value
+		case *parser.FloatLit:
+			value = lit.Value
+		default:
+			return nil, sql3.NewErrLiteralExpected(s.Size.Pos().Line, s.Size.Pos().Column)
+		}
+		percent, err := strconv.ParseFloat(value, 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, sql3.NewErrInvalidTableSamplePercent(s.Size.Pos().Line, s.Size.Pos().Column)
+		}
+		sample.percent = percent
+	}
+	if s.Seed != nil {
+		lit, ok := s.Seed.(*parser.IntegerLit)
+		if !ok {
+			return nil, sql3.NewErrIntegerLiteral(s.Seed.Pos().Line, s.Seed.Pos().Column)
+		}
+		seed, err := strconv.ParseInt(lit.Value, 10, 64)
+		if err != nil {
+			return nil, sql3.NewErrIntegerLiteral(s.Seed.Pos().Line, s.Seed.Pos().Column)
+		}
+		sample.seed = &seed
+	}
+	return sample, nil
+}
+
+// call wraps cond in a Sample() call. A number of rows is sampled from the
+// whole table before cond is applied, as the filter of a query runs over the
+// sampled table. A percentage selects the same rows either way, so cond is
+// sampled directly.
+func (s *tableSample) call(cond *pql.Call) *pql.Call {
+	sample := &pql.Call{
+		Name: "Sample",
+		Args: map[string]interface{}{},
+		Type: pql.PrecallGlobal,
+	}
+	if s.seed != nil {
+		sample.Args["seed"] = *s.seed
+	}
+	if s.rows > 0 {
+		sample.Args["n"] = s.rows
+		sample.Children = []*pql.Call{{Name: "All"}}
+		if cond.Name == "All" {
+			return sample
+		}
+		return &pql.Call{Name: "Intersect", Children: []*pql.Call{sample, cond}}
+	}
+	sample.Args["fraction"] = s.percent / 100
+	sample.Children = []*pql.Call{cond}
+	return sample
+}
+
+func (s *tableSample) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	if s.rows > 0 {
+		result["rows"] = s.rows
+	} else {
+		result["percent"] = s.percent
+	}
+	if s.seed != nil {
+		result["seed"] = *s.seed
+	}
+	return result
+}
+
 // PlanOpPQLTableScan plan operator handles a PQL table scan
 type PlanOpPQLTableScan struct {
 	planner            *ExecutionPlanner
@@ -28,6 +119,7 @@ type PlanOpPQLTableScan struct {
 	filter             types.PlanExpression
 	timeQuantumFilters []types.PlanExpression
 	topExpr            types.PlanExpression
+	sample             *tableSample
 	hints              []*TableQueryHint
 	warnings           []string
 }
@@ -55,6 +147,9 @@ func (p *PlanOpPQLTableScan) Plan() map[string]interface{} {
 	if p.filter != nil {
 		result["filter"] = p.filter.Plan()
 	}
+	if p.sample != nil {
+		result["sample"] = p.sample.Plan()
+	}
 	tqfilters := make([]map[string]interface{}, len(p.timeQuantumFilters))
 	for i, f := range p.timeQuantumFilters {
 		tqfilters[i] = f.Plan()
@@ -130,6 +225,7 @@ func (p *PlanOpPQLTableScan) Iterator(ctx context.Context, row types.Row) (types
 		predicate:          p.filter,
 		timeQuantumFilters: p.timeQuantumFilters,
 		topExpr:            p.topExpr,
+		sample:             p.sample,
 	}, nil
 }
 
@@ -164,16 +260,30 @@ type tableScanRowIter struct {
 	predicate          types.PlanExpression
 	timeQuantumFilters []types.PlanExpression
 	topExpr            types.PlanExpression
+	sample             *tableSample
+
+	// the table being scanned, looked up on the first call to Next()
+	table *dax.Table
+
+	// when batched is set, the Extract() call is executed over
+	// tableScanShardBatchSize shards at a time, and shards holds the shards
+	// yet to be extracted; otherwise it is executed once over all shards
+	batched bool
+	shards  []uint64
 
 	result    []pilosa.ExtractedTableColumn
 	rowWidth  int
 	columnMap map[string]*targetColumn
 }
 
+// tableScanShardBatchSize is the number of shards extracted at a time by a
+// table scan, which bounds the number of rows held in memory by the scan.
+const tableScanShardBatchSize = 4
+
 var _ types.RowIterator = (*tableScanRowIter)(nil)
 
 func (i *tableScanRowIter) Next(ctx context.Context) (types.Row, error) {
-	if i.result == nil {
+	if i.table == nil {
 		err := i.planner.checkAccess(ctx, i.tableName, accessTypeReadData)
 		if err != nil {
 			return nil, err
@@ -205,98 +315,35 @@ func (i *tableScanRowIter) Next(ctx context.Context) (types.Row, error) {
 			}
 		}
 
-		var cond *pql.Call
-
-		cond, err = i.planner.generatePQLCallFromExpr(ctx, i.predicate)
-		if err != nil {
-			return nil, err
-		}
-		if cond == nil {
-			cond = &pql.Call{Name: "All"}
-		}
-
-		if i.topExpr != nil {
-			_, ok := i.topExpr.(*intLiteralPlanExpression)
-			if !ok {
-				return nil, sql3.NewErrInternalf("unexpected top expression type: %T", i.topExpr)
-			}
-			pqlValue, err := planExprToValue(i.topExpr)
+		// Limit() and Sample() are evaluated across all shards, so only
+		// a plain scan can be extracted a batch of shards at a time.
+		if shards, ok := queryShardsFromContext(ctx, i.tableName); ok && i.topExpr == nil && i.sample == nil {
+			i.shards, i.batched = shards, true
+		} else if lister, ok := i.planner.executor.(pilosa.ShardLister); ok && i.topExpr == nil && i.sample == nil {
+			i.shards, err = lister.Shards(ctx, table)
 			if err != nil {
 				return nil, err
 			}
-			cond = &pql.Call{
-				Name:     "Limit",
-				Children: []*pql.Call{cond},
-				Args:     map[string]interface{}{"limit": pqlValue},
-				Type:     pql.PrecallGlobal,
-			}
+			i.batched = true
 		}
-
-		call := &pql.Call{Name: "Extract", Children: []*pql.Call{cond}}
-		for _, c := range i.columns {
-
-			// skip the _id field
-			if strings.EqualFold(c, string(dax.PrimaryKeyFieldName)) {
-				continue
-			}
-
-			foundInTimeQuantumFilters := false
-			for _, tqf := range i.timeQuantumFilters {
-				f, ok := tqf.(*callPlanExpression)
-				if !ok {
-					return nil, sql3.NewErrInternalf("unexpected time quantum filter expression type: %T", tqf)
-				}
-				// argument 0 should be a column ref
-				arg, ok := f.args[0].(*qualifiedRefPlanExpression)
-				if !ok {
-					return nil, sql3.NewErrInternalf("unexpected time quantum filter argument expression type: %T", f.args[0])
-				}
-				if strings.EqualFold(arg.columnName, c) {
-					expr, err := i.planner.generatePQLCallFromExpr(ctx, tqf)
-					if err != nil {
-						return nil, err
-					}
-
-					call.Children = append(call.Children, expr)
-					foundInTimeQuantumFilters = true
-				}
-			}
-
-			if !foundInTimeQuantumFilters {
-				call.Children = append(call.Children,
-					&pql.Call{
-						Name: "Rows",
-						Args: map[string]interface{}{"field": c},
-					},
-				)
+		i.table = table
+		if !i.batched {
+			if err := i.extract(ctx, nil); err != nil {
+				return nil, err
 			}
 		}
+	}
 
-		tbl, err := i.planner.schemaAPI.TableByName(ctx, dax.TableName(i.tableName))
-		if err != nil {
-			return nil, sql3.NewErrTableNotFound(0, 0, i.tableName)
+	for len(i.result) == 0 && len(i.shards) > 0 {
+		n := tableScanShardBatchSize
+		if n > len(i.shards) {
+			n = len(i.shards)
 		}
-
-		queryResponse, err := i.planner.executor.Execute(ctx, tbl, &pql.Query{Calls: []*pql.Call{call}}, nil, nil)
-		if err != nil {
+		shards := i.shards[:n]
+		i.shards = i.shards[n:]
+		if err := i.extract(ctx, shards); err != nil {
 			return nil, err
 		}
-
-		extbl, ok := queryResponse.Results[0].(pilosa.ExtractedTable)
-		if !ok {
-			return nil, sql3.NewErrInternalf("unexpected Extract() result type: %T", queryResponse.Results[0])
-		}
-
-		i.result = extbl.Columns
-
-		//set the source index
-		for idx, fld := range extbl.Fields {
-			mappedColumn, ok := i.columnMap[fld.Name]
-			if !ok {
-				return nil, sql3.NewErrInternalf("mapped column not found for column named '%s'", fld.Name)
-			}
-			mappedColumn.srcColumnIdx = idx
-		}
 	}
 
 	if len(i.result) > 0 {
@@ -354,3 +401,107 @@ func (i *tableScanRowIter) Next(ctx context.Context) (types.Row, error) {
 	}
 	return nil, types.ErrNoMoreRows
 }
+
+// extractCall returns the Extract() call for the scan. A new call is built
+// for each batch of shards, since the executor rewrites the calls it executes
+// (precomputing the children of ConstRow() and the like).
+func (i *tableScanRowIter) extractCall(ctx context.Context) (*pql.Call, error) {
+	cond, err := i.planner.generatePQLCallFromExpr(ctx, i.predicate)
+	if err != nil {
+		return nil, err
+	}
+	if cond == nil {
+		cond = &pql.Call{Name: "All"}
+	}
+	if i.sample != nil {
+		cond = i.sample.call(cond)
+	}
+
+	if i.topExpr != nil {
+		_, ok := i.topExpr.(*intLiteralPlanExpression)
+		if !ok {
+			return nil, sql3.NewErrInternalf("unexpected top expression type: %T", i.topExpr)
+		}
+		pqlValue, err := planExprToValue(i.topExpr)
+		if err != nil {
+			return nil, err
+		}
+		cond = &pql.Call{
+			Name:     "Limit",
+			Children: []*pql.Call{cond},
+			Args:     map[string]interface{}{"limit": pqlValue},
+			Type:     pql.PrecallGlobal,
+		}
+	}
+
+	call := &pql.Call{Name: "Extract", Children: []*pql.Call{cond}}
+	for _, c := range i.columns {
+
+		// skip the _id field
+		if strings.EqualFold(c, string(dax.PrimaryKeyFieldName)) {
+			continue
+		}
+
+		foundInTimeQuantumFilters := false
+		for _, tqf := range i.timeQuantumFilters {
+			f, ok := tqf.(*callPlanExpression)
+			if !ok {
+				return nil, sql3.NewErrInternalf("unexpected time quantum filter expression type: %T", tqf)
+			}
+			// argument 0 should be a column ref
+			arg, ok := f.args[0].(*qualifiedRefPlanExpression)
+			if !ok {
+				return nil, sql3.NewErrInternalf("unexpected time quantum filter argument expression type: %T", f.args[0])
+			}
+			if strings.EqualFold(arg.columnName, c) {
+				expr, err := i.planner.generatePQLCallFromExpr(ctx, tqf)
+				if err != nil {
+					return nil, err
+				}
+
+				call.Children = append(call.Children, expr)
+				foundInTimeQuantumFilters = true
+			}
+		}
+
+		if !foundInTimeQuantumFilters {
+			call.Children = append(call.Children,
+				&pql.Call{
+					Name: "Rows",
+					Args: map[string]interface{}{"field": c},
+				},
+			)
+		}
+	}
+	return call, nil
+}
+
+// extract executes the Extract() call over the given shards (or all shards,
+// if shards is nil), and makes its result the rows returned by Next().
+func (i *tableScanRowIter) extract(ctx context.Context, shards []uint64) error {
+	call, err := i.extractCall(ctx)
+	if err != nil {
+		return err
+	}
+	queryResponse, err := i.planner.executor.Execute(ctx, i.table, &pql.Query{Calls: []*pql.Call{call}}, shards, nil)
+	if err != nil {
+		return err
+	}
+
+	extbl, ok := queryResponse.Results[0].(pilosa.ExtractedTable)
+	if !ok {
+		return sql3.NewErrInternalf("unexpected Extract() result type: %T", queryResponse.Results[0])
+	}
+
+	i.result = extbl.Columns
+
+	//set the source index
+	for idx, fld := range extbl.Fields {
+		mappedColumn, ok := i.columnMap[fld.Name]
+		if !ok {
+			return sql3.NewErrInternalf("mapped column not found for column named '%s'", fld.Name)
+		}
+		mappedColumn.srcColumnIdx = idx
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// PlanOpPrepare plan operator to name a prepared statement for a session.
+type PlanOpPrepare struct {
+	planner   *ExecutionPlanner
+	sessionID string
+	userID    string
+	name      string
+	sql       string
+	warnings  []string
+}
+
+func NewPlanOpPrepare(p *ExecutionPlanner, sessionID string, userID string, name string, sql string) *PlanOpPrepare {
+	return &PlanOpPrepare{
+		planner:   p,
+		sessionID: sessionID,
+		userID:    userID,
+		name:      name,
+		sql:       sql,
+		warnings:  make([]string, 0),
+	}
+}
+
+func (p *PlanOpPrepare) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["sessionID"] = p.sessionID
+	result["name"] = p.name
+	result["sql"] = p.sql
+	return result
+}
+
+func (p *PlanOpPrepare) String() string {
+	return ""
+}
+
+func (p *PlanOpPrepare) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpPrepare) Warnings() []string {
+	return p.warnings
+}
+
+func (p *PlanOpPrepare) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpPrepare) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpPrepare) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &prepareRowIter{
+		planner:   p.planner,
+		sessionID: p.sessionID,
+		userID:    p.userID,
+		name:      p.name,
+		sql:       p.sql,
+	}, nil
+}
+
+func (p *PlanOpPrepare) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	return nil, nil
+}
+
+type prepareRowIter struct {
+	planner   *ExecutionPlanner
+	sessionID string
+	userID    string
+	name      string
+	sql       string
+}
+
+var _ types.RowIterator = (*prepareRowIter)(nil)
+
+func (i *prepareRowIter) Next(ctx context.Context) (types.Row, error) {
+	if err := i.planner.systemLayerAPI.PreparedStatements().Prepare(i.sessionID, i.userID, i.name, i.sql); err != nil {
+		return nil, sql3.NewErrPreparedStatementExists(0, 0, i.name)
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+// PlanOpDeallocate plan operator to remove a prepared statement of a session,
+// or all of them if name is empty.
+type PlanOpDeallocate struct {
+	planner   *ExecutionPlanner
+	sessionID string
+	userID    string
+	name      string
+	warnings  []string
+}
+
+func NewPlanOpDeallocate(p *ExecutionPlanner, sessionID string, userID string, name string) *PlanOpDeallocate {
+	return &PlanOpDeallocate{
+		planner:   p,
+		sessionID: sessionID,
+		userID:    userID,
+		name:      name,
+		warnings:  make([]string, 0),
+	}
+}
+
+func (p *PlanOpDeallocate) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["sessionID"] = p.sessionID
+	result["name"] = p.name
+	return result
+}
+
+func (p *PlanOpDeallocate) String() string {
+	return ""
+}
+
+func (p *PlanOpDeallocate) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpDeallocate) Warnings() []string {
+	return p.warnings
+}
+
+func (p *PlanOpDeallocate) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpDeallocate) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpDeallocate) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &deallocateRowIter{
+		planner:   p.planner,
+		sessionID: p.sessionID,
+		userID:    p.userID,
+		name:      p.name,
+	}, nil
+}
+
+func (p *PlanOpDeallocate) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	return nil, nil
+}
+
+type deallocateRowIter struct {
+	planner   *ExecutionPlanner
+	sessionID string
+	userID    string
+	name      string
+}
+
+var _ types.RowIterator = (*deallocateRowIter)(nil)
+
+func (i *deallocateRowIter) Next(ctx context.Context) (types.Row, error) {
+	prepared := i.planner.systemLayerAPI.PreparedStatements()
+	if i.name == "" {
+		prepared.DeallocateAll(i.sessionID, i.userID)
+	} else if err := prepared.Deallocate(i.sessionID, i.userID, i.name); err != nil {
+		return nil, sql3.NewErrPreparedStatementNotFound(0, 0, i.name)
+	}
+	return nil, types.ErrNoMoreRows
+}
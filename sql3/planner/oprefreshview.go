@@ -0,0 +1,813 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// materializedViewInsertBatchSize is the number of rows written to the table
+// holding the result of a materialized view per insert.
+const materializedViewInsertBatchSize = 1000
+
+// PlanOpRefreshView implements the REFRESH MATERIALIZED VIEW operator
+type PlanOpRefreshView struct {
+	planner  *ExecutionPlanner
+	viewName string
+	warnings []string
+}
+
+func NewPlanOpRefreshView(planner *ExecutionPlanner, viewName string) *PlanOpRefreshView {
+	return &PlanOpRefreshView{
+		planner:  planner,
+		viewName: viewName,
+		warnings: make([]string, 0),
+	}
+}
+
+func (p *PlanOpRefreshView) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpRefreshView) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &refreshViewRowIter{
+		planner:  p.planner,
+		viewName: p.viewName,
+	}, nil
+}
+
+func (p *PlanOpRefreshView) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpRefreshView) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 0 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return NewPlanOpRefreshView(p.planner, p.viewName), nil
+}
+
+func (p *PlanOpRefreshView) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["_schema"] = p.Schema().Plan()
+	result["view"] = p.viewName
+	return result
+}
+
+func (p *PlanOpRefreshView) String() string {
+	return ""
+}
+
+func (p *PlanOpRefreshView) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpRefreshView) Warnings() []string {
+	var w []string
+	w = append(w, p.warnings...)
+	return w
+}
+
+type refreshViewRowIter struct {
+	planner  *ExecutionPlanner
+	viewName string
+}
+
+var _ types.RowIterator = (*refreshViewRowIter)(nil)
+
+func (i *refreshViewRowIter) Next(ctx context.Context) (types.Row, error) {
+	err := i.planner.checkAccess(ctx, i.viewName, accessTypeWriteData)
+	if err != nil {
+		return nil, err
+	}
+
+	// refreshes of a view run one at a time, so each starts from the table
+	// the one before it left behind
+	unlock := i.planner.systemLayerAPI.MaterializedViews().Lock(i.viewName)
+	defer unlock()
+
+	v, err := i.planner.getViewByName(ctx, i.viewName)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, sql3.NewErrViewNotFound(0, 0, i.viewName)
+	}
+	if !v.materialized {
+		return nil, sql3.NewErrViewIsNotMaterialized(0, 0, i.viewName)
+	}
+
+	err = i.planner.refreshMaterializedView(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+// materializedViewQuery is the compiled statement of a materialized view,
+// along with what a refresh needs to know about the shape of its result.
+type materializedViewQuery struct {
+	op     types.PlanOperator
+	schema types.Schema
+
+	// idColumn is the position in schema of an _id column, or -1 if the
+	// statement doesn't return one.
+	idColumn int
+
+	// keyColumns are the positions in schema of the GROUP BY columns if the
+	// view can be refreshed incrementally, otherwise nil. sourceTable is the
+	// table such a view reads.
+	keyColumns  []int
+	sourceTable string
+}
+
+// compileMaterializedViewQuery parses, analyzes and compiles the statement for
+// a materialized view.
+func (p *ExecutionPlanner) compileMaterializedViewQuery(ctx context.Context, view *viewSystemObject) (*materializedViewQuery, error) {
+	ast, err := parser.NewParser(strings.NewReader(view.statement)).ParseStatement()
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := ast.(*parser.SelectStatement)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected ast type")
+	}
+
+	// the shape of the statement is checked before analysis rewrites it
+	keyColumns := materializedViewKeyColumns(sel)
+
+	op, err := p.CompilePlan(ctx, sel)
+	if err != nil {
+		return nil, err
+	}
+	query, ok := op.(*PlanOpQuery)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected root operator type '%T'", op)
+	}
+
+	mvq := &materializedViewQuery{
+		op:       query.ChildOp,
+		schema:   query.Schema(),
+		idColumn: -1,
+	}
+
+	names := make(map[string]struct{})
+	for idx, col := range mvq.schema {
+		if len(col.ColumnName) == 0 {
+			return nil, sql3.NewErrMaterializedViewColumnNameExpected(0, 0, idx+1)
+		}
+		name := strings.ToLower(col.ColumnName)
+		if _, ok := names[name]; ok {
+			return nil, sql3.NewErrDuplicateColumn(0, 0, col.ColumnName)
+		}
+		names[name] = struct{}{}
+
+		if name == string(dax.PrimaryKeyFieldName) {
+			switch col.Type.(type) {
+			case *parser.DataTypeID, *parser.DataTypeString:
+				mvq.idColumn = idx
+			default:
+				return nil, sql3.NewErrTableIDColumnType(0, 0)
+			}
+		}
+	}
+
+	// once analyzed, the source is still a table unless the statement reads
+	// a view which isn't materialized
+	if src, ok := sel.Source.(*parser.QualifiedTableName); ok && keyColumns != nil && mvq.idColumn == -1 {
+		mvq.keyColumns = keyColumns
+		mvq.sourceTable = strings.ToLower(parser.IdentName(src.Name))
+	}
+	return mvq, nil
+}
+
+// materializedViewKeyColumns returns the positions of the GROUP BY columns in
+// the result of sel if it is a GROUP BY over a single table where every other
+// result column is a COUNT or SUM aggregate. The result of these views is the
+// sum of the results over each shard of the table, so they can be refreshed
+// incrementally, and every row is uniquely identified by its group. For any
+// other statement nil is returned.
+func materializedViewKeyColumns(sel *parser.SelectStatement) []int {
+	if len(sel.GroupByExprs) == 0 || sel.Compound != nil || sel.WithClause != nil || sel.Distinct.IsValid() ||
+		sel.TopExpr != nil || sel.LimitExpr != nil || sel.HavingExpr != nil || len(sel.Windows) > 0 {
+		return nil
+	}
+	if src, ok := sel.Source.(*parser.QualifiedTableName); !ok || src.Sample != nil {
+		return nil
+	}
+
+	groupBy := make(map[string]struct{}, len(sel.GroupByExprs))
+	for _, expr := range sel.GroupByExprs {
+		switch expr.(type) {
+		case *parser.Ident, *parser.QualifiedRef:
+		default:
+			return nil
+		}
+		groupBy[strings.ToLower(expr.String())] = struct{}{}
+	}
+
+	keyColumns := make([]int, 0, len(groupBy))
+	grouped := make(map[string]struct{}, len(groupBy))
+	for idx, col := range sel.Columns {
+		switch expr := col.Expr.(type) {
+		case *parser.Call:
+			if expr.Distinct.IsValid() || expr.Filter != nil || expr.Over != nil {
+				return nil
+			}
+			switch strings.ToUpper(parser.IdentName(expr.Name)) {
+			case "COUNT", "SUM":
+			default:
+				return nil
+			}
+		default:
+			key := strings.ToLower(expr.String())
+			if _, ok := groupBy[key]; !ok {
+				return nil
+			}
+			keyColumns = append(keyColumns, idx)
+			grouped[key] = struct{}{}
+		}
+	}
+
+	// every group has to be part of the result, otherwise two rows could end
+	// up with the same key
+	if len(grouped) != len(groupBy) {
+		return nil
+	}
+	return keyColumns
+}
+
+// groupKey returns the key of the group of a row of the result of a view which
+// can be refreshed incrementally.
+func (q *materializedViewQuery) groupKey(row types.Row) (string, error) {
+	key := make([]interface{}, len(q.keyColumns))
+	for i, idx := range q.keyColumns {
+		key[i] = row[idx]
+	}
+	b, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// recordID returns the value to use for the _id column in the table holding
+// the result of a materialized view for a row of the result. ordinal is the
+// position of the row in the result.
+func (q *materializedViewQuery) recordID(row types.Row, ordinal int) (interface{}, error) {
+	switch {
+	case q.idColumn >= 0:
+		return row[q.idColumn], nil
+	case q.keyColumns != nil:
+		return q.groupKey(row)
+	default:
+		return int64(ordinal), nil
+	}
+}
+
+// valueColumns returns the positions in the schema of all the columns other
+// than the _id column.
+func (q *materializedViewQuery) valueColumns() []int {
+	cols := make([]int, 0, len(q.schema))
+	for idx := range q.schema {
+		if idx != q.idColumn {
+			cols = append(cols, idx)
+		}
+	}
+	return cols
+}
+
+// aggregateColumns returns the positions in the schema of the aggregates of a
+// view which can be refreshed incrementally.
+func (q *materializedViewQuery) aggregateColumns() []int {
+	keys := make(map[int]struct{}, len(q.keyColumns))
+	for _, idx := range q.keyColumns {
+		keys[idx] = struct{}{}
+	}
+	cols := make([]int, 0, len(q.schema)-len(q.keyColumns))
+	for idx := range q.schema {
+		if _, ok := keys[idx]; !ok {
+			cols = append(cols, idx)
+		}
+	}
+	return cols
+}
+
+// tableDDL returns the CREATE TABLE statement for a table to hold the result
+// of a materialized view.
+func (q *materializedViewQuery) tableDDL(name string) (string, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "create table %s (%s ", name, dax.PrimaryKeyFieldName)
+	switch {
+	case q.idColumn >= 0:
+		buf.WriteString(q.schema[q.idColumn].Type.TypeDescription())
+	case q.keyColumns != nil:
+		buf.WriteString(dax.BaseTypeString)
+	default:
+		buf.WriteString(dax.BaseTypeID)
+	}
+
+	for _, idx := range q.valueColumns() {
+		col := q.schema[idx]
+		switch col.Type.(type) {
+		case *parser.DataTypeBool, *parser.DataTypeDecimal, *parser.DataTypeID, *parser.DataTypeIDSet,
+			*parser.DataTypeInt, *parser.DataTypeString, *parser.DataTypeStringSet, *parser.DataTypeTimestamp:
+			fmt.Fprintf(&buf, ", %s %s", col.ColumnName, col.Type.TypeDescription())
+		default:
+			return "", sql3.NewErrUnsupported(0, 0, false, fmt.Sprintf("materialized view columns of type '%s'", col.Type.TypeDescription()))
+		}
+	}
+	buf.WriteString(")")
+	return buf.String(), nil
+}
+
+// shardResult executes the statement of a view which can be refreshed
+// incrementally over a single shard of its source table, and returns the rows
+// of the result keyed by group.
+func (q *materializedViewQuery) shardResult(ctx context.Context, shard uint64) (map[string]types.Row, error) {
+	ctx = withQueryShards(ctx, q.sourceTable, []uint64{shard})
+	iter, err := q.op.Iterator(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]types.Row)
+	for {
+		row, err := iter.Next(ctx)
+		if err != nil {
+			if err == types.ErrNoMoreRows {
+				break
+			}
+			return nil, err
+		}
+		key, err := q.groupKey(row)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = append(types.Row(nil), row...)
+	}
+	return result, nil
+}
+
+// totals adds up the results over each shard for the groups in groups, or for
+// every group if groups is nil. The totals are returned ordered by group,
+// along with the groups which have no rows in any shard.
+func (q *materializedViewQuery) totals(partials map[uint64]map[string]types.Row, groups map[string]struct{}) (types.Rows, []string, error) {
+	aggregates := q.aggregateColumns()
+	byGroup := make(map[string]types.Row)
+	for _, partial := range partials {
+		for key, row := range partial {
+			if groups != nil {
+				if _, ok := groups[key]; !ok {
+					continue
+				}
+			}
+			total, ok := byGroup[key]
+			if !ok {
+				byGroup[key] = append(types.Row(nil), row...)
+				continue
+			}
+			for _, idx := range aggregates {
+				v, err := addAggregates(total[idx], row[idx])
+				if err != nil {
+					return nil, nil, err
+				}
+				total[idx] = v
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(byGroup))
+	for key := range byGroup {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	rows := make(types.Rows, len(keys))
+	for i, key := range keys {
+		rows[i] = byGroup[key]
+	}
+
+	var empty []string
+	for key := range groups {
+		if _, ok := byGroup[key]; !ok {
+			empty = append(empty, key)
+		}
+	}
+	sort.Strings(empty)
+	return rows, empty, nil
+}
+
+// addAggregates adds two values of a COUNT or SUM aggregate.
+func addAggregates(a, b interface{}) (interface{}, error) {
+	if a == nil {
+		return b, nil
+	}
+	if b == nil {
+		return a, nil
+	}
+	switch a := a.(type) {
+	case int64:
+		if b, ok := b.(int64); ok {
+			return a + b, nil
+		}
+	case pql.Decimal:
+		if b, ok := b.(pql.Decimal); ok {
+			return pql.AddDecimal(a, b), nil
+		}
+	}
+	return nil, sql3.NewErrInternalf("unexpected aggregate types '%T' and '%T'", a, b)
+}
+
+// queryShardsKey is the context key for the shards of a table the plan
+// operators reading the table are restricted to.
+type queryShardsKey struct{}
+
+type queryShards struct {
+	tableName string
+	shards    []uint64
+}
+
+// withQueryShards returns a context which restricts the operators reading
+// tableName to shards.
+func withQueryShards(ctx context.Context, tableName string, shards []uint64) context.Context {
+	return context.WithValue(ctx, queryShardsKey{}, &queryShards{tableName: tableName, shards: shards})
+}
+
+// queryShardsFromContext returns the shards of tableName ctx restricts reads
+// to, and false if reads aren't restricted.
+func queryShardsFromContext(ctx context.Context, tableName string) ([]uint64, bool) {
+	qs, ok := ctx.Value(queryShardsKey{}).(*queryShards)
+	if !ok || !strings.EqualFold(qs.tableName, tableName) {
+		return nil, false
+	}
+	return qs.shards, true
+}
+
+// rowsIter iterates over rows held in memory.
+type rowsIter struct {
+	rows types.Rows
+}
+
+var _ types.RowIterator = (*rowsIter)(nil)
+
+func (i *rowsIter) Next(ctx context.Context) (types.Row, error) {
+	if len(i.rows) == 0 {
+		return nil, types.ErrNoMoreRows
+	}
+	row := i.rows[0]
+	i.rows = i.rows[1:]
+	return row, nil
+}
+
+// createMaterializedView populates a table with the result of a new
+// materialized view.
+func (p *ExecutionPlanner) createMaterializedView(ctx context.Context, view *viewSystemObject) error {
+	unlock := p.systemLayerAPI.MaterializedViews().Lock(view.name)
+	defer unlock()
+	return p.refreshMaterializedView(ctx, view)
+}
+
+// dropMaterializedView removes the table holding the result of a materialized
+// view.
+func (p *ExecutionPlanner) dropMaterializedView(ctx context.Context, view *viewSystemObject) error {
+	p.systemLayerAPI.MaterializedViews().DeleteState(view.name)
+	if !view.hasTable() {
+		return nil
+	}
+	err := p.schemaAPI.DeleteTable(ctx, dax.TableName(view.table()))
+	if err != nil && !isTableNotFoundError(err) {
+		return err
+	}
+	return nil
+}
+
+// refreshMaterializedView re-executes the statement for a materialized view
+// and brings the table holding its result up to date.
+//
+// Views which are a GROUP BY of COUNT and SUM aggregates over a table are
+// refreshed incrementally when this node did the last refresh: only the shards
+// of the table written to since then are read, and only the groups those
+// shards held before or hold now are rewritten. Every other refresh writes the
+// whole result to a new table, which replaces the old one once it is
+// complete, so readers never see a partial result and a failed refresh leaves
+// the view as it was.
+func (p *ExecutionPlanner) refreshMaterializedView(ctx context.Context, view *viewSystemObject) error {
+	mvq, err := p.compileMaterializedViewQuery(ctx, view)
+	if err != nil {
+		return err
+	}
+	refreshTime := time.Now().UTC()
+
+	if mvq.keyColumns != nil {
+		if versioner, ok := p.executor.(pilosa.ShardVersioner); ok {
+			return p.refreshGroupedMaterializedView(ctx, view, mvq, versioner, refreshTime)
+		}
+	}
+
+	iter, err := mvq.op.Iterator(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return p.rebuildMaterializedView(ctx, view, mvq, refreshTime, iter)
+}
+
+// refreshGroupedMaterializedView refreshes a view which can be refreshed
+// incrementally. The result over each shard of the source table is kept
+// along with the version of the shard it was read at, so the next refresh
+// only has to read the shards whose version has changed.
+func (p *ExecutionPlanner) refreshGroupedMaterializedView(ctx context.Context, view *viewSystemObject, mvq *materializedViewQuery, versioner pilosa.ShardVersioner, refreshTime time.Time) error {
+	tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(mvq.sourceTable))
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return sql3.NewErrTableNotFound(0, 0, mvq.sourceTable)
+		}
+		return err
+	}
+
+	// the versions are read before the shards are, so a write which races
+	// with the refresh changes a version this refresh has seen, and the next
+	// refresh reads the shard again
+	versions, err := versioner.ShardVersions(ctx, tbl)
+	if err != nil {
+		return err
+	}
+
+	// the state of the last refresh is only of use if it describes the table
+	// the view is read from
+	views := p.systemLayerAPI.MaterializedViews()
+	prev, ok := views.GetState(view.name)
+	if !ok || !view.hasTable() || prev.Table != view.table() || prev.RefreshedAt.Unix() != view.refreshedAt.Unix() {
+		prev = nil
+	}
+
+	state := &pilosa.MaterializedViewState{
+		ShardVersions: versions,
+		Partials:      make(map[uint64]map[string]types.Row, len(versions)),
+		RefreshedAt:   refreshTime,
+	}
+
+	// changed holds the groups whose totals have to be written again
+	changed := make(map[string]struct{})
+	read := make([]uint64, 0)
+	for shard, version := range versions {
+		if prev != nil {
+			if v, ok := prev.ShardVersions[shard]; ok && v == version {
+				state.Partials[shard] = prev.Partials[shard]
+				continue
+			}
+			for key := range prev.Partials[shard] {
+				changed[key] = struct{}{}
+			}
+		}
+		read = append(read, shard)
+	}
+	if prev != nil {
+		// shards which no longer hold data add nothing to the totals
+		for shard, partial := range prev.Partials {
+			if _, ok := versions[shard]; ok {
+				continue
+			}
+			for key := range partial {
+				changed[key] = struct{}{}
+			}
+		}
+	}
+
+	sort.Slice(read, func(i, j int) bool { return read[i] < read[j] })
+	for _, shard := range read {
+		partial, err := mvq.shardResult(ctx, shard)
+		if err != nil {
+			return err
+		}
+		state.Partials[shard] = partial
+		for key := range partial {
+			changed[key] = struct{}{}
+		}
+	}
+
+	if prev == nil {
+		// nothing is known about what the view's table holds, so the whole
+		// result is written to a new one
+		rows, _, err := mvq.totals(state.Partials, nil)
+		if err != nil {
+			return err
+		}
+		if err := p.rebuildMaterializedView(ctx, view, mvq, refreshTime, &rowsIter{rows: rows}); err != nil {
+			return err
+		}
+	} else {
+		// if this fails part way, the view's table holds some new totals,
+		// but the state is left as it was, so the next refresh reads the
+		// same shards again and writes the same groups
+		rows, empty, err := mvq.totals(state.Partials, changed)
+		if err != nil {
+			return err
+		}
+		if err := p.insertMaterializedViewRows(ctx, view.table(), mvq, &rowsIter{rows: rows}); err != nil {
+			return err
+		}
+		if err := p.deleteMaterializedViewRows(ctx, view.table(), empty); err != nil {
+			return err
+		}
+		if err := p.updateViewRefresh(ctx, view.name, view.table(), refreshTime); err != nil {
+			return err
+		}
+		view.refreshedAt = refreshTime
+	}
+
+	state.Table = view.table()
+	views.SetState(view.name, state)
+	return nil
+}
+
+// rebuildMaterializedView writes rows, the result of a materialized view, to
+// a new table, then points the view at that table and drops the one it
+// replaces. If writing the new table fails, it is dropped and the view is
+// left as it was.
+func (p *ExecutionPlanner) rebuildMaterializedView(ctx context.Context, view *viewSystemObject, mvq *materializedViewQuery, refreshTime time.Time, rows types.RowIterator) error {
+	tableName := fmt.Sprintf("%s_%d", view.name, refreshTime.UnixNano())
+	err := p.createMaterializedViewTable(ctx, tableName, mvq)
+	if err != nil {
+		return err
+	}
+
+	err = p.insertMaterializedViewRows(ctx, tableName, mvq, rows)
+	if err == nil {
+		err = p.updateViewRefresh(ctx, view.name, tableName, refreshTime)
+	}
+	if err != nil {
+		if derr := p.schemaAPI.DeleteTable(ctx, dax.TableName(tableName)); derr != nil {
+			p.logger.Errorf("dropping table '%s' of failed refresh of materialized view '%s': %v", tableName, view.name, derr)
+		}
+		return err
+	}
+
+	// the view is read from the new table now, so the old one can go
+	if view.hasTable() {
+		if err := p.schemaAPI.DeleteTable(ctx, dax.TableName(view.table())); err != nil && !isTableNotFoundError(err) {
+			p.logger.Errorf("dropping table '%s' replaced by refresh of materialized view '%s': %v", view.table(), view.name, err)
+		}
+	}
+	view.tableName = tableName
+	view.refreshedAt = refreshTime
+	return nil
+}
+
+// createMaterializedViewTable creates a table to hold the result of a
+// materialized view.
+func (p *ExecutionPlanner) createMaterializedViewTable(ctx context.Context, tableName string, mvq *materializedViewQuery) error {
+	ddl, err := mvq.tableDDL(tableName)
+	if err != nil {
+		return err
+	}
+
+	ast, err := parser.NewParser(strings.NewReader(ddl)).ParseStatement()
+	if err != nil {
+		return err
+	}
+	ct, ok := ast.(*parser.CreateTableStatement)
+	if !ok {
+		return sql3.NewErrInternalf("unexpected ast type")
+	}
+	err = p.analyzeCreateTableStatement(ct)
+	if err != nil {
+		return err
+	}
+	ctOp, err := p.compileCreateTableStatement(ctx, ct)
+	if err != nil {
+		return err
+	}
+	ctIter, err := ctOp.Iterator(ctx, nil)
+	if err != nil {
+		return err
+	}
+	_, err = ctIter.Next(ctx)
+	if err != nil && err != types.ErrNoMoreRows {
+		return err
+	}
+	return nil
+}
+
+// insertMaterializedViewRows writes rows of the result of a materialized view
+// to tableName, a batch at a time.
+func (p *ExecutionPlanner) insertMaterializedViewRows(ctx context.Context, tableName string, mvq *materializedViewQuery, rows types.RowIterator) error {
+	valueColumns := mvq.valueColumns()
+
+	targetColumns := []*qualifiedRefPlanExpression{
+		newQualifiedRefPlanExpression(tableName, string(dax.PrimaryKeyFieldName), 0, parser.NewDataTypeString()),
+	}
+	for _, idx := range valueColumns {
+		col := mvq.schema[idx]
+		targetColumns = append(targetColumns, newQualifiedRefPlanExpression(tableName, col.ColumnName, 0, col.Type))
+	}
+
+	insertIter := &insertRowIter{
+		planner:       p,
+		tableName:     tableName,
+		targetColumns: targetColumns,
+	}
+	insert := func(batch [][]types.PlanExpression) error {
+		insertIter.insertValues = batch
+		_, err := insertIter.Next(ctx)
+		if err != nil && err != types.ErrNoMoreRows {
+			return err
+		}
+		return nil
+	}
+
+	insertBatch := make([][]types.PlanExpression, 0)
+	for ordinal := 0; ; ordinal++ {
+		row, err := rows.Next(ctx)
+		if err != nil {
+			if err == types.ErrNoMoreRows {
+				break
+			}
+			return err
+		}
+
+		id, err := mvq.recordID(row, ordinal)
+		if err != nil {
+			return err
+		}
+		irow := make([]types.PlanExpression, 0, len(targetColumns))
+		switch id := id.(type) {
+		case string:
+			irow = append(irow, newStringLiteralPlanExpression(id))
+		case int64:
+			irow = append(irow, newIntLiteralPlanExpression(id))
+		default:
+			return sql3.NewErrInternalf("unexpected key type '%T'", id)
+		}
+
+		for _, idx := range valueColumns {
+			if row[idx] == nil {
+				irow = append(irow, newNullLiteralPlanExpression())
+				continue
+			}
+			expr, err := newLiteralPlanExpressionFromValue(mvq.schema[idx].Type, row[idx])
+			if err != nil {
+				return err
+			}
+			irow = append(irow, expr)
+		}
+		insertBatch = append(insertBatch, irow)
+
+		if len(insertBatch) >= materializedViewInsertBatchSize {
+			if err := insert(insertBatch); err != nil {
+				return err
+			}
+			insertBatch = make([][]types.PlanExpression, 0)
+		}
+	}
+	if len(insertBatch) > 0 {
+		return insert(insertBatch)
+	}
+	return nil
+}
+
+// deleteMaterializedViewRows deletes the rows of groups which no longer exist
+// from the table holding the result of a view which can be refreshed
+// incrementally.
+func (p *ExecutionPlanner) deleteMaterializedViewRows(ctx context.Context, tableName string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(tableName))
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return sql3.NewErrTableNotFound(0, 0, tableName)
+		}
+		return err
+	}
+	columns := make([]interface{}, len(keys))
+	for i, key := range keys {
+		columns[i] = key
+	}
+	call := &pql.Call{
+		Name: "Delete",
+		Children: []*pql.Call{
+			{
+				Name: "ConstRow",
+				Args: map[string]interface{}{
+					"columns": columns,
+				},
+				Type: pql.PrecallGlobal,
+			},
+		},
+	}
+	_, err = p.executor.Execute(ctx, tbl, &pql.Query{Calls: []*pql.Call{call}}, nil, nil)
+	return err
+}
@@ -5,13 +5,17 @@ package planner
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/pql"
 	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/parser"
 	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
 )
 
-// PlanOpTableValuedFunction is an operator for a subquery
+// PlanOpTableValuedFunction is an operator for a table valued function
 type PlanOpTableValuedFunction struct {
 	planner  *ExecutionPlanner
 	callExpr types.PlanExpression
@@ -43,7 +47,19 @@ func (p *PlanOpTableValuedFunction) Schema() types.Schema {
 }
 
 func (p *PlanOpTableValuedFunction) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
-	return nil, sql3.NewErrInternalf("table valued functions are not yet implemented")
+	call, ok := p.callExpr.(*callPlanExpression)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected table valued function expression type '%T'", p.callExpr)
+	}
+	switch strings.ToUpper(call.name) {
+	case "FUNNEL":
+		return &funnelRowIter{
+			planner: p.planner,
+			args:    call.args,
+		}, nil
+	default:
+		return nil, sql3.NewErrInternalf("unexpected table valued function '%s'", call.name)
+	}
 }
 
 func (p *PlanOpTableValuedFunction) Children() []types.PlanOperator {
@@ -51,13 +67,14 @@ func (p *PlanOpTableValuedFunction) Children() []types.PlanOperator {
 }
 
 func (p *PlanOpTableValuedFunction) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
-	return nil, nil
+	return p, nil
 }
 
 func (p *PlanOpTableValuedFunction) Plan() map[string]interface{} {
 	result := make(map[string]interface{})
 	result["_op"] = fmt.Sprintf("%T", p)
 	result["_schema"] = p.Schema().Plan()
+	result["call"] = p.callExpr.Plan()
 	return result
 }
 
@@ -78,3 +95,93 @@ func (p *PlanOpTableValuedFunction) Warnings() []string {
 func (p *PlanOpTableValuedFunction) Name() string {
 	return ""
 }
+
+// funnelRowIter executes a funnel() table valued function as a PQL
+// Funnel() call, and returns a row per step.
+type funnelRowIter struct {
+	planner *ExecutionPlanner
+	args    []types.PlanExpression
+
+	result [][]interface{}
+}
+
+var _ types.RowIterator = (*funnelRowIter)(nil)
+
+func (i *funnelRowIter) Next(ctx context.Context) (types.Row, error) {
+	if i.result == nil {
+		if err := i.execute(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if len(i.result) == 0 {
+		return nil, types.ErrNoMoreRows
+	}
+	row := i.result[0]
+	i.result = i.result[1:]
+	return row, nil
+}
+
+func (i *funnelRowIter) execute(ctx context.Context) error {
+	// analysis has already checked these are literals of the right types
+	vals := make([]interface{}, len(i.args))
+	for j, arg := range i.args {
+		v, err := arg.Evaluate(nil)
+		if err != nil {
+			return err
+		}
+		vals[j] = v
+	}
+	tableName, columnName := vals[0].(string), vals[1].(string)
+
+	err := i.planner.checkAccess(ctx, tableName, accessTypeReadData)
+	if err != nil {
+		return err
+	}
+
+	call := &pql.Call{Name: "Funnel", Args: map[string]interface{}{}}
+	var values []interface{}
+	switch steps := vals[2].(type) {
+	case []string:
+		for _, s := range steps {
+			values = append(values, s)
+		}
+	case []int64:
+		for _, s := range steps {
+			values = append(values, s)
+		}
+	default:
+		return sql3.NewErrInternalf("unexpected funnel steps type '%T'", vals[2])
+	}
+	for _, v := range values {
+		call.Children = append(call.Children, &pql.Call{
+			Name: "Row",
+			Args: map[string]interface{}{columnName: v},
+		})
+	}
+	for j, name := range []string{"window", "from", "to"} {
+		if j+3 < len(vals) && vals[j+3] != nil {
+			call.Args[name] = vals[j+3]
+		}
+	}
+	if len(vals) == 7 {
+		call.Args["ordered"] = vals[6]
+	}
+
+	tbl, err := i.planner.schemaAPI.TableByName(ctx, dax.TableName(tableName))
+	if err != nil {
+		return sql3.NewErrTableNotFound(0, 0, tableName)
+	}
+	queryResponse, err := i.planner.executor.Execute(ctx, tbl, &pql.Query{Calls: []*pql.Call{call}}, nil, nil)
+	if err != nil {
+		return err
+	}
+	funnel, ok := queryResponse.Results[0].(*pilosa.FunnelResult)
+	if !ok {
+		return sql3.NewErrInternalf("unexpected Funnel() result type '%T'", queryResponse.Results[0])
+	}
+	i.result = make([][]interface{}, 0, len(funnel.Counts))
+	for j, n := range funnel.Counts {
+		i.result = append(i.result, []interface{}{int64(j + 1), values[j], int64(n)})
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"fmt"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/errors"
+	"github.com/featurebasedb/featurebase/v3/sql3"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+// PlanOpBeginTransaction plan operator to begin a transaction for a session.
+type PlanOpBeginTransaction struct {
+	planner   *ExecutionPlanner
+	sessionID string
+	userID    string
+	warnings  []string
+}
+
+func NewPlanOpBeginTransaction(p *ExecutionPlanner, sessionID string, userID string) *PlanOpBeginTransaction {
+	return &PlanOpBeginTransaction{
+		planner:   p,
+		sessionID: sessionID,
+		userID:    userID,
+		warnings:  make([]string, 0),
+	}
+}
+
+func (p *PlanOpBeginTransaction) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["sessionID"] = p.sessionID
+	return result
+}
+
+func (p *PlanOpBeginTransaction) String() string {
+	return ""
+}
+
+func (p *PlanOpBeginTransaction) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpBeginTransaction) Warnings() []string {
+	return p.warnings
+}
+
+func (p *PlanOpBeginTransaction) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpBeginTransaction) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpBeginTransaction) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &beginTransactionRowIter{
+		planner:   p.planner,
+		sessionID: p.sessionID,
+		userID:    p.userID,
+	}, nil
+}
+
+func (p *PlanOpBeginTransaction) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	return nil, nil
+}
+
+type beginTransactionRowIter struct {
+	planner   *ExecutionPlanner
+	sessionID string
+	userID    string
+}
+
+var _ types.RowIterator = (*beginTransactionRowIter)(nil)
+
+func (i *beginTransactionRowIter) Next(ctx context.Context) (types.Row, error) {
+	if _, err := i.planner.systemLayerAPI.Transactions().BeginTransaction(i.sessionID, i.userID); err != nil {
+		return nil, sql3.NewErrTransactionInProgress(0, 0)
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+// PlanOpCommitTransaction plan operator to commit the transaction of a
+// session, applying the writes it deferred.
+type PlanOpCommitTransaction struct {
+	planner   *ExecutionPlanner
+	sessionID string
+	userID    string
+	warnings  []string
+}
+
+func NewPlanOpCommitTransaction(p *ExecutionPlanner, sessionID string, userID string) *PlanOpCommitTransaction {
+	return &PlanOpCommitTransaction{
+		planner:   p,
+		sessionID: sessionID,
+		userID:    userID,
+		warnings:  make([]string, 0),
+	}
+}
+
+func (p *PlanOpCommitTransaction) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["sessionID"] = p.sessionID
+	return result
+}
+
+func (p *PlanOpCommitTransaction) String() string {
+	return ""
+}
+
+func (p *PlanOpCommitTransaction) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpCommitTransaction) Warnings() []string {
+	return p.warnings
+}
+
+func (p *PlanOpCommitTransaction) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpCommitTransaction) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpCommitTransaction) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &commitTransactionRowIter{
+		planner:   p.planner,
+		sessionID: p.sessionID,
+		userID:    p.userID,
+	}, nil
+}
+
+func (p *PlanOpCommitTransaction) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	return nil, nil
+}
+
+type commitTransactionRowIter struct {
+	planner   *ExecutionPlanner
+	sessionID string
+	userID    string
+}
+
+var _ types.RowIterator = (*commitTransactionRowIter)(nil)
+
+func (i *commitTransactionRowIter) Next(ctx context.Context) (types.Row, error) {
+	transactions := i.planner.systemLayerAPI.Transactions()
+	txn, err := transactions.EndTransaction(i.sessionID, i.userID)
+	if err != nil {
+		return nil, sql3.NewErrNoTransactionInProgress(0, 0)
+	}
+
+	// a failed transaction is rolled back by COMMIT; none of its writes are
+	// applied
+	if err := txn.Err(); err != nil {
+		transactions.RollbackTransaction(txn)
+		return nil, sql3.NewErrTransactionAborted(0, 0, err)
+	}
+
+	if err := transactions.CommitTransaction(txn); err != nil {
+		return nil, errors.Wrap(err, "committing transaction")
+	}
+	return nil, types.ErrNoMoreRows
+}
+
+// PlanOpRollbackTransaction plan operator to roll back the transaction of a
+// session, discarding the writes it deferred.
+type PlanOpRollbackTransaction struct {
+	planner   *ExecutionPlanner
+	sessionID string
+	userID    string
+	warnings  []string
+}
+
+func NewPlanOpRollbackTransaction(p *ExecutionPlanner, sessionID string, userID string) *PlanOpRollbackTransaction {
+	return &PlanOpRollbackTransaction{
+		planner:   p,
+		sessionID: sessionID,
+		userID:    userID,
+		warnings:  make([]string, 0),
+	}
+}
+
+func (p *PlanOpRollbackTransaction) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["sessionID"] = p.sessionID
+	return result
+}
+
+func (p *PlanOpRollbackTransaction) String() string {
+	return ""
+}
+
+func (p *PlanOpRollbackTransaction) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpRollbackTransaction) Warnings() []string {
+	return p.warnings
+}
+
+func (p *PlanOpRollbackTransaction) Schema() types.Schema {
+	return types.Schema{}
+}
+
+func (p *PlanOpRollbackTransaction) Children() []types.PlanOperator {
+	return []types.PlanOperator{}
+}
+
+func (p *PlanOpRollbackTransaction) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	return &rollbackTransactionRowIter{
+		planner:   p.planner,
+		sessionID: p.sessionID,
+		userID:    p.userID,
+	}, nil
+}
+
+func (p *PlanOpRollbackTransaction) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	return nil, nil
+}
+
+type rollbackTransactionRowIter struct {
+	planner   *ExecutionPlanner
+	sessionID string
+	userID    string
+}
+
+var _ types.RowIterator = (*rollbackTransactionRowIter)(nil)
+
+func (i *rollbackTransactionRowIter) Next(ctx context.Context) (types.Row, error) {
+	transactions := i.planner.systemLayerAPI.Transactions()
+	txn, err := transactions.EndTransaction(i.sessionID, i.userID)
+	if err != nil {
+		return nil, sql3.NewErrNoTransactionInProgress(0, 0)
+	}
+	transactions.RollbackTransaction(txn)
+	return nil, types.ErrNoMoreRows
+}
+
+// PlanOpTransactionStatement plan operator to run a statement in the open
+// transaction of a session. Its reads see the writes the transaction has
+// made, and its writes are held by the transaction until it commits.
+type PlanOpTransactionStatement struct {
+	planner *ExecutionPlanner
+	txn     *pilosa.SQLTransaction
+	// the table the statement writes, or empty for a read
+	tableName string
+	ChildOp   types.PlanOperator
+	warnings  []string
+}
+
+func NewPlanOpTransactionStatement(p *ExecutionPlanner, txn *pilosa.SQLTransaction, tableName string, child types.PlanOperator) *PlanOpTransactionStatement {
+	return &PlanOpTransactionStatement{
+		planner:   p,
+		txn:       txn,
+		tableName: tableName,
+		ChildOp:   child,
+		warnings:  make([]string, 0),
+	}
+}
+
+func (p *PlanOpTransactionStatement) Plan() map[string]interface{} {
+	result := make(map[string]interface{})
+	result["_op"] = fmt.Sprintf("%T", p)
+	result["_schema"] = p.Schema().Plan()
+	result["sessionID"] = p.txn.SessionID
+	result["tableName"] = p.tableName
+	result["child"] = p.ChildOp.Plan()
+	return result
+}
+
+func (p *PlanOpTransactionStatement) String() string {
+	return ""
+}
+
+func (p *PlanOpTransactionStatement) AddWarning(warning string) {
+	p.warnings = append(p.warnings, warning)
+}
+
+func (p *PlanOpTransactionStatement) Warnings() []string {
+	var w []string
+	w = append(w, p.warnings...)
+	w = append(w, p.ChildOp.Warnings()...)
+	return w
+}
+
+func (p *PlanOpTransactionStatement) Schema() types.Schema {
+	return p.ChildOp.Schema()
+}
+
+func (p *PlanOpTransactionStatement) Children() []types.PlanOperator {
+	return []types.PlanOperator{
+		p.ChildOp,
+	}
+}
+
+func (p *PlanOpTransactionStatement) Iterator(ctx context.Context, row types.Row) (types.RowIterator, error) {
+	if p.tableName != "" {
+		if err := p.planner.systemLayerAPI.Transactions().LockWrites(ctx, p.txn); err != nil {
+			p.txn.Fail(err)
+			return nil, err
+		}
+	}
+	ctx = pilosa.WithSQLTransaction(ctx, p.txn)
+	iter, err := p.ChildOp.Iterator(ctx, row)
+	if err != nil {
+		p.txn.Fail(err)
+		return nil, err
+	}
+	return &transactionStatementRowIter{
+		txn:  p.txn,
+		iter: iter,
+	}, nil
+}
+
+func (p *PlanOpTransactionStatement) WithChildren(children ...types.PlanOperator) (types.PlanOperator, error) {
+	if len(children) != 1 {
+		return nil, sql3.NewErrInternalf("unexpected number of children '%d'", len(children))
+	}
+	return NewPlanOpTransactionStatement(p.planner, p.txn, p.tableName, children[0]), nil
+}
+
+type transactionStatementRowIter struct {
+	txn  *pilosa.SQLTransaction
+	iter types.RowIterator
+}
+
+var _ types.RowIterator = (*transactionStatementRowIter)(nil)
+
+func (i *transactionStatementRowIter) Next(ctx context.Context) (types.Row, error) {
+	row, err := i.iter.Next(pilosa.WithSQLTransaction(ctx, i.txn))
+	if err != nil && err != types.ErrNoMoreRows {
+		// some of the statement's writes may have been made, so the
+		// transaction can't commit
+		i.txn.Fail(err)
+	}
+	return row, err
+}
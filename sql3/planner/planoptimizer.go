@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/featurebasedb/featurebase/v3/dax"
@@ -52,6 +53,14 @@ var optimizerFunctions = []OptimizerFunc{
 	// one TableScanOperator,  try to use a PQL aggregate operators instead
 	tryToReplaceGroupByWithPQLAggregate,
 
+	// if the tables have statistics, evaluate the most selective filter
+	// predicates first
+	reorderFilterPredicates,
+
+	// if the tables have statistics, scan the smaller input of an inner join
+	// in the outer loop
+	reorderJoins,
+
 	// update the columnIdx for all the qualified references in various operators
 	fixFieldRefs,
 
@@ -313,7 +322,9 @@ func removeUnusedExtractColumnReferences(ctx context.Context, a *ExecutionPlanne
 			}
 
 			// newExtractList should now contain just the cols that are referenced
-			return NewPlanOpPQLTableScan(a, thisNode.tableName, newExtractList, thisNode.hints), false, nil
+			newOp := NewPlanOpPQLTableScan(a, thisNode.tableName, newExtractList, thisNode.hints)
+			newOp.sample = thisNode.sample
+			return newOp, false, nil
 
 		default:
 			return thisNode, true, nil
@@ -671,8 +682,9 @@ func tryToReplaceGroupByWithPQLAggregate(ctx context.Context, a *ExecutionPlanne
 	//go find the table scan operators
 	tables := getTableScanOperators(ctx, a, n, scope)
 
-	//only do this if we have one TableScanOperator
-	if len(tables) == 1 {
+	//only do this if we have one TableScanOperator, and it isn't sampled
+	//since the aggregate would read the whole table
+	if len(tables) == 1 && tables[0].sample == nil {
 		return TransformPlanOp(n, func(node types.PlanOperator) (types.PlanOperator, bool, error) {
 			switch thisNode := node.(type) {
 			case *PlanOpGroupBy:
@@ -713,6 +725,19 @@ func tryToReplaceGroupByWithPQLAggregate(ctx context.Context, a *ExecutionPlanne
 					case *corrPlanExpression, *varPlanExpression:
 						return thisNode, true, nil
 
+					// sketches are built from the distinct values of each
+					// shard, so keyed and id columns can be pushed down too,
+					// but not sets, whose distinct values are their members
+					case *approxCountDistinctPlanExpression:
+						ref, ok := aggregable.FirstChildExpr().(*qualifiedRefPlanExpression)
+						if !ok {
+							return thisNode, true, nil
+						}
+						switch ref.Type().(type) {
+						case *parser.DataTypeIDSet, *parser.DataTypeStringSet, *parser.DataTypeIDSetQuantum, *parser.DataTypeStringSetQuantum:
+							return thisNode, true, nil
+						}
+
 					case types.Aggregable:
 						switch ref := aggregable.FirstChildExpr().(type) {
 						case *qualifiedRefPlanExpression:
@@ -791,8 +816,9 @@ func tryToReplaceDistinctWithPQLDistinct(ctx context.Context, a *ExecutionPlanne
 	//go find the table scan operators
 	tables := getTableScanOperators(ctx, a, n, scope)
 
-	//only do this if we have one TableScanOperator
-	if len(tables) != 1 {
+	//only do this if we have one TableScanOperator, and it isn't sampled
+	//since the replacement would read the whole table
+	if len(tables) != 1 || tables[0].sample != nil {
 		return n, true, nil
 	}
 	replacedWithDistinct := false
@@ -886,8 +912,9 @@ func tryToReplaceGroupByWithPQLGroupBy(ctx context.Context, a *ExecutionPlanner,
 	//go find the table scan operators
 	tables := getTableScanOperators(ctx, a, n, scope)
 
-	//only do this if we have one TableScanOperator
-	if len(tables) != 1 {
+	//only do this if we have one TableScanOperator, and it isn't sampled
+	//since the replacement would read the whole table
+	if len(tables) != 1 || tables[0].sample != nil {
 		return n, true, nil
 	}
 
@@ -903,6 +930,16 @@ func tryToReplaceGroupByWithPQLGroupBy(ctx context.Context, a *ExecutionPlanner,
 			// get the table
 			table := tables[0]
 
+			// other than column references, we can only group by
+			// expressions which PQL computes by bucketing a column
+			ok, err := a.canGroupByInPQL(ctx, table, thisNode)
+			if err != nil {
+				return thisNode, true, err
+			}
+			if !ok {
+				return thisNode, true, nil
+			}
+
 			// if we are grouping on set columns, see if we have any flatten query hints
 			for _, gbc := range thisNode.GroupByExprs {
 				gbcRef, ok := gbc.(*qualifiedRefPlanExpression)
@@ -934,6 +971,16 @@ func tryToReplaceGroupByWithPQLGroupBy(ctx context.Context, a *ExecutionPlanner,
 				}
 			}
 
+			// if the groups are nearly unique, grouping in memory is cheaper
+			// than a GroupBy that produces a group for almost every row
+			inMemory, err := a.preferInMemoryGroupBy(ctx, table, thisNode.GroupByExprs)
+			if err != nil {
+				return thisNode, true, err
+			}
+			if inMemory {
+				return thisNode, true, nil
+			}
+
 			// get the type of the _id column for this table
 			pkType, err := table.PrimaryKeyType()
 			if err != nil {
@@ -964,7 +1011,17 @@ func tryToReplaceGroupByWithPQLGroupBy(ctx context.Context, a *ExecutionPlanner,
 
 			// use a multi group by if more than 1 aggregate
 			if len(thisNode.Aggregates) > 1 {
-				newOp := NewPlanOpPQLMultiGroupBy(a, ops, thisNode.GroupByExprs)
+				// the multi group by merges the rows of each group by, so
+				// group by expressions refer to the columns of those rows
+				groupByExprs := make([]types.PlanExpression, len(thisNode.GroupByExprs))
+				for idx, expr := range thisNode.GroupByExprs {
+					if _, ok := expr.(*qualifiedRefPlanExpression); ok {
+						groupByExprs[idx] = expr
+						continue
+					}
+					groupByExprs[idx] = newQualifiedRefPlanExpression("", expr.String(), idx, expr.Type())
+				}
+				newOp := NewPlanOpPQLMultiGroupBy(a, ops, groupByExprs)
 				return newOp, false, nil
 			}
 
@@ -977,6 +1034,208 @@ func tryToReplaceGroupByWithPQLGroupBy(ctx context.Context, a *ExecutionPlanner,
 	})
 }
 
+// canGroupByInPQL returns true if every group by expression of a group by
+// over a single table is either a column reference or an expression which
+// can be computed by grouping a column into PQL buckets. GroupBy can't compute
+// a distinct count for buckets, so those aggregates are grouped in memory.
+func (p *ExecutionPlanner) canGroupByInPQL(ctx context.Context, table *PlanOpPQLTableScan, groupBy *PlanOpGroupBy) (bool, error) {
+	var buckets []*pqlGroupByBucket
+	for _, expr := range groupBy.GroupByExprs {
+		if _, ok := expr.(*qualifiedRefPlanExpression); ok {
+			continue
+		}
+		bucket := newPQLGroupByBucket(expr)
+		if bucket == nil {
+			return false, nil
+		}
+		buckets = append(buckets, bucket)
+	}
+	if len(buckets) == 0 {
+		return true, nil
+	}
+
+	for _, agg := range groupBy.Aggregates {
+		switch agg.(type) {
+		case *countDistinctPlanExpression, *approxCountDistinctPlanExpression:
+			return false, nil
+		}
+	}
+
+	tbl, err := p.schemaAPI.TableByName(ctx, dax.TableName(table.tableName))
+	if err != nil {
+		return false, sql3.NewErrTableNotFound(0, 0, table.tableName)
+	}
+	for _, bucket := range buckets {
+		if !bucket.supported(tbl) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// preferInMemoryGroupBy returns true if the statistics for a table estimate
+// that grouping by the expressions given yields a group for most rows.
+// Grouping in memory includes a group for nulls and PQL GroupBy doesn't, so
+// the columns must have no nulls.
+func (p *ExecutionPlanner) preferInMemoryGroupBy(ctx context.Context, table *PlanOpPQLTableScan, groupByExprs []types.PlanExpression) (bool, error) {
+	stats, err := p.getTableStatistics(ctx, table.tableName)
+	if err != nil || stats == nil || stats.rowCount == 0 {
+		return false, err
+	}
+
+	groups := 1.0
+	for _, expr := range groupByExprs {
+		ref, ok := expr.(*qualifiedRefPlanExpression)
+		if !ok {
+			return false, nil
+		}
+		switch ref.Type().(type) {
+		case *parser.DataTypeIDSet, *parser.DataTypeStringSet:
+			return false, nil
+		}
+		col := stats.column(ref.columnName)
+		if col == nil || col.nullFraction > 0 {
+			return false, nil
+		}
+		groups *= float64(col.distinctCount)
+	}
+	return groups >= float64(stats.rowCount)*inMemoryGroupByThreshold, nil
+}
+
+// reorderFilterPredicates sorts the conjuncts of a filter over a single
+// analyzed table by their estimated selectivity, so that evaluation of the
+// filter stops at the first false predicate as early as possible. Only
+// conjuncts which can't fail are moved, and never past one which can; in
+// "b != 0 AND a/b > 1" the first conjunct guards the second.
+func reorderFilterPredicates(ctx context.Context, a *ExecutionPlanner, n types.PlanOperator, scope *OptimizerScope) (types.PlanOperator, bool, error) {
+	return TransformPlanOp(n, func(node types.PlanOperator) (types.PlanOperator, bool, error) {
+		switch thisNode := node.(type) {
+		case *PlanOpFilter:
+			predicates := splitOnAnd(thisNode.Predicate)
+			if len(predicates) < 2 {
+				return thisNode, true, nil
+			}
+
+			stats, err := a.getSingleTableStatistics(ctx, thisNode.ChildOp)
+			if err != nil {
+				return thisNode, true, err
+			}
+			if stats == nil {
+				return thisNode, true, nil
+			}
+
+			sorted, ok := orderPredicates(stats, predicates)
+			if !ok {
+				return thisNode, true, nil
+			}
+			return NewPlanOpFilter(a, joinExprsWithAnd(sorted...), thisNode.ChildOp), false, nil
+
+		default:
+			return thisNode, true, nil
+		}
+	})
+}
+
+// orderPredicates sorts each run of reorderable conjuncts in predicates by
+// their estimated selectivity, leaving the other conjuncts where they are.
+// It returns false if the order is unchanged.
+func orderPredicates(stats *tableStatistics, predicates []types.PlanExpression) ([]types.PlanExpression, bool) {
+	order := make([]int, len(predicates))
+	selectivities := make([]float64, len(predicates))
+	for i, pred := range predicates {
+		order[i] = i
+		selectivities[i] = estimateSelectivity(stats, pred)
+	}
+	for start := 0; start < len(order); {
+		if !isReorderablePredicate(predicates[start]) {
+			start++
+			continue
+		}
+		end := start + 1
+		for end < len(order) && isReorderablePredicate(predicates[end]) {
+			end++
+		}
+		run := order[start:end]
+		sort.SliceStable(run, func(i, j int) bool {
+			return selectivities[run[i]] < selectivities[run[j]]
+		})
+		start = end
+	}
+
+	same := true
+	sorted := make([]types.PlanExpression, len(predicates))
+	for i, idx := range order {
+		sorted[i] = predicates[idx]
+		if idx != i {
+			same = false
+		}
+	}
+	return sorted, !same
+}
+
+// isReorderablePredicate reports whether expr is a comparison of columns and
+// literals, which has no side effects and can't fail, so it can be evaluated
+// in any order with the other conjuncts of a filter.
+func isReorderablePredicate(expr types.PlanExpression) bool {
+	e, ok := expr.(*binOpPlanExpression)
+	if !ok {
+		return false
+	}
+	switch e.op {
+	case parser.EQ, parser.NE, parser.LT, parser.LE, parser.GT, parser.GE, parser.IS, parser.ISNOT:
+	default:
+		return false
+	}
+	return isColumnOrLiteral(e.lhs) && isColumnOrLiteral(e.rhs)
+}
+
+// isColumnOrLiteral reports whether expr is a column reference or a scalar
+// literal.
+func isColumnOrLiteral(expr types.PlanExpression) bool {
+	switch expr.(type) {
+	case *qualifiedRefPlanExpression, *nullLiteralPlanExpression, *intLiteralPlanExpression,
+		*floatLiteralPlanExpression, *boolLiteralPlanExpression, *timestampLiteralPlanExpression,
+		*stringLiteralPlanExpression:
+		return true
+	}
+	return false
+}
+
+// reorderJoins makes an inner join scan its bottom input in the outer loop if
+// the statistics estimate it produces fewer rows than the top input. The
+// inner input is scanned once for every row of the outer input.
+func reorderJoins(ctx context.Context, a *ExecutionPlanner, n types.PlanOperator, scope *OptimizerScope) (types.PlanOperator, bool, error) {
+	return TransformPlanOp(n, func(node types.PlanOperator) (types.PlanOperator, bool, error) {
+		switch thisNode := node.(type) {
+		case *PlanOpNestedLoops:
+			if thisNode.jType != joinTypeInner || thisNode.bottomOuter {
+				return thisNode, true, nil
+			}
+
+			topRows, ok, err := a.estimateRowCount(ctx, thisNode.top)
+			if err != nil || !ok {
+				return thisNode, true, err
+			}
+			bottomRows, ok, err := a.estimateRowCount(ctx, thisNode.bottom)
+			if err != nil || !ok {
+				return thisNode, true, err
+			}
+			if bottomRows >= topRows {
+				return thisNode, true, nil
+			}
+
+			newNode, err := thisNode.withBottomOuter()
+			if err != nil {
+				return thisNode, true, err
+			}
+			return newNode, false, nil
+
+		default:
+			return thisNode, true, nil
+		}
+	})
+}
+
 func pushdownPQLTop(ctx context.Context, a *ExecutionPlanner, n types.PlanOperator, scope *OptimizerScope) (types.PlanOperator, bool, error) {
 	// bail if there are any joins
 	joins, err := hasJoins(ctx, a, n, scope)
@@ -1070,6 +1329,12 @@ func fixProjectionReferences(ctx context.Context, a *ExecutionPlanner, n types.P
 							return e, true, nil
 
 						default:
+							// an expression in the group by list is a column of the child
+							for idx, sc := range childSchema {
+								if sc.RelationName == "" && strings.EqualFold(e.String(), sc.ColumnName) {
+									return newQualifiedRefPlanExpression("", "", idx, e.Type()), false, nil
+								}
+							}
 							return e, true, nil
 						}
 					}, func(parentExpr, childExpr types.PlanExpression) bool {
@@ -1386,7 +1651,7 @@ func fixFieldRefIndexesOnExpressionsForHaving(ctx context.Context, scope *Optimi
 func fixFieldRefIndexesForHaving(ctx context.Context, scope *OptimizerScope, a *ExecutionPlanner, schema types.Schema, exp types.PlanExpression) (types.PlanExpression, bool, error) {
 	return TransformExpr(exp, func(e types.PlanExpression) (types.PlanExpression, bool, error) {
 		switch typedExpr := e.(type) {
-		case *sumPlanExpression, *countPlanExpression, *countDistinctPlanExpression,
+		case *sumPlanExpression, *countPlanExpression, *countDistinctPlanExpression, *approxCountDistinctPlanExpression,
 			*avgPlanExpression, *minPlanExpression, *maxPlanExpression, *countStarPlanExpression,
 			*percentilePlanExpression:
 			for i, col := range schema {
@@ -1413,7 +1678,7 @@ func fixFieldRefIndexesForHaving(ctx context.Context, scope *OptimizerScope, a *
 		return e, true, nil
 	}, func(parentExpr, childExpr types.PlanExpression) bool {
 		switch parentExpr.(type) {
-		case *sumPlanExpression, *countPlanExpression, *countDistinctPlanExpression,
+		case *sumPlanExpression, *countPlanExpression, *countDistinctPlanExpression, *approxCountDistinctPlanExpression,
 			*avgPlanExpression, *minPlanExpression, *maxPlanExpression,
 			*percentilePlanExpression:
 			return false
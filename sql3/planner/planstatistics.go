@@ -0,0 +1,230 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+const (
+	// the selectivity used for a predicate we know nothing about
+	defaultSelectivity = 1.0 / 3.0
+
+	// the selectivity used for a LIKE, REGEXP or GLOB predicate
+	patternSelectivity = 0.1
+
+	// if the estimated number of groups is at least this fraction of the
+	// rows in a table, the groups are computed in memory rather than with a
+	// PQL GroupBy
+	inMemoryGroupByThreshold = 0.5
+)
+
+// tableStatistics holds the statistics collected by ANALYZE for a table.
+type tableStatistics struct {
+	rowCount int64
+	columns  map[string]*columnStatisticsSystemObject
+}
+
+// column returns the statistics for a column, or nil if there are none.
+func (s *tableStatistics) column(name string) *columnStatisticsSystemObject {
+	for k, v := range s.columns {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// getTableStatistics returns the statistics for a table, or nil if the table
+// has not been analyzed. Statistics are read once per table for the life of
+// the planner.
+func (p *ExecutionPlanner) getTableStatistics(ctx context.Context, tableName string) (*tableStatistics, error) {
+	if stats, ok := p.statistics[tableName]; ok {
+		return stats, nil
+	}
+
+	columns, err := p.getColumnStatistics(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats *tableStatistics
+	if len(columns) > 0 {
+		stats = &tableStatistics{
+			columns: columns,
+		}
+		for _, c := range columns {
+			stats.rowCount = c.rowCount
+			break
+		}
+	}
+
+	if p.statistics == nil {
+		p.statistics = make(map[string]*tableStatistics)
+	}
+	p.statistics[tableName] = stats
+	return stats, nil
+}
+
+// estimateSelectivity estimates the fraction of the rows in a table for which
+// a predicate is true.
+func estimateSelectivity(stats *tableStatistics, expr types.PlanExpression) float64 {
+	switch e := expr.(type) {
+	case *binOpPlanExpression:
+		switch e.op {
+		case parser.AND:
+			return estimateSelectivity(stats, e.lhs) * estimateSelectivity(stats, e.rhs)
+
+		case parser.OR:
+			l := estimateSelectivity(stats, e.lhs)
+			r := estimateSelectivity(stats, e.rhs)
+			return l + r - l*r
+
+		case parser.IS, parser.ISNOT:
+			col := columnStatisticsForExpr(stats, e.lhs)
+			if col == nil {
+				return defaultSelectivity
+			}
+			if e.op == parser.IS {
+				return col.nullFraction
+			}
+			return 1 - col.nullFraction
+
+		case parser.EQ, parser.NE:
+			col := columnStatisticsForExpr(stats, e.lhs)
+			if col == nil {
+				col = columnStatisticsForExpr(stats, e.rhs)
+			}
+			if col == nil || col.distinctCount == 0 {
+				return defaultSelectivity
+			}
+			eq := (1 - col.nullFraction) / float64(col.distinctCount)
+			if e.op == parser.EQ {
+				return eq
+			}
+			return 1 - col.nullFraction - eq
+
+		case parser.LT, parser.LE, parser.GT, parser.GE:
+			return estimateRangeSelectivity(stats, e)
+
+		case parser.LIKE, parser.NOTLIKE, parser.REGEXP, parser.NOTREGEXP, parser.GLOB, parser.NOTGLOB:
+			switch e.op {
+			case parser.NOTLIKE, parser.NOTREGEXP, parser.NOTGLOB:
+				return 1 - patternSelectivity
+			}
+			return patternSelectivity
+		}
+
+	case *unaryOpPlanExpression:
+		if e.op == parser.NOT {
+			return 1 - estimateSelectivity(stats, e.rhs)
+		}
+	}
+	return defaultSelectivity
+}
+
+// estimateRangeSelectivity estimates the selectivity of a comparison between
+// an int column and an int literal by interpolating between the min and max of
+// the column.
+func estimateRangeSelectivity(stats *tableStatistics, e *binOpPlanExpression) float64 {
+	col := columnStatisticsForExpr(stats, e.lhs)
+	lit, ok := e.rhs.(*intLiteralPlanExpression)
+	if col == nil || !ok || !col.hasRange {
+		return defaultSelectivity
+	}
+	min, err := strconv.ParseInt(col.minValue, 10, 64)
+	if err != nil {
+		return defaultSelectivity
+	}
+	max, err := strconv.ParseInt(col.maxValue, 10, 64)
+	if err != nil {
+		return defaultSelectivity
+	}
+
+	// the fraction of the non-null values below the literal
+	var below float64
+	switch {
+	case lit.value <= min:
+		below = 0
+	case lit.value > max:
+		below = 1
+	default:
+		below = float64(lit.value-min) / float64(max-min+1)
+	}
+
+	nonNull := 1 - col.nullFraction
+	switch e.op {
+	case parser.LT, parser.LE:
+		return nonNull * below
+	default:
+		return nonNull * (1 - below)
+	}
+}
+
+// columnStatisticsForExpr returns the statistics for the column referenced by
+// an expression, or nil if the expression is not a column reference.
+func columnStatisticsForExpr(stats *tableStatistics, expr types.PlanExpression) *columnStatisticsSystemObject {
+	ref, ok := expr.(*qualifiedRefPlanExpression)
+	if !ok || stats == nil {
+		return nil
+	}
+	return stats.column(ref.columnName)
+}
+
+// estimateRowCount estimates the number of rows an operator will produce. It
+// returns false if there are no statistics for the operator.
+func (p *ExecutionPlanner) estimateRowCount(ctx context.Context, op types.PlanOperator) (float64, bool, error) {
+	switch thisOp := op.(type) {
+	case *PlanOpRelAlias:
+		return p.estimateRowCount(ctx, thisOp.ChildOp)
+
+	case *PlanOpFilter:
+		rows, ok, err := p.estimateRowCount(ctx, thisOp.ChildOp)
+		if err != nil || !ok {
+			return 0, false, err
+		}
+		stats, err := p.getSingleTableStatistics(ctx, thisOp.ChildOp)
+		if err != nil {
+			return 0, false, err
+		}
+		return rows * estimateSelectivity(stats, thisOp.Predicate), true, nil
+
+	case *PlanOpPQLTableScan:
+		stats, err := p.getTableStatistics(ctx, thisOp.tableName)
+		if err != nil || stats == nil {
+			return 0, false, err
+		}
+		rows := float64(stats.rowCount)
+		if thisOp.sample != nil {
+			if thisOp.sample.rows > 0 {
+				rows = math.Min(rows, float64(thisOp.sample.rows))
+			} else {
+				rows *= thisOp.sample.percent / 100
+			}
+		}
+		if thisOp.filter != nil {
+			rows *= estimateSelectivity(stats, thisOp.filter)
+		}
+		return rows, true, nil
+
+	default:
+		return 0, false, nil
+	}
+}
+
+// getSingleTableStatistics returns the statistics for the table scanned by an
+// operator, or nil if it scans more or less than one table or the table has
+// not been analyzed.
+func (p *ExecutionPlanner) getSingleTableStatistics(ctx context.Context, op types.PlanOperator) (*tableStatistics, error) {
+	tables := getTableScanOperators(ctx, p, op, nil)
+	if len(tables) != 1 {
+		return nil, nil
+	}
+	return p.getTableStatistics(ctx, tables[0].tableName)
+}
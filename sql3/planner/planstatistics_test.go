@@ -0,0 +1,151 @@
+// Copyright 2023 Molecula Corp. All rights reserved.
+
+package planner
+
+import (
+	"math"
+	"testing"
+
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+)
+
+func Test_estimateSelectivity(t *testing.T) {
+	stats := &tableStatistics{
+		rowCount: 100,
+		columns: map[string]*columnStatisticsSystemObject{
+			"i": {columnName: "i", rowCount: 100, distinctCount: 10, nullFraction: 0.5, hasRange: true, minValue: "0", maxValue: "99"},
+			"s": {columnName: "s", rowCount: 100, distinctCount: 4},
+		},
+	}
+	ref := func(name string) types.PlanExpression {
+		return newQualifiedRefPlanExpression("t", name, 0, parser.NewDataTypeInt())
+	}
+	binOp := func(lhs types.PlanExpression, op parser.Token, rhs types.PlanExpression) types.PlanExpression {
+		return newBinOpPlanExpression(lhs, op, rhs, parser.NewDataTypeBool())
+	}
+
+	tests := []struct {
+		name string
+		expr types.PlanExpression
+		want float64
+	}{
+		{
+			name: "eq",
+			expr: binOp(ref("s"), parser.EQ, newStringLiteralPlanExpression("a")),
+			want: 0.25,
+		}, {
+			name: "eq-nulls",
+			expr: binOp(ref("i"), parser.EQ, newIntLiteralPlanExpression(1)),
+			want: 0.05,
+		}, {
+			name: "ne",
+			expr: binOp(ref("s"), parser.NE, newStringLiteralPlanExpression("a")),
+			want: 0.75,
+		}, {
+			name: "is-null",
+			expr: binOp(ref("i"), parser.IS, newNullLiteralPlanExpression()),
+			want: 0.5,
+		}, {
+			name: "lt",
+			expr: binOp(ref("i"), parser.LT, newIntLiteralPlanExpression(25)),
+			want: 0.125,
+		}, {
+			name: "gt-max",
+			expr: binOp(ref("i"), parser.GT, newIntLiteralPlanExpression(200)),
+			want: 0,
+		}, {
+			name: "and",
+			expr: binOp(
+				binOp(ref("s"), parser.EQ, newStringLiteralPlanExpression("a")),
+				parser.AND,
+				binOp(ref("i"), parser.IS, newNullLiteralPlanExpression()),
+			),
+			want: 0.125,
+		}, {
+			name: "or",
+			expr: binOp(
+				binOp(ref("s"), parser.EQ, newStringLiteralPlanExpression("a")),
+				parser.OR,
+				binOp(ref("i"), parser.IS, newNullLiteralPlanExpression()),
+			),
+			want: 0.625,
+		}, {
+			name: "unknown-column",
+			expr: binOp(ref("x"), parser.EQ, newIntLiteralPlanExpression(1)),
+			want: defaultSelectivity,
+		}, {
+			name: "like",
+			expr: binOp(ref("s"), parser.LIKE, newStringLiteralPlanExpression("a%")),
+			want: patternSelectivity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateSelectivity(stats, tt.expr); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("estimateSelectivity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_orderPredicates(t *testing.T) {
+	stats := &tableStatistics{
+		rowCount: 100,
+		columns: map[string]*columnStatisticsSystemObject{
+			"a": {columnName: "a", rowCount: 100, distinctCount: 100},
+			"b": {columnName: "b", rowCount: 100, distinctCount: 4},
+		},
+	}
+	ref := func(name string) types.PlanExpression {
+		return newQualifiedRefPlanExpression("t", name, 0, parser.NewDataTypeInt())
+	}
+	binOp := func(lhs types.PlanExpression, op parser.Token, rhs types.PlanExpression) types.PlanExpression {
+		return newBinOpPlanExpression(lhs, op, rhs, parser.NewDataTypeInt())
+	}
+	bNotZero := binOp(ref("b"), parser.NE, newIntLiteralPlanExpression(0))
+	aEqOne := binOp(ref("a"), parser.EQ, newIntLiteralPlanExpression(1))
+	// a/b > 1 is more selective than b != 0, but fails when b is 0
+	aDivB := binOp(binOp(ref("a"), parser.SLASH, ref("b")), parser.GT, newIntLiteralPlanExpression(1))
+
+	tests := []struct {
+		name       string
+		predicates []types.PlanExpression
+		want       []types.PlanExpression
+	}{
+		{
+			name:       "comparisons",
+			predicates: []types.PlanExpression{bNotZero, aEqOne},
+			want:       []types.PlanExpression{aEqOne, bNotZero},
+		}, {
+			name:       "guard",
+			predicates: []types.PlanExpression{bNotZero, aDivB},
+		}, {
+			name:       "guard-after-run",
+			predicates: []types.PlanExpression{bNotZero, aEqOne, aDivB},
+			want:       []types.PlanExpression{aEqOne, bNotZero, aDivB},
+		}, {
+			name:       "not-moved-past",
+			predicates: []types.PlanExpression{aDivB, bNotZero},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := orderPredicates(stats, tt.predicates)
+			if tt.want == nil {
+				if changed {
+					t.Fatalf("expected order to be unchanged, got %v", got)
+				}
+				return
+			}
+			if !changed {
+				t.Fatal("expected order to change")
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("predicate %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -5,18 +5,43 @@ package planner
 import (
 	"context"
 	"encoding/json"
+	"math"
+	"strconv"
 	"time"
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/pql"
 	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/parser"
 	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
 )
 
 type viewSystemObject struct {
-	name      string
-	statement string
+	name         string
+	statement    string
+	materialized bool
+
+	// for a materialized view, the table holding its result and when it
+	// was last refreshed
+	tableName   string
+	refreshedAt time.Time
+}
+
+// table returns the name of the table holding the result of a materialized
+// view. Views materialized before the result was written to a table of its
+// own each refresh have a table named for the view.
+func (v *viewSystemObject) table() string {
+	if v.tableName != "" {
+		return v.tableName
+	}
+	return v.name
+}
+
+// hasTable returns true if a table has been populated with the result of a
+// materialized view.
+func (v *viewSystemObject) hasTable() bool {
+	return v.tableName != "" || !v.refreshedAt.IsZero()
 }
 
 type functionSystemObject struct {
@@ -34,7 +59,7 @@ type modelSystemObject struct {
 }
 
 func (p *ExecutionPlanner) ensureViewsSystemTableExists(ctx context.Context) error {
-	_, err := p.schemaAPI.TableByName(ctx, "fb_views")
+	tbl, err := p.schemaAPI.TableByName(ctx, "fb_views")
 	if err != nil {
 		if !isTableNotFoundError(err) {
 			return err
@@ -48,6 +73,9 @@ func (p *ExecutionPlanner) ensureViewsSystemTableExists(ctx context.Context) err
 		//		updated_by string
 		//		created_at timestamp
 		//		updated_at timestamp
+		//		is_materialized bool
+		//		refreshed_at timestamp
+		//		table_name string
 		//  );
 
 		// if it doesn't, create it by making the appropriate iterator
@@ -110,6 +138,31 @@ func (p *ExecutionPlanner) ensureViewsSystemTableExists(ctx context.Context) err
 						pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds),
 					},
 				},
+				{
+					planner:  p,
+					name:     "is_materialized",
+					typeName: dax.BaseTypeBool,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeBool(),
+					},
+				},
+				{
+					planner:  p,
+					name:     "refreshed_at",
+					typeName: dax.BaseTypeTimestamp,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds),
+					},
+				},
+				{
+					planner:  p,
+					name:     "table_name",
+					typeName: dax.BaseTypeString,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize),
+						pilosa.OptFieldKeys(),
+					},
+				},
 			},
 			description: "system table for views",
 		}
@@ -118,11 +171,60 @@ func (p *ExecutionPlanner) ensureViewsSystemTableExists(ctx context.Context) err
 		if err != nil && err != types.ErrNoMoreRows {
 			return err
 		}
+		return nil
+	}
+
+	// fb_views tables created before materialized views existed won't have
+	// the materialized columns, so add them if they are missing
+	if _, ok := tbl.Field("is_materialized"); !ok {
+		if err := p.createSystemTableField(ctx, "fb_views", "is_materialized", pilosa.OptFieldTypeBool()); err != nil {
+			return err
+		}
+	}
+	if _, ok := tbl.Field("refreshed_at"); !ok {
+		if err := p.createSystemTableField(ctx, "fb_views", "refreshed_at", pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds)); err != nil {
+			return err
+		}
+	}
+	if _, ok := tbl.Field("table_name"); !ok {
+		if err := p.createSystemTableField(ctx, "fb_views", "table_name", pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize), pilosa.OptFieldKeys()); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// createSystemTableField adds a column to an existing system table.
+func (p *ExecutionPlanner) createSystemTableField(ctx context.Context, tableName string, fieldName string, fos ...pilosa.FieldOption) error {
+	fld, err := pilosa.FieldFromFieldOptions(dax.FieldName(fieldName), fos...)
+	if err != nil {
+		return err
+	}
+	fld.Options.TrackExistence = true
+	return p.schemaAPI.CreateField(ctx, dax.TableName(tableName), fld)
+}
+
 func (p *ExecutionPlanner) getViewByName(ctx context.Context, name string) (*viewSystemObject, error) {
+	return p.getView(ctx, string(dax.PrimaryKeyFieldName), name)
+}
+
+// materializedViewForTable returns the materialized view named tableName, or
+// whose result is held in the table tableName. It returns nil if there is no
+// such view.
+func (p *ExecutionPlanner) materializedViewForTable(ctx context.Context, tableName string) (*viewSystemObject, error) {
+	v, err := p.getViewByName(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if v != nil && v.materialized {
+		return v, nil
+	}
+	return p.getView(ctx, "table_name", tableName)
+}
+
+// getView returns the view whose value in column is value, or nil if there
+// isn't one.
+func (p *ExecutionPlanner) getView(ctx context.Context, column string, value string) (*viewSystemObject, error) {
 	err := p.ensureViewsSystemTableExists(ctx)
 	if err != nil {
 		return nil, err
@@ -143,9 +245,9 @@ func (p *ExecutionPlanner) getViewByName(ctx context.Context, name string) (*vie
 		tableName: "fb_views",
 		columns:   cols,
 		predicate: newBinOpPlanExpression(
-			newQualifiedRefPlanExpression("fb_views", string(dax.PrimaryKeyFieldName), 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_views", column, 0, parser.NewDataTypeString()),
 			parser.EQ,
-			newStringLiteralPlanExpression(name),
+			newStringLiteralPlanExpression(value),
 			parser.NewDataTypeBool(),
 		),
 		topExpr: nil,
@@ -160,10 +262,30 @@ func (p *ExecutionPlanner) getViewByName(ctx context.Context, name string) (*vie
 		return nil, err
 	}
 
-	return &viewSystemObject{
-		name:      row[1].(string),
-		statement: row[2].(string),
-	}, nil
+	view := &viewSystemObject{}
+	for i, c := range cols {
+		switch c {
+		case "name":
+			view.name = row[i].(string)
+		case "statement":
+			view.statement = row[i].(string)
+		case "is_materialized":
+			// views created before materialized views existed will have a
+			// null here
+			if b, ok := row[i].(bool); ok {
+				view.materialized = b
+			}
+		case "table_name":
+			if s, ok := row[i].(string); ok {
+				view.tableName = s
+			}
+		case "refreshed_at":
+			if t, ok := row[i].(time.Time); ok {
+				view.refreshedAt = t
+			}
+		}
+	}
+	return view, nil
 }
 
 func (p *ExecutionPlanner) insertView(ctx context.Context, view *viewSystemObject) error {
@@ -185,6 +307,7 @@ func (p *ExecutionPlanner) insertView(ctx context.Context, view *viewSystemObjec
 			newQualifiedRefPlanExpression("fb_views", "updated_by", 0, parser.NewDataTypeString()),
 			newQualifiedRefPlanExpression("fb_views", "created_at", 0, parser.NewDataTypeTimestamp()),
 			newQualifiedRefPlanExpression("fb_views", "updated_at", 0, parser.NewDataTypeTimestamp()),
+			newQualifiedRefPlanExpression("fb_views", "is_materialized", 0, parser.NewDataTypeBool()),
 		},
 		insertValues: [][]types.PlanExpression{
 			{
@@ -195,6 +318,7 @@ func (p *ExecutionPlanner) insertView(ctx context.Context, view *viewSystemObjec
 				newStringLiteralPlanExpression(""),
 				newTimestampLiteralPlanExpression(createTime),
 				newTimestampLiteralPlanExpression(createTime),
+				newBoolLiteralPlanExpression(view.materialized),
 			},
 		},
 	}
@@ -238,6 +362,38 @@ func (p *ExecutionPlanner) updateView(ctx context.Context, view *viewSystemObjec
 	return nil
 }
 
+// updateViewRefresh records the table holding the result of a materialized
+// view and when it was refreshed. Both are written in a single insert, so
+// readers of the view switch to a new table at once.
+func (p *ExecutionPlanner) updateViewRefresh(ctx context.Context, viewName string, tableName string, refreshTime time.Time) error {
+	err := p.ensureViewsSystemTableExists(ctx)
+	if err != nil {
+		return err
+	}
+
+	iter := &insertRowIter{
+		planner:   p,
+		tableName: "fb_views",
+		targetColumns: []*qualifiedRefPlanExpression{
+			newQualifiedRefPlanExpression("fb_views", string(dax.PrimaryKeyFieldName), 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_views", "table_name", 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_views", "refreshed_at", 0, parser.NewDataTypeTimestamp()),
+		},
+		insertValues: [][]types.PlanExpression{
+			{
+				newStringLiteralPlanExpression(viewName),
+				newStringLiteralPlanExpression(tableName),
+				newTimestampLiteralPlanExpression(refreshTime.UTC()),
+			},
+		},
+	}
+	_, err = iter.Next(ctx)
+	if err != nil && err != types.ErrNoMoreRows {
+		return err
+	}
+	return nil
+}
+
 func (p *ExecutionPlanner) deleteView(ctx context.Context, viewName string) error {
 	err := p.ensureViewsSystemTableExists(ctx)
 	if err != nil {
@@ -878,3 +1034,288 @@ func (p *ExecutionPlanner) deleteModel(modelName string) error {
 
 	return nil
 }
+
+// columnStatisticsSystemObject holds the statistics collected by ANALYZE for a
+// single column of a table.
+type columnStatisticsSystemObject struct {
+	tableName     string
+	columnName    string
+	rowCount      int64
+	distinctCount int64
+	nullFraction  float64
+
+	// hasRange is true for BSI (int, decimal and timestamp) columns with
+	// values, for which minValue and maxValue are set. These are stored as
+	// strings so that a single system table column can hold the values of
+	// all three types.
+	hasRange bool
+	minValue string
+	maxValue string
+}
+
+// columnStatisticsID returns the _id of the fb_column_stats row for a column.
+func columnStatisticsID(tableName, columnName string) string {
+	return tableName + "." + columnName
+}
+
+func (p *ExecutionPlanner) ensureColumnStatsSystemTableExists(ctx context.Context) error {
+	_, err := p.schemaAPI.TableByName(ctx, "fb_column_stats")
+	if err != nil {
+		if !isTableNotFoundError(err) {
+			return err
+		}
+
+		//  create table fb_column_stats (
+		// 		_id string
+		//		table_name string
+		//		column_name string
+		//		row_count int
+		//		distinct_count int
+		//		null_fraction decimal(4)
+		//		min_value string
+		//		max_value string
+		//		analyzed_at timestamp
+		//  );
+
+		// if it doesn't, create it by making the appropriate iterator
+		iter := &createTableRowIter{
+			planner:       p,
+			tableName:     "fb_column_stats",
+			failIfExists:  false,
+			isKeyed:       true,
+			keyPartitions: 0,
+			columns: []*createTableField{
+				{
+					planner:  p,
+					name:     "table_name",
+					typeName: dax.BaseTypeString,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize),
+						pilosa.OptFieldKeys(),
+					},
+				},
+				{
+					planner:  p,
+					name:     "column_name",
+					typeName: dax.BaseTypeString,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize),
+						pilosa.OptFieldKeys(),
+					},
+				},
+				{
+					planner:  p,
+					name:     "row_count",
+					typeName: dax.BaseTypeInt,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeInt(0, math.MaxInt64),
+					},
+				},
+				{
+					planner:  p,
+					name:     "distinct_count",
+					typeName: dax.BaseTypeInt,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeInt(0, math.MaxInt64),
+					},
+				},
+				{
+					planner:  p,
+					name:     "null_fraction",
+					typeName: dax.BaseTypeDecimal,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeDecimal(4),
+					},
+				},
+				{
+					planner:  p,
+					name:     "min_value",
+					typeName: dax.BaseTypeString,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize),
+						pilosa.OptFieldKeys(),
+					},
+				},
+				{
+					planner:  p,
+					name:     "max_value",
+					typeName: dax.BaseTypeString,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeMutex(pilosa.DefaultCacheType, pilosa.DefaultCacheSize),
+						pilosa.OptFieldKeys(),
+					},
+				},
+				{
+					planner:  p,
+					name:     "analyzed_at",
+					typeName: dax.BaseTypeTimestamp,
+					fos: []pilosa.FieldOption{
+						pilosa.OptFieldTypeTimestamp(pilosa.DefaultEpoch, pilosa.TimeUnitSeconds),
+					},
+				},
+			},
+			description: "system table for column statistics",
+		}
+		// call next on our iterator to create the table
+		_, err := iter.Next(ctx)
+		if err != nil && err != types.ErrNoMoreRows {
+			return err
+		}
+	}
+	return nil
+}
+
+// getColumnStatistics returns the statistics for the columns of a table,
+// keyed by column name. Unlike the other system objects, reading statistics
+// doesn't create the system table, since it happens while optimizing every
+// query; no statistics are returned if ANALYZE has never been run.
+func (p *ExecutionPlanner) getColumnStatistics(ctx context.Context, tableName string) (map[string]*columnStatisticsSystemObject, error) {
+	tbl, err := p.schemaAPI.TableByName(ctx, "fb_column_stats")
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	cols := make([]string, len(tbl.Fields))
+	for i, c := range tbl.Fields {
+		cols[i] = string(c.Name)
+	}
+
+	iter := &tableScanRowIter{
+		planner:   p,
+		tableName: "fb_column_stats",
+		columns:   cols,
+		predicate: newBinOpPlanExpression(
+			newQualifiedRefPlanExpression("fb_column_stats", "table_name", 0, parser.NewDataTypeString()),
+			parser.EQ,
+			newStringLiteralPlanExpression(tableName),
+			parser.NewDataTypeBool(),
+		),
+		topExpr: nil,
+	}
+
+	result := make(map[string]*columnStatisticsSystemObject)
+	for {
+		row, err := iter.Next(ctx)
+		if err != nil {
+			if err == types.ErrNoMoreRows {
+				break
+			}
+			return nil, err
+		}
+
+		stats := &columnStatisticsSystemObject{}
+		for i, c := range cols {
+			switch c {
+			case "table_name":
+				stats.tableName, _ = row[i].(string)
+			case "column_name":
+				stats.columnName, _ = row[i].(string)
+			case "row_count":
+				stats.rowCount, _ = row[i].(int64)
+			case "distinct_count":
+				stats.distinctCount, _ = row[i].(int64)
+			case "null_fraction":
+				if d, ok := row[i].(pql.Decimal); ok {
+					stats.nullFraction = d.Float64()
+				}
+			case "min_value":
+				stats.minValue, stats.hasRange = row[i].(string)
+			case "max_value":
+				stats.maxValue, _ = row[i].(string)
+			}
+		}
+		result[stats.columnName] = stats
+	}
+	return result, nil
+}
+
+// replaceColumnStatistics replaces all the statistics for a table with the
+// statistics given.
+func (p *ExecutionPlanner) replaceColumnStatistics(ctx context.Context, tableName string, stats []*columnStatisticsSystemObject) error {
+	err := p.ensureColumnStatsSystemTableExists(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = p.deleteColumnStatistics(ctx, tableName)
+	if err != nil {
+		return err
+	}
+	if len(stats) == 0 {
+		return nil
+	}
+
+	analyzeTime := time.Now().UTC()
+
+	insertValues := make([][]types.PlanExpression, len(stats))
+	for i, s := range stats {
+		var minValue, maxValue types.PlanExpression = newNullLiteralPlanExpression(), newNullLiteralPlanExpression()
+		if s.hasRange {
+			minValue = newStringLiteralPlanExpression(s.minValue)
+			maxValue = newStringLiteralPlanExpression(s.maxValue)
+		}
+		insertValues[i] = []types.PlanExpression{
+			newStringLiteralPlanExpression(columnStatisticsID(s.tableName, s.columnName)),
+			newStringLiteralPlanExpression(s.tableName),
+			newStringLiteralPlanExpression(s.columnName),
+			newIntLiteralPlanExpression(s.rowCount),
+			newIntLiteralPlanExpression(s.distinctCount),
+			newFloatLiteralPlanExpression(strconv.FormatFloat(s.nullFraction, 'f', 4, 64)),
+			minValue,
+			maxValue,
+			newTimestampLiteralPlanExpression(analyzeTime),
+		}
+	}
+
+	iter := &insertRowIter{
+		planner:   p,
+		tableName: "fb_column_stats",
+		targetColumns: []*qualifiedRefPlanExpression{
+			newQualifiedRefPlanExpression("fb_column_stats", string(dax.PrimaryKeyFieldName), 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_column_stats", "table_name", 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_column_stats", "column_name", 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_column_stats", "row_count", 0, parser.NewDataTypeInt()),
+			newQualifiedRefPlanExpression("fb_column_stats", "distinct_count", 0, parser.NewDataTypeInt()),
+			newQualifiedRefPlanExpression("fb_column_stats", "null_fraction", 0, parser.NewDataTypeDecimal(4)),
+			newQualifiedRefPlanExpression("fb_column_stats", "min_value", 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_column_stats", "max_value", 0, parser.NewDataTypeString()),
+			newQualifiedRefPlanExpression("fb_column_stats", "analyzed_at", 0, parser.NewDataTypeTimestamp()),
+		},
+		insertValues: insertValues,
+	}
+	_, err = iter.Next(ctx)
+	if err != nil && err != types.ErrNoMoreRows {
+		return err
+	}
+	return nil
+}
+
+// deleteColumnStatistics deletes the statistics for a table, if there are any.
+func (p *ExecutionPlanner) deleteColumnStatistics(ctx context.Context, tableName string) error {
+	_, err := p.schemaAPI.TableByName(ctx, "fb_column_stats")
+	if err != nil {
+		if isTableNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	iter := &filteredDeleteRowIter{
+		planner:   p,
+		tableName: "fb_column_stats",
+		filter: newBinOpPlanExpression(
+			newQualifiedRefPlanExpression("fb_column_stats", "table_name", 0, parser.NewDataTypeString()),
+			parser.EQ,
+			newStringLiteralPlanExpression(tableName),
+			parser.NewDataTypeBool(),
+		),
+	}
+	_, err = iter.Next(ctx)
+	if err != nil && err != types.ErrNoMoreRows {
+		return err
+	}
+	return nil
+}
@@ -1,71 +1,135 @@
 package planner
 
 import (
+	"context"
+	"strings"
+
 	"github.com/featurebasedb/featurebase/v3/sql3"
 	"github.com/featurebasedb/featurebase/v3/sql3/parser"
 )
 
-func (p *ExecutionPlanner) analyzeUserDefinedFunction(call *parser.Call, scope parser.Statement, function *functionSystemObject) (parser.Expr, error) {
-	// TODO(pok) removing user defined functions for now
-	// // hard code to 1 string parameter
-	// if len(call.Args) != 1 {
-	// 	return nil, sql3.NewErrCallParameterCountMismatch(call.Rparen.Line, call.Rparen.Column, call.Name.Name, 2, len(call.Args))
-	// }
-
-	// // arg 1
-	// argType := parser.NewDataTypeString()
-	// if !typesAreAssignmentCompatible(argType, call.Args[0].DataType()) {
-	// 	return nil, sql3.NewErrParameterTypeMistmatch(call.Args[0].Pos().Line, call.Args[0].Pos().Column, call.Args[0].DataType().TypeDescription(), argType.TypeDescription())
-	// }
-
-	// //return string
-	// call.ResultDataType = parser.NewDataTypeString()
+// analyzeUserDefinedFunction inlines a call to a user defined function. The
+// RETURN expression of the function, with each parameter replaced by the
+// matching argument of the call, is analyzed in the scope of the call and
+// takes the place of the call in the plan. Nothing other than sql3
+// expressions is ever evaluated for a user defined function.
+func (p *ExecutionPlanner) analyzeUserDefinedFunction(ctx context.Context, call *parser.Call, scope parser.Statement, function *functionSystemObject) (parser.Expr, error) {
+	if function.language != "sql" {
+		return nil, sql3.NewErrUnsupported(call.Name.NamePos.Line, call.Name.NamePos.Column, true, "language '"+function.language+"'")
+	}
 
-	// return call, nil
-	return nil, sql3.NewErrUnsupported(0, 0, false, "user defined functions")
-}
+	for _, name := range p.inlinedFunctions {
+		if name == function.name {
+			return nil, sql3.NewErrFunctionRecursiveCall(call.Name.NamePos.Line, call.Name.NamePos.Column, call.Name.Name)
+		}
+	}
 
-func (n *callPlanExpression) evaluateUserDefinedFunction(currentRow []interface{}) (interface{}, error) {
-	// TODO(pok) removing what effectively is a remote code exploit
-	// we will come back to this to add sql udfs and external code later
+	def, err := parseFunctionDefinition(function)
+	if err != nil {
+		return nil, err
+	}
+	ret, err := functionReturnStatement(def)
+	if err != nil {
+		return nil, err
+	}
 
-	// argEval, err := n.args[0].Evaluate(currentRow)
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// // nil if anything is nil
-	// if argEval == nil {
-	// 	return nil, nil
-	// }
+	if len(call.Args) != len(def.Parameters) {
+		return nil, sql3.NewErrCallParameterCountMismatch(call.Rparen.Line, call.Rparen.Column, call.Name.Name, len(def.Parameters), len(call.Args))
+	}
 
-	// //get the value
-	// coercedArg, err := coerceValue(n.args[0].Type(), parser.NewDataTypeString(), argEval, parser.Pos{Line: 0, Column: 0})
-	// if err != nil {
-	// 	return nil, err
-	// }
+	// the arguments have already been analyzed; any whose type differs from
+	// that of its parameter is cast, so the body sees the types it was
+	// checked with when the function was created
+	args := make(map[string]parser.Expr, len(call.Args))
+	for i, param := range def.Parameters {
+		paramType, err := dataTypeFromParserType(param.Type)
+		if err != nil {
+			return nil, err
+		}
+		arg := call.Args[i]
+		if !typesAreAssignmentCompatible(paramType, arg.DataType()) {
+			return nil, sql3.NewErrParameterTypeMistmatch(arg.Pos().Line, arg.Pos().Column, arg.DataType().TypeDescription(), paramType.TypeDescription())
+		}
+		if !typesMatch(paramType, arg.DataType()) {
+			arg = &parser.CastExpr{
+				Cast:   arg.Pos(),
+				X:      arg,
+				Type:   param.Type,
+				Rparen: arg.Pos(),
+			}
+		}
+		args[strings.ToLower(param.Name.Name)] = &parser.ParenExpr{
+			Lparen: arg.Pos(),
+			X:      arg,
+			Rparen: arg.Pos(),
+		}
+	}
 
-	// arg, argOk := coercedArg.(string)
-	// if !argOk {
-	// 	return nil, sql3.NewErrInternalf("unable to convert value")
-	// }
+	// substitute the arguments for the parameters on the way out of each node,
+	// so the arguments themselves are not walked
+	body, err := parser.Walk(parser.VisitEndFunc(func(node parser.Node) (parser.Node, error) {
+		if v, ok := node.(*parser.Variable); ok {
+			arg, ok := args[strings.ToLower(v.Name)]
+			if !ok {
+				return nil, sql3.NewErrUnknownIdentifier(v.NamePos.Line, v.NamePos.Column, v.Name)
+			}
+			return arg, nil
+		}
+		return node, nil
+	}), parser.CloneExpr(ret.ReturnExpr))
+	if err != nil {
+		return nil, err
+	}
 
-	// // save the body to a temp file
-	// file, err := os.CreateTemp("", "py-body")
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// defer os.Remove(file.Name())
+	p.inlinedFunctions = append(p.inlinedFunctions, function.name)
+	expr, err := p.analyzeExpression(ctx, body.(parser.Expr), scope)
+	p.inlinedFunctions = p.inlinedFunctions[:len(p.inlinedFunctions)-1]
+	if err != nil {
+		return nil, err
+	}
 
-	// file.Write([]byte(n.udfReference.body))
+	returnType, err := dataTypeFromParserType(def.ReturnType)
+	if err != nil {
+		return nil, err
+	}
+	if !typesAreAssignmentCompatible(returnType, expr.DataType()) {
+		return nil, sql3.NewErrFunctionReturnType(call.Name.NamePos.Line, call.Name.NamePos.Column, call.Name.Name, expr.DataType().TypeDescription(), returnType.TypeDescription())
+	}
+	if typesMatch(returnType, expr.DataType()) {
+		return expr, nil
+	}
 
-	// cmd := exec.Command("python3", file.Name(), arg)
-	// stdout, err := cmd.Output()
+	// expr has already been analyzed, so the cast to the return type is built
+	// already analyzed too
+	return &parser.CastExpr{
+		Cast:           call.Name.NamePos,
+		Lparen:         call.Lparen,
+		X:              expr,
+		Type:           def.ReturnType,
+		Rparen:         call.Rparen,
+		ResultDataType: returnType,
+	}, nil
+}
 
-	// if err != nil {
-	// 	return nil, err
-	// }
+// parseFunctionDefinition parses the CREATE FUNCTION statement stored as the
+// body of a user defined function.
+func parseFunctionDefinition(function *functionSystemObject) (*parser.CreateFunctionStatement, error) {
+	stmt, err := parser.NewParser(strings.NewReader(function.body)).ParseStatement()
+	if err != nil {
+		return nil, sql3.NewErrInternalf("parsing definition of function '%s': %v", function.name, err)
+	}
+	def, ok := stmt.(*parser.CreateFunctionStatement)
+	if !ok {
+		return nil, sql3.NewErrInternalf("unexpected definition type '%T' for function '%s'", stmt, function.name)
+	}
+	return def, nil
+}
 
-	// retVal := string(stdout)
-	// return retVal, nil
-	return nil, sql3.NewErrUnsupported(0, 0, false, "user defined functions")
+// typesMatch returns true if a value of sourceType needs no conversion to be a
+// value of targetType. Null values match any type.
+func typesMatch(targetType parser.ExprDataType, sourceType parser.ExprDataType) bool {
+	if _, ok := sourceType.(*parser.DataTypeVoid); ok {
+		return true
+	}
+	return strings.EqualFold(targetType.TypeDescription(), sourceType.TypeDescription())
 }
@@ -3,10 +3,13 @@ package sql3_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
@@ -19,8 +22,12 @@ import (
 	"github.com/apache/arrow/go/v10/parquet"
 	"github.com/apache/arrow/go/v10/parquet/pqarrow"
 	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/authn"
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
 	"github.com/featurebasedb/featurebase/v3/dax"
 	"github.com/featurebasedb/featurebase/v3/pql"
+	"github.com/featurebasedb/featurebase/v3/server"
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
 	sql_test "github.com/featurebasedb/featurebase/v3/sql3/test"
 	"github.com/featurebasedb/featurebase/v3/test"
 	"github.com/featurebasedb/featurebase/v3/vprint"
@@ -937,7 +944,6 @@ func TestPlanner_AlterTable(t *testing.T) {
 		}
 	})
 	t.Run("AlterTableRename", func(t *testing.T) {
-		t.Skip("not yet implemented")
 		results, columns, _, err := sql_test.MustQueryRows(t, nil, server, fmt.Sprintf(`alter table %i rename column f to g`, c))
 		if err != nil {
 			t.Fatal(err)
@@ -952,6 +958,163 @@ func TestPlanner_AlterTable(t *testing.T) {
 	})
 }
 
+func TestPlanner_Transaction(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	server := c.GetNode(0).Server
+	session := fbcontext.WithSessionID(context.Background(), "session-1")
+
+	mustQuery := func(t *testing.T, sql string) [][]interface{} {
+		t.Helper()
+		results, _, _, err := sql_test.MustQueryRows(t, session, server, sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+	mustError := func(t *testing.T, sql string, exp string) {
+		t.Helper()
+		_, _, _, err := sql_test.MustQueryRows(t, session, server, sql)
+		if err == nil || !strings.Contains(err.Error(), exp) {
+			t.Fatalf("expected error containing '%s', got %v", exp, err)
+		}
+	}
+	count := func(t *testing.T, ctx context.Context) int64 {
+		t.Helper()
+		results, _, _, err := sql_test.MustQueryRows(t, ctx, server, `select count(*) from txn_test`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results[0][0].(int64)
+	}
+
+	mustQuery(t, `create table txn_test (_id id, a int)`)
+
+	t.Run("NoSession", func(t *testing.T) {
+		_, _, _, err := sql_test.MustQueryRows(t, nil, server, `begin`)
+		if err == nil || !strings.Contains(err.Error(), "transactions require a session") {
+			t.Fatalf("expected 'transactions require a session', got %v", err)
+		}
+	})
+
+	t.Run("NoTransaction", func(t *testing.T) {
+		mustError(t, `commit`, "there is no transaction in progress")
+		mustError(t, `rollback`, "there is no transaction in progress")
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		mustQuery(t, `begin`)
+		mustError(t, `begin transaction`, "there is already a transaction in progress")
+		mustQuery(t, `insert into txn_test (_id, a) values (1, 10), (2, 20), (3, 30)`)
+		mustQuery(t, `delete from txn_test where _id = 2`)
+
+		// the transaction sees its own writes, but nothing is visible
+		// outside it until it commits
+		if n := count(t, session); n != 2 {
+			t.Fatalf("expected 2 rows in the transaction, got %d", n)
+		} else if n := count(t, nil); n != 0 {
+			t.Fatalf("expected 0 rows before commit, got %d", n)
+		}
+		mustQuery(t, `commit`)
+
+		results := mustQuery(t, `select _id, a from txn_test`)
+		if diff := cmp.Diff([][]interface{}{
+			{int64(1), int64(10)},
+			{int64(3), int64(30)},
+		}, results); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		mustQuery(t, `begin`)
+		mustQuery(t, `insert into txn_test (_id, a) values (4, 40)`)
+		mustQuery(t, `delete from txn_test`)
+		if n := count(t, session); n != 0 {
+			t.Fatalf("expected 0 rows in the transaction, got %d", n)
+		}
+		mustQuery(t, `rollback`)
+		if n := count(t, session); n != 2 {
+			t.Fatalf("expected 2 rows after rollback, got %d", n)
+		}
+	})
+
+	t.Run("Aborted", func(t *testing.T) {
+		mustQuery(t, `begin`)
+		mustQuery(t, `insert into txn_test (_id, a) values (5, 50)`)
+		mustError(t, `insert into txn_missing (_id, a) values (6, 60)`, "table 'txn_missing' not found")
+		mustError(t, `insert into txn_test (_id, a) values (7, 70)`, "current transaction is aborted")
+		mustError(t, `commit`, "current transaction is aborted")
+		if n := count(t, session); n != 2 {
+			t.Fatalf("expected 2 rows after aborted commit, got %d", n)
+		}
+		mustError(t, `commit`, "there is no transaction in progress")
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		mustQuery(t, `begin`)
+		mustError(t, `create table txn_other (_id id, a int)`, "statements can run inside a transaction")
+		mustError(t, `rollback to savepoint sp`, "savepoints are not supported")
+		mustError(t, `end transaction`, "current transaction is aborted")
+	})
+
+	t.Run("OneWriter", func(t *testing.T) {
+		other := fbcontext.WithSessionID(context.Background(), "session-2")
+		mustQuery(t, `begin`)
+		mustQuery(t, `insert into txn_test (_id, a) values (8, 80)`)
+
+		// another transaction can read, but waits for the first to end
+		// before it writes
+		if _, _, _, err := sql_test.MustQueryRows(t, other, server, `begin`); err != nil {
+			t.Fatal(err)
+		} else if n := count(t, other); n != 2 {
+			t.Fatalf("expected 2 rows in the other transaction, got %d", n)
+		}
+		timeout, cancel := context.WithTimeout(other, 50*time.Millisecond)
+		defer cancel()
+		if _, _, _, err := sql_test.MustQueryRows(t, timeout, server, `insert into txn_test (_id, a) values (9, 90)`); err == nil {
+			t.Fatal("expected the other transaction's write to wait for the writer")
+		} else if _, _, _, err := sql_test.MustQueryRows(t, other, server, `rollback`); err != nil {
+			t.Fatal(err)
+		}
+
+		mustQuery(t, `commit`)
+		results := mustQuery(t, `select _id, a from txn_test`)
+		if diff := cmp.Diff([][]interface{}{
+			{int64(1), int64(10)},
+			{int64(3), int64(30)},
+			{int64(8), int64(80)},
+		}, results); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		alice := fbcontext.WithUserID(session, "alice")
+		bob := fbcontext.WithUserID(session, "bob")
+		if _, _, _, err := sql_test.MustQueryRows(t, alice, server, `begin`); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if _, _, _, err := sql_test.MustQueryRows(t, alice, server, `rollback`); err != nil {
+				t.Fatal(err)
+			}
+		}()
+
+		// the same session id used by another user is a different session
+		if _, _, _, err := sql_test.MustQueryRows(t, bob, server, `commit`); err == nil || !strings.Contains(err.Error(), "there is no transaction in progress") {
+			t.Fatalf("expected 'there is no transaction in progress', got %v", err)
+		}
+		if _, _, _, err := sql_test.MustQueryRows(t, bob, server, `begin`); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, _, err := sql_test.MustQueryRows(t, bob, server, `rollback`); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
 func TestPlanner_DropThings(t *testing.T) {
 	c := test.MustRunCluster(t, 1)
 	defer c.Close()
@@ -3350,3 +3513,661 @@ WITH
 		t.Fatal(diff)
 	}
 }
+
+func TestPlanner_UserDefinedFunctions(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	server := c.GetNode(0).Server
+
+	mustQuery := func(t *testing.T, sql string) [][]interface{} {
+		t.Helper()
+		results, _, _, err := sql_test.MustQueryRows(t, nil, server, sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+	mustError := func(t *testing.T, sql string, exp string) {
+		t.Helper()
+		_, _, _, err := sql_test.MustQueryRows(t, nil, server, sql)
+		if err == nil || !strings.Contains(err.Error(), exp) {
+			t.Fatalf("expected error containing '%s', got %v", exp, err)
+		}
+	}
+
+	mustQuery(t, `create table udf_test (_id id, a int, s string)`)
+	mustQuery(t, `insert into udf_test values (1, 10, 'x'), (2, 20, 'y'), (3, null, 'z')`)
+
+	t.Run("Create", func(t *testing.T) {
+		mustQuery(t, `create function add_tax (@amount int, @rate int) returns int as begin return @amount + @amount * @rate / 100 end`)
+		mustQuery(t, `create function label (@s string) returns string with language 'sql' as begin return 'item-' || @s; end`)
+		mustQuery(t, `create function double_tax (@amount int) returns decimal(2) as begin return add_tax(@amount, 10) * 2 end`)
+		mustQuery(t, `create function if not exists label (@s string) returns string as begin return @s end`)
+		mustError(t, `create function label (@s string) returns string as begin return @s end`, "function 'label' already exists")
+	})
+
+	t.Run("Call", func(t *testing.T) {
+		results := mustQuery(t, `select _id, add_tax(a, 10) as t, label(s) as l, double_tax(a) as d from udf_test`)
+		got := make(map[int64][]interface{})
+		for _, row := range results {
+			got[row[0].(int64)] = row[1:]
+		}
+		exp := map[int64][]interface{}{
+			1: {int64(11), "item-x", pql.NewDecimal(2200, 2)},
+			2: {int64(22), "item-y", pql.NewDecimal(4400, 2)},
+			3: {nil, "item-z", nil},
+		}
+		opt := cmp.Comparer(func(x, y pql.Decimal) bool {
+			return x.EqualTo(y)
+		})
+		if diff := cmp.Diff(exp, got, opt); diff != "" {
+			t.Fatal(diff)
+		}
+		results = mustQuery(t, `select _id from udf_test where add_tax(a, 50) > 20`)
+		if len(results) != 1 || results[0][0] != int64(2) {
+			t.Fatalf("unexpected filtered results: %v", results)
+		}
+	})
+
+	t.Run("CallErrors", func(t *testing.T) {
+		mustError(t, `select add_tax(a) from udf_test`, "count of formal parameters (2) does not match count of actual parameters (1)")
+		mustError(t, `select add_tax(s, 10) from udf_test`, "an expression of type 'string' cannot be passed to a parameter of type 'int'")
+	})
+
+	t.Run("CreateErrors", func(t *testing.T) {
+		mustError(t, `create function bad (@a int) returns int as begin return a end`, "column 'a' not found")
+		mustError(t, `create function bad (@a int) returns int as begin return udf_test.a end`, "column 'udf_test.a' not found")
+		mustError(t, `create function bad (@a int) returns int as begin return @b end`, "unknown identifier '@b'")
+		mustError(t, `create function bad (@a int) returns int as begin return 'x' end`, "returns an expression of type 'string' which cannot be assigned to type 'int'")
+		mustError(t, `create function bad (@a int) returns int as begin return (select count(*) from udf_test) end`, "function body must be a single RETURN")
+		mustError(t, `create function bad (@a int, @a int) returns int as begin return @a end`, "duplicate parameter '@a'")
+		mustError(t, `create function bad (@a string) returns string with language 'python' as begin return @a end`, "language 'python' is not supported")
+	})
+}
+
+func TestPlanner_Export(t *testing.T) {
+	// exports are posted to /exports/ok; /exports/forbidden refuses them
+	var body []byte
+	var contentType string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/exports/ok":
+			body, _ = io.ReadAll(r.Body)
+			contentType = r.Header.Get("Content-Type")
+		case "/exports/redirect":
+			http.Redirect(w, r, "/other", http.StatusTemporaryRedirect)
+		default:
+			http.Error(w, "nope", http.StatusForbidden)
+		}
+	}))
+	defer ts.Close()
+
+	dir := t.TempDir()
+	c := test.MustRunCluster(t, 1, []server.CommandOption{
+		server.OptCommandServerOptions(
+			pilosa.OptServerExportDirectory(dir),
+			pilosa.OptServerExportURLs([]string{ts.URL + "/exports"}),
+		),
+	})
+	defer c.Close()
+
+	svr := c.GetNode(0).Server
+
+	mustQuery := func(t *testing.T, sql string) [][]interface{} {
+		t.Helper()
+		results, _, _, err := sql_test.MustQueryRows(t, nil, svr, sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+	mustError := func(t *testing.T, sql string, exp string) {
+		t.Helper()
+		_, _, _, err := sql_test.MustQueryRows(t, nil, svr, sql)
+		if err == nil || !strings.Contains(err.Error(), exp) {
+			t.Fatalf("expected error containing '%s', got %v", exp, err)
+		}
+	}
+
+	mustQuery(t, `create table export_test (_id id, a int, d decimal(2), s string, ss stringset)`)
+	mustQuery(t, `insert into export_test values (1, 10, 1.25, 'x,y', ['b', 'a']), (2, null, null, null, null)`)
+
+	t.Run("CSV", func(t *testing.T) {
+		mustQuery(t, `copy (select _id, a, d, s, ss from export_test where _id = 1) to 'export.csv' with format 'CSV' output 'FILE' header_row`)
+		data, err := os.ReadFile(filepath.Join(dir, "export.csv"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := "_id,a,d,s,ss\n1,10,1.25,\"x,y\",\"[\"\"a\"\",\"\"b\"\"]\"\n"
+		if string(data) != exp {
+			t.Fatalf("expected %q, got %q", exp, string(data))
+		}
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		mustQuery(t, `copy (select _id, a, d, s, ss from export_test where _id = 2) to 'sub/export.ndjson' with format 'NDJSON' output 'FILE'`)
+		data, err := os.ReadFile(filepath.Join(dir, "sub", "export.ndjson"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		exp := `{"_id":2,"a":null,"d":null,"s":null,"ss":null}` + "\n"
+		if string(data) != exp {
+			t.Fatalf("expected %q, got %q", exp, string(data))
+		}
+	})
+
+	t.Run("Parquet", func(t *testing.T) {
+		// exported parquet can be loaded again with BULK INSERT
+		mustQuery(t, `copy (select _id, a, d, s from export_test where _id = 1) to 'export.parquet' with format 'PARQUET' output 'FILE'`)
+		mustQuery(t, `create table export_parquet (_id id, a int, d decimal(2), s string)`)
+		mustQuery(t, fmt.Sprintf(`bulk insert into export_parquet (_id, a, d, s) map ('_id' id, 'a' int, 'd' decimal(2), 's' string) from '%s' with format 'PARQUET' input 'FILE'`, filepath.Join(dir, "export.parquet")))
+		results := mustQuery(t, `select _id, a, d, s from export_parquet`)
+		opt := cmp.Comparer(func(x, y pql.Decimal) bool {
+			return x.EqualTo(y)
+		})
+		if diff := cmp.Diff([][]interface{}{
+			{int64(1), int64(10), pql.NewDecimal(125, 2), "x,y"},
+		}, results, opt); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("URL", func(t *testing.T) {
+		mustQuery(t, fmt.Sprintf(`copy (select _id, a from export_test where _id = 1) to '%s/exports/ok' with format 'NDJSON' output 'URL'`, ts.URL))
+		if exp := `{"_id":1,"a":10}` + "\n"; string(body) != exp {
+			t.Fatalf("expected %q, got %q", exp, string(body))
+		}
+		if contentType != "application/x-ndjson" {
+			t.Fatalf("unexpected content type %q", contentType)
+		}
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		mustError(t, fmt.Sprintf(`copy (select _id from export_test) to '%s' with format 'CSV' output 'FILE'`, filepath.Join(dir, "abs.csv")), "path must be relative to the export directory")
+		mustError(t, `copy (select _id from export_test) to '../x.csv' with format 'CSV' output 'FILE'`, "path must not contain '..'")
+		mustError(t, `copy (select _id from export_test) to 'sub/../../x.csv' with format 'CSV' output 'FILE'`, "path must not contain '..'")
+		mustError(t, fmt.Sprintf(`copy (select _id from export_test) to '%s/other' with format 'CSV' output 'URL'`, ts.URL), "url is not an allowed export url")
+		mustError(t, fmt.Sprintf(`copy (select _id from export_test) to '%s/exports/../other' with format 'CSV' output 'URL'`, ts.URL), "url is not an allowed export url")
+		mustError(t, fmt.Sprintf(`copy (select _id from export_test) to '%s/exportsx' with format 'CSV' output 'URL'`, ts.URL), "url is not an allowed export url")
+		mustError(t, fmt.Sprintf(`copy (select _id from export_test) to '%s/exports/redirect' with format 'CSV' output 'URL'`, ts.URL), "307")
+
+		// only admins can export
+		ctx := authn.WithAdmin(context.Background(), false)
+		if _, _, _, err := sql_test.MustQueryRows(t, ctx, svr, `copy (select _id from export_test) to 'user.csv' with format 'CSV' output 'FILE'`); err == nil || !strings.Contains(err.Error(), "admin permission required") {
+			t.Fatalf("expected 'admin permission required', got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "user.csv")); !os.IsNotExist(err) {
+			t.Fatalf("expected no export, got %v", err)
+		}
+	})
+
+	t.Run("NotConfigured", func(t *testing.T) {
+		c := test.MustRunCluster(t, 1)
+		defer c.Close()
+		svr := c.GetNode(0).Server
+
+		if _, _, _, err := sql_test.MustQueryRows(t, nil, svr, `create table export_test (_id id, a int)`); err != nil {
+			t.Fatal(err)
+		}
+		for sql, exp := range map[string]string{
+			`copy (select _id from export_test) to 'x.csv' with format 'CSV' output 'FILE'`:                             "FILE exports are not enabled",
+			fmt.Sprintf(`copy (select _id from export_test) to '%s/exports/ok' with format 'CSV' output 'URL'`, ts.URL): "url is not an allowed export url",
+		} {
+			if _, _, _, err := sql_test.MustQueryRows(t, nil, svr, sql); err == nil || !strings.Contains(err.Error(), exp) {
+				t.Fatalf("expected error containing '%s', got %v", exp, err)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		mustError(t, `copy (select _id from export_test) to 'x' with format 'XML' output 'FILE'`, "invalid format specifier 'XML'")
+		mustError(t, `copy (select _id from export_test) to 'x' with format 'CSV'`, "output specifier expected")
+		mustError(t, `copy (select _id from export_test) to 'x' with format 'CSV' output 'STREAM'`, "invalid output specifier 'STREAM'")
+		mustError(t, `copy (select _id from export_test) to 1 with format 'CSV' output 'FILE'`, "string literal expected")
+		mustError(t, `copy (select _id from export_test) to 'missing/x.csv' with format 'CSV' output 'FILE'`, "unable to write destination")
+
+		// a failed export leaves the file already at the target alone
+		path := filepath.Join(dir, "existing.csv")
+		if err := os.WriteFile(path, []byte("keep"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mustError(t, `copy (select a / (a - 10) from export_test) to 'existing.csv' with format 'CSV' output 'FILE'`, "divisor is equal to zero")
+		if data, err := os.ReadFile(path); err != nil || string(data) != "keep" {
+			t.Fatalf("expected existing file to be kept, got %q, %v", data, err)
+		}
+		if entries, err := os.ReadDir(dir); err != nil {
+			t.Fatal(err)
+		} else {
+			for _, e := range entries {
+				if strings.HasPrefix(e.Name(), ".") {
+					t.Fatalf("unexpected temporary file %s", e.Name())
+				}
+			}
+		}
+
+		mustError(t, fmt.Sprintf(`copy (select _id from export_test) to '%s/exports/forbidden' with format 'CSV' output 'URL'`, ts.URL), "403")
+	})
+}
+
+func TestPlanner_ApproxCountDistinct(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	server := c.GetNode(0).Server
+
+	mustQuery := func(t *testing.T, sql string) [][]interface{} {
+		t.Helper()
+		results, _, _, err := sql_test.MustQueryRows(t, nil, server, sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+	mustError := func(t *testing.T, sql string, exp string) {
+		t.Helper()
+		_, _, _, err := sql_test.MustQueryRows(t, nil, server, sql)
+		if err == nil || !strings.Contains(err.Error(), exp) {
+			t.Fatalf("expected error containing '%s', got %v", exp, err)
+		}
+	}
+	mustEstimate := func(t *testing.T, sql string, exp int64, tol float64) {
+		t.Helper()
+		results := mustQuery(t, sql)
+		if len(results) != 1 || len(results[0]) < 1 {
+			t.Fatalf("unexpected results: %v", results)
+		}
+		got := results[0][0].(int64)
+		if diff := float64(got - exp); diff > float64(exp)*tol || -diff > float64(exp)*tol {
+			t.Fatalf("%s: expected about %d, got %d", sql, exp, got)
+		}
+	}
+
+	mustQuery(t, `create table approx_test (_id id, a int, s string, ss stringset)`)
+
+	// 2000 rows spread over several shards, with 500 distinct values of a,
+	// 300 of s and 50 of ss
+	var sb strings.Builder
+	sb.WriteString(`insert into approx_test values `)
+	for i := 0; i < 2000; i++ {
+		a := i % 500
+		if a%2 == 1 {
+			a = -a
+		}
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "(%d, %d, 'k%d', ['x%d'])", i*3001, a, i%300, i%50)
+	}
+	mustQuery(t, sb.String())
+
+	t.Run("PushedDown", func(t *testing.T) {
+		mustEstimate(t, `select approx_count_distinct(a) from approx_test`, 500, 0.02)
+		mustEstimate(t, `select approx_count_distinct(a, 10) from approx_test`, 500, 0.1)
+		mustEstimate(t, `select approx_count_distinct(s) from approx_test`, 300, 0.02)
+		mustEstimate(t, `select approx_count_distinct(_id) from approx_test`, 2000, 0)
+		mustEstimate(t, `select approx_count_distinct(a) from approx_test where s = 'k1'`, 5, 0)
+	})
+
+	t.Run("Aggregated", func(t *testing.T) {
+		// var() can't be pushed down, so neither is anything alongside it
+		mustEstimate(t, `select approx_count_distinct(a), var(a) from approx_test`, 500, 0.02)
+		mustEstimate(t, `select approx_count_distinct(ss) from approx_test`, 50, 0.02)
+	})
+
+	t.Run("GroupBy", func(t *testing.T) {
+		exact := mustQuery(t, `select s, count(distinct a) from approx_test group by s`)
+		approx := mustQuery(t, `select s, approx_count_distinct(a) from approx_test group by s`)
+		if !reflect.DeepEqual(exact, approx) {
+			t.Fatalf("expected %v, got %v", exact, approx)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		mustError(t, `select approx_count_distinct(*) from approx_test`, "column reference expected")
+		mustError(t, `select approx_count_distinct(a, 2) from approx_test`, "value '2' out of range")
+		mustError(t, `select approx_count_distinct(a, s) from approx_test`, "integer literal expected")
+		mustError(t, `select approx_count_distinct(a, 10, 1) from approx_test`, "does not match count of actual parameters")
+	})
+}
+
+func TestPlanner_PreparedStatements(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	server := c.GetNode(0).Server
+	session := fbcontext.WithSessionID(context.Background(), "session-1")
+
+	mustQuery := func(t *testing.T, sql string) [][]interface{} {
+		t.Helper()
+		results, _, _, err := sql_test.MustQueryRows(t, session, server, sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+	mustError := func(t *testing.T, sql string, exp string) {
+		t.Helper()
+		_, _, _, err := sql_test.MustQueryRows(t, session, server, sql)
+		if err == nil || !strings.Contains(err.Error(), exp) {
+			t.Fatalf("expected error containing '%s', got %v", exp, err)
+		}
+	}
+	queryBound := func(t *testing.T, sql string, parameters ...interface{}) ([][]interface{}, error) {
+		t.Helper()
+		ctx := fbcontext.WithRequestID(context.Background(), fmt.Sprintf("prep-%d", time.Now().UnixNano()))
+		op, err := server.CompilePreparedExecutionPlan(ctx, sql, parameters)
+		if err != nil {
+			return nil, err
+		}
+		iter, err := op.Iterator(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		results := make([][]interface{}, 0)
+		for {
+			row, err := iter.Next(ctx)
+			if err == types.ErrNoMoreRows {
+				return results, nil
+			} else if err != nil {
+				return nil, err
+			}
+			results = append(results, row)
+		}
+	}
+
+	mustQuery(t, `create table prep_test (_id id, a int, s string, ss stringset)`)
+	mustQuery(t, `insert into prep_test (_id, a, s, ss) values (1, 10, 'x', ['p', 'q']), (2, 20, 'y', ['q']), (3, 30, 'x', ['r'])`)
+
+	t.Run("Bound", func(t *testing.T) {
+		results, err := queryBound(t, `select _id from prep_test where a > $1 and s = $2`, json.Number("5"), "x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([][]interface{}{{int64(1)}, {int64(3)}}, results); diff != "" {
+			t.Fatal(diff)
+		}
+
+		// the cached analysis is reused with different values
+		results, err = queryBound(t, `select _id from prep_test where a > $1 and s = $2`, json.Number("15"), "x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([][]interface{}{{int64(3)}}, results); diff != "" {
+			t.Fatal(diff)
+		}
+
+		if _, err := queryBound(t, `insert into prep_test (_id, a, s, ss) values ($1, $2, $3, $4)`, json.Number("4"), json.Number("40"), "z", []interface{}{"q"}); err != nil {
+			t.Fatal(err)
+		}
+		results, err = queryBound(t, `select _id from prep_test where setcontains(ss, $1)`, "q")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([][]interface{}{{int64(1)}, {int64(2)}, {int64(4)}}, results); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("BoundErrors", func(t *testing.T) {
+		if _, err := queryBound(t, `select _id from prep_test where a > $1`); err == nil || !strings.Contains(err.Error(), "statement takes 1 parameter(s), 0 given") {
+			t.Fatalf("expected parameter count error, got %v", err)
+		}
+		if _, err := queryBound(t, `select _id from prep_test where a > $1`, "ten"); err == nil || !strings.Contains(err.Error(), "is not a valid int") {
+			t.Fatalf("expected invalid parameter value error, got %v", err)
+		}
+		if _, err := queryBound(t, `create table prep_other (_id id, a int default $1)`, json.Number("1")); err == nil {
+			t.Fatal("expected error for parameters in create table")
+		}
+		mustError(t, `select _id from prep_test where a > $1`, "statement takes 1 parameter(s), 0 given")
+	})
+
+	t.Run("SchemaChange", func(t *testing.T) {
+		results, err := queryBound(t, `select * from prep_test where _id = $1`, json.Number("2"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || len(results[0]) != 4 {
+			t.Fatalf("unexpected results: %v", results)
+		}
+
+		mustQuery(t, `alter table prep_test add column b int`)
+		results, err = queryBound(t, `select * from prep_test where _id = $1`, json.Number("2"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || len(results[0]) != 5 {
+			t.Fatalf("expected the added column after a schema change, got: %v", results)
+		}
+	})
+
+	t.Run("NoSession", func(t *testing.T) {
+		_, _, _, err := sql_test.MustQueryRows(t, nil, server, `prepare q as select _id from prep_test`)
+		if err == nil || !strings.Contains(err.Error(), "prepared statements require a session") {
+			t.Fatalf("expected 'prepared statements require a session', got %v", err)
+		}
+	})
+
+	t.Run("PrepareExecute", func(t *testing.T) {
+		mustQuery(t, `prepare q as select _id from prep_test where a >= $1 and a < $2`)
+		mustError(t, `prepare q as select _id from prep_test`, "prepared statement 'q' already exists")
+		mustError(t, `prepare bad as select _id from prep_missing where a = $1`, "'prep_missing' not found")
+
+		results := mustQuery(t, `execute q(20, 40)`)
+		if diff := cmp.Diff([][]interface{}{{int64(2)}, {int64(3)}}, results); diff != "" {
+			t.Fatal(diff)
+		}
+		mustError(t, `execute q(20)`, "statement takes 2 parameter(s), 1 given")
+		mustError(t, `execute q(20, a)`, "literal expected for parameter value")
+
+		// statements are private to their session
+		other := fbcontext.WithSessionID(context.Background(), "session-2")
+		if _, _, _, err := sql_test.MustQueryRows(t, other, server, `execute q(20, 40)`); err == nil || !strings.Contains(err.Error(), "prepared statement 'q' does not exist") {
+			t.Fatalf("expected 'prepared statement 'q' does not exist', got %v", err)
+		}
+
+		mustQuery(t, `deallocate q`)
+		mustError(t, `execute q(20, 40)`, "prepared statement 'q' does not exist")
+		mustError(t, `deallocate prepare q`, "prepared statement 'q' does not exist")
+
+		mustQuery(t, `prepare q1 as delete from prep_test where _id = $1`)
+		mustQuery(t, `prepare q2 as select count(*) from prep_test`)
+		mustQuery(t, `execute q1(4)`)
+		if diff := cmp.Diff([][]interface{}{{int64(3)}}, mustQuery(t, `execute q2`)); diff != "" {
+			t.Fatal(diff)
+		}
+		mustQuery(t, `deallocate all`)
+		mustError(t, `execute q2`, "prepared statement 'q2' does not exist")
+	})
+}
+
+func TestPlanner_Funnel(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	server := c.GetNode(0).Server
+
+	mustQuery := func(t *testing.T, sql string) [][]interface{} {
+		t.Helper()
+		results, _, _, err := sql_test.MustQueryRows(t, nil, server, sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+	mustError := func(t *testing.T, sql string, exp string) {
+		t.Helper()
+		_, _, _, err := sql_test.MustQueryRows(t, nil, server, sql)
+		if err == nil || !strings.Contains(err.Error(), exp) {
+			t.Fatalf("expected error containing '%s', got %v", exp, err)
+		}
+	}
+
+	mustQuery(t, `create table funnel_test (_id id, event stringsetq timequantum 'YMD', code idsetq timequantum 'YMD', name string)`)
+	for _, ev := range []struct {
+		id    int
+		event string
+		code  int
+		ts    string
+	}{
+		{1, "signup", 1, "2022-01-01"},
+		{1, "view", 2, "2022-01-03"},
+		{1, "buy", 3, "2022-01-05"},
+		{2, "signup", 1, "2022-01-01"},
+		{2, "view", 2, "2022-01-02"},
+		{2, "buy", 3, "2022-01-10"},
+		{3, "view", 2, "2022-01-01"},
+		{3, "signup", 1, "2022-01-02"},
+		{3, "buy", 3, "2022-01-03"},
+		{4, "signup", 1, "2022-01-05"},
+	} {
+		c.Query(t, "funnel_test", fmt.Sprintf(`Set(%d, event=%q, %sT00:00) Set(%d, code=%d, %sT00:00)`, ev.id, ev.event, ev.ts, ev.id, ev.code, ev.ts))
+	}
+
+	t.Run("Ordered", func(t *testing.T) {
+		results := mustQuery(t, `select * from funnel(funnel_test, event, ['signup', 'view', 'buy'], '7d')`)
+		exp := [][]interface{}{
+			{int64(1), "signup", int64(4)},
+			{int64(2), "view", int64(2)},
+			{int64(3), "buy", int64(1)},
+		}
+		if diff := cmp.Diff(exp, results); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("Unordered", func(t *testing.T) {
+		results := mustQuery(t, `select f.step, f.count from funnel('funnel_test', 'event', ['signup', 'view', 'buy'], '7d', null, null, false) as f where f.step > 1`)
+		exp := [][]interface{}{
+			{int64(2), int64(3)},
+			{int64(3), int64(2)},
+		}
+		if diff := cmp.Diff(exp, results); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("IDsInRange", func(t *testing.T) {
+		results := mustQuery(t, `select value, count from funnel(funnel_test, code, [1, 2], null, '2022-01-02', '2022-01-06')`)
+		exp := [][]interface{}{
+			{int64(1), int64(2)},
+			{int64(2), int64(0)},
+		}
+		if diff := cmp.Diff(exp, results); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		mustError(t, `select * from funnel(funnel_test, event, ['signup'])`, "count of formal parameters")
+		mustError(t, `select * from funnel(missing, event, ['signup'], '7d')`, "table or view 'missing' not found")
+		mustError(t, `select * from funnel(funnel_test, nope, ['signup'], '7d')`, "column 'nope' not found")
+		mustError(t, `select * from funnel(funnel_test, name, ['signup'], '7d')`, "time quantum expression expected")
+		mustError(t, `select * from funnel(funnel_test, event, [1, 2], '7d')`, "do not match")
+		mustError(t, `select * from funnel(funnel_test, event, ['signup'], 7)`, "string literal expected")
+		mustError(t, `select * from funnel(funnel_test, event, ['signup'], '7d', null, null, 1)`, "bool literal expected")
+		mustError(t, `select * from funnel(funnel_test, event, ['signup'], 'soon')`, "invalid window")
+		mustError(t, `select * from nofunc(funnel_test)`, "unknown function 'nofunc'")
+	})
+}
+
+func TestPlanner_TableSample(t *testing.T) {
+	c := test.MustRunCluster(t, 1)
+	defer c.Close()
+
+	server := c.GetNode(0).Server
+
+	mustQuery := func(t *testing.T, sql string) [][]interface{} {
+		t.Helper()
+		results, _, _, err := sql_test.MustQueryRows(t, nil, server, sql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return results
+	}
+	mustError := func(t *testing.T, sql string, exp string) {
+		t.Helper()
+		_, _, _, err := sql_test.MustQueryRows(t, nil, server, sql)
+		if err == nil || !strings.Contains(err.Error(), exp) {
+			t.Fatalf("expected error containing '%s', got %v", exp, err)
+		}
+	}
+
+	mustQuery(t, `create table sample_test (_id id, v int, s string)`)
+	values := make([]string, 0, 200)
+	ids := make([]uint64, 0, 200)
+	for i := 1; i <= 200; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d, '%s')", i, i, []string{"a", "b", "c"}[i%3]))
+		ids = append(ids, uint64(i))
+	}
+	mustQuery(t, `insert into sample_test values `+strings.Join(values, ", "))
+
+	// expected returns the ids a sample selects that pass filter.
+	expected := func(sample pilosa.SampleOptions, filter func(uint64) bool) [][]interface{} {
+		exp := make([][]interface{}, 0)
+		for _, id := range sample.Apply(pilosa.NewRow(ids...)).Columns() {
+			if filter(id) {
+				exp = append(exp, []interface{}{int64(id)})
+			}
+		}
+		return exp
+	}
+	all := func(uint64) bool { return true }
+
+	t.Run("Rows", func(t *testing.T) {
+		results := mustQuery(t, `select _id from sample_test tablesample (10 rows) repeatable (7)`)
+		if diff := cmp.Diff(expected(pilosa.SampleOptions{N: 10, Seed: 7}, all), results); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("RowsBeforeFilter", func(t *testing.T) {
+		results := mustQuery(t, `select _id from sample_test tablesample (50 rows) repeatable (3) where v < 100`)
+		exp := expected(pilosa.SampleOptions{N: 50, Seed: 3}, func(id uint64) bool { return id < 100 })
+		if diff := cmp.Diff(exp, results); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("Percent", func(t *testing.T) {
+		results := mustQuery(t, `select t._id from sample_test as t tablesample (25) repeatable (7) where t.v > 50`)
+		exp := expected(pilosa.SampleOptions{Fraction: 0.25, Seed: 7}, func(id uint64) bool { return id > 50 })
+		if diff := cmp.Diff(exp, results); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("Aggregates", func(t *testing.T) {
+		results := mustQuery(t, `select count(*), sum(v) from sample_test tablesample (12.5 percent) repeatable (1)`)
+		var count, sum int64
+		for _, row := range expected(pilosa.SampleOptions{Fraction: 0.125, Seed: 1}, all) {
+			count++
+			sum += row[0].(int64)
+		}
+		if diff := cmp.Diff([][]interface{}{{count, sum}}, results); diff != "" {
+			t.Fatal(diff)
+		}
+
+		results = mustQuery(t, `select s, count(*) from sample_test tablesample (20 rows) group by s`)
+		var total int64
+		for _, row := range results {
+			total += row[1].(int64)
+		}
+		if total != 20 {
+			t.Fatalf("expected 20 sampled rows, got %d", total)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		mustError(t, `select * from sample_test tablesample (0 percent)`, "table sample percent must be greater than 0 and at most 100")
+		mustError(t, `select * from sample_test tablesample (101)`, "table sample percent must be greater than 0 and at most 100")
+		mustError(t, `select * from sample_test tablesample (0 rows)`, "table sample row count must be greater than 0")
+		mustError(t, `select * from sample_test tablesample (1.5 rows)`, "integer literal expected")
+		mustError(t, `select * from sample_test tablesample ('a')`, "literal expression expected")
+		mustError(t, `select * from sample_test tablesample (10) repeatable ('x')`, "integer literal expected")
+		mustError(t, `select * from fb_database_info tablesample (10)`, "TABLESAMPLE is not supported on 'fb_database_info'")
+	})
+}
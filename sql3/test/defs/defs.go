@@ -38,6 +38,7 @@ var TableTests []TableTest = []TableTest{
 
 	subqueryTests,
 	viewTests,
+	materializedViewTests,
 
 	topLimitTests,
 
@@ -157,6 +158,13 @@ var TableTests []TableTest = []TableTest{
 	likeTests,
 	notLikeTests,
 
+	// pattern tests
+	regexpTests,
+	globTests,
+
+	// geo tests
+	geoTests,
+
 	// null tests
 	nullTests,
 	notNullTests,
@@ -185,10 +193,12 @@ var TableTests []TableTest = []TableTest{
 	// groupby tests
 	groupByTests,
 	groupBySetDistinctTests,
+	groupByBucketTests,
 
 	// create table tests
 	createTable,
 	alterTable,
+	renameTests,
 
 	// joins
 	joinTestsUsers,
@@ -196,6 +206,10 @@ var TableTests []TableTest = []TableTest{
 	joinTestsQuantity,
 	joinTests,
 
+	// analyze tests
+	analyzeTests,
+	analyzeJoinTests,
+
 	// bulk insert
 	bulkInsertTable,
 	bulkInsert,
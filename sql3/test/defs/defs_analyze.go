@@ -0,0 +1,261 @@
+package defs
+
+import (
+	"time"
+
+	featurebase "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/dax"
+	"github.com/featurebasedb/featurebase/v3/pql"
+)
+
+// ANALYZE tests
+var analyzeTests = TableTest{
+	name: "analyzetests",
+	Table: tbl(
+		"analyze_all_types",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("i1", fldTypeInt, "min 0", "max 1000"),
+			srcHdr("b1", fldTypeBool),
+			srcHdr("d1", fldTypeDecimal2),
+			srcHdr("s1", fldTypeString),
+			srcHdr("ss1", fldTypeStringSet),
+			srcHdr("t1", fldTypeTimestamp),
+		),
+		srcRows(
+			srcRow(int64(1), int64(10), bool(true), float64(1.25), string("a"), []string{"x", "y"}, time.Unix(0, 0).UTC()),
+			srcRow(int64(2), int64(20), bool(false), float64(2.5), string("a"), []string{"y"}, time.Unix(60, 0).UTC()),
+			srcRow(int64(3), int64(30), bool(true), nil, string("b"), nil, time.Unix(120, 0).UTC()),
+			srcRow(int64(4), nil, nil, float64(10), nil, []string{"z"}, nil),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			name: "analyze-table-not-found",
+			SQLs: sqls(
+				"analyze analyze_no_such_table",
+			),
+			ExpErr: "table 'analyze_no_such_table' not found",
+		},
+		{
+			name: "analyze-system-table",
+			SQLs: sqls(
+				"analyze fb_database_info",
+			),
+			ExpErr: "ANALYZE on system tables is not supported",
+		},
+		{
+			name: "analyze",
+			SQLs: sqls(
+				"analyze analyze_all_types",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "analyze-statistics",
+			SQLs: sqls(
+				"select column_name, row_count, distinct_count, null_fraction, min_value, max_value from fb_column_stats where table_name = 'analyze_all_types'",
+			),
+			ExpHdrs: hdrs(
+				hdr("column_name", fldTypeString),
+				hdr("row_count", fldTypeInt),
+				hdr("distinct_count", fldTypeInt),
+				hdr("null_fraction", featurebase.WireQueryField{
+					Type:     dax.BaseTypeDecimal + "(4)",
+					BaseType: dax.BaseTypeDecimal,
+					TypeInfo: map[string]interface{}{"scale": int64(4)},
+				}),
+				hdr("min_value", fldTypeString),
+				hdr("max_value", fldTypeString),
+			),
+			ExpRows: rows(
+				row(string("_id"), int64(4), int64(4), pql.NewDecimal(0, 4), nil, nil),
+				row(string("i1"), int64(4), int64(3), pql.NewDecimal(2500, 4), string("10"), string("30")),
+				row(string("b1"), int64(4), int64(2), pql.NewDecimal(2500, 4), nil, nil),
+				row(string("d1"), int64(4), int64(3), pql.NewDecimal(2500, 4), string("1.25"), string("10.00")),
+				row(string("s1"), int64(4), int64(2), pql.NewDecimal(2500, 4), nil, nil),
+				row(string("ss1"), int64(4), int64(3), pql.NewDecimal(2500, 4), nil, nil),
+				row(string("t1"), int64(4), int64(3), pql.NewDecimal(2500, 4), string("1970-01-01T00:00:00Z"), string("1970-01-01T00:02:00Z")),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// analyzing again replaces the statistics
+			name: "analyze-again",
+			SQLs: sqls(
+				"insert into analyze_all_types (_id, i1) values (5, 50)",
+				"analyze analyze_all_types",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "analyze-statistics-replaced",
+			SQLs: sqls(
+				"select column_name, row_count, distinct_count, max_value from fb_column_stats where table_name = 'analyze_all_types' and column_name = 'i1'",
+			),
+			ExpHdrs: hdrs(
+				hdr("column_name", fldTypeString),
+				hdr("row_count", fldTypeInt),
+				hdr("distinct_count", fldTypeInt),
+				hdr("max_value", fldTypeString),
+			),
+			ExpRows: rows(
+				row(string("i1"), int64(5), int64(4), string("50")),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "analyzed-groupby-filtered",
+			SQLs: sqls(
+				"select i1, count(*) from analyze_all_types where i1 is not null group by i1",
+			),
+			ExpHdrs: hdrs(
+				hdr("i1", fldTypeInt),
+				hdr("", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(10), int64(1)),
+				row(int64(20), int64(1)),
+				row(int64(30), int64(1)),
+				row(int64(50), int64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// s1 has nulls, so this is grouped with a PQL GroupBy, which
+			// doesn't return a group for null
+			name: "analyzed-groupby",
+			SQLs: sqls(
+				"select s1, count(*) from analyze_all_types group by s1",
+			),
+			ExpHdrs: hdrs(
+				hdr("s1", fldTypeString),
+				hdr("", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("a"), int64(2)),
+				row(string("b"), int64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// the predicates which can't be pushed down are ordered by their
+			// estimated selectivity
+			name: "analyzed-filter",
+			SQLs: sqls(
+				"select _id from analyze_all_types where i1 + 1 > 15 and s1 like 'a%' and i1 * 2 < 100",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(2)),
+			),
+			Compare: CompareExactUnordered,
+		},
+	},
+}
+
+// analyzeJoinTests joins a table to analyze_all_types; once both are
+// analyzed the smaller table is scanned in the outer loop of the join.
+var analyzeJoinTests = TableTest{
+	name: "analyzejointests",
+	Table: tbl(
+		"analyze_join",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("i1", fldTypeInt, "min 0", "max 1000"),
+			srcHdr("label", fldTypeString),
+		),
+		srcRows(
+			srcRow(int64(1), int64(10), string("ten")),
+			srcRow(int64(2), int64(30), string("thirty")),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			name: "analyze-join-table",
+			SQLs: sqls(
+				"analyze analyze_join",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "analyzed-join",
+			SQLs: sqls(
+				"select a._id, a.i1, j.label from analyze_all_types a inner join analyze_join j on a.i1 = j.i1",
+				"select a._id, a.i1, j.label from analyze_join j inner join analyze_all_types a on a.i1 = j.i1",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("i1", fldTypeInt),
+				hdr("label", fldTypeString),
+			),
+			ExpRows: rows(
+				row(int64(1), int64(10), string("ten")),
+				row(int64(3), int64(30), string("thirty")),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "analyzed-join-filtered",
+			SQLs: sqls(
+				"select a._id, j.label from analyze_all_types a inner join analyze_join j on a.i1 = j.i1 where a.s1 = 'b' and j._id > 0",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("label", fldTypeString),
+			),
+			ExpRows: rows(
+				row(int64(3), string("thirty")),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// every i1 is distinct and none are null, so this is grouped in
+			// memory rather than with a PQL GroupBy
+			name: "analyzed-groupby-unique",
+			SQLs: sqls(
+				"select i1, count(*) from analyze_join group by i1",
+			),
+			ExpHdrs: hdrs(
+				hdr("i1", fldTypeInt),
+				hdr("", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(10), int64(1)),
+				row(int64(30), int64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "drop-analyzed-table",
+			SQLs: sqls(
+				"drop table analyze_join",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// dropping a table drops its statistics
+			name: "dropped-table-statistics",
+			SQLs: sqls(
+				"select count(*) from fb_column_stats where table_name = 'analyze_join'",
+			),
+			ExpHdrs: hdrs(
+				hdr("", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(0)),
+			),
+			Compare: CompareExactUnordered,
+		},
+	},
+}
@@ -80,3 +80,193 @@ var alterTable = TableTest{
 		},
 	},
 }
+
+var renameTests = TableTest{
+	name: "renameTests",
+	Table: tbl(
+		"rename_test",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("an_int", fldTypeInt, "min 0", "max 100"),
+			srcHdr("a_string", fldTypeString),
+			srcHdr("a_string_set", fldTypeStringSet),
+		),
+		srcRows(
+			srcRow(int64(1), int64(11), "str1", []string{"a1", "b1"}),
+			srcRow(int64(2), int64(22), "str2", []string{"a2", "b2"}),
+			srcRow(int64(3), int64(33), "str3", []string{"a3", "b3"}),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			name: "renameColumnNonExistent",
+			SQLs: sqls(
+				"alter table rename_test rename column b_int to c_int",
+			),
+			ExpErr: "column 'b_int' not found",
+		},
+		{
+			name: "renameColumnToExisting",
+			SQLs: sqls(
+				"alter table rename_test rename column an_int to a_string",
+			),
+			ExpErr: "duplicate column 'a_string'",
+		},
+		{
+			name: "renameColumnID",
+			SQLs: sqls(
+				"alter table rename_test rename column _id to id",
+			),
+			ExpErr: "_id column cannot be renamed",
+		},
+		{
+			name: "renameColumns",
+			SQLs: sqls(
+				"alter table rename_test rename column an_int to the_int",
+				"alter table rename_test rename a_string to the_string",
+				"alter table rename_test rename column a_string_set to the_string_set",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "selectRenamedColumns",
+			SQLs: sqls(
+				"select _id, the_int, the_string, the_string_set from rename_test",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("the_int", fldTypeInt),
+				hdr("the_string", fldTypeString),
+				hdr("the_string_set", fldTypeStringSet),
+			),
+			ExpRows: rows(
+				row(int64(1), int64(11), "str1", []string{"a1", "b1"}),
+				row(int64(2), int64(22), "str2", []string{"a2", "b2"}),
+				row(int64(3), int64(33), "str3", []string{"a3", "b3"}),
+			),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "selectOldColumn",
+			SQLs: sqls(
+				"select an_int from rename_test",
+			),
+			ExpErr: "column 'an_int' not found",
+		},
+		{
+			name: "filterRenamedColumns",
+			SQLs: sqls(
+				"select _id from rename_test where the_int > 20 and the_string = 'str3'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(3)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "createOtherTables",
+			SQLs: sqls(
+				"create table rename_other (_id id, an_int int)",
+				"create table rename_keyed (_id string, an_int int)",
+				"insert into rename_keyed values ('a', 1), ('b', 2)",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "renameTableToExisting",
+			SQLs: sqls(
+				"alter table rename_test rename to rename_other",
+			),
+			ExpErr: "table or view 'rename_other' already exists",
+		},
+		{
+			name: "renameKeyedTable",
+			SQLs: sqls(
+				"alter table rename_keyed rename to renamed_keyed",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "selectRenamedKeyedTable",
+			SQLs: sqls(
+				"select _id, an_int from renamed_keyed",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeString),
+				hdr("an_int", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(string("a"), int64(1)),
+				row(string("b"), int64(2)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "renameTable",
+			SQLs: sqls(
+				"alter table rename_test rename to renamed_test",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "selectRenamedTable",
+			SQLs: sqls(
+				"select _id, the_int, the_string, the_string_set from renamed_test",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("the_int", fldTypeInt),
+				hdr("the_string", fldTypeString),
+				hdr("the_string_set", fldTypeStringSet),
+			),
+			ExpRows: rows(
+				row(int64(1), int64(11), "str1", []string{"a1", "b1"}),
+				row(int64(2), int64(22), "str2", []string{"a2", "b2"}),
+				row(int64(3), int64(33), "str3", []string{"a3", "b3"}),
+			),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "insertRenamedTable",
+			SQLs: sqls(
+				"insert into renamed_test (_id, the_int, the_string, the_string_set) values (4, 44, 'str4', ['a4'])",
+			),
+			ExpHdrs: hdrs(),
+			ExpRows: rows(),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "countRenamedTable",
+			SQLs: sqls(
+				"select count(*) from renamed_test where setcontains(the_string_set, 'a4') or the_int = 11",
+			),
+			ExpHdrs: hdrs(
+				hdr("", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(2)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "selectOldTable",
+			SQLs: sqls(
+				"select * from rename_test",
+			),
+			ExpErr: "table or view 'rename_test' not found",
+		},
+	},
+}
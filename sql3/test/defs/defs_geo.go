@@ -0,0 +1,117 @@
+package defs
+
+// geo tests
+var geoTests = TableTest{
+	Table: tbl(
+		"geo_test",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("loc", fldTypeGeo),
+			srcHdr("s1", fldTypeString),
+		),
+		srcRows(
+			srcRow(int64(1), string("40.7128,-74.0060"), string("new york")),
+			srcRow(int64(2), string("40.7357,-74.1724"), string("newark")),
+			srcRow(int64(3), string("51.5074,-0.1278"), string("london")),
+			srcRow(int64(4), string("-17.7134,178.0650"), string("suva")),
+			srcRow(int64(5), string("-16.5,-179.9"), string("taveuni")),
+			srcRow(int64(6), nil, string("nowhere")),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			SQLs: sqls(
+				"select _id from geo_test where st_within(loc, 40, -75, 41, -73)",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(2)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// crosses the antimeridian
+			SQLs: sqls(
+				"select _id from geo_test where st_within(loc, -20, 170, -10, -170)",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(4)),
+				row(int64(5)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_test where st_dwithin(loc, 40.7128, -74.0060, 5000)",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_test where st_dwithin(loc, 40.7128, -74.0060, 20000) and s1 = 'newark'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(2)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// evaluated rather than pushed down
+			SQLs: sqls(
+				"select _id, st_dwithin(loc, 51.5, -0.12, 10000) from geo_test",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("", fldTypeBool),
+			),
+			ExpRows: rows(
+				row(int64(1), bool(false)),
+				row(int64(2), bool(false)),
+				row(int64(3), bool(true)),
+				row(int64(4), bool(false)),
+				row(int64(5), bool(false)),
+				row(int64(6), nil),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_test where st_within(s1, 40, -75, 41, -73)",
+			),
+			ExpErr: "invalid value 'new york' for parameter 'point'",
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_test where st_within(loc, 40, -75)",
+			),
+			ExpErr: "'st_within': count of formal parameters (5) does not match count of actual parameters (3)",
+		},
+		{
+			SQLs: sqls(
+				"select _id from geo_test where st_dwithin(loc, 'a', -75, 10)",
+			),
+			ExpErr: "an expression of type 'string' cannot be assigned to type 'decimal'",
+		},
+		{
+			SQLs: sqls(
+				"insert into geo_test (_id, loc) values (7, '95,0')",
+			),
+			ExpErr: "value '95,0' out of range",
+		},
+	},
+}
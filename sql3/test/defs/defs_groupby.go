@@ -259,6 +259,155 @@ var groupByTests = TableTest{
 	},
 }
 
+// groupby time bucket tests
+var groupByBucketTests = TableTest{
+	Table: tbl(
+		"groupby_bucket_test",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("ts", fldTypeTimestamp),
+			srcHdr("i1", fldTypeInt, "min 0", "max 1000"),
+			srcHdr("i2", fldTypeInt, "min -1000", "max 1000"),
+			srcHdr("s1", fldTypeString),
+		),
+		srcRows(
+			srcRow(int64(1), timestampFromString("2021-01-27T08:00:00Z"), int64(5), int64(-5), string("a")),
+			srcRow(int64(2), timestampFromString("2021-01-27T23:59:59Z"), int64(15), int64(5), string("b")),
+			srcRow(int64(3), timestampFromString("2021-01-28T00:00:00Z"), int64(17), int64(-15), string("a")),
+			srcRow(int64(4), timestampFromString("2021-03-01T12:00:00Z"), int64(25), int64(15), string("b")),
+			srcRow(int64(5), timestampFromString("2020-12-31T23:00:00Z"), int64(31), int64(25), string("a")),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			name: "DateTruncDay",
+			SQLs: sqls(
+				"select date_trunc('d', ts) as d, count(*) as n from groupby_bucket_test group by date_trunc('d', ts)",
+			),
+			ExpHdrs: hdrs(
+				hdr("d", fldTypeString),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row("2020-12-31", int64(1)),
+				row("2021-01-27", int64(2)),
+				row("2021-01-28", int64(1)),
+				row("2021-03-01", int64(1)),
+			),
+			Compare: CompareExactOrdered,
+			PlanCheck: func(jplan []byte) error {
+				return operatorPresentAtPath(jplan, "$.child.child._op", "*planner.PlanOpPQLGroupBy")
+			},
+		},
+		{
+			name: "DateTruncMonthMultipleAggregates",
+			SQLs: sqls(
+				"select date_trunc('m', ts) as m, count(*) as n, sum(i1) as total from groupby_bucket_test group by date_trunc('m', ts)",
+			),
+			ExpHdrs: hdrs(
+				hdr("m", fldTypeString),
+				hdr("n", fldTypeInt),
+				hdr("total", fldTypeInt),
+			),
+			ExpRows: rows(
+				row("2020-12", int64(1), int64(31)),
+				row("2021-01", int64(3), int64(37)),
+				row("2021-03", int64(1), int64(25)),
+			),
+			Compare: CompareExactUnordered,
+			PlanCheck: func(jplan []byte) error {
+				return operatorPresentAtPath(jplan, "$.child.child._op", "*planner.PlanOpPQLMultiGroupBy")
+			},
+		},
+		{
+			name: "DateTruncYearAndColumn",
+			SQLs: sqls(
+				"select s1, date_trunc('yy', ts) as y, count(*) as n from groupby_bucket_test group by s1, date_trunc('yy', ts)",
+			),
+			ExpHdrs: hdrs(
+				hdr("s1", fldTypeString),
+				hdr("y", fldTypeString),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row("a", "2020", int64(1)),
+				row("a", "2021", int64(2)),
+				row("b", "2021", int64(2)),
+			),
+			Compare: CompareExactUnordered,
+			PlanCheck: func(jplan []byte) error {
+				return operatorPresentAtPath(jplan, "$.child.child._op", "*planner.PlanOpPQLGroupBy")
+			},
+		},
+		{
+			name: "DateTruncCountDistinct",
+			SQLs: sqls(
+				"select date_trunc('d', ts) as d, count(distinct s1) as n from groupby_bucket_test group by date_trunc('d', ts)",
+			),
+			ExpHdrs: hdrs(
+				hdr("d", fldTypeString),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row("2020-12-31", int64(1)),
+				row("2021-01-27", int64(2)),
+				row("2021-01-28", int64(1)),
+				row("2021-03-01", int64(1)),
+			),
+			Compare: CompareExactUnordered,
+			PlanCheck: func(jplan []byte) error {
+				return operatorPresentAtPath(jplan, "$.child.child._op", "*planner.PlanOpGroupBy")
+			},
+		},
+		{
+			name: "IntegerDivision",
+			SQLs: sqls(
+				"select i1 / 10 as q, count(*) as n from groupby_bucket_test group by i1 / 10",
+			),
+			ExpHdrs: hdrs(
+				hdr("q", fldTypeInt),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(0), int64(1)),
+				row(int64(1), int64(2)),
+				row(int64(2), int64(1)),
+				row(int64(3), int64(1)),
+			),
+			Compare: CompareExactOrdered,
+			PlanCheck: func(jplan []byte) error {
+				return operatorPresentAtPath(jplan, "$.child.child._op", "*planner.PlanOpPQLGroupBy")
+			},
+		},
+		{
+			name: "IntegerDivisionNegative",
+			SQLs: sqls(
+				"select i2 / 10 as q, count(*) as n from groupby_bucket_test group by i2 / 10",
+			),
+			ExpHdrs: hdrs(
+				hdr("q", fldTypeInt),
+				hdr("n", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(-1), int64(1)),
+				row(int64(0), int64(2)),
+				row(int64(1), int64(1)),
+				row(int64(2), int64(1)),
+			),
+			Compare: CompareExactUnordered,
+			PlanCheck: func(jplan []byte) error {
+				return operatorPresentAtPath(jplan, "$.child.child._op", "*planner.PlanOpGroupBy")
+			},
+		},
+		{
+			SQLs: sqls(
+				"select ts, count(*) from groupby_bucket_test group by date_trunc('d', ts)",
+			),
+			ExpErr: "column 'ts' invalid in select list because it is not aggregated or grouped",
+		},
+	},
+}
+
 // groupby/distinct with sets tests
 var groupBySetDistinctTests = TableTest{
 	Table: tbl(
@@ -0,0 +1,212 @@
+package defs
+
+// REGEXP tests
+var regexpTests = TableTest{
+	Table: tbl(
+		"regexp_all_types",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("i1", fldTypeInt, "min 0", "max 1000"),
+			srcHdr("s1", fldTypeString),
+			srcHdr("ss1", fldTypeStringSet),
+		),
+		srcRows(
+			srcRow(int64(1), int64(10), string("foo"), []string{"red", "green"}),
+			srcRow(int64(2), int64(20), string("food"), []string{"blue"}),
+			srcRow(int64(3), int64(30), string("bar"), []string{"green", "blue"}),
+			srcRow(int64(4), int64(40), string("Foo"), []string{"yellow"}),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			SQLs: sqls(
+				"select i1 regexp 'f.*' from regexp_all_types",
+			),
+			ExpErr: "operator 'REGEXP' incompatible with type 'int'",
+		},
+		{
+			SQLs: sqls(
+				"select s1 regexp 'f(' from regexp_all_types",
+			),
+			ExpErr: "invalid pattern 'f(' for operator 'REGEXP'",
+		},
+		{
+			SQLs: sqls(
+				"select _id, s1 regexp '^fo+$' from regexp_all_types",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("", fldTypeBool),
+			),
+			ExpRows: rows(
+				row(int64(1), bool(true)),
+				row(int64(2), bool(false)),
+				row(int64(3), bool(false)),
+				row(int64(4), bool(false)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			// pushed down to the translate store
+			SQLs: sqls(
+				"select _id from regexp_all_types where s1 regexp '^fo'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(2)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from regexp_all_types where s1 regexp '(?i)^fo'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(2)),
+				row(int64(4)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from regexp_all_types where ss1 regexp 'ee'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(3)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from regexp_all_types where s1 not regexp '^fo'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(3)),
+				row(int64(4)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from regexp_all_types where ss1 not regexp 'ee'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(2)),
+				row(int64(4)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select count(*) from regexp_all_types where s1 regexp 'o' and i1 > 10",
+			),
+			ExpHdrs: hdrs(
+				hdr("", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(2)),
+			),
+			Compare: CompareExactUnordered,
+		},
+	},
+}
+
+// GLOB tests
+var globTests = TableTest{
+	Table: tbl(
+		"glob_all_types",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("b1", fldTypeBool),
+			srcHdr("s1", fldTypeString),
+			srcHdr("ss1", fldTypeStringSet),
+		),
+		srcRows(
+			srcRow(int64(1), bool(true), string("foo"), []string{"red", "green"}),
+			srcRow(int64(2), bool(true), string("food"), []string{"blue"}),
+			srcRow(int64(3), bool(false), string("bar"), []string{"green", "blue"}),
+			srcRow(int64(4), bool(false), string("Foo"), []string{"yellow"}),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			SQLs: sqls(
+				"select b1 glob 'f*' from glob_all_types",
+			),
+			ExpErr: "operator 'GLOB' incompatible with type 'bool'",
+		},
+		{
+			SQLs: sqls(
+				"select _id, s1 glob 'f??' from glob_all_types",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("", fldTypeBool),
+			),
+			ExpRows: rows(
+				row(int64(1), bool(true)),
+				row(int64(2), bool(false)),
+				row(int64(3), bool(false)),
+				row(int64(4), bool(false)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from glob_all_types where s1 glob '[fF]oo*'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(1)),
+				row(int64(2)),
+				row(int64(4)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from glob_all_types where ss1 glob 'bl*'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(2)),
+				row(int64(3)),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			SQLs: sqls(
+				"select _id from glob_all_types where s1 not glob 'f*'",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+			),
+			ExpRows: rows(
+				row(int64(3)),
+				row(int64(4)),
+			),
+			Compare: CompareExactUnordered,
+		},
+	},
+}
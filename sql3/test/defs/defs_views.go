@@ -167,3 +167,247 @@ var viewTests = TableTest{
 		},
 	},
 }
+
+var materializedViewTests = TableTest{
+	name: "materializedviewtests",
+	Table: tbl(
+		"mvtable",
+		srcHdrs(
+			srcHdr("_id", fldTypeID),
+			srcHdr("a_string", fldTypeString),
+			srcHdr("a_int", fldTypeInt),
+		),
+		srcRows(
+			srcRow(int64(1), "str1", int64(10)),
+			srcRow(int64(2), "str1", int64(20)),
+			srcRow(int64(3), "str2", int64(30)),
+			srcRow(int64(4), "str2", int64(40)),
+			srcRow(int64(5), "str3", int64(50)),
+		),
+	),
+	SQLTests: []SQLTest{
+		{
+			name: "create-materialized-view-unnamed-column",
+			SQLs: sqls(
+				"create materialized view mvunnamed as select a_string, count(*) from mvtable group by a_string;",
+			),
+			ExpErr: "materialized view column 2 must have a name",
+		},
+		{
+			name: "create-materialized-view",
+			SQLs: sqls(
+				"create materialized view mvgrouped as select a_string, count(*) as cnt, sum(a_int) as total from mvtable group by a_string;",
+				"create materialized view mvids as select _id, a_string, a_int from mvtable where a_int > 20;",
+				"create view mvplain as select a_string from mvtable;",
+			),
+			ExpHdrs:        hdrs(),
+			ExpRows:        rows(),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "create-materialized-view-should-fail",
+			SQLs: sqls(
+				"create materialized view mvgrouped as select a_string from mvtable;",
+			),
+			ExpErr: "view 'mvgrouped' already exists",
+		},
+		{
+			name: "select-materialized-view",
+			SQLs: sqls(
+				"select a_string, cnt, total from mvgrouped;",
+			),
+			ExpHdrs: hdrs(
+				hdr("a_string", fldTypeString),
+				hdr("cnt", fldTypeInt),
+				hdr("total", fldTypeInt),
+			),
+			ExpRows: rows(
+				row("str1", int64(2), int64(30)),
+				row("str2", int64(2), int64(70)),
+				row("str3", int64(1), int64(50)),
+			),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "select-materialized-view-with-id",
+			SQLs: sqls(
+				"select _id, a_string, a_int from mvids;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("a_string", fldTypeString),
+				hdr("a_int", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(3), "str2", int64(30)),
+				row(int64(4), "str2", int64(40)),
+				row(int64(5), "str3", int64(50)),
+			),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "materialized-view-in-fb-views",
+			SQLs: sqls(
+				"select _id, is_materialized from fb_views where _id = 'mvgrouped';",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeString),
+				hdr("is_materialized", fldTypeBool),
+			),
+			ExpRows: rows(
+				row("mvgrouped", true),
+			),
+			Compare: CompareExactUnordered,
+		},
+		{
+			name: "change-materialized-view-source",
+			SQLs: sqls(
+				"insert into mvtable values (6, 'str3', 60), (7, 'str4', 70);",
+				"delete from mvtable where a_string = 'str1';",
+			),
+			ExpHdrs:        hdrs(),
+			ExpRows:        rows(),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "select-stale-materialized-view",
+			SQLs: sqls(
+				"select a_string, cnt, total from mvgrouped;",
+			),
+			ExpHdrs: hdrs(
+				hdr("a_string", fldTypeString),
+				hdr("cnt", fldTypeInt),
+				hdr("total", fldTypeInt),
+			),
+			ExpRows: rows(
+				row("str1", int64(2), int64(30)),
+				row("str2", int64(2), int64(70)),
+				row("str3", int64(1), int64(50)),
+			),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "refresh-materialized-view",
+			SQLs: sqls(
+				"refresh materialized view mvgrouped;",
+				"refresh materialized view mvids;",
+			),
+			ExpHdrs:        hdrs(),
+			ExpRows:        rows(),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "select-refreshed-materialized-view",
+			SQLs: sqls(
+				"select a_string, cnt, total from mvgrouped;",
+			),
+			ExpHdrs: hdrs(
+				hdr("a_string", fldTypeString),
+				hdr("cnt", fldTypeInt),
+				hdr("total", fldTypeInt),
+			),
+			ExpRows: rows(
+				row("str2", int64(2), int64(70)),
+				row("str3", int64(2), int64(110)),
+				row("str4", int64(1), int64(70)),
+			),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "select-refreshed-materialized-view-with-id",
+			SQLs: sqls(
+				"select _id, a_string, a_int from mvids;",
+			),
+			ExpHdrs: hdrs(
+				hdr("_id", fldTypeID),
+				hdr("a_string", fldTypeString),
+				hdr("a_int", fldTypeInt),
+			),
+			ExpRows: rows(
+				row(int64(3), "str2", int64(30)),
+				row(int64(4), "str2", int64(40)),
+				row(int64(5), "str3", int64(50)),
+				row(int64(6), "str3", int64(60)),
+				row(int64(7), "str4", int64(70)),
+			),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "refresh-materialized-view-again",
+			SQLs: sqls(
+				"insert into mvtable values (3000000, 'str5', 5);",
+				"delete from mvtable where a_string = 'str4';",
+				"refresh materialized view mvgrouped;",
+			),
+			ExpHdrs:        hdrs(),
+			ExpRows:        rows(),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "select-materialized-view-refreshed-again",
+			SQLs: sqls(
+				"select a_string, cnt, total from mvgrouped;",
+			),
+			ExpHdrs: hdrs(
+				hdr("a_string", fldTypeString),
+				hdr("cnt", fldTypeInt),
+				hdr("total", fldTypeInt),
+			),
+			ExpRows: rows(
+				row("str2", int64(2), int64(70)),
+				row("str3", int64(2), int64(110)),
+				row("str5", int64(1), int64(5)),
+			),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "refresh-view-not-materialized",
+			SQLs: sqls(
+				"refresh materialized view mvplain;",
+			),
+			ExpErr: "view 'mvplain' is not a materialized view",
+		},
+		{
+			name: "alter-materialized-view",
+			SQLs: sqls(
+				"alter view mvgrouped as select a_string from mvtable;",
+			),
+			ExpErr: "'mvgrouped' is a materialized view",
+		},
+		{
+			name: "drop-materialized-view-table",
+			SQLs: sqls(
+				"drop table mvgrouped;",
+			),
+			ExpErr: "'mvgrouped' is a materialized view",
+		},
+		{
+			name: "drop-materialized-view",
+			SQLs: sqls(
+				"drop materialized view mvgrouped;",
+				"drop view mvids;",
+			),
+			ExpHdrs:        hdrs(),
+			ExpRows:        rows(),
+			Compare:        CompareExactUnordered,
+			SortStringKeys: true,
+		},
+		{
+			name: "select-materialized-view-after-drop",
+			SQLs: sqls(
+				"select * from mvgrouped;",
+			),
+			ExpErr: "table or view 'mvgrouped' not found",
+		},
+	},
+}
@@ -23,6 +23,10 @@ var (
 		Type:     dax.BaseTypeBool,
 		BaseType: dax.BaseTypeBool,
 	}
+	fldTypeGeo featurebase.WireQueryField = featurebase.WireQueryField{
+		Type:     dax.BaseTypeGeo,
+		BaseType: dax.BaseTypeGeo,
+	}
 	fldTypeIDSet featurebase.WireQueryField = featurebase.WireQueryField{
 		Type:     dax.BaseTypeIDSet,
 		BaseType: dax.BaseTypeIDSet,
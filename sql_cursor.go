@@ -0,0 +1,163 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// defaultSQLCursorTimeout is how long a cursor can go unread before it is
+// closed.
+const defaultSQLCursorTimeout = 5 * time.Minute
+
+// defaultMaxSQLCursors is how many cursors can be open at once.
+const defaultMaxSQLCursors = 1000
+
+// sqlCursor is a sql query whose results are being read a page at a time, by
+// a /sql request followed by /sql/cursor/{id} requests.
+type sqlCursor struct {
+	id        string
+	requestID string
+
+	// userID is the user who opened the cursor; only they can read or
+	// close it.
+	userID string
+
+	// ctx is the context the query executes in. It holds the values of the
+	// context of the request which opened the cursor, but not its
+	// cancellation, so that the query outlives the request; cancel is called
+	// when the cursor is closed.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	op       types.PlanOperator
+	iter     types.RowIterator
+	schema   WireQuerySchema
+	pageSize int
+
+	// next is the first row of the next page; it is read ahead of the page
+	// so a cursor only exists while there are rows left to read.
+	next types.Row
+
+	// busy is set while a page is being read, during which the cursor
+	// doesn't expire, and can't be read by another request.
+	busy  bool
+	timer *time.Timer
+}
+
+// sqlCursors holds the open cursors of a Handler.
+type sqlCursors struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	max     int
+	cursors map[string]*sqlCursor
+}
+
+func newSQLCursors(timeout time.Duration, max int) *sqlCursors {
+	return &sqlCursors{
+		timeout: timeout,
+		max:     max,
+		cursors: make(map[string]*sqlCursor),
+	}
+}
+
+// open adds cur to the open cursors, assigning its id. The cursor is closed
+// if it isn't read within the timeout.
+func (c *sqlCursors) open(cur *sqlCursor) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return errors.Wrap(err, "generating cursor id")
+	}
+	cur.id = id.String()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.cursors) >= c.max {
+		return errors.Errorf("too many open cursors (max %d); read or close some before opening more", c.max)
+	}
+	c.cursors[cur.id] = cur
+	cur.timer = time.AfterFunc(c.timeout, func() { c.expire(cur) })
+	return nil
+}
+
+// acquire returns the cursor with the given id opened by userID, marking
+// it busy until it is released or closed.
+func (c *sqlCursors) acquire(id, userID string) (*sqlCursor, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cur, ok := c.cursors[id]
+	if !ok || cur.userID != userID {
+		return nil, errors.Errorf("cursor '%s' not found; it may have expired", id)
+	}
+	if cur.busy {
+		return nil, errors.Errorf("cursor '%s' is being read by another request", id)
+	}
+	cur.busy = true
+	cur.timer.Stop()
+	return cur, nil
+}
+
+// release makes an acquired cursor available to be read again, restarting
+// its timeout.
+func (c *sqlCursors) release(cur *sqlCursor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cur.busy = false
+	cur.timer.Reset(c.timeout)
+}
+
+// close removes the cursor with the given id opened by userID, stopping its
+// query. It returns false if there is no such cursor.
+func (c *sqlCursors) close(id, userID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cur, ok := c.cursors[id]
+	if !ok || cur.userID != userID {
+		return false
+	}
+	c.remove(cur)
+	return true
+}
+
+// closeAll closes every open cursor.
+func (c *sqlCursors) closeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cur := range c.cursors {
+		c.remove(cur)
+	}
+}
+
+// expire closes cur once its timeout has passed, unless it is being read.
+func (c *sqlCursors) expire(cur *sqlCursor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cur.busy || c.cursors[cur.id] != cur {
+		return
+	}
+	c.remove(cur)
+}
+
+// remove must be called with c.mu held.
+func (c *sqlCursors) remove(cur *sqlCursor) {
+	cur.timer.Stop()
+	cur.cancel()
+	delete(c.cursors, cur.id)
+}
+
+// detachedContext holds the values of its parent context, without its
+// deadline or cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
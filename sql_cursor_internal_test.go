@@ -0,0 +1,115 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSQLCursors(t *testing.T) {
+	newCursor := func() *sqlCursor {
+		ctx, cancel := context.WithCancel(detachedContext{parent: context.Background()})
+		return &sqlCursor{ctx: ctx, cancel: cancel}
+	}
+
+	t.Run("Expire", func(t *testing.T) {
+		cursors := newSQLCursors(10*time.Millisecond, defaultMaxSQLCursors)
+		cur := newCursor()
+		if err := cursors.open(cur); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case <-cur.ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected cursor to expire")
+		}
+		if _, err := cursors.acquire(cur.id, ""); err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	})
+
+	t.Run("Busy", func(t *testing.T) {
+		cursors := newSQLCursors(10*time.Millisecond, defaultMaxSQLCursors)
+		cur := newCursor()
+		if err := cursors.open(cur); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cursors.acquire(cur.id, ""); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cursors.acquire(cur.id, ""); err == nil || !strings.Contains(err.Error(), "being read") {
+			t.Fatalf("expected busy error, got %v", err)
+		}
+
+		// a cursor doesn't expire while it is being read
+		time.Sleep(50 * time.Millisecond)
+		if err := cur.ctx.Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cursors.release(cur)
+		select {
+		case <-cur.ctx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected cursor to expire")
+		}
+	})
+
+	t.Run("CloseAll", func(t *testing.T) {
+		cursors := newSQLCursors(time.Hour, defaultMaxSQLCursors)
+		a, b := newCursor(), newCursor()
+		for _, cur := range []*sqlCursor{a, b} {
+			if err := cursors.open(cur); err != nil {
+				t.Fatal(err)
+			}
+		}
+		cursors.closeAll()
+		if a.ctx.Err() == nil || b.ctx.Err() == nil {
+			t.Fatal("expected cursors to be closed")
+		}
+		if cursors.close(a.id, "") {
+			t.Fatal("expected cursor to be gone")
+		}
+	})
+
+	t.Run("Owner", func(t *testing.T) {
+		cursors := newSQLCursors(time.Hour, defaultMaxSQLCursors)
+		cur := newCursor()
+		cur.userID = "alice"
+		if err := cursors.open(cur); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cursors.acquire(cur.id, "bob"); err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Fatalf("expected not found error, got %v", err)
+		} else if cursors.close(cur.id, "bob") {
+			t.Fatal("expected another user to be unable to close the cursor")
+		} else if _, err := cursors.acquire(cur.id, "alice"); err != nil {
+			t.Fatal(err)
+		} else if !cursors.close(cur.id, "alice") {
+			t.Fatal("expected the cursor to be closed")
+		}
+	})
+
+	t.Run("Max", func(t *testing.T) {
+		cursors := newSQLCursors(time.Hour, 2)
+		a, b := newCursor(), newCursor()
+		for _, cur := range []*sqlCursor{a, b} {
+			if err := cursors.open(cur); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := cursors.open(newCursor()); err == nil || !strings.Contains(err.Error(), "too many open cursors") {
+			t.Fatalf("expected too many open cursors error, got %v", err)
+		}
+
+		// closing a cursor makes room for another
+		cursors.close(a.id, "")
+		if err := cursors.open(newCursor()); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
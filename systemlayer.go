@@ -1,8 +1,14 @@
 package pilosa
 
 import (
+	"context"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/featurebasedb/featurebase/v3/sql3/parser"
+	planner_types "github.com/featurebasedb/featurebase/v3/sql3/planner/types"
+	"github.com/pkg/errors"
 )
 
 // ExecutionRequest holds data about an (sql) execution request
@@ -93,7 +99,209 @@ type ExecutionRequestsAPI interface {
 	GetRequest(requestID string) (ExecutionRequest, error)
 }
 
+// SQLTransaction holds the state of an open (sql) transaction. The writes
+// issued between BEGIN and COMMIT are applied as they arrive, but through a
+// single write Qcx which holds on to them until the transaction commits, so
+// no one else sees them before COMMIT, and ROLLBACK discards them.
+type SQLTransaction struct {
+	// the id of the session that owns the transaction
+	SessionID string
+	// the id of the user who began the transaction; only requests from the
+	// same user can use it
+	UserID string
+	// time the transaction began
+	StartTime time.Time
+
+	mu  sync.Mutex
+	qcx *Qcx
+	err error
+}
+
+// NewSQLTransaction returns a new, empty transaction for the session of a
+// user.
+func NewSQLTransaction(sessionID string, userID string) *SQLTransaction {
+	return &SQLTransaction{
+		SessionID: sessionID,
+		UserID:    userID,
+		StartTime: time.Now(),
+	}
+}
+
+// sessionQcx returns the write Qcx of the transaction, allocating it from
+// txf the first time the transaction reads or writes.
+func (t *SQLTransaction) sessionQcx(txf *TxFactory) (*Qcx, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		return nil, t.err
+	}
+	if t.qcx == nil {
+		t.qcx = txf.NewSessionQcx()
+	} else if t.qcx.Txf != txf {
+		return nil, errors.New("transaction used with another holder")
+	}
+	return t.qcx, nil
+}
+
+// Fail marks the transaction as failed. A failed transaction cannot commit;
+// the first error recorded is kept.
+func (t *SQLTransaction) Fail(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+// Err returns the error which failed the transaction, if any.
+func (t *SQLTransaction) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// Commit commits the writes of the transaction. A failed transaction is
+// rolled back instead, and its error is returned.
+func (t *SQLTransaction) Commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		if t.qcx != nil {
+			t.qcx.Abort()
+		}
+		return t.err
+	}
+	if t.qcx == nil {
+		return nil
+	}
+	return t.qcx.Finish()
+}
+
+// Rollback discards the writes of the transaction.
+func (t *SQLTransaction) Rollback() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.qcx != nil {
+		t.qcx.Abort()
+	}
+}
+
+// sqlTransactionKey is the context key for the sql transaction a request
+// runs in.
+type sqlTransactionKey struct{}
+
+// WithSQLTransaction returns a context whose reads and writes run in the
+// write Qcx of txn.
+func WithSQLTransaction(ctx context.Context, txn *SQLTransaction) context.Context {
+	return context.WithValue(ctx, sqlTransactionKey{}, txn)
+}
+
+// sqlTransactionFromContext returns the sql transaction set on ctx by
+// WithSQLTransaction, if there is one.
+func sqlTransactionFromContext(ctx context.Context) *SQLTransaction {
+	txn, _ := ctx.Value(sqlTransactionKey{}).(*SQLTransaction)
+	return txn
+}
+
+// SQLTransactionsAPI defines the API for tracking the open (sql) transaction
+// of each session. A session belongs to the user who began the transaction;
+// the same session id used by another user is a different session.
+type SQLTransactionsAPI interface {
+	// begin a transaction for a session of a user
+	BeginTransaction(sessionID string, userID string) (*SQLTransaction, error)
+
+	// get the open transaction for a session of a user, if there is one
+	GetTransaction(sessionID string, userID string) (*SQLTransaction, bool)
+
+	// end the open transaction for a session of a user, returning it; it
+	// must then be passed to CommitTransaction or RollbackTransaction
+	EndTransaction(sessionID string, userID string) (*SQLTransaction, error)
+
+	// wait until txn is the only open transaction which writes. The writes
+	// of a transaction hold the shards they write until it ends, so
+	// transactions write one at a time, rather than wait on each other.
+	LockWrites(ctx context.Context, txn *SQLTransaction) error
+
+	// commit a transaction returned by EndTransaction
+	CommitTransaction(txn *SQLTransaction) error
+
+	// roll back a transaction returned by EndTransaction
+	RollbackTransaction(txn *SQLTransaction)
+}
+
+// PreparedStatement holds a (sql) statement which has been parsed and
+// analyzed, and which can be compiled again and again with different values
+// for its parameters.
+type PreparedStatement struct {
+	// the text of the statement
+	SQL string
+	// the analyzed statement; it is shared, so it must be cloned before it is
+	// compiled
+	Statement parser.Statement
+	// the number of positional parameters the statement takes
+	ParameterCount int
+	// the tables the statement was analyzed against
+	Tables []string
+	// the version of the schema of those tables
+	SchemaVersion string
+}
+
+// PreparedStatementsAPI defines the API for caching analyzed statements, and
+// for tracking the statements each session has prepared by name. A session is
+// identified by its id and the user it belongs to.
+type PreparedStatementsAPI interface {
+	// get the cached analysis of a statement
+	GetStatement(sql string) (*PreparedStatement, bool)
+
+	// cache the analysis of a statement, replacing any older analysis
+	AddStatement(stmt *PreparedStatement)
+
+	// name a statement for a session
+	Prepare(sessionID string, userID string, name string, sql string) error
+
+	// get the text of a statement a session has prepared
+	GetPrepared(sessionID string, userID string, name string) (string, bool)
+
+	// remove a statement a session has prepared
+	Deallocate(sessionID string, userID string, name string) error
+
+	// remove all the statements a session has prepared
+	DeallocateAll(sessionID string, userID string)
+}
+
+// MaterializedViewState holds what an incremental refresh of a materialized
+// view needs to know about the refresh before it
+type MaterializedViewState struct {
+	// the table the refresh wrote the result to, and when it was done
+	Table       string
+	RefreshedAt time.Time
+	// the version of each shard of the view's source table when it was read
+	ShardVersions map[uint64]uint64
+	// the result of the view's statement over each shard of the source
+	// table, keyed by shard and then by the group of each row
+	Partials map[uint64]map[string]planner_types.Row
+}
+
+// MaterializedViewsAPI defines the API for keeping the state of the last
+// refresh of each materialized view, and for serializing refreshes of a view
+type MaterializedViewsAPI interface {
+	// lock a view for a refresh; the returned func unlocks it
+	Lock(viewName string) func()
+
+	// get the state of the last refresh of a view
+	GetState(viewName string) (*MaterializedViewState, bool)
+
+	// set the state of the last refresh of a view
+	SetState(viewName string, state *MaterializedViewState)
+
+	// forget the state of a view
+	DeleteState(viewName string)
+}
+
 // SystemLayerAPI defines an api to allow access to internal FeatureBase state
 type SystemLayerAPI interface {
 	ExecutionRequests() ExecutionRequestsAPI
+	Transactions() SQLTransactionsAPI
+	PreparedStatements() PreparedStatementsAPI
+	MaterializedViews() MaterializedViewsAPI
 }
@@ -0,0 +1,70 @@
+package systemlayer
+
+import (
+	"strings"
+	"sync"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+)
+
+// MaterializedViews is an internal struct that keeps the state of the last
+// refresh of each materialized view, and a lock for each view so refreshes of
+// a view run one at a time
+type MaterializedViews struct {
+	mu sync.Mutex
+	// the refresh locks, keyed by (lower case) view name
+	locks map[string]*sync.Mutex
+	// the state of the last refresh, keyed by (lower case) view name
+	states map[string]*pilosa.MaterializedViewState
+}
+
+// Ensure type implements interface.
+var _ pilosa.MaterializedViewsAPI = (*MaterializedViews)(nil)
+
+func NewMaterializedViewsAPI() *MaterializedViews {
+	return &MaterializedViews{
+		locks:  make(map[string]*sync.Mutex),
+		states: make(map[string]*pilosa.MaterializedViewState),
+	}
+}
+
+// Lock waits until no other refresh of a view is running, and returns a func
+// which ends the refresh
+func (m *MaterializedViews) Lock(viewName string) func() {
+	viewName = strings.ToLower(viewName)
+	m.mu.Lock()
+	l, ok := m.locks[viewName]
+	if !ok {
+		l = &sync.Mutex{}
+		m.locks[viewName] = l
+	}
+	m.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// GetState returns the state of the last refresh of a view
+func (m *MaterializedViews) GetState(viewName string) (*pilosa.MaterializedViewState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[strings.ToLower(viewName)]
+	return state, ok
+}
+
+// SetState sets the state of the last refresh of a view
+func (m *MaterializedViews) SetState(viewName string, state *pilosa.MaterializedViewState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.states[strings.ToLower(viewName)] = state
+}
+
+// DeleteState forgets the state of a view
+func (m *MaterializedViews) DeleteState(viewName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.states, strings.ToLower(viewName))
+}
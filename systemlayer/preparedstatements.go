@@ -0,0 +1,154 @@
+package systemlayer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+	"github.com/featurebasedb/featurebase/v3/lru"
+)
+
+// defaultPreparedStatementCacheSize is the number of analyzed statements
+// kept; the least recently used are evicted first
+const defaultPreparedStatementCacheSize = 1000
+
+// defaultPreparedStatementIdleTimeout is how long the statements a session
+// has prepared are kept without the session using any of them
+const defaultPreparedStatementIdleTimeout = time.Hour
+
+// preparedSession is the statements a session has prepared, and the last time
+// the session used them
+type preparedSession struct {
+	// the text of the statements, keyed by (lower case) name
+	statements map[string]string
+	lastUsed   time.Time
+}
+
+// PreparedStatements is an internal struct that caches analyzed statements
+// by their text, and keeps the statements each session has prepared by name
+type PreparedStatements struct {
+	mu sync.Mutex
+	// the analyzed statements, keyed by statement text
+	statements *lru.Cache
+	// the statements each session has prepared, keyed by session
+	prepared map[sessionKey]*preparedSession
+
+	// the statements of sessions which go unused for longer than
+	// idleTimeout are removed, so that abandoned sessions don't keep them
+	// forever
+	idleTimeout time.Duration
+	now         func() time.Time
+}
+
+// Ensure type implements interface.
+var _ pilosa.PreparedStatementsAPI = (*PreparedStatements)(nil)
+
+func NewPreparedStatementsAPI() *PreparedStatements {
+	return &PreparedStatements{
+		statements:  lru.New(defaultPreparedStatementCacheSize),
+		prepared:    make(map[sessionKey]*preparedSession),
+		idleTimeout: defaultPreparedStatementIdleTimeout,
+		now:         time.Now,
+	}
+}
+
+// GetStatement returns the cached analysis of a statement
+func (p *PreparedStatements) GetStatement(sql string) (*pilosa.PreparedStatement, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stmt, ok := p.statements.Get(sql)
+	if !ok {
+		return nil, false
+	}
+	return stmt.(*pilosa.PreparedStatement), true
+}
+
+// AddStatement caches the analysis of a statement
+func (p *PreparedStatements) AddStatement(stmt *pilosa.PreparedStatement) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.statements.Add(stmt.SQL, stmt)
+}
+
+// Prepare names the statement text sql for a session
+func (p *PreparedStatements) Prepare(sessionID string, userID string, name string, sql string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.expireIdle()
+
+	name = strings.ToLower(name)
+	key := sessionKey{sessionID: sessionID, userID: userID}
+	session, ok := p.prepared[key]
+	if !ok {
+		session = &preparedSession{statements: make(map[string]string)}
+		p.prepared[key] = session
+	}
+	session.lastUsed = p.now()
+	if _, ok := session.statements[name]; ok {
+		return fmt.Errorf("prepared statement %s already exists", name)
+	}
+	session.statements[name] = sql
+	return nil
+}
+
+// GetPrepared returns the text of a statement a session has prepared
+func (p *PreparedStatements) GetPrepared(sessionID string, userID string, name string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.expireIdle()
+
+	session, ok := p.prepared[sessionKey{sessionID: sessionID, userID: userID}]
+	if !ok {
+		return "", false
+	}
+	session.lastUsed = p.now()
+	sql, ok := session.statements[strings.ToLower(name)]
+	return sql, ok
+}
+
+// Deallocate removes a statement a session has prepared
+func (p *PreparedStatements) Deallocate(sessionID string, userID string, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.expireIdle()
+
+	name = strings.ToLower(name)
+	key := sessionKey{sessionID: sessionID, userID: userID}
+	session, ok := p.prepared[key]
+	if !ok {
+		return fmt.Errorf("prepared statement %s does not exist", name)
+	} else if _, ok := session.statements[name]; !ok {
+		return fmt.Errorf("prepared statement %s does not exist", name)
+	}
+	delete(session.statements, name)
+	if len(session.statements) == 0 {
+		delete(p.prepared, key)
+	}
+	return nil
+}
+
+// DeallocateAll removes all the statements a session has prepared
+func (p *PreparedStatements) DeallocateAll(sessionID string, userID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.prepared, sessionKey{sessionID: sessionID, userID: userID})
+}
+
+// expireIdle removes the statements of sessions which have not used them for
+// longer than the idle timeout. Call only when holding p.mu.
+func (p *PreparedStatements) expireIdle() {
+	if p.idleTimeout <= 0 {
+		return
+	}
+	now := p.now()
+	for key, session := range p.prepared {
+		if now.Sub(session.lastUsed) > p.idleTimeout {
+			delete(p.prepared, key)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package systemlayer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPreparedStatements_IdleTimeout(t *testing.T) {
+	now := time.Now()
+	prepared := NewPreparedStatementsAPI()
+	prepared.now = func() time.Time { return now }
+
+	if err := prepared.Prepare("s1", "alice", "q", "select 1"); err != nil {
+		t.Fatal(err)
+	} else if err := prepared.Prepare("s2", "alice", "q", "select 2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// using a session's statements keeps them
+	now = now.Add(defaultPreparedStatementIdleTimeout / 2)
+	if _, ok := prepared.GetPrepared("s1", "alice", "q"); !ok {
+		t.Fatal("expected statement for s1")
+	}
+
+	now = now.Add(defaultPreparedStatementIdleTimeout/2 + time.Second)
+	if _, ok := prepared.GetPrepared("s1", "alice", "q"); !ok {
+		t.Fatal("expected statement for s1 to still be prepared")
+	} else if _, ok := prepared.GetPrepared("s2", "alice", "q"); ok {
+		t.Fatal("expected idle statement for s2 to be removed")
+	} else if err := prepared.Prepare("s2", "alice", "q", "select 2"); err != nil {
+		t.Fatalf("expected to prepare the statement for s2 again, got %v", err)
+	}
+}
+
+func TestPreparedStatements_Users(t *testing.T) {
+	prepared := NewPreparedStatementsAPI()
+	if err := prepared.Prepare("s1", "alice", "q", "select 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := prepared.GetPrepared("s1", "bob", "q"); ok {
+		t.Fatal("expected no statement for another user's session")
+	} else if err := prepared.Deallocate("s1", "bob", "q"); err == nil {
+		t.Fatal("expected another user to be unable to deallocate the statement")
+	} else if err := prepared.Prepare("s1", "bob", "q", "select 2"); err != nil {
+		t.Fatalf("expected another user to prepare a statement of the same name, got %v", err)
+	}
+
+	prepared.DeallocateAll("s1", "bob")
+	if sql, ok := prepared.GetPrepared("s1", "alice", "q"); !ok || sql != "select 1" {
+		t.Fatalf("expected alice's statement, got %q", sql)
+	}
+}
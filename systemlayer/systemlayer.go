@@ -6,15 +6,33 @@ import pilosa "github.com/featurebasedb/featurebase/v3"
 // Initially this is just the execution requests, but later may include other
 // internal state (Buffer Pool?)
 type SystemLayer struct {
-	executionRequests pilosa.ExecutionRequestsAPI
+	executionRequests  pilosa.ExecutionRequestsAPI
+	transactions       pilosa.SQLTransactionsAPI
+	preparedStatements pilosa.PreparedStatementsAPI
+	materializedViews  pilosa.MaterializedViewsAPI
 }
 
 func NewSystemLayer() *SystemLayer {
 	return &SystemLayer{
-		executionRequests: NewExecutionRequestsAPI(),
+		executionRequests:  NewExecutionRequestsAPI(),
+		transactions:       NewTransactionsAPI(),
+		preparedStatements: NewPreparedStatementsAPI(),
+		materializedViews:  NewMaterializedViewsAPI(),
 	}
 }
 
 func (e *SystemLayer) ExecutionRequests() pilosa.ExecutionRequestsAPI {
 	return e.executionRequests
 }
+
+func (e *SystemLayer) Transactions() pilosa.SQLTransactionsAPI {
+	return e.transactions
+}
+
+func (e *SystemLayer) PreparedStatements() pilosa.PreparedStatementsAPI {
+	return e.preparedStatements
+}
+
+func (e *SystemLayer) MaterializedViews() pilosa.MaterializedViewsAPI {
+	return e.materializedViews
+}
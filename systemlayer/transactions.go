@@ -0,0 +1,207 @@
+package systemlayer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pilosa "github.com/featurebasedb/featurebase/v3"
+)
+
+// defaultTransactionIdleTimeout is how long an open transaction can go without
+// a statement from its session before it is rolled back
+const defaultTransactionIdleTimeout = 10 * time.Minute
+
+// sessionKey identifies a session; the same session id used by different
+// users names different sessions
+type sessionKey struct {
+	sessionID string
+	userID    string
+}
+
+// openTransaction is a transaction which has not yet been committed or rolled
+// back, and the last time its session used it
+type openTransaction struct {
+	txn      *pilosa.SQLTransaction
+	lastUsed time.Time
+}
+
+// Transactions is an internal struct that keeps the open sql transaction for
+// each session, and lets one transaction at a time write
+type Transactions struct {
+	mu sync.Mutex
+	// the open transactions, keyed by session
+	transactions map[sessionKey]*openTransaction
+
+	// the transaction which holds the right to write, if any, and a
+	// condition to wait on for it to be released
+	writer         *pilosa.SQLTransaction
+	writerReleased *sync.Cond
+	// fires when the writer may have been idle for longer than idleTimeout
+	writerTimer *time.Timer
+
+	// open transactions which go unused for longer than idleTimeout are
+	// rolled back, so that abandoned sessions don't hold their writes, and
+	// the shards they wrote, forever
+	idleTimeout time.Duration
+	now         func() time.Time
+}
+
+// Ensure type implements interface.
+var _ pilosa.SQLTransactionsAPI = (*Transactions)(nil)
+
+func NewTransactionsAPI() *Transactions {
+	t := &Transactions{
+		transactions: make(map[sessionKey]*openTransaction),
+		idleTimeout:  defaultTransactionIdleTimeout,
+		now:          time.Now,
+	}
+	t.writerReleased = sync.NewCond(&t.mu)
+	return t
+}
+
+// BeginTransaction starts a new transaction for a session
+func (t *Transactions) BeginTransaction(sessionID string, userID string) (*pilosa.SQLTransaction, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expireIdle()
+
+	key := sessionKey{sessionID: sessionID, userID: userID}
+	if _, ok := t.transactions[key]; ok {
+		return nil, fmt.Errorf("session %s already has a transaction in progress", sessionID)
+	}
+	txn := pilosa.NewSQLTransaction(sessionID, userID)
+	t.transactions[key] = &openTransaction{txn: txn, lastUsed: t.now()}
+	return txn, nil
+}
+
+// GetTransaction returns the open transaction for a session
+func (t *Transactions) GetTransaction(sessionID string, userID string) (*pilosa.SQLTransaction, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expireIdle()
+
+	open, ok := t.transactions[sessionKey{sessionID: sessionID, userID: userID}]
+	if !ok {
+		return nil, false
+	}
+	open.lastUsed = t.now()
+	return open.txn, true
+}
+
+// EndTransaction removes the open transaction for a session and returns it
+func (t *Transactions) EndTransaction(sessionID string, userID string) (*pilosa.SQLTransaction, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.expireIdle()
+
+	key := sessionKey{sessionID: sessionID, userID: userID}
+	open, ok := t.transactions[key]
+	if !ok {
+		return nil, fmt.Errorf("session %s has no transaction in progress", sessionID)
+	}
+	delete(t.transactions, key)
+	return open.txn, nil
+}
+
+// LockWrites waits until no other open transaction holds the right to write,
+// then gives it to txn until txn ends. While waiting, a writer which has gone
+// idle is rolled back.
+func (t *Transactions) LockWrites(ctx context.Context, txn *pilosa.SQLTransaction) error {
+	// wake the waiters when ctx is done, so they can give up
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			t.mu.Lock()
+			t.writerReleased.Broadcast()
+			t.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.writer != nil && t.writer != txn {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		t.writerReleased.Wait()
+	}
+	if t.writer == nil {
+		t.writer = txn
+		t.watchWriter()
+	}
+	return nil
+}
+
+// CommitTransaction commits a transaction returned by EndTransaction, and
+// lets the next transaction write
+func (t *Transactions) CommitTransaction(txn *pilosa.SQLTransaction) error {
+	defer t.releaseWriter(txn)
+	return txn.Commit()
+}
+
+// RollbackTransaction rolls back a transaction returned by EndTransaction, and
+// lets the next transaction write
+func (t *Transactions) RollbackTransaction(txn *pilosa.SQLTransaction) {
+	defer t.releaseWriter(txn)
+	txn.Rollback()
+}
+
+// releaseWriter takes the right to write away from txn, if it has it.
+func (t *Transactions) releaseWriter(txn *pilosa.SQLTransaction) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.unprotectedReleaseWriter(txn)
+}
+
+// unprotectedReleaseWriter is releaseWriter for callers holding t.mu.
+func (t *Transactions) unprotectedReleaseWriter(txn *pilosa.SQLTransaction) {
+	if t.writer != txn {
+		return
+	}
+	t.writer = nil
+	if t.writerTimer != nil {
+		t.writerTimer.Stop()
+		t.writerTimer = nil
+	}
+	t.writerReleased.Broadcast()
+}
+
+// watchWriter arranges for idle transactions to be expired once the writer
+// could have been idle for longer than the idle timeout, since the writes
+// it holds block every other write to the same shards, including those
+// which don't go through this API. Call only when holding t.mu.
+func (t *Transactions) watchWriter() {
+	if t.idleTimeout <= 0 {
+		return
+	}
+	t.writerTimer = time.AfterFunc(t.idleTimeout, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.writerTimer = nil
+		t.expireIdle()
+		if t.writer != nil {
+			t.watchWriter()
+		}
+	})
+}
+
+// expireIdle rolls back the open transactions which have not been used for
+// longer than the idle timeout. Call only when holding t.mu.
+func (t *Transactions) expireIdle() {
+	if t.idleTimeout <= 0 {
+		return
+	}
+	now := t.now()
+	for key, open := range t.transactions {
+		if now.Sub(open.lastUsed) > t.idleTimeout {
+			delete(t.transactions, key)
+			open.txn.Rollback()
+			t.unprotectedReleaseWriter(open.txn)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package systemlayer
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTransactions_IdleTimeout(t *testing.T) {
+	now := time.Now()
+	transactions := NewTransactionsAPI()
+	transactions.now = func() time.Time { return now }
+
+	if _, err := transactions.BeginTransaction("s1", "alice"); err != nil {
+		t.Fatal(err)
+	} else if _, err := transactions.BeginTransaction("s2", "alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	// using a transaction keeps it open
+	now = now.Add(defaultTransactionIdleTimeout / 2)
+	if _, ok := transactions.GetTransaction("s1", "alice"); !ok {
+		t.Fatal("expected transaction for s1")
+	}
+
+	now = now.Add(defaultTransactionIdleTimeout/2 + time.Second)
+	if _, ok := transactions.GetTransaction("s1", "alice"); !ok {
+		t.Fatal("expected transaction for s1 to still be open")
+	} else if _, ok := transactions.GetTransaction("s2", "alice"); ok {
+		t.Fatal("expected idle transaction for s2 to be rolled back")
+	} else if _, err := transactions.EndTransaction("s2", "alice"); err == nil {
+		t.Fatal("expected no transaction in progress for s2")
+	}
+}
+
+func TestTransactions_Users(t *testing.T) {
+	transactions := NewTransactionsAPI()
+	txn, err := transactions.BeginTransaction("s1", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := transactions.GetTransaction("s1", "bob"); ok {
+		t.Fatal("expected no transaction for another user's session")
+	} else if _, err := transactions.EndTransaction("s1", "bob"); err == nil {
+		t.Fatal("expected another user to be unable to end the transaction")
+	} else if got, err := transactions.EndTransaction("s1", "alice"); err != nil {
+		t.Fatal(err)
+	} else if got != txn {
+		t.Fatal("expected alice's transaction")
+	}
+}
+
+func TestTransactions_LockWrites(t *testing.T) {
+	transactions := NewTransactionsAPI()
+	txn1, err := transactions.BeginTransaction("s1", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn2, err := transactions.BeginTransaction("s2", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := transactions.LockWrites(context.Background(), txn1); err != nil {
+		t.Fatal(err)
+	} else if err := transactions.LockWrites(context.Background(), txn1); err != nil {
+		t.Fatalf("expected the writer to lock writes again, got %v", err)
+	}
+
+	// only one transaction writes at a time
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := transactions.LockWrites(ctx, txn2); err == nil {
+		t.Fatal("expected another transaction to wait for the writer")
+	}
+
+	// ending the writer lets the next transaction write
+	if _, err := transactions.EndTransaction("s1", "alice"); err != nil {
+		t.Fatal(err)
+	} else if err := transactions.CommitTransaction(txn1); err != nil {
+		t.Fatal(err)
+	} else if err := transactions.LockWrites(context.Background(), txn2); err != nil {
+		t.Fatal(err)
+	}
+}
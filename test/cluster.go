@@ -299,6 +299,9 @@ func (c *ShareableCluster) ImportBitsWithTimestamp(t testing.TB, index, field st
 						}
 
 					}
+					if err := qcx.Finish(); err != nil {
+						t.Fatalf("committing data: %v", err)
+					}
 				}()
 
 			}
@@ -331,6 +334,9 @@ func (c *ShareableCluster) ImportKeyKey(t testing.TB, index, field string, valAn
 	if err != nil {
 		t.Fatalf("importing keykey data: %v", err)
 	}
+	if err := qcx.Finish(); err != nil {
+		t.Fatalf("committing keykey data: %v", err)
+	}
 }
 
 // TimeQuantumKey is a string key and a string+key value
@@ -363,6 +369,9 @@ func (c *ShareableCluster) ImportTimeQuantumKey(t testing.TB, index, field strin
 	if err != nil {
 		t.Fatalf("importing keykey data: %v", err)
 	}
+	if err := qcx.Finish(); err != nil {
+		t.Fatalf("committing keykey data: %v", err)
+	}
 }
 
 // IntKey is a string key and a signed integer value.
@@ -390,6 +399,9 @@ func (c *ShareableCluster) ImportIntKey(t testing.TB, index, field string, pairs
 	if err := c.GetPrimary().API.ImportValue(context.Background(), qcx, importRequest); err != nil {
 		t.Fatalf("importing IntKey data: %v", err)
 	}
+	if err := qcx.Finish(); err != nil {
+		t.Fatalf("committing IntKey data: %v", err)
+	}
 }
 
 type IntID struct {
@@ -416,6 +428,9 @@ func (c *ShareableCluster) ImportIntID(t testing.TB, index, field string, pairs
 	if err := c.GetPrimary().API.ImportValue(context.Background(), qcx, importRequest); err != nil {
 		t.Fatalf("importing IntID data: %v", err)
 	}
+	if err := qcx.Finish(); err != nil {
+		t.Fatalf("committing IntID data: %v", err)
+	}
 }
 
 // KeyID represents a key and an ID for importing data into an index
@@ -444,6 +459,9 @@ func (c *ShareableCluster) ImportIDKey(t testing.TB, index, field string, pairs
 	if err != nil {
 		t.Fatalf("importing IDKey data: %v", err)
 	}
+	if err := qcx.Finish(); err != nil {
+		t.Fatalf("committing IDKey data: %v", err)
+	}
 }
 
 // CreateField creates the index (if necessary) and field specified.
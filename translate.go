@@ -100,7 +100,9 @@ type TranslatorTx interface {
 type OpenTranslateStoreFunc func(path, index, field string, partitionID, partitionN int, fsyncEnabled bool) (TranslateStore, error)
 
 // GenerateNextPartitionedID returns the next ID within the same partition.
-func GenerateNextPartitionedID(index string, prev uint64, partitionID, partitionN int) uint64 {
+// partitionKey is the name shards of the index are partitioned by, which is
+// not its name if it has been renamed. See Index.PartitionKey().
+func GenerateNextPartitionedID(partitionKey string, prev uint64, partitionID, partitionN int) uint64 {
 	// If the translation store is not partitioned, just return
 	// the next ID.
 	if partitionID == -1 {
@@ -109,7 +111,7 @@ func GenerateNextPartitionedID(index string, prev uint64, partitionID, partition
 	// Try to use the next ID if it is in the same partition.
 	// Otherwise find ID in next shard that has a matching partition.
 	for id := prev + 1; ; id += ShardWidth {
-		if disco.ShardToShardPartition(index, id/ShardWidth, partitionN) == partitionID {
+		if disco.ShardToShardPartition(partitionKey, id/ShardWidth, partitionN) == partitionID {
 			return id
 		}
 	}
@@ -313,3 +315,53 @@ func (b *BoltInMemTranslateStore) Close() error {
 	}
 	return nil
 }
+
+// snapshotTranslateStore copies the contents of store into a temporary file
+// so they can be restored into a store which is reopened under a new name.
+// Stores which live on disk at path are moved along with their directory,
+// so nil is returned for those.
+func snapshotTranslateStore(store TranslateStore, path string) (*os.File, error) {
+	if store == nil {
+		return nil, nil
+	} else if _, err := os.Stat(path); err == nil {
+		return nil, nil
+	}
+
+	tx, err := store.Begin(false)
+	if err != nil {
+		return nil, errors.Wrap(err, "beginning translate store transaction")
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	f, err := os.CreateTemp("", "translate-snapshot-")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating translate snapshot")
+	}
+	if _, err := tx.WriteTo(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, errors.Wrap(err, "writing translate snapshot")
+	}
+	return f, nil
+}
+
+// restoreTranslateStore reads a snapshot taken by snapshotTranslateStore into
+// store and removes the snapshot file.
+func restoreTranslateStore(store TranslateStore, f *os.File) error {
+	if f == nil {
+		return nil
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if store == nil {
+		return nil
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrap(err, "seeking translate snapshot")
+	}
+	if _, err := store.ReadFrom(f); err != nil {
+		return errors.Wrap(err, "restoring translate snapshot")
+	}
+	return nil
+}
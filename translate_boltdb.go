@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/featurebasedb/featurebase/v3/encryption"
 	"github.com/featurebasedb/featurebase/v3/roaring"
 	"github.com/pkg/errors"
 	bolt "go.etcd.io/bbolt"
@@ -34,6 +35,11 @@ var (
 	bucketIDs  = []byte("ids")
 	bucketFree = []byte("free")
 	freeKey    = []byte("free")
+
+	// bucketMeta only exists in encrypted stores.
+	bucketMeta     = []byte("meta")
+	digestKeyIDKey = []byte("digest-key-id")
+	checkKey       = []byte("check")
 )
 
 const (
@@ -53,6 +59,22 @@ func OpenTranslateStore(path, index, field string, partitionID, partitionN int,
 	return s, nil
 }
 
+// OpenEncryptedTranslateStore returns a function which opens boltdb
+// translation stores encrypted with c. Keys are sealed in the IDs bucket,
+// and the keys bucket is indexed by a keyed digest of each key, so no key
+// is stored in plaintext.
+func OpenEncryptedTranslateStore(c *encryption.Cipher) OpenTranslateStoreFunc {
+	return func(path, index, field string, partitionID, partitionN int, fsyncEnabled bool) (TranslateStore, error) {
+		s := NewBoltTranslateStore(index, field, partitionID, partitionN, fsyncEnabled)
+		s.Path = path
+		s.cipher = c
+		if err := s.Open(); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+}
+
 // Ensure type implements interface.
 var _ TranslateStore = &BoltTranslateStore{}
 
@@ -70,6 +92,8 @@ type BoltTranslateStore struct {
 	mu sync.RWMutex
 	db *bolt.DB
 
+	// index is the partition key of the index, which is not its name if
+	// it has been renamed. See Index.PartitionKey().
 	index       string
 	field       string
 	partitionID int
@@ -82,6 +106,12 @@ type BoltTranslateStore struct {
 	fsyncEnabled bool
 	writeNotify  chan struct{}
 
+	// cipher, if set, encrypts the keys in the store. The keys bucket is
+	// then indexed by digests made with the key digestKeyID, which is fixed
+	// when the store is created.
+	cipher      *encryption.Cipher
+	digestKeyID uint32
+
 	// File path to database file.
 	Path string
 }
@@ -124,7 +154,7 @@ func (s *BoltTranslateStore) Open() (err error) {
 		} else if _, err := tx.CreateBucketIfNotExists(bucketFree); err != nil {
 			return err
 		}
-		return nil
+		return s.initEncryption(tx)
 	}); err != nil {
 		s.db.Close()
 		return err
@@ -133,6 +163,82 @@ func (s *BoltTranslateStore) Open() (err error) {
 	return nil
 }
 
+// initEncryption records the digest key of a new encrypted store, and checks
+// that an existing store is opened with a cipher if and only if it was
+// created with one.
+func (s *BoltTranslateStore) initEncryption(tx *bolt.Tx) error {
+	var keyID, check []byte
+	if bkt := tx.Bucket(bucketMeta); bkt != nil {
+		keyID, check = bkt.Get(digestKeyIDKey), bkt.Get(checkKey)
+	}
+
+	switch {
+	case s.cipher == nil && keyID == nil:
+		return nil
+	case s.cipher == nil:
+		return errors.New("translate store is encrypted")
+	case keyID != nil:
+		s.digestKeyID = binary.BigEndian.Uint32(keyID)
+		if _, err := s.cipher.Digest(s.digestKeyID, nil); err != nil {
+			return errors.Wrap(err, "translate store digest key")
+		} else if _, err := s.cipher.Open(nil, check); err != nil {
+			return errors.Wrap(err, "translate store key check")
+		}
+		return nil
+	}
+
+	if id, _ := tx.Bucket(bucketIDs).Cursor().First(); id != nil {
+		return errors.New("translate store is not encrypted")
+	}
+	bkt, err := tx.CreateBucketIfNotExists(bucketMeta)
+	if err != nil {
+		return err
+	}
+	s.digestKeyID = s.cipher.CurrentKeyID()
+	if check, err = s.cipher.Seal(nil, checkKey); err != nil {
+		return err
+	} else if err := bkt.Put(checkKey, check); err != nil {
+		return err
+	}
+	keyID = make([]byte, 4)
+	binary.BigEndian.PutUint32(keyID, s.digestKeyID)
+	return bkt.Put(digestKeyIDKey, keyID)
+}
+
+// boltKey returns the bolt representation of a key, as stored in the IDs
+// bucket before any encryption.
+func boltKey(key string) []byte {
+	if key == "" {
+		return emptyKey
+	}
+	return []byte(key)
+}
+
+// indexKey returns the keys bucket key for the bolt representation of a key.
+func (s *BoltTranslateStore) indexKey(boltKey []byte) ([]byte, error) {
+	if s.cipher == nil {
+		return boltKey, nil
+	}
+	return s.cipher.Digest(s.digestKeyID, boltKey)
+}
+
+// sealKey returns the IDs bucket value for the bolt representation of a key.
+func (s *BoltTranslateStore) sealKey(boltKey []byte) ([]byte, error) {
+	if s.cipher == nil {
+		return boltKey, nil
+	}
+	return s.cipher.Seal(nil, boltKey)
+}
+
+// openKey returns the bolt representation of a key from its IDs bucket
+// value.
+func (s *BoltTranslateStore) openKey(value []byte) ([]byte, error) {
+	if s.cipher == nil || value == nil {
+		return value, nil
+	}
+	return s.cipher.Open(nil, value)
+}
+
 // Close closes the underlying database.
 func (s *BoltTranslateStore) Close() (err error) {
 	s.once.Do(func() { close(s.closing) })
@@ -188,7 +294,11 @@ func (s *BoltTranslateStore) FindKeys(keys ...string) (map[string]uint64, error)
 			return errors.Errorf(errFmtTranslateBucketNotFound, bucketKeys)
 		}
 		for _, key := range keys {
-			id, _ := findIDByKey(bkt, key)
+			indexKey, err := s.indexKey(boltKey(key))
+			if err != nil {
+				return err
+			}
+			id := findIDByKey(bkt, indexKey)
 			if id == 0 {
 				// The key does not exist.
 				continue
@@ -245,11 +355,19 @@ func (s *BoltTranslateStore) CreateKeys(keys ...string) (map[string]uint64, erro
 			defer getter.Close()
 
 			for idx, key := range keys {
-				id, boltKey := findIDByKey(keyBucket, key)
+				indexKey, err := s.indexKey(boltKey(key))
+				if err != nil {
+					return err
+				}
+				id := findIDByKey(keyBucket, indexKey)
 				if id != 0 {
 					result[key] = id
 					continue
 				}
+				value, err := s.sealKey(boltKey(key))
+				if err != nil {
+					return err
+				}
 				// see if we can re-use any IDs first
 				if id = getter.GetFreeID(); id == 0 {
 					id = GenerateNextPartitionedID(s.index, maxID(tx), s.partitionID, s.partitionN)
@@ -257,9 +375,9 @@ func (s *BoltTranslateStore) CreateKeys(keys ...string) (map[string]uint64, erro
 				idBytes := idScratch[puts*8 : puts*8+8]
 				binary.BigEndian.PutUint64(idBytes, id)
 				puts++
-				if err := keyBucket.Put(boltKey, idBytes); err != nil {
+				if err := keyBucket.Put(indexKey, idBytes); err != nil {
 					return err
-				} else if err := idBucket.Put(idBytes, boltKey); err != nil {
+				} else if err := idBucket.Put(idBytes, value); err != nil {
 					return err
 				}
 				result[key] = id
@@ -294,6 +412,10 @@ func (s *BoltTranslateStore) Match(filter func([]byte) bool) ([]uint64, error) {
 		}
 
 		return idBucket.ForEach(func(id, key []byte) error {
+			key, err := s.openKey(key)
+			if err != nil {
+				return err
+			}
 			if bytes.Equal(key, emptyKey) {
 				key = nil
 			}
@@ -320,7 +442,7 @@ func (s *BoltTranslateStore) TranslateID(id uint64) (string, error) {
 		return "", err
 	}
 	defer func() { _ = tx.Rollback() }()
-	return findKeyByID(tx.Bucket(bucketIDs), id), nil
+	return s.findKeyByID(tx.Bucket(bucketIDs), id)
 }
 
 // TranslateIDs converts a list of integer IDs to a list of string keys.
@@ -339,7 +461,9 @@ func (s *BoltTranslateStore) TranslateIDs(ids []uint64) ([]string, error) {
 
 	keys := make([]string, len(ids))
 	for i, id := range ids {
-		keys[i] = findKeyByID(bucket, id)
+		if keys[i], err = s.findKeyByID(bucket, id); err != nil {
+			return nil, err
+		}
 	}
 	return keys, nil
 }
@@ -347,9 +471,17 @@ func (s *BoltTranslateStore) TranslateIDs(ids []uint64) ([]string, error) {
 // ForceSet writes the id/key pair to the store even if read only. Used by replication.
 func (s *BoltTranslateStore) ForceSet(id uint64, key string) error {
 	if err := s.db.Update(func(tx *bolt.Tx) (err error) {
-		if err := tx.Bucket(bucketKeys).Put([]byte(key), u64tob(id)); err != nil {
+		indexKey, value := []byte(key), []byte(key)
+		if s.cipher != nil {
+			if indexKey, err = s.indexKey(boltKey(key)); err != nil {
+				return err
+			} else if value, err = s.sealKey(boltKey(key)); err != nil {
+				return err
+			}
+		}
+		if err := tx.Bucket(bucketKeys).Put(indexKey, u64tob(id)); err != nil {
 			return err
-		} else if err := tx.Bucket(bucketIDs).Put(u64tob(id), []byte(key)); err != nil {
+		} else if err := tx.Bucket(bucketIDs).Put(u64tob(id), value); err != nil {
 			return err
 		}
 		return nil
@@ -480,6 +612,10 @@ func (r *BoltTranslateEntryReader) ReadEntry(entry *TranslateEntry) error {
 			if key == nil {
 				return nil
 			}
+			value, err := r.store.openKey(value)
+			if err != nil {
+				return err
+			}
 
 			// Copy ID & key to entry and mark as found.
 			found = true
@@ -576,8 +712,13 @@ func (s *BoltTranslateStore) Delete(records *roaring.Bitmap) (Commitor, error) {
 	ids := records.Slice()
 	for i := range ids {
 		id := u64tob(ids[i])
-		boltKey := idBucket.Get(id)
-		err = keyBucket.Delete(boltKey)
+		indexKey, err := s.openKey(idBucket.Get(id))
+		if err == nil && s.cipher != nil && indexKey != nil {
+			indexKey, err = s.indexKey(indexKey)
+		}
+		if err == nil {
+			err = keyBucket.Delete(indexKey)
+		}
 		if err != nil {
 			tx.Rollback()
 			return &boltWrapper{}, err
@@ -601,18 +742,11 @@ var emptyKey = []byte{
 	0x00,
 }
 
-func findIDByKey(bkt *bolt.Bucket, key string) (uint64, []byte) {
-	var boltKey []byte
-	if key == "" {
-		boltKey = emptyKey
-	} else {
-		boltKey = []byte(key)
-	}
-
-	if value := bkt.Get(boltKey); value != nil {
-		return btou64(value), boltKey
+func findIDByKey(bkt *bolt.Bucket, indexKey []byte) uint64 {
+	if value := bkt.Get(indexKey); value != nil {
+		return btou64(value)
 	}
-	return 0, boltKey
+	return 0
 }
 
 // freeIDGetter reduces the amount of marshaling required to get multiple ids
@@ -693,12 +827,15 @@ func (g *freeIDGetter) Close() error {
 	return nil
 }
 
-func findKeyByID(bkt *bolt.Bucket, id uint64) string {
-	boltKey := bkt.Get(u64tob(id))
+func (s *BoltTranslateStore) findKeyByID(bkt *bolt.Bucket, id uint64) (string, error) {
+	boltKey, err := s.openKey(bkt.Get(u64tob(id)))
+	if err != nil {
+		return "", err
+	}
 	if bytes.Equal(boltKey, emptyKey) {
-		return ""
+		return "", nil
 	}
-	return string(boltKey)
+	return string(boltKey), nil
 }
 
 // u64tob encodes v to big endian encoding.
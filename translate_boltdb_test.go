@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"reflect"
 	"strconv"
 	"testing"
@@ -13,6 +14,7 @@ import (
 
 	pilosa "github.com/featurebasedb/featurebase/v3"
 	"github.com/featurebasedb/featurebase/v3/disco"
+	"github.com/featurebasedb/featurebase/v3/encryption"
 	"github.com/featurebasedb/featurebase/v3/roaring"
 	"github.com/featurebasedb/featurebase/v3/testhook"
 	"github.com/stretchr/testify/require"
@@ -509,6 +511,81 @@ func TestTranslateStore_ReadWrite(t *testing.T) {
 	})
 }
 
+func TestTranslateStore_Encrypted(t *testing.T) {
+	keyring, err := encryption.NewStaticKeyring(map[uint32][]byte{1: bytes.Repeat([]byte{1}, 32)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := encryption.NewCipher(keyring)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := MustNewTranslateStore(t).Path
+	open := pilosa.OpenEncryptedTranslateStore(c)
+	ts, err := open(path, "I", "F", 0, disco.DefaultPartitionN, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := ts.(*pilosa.BoltTranslateStore)
+
+	ids, err := s.CreateKeys("foo", "bar", "", "deleteme")
+	if err != nil {
+		t.Fatal(err)
+	} else if err := s.ForceSet(100, "forced"); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := s.FindKeys("foo", "bar", "", "forced", "missing"); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(found, map[string]uint64{"foo": ids["foo"], "bar": ids["bar"], "": ids[""], "forced": 100}) {
+		t.Fatalf("unexpected keys: %v", found)
+	}
+	if keys, err := s.TranslateIDs([]uint64{ids["foo"], ids[""], 100}); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(keys, []string{"foo", "", "forced"}) {
+		t.Fatalf("unexpected keys: %q", keys)
+	}
+	if matches, err := s.Match(func(key []byte) bool { return bytes.HasPrefix(key, []byte("ba")) }); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(matches, []uint64{ids["bar"]}) {
+		t.Fatalf("unexpected matches: %v", matches)
+	}
+	if commit, err := s.Delete(roaring.NewBitmap(ids["deleteme"])); err != nil {
+		t.Fatal(err)
+	} else if err := commit.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := s.FindKeys("deleteme"); err != nil {
+		t.Fatal(err)
+	} else if len(found) != 0 {
+		t.Fatalf("expected deleted key to be gone, got %v", found)
+	}
+	MustCloseTranslateStore(s)
+
+	// no key appears in the file.
+	if data, err := os.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if bytes.Contains(data, []byte("forced")) || bytes.Contains(data, []byte("foo")) {
+		t.Fatal("expected keys to be encrypted")
+	}
+
+	// the store cannot be opened without the key.
+	if _, err := pilosa.OpenTranslateStore(path, "I", "F", 0, disco.DefaultPartitionN, false); err == nil {
+		t.Fatal("expected error opening encrypted store without a key")
+	}
+
+	ts, err = open(path, "I", "F", 0, disco.DefaultPartitionN, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer MustCloseTranslateStore(ts.(*pilosa.BoltTranslateStore))
+	if key, err := ts.TranslateID(ids["bar"]); err != nil {
+		t.Fatal(err)
+	} else if key != "bar" {
+		t.Fatalf("expected bar, got %q", key)
+	}
+}
+
 // MustOpenNewTranslateStore returns a new, opened TranslateStore.
 func MustOpenNewTranslateStore(tb testing.TB) *pilosa.BoltTranslateStore {
 	s := MustNewTranslateStore(tb)
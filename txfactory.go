@@ -80,6 +80,10 @@ var sep = string(os.PathSeparator)
 // we make a special write Tx and use it for all matching writes.
 // This is then committed at the final, top-level, Qcx.Finish() call.
 //
+// The Qcx of a sql transaction, from NewSessionQcx(), is the other
+// exception: it keeps every write Tx it hands out, one per index and
+// shard, until Finish() commits them or Abort() rolls them back.
+//
 // See also the Qcx.GetTx() example and the TxGroup description below.
 type Qcx struct {
 	Grp     *TxGroup
@@ -106,6 +110,17 @@ type Qcx struct {
 
 	// don't allow automatic reuse now. Must manually call Reset, or NewQcx().
 	done bool
+
+	// deferWrites is set on the Qcx of a sql transaction. Its write Tx
+	// are kept in writes, in the order they were begun, and are used by
+	// every later read or write of the same shard, so the transaction
+	// sees its own writes. Finish commits them.
+	deferWrites bool
+	writes      map[grpkey]Tx
+	writeOrder  []grpkey
+
+	// onCommit are called by Finish once every Tx has committed.
+	onCommit []func()
 }
 
 // Finish commits/rollsback all stored Tx. It no longer resets the
@@ -114,6 +129,9 @@ type Qcx struct {
 func (q *Qcx) Finish() (err error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+	if q.deferWrites {
+		err = q.commitWrites()
+	}
 	if q.RequiredForAtomicWriteTx != nil {
 		if q.RequiredTxo.Write {
 			err = (*q.RequiredForAtomicWriteTx).Commit() // PanicOn here on 2nd. is this a double commit?
@@ -129,10 +147,17 @@ func (q *Qcx) Finish() (err error) {
 	}
 	q.done = true
 
+	onCommit := q.onCommit
+	q.onCommit = nil
 	if err != nil {
 		return err
+	} else if err2 != nil {
+		return err2
 	}
-	return err2
+	for _, fn := range onCommit {
+		fn()
+	}
+	return nil
 }
 
 // Abort rolls back all Tx generated and stored within the Qcx.
@@ -140,6 +165,8 @@ func (q *Qcx) Finish() (err error) {
 func (q *Qcx) Abort() {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+	q.rollbackWrites()
+	q.onCommit = nil
 	if q.RequiredForAtomicWriteTx != nil {
 		(*q.RequiredForAtomicWriteTx).Rollback()
 	}
@@ -152,6 +179,49 @@ func (q *Qcx) Abort() {
 	q.done = true
 }
 
+// commitWrites commits the write Tx of a sql transaction. Each shard
+// commits on its own, so if one fails, the shards after it are rolled
+// back, but the shards before it stay committed. Call only when holding
+// q.mu.
+func (q *Qcx) commitWrites() (err error) {
+	for _, key := range q.writeOrder {
+		tx := q.writes[key]
+		if err != nil {
+			tx.Rollback()
+			continue
+		}
+		if err = tx.Commit(); err != nil {
+			err = errors.Wrapf(err, "committing index %s shard %d", key.index, key.shard)
+		}
+	}
+	q.writes, q.writeOrder = nil, nil
+	return err
+}
+
+// rollbackWrites rolls back the write Tx of a sql transaction. Call only
+// when holding q.mu.
+func (q *Qcx) rollbackWrites() {
+	for _, key := range q.writeOrder {
+		q.writes[key].Rollback()
+	}
+	q.writes, q.writeOrder = nil, nil
+}
+
+// DefersWrites reports whether the Qcx is that of a sql transaction, which
+// holds its writes until Finish.
+func (q *Qcx) DefersWrites() bool {
+	return q.deferWrites
+}
+
+// OnCommit registers fn to be called once Finish has committed every Tx of
+// the Qcx. Work which can't be undone, like deleting keys or recording
+// changes, is done this way, so that it only happens if the writes commit.
+func (q *Qcx) OnCommit(fn func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onCommit = append(q.onCommit, fn)
+}
+
 // Reset forgets everything are starts fresh with an empty
 // group, ready for use again as if NewQcx() had been called.
 func (q *Qcx) Reset() {
@@ -164,6 +234,7 @@ func (q *Qcx) unprotected_reset() {
 	q.RequiredForAtomicWriteTx = nil
 	q.RequiredTxo = nil
 	q.Grp = q.Txf.NewTxGroup()
+	q.onCommit = nil
 	q.done = false
 }
 
@@ -205,6 +276,15 @@ func (f *TxFactory) NewWritableQcx() (qcx *Qcx) {
 	return
 }
 
+// NewSessionQcx allocates a writable Qcx for a sql transaction, which
+// holds on to its writes until Finish() commits them.
+func (f *TxFactory) NewSessionQcx() (qcx *Qcx) {
+	qcx = f.NewWritableQcx()
+	qcx.deferWrites = true
+	qcx.writes = make(map[grpkey]Tx)
+	return qcx
+}
+
 var NoopFinisher = func(perr *error) {}
 
 var ErrQcxDone = fmt.Errorf("Qcx already Aborted or Finished, so must call reset before re-use")
@@ -261,6 +341,22 @@ func (qcx *Qcx) GetTx(o Txo) (tx Tx, finisher func(perr *error), err error) {
 		return qcx.Txf.NewTx(o), NoopFinisher, nil
 	}
 
+	// a sql transaction reads and writes a shard it has written through
+	// the one write Tx it holds for the shard
+	if qcx.deferWrites {
+		mustHaveIndexShard(&o)
+		key := grpkey{index: o.Index.name, shard: o.Shard}
+		if tx, ok := qcx.writes[key]; ok {
+			return tx, NoopFinisher, nil
+		}
+		if o.Write {
+			tx = qcx.Txf.NewTx(o)
+			qcx.writes[key] = tx
+			qcx.writeOrder = append(qcx.writeOrder, key)
+			return tx, NoopFinisher, nil
+		}
+	}
+
 	// qcx.write reflects the top executor determination
 	// if a write will be happen at some point, in which case, to avoid
 	// locking problems with multi-shard things, we (probably incorrectly)
@@ -479,6 +575,14 @@ func (f *TxFactory) DeleteFieldFromStore(index, field, fieldPath string) (err er
 	return f.dbPerShard.DeleteFieldFromStore(index, field, fieldPath)
 }
 
+func (f *TxFactory) RenameFieldInStore(index, field, newField string) (err error) {
+	return f.dbPerShard.RenameFieldInStore(index, field, newField)
+}
+
+func (f *TxFactory) ReleaseIndex(index string) (err error) {
+	return f.dbPerShard.ReleaseIndex(index)
+}
+
 func (f *TxFactory) DeleteFragmentFromStore(
 	index, field, view string, shard uint64, frag *fragment,
 ) (err error) {
@@ -32,6 +32,15 @@ const (
 	viewExistence = "existence"
 )
 
+// renamedView returns the name of the view once its field, field, has been
+// renamed to newField. Only the BSI group view carries the field name.
+func renamedView(field, newField, view string) string {
+	if view == viewBSIGroupPrefix+field {
+		return viewBSIGroupPrefix + newField
+	}
+	return view
+}
+
 // view represents a container for field data.
 type view struct {
 	mu            sync.RWMutex
@@ -22,6 +22,10 @@ type WireQueryResponse struct {
 	Warnings      []string               `json:"warnings"`
 	QueryPlan     map[string]interface{} `json:"query-plan"`
 	ExecutionTime int64                  `json:"execution-time"`
+
+	// Cursor is set on a page of a paginated response when there are rows
+	// left to read from /sql/cursor/{id}.
+	Cursor string `json:"cursor,omitempty"`
 }
 
 // WireQuerySchema is a list of Fields which map to the data columns in the
@@ -181,6 +185,23 @@ func (s *WireQueryResponse) UnmarshalJSONTyped(in []byte, typed bool) error {
 					s.Data[i][j] = val
 				}
 
+			case dax.BaseTypeGeo:
+				if src, ok := s.Data[i][j].(map[string]interface{}); ok {
+					var p GeoPoint
+					for k, dst := range map[string]*float64{"lat": &p.Lat, "lon": &p.Lon} {
+						jn, ok := src[k].(json.Number)
+						if !ok {
+							return errors.Errorf("point is missing %s", k)
+						}
+						f, err := jn.Float64()
+						if err != nil {
+							return errors.Wrap(err, "parsing point")
+						}
+						*dst = f
+					}
+					s.Data[i][j] = p
+				}
+
 			case dax.BaseTypeBool, dax.BaseTypeString:
 				// no need to convert
 
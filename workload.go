@@ -0,0 +1,329 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/authn"
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
+	"github.com/pkg/errors"
+)
+
+// QueryPriority orders the shards of concurrent queries waiting for the
+// executor's workers: the shards of a query with a higher priority are worked
+// on ahead of those of queries with lower priorities.
+type QueryPriority int
+
+const (
+	QueryPriorityLow QueryPriority = iota
+	QueryPriorityNormal
+	QueryPriorityHigh
+
+	numQueryPriorities = int(QueryPriorityHigh) + 1
+)
+
+// ParseQueryPriority returns the priority named by s, which is one of "low",
+// "normal" and "high". The empty string is the normal priority.
+func ParseQueryPriority(s string) (QueryPriority, error) {
+	switch s {
+	case "low":
+		return QueryPriorityLow, nil
+	case "", "normal":
+		return QueryPriorityNormal, nil
+	case "high":
+		return QueryPriorityHigh, nil
+	}
+	return 0, errors.Errorf("invalid query priority '%s', expected low, normal or high", s)
+}
+
+func (p QueryPriority) String() string {
+	switch p {
+	case QueryPriorityLow:
+		return "low"
+	case QueryPriorityHigh:
+		return "high"
+	}
+	return "normal"
+}
+
+// DefaultResourcePoolName is the name of the resource pool queries run in
+// when no other pool is mapped to their user or groups. Unless it is
+// configured, the default pool doesn't limit its queries.
+const DefaultResourcePoolName = "default"
+
+// ResourcePool is a named share of the resources a node uses to execute
+// queries. A query runs in the first pool which lists its user, or one of its
+// groups, and otherwise in the default pool.
+type ResourcePool struct {
+	Name string
+
+	// Users and Groups map authenticated users to the pool, by user id and by
+	// group id or name.
+	Users  []string
+	Groups []string
+
+	// MaxConcurrency is the number of queries which can run in the pool at
+	// once; further queries wait in a queue for a running query to finish.
+	// Zero means no limit.
+	MaxConcurrency int
+
+	// MaxQueued is the number of queries which can wait in the queue; a query
+	// arriving to a full queue fails with ErrResourcePoolQueueFull. Zero means
+	// no limit.
+	MaxQueued int
+
+	// QueueTimeout is how long a query waits in the queue before it fails
+	// with ErrResourcePoolQueueTimeout. Zero means no limit.
+	QueueTimeout time.Duration
+
+	// Priority is the priority of the pool's queries in the executor.
+	Priority QueryPriority
+
+	// MaxQueryMemory is the most memory, in bytes, the results of each query
+	// of the pool can use; it doesn't limit the memory used to execute the
+	// query. A query fails with ErrQueryMemoryExceeded when its results need
+	// more. Zero means the node-wide max-query-memory, which only applies to
+	// Extract() and SELECT.
+	MaxQueryMemory int64
+}
+
+// workloadManager admits queries to the resource pools of a node.
+type workloadManager struct {
+	pools       []*resourcePool
+	defaultPool *resourcePool
+}
+
+// newWorkloadManager returns a workloadManager for the given pools, adding
+// the default pool if it isn't among them.
+func newWorkloadManager(pools []ResourcePool) (*workloadManager, error) {
+	m := &workloadManager{}
+	names := make(map[string]struct{})
+	for _, cfg := range pools {
+		if cfg.Name == "" {
+			return nil, errors.New("resource pool name required")
+		}
+		if _, ok := names[cfg.Name]; ok {
+			return nil, errors.Errorf("duplicate resource pool '%s'", cfg.Name)
+		}
+		names[cfg.Name] = struct{}{}
+		if cfg.MaxConcurrency < 0 || cfg.MaxQueued < 0 || cfg.QueueTimeout < 0 || cfg.MaxQueryMemory < 0 {
+			return nil, errors.Errorf("resource pool '%s': limits must not be negative", cfg.Name)
+		}
+		if cfg.Priority < QueryPriorityLow || cfg.Priority > QueryPriorityHigh {
+			return nil, errors.Errorf("resource pool '%s': invalid priority %d", cfg.Name, cfg.Priority)
+		}
+
+		p := &resourcePool{ResourcePool: cfg}
+		if cfg.Name == DefaultResourcePoolName {
+			m.defaultPool = p
+		}
+		m.pools = append(m.pools, p)
+	}
+	if m.defaultPool == nil {
+		m.defaultPool = &resourcePool{ResourcePool: ResourcePool{Name: DefaultResourcePoolName, Priority: QueryPriorityNormal}}
+		m.pools = append(m.pools, m.defaultPool)
+	}
+	return m, nil
+}
+
+type contextKeyResourcePool struct{}
+
+// contextKeyAdmittedRemotely marks the context of a query admitted by the
+// node which sent it.
+type contextKeyAdmittedRemotely struct{}
+
+// resourcePoolFromContext returns the pool a query is running in, or nil if
+// it hasn't been admitted to one.
+func resourcePoolFromContext(ctx context.Context) *resourcePool {
+	p, _ := ctx.Value(contextKeyResourcePool{}).(*resourcePool)
+	return p
+}
+
+// queryPriority returns the priority of the query running in ctx.
+func queryPriority(ctx context.Context) QueryPriority {
+	if p := resourcePoolFromContext(ctx); p != nil {
+		return p.Priority
+	}
+	return QueryPriorityNormal
+}
+
+// admit waits for the query running in ctx to be admitted to its pool,
+// returning a context holding the pool, and a function to call when the query
+// has finished. A query which has already been admitted (such as when one
+// request reads the pages of another's results) runs in the same pool.
+// A nil workloadManager admits every query, as does a context returned by
+// withPool.
+func (m *workloadManager) admit(ctx context.Context) (context.Context, func(), error) {
+	if m == nil || ctx.Value(contextKeyAdmittedRemotely{}) != nil {
+		return ctx, func() {}, nil
+	}
+	p := resourcePoolFromContext(ctx)
+	if p == nil {
+		p = m.resolve(ctx)
+		ctx = context.WithValue(ctx, contextKeyResourcePool{}, p)
+	}
+	release, err := p.acquire(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ctx, release, nil
+}
+
+// withPool returns a context holding the pool with the given name, for
+// executing the part of a query sent by the node which admitted it. The
+// query isn't admitted again, since it already holds a slot in its pool on
+// that node. A query naming an unknown pool runs outside of any pool. It must
+// only be used for requests authenticated as coming from another node.
+func (m *workloadManager) withPool(ctx context.Context, name string) context.Context {
+	if m == nil {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, contextKeyAdmittedRemotely{}, true)
+	for _, p := range m.pools {
+		if p.Name == name {
+			return context.WithValue(ctx, contextKeyResourcePool{}, p)
+		}
+	}
+	return ctx
+}
+
+// resolve returns the pool mapped to the user or groups of ctx.
+func (m *workloadManager) resolve(ctx context.Context) *resourcePool {
+	userID, _ := fbcontext.UserID(ctx)
+
+	var groups []string
+	switch g := ctx.Value(contextKeyGroupMembership).(type) {
+	case []authn.Group:
+		for _, group := range g {
+			groups = append(groups, group.GroupID, group.GroupName)
+		}
+	case []string:
+		groups = g
+	}
+
+	for _, p := range m.pools {
+		if userID != "" && containsString(p.Users, userID) {
+			return p
+		}
+		for _, group := range groups {
+			if group != "" && containsString(p.Groups, group) {
+				return p
+			}
+		}
+	}
+	return m.defaultPool
+}
+
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// resourcePool tracks the queries running and waiting in a ResourcePool.
+type resourcePool struct {
+	ResourcePool
+
+	mu      sync.Mutex
+	running int
+	// queue holds the waiting queries in the order they arrived; a query is
+	// admitted by closing its channel, handing it the slot of a query which
+	// has finished.
+	queue []chan struct{}
+}
+
+// acquire waits for a slot to run a query in, returning a function which
+// gives the slot back.
+func (p *resourcePool) acquire(ctx context.Context) (func(), error) {
+	p.mu.Lock()
+	if p.MaxConcurrency == 0 || (p.running < p.MaxConcurrency && len(p.queue) == 0) {
+		p.running++
+		p.mu.Unlock()
+		p.updateGauges()
+		return p.releaser(), nil
+	}
+	if p.MaxQueued > 0 && len(p.queue) >= p.MaxQueued {
+		p.mu.Unlock()
+		CounterResourcePoolRejected.WithLabelValues(p.Name).Inc()
+		return nil, errors.Wrapf(ErrResourcePoolQueueFull, "resource pool '%s' has %d queries waiting", p.Name, p.MaxQueued)
+	}
+	admitted := make(chan struct{})
+	p.queue = append(p.queue, admitted)
+	p.mu.Unlock()
+	p.updateGauges()
+
+	var timeout <-chan time.Time
+	if p.QueueTimeout > 0 {
+		timer := time.NewTimer(p.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-admitted:
+		return p.releaser(), nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		CounterResourcePoolRejected.WithLabelValues(p.Name).Inc()
+		err = errors.Wrapf(ErrResourcePoolQueueTimeout, "waited %s in resource pool '%s'", p.QueueTimeout, p.Name)
+	}
+
+	// Leave the queue, unless the query was admitted in the meantime, in
+	// which case it has a slot to give back.
+	p.mu.Lock()
+	for i, c := range p.queue {
+		if c == admitted {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			p.mu.Unlock()
+			p.updateGauges()
+			return nil, err
+		}
+	}
+	p.mu.Unlock()
+	p.releaser()()
+	return nil, err
+}
+
+// releaser returns a function which gives back a slot in the pool, to the
+// first waiting query if there is one. Calls after the first do nothing.
+func (p *resourcePool) releaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			if len(p.queue) > 0 {
+				close(p.queue[0])
+				p.queue = p.queue[1:]
+			} else {
+				p.running--
+			}
+			p.mu.Unlock()
+			p.updateGauges()
+		})
+	}
+}
+
+func (p *resourcePool) updateGauges() {
+	p.mu.Lock()
+	running, queued := p.running, len(p.queue)
+	p.mu.Unlock()
+	GaugeResourcePoolRunning.WithLabelValues(p.Name).Set(float64(running))
+	GaugeResourcePoolQueued.WithLabelValues(p.Name).Set(float64(queued))
+}
+
+// queryMemoryExceeded returns the error for a query whose results need more
+// memory than its max query memory allows.
+func queryMemoryExceeded(ctx context.Context) error {
+	if p := resourcePoolFromContext(ctx); p != nil && p.MaxQueryMemory > 0 {
+		return errors.Wrapf(ErrQueryMemoryExceeded, "resource pool '%s' allows %d bytes per query", p.Name, p.MaxQueryMemory)
+	}
+	return ErrQueryMemoryExceeded
+}
@@ -0,0 +1,190 @@
+// Copyright 2022 Molecula Corp. (DBA FeatureBase).
+// SPDX-License-Identifier: Apache-2.0
+package pilosa
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/featurebasedb/featurebase/v3/authn"
+	fbcontext "github.com/featurebasedb/featurebase/v3/context"
+	"github.com/pkg/errors"
+)
+
+func TestWorkloadManager(t *testing.T) {
+	t.Run("Resolve", func(t *testing.T) {
+		m, err := newWorkloadManager([]ResourcePool{
+			{Name: "etl", Users: []string{"loader"}},
+			{Name: "analysts", Groups: []string{"analysts"}, Priority: QueryPriorityLow},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, test := range []struct {
+			ctx  context.Context
+			pool string
+		}{
+			{ctx: context.Background(), pool: DefaultResourcePoolName},
+			{ctx: fbcontext.WithUserID(context.Background(), "loader"), pool: "etl"},
+			{ctx: fbcontext.WithUserID(context.Background(), "someone"), pool: DefaultResourcePoolName},
+			{ctx: context.WithValue(context.Background(), contextKeyGroupMembership, []authn.Group{{GroupID: "g1", GroupName: "analysts"}}), pool: "analysts"},
+			{ctx: context.WithValue(context.Background(), contextKeyGroupMembership, []string{"analysts"}), pool: "analysts"},
+		} {
+			if got := m.resolve(test.ctx).Name; got != test.pool {
+				t.Errorf("expected pool %s, got %s", test.pool, got)
+			}
+		}
+
+		ctx := m.withPool(context.Background(), "analysts")
+		if got := queryPriority(ctx); got != QueryPriorityLow {
+			t.Errorf("expected low priority, got %s", got)
+		}
+		if got := queryPriority(context.Background()); got != QueryPriorityNormal {
+			t.Errorf("expected normal priority, got %s", got)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, pools := range [][]ResourcePool{
+			{{}},
+			{{Name: "a"}, {Name: "a"}},
+			{{Name: "a", MaxConcurrency: -1}},
+			{{Name: "a", Priority: QueryPriority(7)}},
+		} {
+			if _, err := newWorkloadManager(pools); err == nil {
+				t.Errorf("expected error for %+v", pools)
+			}
+		}
+	})
+
+	t.Run("Queue", func(t *testing.T) {
+		m, err := newWorkloadManager([]ResourcePool{
+			{Name: DefaultResourcePoolName, MaxConcurrency: 1, MaxQueued: 1},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, release, err := m.admit(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		admitted := make(chan error, 1)
+		go func() {
+			_, release, err := m.admit(context.Background())
+			if err == nil {
+				release()
+			}
+			admitted <- err
+		}()
+
+		// Wait for the second query to be queued, after which the queue is
+		// full.
+		for {
+			m.defaultPool.mu.Lock()
+			n := len(m.defaultPool.queue)
+			m.defaultPool.mu.Unlock()
+			if n == 1 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if _, _, err := m.admit(context.Background()); errors.Cause(err) != ErrResourcePoolQueueFull {
+			t.Fatalf("expected queue full error, got %v", err)
+		}
+
+		release()
+		release() // releasing twice gives back one slot
+		select {
+		case err := <-admitted:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected queued query to be admitted")
+		}
+
+		m.defaultPool.mu.Lock()
+		defer m.defaultPool.mu.Unlock()
+		if m.defaultPool.running != 0 || len(m.defaultPool.queue) != 0 {
+			t.Fatalf("expected empty pool, got %d running, %d queued", m.defaultPool.running, len(m.defaultPool.queue))
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		m, err := newWorkloadManager([]ResourcePool{
+			{Name: DefaultResourcePoolName, MaxConcurrency: 1, QueueTimeout: 10 * time.Millisecond},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, release, err := m.admit(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer release()
+
+		if _, _, err := m.admit(context.Background()); errors.Cause(err) != ErrResourcePoolQueueTimeout {
+			t.Fatalf("expected queue timeout error, got %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, _, err := m.admit(ctx); err != context.Canceled {
+			t.Fatalf("expected context canceled, got %v", err)
+		}
+
+		m.defaultPool.mu.Lock()
+		defer m.defaultPool.mu.Unlock()
+		if len(m.defaultPool.queue) != 0 {
+			t.Fatalf("expected empty queue, got %d", len(m.defaultPool.queue))
+		}
+	})
+
+	t.Run("MemoryExceeded", func(t *testing.T) {
+		m, err := newWorkloadManager([]ResourcePool{{Name: "small", Users: []string{"u"}, MaxQueryMemory: 100}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, release, err := m.admit(fbcontext.WithUserID(context.Background(), "u"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer release()
+
+		err = queryMemoryExceeded(ctx)
+		if errors.Cause(err) != ErrQueryMemoryExceeded {
+			t.Fatalf("expected memory exceeded error, got %v", err)
+		} else if err.Error() != "resource pool 'small' allows 100 bytes per query: query results exceeded the max query memory" {
+			t.Fatalf("unexpected error message: %v", err)
+		}
+	})
+}
+
+func TestExecutorNextJobPriority(t *testing.T) {
+	e := &executor{}
+	for i := range e.work {
+		e.work[i] = make(chan job, 2)
+	}
+	e.work[QueryPriorityLow] <- job{shard: 1}
+	e.work[QueryPriorityNormal] <- job{shard: 2}
+	e.work[QueryPriorityHigh] <- job{shard: 3}
+
+	for _, shard := range []uint64{3, 2, 1} {
+		j, ok := e.nextJob()
+		if !ok || j.shard != shard {
+			t.Fatalf("expected shard %d, got %d (%v)", shard, j.shard, ok)
+		}
+	}
+
+	for _, work := range e.work {
+		close(work)
+	}
+	if _, ok := e.nextJob(); ok {
+		t.Fatal("expected no job after close")
+	}
+}